@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE image ADD COLUMN stock INTEGER NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE image DROP COLUMN stock;
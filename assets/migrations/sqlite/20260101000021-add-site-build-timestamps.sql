@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE site ADD COLUMN last_generated_at DATETIME;
+ALTER TABLE site ADD COLUMN last_published_commit TEXT;
+
+-- +migrate Down
+ALTER TABLE site DROP COLUMN last_published_commit;
+ALTER TABLE site DROP COLUMN last_generated_at;
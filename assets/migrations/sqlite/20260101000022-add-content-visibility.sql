@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public';
+
+-- +migrate Down
+ALTER TABLE content DROP COLUMN visibility;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS content_revision (
+    id TEXT PRIMARY KEY,
+    content_id TEXT NOT NULL,
+    heading TEXT NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (content_id) REFERENCES content(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_content_revision_content_id ON content_revision(content_id, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS content_revision;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS content_kind (
+    id TEXT PRIMARY KEY,
+    site_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    permalink TEXT NOT NULL DEFAULT '',
+    listed INTEGER NOT NULL DEFAULT 1,
+    feed INTEGER NOT NULL DEFAULT 0,
+    redirect INTEGER NOT NULL DEFAULT 0,
+    layout_id TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (site_id) REFERENCES site(id) ON DELETE CASCADE,
+    FOREIGN KEY (layout_id) REFERENCES layout(id) ON DELETE SET NULL,
+    UNIQUE(site_id, name)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS content_kind;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN lang TEXT NOT NULL DEFAULT '';
+ALTER TABLE content ADD COLUMN translation_group TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_content_translation_group ON content(site_id, translation_group);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_content_translation_group;
+ALTER TABLE content DROP COLUMN translation_group;
+ALTER TABLE content DROP COLUMN lang;
@@ -0,0 +1,7 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS idx_image_site_created_at ON image(site_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_layout_header_image_id ON layout(header_image_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_layout_header_image_id;
+DROP INDEX IF EXISTS idx_image_site_created_at;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE meta ADD COLUMN keep_links INTEGER DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE meta DROP COLUMN keep_links;
@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS publish_job (
+    id TEXT PRIMARY KEY,
+    site_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued',
+    progress TEXT NOT NULL DEFAULT '',
+    commit_hash TEXT NOT NULL DEFAULT '',
+    commit_url TEXT NOT NULL DEFAULT '',
+    no_changes INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    FOREIGN KEY (site_id) REFERENCES site(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_publish_job_site ON publish_job(site_id, created_at);
+
+-- A site has at most one publish waiting or in progress.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_publish_job_active ON publish_job(site_id) WHERE status IN ('queued', 'running');
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_publish_job_active;
+DROP INDEX IF EXISTS idx_publish_job_site;
+DROP TABLE IF EXISTS publish_job;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN layout_id TEXT REFERENCES layout(id) ON DELETE SET NULL;
+
+-- +migrate Down
+ALTER TABLE content DROP COLUMN layout_id;
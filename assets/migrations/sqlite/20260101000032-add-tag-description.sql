@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE tag ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE tag ADD COLUMN header_image_id TEXT REFERENCES image(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tag_header_image_id ON tag(header_image_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_tag_header_image_id;
+ALTER TABLE tag DROP COLUMN header_image_id;
+ALTER TABLE tag DROP COLUMN description;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE content ADD COLUMN link_url TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE content DROP COLUMN link_url;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE meta ADD COLUMN show_updated INTEGER DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE meta DROP COLUMN show_updated;
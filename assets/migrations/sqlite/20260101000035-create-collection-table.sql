@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS collection (
+    id TEXT PRIMARY KEY,
+    site_id TEXT NOT NULL,
+    short_id TEXT,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    FOREIGN KEY (site_id) REFERENCES site(id) ON DELETE CASCADE,
+    UNIQUE(site_id, slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_site_id ON collection(site_id);
+
+CREATE TABLE IF NOT EXISTS collection_images (
+    id TEXT PRIMARY KEY,
+    collection_id TEXT NOT NULL,
+    image_id TEXT NOT NULL,
+    created_at TIMESTAMP,
+    FOREIGN KEY (collection_id) REFERENCES collection(id) ON DELETE CASCADE,
+    FOREIGN KEY (image_id) REFERENCES image(id) ON DELETE CASCADE,
+    UNIQUE(collection_id, image_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_images_image_id ON collection_images(image_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS collection_images;
+DROP TABLE IF EXISTS collection;
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS content_field (
+    id TEXT PRIMARY KEY,
+    content_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'string',
+    value TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (content_id) REFERENCES content(id) ON DELETE CASCADE,
+    UNIQUE(content_id, name)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS content_field;
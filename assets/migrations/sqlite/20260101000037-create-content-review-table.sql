@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS content_review (
+    content_id TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL DEFAULT '',
+    submitted_by TEXT NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    submitted_at TIMESTAMP NOT NULL,
+    reviewed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (content_id) REFERENCES content(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_review_status ON content_review(status);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_content_review_status;
+DROP TABLE IF EXISTS content_review;
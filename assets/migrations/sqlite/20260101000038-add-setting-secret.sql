@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE setting ADD COLUMN secret INTEGER DEFAULT 0;
+
+-- Settings known to hold credentials. Their plaintext values are encrypted
+-- when Clio starts with an encryption key.
+UPDATE setting SET secret = 1
+WHERE ref_key IN ('ssg.publish.auth.token', 'ssg.backup.auth.token', 'ssg.git.ssh.key', 'ssg.git.ssh.passphrase', 'ssg.git.signing.key');
+
+-- +migrate Down
+ALTER TABLE setting DROP COLUMN secret;
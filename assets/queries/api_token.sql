@@ -14,3 +14,8 @@ DELETE FROM api_token WHERE id = ?;
 
 -- name: UpdateAPITokenLastUsed :exec
 UPDATE api_token SET last_used_at = ? WHERE id = ?;
+
+-- name: PurgeExpiredAPITokens :execrows
+DELETE FROM api_token WHERE id IN (
+    SELECT id FROM api_token WHERE expires_at IS NOT NULL AND expires_at <= ? LIMIT ?
+);
@@ -59,3 +59,22 @@ DELETE FROM session WHERE expires_at <= datetime('now');
 
 -- name: DeleteUserSessions :exec
 DELETE FROM session WHERE user_id = ?;
+
+-- name: PurgeExpiredSessions :execrows
+DELETE FROM session WHERE id IN (
+    SELECT id FROM session WHERE expires_at <= ? LIMIT ?
+);
+
+-- name: GetLoginFailure :one
+SELECT * FROM login_failure WHERE user_id = ?;
+
+-- name: UpsertLoginFailure :exec
+INSERT INTO login_failure (user_id, failures, first_failed_at, locked_until)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    failures = excluded.failures,
+    first_failed_at = excluded.first_failed_at,
+    locked_until = excluded.locked_until;
+
+-- name: DeleteLoginFailure :exec
+DELETE FROM login_failure WHERE user_id = ?;
@@ -0,0 +1,44 @@
+-- name: CreateCollection :one
+INSERT INTO collection (id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetCollection :one
+SELECT * FROM collection WHERE id = ?;
+
+-- name: GetCollectionsBySiteID :many
+SELECT c.*, (SELECT COUNT(*) FROM collection_images ci WHERE ci.collection_id = c.id) AS image_count
+FROM collection c
+WHERE c.site_id = ?
+ORDER BY c.name;
+
+-- name: GetCollectionsByImageID :many
+SELECT c.* FROM collection c
+JOIN collection_images ci ON ci.collection_id = c.id
+WHERE ci.image_id = ?
+ORDER BY c.name;
+
+-- name: UpdateCollection :one
+UPDATE collection SET
+    name = ?,
+    slug = ?,
+    updated_by = ?,
+    updated_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteCollection :exec
+DELETE FROM collection WHERE id = ?;
+
+-- name: AddImageToCollection :exec
+INSERT OR IGNORE INTO collection_images (id, collection_id, image_id, created_at)
+VALUES (?, ?, ?, ?);
+
+-- name: RemoveImageFromCollection :exec
+DELETE FROM collection_images WHERE collection_id = ? AND image_id = ?;
+
+-- name: GetImagesByCollectionID :many
+SELECT i.* FROM image i
+JOIN collection_images ci ON ci.image_id = i.id
+WHERE ci.collection_id = ?
+ORDER BY i.created_at DESC;
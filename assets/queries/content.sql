@@ -1,6 +1,6 @@
 -- name: CreateContent :one
-INSERT INTO content (id, site_id, user_id, short_id, section_id, contributor_id, contributor_handle, author_username, kind, heading, summary, body, draft, featured, series, series_order, published_at, hero_title_dark, images_meta, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO content (id, site_id, user_id, short_id, section_id, contributor_id, contributor_handle, author_username, kind, heading, summary, body, draft, featured, series, series_order, published_at, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetContent :one
@@ -9,11 +9,32 @@ SELECT * FROM content WHERE id = ?;
 -- name: GetContentBySiteID :many
 SELECT * FROM content WHERE site_id = ? ORDER BY created_at DESC;
 
+-- name: GetContentBySiteIDAndKind :many
+SELECT * FROM content WHERE site_id = ? AND COALESCE(NULLIF(kind, ''), 'post') = ? ORDER BY created_at DESC;
+
+-- name: GetContentByTranslationGroup :many
+SELECT * FROM content WHERE site_id = ? AND translation_group = ? ORDER BY lang;
+
 -- name: GetContentBySectionID :many
 SELECT * FROM content WHERE section_id = ? ORDER BY created_at DESC;
 
 -- name: GetPublishedContentBySiteID :many
-SELECT * FROM content WHERE site_id = ? AND draft = 0 ORDER BY published_at DESC;
+SELECT * FROM content WHERE site_id = ? AND draft = 0 AND visibility != 'private' ORDER BY published_at DESC;
+
+-- name: GetFeaturedContent :many
+SELECT * FROM content
+WHERE site_id = sqlc.arg(site_id) AND featured = 1 AND draft = 0 AND visibility = 'public'
+  AND (published_at IS NULL OR julianday(published_at) <= julianday(sqlc.arg(now)))
+ORDER BY published_at DESC
+LIMIT sqlc.arg(limit);
+
+-- name: GetContentByDateRange :many
+SELECT * FROM content
+WHERE site_id = sqlc.arg(site_id) AND draft = 0 AND visibility = 'public' AND published_at IS NOT NULL
+  AND julianday(published_at) >= julianday(sqlc.arg(from_date))
+  AND julianday(published_at) < julianday(sqlc.arg(to_date))
+  AND julianday(published_at) <= julianday(sqlc.arg(now))
+ORDER BY published_at DESC;
 
 -- name: GetContentWithMeta :one
 SELECT
@@ -36,16 +57,28 @@ SELECT
     m.summary as meta_summary,
     m.description as meta_description,
     m.keywords as meta_keywords,
+    m.robots as meta_robots,
+    m.canonical_url as meta_canonical_url,
+    m.sitemap as meta_sitemap,
+    m.table_of_contents as meta_table_of_contents,
+    m.share as meta_share,
+    m.comments as meta_comments,
+    m.keep_links as meta_keep_links,
+    m.show_updated as meta_show_updated,
     hi.file_path as header_image_path,
     hi.alt_text as header_image_alt,
     hi.title as header_image_caption,
     hi.attribution as header_image_attribution,
-    hi.attribution_url as header_image_attribution_url
+    hi.attribution_url as header_image_attribution_url,
+    hi.width as header_image_width,
+    hi.height as header_image_height,
+    cr.status as review_status
 FROM content c
 LEFT JOIN section s ON c.section_id = s.id
 LEFT JOIN meta m ON c.id = m.content_id
 LEFT JOIN content_images ci ON c.id = ci.content_id AND ci.is_header = 1
 LEFT JOIN image hi ON ci.image_id = hi.id
+LEFT JOIN content_review cr ON c.id = cr.content_id
 WHERE c.site_id = ?
 ORDER BY c.created_at DESC;
 
@@ -58,6 +91,69 @@ LIMIT ? OFFSET ?;
 -- name: CountContent :one
 SELECT COUNT(*) FROM content WHERE site_id = ?;
 
+-- name: CountContentByStatus :one
+SELECT
+    CAST(COALESCE(SUM(CASE WHEN draft = 0 AND (published_at IS NULL OR julianday(published_at) <= julianday(sqlc.arg(now))) THEN 1 ELSE 0 END), 0) AS INTEGER) AS published,
+    CAST(COALESCE(SUM(CASE WHEN draft = 1 THEN 1 ELSE 0 END), 0) AS INTEGER) AS drafts,
+    CAST(COALESCE(SUM(CASE WHEN draft = 0 AND julianday(published_at) > julianday(sqlc.arg(now)) THEN 1 ELSE 0 END), 0) AS INTEGER) AS scheduled
+FROM content
+WHERE site_id = sqlc.arg(site_id);
+
+-- name: GetContentEditedSince :many
+SELECT * FROM content
+WHERE site_id = sqlc.arg(site_id) AND julianday(updated_at) > julianday(sqlc.arg(since))
+ORDER BY updated_at DESC;
+
+-- name: GetStaleDrafts :many
+SELECT * FROM content
+WHERE site_id = sqlc.arg(site_id) AND draft = 1 AND julianday(updated_at) < julianday(sqlc.arg(before))
+ORDER BY updated_at;
+
+-- name: GetContentByContributor :many
+SELECT * FROM content
+WHERE contributor_id = ?
+ORDER BY COALESCE(published_at, created_at) DESC;
+
+-- name: GetRecentlyUpdatedContent :many
+SELECT * FROM content
+WHERE site_id = ?
+ORDER BY updated_at DESC
+LIMIT ?;
+
+-- name: ListFilteredContent :many
+SELECT * FROM content
+WHERE site_id = sqlc.arg(site_id)
+  AND (sqlc.arg(search) = '' OR heading LIKE sqlc.arg(search))
+  AND (sqlc.arg(section_id) = '' OR section_id = sqlc.arg(section_id))
+  AND (sqlc.arg(contributor_id) = '' OR contributor_id = sqlc.arg(contributor_id))
+  AND (sqlc.arg(tag) = '' OR id IN (
+      SELECT ct.content_id FROM content_tag ct
+      JOIN tag t ON t.id = ct.tag_id
+      WHERE t.site_id = sqlc.arg(site_id) AND t.slug = sqlc.arg(tag)))
+  AND (sqlc.arg(status) = ''
+      OR (sqlc.arg(status) = 'draft' AND draft = 1)
+      OR (sqlc.arg(status) = 'published' AND draft = 0 AND (published_at IS NULL OR julianday(published_at) <= julianday(sqlc.arg(now))))
+      OR (sqlc.arg(status) = 'scheduled' AND draft = 0 AND julianday(published_at) > julianday(sqlc.arg(now))))
+  AND (sqlc.arg(visibility) = '' OR visibility = sqlc.arg(visibility))
+ORDER BY created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountFilteredContent :one
+SELECT COUNT(*) FROM content
+WHERE site_id = sqlc.arg(site_id)
+  AND (sqlc.arg(search) = '' OR heading LIKE sqlc.arg(search))
+  AND (sqlc.arg(section_id) = '' OR section_id = sqlc.arg(section_id))
+  AND (sqlc.arg(contributor_id) = '' OR contributor_id = sqlc.arg(contributor_id))
+  AND (sqlc.arg(tag) = '' OR id IN (
+      SELECT ct.content_id FROM content_tag ct
+      JOIN tag t ON t.id = ct.tag_id
+      WHERE t.site_id = sqlc.arg(site_id) AND t.slug = sqlc.arg(tag)))
+  AND (sqlc.arg(status) = ''
+      OR (sqlc.arg(status) = 'draft' AND draft = 1)
+      OR (sqlc.arg(status) = 'published' AND draft = 0 AND (published_at IS NULL OR julianday(published_at) <= julianday(sqlc.arg(now))))
+      OR (sqlc.arg(status) = 'scheduled' AND draft = 0 AND julianday(published_at) > julianday(sqlc.arg(now))))
+  AND (sqlc.arg(visibility) = '' OR visibility = sqlc.arg(visibility));
+
 -- name: SearchContent :many
 SELECT * FROM content
 WHERE site_id = ? AND heading LIKE ?
@@ -84,10 +180,37 @@ UPDATE content SET
     published_at = ?,
     hero_title_dark = ?,
     images_meta = ?,
+    visibility = ?,
+    lang = ?,
+    translation_group = ?,
+    layout_id = ?,
+    link_url = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
 RETURNING *;
 
+-- name: UpdateContentBody :exec
+UPDATE content SET
+    body = ?,
+    images_meta = ?,
+    updated_by = ?,
+    updated_at = ?
+WHERE id = ?;
+
+-- name: MoveContent :exec
+UPDATE content SET
+    site_id = ?,
+    section_id = ?,
+    contributor_id = ?,
+    contributor_handle = ?,
+    author_username = ?,
+    updated_by = ?,
+    updated_at = ?
+WHERE id = ?;
+
+-- name: SetContentTranslationGroup :exec
+UPDATE content SET translation_group = ? WHERE id = ?;
+
 -- name: DeleteContent :exec
 DELETE FROM content WHERE id = ?;
@@ -0,0 +1,19 @@
+-- name: AcquireContentEditLock :execrows
+INSERT INTO content_edit_lock (content_id, user_id, user_name, acquired_at, expires_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (content_id) DO UPDATE SET
+    user_id = excluded.user_id,
+    user_name = excluded.user_name,
+    acquired_at = CASE WHEN content_edit_lock.user_id = excluded.user_id THEN content_edit_lock.acquired_at ELSE excluded.acquired_at END,
+    expires_at = excluded.expires_at
+WHERE content_edit_lock.user_id = excluded.user_id
+   OR julianday(content_edit_lock.expires_at) <= julianday(excluded.acquired_at);
+
+-- name: GetContentEditLock :one
+SELECT * FROM content_edit_lock WHERE content_id = ?;
+
+-- name: ReleaseContentEditLock :exec
+DELETE FROM content_edit_lock WHERE content_id = ? AND user_id = ?;
+
+-- name: DeleteContentEditLock :exec
+DELETE FROM content_edit_lock WHERE content_id = ?;
@@ -0,0 +1,22 @@
+-- name: GetContentFieldsByContentID :many
+SELECT * FROM content_field WHERE content_id = ? ORDER BY name;
+
+-- name: GetContentFieldsBySiteID :many
+SELECT cf.* FROM content_field cf
+JOIN content c ON c.id = cf.content_id
+WHERE c.site_id = ?
+ORDER BY cf.content_id, cf.name;
+
+-- name: UpsertContentField :exec
+INSERT INTO content_field (id, content_id, name, type, value, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (content_id, name) DO UPDATE SET
+    type = excluded.type,
+    value = excluded.value,
+    updated_at = excluded.updated_at;
+
+-- name: DeleteContentField :exec
+DELETE FROM content_field WHERE content_id = ? AND name = ?;
+
+-- name: DeleteContentFieldsByContentID :exec
+DELETE FROM content_field WHERE content_id = ?;
@@ -0,0 +1,17 @@
+-- name: GetContentKindsBySiteID :many
+SELECT * FROM content_kind WHERE site_id = ? ORDER BY name;
+
+-- name: UpsertContentKind :exec
+INSERT INTO content_kind (id, site_id, name, label, permalink, listed, feed, redirect, layout_id, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (site_id, name) DO UPDATE SET
+    label = excluded.label,
+    permalink = excluded.permalink,
+    listed = excluded.listed,
+    feed = excluded.feed,
+    redirect = excluded.redirect,
+    layout_id = excluded.layout_id,
+    updated_at = excluded.updated_at;
+
+-- name: DeleteContentKind :exec
+DELETE FROM content_kind WHERE site_id = ? AND name = ?;
@@ -0,0 +1,31 @@
+-- name: GetContentReview :one
+SELECT cr.*,
+    CAST(COALESCE(r.name, '') AS TEXT) AS reviewer_name,
+    CAST(COALESCE(s.name, '') AS TEXT) AS submitter_name
+FROM content_review cr
+LEFT JOIN user r ON r.id = cr.reviewer_id
+LEFT JOIN user s ON s.id = cr.submitted_by
+WHERE cr.content_id = ?;
+
+-- name: UpsertContentReview :exec
+INSERT INTO content_review (content_id, status, reviewer_id, submitted_by, comment, submitted_at, reviewed_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (content_id) DO UPDATE SET
+    status = excluded.status,
+    reviewer_id = excluded.reviewer_id,
+    submitted_by = excluded.submitted_by,
+    comment = excluded.comment,
+    submitted_at = excluded.submitted_at,
+    reviewed_at = excluded.reviewed_at,
+    updated_at = excluded.updated_at;
+
+-- name: GetOpenContentReviewsBySiteID :many
+SELECT cr.*, c.heading, c.short_id,
+    CAST(COALESCE(r.name, '') AS TEXT) AS reviewer_name,
+    CAST(COALESCE(s.name, '') AS TEXT) AS submitter_name
+FROM content_review cr
+JOIN content c ON c.id = cr.content_id
+LEFT JOIN user r ON r.id = cr.reviewer_id
+LEFT JOIN user s ON s.id = cr.submitted_by
+WHERE c.site_id = ? AND cr.status IN ('in_review', 'changes_requested')
+ORDER BY cr.submitted_at;
@@ -0,0 +1,19 @@
+-- name: CreateContentRevision :one
+INSERT INTO content_revision (id, content_id, heading, summary, body, created_by, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetContentRevision :one
+SELECT * FROM content_revision WHERE id = ?;
+
+-- name: ListContentRevisions :many
+SELECT * FROM content_revision WHERE content_id = ? ORDER BY created_at DESC;
+
+-- name: GetLatestContentRevision :one
+SELECT * FROM content_revision WHERE content_id = ? ORDER BY created_at DESC LIMIT 1;
+
+-- name: PruneContentRevisions :exec
+DELETE FROM content_revision
+WHERE content_id = ? AND id NOT IN (
+    SELECT id FROM content_revision WHERE content_id = ? ORDER BY created_at DESC LIMIT ?
+);
@@ -13,7 +13,7 @@ SELECT * FROM contributor WHERE site_id = ? AND handle = ?;
 SELECT * FROM contributor WHERE site_id = ? ORDER BY name, surname;
 
 -- name: ListContributorsWithProfile :many
-SELECT c.*, p.photo_path as profile_photo_path
+SELECT c.*, p.photo_path as profile_photo_path, p.bio as profile_bio, p.social_links as profile_social_links
 FROM contributor c
 LEFT JOIN profile p ON c.profile_id = p.id
 WHERE c.site_id = ?
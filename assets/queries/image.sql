@@ -1,6 +1,6 @@
 -- name: CreateImage :one
-INSERT INTO image (id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO image (id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, stock, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetImage :one
@@ -15,6 +15,67 @@ SELECT * FROM image WHERE site_id = ? AND file_path = ?;
 -- name: GetImagesBySiteID :many
 SELECT * FROM image WHERE site_id = ? ORDER BY created_at DESC;
 
+-- name: ListFilteredImages :many
+SELECT * FROM image
+WHERE site_id = sqlc.arg(site_id)
+  AND (sqlc.arg(search) = '' OR file_name LIKE sqlc.arg(search) OR title LIKE sqlc.arg(search) OR alt_text LIKE sqlc.arg(search))
+  AND (sqlc.arg(usage) = ''
+      OR (sqlc.arg(usage) = 'used' AND (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL)))
+      OR (sqlc.arg(usage) = 'unused' AND NOT (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL))))
+  AND (sqlc.arg(collection_id) = '' OR id IN (SELECT image_id FROM collection_images WHERE collection_id = sqlc.arg(collection_id)))
+ORDER BY created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountFilteredImages :one
+SELECT COUNT(*) FROM image
+WHERE site_id = sqlc.arg(site_id)
+  AND (sqlc.arg(search) = '' OR file_name LIKE sqlc.arg(search) OR title LIKE sqlc.arg(search) OR alt_text LIKE sqlc.arg(search))
+  AND (sqlc.arg(usage) = ''
+      OR (sqlc.arg(usage) = 'used' AND (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL)))
+      OR (sqlc.arg(usage) = 'unused' AND NOT (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL))))
+  AND (sqlc.arg(collection_id) = '' OR id IN (SELECT image_id FROM collection_images WHERE collection_id = sqlc.arg(collection_id)));
+
+-- name: GetUnlinkedImagesBySiteID :many
+SELECT * FROM image
+WHERE site_id = ?
+  AND id NOT IN (SELECT image_id FROM content_images)
+  AND id NOT IN (SELECT image_id FROM section_images)
+  AND id NOT IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+  AND id NOT IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL)
+ORDER BY created_at DESC;
+
+-- name: GetContentUsingImage :many
+SELECT c.id, c.short_id, c.heading, ci.is_header
+FROM content_images ci
+JOIN content c ON ci.content_id = c.id
+WHERE ci.image_id = ?
+ORDER BY c.heading;
+
+-- name: GetSectionsUsingImage :many
+SELECT s.id, s.name, s.path, si.is_header
+FROM section_images si
+JOIN section s ON si.section_id = s.id
+WHERE si.image_id = ?
+ORDER BY s.path;
+
+-- name: GetLayoutsUsingImage :many
+SELECT id, name FROM layout WHERE header_image_id = ? ORDER BY name;
+
+-- name: GetTagsUsingImage :many
+SELECT id, name FROM tag WHERE header_image_id = ? ORDER BY name;
+
 -- name: UpdateImage :one
 UPDATE image SET
     file_name = ?,
@@ -25,6 +86,7 @@ UPDATE image SET
     attribution_url = ?,
     width = ?,
     height = ?,
+    stock = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
@@ -118,6 +180,9 @@ JOIN image i ON ci.image_id = i.id
 WHERE c.site_id = ?
 ORDER BY c.short_id, ci.order_num;
 
+-- name: UpdateContentImageImageID :exec
+UPDATE content_images SET image_id = ? WHERE id = ?;
+
 -- name: DeleteContentImage :exec
 DELETE FROM content_images WHERE id = ?;
 
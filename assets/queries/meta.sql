@@ -1,6 +1,6 @@
 -- name: CreateMeta :one
-INSERT INTO meta (id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO meta (id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, keep_links, show_updated, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetMeta :one
@@ -21,6 +21,8 @@ UPDATE meta SET
     table_of_contents = ?,
     share = ?,
     comments = ?,
+    keep_links = ?,
+    show_updated = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
@@ -31,3 +33,6 @@ DELETE FROM meta WHERE id = ?;
 
 -- name: DeleteMetaByContentID :exec
 DELETE FROM meta WHERE content_id = ?;
+
+-- name: UpdateMetaSiteID :exec
+UPDATE meta SET site_id = ? WHERE content_id = ?;
@@ -0,0 +1,35 @@
+-- name: CreatePublishJob :one
+INSERT INTO publish_job (id, site_id, status, created_by, created_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetPublishJob :one
+SELECT * FROM publish_job WHERE id = ?;
+
+-- name: GetActivePublishJob :one
+SELECT * FROM publish_job WHERE site_id = ? AND status IN ('queued', 'running') LIMIT 1;
+
+-- name: ListPublishJobsBySiteID :many
+SELECT * FROM publish_job WHERE site_id = ? ORDER BY created_at DESC LIMIT ?;
+
+-- name: UpdatePublishJob :exec
+UPDATE publish_job SET
+    status = ?,
+    progress = ?,
+    commit_hash = ?,
+    commit_url = ?,
+    no_changes = ?,
+    error = ?,
+    started_at = ?,
+    finished_at = ?
+WHERE id = ?;
+
+-- name: PrunePublishJobs :exec
+DELETE FROM publish_job
+WHERE site_id = ? AND status NOT IN ('queued', 'running') AND id NOT IN (
+    SELECT id FROM publish_job WHERE site_id = ? ORDER BY created_at DESC LIMIT ?
+);
+
+-- name: FailUnfinishedPublishJobs :execrows
+UPDATE publish_job SET status = 'failed', error = ?, finished_at = ?
+WHERE status IN ('queued', 'running');
@@ -1,6 +1,6 @@
 -- name: CreateSetting :one
-INSERT INTO setting (id, site_id, short_id, name, description, value, ref_key, category, position, system, type, constraints, ui_control, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO setting (id, site_id, short_id, name, description, value, ref_key, category, position, system, type, constraints, ui_control, secret, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetSetting :one
@@ -15,6 +15,9 @@ SELECT * FROM setting WHERE site_id = ? AND ref_key = ?;
 -- name: GetSettingsBySiteID :many
 SELECT * FROM setting WHERE site_id = ? ORDER BY category, position, name;
 
+-- name: GetSecretSettings :many
+SELECT * FROM setting WHERE secret = 1 AND value != '';
+
 -- name: UpdateSetting :one
 UPDATE setting SET
     name = ?,
@@ -27,6 +30,7 @@ UPDATE setting SET
     type = ?,
     constraints = ?,
     ui_control = ?,
+    secret = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
@@ -34,3 +38,9 @@ RETURNING *;
 
 -- name: DeleteSetting :exec
 DELETE FROM setting WHERE id = ?;
+
+-- name: UpdateSettingPosition :exec
+UPDATE setting SET position = ?, updated_at = ? WHERE id = ? AND site_id = ?;
+
+-- name: UpdateSettingValue :exec
+UPDATE setting SET value = ? WHERE id = ?;
@@ -28,5 +28,21 @@ UPDATE site SET
 WHERE id = ?
 RETURNING *;
 
+-- name: MarkSiteGenerated :exec
+UPDATE site SET last_generated_at = ? WHERE id = ?;
+
+-- name: MarkSitePublished :exec
+UPDATE site SET
+    last_published_at = ?,
+    last_published_commit = COALESCE(?, last_published_commit)
+WHERE id = ?;
+
+-- name: GetSiteCounts :one
+SELECT
+    (SELECT COUNT(*) FROM section WHERE section.site_id = sqlc.arg(site_id)) AS sections,
+    (SELECT COUNT(*) FROM tag WHERE tag.site_id = sqlc.arg(site_id)) AS tags,
+    (SELECT COUNT(*) FROM image WHERE image.site_id = sqlc.arg(site_id)) AS images,
+    (SELECT COUNT(*) FROM contributor WHERE contributor.site_id = sqlc.arg(site_id)) AS contributors;
+
 -- name: DeleteSite :exec
 DELETE FROM site WHERE id = ?;
@@ -1,6 +1,6 @@
 -- name: CreateTag :one
-INSERT INTO tag (id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO tag (id, site_id, short_id, name, slug, description, header_image_id, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetTag :one
@@ -19,6 +19,8 @@ SELECT * FROM tag WHERE site_id = ? ORDER BY name;
 UPDATE tag SET
     name = ?,
     slug = ?,
+    description = ?,
+    header_image_id = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
@@ -38,8 +40,12 @@ DELETE FROM content_tag WHERE content_id = ? AND tag_id = ?;
 DELETE FROM content_tag WHERE content_id = ?;
 
 -- name: GetTagsForContent :many
-SELECT t.* FROM tag t
+SELECT
+    t.*,
+    hi.file_path as header_image_path
+FROM tag t
 JOIN content_tag ct ON t.id = ct.tag_id
+LEFT JOIN image hi ON t.header_image_id = hi.id
 WHERE ct.content_id = ?
 ORDER BY t.name;
 
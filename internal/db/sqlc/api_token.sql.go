@@ -111,6 +111,25 @@ func (q *Queries) ListAPITokensByUser(ctx context.Context, userID string) ([]Api
 	return items, nil
 }
 
+const purgeExpiredAPITokens = `-- name: PurgeExpiredAPITokens :execrows
+DELETE FROM api_token WHERE id IN (
+    SELECT id FROM api_token WHERE expires_at IS NOT NULL AND expires_at <= ? LIMIT ?
+)
+`
+
+type PurgeExpiredAPITokensParams struct {
+	ExpiresAt sql.NullTime `json:"expires_at"`
+	Limit     int64        `json:"limit"`
+}
+
+func (q *Queries) PurgeExpiredAPITokens(ctx context.Context, arg PurgeExpiredAPITokensParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeExpiredAPITokens, arg.ExpiresAt, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateAPITokenLastUsed = `-- name: UpdateAPITokenLastUsed :exec
 UPDATE api_token SET last_used_at = ? WHERE id = ?
 `
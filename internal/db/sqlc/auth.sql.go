@@ -99,6 +99,15 @@ func (q *Queries) DeleteExpiredSessions(ctx context.Context) error {
 	return err
 }
 
+const deleteLoginFailure = `-- name: DeleteLoginFailure :exec
+DELETE FROM login_failure WHERE user_id = ?
+`
+
+func (q *Queries) DeleteLoginFailure(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteLoginFailure, userID)
+	return err
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM session WHERE id = ?
 `
@@ -126,6 +135,22 @@ func (q *Queries) DeleteUserSessions(ctx context.Context, userID string) error {
 	return err
 }
 
+const getLoginFailure = `-- name: GetLoginFailure :one
+SELECT user_id, failures, first_failed_at, locked_until FROM login_failure WHERE user_id = ?
+`
+
+func (q *Queries) GetLoginFailure(ctx context.Context, userID string) (LoginFailure, error) {
+	row := q.db.QueryRowContext(ctx, getLoginFailure, userID)
+	var i LoginFailure
+	err := row.Scan(
+		&i.UserID,
+		&i.Failures,
+		&i.FirstFailedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
 const getSession = `-- name: GetSession :one
 SELECT id, user_id, expires_at, created_at FROM session WHERE id = ?
 `
@@ -319,6 +344,25 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const purgeExpiredSessions = `-- name: PurgeExpiredSessions :execrows
+DELETE FROM session WHERE id IN (
+    SELECT id FROM session WHERE expires_at <= ? LIMIT ?
+)
+`
+
+type PurgeExpiredSessionsParams struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	Limit     int64     `json:"limit"`
+}
+
+func (q *Queries) PurgeExpiredSessions(ctx context.Context, arg PurgeExpiredSessionsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeExpiredSessions, arg.ExpiresAt, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const setUserProfile = `-- name: SetUserProfile :exec
 UPDATE user SET profile_id = ?, updated_at = ? WHERE id = ?
 `
@@ -385,3 +429,29 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 	)
 	return i, err
 }
+
+const upsertLoginFailure = `-- name: UpsertLoginFailure :exec
+INSERT INTO login_failure (user_id, failures, first_failed_at, locked_until)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    failures = excluded.failures,
+    first_failed_at = excluded.first_failed_at,
+    locked_until = excluded.locked_until
+`
+
+type UpsertLoginFailureParams struct {
+	UserID        string       `json:"user_id"`
+	Failures      int64        `json:"failures"`
+	FirstFailedAt time.Time    `json:"first_failed_at"`
+	LockedUntil   sql.NullTime `json:"locked_until"`
+}
+
+func (q *Queries) UpsertLoginFailure(ctx context.Context, arg UpsertLoginFailureParams) error {
+	_, err := q.db.ExecContext(ctx, upsertLoginFailure,
+		arg.UserID,
+		arg.Failures,
+		arg.FirstFailedAt,
+		arg.LockedUntil,
+	)
+	return err
+}
@@ -21,6 +21,77 @@ func (q *Queries) CountContent(ctx context.Context, siteID string) (int64, error
 	return count, err
 }
 
+const countContentByStatus = `-- name: CountContentByStatus :one
+SELECT
+    CAST(COALESCE(SUM(CASE WHEN draft = 0 AND (published_at IS NULL OR julianday(published_at) <= julianday(?1)) THEN 1 ELSE 0 END), 0) AS INTEGER) AS published,
+    CAST(COALESCE(SUM(CASE WHEN draft = 1 THEN 1 ELSE 0 END), 0) AS INTEGER) AS drafts,
+    CAST(COALESCE(SUM(CASE WHEN draft = 0 AND julianday(published_at) > julianday(?1) THEN 1 ELSE 0 END), 0) AS INTEGER) AS scheduled
+FROM content
+WHERE site_id = ?2
+`
+
+type CountContentByStatusParams struct {
+	Now    interface{} `json:"now"`
+	SiteID string      `json:"site_id"`
+}
+
+type CountContentByStatusRow struct {
+	Published int64 `json:"published"`
+	Drafts    int64 `json:"drafts"`
+	Scheduled int64 `json:"scheduled"`
+}
+
+func (q *Queries) CountContentByStatus(ctx context.Context, arg CountContentByStatusParams) (CountContentByStatusRow, error) {
+	row := q.db.QueryRowContext(ctx, countContentByStatus, arg.Now, arg.SiteID)
+	var i CountContentByStatusRow
+	err := row.Scan(&i.Published, &i.Drafts, &i.Scheduled)
+	return i, err
+}
+
+const countFilteredContent = `-- name: CountFilteredContent :one
+SELECT COUNT(*) FROM content
+WHERE site_id = ?1
+  AND (?2 = '' OR heading LIKE ?2)
+  AND (?3 = '' OR section_id = ?3)
+  AND (?4 = '' OR contributor_id = ?4)
+  AND (?5 = '' OR id IN (
+      SELECT ct.content_id FROM content_tag ct
+      JOIN tag t ON t.id = ct.tag_id
+      WHERE t.site_id = ?1 AND t.slug = ?5))
+  AND (?6 = ''
+      OR (?6 = 'draft' AND draft = 1)
+      OR (?6 = 'published' AND draft = 0 AND (published_at IS NULL OR julianday(published_at) <= julianday(?7)))
+      OR (?6 = 'scheduled' AND draft = 0 AND julianday(published_at) > julianday(?7)))
+  AND (?8 = '' OR visibility = ?8)
+`
+
+type CountFilteredContentParams struct {
+	SiteID        string      `json:"site_id"`
+	Search        string      `json:"search"`
+	SectionID     string      `json:"section_id"`
+	ContributorID string      `json:"contributor_id"`
+	Tag           string      `json:"tag"`
+	Status        string      `json:"status"`
+	Now           interface{} `json:"now"`
+	Visibility    string      `json:"visibility"`
+}
+
+func (q *Queries) CountFilteredContent(ctx context.Context, arg CountFilteredContentParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFilteredContent,
+		arg.SiteID,
+		arg.Search,
+		arg.SectionID,
+		arg.ContributorID,
+		arg.Tag,
+		arg.Status,
+		arg.Now,
+		arg.Visibility,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countSearchContent = `-- name: CountSearchContent :one
 SELECT COUNT(*) FROM content WHERE site_id = ? AND heading LIKE ?
 `
@@ -38,9 +109,9 @@ func (q *Queries) CountSearchContent(ctx context.Context, arg CountSearchContent
 }
 
 const createContent = `-- name: CreateContent :one
-INSERT INTO content (id, site_id, user_id, short_id, section_id, contributor_id, contributor_handle, author_username, kind, heading, summary, body, draft, featured, series, series_order, published_at, hero_title_dark, images_meta, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta
+INSERT INTO content (id, site_id, user_id, short_id, section_id, contributor_id, contributor_handle, author_username, kind, heading, summary, body, draft, featured, series, series_order, published_at, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url
 `
 
 type CreateContentParams struct {
@@ -63,6 +134,11 @@ type CreateContentParams struct {
 	PublishedAt       sql.NullTime   `json:"published_at"`
 	HeroTitleDark     sql.NullInt64  `json:"hero_title_dark"`
 	ImagesMeta        sql.NullString `json:"images_meta"`
+	Visibility        string         `json:"visibility"`
+	Lang              string         `json:"lang"`
+	TranslationGroup  string         `json:"translation_group"`
+	LayoutID          sql.NullString `json:"layout_id"`
+	LinkURL           string         `json:"link_url"`
 	CreatedBy         sql.NullString `json:"created_by"`
 	UpdatedBy         sql.NullString `json:"updated_by"`
 	CreatedAt         sql.NullTime   `json:"created_at"`
@@ -90,6 +166,11 @@ func (q *Queries) CreateContent(ctx context.Context, arg CreateContentParams) (C
 		arg.PublishedAt,
 		arg.HeroTitleDark,
 		arg.ImagesMeta,
+		arg.Visibility,
+		arg.Lang,
+		arg.TranslationGroup,
+		arg.LayoutID,
+		arg.LinkURL,
 		arg.CreatedBy,
 		arg.UpdatedBy,
 		arg.CreatedAt,
@@ -120,6 +201,11 @@ func (q *Queries) CreateContent(ctx context.Context, arg CreateContentParams) (C
 		&i.AuthorUsername,
 		&i.HeroTitleDark,
 		&i.ImagesMeta,
+		&i.Visibility,
+		&i.Lang,
+		&i.TranslationGroup,
+		&i.LayoutID,
+		&i.LinkURL,
 	)
 	return i, err
 }
@@ -135,22 +221,34 @@ func (q *Queries) DeleteContent(ctx context.Context, id string) error {
 
 const getAllContentWithMeta = `-- name: GetAllContentWithMeta :many
 SELECT
-    c.id, c.site_id, c.user_id, c.short_id, c.section_id, c.kind, c.heading, c.summary, c.body, c.draft, c.featured, c.series, c.series_order, c.published_at, c.created_by, c.updated_by, c.created_at, c.updated_at, c.contributor_id, c.contributor_handle, c.author_username, c.hero_title_dark, c.images_meta,
+    c.id, c.site_id, c.user_id, c.short_id, c.section_id, c.kind, c.heading, c.summary, c.body, c.draft, c.featured, c.series, c.series_order, c.published_at, c.created_by, c.updated_by, c.created_at, c.updated_at, c.contributor_id, c.contributor_handle, c.author_username, c.hero_title_dark, c.images_meta, c.visibility, c.lang, c.translation_group, c.layout_id, c.link_url,
     s.path as section_path,
     s.name as section_name,
     m.summary as meta_summary,
     m.description as meta_description,
     m.keywords as meta_keywords,
+    m.robots as meta_robots,
+    m.canonical_url as meta_canonical_url,
+    m.sitemap as meta_sitemap,
+    m.table_of_contents as meta_table_of_contents,
+    m.share as meta_share,
+    m.comments as meta_comments,
+    m.keep_links as meta_keep_links,
+    m.show_updated as meta_show_updated,
     hi.file_path as header_image_path,
     hi.alt_text as header_image_alt,
     hi.title as header_image_caption,
     hi.attribution as header_image_attribution,
-    hi.attribution_url as header_image_attribution_url
+    hi.attribution_url as header_image_attribution_url,
+    hi.width as header_image_width,
+    hi.height as header_image_height,
+    cr.status as review_status
 FROM content c
 LEFT JOIN section s ON c.section_id = s.id
 LEFT JOIN meta m ON c.id = m.content_id
 LEFT JOIN content_images ci ON c.id = ci.content_id AND ci.is_header = 1
 LEFT JOIN image hi ON ci.image_id = hi.id
+LEFT JOIN content_review cr ON c.id = cr.content_id
 WHERE c.site_id = ?
 ORDER BY c.created_at DESC
 `
@@ -179,16 +277,32 @@ type GetAllContentWithMetaRow struct {
 	AuthorUsername            string         `json:"author_username"`
 	HeroTitleDark             sql.NullInt64  `json:"hero_title_dark"`
 	ImagesMeta                sql.NullString `json:"images_meta"`
+	Visibility                string         `json:"visibility"`
+	Lang                      string         `json:"lang"`
+	TranslationGroup          string         `json:"translation_group"`
+	LayoutID                  sql.NullString `json:"layout_id"`
+	LinkURL                   string         `json:"link_url"`
 	SectionPath               sql.NullString `json:"section_path"`
 	SectionName               sql.NullString `json:"section_name"`
 	MetaSummary               sql.NullString `json:"meta_summary"`
 	MetaDescription           sql.NullString `json:"meta_description"`
 	MetaKeywords              sql.NullString `json:"meta_keywords"`
+	MetaRobots                sql.NullString `json:"meta_robots"`
+	MetaCanonicalUrl          sql.NullString `json:"meta_canonical_url"`
+	MetaSitemap               sql.NullString `json:"meta_sitemap"`
+	MetaTableOfContents       sql.NullInt64  `json:"meta_table_of_contents"`
+	MetaShare                 sql.NullInt64  `json:"meta_share"`
+	MetaComments              sql.NullInt64  `json:"meta_comments"`
+	MetaKeepLinks             sql.NullInt64  `json:"meta_keep_links"`
+	MetaShowUpdated           sql.NullInt64  `json:"meta_show_updated"`
 	HeaderImagePath           sql.NullString `json:"header_image_path"`
 	HeaderImageAlt            sql.NullString `json:"header_image_alt"`
 	HeaderImageCaption        sql.NullString `json:"header_image_caption"`
 	HeaderImageAttribution    sql.NullString `json:"header_image_attribution"`
 	HeaderImageAttributionUrl sql.NullString `json:"header_image_attribution_url"`
+	HeaderImageWidth          sql.NullInt64  `json:"header_image_width"`
+	HeaderImageHeight         sql.NullInt64  `json:"header_image_height"`
+	ReviewStatus              sql.NullString `json:"review_status"`
 }
 
 func (q *Queries) GetAllContentWithMeta(ctx context.Context, siteID string) ([]GetAllContentWithMetaRow, error) {
@@ -224,16 +338,32 @@ func (q *Queries) GetAllContentWithMeta(ctx context.Context, siteID string) ([]G
 			&i.AuthorUsername,
 			&i.HeroTitleDark,
 			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
 			&i.SectionPath,
 			&i.SectionName,
 			&i.MetaSummary,
 			&i.MetaDescription,
 			&i.MetaKeywords,
+			&i.MetaRobots,
+			&i.MetaCanonicalUrl,
+			&i.MetaSitemap,
+			&i.MetaTableOfContents,
+			&i.MetaShare,
+			&i.MetaComments,
+			&i.MetaKeepLinks,
+			&i.MetaShowUpdated,
 			&i.HeaderImagePath,
 			&i.HeaderImageAlt,
 			&i.HeaderImageCaption,
 			&i.HeaderImageAttribution,
 			&i.HeaderImageAttributionUrl,
+			&i.HeaderImageWidth,
+			&i.HeaderImageHeight,
+			&i.ReviewStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -249,7 +379,7 @@ func (q *Queries) GetAllContentWithMeta(ctx context.Context, siteID string) ([]G
 }
 
 const getContent = `-- name: GetContent :one
-SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta FROM content WHERE id = ?
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content WHERE id = ?
 `
 
 func (q *Queries) GetContent(ctx context.Context, id string) (Content, error) {
@@ -279,12 +409,17 @@ func (q *Queries) GetContent(ctx context.Context, id string) (Content, error) {
 		&i.AuthorUsername,
 		&i.HeroTitleDark,
 		&i.ImagesMeta,
+		&i.Visibility,
+		&i.Lang,
+		&i.TranslationGroup,
+		&i.LayoutID,
+		&i.LinkURL,
 	)
 	return i, err
 }
 
 const getContentBySectionID = `-- name: GetContentBySectionID :many
-SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta FROM content WHERE section_id = ? ORDER BY created_at DESC
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content WHERE section_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetContentBySectionID(ctx context.Context, sectionID sql.NullString) ([]Content, error) {
@@ -320,6 +455,11 @@ func (q *Queries) GetContentBySectionID(ctx context.Context, sectionID sql.NullS
 			&i.AuthorUsername,
 			&i.HeroTitleDark,
 			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
 		); err != nil {
 			return nil, err
 		}
@@ -335,7 +475,7 @@ func (q *Queries) GetContentBySectionID(ctx context.Context, sectionID sql.NullS
 }
 
 const getContentBySiteID = `-- name: GetContentBySiteID :many
-SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta FROM content WHERE site_id = ? ORDER BY created_at DESC
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content WHERE site_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetContentBySiteID(ctx context.Context, siteID string) ([]Content, error) {
@@ -371,6 +511,11 @@ func (q *Queries) GetContentBySiteID(ctx context.Context, siteID string) ([]Cont
 			&i.AuthorUsername,
 			&i.HeroTitleDark,
 			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
 		); err != nil {
 			return nil, err
 		}
@@ -385,102 +530,78 @@ func (q *Queries) GetContentBySiteID(ctx context.Context, siteID string) ([]Cont
 	return items, nil
 }
 
-const getContentWithMeta = `-- name: GetContentWithMeta :one
-SELECT
-    c.id, c.site_id, c.user_id, c.short_id, c.section_id, c.kind, c.heading, c.summary, c.body, c.draft, c.featured, c.series, c.series_order, c.published_at, c.created_by, c.updated_by, c.created_at, c.updated_at, c.contributor_id, c.contributor_handle, c.author_username, c.hero_title_dark, c.images_meta,
-    s.path as section_path,
-    s.name as section_name,
-    m.summary as meta_summary,
-    m.description as meta_description,
-    m.keywords as meta_keywords
-FROM content c
-LEFT JOIN section s ON c.section_id = s.id
-LEFT JOIN meta m ON c.id = m.content_id
-WHERE c.id = ?
+const getContentBySiteIDAndKind = `-- name: GetContentBySiteIDAndKind :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content WHERE site_id = ? AND COALESCE(NULLIF(kind, ''), 'post') = ? ORDER BY created_at DESC
 `
 
-type GetContentWithMetaRow struct {
-	ID                string         `json:"id"`
-	SiteID            string         `json:"site_id"`
-	UserID            sql.NullString `json:"user_id"`
-	ShortID           sql.NullString `json:"short_id"`
-	SectionID         sql.NullString `json:"section_id"`
-	Kind              sql.NullString `json:"kind"`
-	Heading           string         `json:"heading"`
-	Summary           sql.NullString `json:"summary"`
-	Body              sql.NullString `json:"body"`
-	Draft             sql.NullInt64  `json:"draft"`
-	Featured          sql.NullInt64  `json:"featured"`
-	Series            sql.NullString `json:"series"`
-	SeriesOrder       sql.NullInt64  `json:"series_order"`
-	PublishedAt       sql.NullTime   `json:"published_at"`
-	CreatedBy         sql.NullString `json:"created_by"`
-	UpdatedBy         sql.NullString `json:"updated_by"`
-	CreatedAt         sql.NullTime   `json:"created_at"`
-	UpdatedAt         sql.NullTime   `json:"updated_at"`
-	ContributorID     sql.NullString `json:"contributor_id"`
-	ContributorHandle string         `json:"contributor_handle"`
-	AuthorUsername    string         `json:"author_username"`
-	HeroTitleDark     sql.NullInt64  `json:"hero_title_dark"`
-	ImagesMeta        sql.NullString `json:"images_meta"`
-	SectionPath       sql.NullString `json:"section_path"`
-	SectionName       sql.NullString `json:"section_name"`
-	MetaSummary       sql.NullString `json:"meta_summary"`
-	MetaDescription   sql.NullString `json:"meta_description"`
-	MetaKeywords      sql.NullString `json:"meta_keywords"`
+type GetContentBySiteIDAndKindParams struct {
+	SiteID string `json:"site_id"`
+	Kind   string `json:"kind"`
 }
 
-func (q *Queries) GetContentWithMeta(ctx context.Context, id string) (GetContentWithMetaRow, error) {
-	row := q.db.QueryRowContext(ctx, getContentWithMeta, id)
-	var i GetContentWithMetaRow
-	err := row.Scan(
-		&i.ID,
-		&i.SiteID,
-		&i.UserID,
-		&i.ShortID,
-		&i.SectionID,
-		&i.Kind,
-		&i.Heading,
-		&i.Summary,
-		&i.Body,
-		&i.Draft,
-		&i.Featured,
-		&i.Series,
-		&i.SeriesOrder,
-		&i.PublishedAt,
-		&i.CreatedBy,
-		&i.UpdatedBy,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.ContributorID,
-		&i.ContributorHandle,
-		&i.AuthorUsername,
-		&i.HeroTitleDark,
-		&i.ImagesMeta,
-		&i.SectionPath,
-		&i.SectionName,
-		&i.MetaSummary,
-		&i.MetaDescription,
-		&i.MetaKeywords,
-	)
-	return i, err
+func (q *Queries) GetContentBySiteIDAndKind(ctx context.Context, arg GetContentBySiteIDAndKindParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getContentBySiteIDAndKind, arg.SiteID, arg.Kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getContentWithPagination = `-- name: GetContentWithPagination :many
-SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta FROM content
-WHERE site_id = ?
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+const getContentByTranslationGroup = `-- name: GetContentByTranslationGroup :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content WHERE site_id = ? AND translation_group = ? ORDER BY lang
 `
 
-type GetContentWithPaginationParams struct {
-	SiteID string `json:"site_id"`
-	Limit  int64  `json:"limit"`
-	Offset int64  `json:"offset"`
+type GetContentByTranslationGroupParams struct {
+	SiteID           string `json:"site_id"`
+	TranslationGroup string `json:"translation_group"`
 }
 
-func (q *Queries) GetContentWithPagination(ctx context.Context, arg GetContentWithPaginationParams) ([]Content, error) {
-	rows, err := q.db.QueryContext(ctx, getContentWithPagination, arg.SiteID, arg.Limit, arg.Offset)
+func (q *Queries) GetContentByTranslationGroup(ctx context.Context, arg GetContentByTranslationGroupParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getContentByTranslationGroup, arg.SiteID, arg.TranslationGroup)
 	if err != nil {
 		return nil, err
 	}
@@ -512,6 +633,11 @@ func (q *Queries) GetContentWithPagination(ctx context.Context, arg GetContentWi
 			&i.AuthorUsername,
 			&i.HeroTitleDark,
 			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
 		); err != nil {
 			return nil, err
 		}
@@ -526,12 +652,14 @@ func (q *Queries) GetContentWithPagination(ctx context.Context, arg GetContentWi
 	return items, nil
 }
 
-const getPublishedContentBySiteID = `-- name: GetPublishedContentBySiteID :many
-SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta FROM content WHERE site_id = ? AND draft = 0 ORDER BY published_at DESC
+const getContentByContributor = `-- name: GetContentByContributor :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE contributor_id = ?
+ORDER BY COALESCE(published_at, created_at) DESC
 `
 
-func (q *Queries) GetPublishedContentBySiteID(ctx context.Context, siteID string) ([]Content, error) {
-	rows, err := q.db.QueryContext(ctx, getPublishedContentBySiteID, siteID)
+func (q *Queries) GetContentByContributor(ctx context.Context, contributorID sql.NullString) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getContentByContributor, contributorID)
 	if err != nil {
 		return nil, err
 	}
@@ -563,6 +691,11 @@ func (q *Queries) GetPublishedContentBySiteID(ctx context.Context, siteID string
 			&i.AuthorUsername,
 			&i.HeroTitleDark,
 			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
 		); err != nil {
 			return nil, err
 		}
@@ -577,26 +710,28 @@ func (q *Queries) GetPublishedContentBySiteID(ctx context.Context, siteID string
 	return items, nil
 }
 
-const searchContent = `-- name: SearchContent :many
-SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta FROM content
-WHERE site_id = ? AND heading LIKE ?
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+const getContentByDateRange = `-- name: GetContentByDateRange :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ?1 AND draft = 0 AND visibility = 'public' AND published_at IS NOT NULL
+  AND julianday(published_at) >= julianday(?2)
+  AND julianday(published_at) < julianday(?3)
+  AND julianday(published_at) <= julianday(?4)
+ORDER BY published_at DESC
 `
 
-type SearchContentParams struct {
-	SiteID  string `json:"site_id"`
-	Heading string `json:"heading"`
-	Limit   int64  `json:"limit"`
-	Offset  int64  `json:"offset"`
+type GetContentByDateRangeParams struct {
+	SiteID   string      `json:"site_id"`
+	FromDate interface{} `json:"from_date"`
+	ToDate   interface{} `json:"to_date"`
+	Now      interface{} `json:"now"`
 }
 
-func (q *Queries) SearchContent(ctx context.Context, arg SearchContentParams) ([]Content, error) {
-	rows, err := q.db.QueryContext(ctx, searchContent,
+func (q *Queries) GetContentByDateRange(ctx context.Context, arg GetContentByDateRangeParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getContentByDateRange,
 		arg.SiteID,
-		arg.Heading,
-		arg.Limit,
-		arg.Offset,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Now,
 	)
 	if err != nil {
 		return nil, err
@@ -629,6 +764,11 @@ func (q *Queries) SearchContent(ctx context.Context, arg SearchContentParams) ([
 			&i.AuthorUsername,
 			&i.HeroTitleDark,
 			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
 		); err != nil {
 			return nil, err
 		}
@@ -643,96 +783,829 @@ func (q *Queries) SearchContent(ctx context.Context, arg SearchContentParams) ([
 	return items, nil
 }
 
-const updateContent = `-- name: UpdateContent :one
-UPDATE content SET
-    section_id = ?,
-    contributor_id = ?,
-    contributor_handle = ?,
-    author_username = ?,
-    kind = ?,
-    heading = ?,
-    summary = ?,
-    body = ?,
-    draft = ?,
-    featured = ?,
-    series = ?,
-    series_order = ?,
-    published_at = ?,
-    hero_title_dark = ?,
-    images_meta = ?,
-    updated_by = ?,
-    updated_at = ?
-WHERE id = ?
-RETURNING id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta
+const getContentEditedSince = `-- name: GetContentEditedSince :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ?1 AND julianday(updated_at) > julianday(?2)
+ORDER BY updated_at DESC
 `
 
-type UpdateContentParams struct {
-	SectionID         sql.NullString `json:"section_id"`
-	ContributorID     sql.NullString `json:"contributor_id"`
-	ContributorHandle string         `json:"contributor_handle"`
-	AuthorUsername    string         `json:"author_username"`
-	Kind              sql.NullString `json:"kind"`
-	Heading           string         `json:"heading"`
-	Summary           sql.NullString `json:"summary"`
-	Body              sql.NullString `json:"body"`
-	Draft             sql.NullInt64  `json:"draft"`
-	Featured          sql.NullInt64  `json:"featured"`
-	Series            sql.NullString `json:"series"`
-	SeriesOrder       sql.NullInt64  `json:"series_order"`
-	PublishedAt       sql.NullTime   `json:"published_at"`
-	HeroTitleDark     sql.NullInt64  `json:"hero_title_dark"`
-	ImagesMeta        sql.NullString `json:"images_meta"`
-	UpdatedBy         sql.NullString `json:"updated_by"`
-	UpdatedAt         sql.NullTime   `json:"updated_at"`
-	ID                string         `json:"id"`
+type GetContentEditedSinceParams struct {
+	SiteID string      `json:"site_id"`
+	Since  interface{} `json:"since"`
 }
 
-func (q *Queries) UpdateContent(ctx context.Context, arg UpdateContentParams) (Content, error) {
-	row := q.db.QueryRowContext(ctx, updateContent,
-		arg.SectionID,
-		arg.ContributorID,
-		arg.ContributorHandle,
-		arg.AuthorUsername,
-		arg.Kind,
-		arg.Heading,
-		arg.Summary,
-		arg.Body,
-		arg.Draft,
-		arg.Featured,
-		arg.Series,
-		arg.SeriesOrder,
-		arg.PublishedAt,
-		arg.HeroTitleDark,
-		arg.ImagesMeta,
-		arg.UpdatedBy,
-		arg.UpdatedAt,
-		arg.ID,
-	)
-	var i Content
-	err := row.Scan(
-		&i.ID,
-		&i.SiteID,
-		&i.UserID,
-		&i.ShortID,
-		&i.SectionID,
-		&i.Kind,
-		&i.Heading,
-		&i.Summary,
-		&i.Body,
-		&i.Draft,
-		&i.Featured,
-		&i.Series,
-		&i.SeriesOrder,
-		&i.PublishedAt,
-		&i.CreatedBy,
-		&i.UpdatedBy,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.ContributorID,
-		&i.ContributorHandle,
-		&i.AuthorUsername,
-		&i.HeroTitleDark,
-		&i.ImagesMeta,
-	)
-	return i, err
+func (q *Queries) GetContentEditedSince(ctx context.Context, arg GetContentEditedSinceParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getContentEditedSince, arg.SiteID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContentWithMeta = `-- name: GetContentWithMeta :one
+SELECT
+    c.id, c.site_id, c.user_id, c.short_id, c.section_id, c.kind, c.heading, c.summary, c.body, c.draft, c.featured, c.series, c.series_order, c.published_at, c.created_by, c.updated_by, c.created_at, c.updated_at, c.contributor_id, c.contributor_handle, c.author_username, c.hero_title_dark, c.images_meta, c.visibility, c.lang, c.translation_group, c.layout_id, c.link_url,
+    s.path as section_path,
+    s.name as section_name,
+    m.summary as meta_summary,
+    m.description as meta_description,
+    m.keywords as meta_keywords
+FROM content c
+LEFT JOIN section s ON c.section_id = s.id
+LEFT JOIN meta m ON c.id = m.content_id
+WHERE c.id = ?
+`
+
+type GetContentWithMetaRow struct {
+	ID                string         `json:"id"`
+	SiteID            string         `json:"site_id"`
+	UserID            sql.NullString `json:"user_id"`
+	ShortID           sql.NullString `json:"short_id"`
+	SectionID         sql.NullString `json:"section_id"`
+	Kind              sql.NullString `json:"kind"`
+	Heading           string         `json:"heading"`
+	Summary           sql.NullString `json:"summary"`
+	Body              sql.NullString `json:"body"`
+	Draft             sql.NullInt64  `json:"draft"`
+	Featured          sql.NullInt64  `json:"featured"`
+	Series            sql.NullString `json:"series"`
+	SeriesOrder       sql.NullInt64  `json:"series_order"`
+	PublishedAt       sql.NullTime   `json:"published_at"`
+	CreatedBy         sql.NullString `json:"created_by"`
+	UpdatedBy         sql.NullString `json:"updated_by"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	ContributorID     sql.NullString `json:"contributor_id"`
+	ContributorHandle string         `json:"contributor_handle"`
+	AuthorUsername    string         `json:"author_username"`
+	HeroTitleDark     sql.NullInt64  `json:"hero_title_dark"`
+	ImagesMeta        sql.NullString `json:"images_meta"`
+	Visibility        string         `json:"visibility"`
+	Lang              string         `json:"lang"`
+	TranslationGroup  string         `json:"translation_group"`
+	LayoutID          sql.NullString `json:"layout_id"`
+	LinkURL           string         `json:"link_url"`
+	SectionPath       sql.NullString `json:"section_path"`
+	SectionName       sql.NullString `json:"section_name"`
+	MetaSummary       sql.NullString `json:"meta_summary"`
+	MetaDescription   sql.NullString `json:"meta_description"`
+	MetaKeywords      sql.NullString `json:"meta_keywords"`
+}
+
+func (q *Queries) GetContentWithMeta(ctx context.Context, id string) (GetContentWithMetaRow, error) {
+	row := q.db.QueryRowContext(ctx, getContentWithMeta, id)
+	var i GetContentWithMetaRow
+	err := row.Scan(
+		&i.ID,
+		&i.SiteID,
+		&i.UserID,
+		&i.ShortID,
+		&i.SectionID,
+		&i.Kind,
+		&i.Heading,
+		&i.Summary,
+		&i.Body,
+		&i.Draft,
+		&i.Featured,
+		&i.Series,
+		&i.SeriesOrder,
+		&i.PublishedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ContributorID,
+		&i.ContributorHandle,
+		&i.AuthorUsername,
+		&i.HeroTitleDark,
+		&i.ImagesMeta,
+		&i.Visibility,
+		&i.Lang,
+		&i.TranslationGroup,
+		&i.LayoutID,
+		&i.LinkURL,
+		&i.SectionPath,
+		&i.SectionName,
+		&i.MetaSummary,
+		&i.MetaDescription,
+		&i.MetaKeywords,
+	)
+	return i, err
+}
+
+const getContentWithPagination = `-- name: GetContentWithPagination :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ?
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type GetContentWithPaginationParams struct {
+	SiteID string `json:"site_id"`
+	Limit  int64  `json:"limit"`
+	Offset int64  `json:"offset"`
+}
+
+func (q *Queries) GetContentWithPagination(ctx context.Context, arg GetContentWithPaginationParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getContentWithPagination, arg.SiteID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeaturedContent = `-- name: GetFeaturedContent :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ?1 AND featured = 1 AND draft = 0 AND visibility = 'public'
+  AND (published_at IS NULL OR julianday(published_at) <= julianday(?2))
+ORDER BY published_at DESC
+LIMIT ?3
+`
+
+type GetFeaturedContentParams struct {
+	SiteID string      `json:"site_id"`
+	Now    interface{} `json:"now"`
+	Limit  int64       `json:"limit"`
+}
+
+func (q *Queries) GetFeaturedContent(ctx context.Context, arg GetFeaturedContentParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getFeaturedContent, arg.SiteID, arg.Now, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishedContentBySiteID = `-- name: GetPublishedContentBySiteID :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content WHERE site_id = ? AND draft = 0 AND visibility != 'private' ORDER BY published_at DESC
+`
+
+func (q *Queries) GetPublishedContentBySiteID(ctx context.Context, siteID string) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getPublishedContentBySiteID, siteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentlyUpdatedContent = `-- name: GetRecentlyUpdatedContent :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ?
+ORDER BY updated_at DESC
+LIMIT ?
+`
+
+type GetRecentlyUpdatedContentParams struct {
+	SiteID string `json:"site_id"`
+	Limit  int64  `json:"limit"`
+}
+
+func (q *Queries) GetRecentlyUpdatedContent(ctx context.Context, arg GetRecentlyUpdatedContentParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentlyUpdatedContent, arg.SiteID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStaleDrafts = `-- name: GetStaleDrafts :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ?1 AND draft = 1 AND julianday(updated_at) < julianday(?2)
+ORDER BY updated_at
+`
+
+type GetStaleDraftsParams struct {
+	SiteID string      `json:"site_id"`
+	Before interface{} `json:"before"`
+}
+
+func (q *Queries) GetStaleDrafts(ctx context.Context, arg GetStaleDraftsParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, getStaleDrafts, arg.SiteID, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFilteredContent = `-- name: ListFilteredContent :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ?1
+  AND (?2 = '' OR heading LIKE ?2)
+  AND (?3 = '' OR section_id = ?3)
+  AND (?4 = '' OR contributor_id = ?4)
+  AND (?5 = '' OR id IN (
+      SELECT ct.content_id FROM content_tag ct
+      JOIN tag t ON t.id = ct.tag_id
+      WHERE t.site_id = ?1 AND t.slug = ?5))
+  AND (?6 = ''
+      OR (?6 = 'draft' AND draft = 1)
+      OR (?6 = 'published' AND draft = 0 AND (published_at IS NULL OR julianday(published_at) <= julianday(?7)))
+      OR (?6 = 'scheduled' AND draft = 0 AND julianday(published_at) > julianday(?7)))
+  AND (?8 = '' OR visibility = ?8)
+ORDER BY created_at DESC
+LIMIT ?9 OFFSET ?10
+`
+
+type ListFilteredContentParams struct {
+	SiteID        string      `json:"site_id"`
+	Search        string      `json:"search"`
+	SectionID     string      `json:"section_id"`
+	ContributorID string      `json:"contributor_id"`
+	Tag           string      `json:"tag"`
+	Status        string      `json:"status"`
+	Now           interface{} `json:"now"`
+	Visibility    string      `json:"visibility"`
+	Limit         int64       `json:"limit"`
+	Offset        int64       `json:"offset"`
+}
+
+func (q *Queries) ListFilteredContent(ctx context.Context, arg ListFilteredContentParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, listFilteredContent,
+		arg.SiteID,
+		arg.Search,
+		arg.SectionID,
+		arg.ContributorID,
+		arg.Tag,
+		arg.Status,
+		arg.Now,
+		arg.Visibility,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveContent = `-- name: MoveContent :exec
+UPDATE content SET
+    site_id = ?,
+    section_id = ?,
+    contributor_id = ?,
+    contributor_handle = ?,
+    author_username = ?,
+    updated_by = ?,
+    updated_at = ?
+WHERE id = ?
+`
+
+type MoveContentParams struct {
+	SiteID            string         `json:"site_id"`
+	SectionID         sql.NullString `json:"section_id"`
+	ContributorID     sql.NullString `json:"contributor_id"`
+	ContributorHandle string         `json:"contributor_handle"`
+	AuthorUsername    string         `json:"author_username"`
+	UpdatedBy         sql.NullString `json:"updated_by"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	ID                string         `json:"id"`
+}
+
+func (q *Queries) MoveContent(ctx context.Context, arg MoveContentParams) error {
+	_, err := q.db.ExecContext(ctx, moveContent,
+		arg.SiteID,
+		arg.SectionID,
+		arg.ContributorID,
+		arg.ContributorHandle,
+		arg.AuthorUsername,
+		arg.UpdatedBy,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const searchContent = `-- name: SearchContent :many
+SELECT id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url FROM content
+WHERE site_id = ? AND heading LIKE ?
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type SearchContentParams struct {
+	SiteID  string `json:"site_id"`
+	Heading string `json:"heading"`
+	Limit   int64  `json:"limit"`
+	Offset  int64  `json:"offset"`
+}
+
+func (q *Queries) SearchContent(ctx context.Context, arg SearchContentParams) ([]Content, error) {
+	rows, err := q.db.QueryContext(ctx, searchContent,
+		arg.SiteID,
+		arg.Heading,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Content
+	for rows.Next() {
+		var i Content
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.UserID,
+			&i.ShortID,
+			&i.SectionID,
+			&i.Kind,
+			&i.Heading,
+			&i.Summary,
+			&i.Body,
+			&i.Draft,
+			&i.Featured,
+			&i.Series,
+			&i.SeriesOrder,
+			&i.PublishedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ContributorID,
+			&i.ContributorHandle,
+			&i.AuthorUsername,
+			&i.HeroTitleDark,
+			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setContentTranslationGroup = `-- name: SetContentTranslationGroup :exec
+UPDATE content SET translation_group = ? WHERE id = ?
+`
+
+type SetContentTranslationGroupParams struct {
+	TranslationGroup string `json:"translation_group"`
+	ID               string `json:"id"`
+}
+
+func (q *Queries) SetContentTranslationGroup(ctx context.Context, arg SetContentTranslationGroupParams) error {
+	_, err := q.db.ExecContext(ctx, setContentTranslationGroup, arg.TranslationGroup, arg.ID)
+	return err
+}
+
+const updateContent = `-- name: UpdateContent :one
+UPDATE content SET
+    section_id = ?,
+    contributor_id = ?,
+    contributor_handle = ?,
+    author_username = ?,
+    kind = ?,
+    heading = ?,
+    summary = ?,
+    body = ?,
+    draft = ?,
+    featured = ?,
+    series = ?,
+    series_order = ?,
+    published_at = ?,
+    hero_title_dark = ?,
+    images_meta = ?,
+    visibility = ?,
+    lang = ?,
+    translation_group = ?,
+    layout_id = ?,
+    link_url = ?,
+    updated_by = ?,
+    updated_at = ?
+WHERE id = ?
+RETURNING id, site_id, user_id, short_id, section_id, kind, heading, summary, body, draft, featured, series, series_order, published_at, created_by, updated_by, created_at, updated_at, contributor_id, contributor_handle, author_username, hero_title_dark, images_meta, visibility, lang, translation_group, layout_id, link_url
+`
+
+type UpdateContentParams struct {
+	SectionID         sql.NullString `json:"section_id"`
+	ContributorID     sql.NullString `json:"contributor_id"`
+	ContributorHandle string         `json:"contributor_handle"`
+	AuthorUsername    string         `json:"author_username"`
+	Kind              sql.NullString `json:"kind"`
+	Heading           string         `json:"heading"`
+	Summary           sql.NullString `json:"summary"`
+	Body              sql.NullString `json:"body"`
+	Draft             sql.NullInt64  `json:"draft"`
+	Featured          sql.NullInt64  `json:"featured"`
+	Series            sql.NullString `json:"series"`
+	SeriesOrder       sql.NullInt64  `json:"series_order"`
+	PublishedAt       sql.NullTime   `json:"published_at"`
+	HeroTitleDark     sql.NullInt64  `json:"hero_title_dark"`
+	ImagesMeta        sql.NullString `json:"images_meta"`
+	Visibility        string         `json:"visibility"`
+	Lang              string         `json:"lang"`
+	TranslationGroup  string         `json:"translation_group"`
+	LayoutID          sql.NullString `json:"layout_id"`
+	LinkURL           string         `json:"link_url"`
+	UpdatedBy         sql.NullString `json:"updated_by"`
+	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	ID                string         `json:"id"`
+}
+
+func (q *Queries) UpdateContent(ctx context.Context, arg UpdateContentParams) (Content, error) {
+	row := q.db.QueryRowContext(ctx, updateContent,
+		arg.SectionID,
+		arg.ContributorID,
+		arg.ContributorHandle,
+		arg.AuthorUsername,
+		arg.Kind,
+		arg.Heading,
+		arg.Summary,
+		arg.Body,
+		arg.Draft,
+		arg.Featured,
+		arg.Series,
+		arg.SeriesOrder,
+		arg.PublishedAt,
+		arg.HeroTitleDark,
+		arg.ImagesMeta,
+		arg.Visibility,
+		arg.Lang,
+		arg.TranslationGroup,
+		arg.LayoutID,
+		arg.LinkURL,
+		arg.UpdatedBy,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i Content
+	err := row.Scan(
+		&i.ID,
+		&i.SiteID,
+		&i.UserID,
+		&i.ShortID,
+		&i.SectionID,
+		&i.Kind,
+		&i.Heading,
+		&i.Summary,
+		&i.Body,
+		&i.Draft,
+		&i.Featured,
+		&i.Series,
+		&i.SeriesOrder,
+		&i.PublishedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ContributorID,
+		&i.ContributorHandle,
+		&i.AuthorUsername,
+		&i.HeroTitleDark,
+		&i.ImagesMeta,
+		&i.Visibility,
+		&i.Lang,
+		&i.TranslationGroup,
+		&i.LayoutID,
+		&i.LinkURL,
+	)
+	return i, err
+}
+
+const updateContentBody = `-- name: UpdateContentBody :exec
+UPDATE content SET
+    body = ?,
+    images_meta = ?,
+    updated_by = ?,
+    updated_at = ?
+WHERE id = ?
+`
+
+type UpdateContentBodyParams struct {
+	Body       sql.NullString `json:"body"`
+	ImagesMeta sql.NullString `json:"images_meta"`
+	UpdatedBy  sql.NullString `json:"updated_by"`
+	UpdatedAt  sql.NullTime   `json:"updated_at"`
+	ID         string         `json:"id"`
+}
+
+func (q *Queries) UpdateContentBody(ctx context.Context, arg UpdateContentBodyParams) error {
+	_, err := q.db.ExecContext(ctx, updateContentBody,
+		arg.Body,
+		arg.ImagesMeta,
+		arg.UpdatedBy,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
 }
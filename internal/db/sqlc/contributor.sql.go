@@ -180,7 +180,7 @@ func (q *Queries) ListContributorsBySiteID(ctx context.Context, siteID string) (
 }
 
 const listContributorsWithProfile = `-- name: ListContributorsWithProfile :many
-SELECT c.id, c.short_id, c.site_id, c.profile_id, c.handle, c.name, c.surname, c.bio, c.social_links, c.role, c.created_by, c.updated_by, c.created_at, c.updated_at, p.photo_path as profile_photo_path
+SELECT c.id, c.short_id, c.site_id, c.profile_id, c.handle, c.name, c.surname, c.bio, c.social_links, c.role, c.created_by, c.updated_by, c.created_at, c.updated_at, p.photo_path as profile_photo_path, p.bio as profile_bio, p.social_links as profile_social_links
 FROM contributor c
 LEFT JOIN profile p ON c.profile_id = p.id
 WHERE c.site_id = ?
@@ -188,21 +188,23 @@ ORDER BY c.name, c.surname
 `
 
 type ListContributorsWithProfileRow struct {
-	ID               string         `json:"id"`
-	ShortID          string         `json:"short_id"`
-	SiteID           string         `json:"site_id"`
-	ProfileID        sql.NullString `json:"profile_id"`
-	Handle           string         `json:"handle"`
-	Name             string         `json:"name"`
-	Surname          string         `json:"surname"`
-	Bio              string         `json:"bio"`
-	SocialLinks      string         `json:"social_links"`
-	Role             string         `json:"role"`
-	CreatedBy        string         `json:"created_by"`
-	UpdatedBy        string         `json:"updated_by"`
-	CreatedAt        time.Time      `json:"created_at"`
-	UpdatedAt        time.Time      `json:"updated_at"`
-	ProfilePhotoPath sql.NullString `json:"profile_photo_path"`
+	ID                 string         `json:"id"`
+	ShortID            string         `json:"short_id"`
+	SiteID             string         `json:"site_id"`
+	ProfileID          sql.NullString `json:"profile_id"`
+	Handle             string         `json:"handle"`
+	Name               string         `json:"name"`
+	Surname            string         `json:"surname"`
+	Bio                string         `json:"bio"`
+	SocialLinks        string         `json:"social_links"`
+	Role               string         `json:"role"`
+	CreatedBy          string         `json:"created_by"`
+	UpdatedBy          string         `json:"updated_by"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	ProfilePhotoPath   sql.NullString `json:"profile_photo_path"`
+	ProfileBio         sql.NullString `json:"profile_bio"`
+	ProfileSocialLinks sql.NullString `json:"profile_social_links"`
 }
 
 func (q *Queries) ListContributorsWithProfile(ctx context.Context, siteID string) ([]ListContributorsWithProfileRow, error) {
@@ -230,6 +232,8 @@ func (q *Queries) ListContributorsWithProfile(ctx context.Context, siteID string
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.ProfilePhotoPath,
+			&i.ProfileBio,
+			&i.ProfileSocialLinks,
 		); err != nil {
 			return nil, err
 		}
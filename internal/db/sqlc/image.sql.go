@@ -10,6 +10,41 @@ import (
 	"database/sql"
 )
 
+const countFilteredImages = `-- name: CountFilteredImages :one
+SELECT COUNT(*) FROM image
+WHERE site_id = ?1
+  AND (?2 = '' OR file_name LIKE ?2 OR title LIKE ?2 OR alt_text LIKE ?2)
+  AND (?3 = ''
+      OR (?3 = 'used' AND (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL)))
+      OR (?3 = 'unused' AND NOT (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL))))
+  AND (?4 = '' OR id IN (SELECT image_id FROM collection_images WHERE collection_id = ?4))
+`
+
+type CountFilteredImagesParams struct {
+	SiteID       string `json:"site_id"`
+	Search       string `json:"search"`
+	Usage        string `json:"usage"`
+	CollectionID string `json:"collection_id"`
+}
+
+func (q *Queries) CountFilteredImages(ctx context.Context, arg CountFilteredImagesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFilteredImages,
+		arg.SiteID,
+		arg.Search,
+		arg.Usage,
+		arg.CollectionID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createContentImage = `-- name: CreateContentImage :exec
 INSERT INTO content_images (id, content_id, image_id, is_header, is_featured, order_num, created_at)
 VALUES (?, ?, ?, ?, ?, ?, ?)
@@ -39,9 +74,9 @@ func (q *Queries) CreateContentImage(ctx context.Context, arg CreateContentImage
 }
 
 const createImage = `-- name: CreateImage :one
-INSERT INTO image (id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at
+INSERT INTO image (id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, stock, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock
 `
 
 type CreateImageParams struct {
@@ -56,6 +91,7 @@ type CreateImageParams struct {
 	AttributionUrl sql.NullString `json:"attribution_url"`
 	Width          sql.NullInt64  `json:"width"`
 	Height         sql.NullInt64  `json:"height"`
+	Stock          int64          `json:"stock"`
 	CreatedBy      sql.NullString `json:"created_by"`
 	UpdatedBy      sql.NullString `json:"updated_by"`
 	CreatedAt      sql.NullTime   `json:"created_at"`
@@ -75,6 +111,7 @@ func (q *Queries) CreateImage(ctx context.Context, arg CreateImageParams) (Image
 		arg.AttributionUrl,
 		arg.Width,
 		arg.Height,
+		arg.Stock,
 		arg.CreatedBy,
 		arg.UpdatedBy,
 		arg.CreatedAt,
@@ -97,6 +134,7 @@ func (q *Queries) CreateImage(ctx context.Context, arg CreateImageParams) (Image
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Stock,
 	)
 	return i, err
 }
@@ -450,8 +488,51 @@ func (q *Queries) GetContentImagesWithDetails(ctx context.Context, contentID str
 	return items, nil
 }
 
+const getContentUsingImage = `-- name: GetContentUsingImage :many
+SELECT c.id, c.short_id, c.heading, ci.is_header
+FROM content_images ci
+JOIN content c ON ci.content_id = c.id
+WHERE ci.image_id = ?
+ORDER BY c.heading
+`
+
+type GetContentUsingImageRow struct {
+	ID       string         `json:"id"`
+	ShortID  sql.NullString `json:"short_id"`
+	Heading  string         `json:"heading"`
+	IsHeader sql.NullInt64  `json:"is_header"`
+}
+
+func (q *Queries) GetContentUsingImage(ctx context.Context, imageID string) ([]GetContentUsingImageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getContentUsingImage, imageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetContentUsingImageRow
+	for rows.Next() {
+		var i GetContentUsingImageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShortID,
+			&i.Heading,
+			&i.IsHeader,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getImage = `-- name: GetImage :one
-SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at FROM image WHERE id = ?
+SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock FROM image WHERE id = ?
 `
 
 func (q *Queries) GetImage(ctx context.Context, id string) (Image, error) {
@@ -473,12 +554,13 @@ func (q *Queries) GetImage(ctx context.Context, id string) (Image, error) {
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Stock,
 	)
 	return i, err
 }
 
 const getImageByPath = `-- name: GetImageByPath :one
-SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at FROM image WHERE site_id = ? AND file_path = ?
+SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock FROM image WHERE site_id = ? AND file_path = ?
 `
 
 type GetImageByPathParams struct {
@@ -505,12 +587,13 @@ func (q *Queries) GetImageByPath(ctx context.Context, arg GetImageByPathParams)
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Stock,
 	)
 	return i, err
 }
 
 const getImageByShortID = `-- name: GetImageByShortID :one
-SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at FROM image WHERE short_id = ?
+SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock FROM image WHERE short_id = ?
 `
 
 func (q *Queries) GetImageByShortID(ctx context.Context, shortID sql.NullString) (Image, error) {
@@ -532,6 +615,7 @@ func (q *Queries) GetImageByShortID(ctx context.Context, shortID sql.NullString)
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Stock,
 	)
 	return i, err
 }
@@ -603,7 +687,7 @@ func (q *Queries) GetImageVariantsByImageID(ctx context.Context, imageID string)
 }
 
 const getImagesBySiteID = `-- name: GetImagesBySiteID :many
-SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at FROM image WHERE site_id = ? ORDER BY created_at DESC
+SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock FROM image WHERE site_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) GetImagesBySiteID(ctx context.Context, siteID string) ([]Image, error) {
@@ -631,6 +715,7 @@ func (q *Queries) GetImagesBySiteID(ctx context.Context, siteID string) ([]Image
 			&i.UpdatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Stock,
 		); err != nil {
 			return nil, err
 		}
@@ -645,6 +730,38 @@ func (q *Queries) GetImagesBySiteID(ctx context.Context, siteID string) ([]Image
 	return items, nil
 }
 
+const getLayoutsUsingImage = `-- name: GetLayoutsUsingImage :many
+SELECT id, name FROM layout WHERE header_image_id = ? ORDER BY name
+`
+
+type GetLayoutsUsingImageRow struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) GetLayoutsUsingImage(ctx context.Context, headerImageID sql.NullString) ([]GetLayoutsUsingImageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getLayoutsUsingImage, headerImageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLayoutsUsingImageRow
+	for rows.Next() {
+		var i GetLayoutsUsingImageRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSectionImageWithDetails = `-- name: GetSectionImageWithDetails :one
 SELECT
     si.id as section_image_id,
@@ -808,6 +925,219 @@ func (q *Queries) GetSectionImagesWithDetails(ctx context.Context, sectionID str
 	return items, nil
 }
 
+const getSectionsUsingImage = `-- name: GetSectionsUsingImage :many
+SELECT s.id, s.name, s.path, si.is_header
+FROM section_images si
+JOIN section s ON si.section_id = s.id
+WHERE si.image_id = ?
+ORDER BY s.path
+`
+
+type GetSectionsUsingImageRow struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Path     sql.NullString `json:"path"`
+	IsHeader sql.NullInt64  `json:"is_header"`
+}
+
+func (q *Queries) GetSectionsUsingImage(ctx context.Context, imageID string) ([]GetSectionsUsingImageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSectionsUsingImage, imageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSectionsUsingImageRow
+	for rows.Next() {
+		var i GetSectionsUsingImageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Path,
+			&i.IsHeader,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagsUsingImage = `-- name: GetTagsUsingImage :many
+SELECT id, name FROM tag WHERE header_image_id = ? ORDER BY name
+`
+
+type GetTagsUsingImageRow struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) GetTagsUsingImage(ctx context.Context, headerImageID sql.NullString) ([]GetTagsUsingImageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagsUsingImage, headerImageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsUsingImageRow
+	for rows.Next() {
+		var i GetTagsUsingImageRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnlinkedImagesBySiteID = `-- name: GetUnlinkedImagesBySiteID :many
+SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock FROM image
+WHERE site_id = ?
+  AND id NOT IN (SELECT image_id FROM content_images)
+  AND id NOT IN (SELECT image_id FROM section_images)
+  AND id NOT IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+  AND id NOT IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL)
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetUnlinkedImagesBySiteID(ctx context.Context, siteID string) ([]Image, error) {
+	rows, err := q.db.QueryContext(ctx, getUnlinkedImagesBySiteID, siteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Image
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.ShortID,
+			&i.FileName,
+			&i.FilePath,
+			&i.AltText,
+			&i.Title,
+			&i.Attribution,
+			&i.AttributionUrl,
+			&i.Width,
+			&i.Height,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFilteredImages = `-- name: ListFilteredImages :many
+SELECT id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock FROM image
+WHERE site_id = ?1
+  AND (?2 = '' OR file_name LIKE ?2 OR title LIKE ?2 OR alt_text LIKE ?2)
+  AND (?3 = ''
+      OR (?3 = 'used' AND (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL)))
+      OR (?3 = 'unused' AND NOT (id IN (SELECT image_id FROM content_images)
+          OR id IN (SELECT image_id FROM section_images)
+          OR id IN (SELECT header_image_id FROM layout WHERE header_image_id IS NOT NULL)
+          OR id IN (SELECT header_image_id FROM tag WHERE header_image_id IS NOT NULL))))
+  AND (?4 = '' OR id IN (SELECT image_id FROM collection_images WHERE collection_id = ?4))
+ORDER BY created_at DESC
+LIMIT ?5 OFFSET ?6
+`
+
+type ListFilteredImagesParams struct {
+	SiteID       string `json:"site_id"`
+	Search       string `json:"search"`
+	Usage        string `json:"usage"`
+	CollectionID string `json:"collection_id"`
+	Limit        int64  `json:"limit"`
+	Offset       int64  `json:"offset"`
+}
+
+func (q *Queries) ListFilteredImages(ctx context.Context, arg ListFilteredImagesParams) ([]Image, error) {
+	rows, err := q.db.QueryContext(ctx, listFilteredImages,
+		arg.SiteID,
+		arg.Search,
+		arg.Usage,
+		arg.CollectionID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Image
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.ShortID,
+			&i.FileName,
+			&i.FilePath,
+			&i.AltText,
+			&i.Title,
+			&i.Attribution,
+			&i.AttributionUrl,
+			&i.Width,
+			&i.Height,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Stock,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateContentImageImageID = `-- name: UpdateContentImageImageID :exec
+UPDATE content_images SET image_id = ? WHERE id = ?
+`
+
+type UpdateContentImageImageIDParams struct {
+	ImageID string `json:"image_id"`
+	ID      string `json:"id"`
+}
+
+func (q *Queries) UpdateContentImageImageID(ctx context.Context, arg UpdateContentImageImageIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateContentImageImageID, arg.ImageID, arg.ID)
+	return err
+}
+
 const updateImage = `-- name: UpdateImage :one
 UPDATE image SET
     file_name = ?,
@@ -818,10 +1148,11 @@ UPDATE image SET
     attribution_url = ?,
     width = ?,
     height = ?,
+    stock = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
-RETURNING id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at
+RETURNING id, site_id, short_id, file_name, file_path, alt_text, title, attribution, attribution_url, width, height, created_by, updated_by, created_at, updated_at, stock
 `
 
 type UpdateImageParams struct {
@@ -833,6 +1164,7 @@ type UpdateImageParams struct {
 	AttributionUrl sql.NullString `json:"attribution_url"`
 	Width          sql.NullInt64  `json:"width"`
 	Height         sql.NullInt64  `json:"height"`
+	Stock          int64          `json:"stock"`
 	UpdatedBy      sql.NullString `json:"updated_by"`
 	UpdatedAt      sql.NullTime   `json:"updated_at"`
 	ID             string         `json:"id"`
@@ -848,6 +1180,7 @@ func (q *Queries) UpdateImage(ctx context.Context, arg UpdateImageParams) (Image
 		arg.AttributionUrl,
 		arg.Width,
 		arg.Height,
+		arg.Stock,
 		arg.UpdatedBy,
 		arg.UpdatedAt,
 		arg.ID,
@@ -869,6 +1202,7 @@ func (q *Queries) UpdateImage(ctx context.Context, arg UpdateImageParams) (Image
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Stock,
 	)
 	return i, err
 }
@@ -11,9 +11,9 @@ import (
 )
 
 const createMeta = `-- name: CreateMeta :one
-INSERT INTO meta (id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at
+INSERT INTO meta (id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, keep_links, show_updated, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at, keep_links, show_updated
 `
 
 type CreateMetaParams struct {
@@ -31,6 +31,8 @@ type CreateMetaParams struct {
 	TableOfContents sql.NullInt64  `json:"table_of_contents"`
 	Share           sql.NullInt64  `json:"share"`
 	Comments        sql.NullInt64  `json:"comments"`
+	KeepLinks       sql.NullInt64  `json:"keep_links"`
+	ShowUpdated     sql.NullInt64  `json:"show_updated"`
 	CreatedBy       sql.NullString `json:"created_by"`
 	UpdatedBy       sql.NullString `json:"updated_by"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
@@ -53,6 +55,8 @@ func (q *Queries) CreateMeta(ctx context.Context, arg CreateMetaParams) (Meta, e
 		arg.TableOfContents,
 		arg.Share,
 		arg.Comments,
+		arg.KeepLinks,
+		arg.ShowUpdated,
 		arg.CreatedBy,
 		arg.UpdatedBy,
 		arg.CreatedAt,
@@ -78,6 +82,8 @@ func (q *Queries) CreateMeta(ctx context.Context, arg CreateMetaParams) (Meta, e
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.KeepLinks,
+		&i.ShowUpdated,
 	)
 	return i, err
 }
@@ -101,7 +107,7 @@ func (q *Queries) DeleteMetaByContentID(ctx context.Context, contentID string) e
 }
 
 const getMeta = `-- name: GetMeta :one
-SELECT id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at FROM meta WHERE id = ?
+SELECT id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at, keep_links, show_updated FROM meta WHERE id = ?
 `
 
 func (q *Queries) GetMeta(ctx context.Context, id string) (Meta, error) {
@@ -126,12 +132,14 @@ func (q *Queries) GetMeta(ctx context.Context, id string) (Meta, error) {
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.KeepLinks,
+		&i.ShowUpdated,
 	)
 	return i, err
 }
 
 const getMetaByContentID = `-- name: GetMetaByContentID :one
-SELECT id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at FROM meta WHERE content_id = ?
+SELECT id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at, keep_links, show_updated FROM meta WHERE content_id = ?
 `
 
 func (q *Queries) GetMetaByContentID(ctx context.Context, contentID string) (Meta, error) {
@@ -156,6 +164,8 @@ func (q *Queries) GetMetaByContentID(ctx context.Context, contentID string) (Met
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.KeepLinks,
+		&i.ShowUpdated,
 	)
 	return i, err
 }
@@ -172,10 +182,12 @@ UPDATE meta SET
     table_of_contents = ?,
     share = ?,
     comments = ?,
+    keep_links = ?,
+    show_updated = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
-RETURNING id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at
+RETURNING id, site_id, short_id, content_id, summary, excerpt, description, keywords, robots, canonical_url, sitemap, table_of_contents, share, comments, created_by, updated_by, created_at, updated_at, keep_links, show_updated
 `
 
 type UpdateMetaParams struct {
@@ -189,6 +201,8 @@ type UpdateMetaParams struct {
 	TableOfContents sql.NullInt64  `json:"table_of_contents"`
 	Share           sql.NullInt64  `json:"share"`
 	Comments        sql.NullInt64  `json:"comments"`
+	KeepLinks       sql.NullInt64  `json:"keep_links"`
+	ShowUpdated     sql.NullInt64  `json:"show_updated"`
 	UpdatedBy       sql.NullString `json:"updated_by"`
 	UpdatedAt       sql.NullTime   `json:"updated_at"`
 	ID              string         `json:"id"`
@@ -206,6 +220,8 @@ func (q *Queries) UpdateMeta(ctx context.Context, arg UpdateMetaParams) (Meta, e
 		arg.TableOfContents,
 		arg.Share,
 		arg.Comments,
+		arg.KeepLinks,
+		arg.ShowUpdated,
 		arg.UpdatedBy,
 		arg.UpdatedAt,
 		arg.ID,
@@ -230,6 +246,22 @@ func (q *Queries) UpdateMeta(ctx context.Context, arg UpdateMetaParams) (Meta, e
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.KeepLinks,
+		&i.ShowUpdated,
 	)
 	return i, err
 }
+
+const updateMetaSiteID = `-- name: UpdateMetaSiteID :exec
+UPDATE meta SET site_id = ? WHERE content_id = ?
+`
+
+type UpdateMetaSiteIDParams struct {
+	SiteID    string `json:"site_id"`
+	ContentID string `json:"content_id"`
+}
+
+func (q *Queries) UpdateMetaSiteID(ctx context.Context, arg UpdateMetaSiteIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateMetaSiteID, arg.SiteID, arg.ContentID)
+	return err
+}
@@ -19,6 +19,25 @@ type ApiToken struct {
 	CreatedAt  time.Time    `json:"created_at"`
 }
 
+type Collection struct {
+	ID        string         `json:"id"`
+	SiteID    string         `json:"site_id"`
+	ShortID   sql.NullString `json:"short_id"`
+	Name      string         `json:"name"`
+	Slug      string         `json:"slug"`
+	CreatedBy sql.NullString `json:"created_by"`
+	UpdatedBy sql.NullString `json:"updated_by"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+	UpdatedAt sql.NullTime   `json:"updated_at"`
+}
+
+type CollectionImage struct {
+	ID           string       `json:"id"`
+	CollectionID string       `json:"collection_id"`
+	ImageID      string       `json:"image_id"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+}
+
 type Content struct {
 	ID                string         `json:"id"`
 	SiteID            string         `json:"site_id"`
@@ -43,6 +62,11 @@ type Content struct {
 	AuthorUsername    string         `json:"author_username"`
 	HeroTitleDark     sql.NullInt64  `json:"hero_title_dark"`
 	ImagesMeta        sql.NullString `json:"images_meta"`
+	Visibility        string         `json:"visibility"`
+	Lang              string         `json:"lang"`
+	TranslationGroup  string         `json:"translation_group"`
+	LayoutID          sql.NullString `json:"layout_id"`
+	LinkURL           string         `json:"link_url"`
 }
 
 type ContentImage struct {
@@ -55,6 +79,59 @@ type ContentImage struct {
 	CreatedAt  sql.NullTime  `json:"created_at"`
 }
 
+type ContentEditLock struct {
+	ContentID  string    `json:"content_id"`
+	UserID     string    `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type ContentField struct {
+	ID        string    `json:"id"`
+	ContentID string    `json:"content_id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ContentReview struct {
+	ContentID   string       `json:"content_id"`
+	Status      string       `json:"status"`
+	ReviewerID  string       `json:"reviewer_id"`
+	SubmittedBy string       `json:"submitted_by"`
+	Comment     string       `json:"comment"`
+	SubmittedAt time.Time    `json:"submitted_at"`
+	ReviewedAt  sql.NullTime `json:"reviewed_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type ContentKind struct {
+	ID        string         `json:"id"`
+	SiteID    string         `json:"site_id"`
+	Name      string         `json:"name"`
+	Label     string         `json:"label"`
+	Permalink string         `json:"permalink"`
+	Listed    int64          `json:"listed"`
+	Feed      int64          `json:"feed"`
+	Redirect  int64          `json:"redirect"`
+	LayoutID  sql.NullString `json:"layout_id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type ContentRevision struct {
+	ID        string    `json:"id"`
+	ContentID string    `json:"content_id"`
+	Heading   string    `json:"heading"`
+	Summary   string    `json:"summary"`
+	Body      string    `json:"body"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type ContentTag struct {
 	ID        string       `json:"id"`
 	ContentID string       `json:"content_id"`
@@ -108,6 +185,7 @@ type Image struct {
 	UpdatedBy      sql.NullString `json:"updated_by"`
 	CreatedAt      sql.NullTime   `json:"created_at"`
 	UpdatedAt      sql.NullTime   `json:"updated_at"`
+	Stock          int64          `json:"stock"`
 }
 
 type ImageVariant struct {
@@ -157,6 +235,13 @@ type Layout struct {
 	UpdatedAt         sql.NullTime   `json:"updated_at"`
 }
 
+type LoginFailure struct {
+	UserID        string       `json:"user_id"`
+	Failures      int64        `json:"failures"`
+	FirstFailedAt time.Time    `json:"first_failed_at"`
+	LockedUntil   sql.NullTime `json:"locked_until"`
+}
+
 type Meta struct {
 	ID              string         `json:"id"`
 	SiteID          string         `json:"site_id"`
@@ -176,6 +261,8 @@ type Meta struct {
 	UpdatedBy       sql.NullString `json:"updated_by"`
 	CreatedAt       sql.NullTime   `json:"created_at"`
 	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	KeepLinks       sql.NullInt64  `json:"keep_links"`
+	ShowUpdated     sql.NullInt64  `json:"show_updated"`
 }
 
 type Profile struct {
@@ -194,6 +281,21 @@ type Profile struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type PublishJob struct {
+	ID         string       `json:"id"`
+	SiteID     string       `json:"site_id"`
+	Status     string       `json:"status"`
+	Progress   string       `json:"progress"`
+	CommitHash string       `json:"commit_hash"`
+	CommitUrl  string       `json:"commit_url"`
+	NoChanges  int64        `json:"no_changes"`
+	Error      string       `json:"error"`
+	CreatedBy  string       `json:"created_by"`
+	CreatedAt  time.Time    `json:"created_at"`
+	StartedAt  sql.NullTime `json:"started_at"`
+	FinishedAt sql.NullTime `json:"finished_at"`
+}
+
 type Section struct {
 	ID            string         `json:"id"`
 	SiteID        string         `json:"site_id"`
@@ -245,33 +347,38 @@ type Setting struct {
 	UiControl   sql.NullString `json:"ui_control"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
 	UpdatedAt   sql.NullTime   `json:"updated_at"`
+	Secret      sql.NullInt64  `json:"secret"`
 }
 
 type Site struct {
-	ID                string         `json:"id"`
-	ShortID           string         `json:"short_id"`
-	Name              string         `json:"name"`
-	Slug              string         `json:"slug"`
-	Active            int64          `json:"active"`
-	DefaultLayoutID   sql.NullString `json:"default_layout_id"`
-	DefaultLayoutName sql.NullString `json:"default_layout_name"`
-	CreatedBy         string         `json:"created_by"`
-	UpdatedBy         string         `json:"updated_by"`
-	CreatedAt         time.Time      `json:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at"`
-	LastPublishedAt   sql.NullTime   `json:"last_published_at"`
+	ID                  string         `json:"id"`
+	ShortID             string         `json:"short_id"`
+	Name                string         `json:"name"`
+	Slug                string         `json:"slug"`
+	Active              int64          `json:"active"`
+	DefaultLayoutID     sql.NullString `json:"default_layout_id"`
+	DefaultLayoutName   sql.NullString `json:"default_layout_name"`
+	CreatedBy           string         `json:"created_by"`
+	UpdatedBy           string         `json:"updated_by"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	LastPublishedAt     sql.NullTime   `json:"last_published_at"`
+	LastGeneratedAt     sql.NullTime   `json:"last_generated_at"`
+	LastPublishedCommit sql.NullString `json:"last_published_commit"`
 }
 
 type Tag struct {
-	ID        string         `json:"id"`
-	SiteID    string         `json:"site_id"`
-	ShortID   sql.NullString `json:"short_id"`
-	Name      string         `json:"name"`
-	Slug      string         `json:"slug"`
-	CreatedBy sql.NullString `json:"created_by"`
-	UpdatedBy sql.NullString `json:"updated_by"`
-	CreatedAt sql.NullTime   `json:"created_at"`
-	UpdatedAt sql.NullTime   `json:"updated_at"`
+	ID            string         `json:"id"`
+	SiteID        string         `json:"site_id"`
+	ShortID       sql.NullString `json:"short_id"`
+	Name          string         `json:"name"`
+	Slug          string         `json:"slug"`
+	CreatedBy     sql.NullString `json:"created_by"`
+	UpdatedBy     sql.NullString `json:"updated_by"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
+	Description   string         `json:"description"`
+	HeaderImageID sql.NullString `json:"header_image_id"`
 }
 
 type User struct {
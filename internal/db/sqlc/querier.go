@@ -10,13 +10,20 @@ import (
 )
 
 type Querier interface {
+	AcquireContentEditLock(ctx context.Context, arg AcquireContentEditLockParams) (int64, error)
+	AddImageToCollection(ctx context.Context, arg AddImageToCollectionParams) error
 	AddTagToContent(ctx context.Context, arg AddTagToContentParams) error
 	CountContent(ctx context.Context, siteID string) (int64, error)
+	CountContentByStatus(ctx context.Context, arg CountContentByStatusParams) (CountContentByStatusRow, error)
+	CountFilteredContent(ctx context.Context, arg CountFilteredContentParams) (int64, error)
+	CountFilteredImages(ctx context.Context, arg CountFilteredImagesParams) (int64, error)
 	CountSearchContent(ctx context.Context, arg CountSearchContentParams) (int64, error)
 	CountUnreadFormSubmissions(ctx context.Context, siteID string) (int64, error)
 	CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error)
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error)
 	CreateContent(ctx context.Context, arg CreateContentParams) (Content, error)
 	CreateContentImage(ctx context.Context, arg CreateContentImageParams) error
+	CreateContentRevision(ctx context.Context, arg CreateContentRevisionParams) (ContentRevision, error)
 	CreateContributor(ctx context.Context, arg CreateContributorParams) (Contributor, error)
 	CreateFormSubmission(ctx context.Context, arg CreateFormSubmissionParams) (FormSubmission, error)
 	CreateImage(ctx context.Context, arg CreateImageParams) (Image, error)
@@ -25,6 +32,7 @@ type Querier interface {
 	CreateLayout(ctx context.Context, arg CreateLayoutParams) (Layout, error)
 	CreateMeta(ctx context.Context, arg CreateMetaParams) (Meta, error)
 	CreateProfile(ctx context.Context, arg CreateProfileParams) (Profile, error)
+	CreatePublishJob(ctx context.Context, arg CreatePublishJobParams) (PublishJob, error)
 	CreateSection(ctx context.Context, arg CreateSectionParams) (Section, error)
 	CreateSectionImage(ctx context.Context, arg CreateSectionImageParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
@@ -33,9 +41,14 @@ type Querier interface {
 	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	DeleteAPIToken(ctx context.Context, id string) error
+	DeleteCollection(ctx context.Context, id string) error
 	DeleteContent(ctx context.Context, id string) error
+	DeleteContentEditLock(ctx context.Context, contentID string) error
+	DeleteContentField(ctx context.Context, arg DeleteContentFieldParams) error
+	DeleteContentFieldsByContentID(ctx context.Context, contentID string) error
 	DeleteContentImage(ctx context.Context, id string) error
 	DeleteContentImageByContentAndImage(ctx context.Context, arg DeleteContentImageByContentAndImageParams) error
+	DeleteContentKind(ctx context.Context, arg DeleteContentKindParams) error
 	DeleteContributor(ctx context.Context, id string) error
 	DeleteExpiredSessions(ctx context.Context) error
 	DeleteFormSubmission(ctx context.Context, id string) error
@@ -44,6 +57,7 @@ type Querier interface {
 	DeleteImport(ctx context.Context, id string) error
 	DeleteImportByContentID(ctx context.Context, contentID sql.NullString) error
 	DeleteLayout(ctx context.Context, id string) error
+	DeleteLoginFailure(ctx context.Context, userID string) error
 	DeleteMeta(ctx context.Context, id string) error
 	DeleteMetaByContentID(ctx context.Context, contentID string) error
 	DeleteProfile(ctx context.Context, id string) error
@@ -55,57 +69,88 @@ type Querier interface {
 	DeleteTag(ctx context.Context, id string) error
 	DeleteUser(ctx context.Context, id string) error
 	DeleteUserSessions(ctx context.Context, userID string) error
+	FailUnfinishedPublishJobs(ctx context.Context, arg FailUnfinishedPublishJobsParams) (int64, error)
 	GetAPITokenByHash(ctx context.Context, tokenHash string) (ApiToken, error)
+	GetActivePublishJob(ctx context.Context, siteID string) (PublishJob, error)
 	GetAllContentImagesBySiteID(ctx context.Context, siteID string) ([]GetAllContentImagesBySiteIDRow, error)
 	GetAllContentWithMeta(ctx context.Context, siteID string) ([]GetAllContentWithMetaRow, error)
+	GetCollection(ctx context.Context, id string) (Collection, error)
+	GetCollectionsByImageID(ctx context.Context, imageID string) ([]Collection, error)
+	GetCollectionsBySiteID(ctx context.Context, siteID string) ([]GetCollectionsBySiteIDRow, error)
 	GetContent(ctx context.Context, id string) (Content, error)
 	GetContentBySectionID(ctx context.Context, sectionID sql.NullString) ([]Content, error)
 	GetContentBySiteID(ctx context.Context, siteID string) ([]Content, error)
+	GetContentBySiteIDAndKind(ctx context.Context, arg GetContentBySiteIDAndKindParams) ([]Content, error)
+	GetContentByTranslationGroup(ctx context.Context, arg GetContentByTranslationGroupParams) ([]Content, error)
+	GetContentByContributor(ctx context.Context, contributorID sql.NullString) ([]Content, error)
+	GetContentEditLock(ctx context.Context, contentID string) (ContentEditLock, error)
+	GetContentByDateRange(ctx context.Context, arg GetContentByDateRangeParams) ([]Content, error)
+	GetContentEditedSince(ctx context.Context, arg GetContentEditedSinceParams) ([]Content, error)
 	GetContentForTag(ctx context.Context, tagID string) ([]Content, error)
+	GetContentFieldsByContentID(ctx context.Context, contentID string) ([]ContentField, error)
+	GetContentFieldsBySiteID(ctx context.Context, siteID string) ([]ContentField, error)
 	GetContentImageWithDetails(ctx context.Context, id string) (GetContentImageWithDetailsRow, error)
 	GetContentImagesByContentID(ctx context.Context, contentID string) ([]ContentImage, error)
 	GetContentImagesWithDetails(ctx context.Context, contentID string) ([]GetContentImagesWithDetailsRow, error)
+	GetContentKindsBySiteID(ctx context.Context, siteID string) ([]ContentKind, error)
+	GetContentReview(ctx context.Context, contentID string) (GetContentReviewRow, error)
+	GetContentRevision(ctx context.Context, id string) (ContentRevision, error)
+	GetContentUsingImage(ctx context.Context, imageID string) ([]GetContentUsingImageRow, error)
 	GetContentWithMeta(ctx context.Context, id string) (GetContentWithMetaRow, error)
 	GetContentWithPagination(ctx context.Context, arg GetContentWithPaginationParams) ([]Content, error)
 	GetContributor(ctx context.Context, id string) (Contributor, error)
 	GetContributorByHandle(ctx context.Context, arg GetContributorByHandleParams) (Contributor, error)
+	GetFeaturedContent(ctx context.Context, arg GetFeaturedContentParams) ([]Content, error)
 	GetFormSubmission(ctx context.Context, id string) (FormSubmission, error)
 	GetImage(ctx context.Context, id string) (Image, error)
 	GetImageByPath(ctx context.Context, arg GetImageByPathParams) (Image, error)
 	GetImageByShortID(ctx context.Context, shortID sql.NullString) (Image, error)
 	GetImageVariant(ctx context.Context, id string) (ImageVariant, error)
 	GetImageVariantsByImageID(ctx context.Context, imageID string) ([]ImageVariant, error)
+	GetImagesByCollectionID(ctx context.Context, collectionID string) ([]Image, error)
 	GetImagesBySiteID(ctx context.Context, siteID string) ([]Image, error)
 	GetImport(ctx context.Context, id string) (Import, error)
 	GetImportByContentID(ctx context.Context, contentID sql.NullString) (Import, error)
 	GetImportByFilePath(ctx context.Context, filePath string) (Import, error)
+	GetLatestContentRevision(ctx context.Context, contentID string) (ContentRevision, error)
 	GetLayout(ctx context.Context, id string) (Layout, error)
 	GetLayoutByName(ctx context.Context, arg GetLayoutByNameParams) (Layout, error)
 	GetLayoutsBySiteID(ctx context.Context, siteID string) ([]Layout, error)
+	GetLayoutsUsingImage(ctx context.Context, headerImageID sql.NullString) ([]GetLayoutsUsingImageRow, error)
+	GetLoginFailure(ctx context.Context, userID string) (LoginFailure, error)
 	GetMeta(ctx context.Context, id string) (Meta, error)
 	GetMetaByContentID(ctx context.Context, contentID string) (Meta, error)
+	GetOpenContentReviewsBySiteID(ctx context.Context, siteID string) ([]GetOpenContentReviewsBySiteIDRow, error)
 	GetProfile(ctx context.Context, id string) (Profile, error)
 	GetProfileBySlug(ctx context.Context, arg GetProfileBySlugParams) (Profile, error)
+	GetPublishJob(ctx context.Context, id string) (PublishJob, error)
 	GetPublishedContentBySiteID(ctx context.Context, siteID string) ([]Content, error)
+	GetRecentlyUpdatedContent(ctx context.Context, arg GetRecentlyUpdatedContentParams) ([]Content, error)
 	GetSection(ctx context.Context, id string) (Section, error)
 	GetSectionByPath(ctx context.Context, arg GetSectionByPathParams) (Section, error)
 	GetSectionImageWithDetails(ctx context.Context, id string) (GetSectionImageWithDetailsRow, error)
 	GetSectionImagesBySectionID(ctx context.Context, sectionID string) ([]SectionImage, error)
 	GetSectionImagesWithDetails(ctx context.Context, sectionID string) ([]GetSectionImagesWithDetailsRow, error)
 	GetSectionsBySiteID(ctx context.Context, siteID string) ([]Section, error)
+	GetSectionsUsingImage(ctx context.Context, imageID string) ([]GetSectionsUsingImageRow, error)
 	GetSectionsWithHeaderImage(ctx context.Context, siteID string) ([]GetSectionsWithHeaderImageRow, error)
 	GetSession(ctx context.Context, id string) (Session, error)
+	GetSecretSettings(ctx context.Context) ([]Setting, error)
 	GetSetting(ctx context.Context, id string) (Setting, error)
 	GetSettingByName(ctx context.Context, arg GetSettingByNameParams) (Setting, error)
 	GetSettingByRefKey(ctx context.Context, arg GetSettingByRefKeyParams) (Setting, error)
 	GetSettingsBySiteID(ctx context.Context, siteID string) ([]Setting, error)
 	GetSite(ctx context.Context, id string) (Site, error)
 	GetSiteBySlug(ctx context.Context, slug string) (Site, error)
+	GetSiteCounts(ctx context.Context, siteID string) (GetSiteCountsRow, error)
+	GetStaleDrafts(ctx context.Context, arg GetStaleDraftsParams) ([]Content, error)
 	GetTag(ctx context.Context, id string) (Tag, error)
 	GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error)
 	GetTagBySlug(ctx context.Context, arg GetTagBySlugParams) (Tag, error)
 	GetTagsBySiteID(ctx context.Context, siteID string) ([]Tag, error)
-	GetTagsForContent(ctx context.Context, contentID string) ([]Tag, error)
+	GetTagsForContent(ctx context.Context, contentID string) ([]GetTagsForContentRow, error)
+	GetTagsUsingImage(ctx context.Context, headerImageID sql.NullString) ([]GetTagsUsingImageRow, error)
+	GetUnlinkedImagesBySiteID(ctx context.Context, siteID string) ([]Image, error)
 	GetUser(ctx context.Context, id string) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByName(ctx context.Context, name string) (User, error)
@@ -113,21 +158,38 @@ type Querier interface {
 	GetValidSession(ctx context.Context, id string) (Session, error)
 	ListAPITokensByUser(ctx context.Context, userID string) ([]ApiToken, error)
 	ListAllSites(ctx context.Context) ([]Site, error)
+	ListContentRevisions(ctx context.Context, contentID string) ([]ContentRevision, error)
 	ListContributorsBySiteID(ctx context.Context, siteID string) ([]Contributor, error)
 	ListContributorsWithProfile(ctx context.Context, siteID string) ([]ListContributorsWithProfileRow, error)
+	ListFilteredContent(ctx context.Context, arg ListFilteredContentParams) ([]Content, error)
+	ListFilteredImages(ctx context.Context, arg ListFilteredImagesParams) ([]Image, error)
 	ListFormSubmissionsBySite(ctx context.Context, siteID string) ([]FormSubmission, error)
 	ListImportsBySiteID(ctx context.Context, siteID string) ([]ListImportsBySiteIDRow, error)
 	ListProfiles(ctx context.Context, siteID string) ([]Profile, error)
+	ListPublishJobsBySiteID(ctx context.Context, arg ListPublishJobsBySiteIDParams) ([]PublishJob, error)
 	ListSites(ctx context.Context) ([]Site, error)
 	ListUsers(ctx context.Context) ([]User, error)
 	MarkFormSubmissionRead(ctx context.Context, arg MarkFormSubmissionReadParams) error
+	MarkSiteGenerated(ctx context.Context, arg MarkSiteGeneratedParams) error
+	MarkSitePublished(ctx context.Context, arg MarkSitePublishedParams) error
+	MoveContent(ctx context.Context, arg MoveContentParams) error
+	PruneContentRevisions(ctx context.Context, arg PruneContentRevisionsParams) error
+	PrunePublishJobs(ctx context.Context, arg PrunePublishJobsParams) error
+	PurgeExpiredAPITokens(ctx context.Context, arg PurgeExpiredAPITokensParams) (int64, error)
+	PurgeExpiredSessions(ctx context.Context, arg PurgeExpiredSessionsParams) (int64, error)
+	ReleaseContentEditLock(ctx context.Context, arg ReleaseContentEditLockParams) error
 	RemoveAllTagsFromContent(ctx context.Context, contentID string) error
+	RemoveImageFromCollection(ctx context.Context, arg RemoveImageFromCollectionParams) error
 	RemoveTagFromContent(ctx context.Context, arg RemoveTagFromContentParams) error
 	SearchContent(ctx context.Context, arg SearchContentParams) ([]Content, error)
+	SetContentTranslationGroup(ctx context.Context, arg SetContentTranslationGroupParams) error
 	SetContributorProfile(ctx context.Context, arg SetContributorProfileParams) error
 	SetUserProfile(ctx context.Context, arg SetUserProfileParams) error
 	UpdateAPITokenLastUsed(ctx context.Context, arg UpdateAPITokenLastUsedParams) error
+	UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (Collection, error)
 	UpdateContent(ctx context.Context, arg UpdateContentParams) (Content, error)
+	UpdateContentBody(ctx context.Context, arg UpdateContentBodyParams) error
+	UpdateContentImageImageID(ctx context.Context, arg UpdateContentImageImageIDParams) error
 	UpdateContributor(ctx context.Context, arg UpdateContributorParams) (Contributor, error)
 	UpdateImage(ctx context.Context, arg UpdateImageParams) (Image, error)
 	UpdateImageVariant(ctx context.Context, arg UpdateImageVariantParams) (ImageVariant, error)
@@ -135,12 +197,20 @@ type Querier interface {
 	UpdateImportStatus(ctx context.Context, arg UpdateImportStatusParams) (Import, error)
 	UpdateLayout(ctx context.Context, arg UpdateLayoutParams) (Layout, error)
 	UpdateMeta(ctx context.Context, arg UpdateMetaParams) (Meta, error)
+	UpdateMetaSiteID(ctx context.Context, arg UpdateMetaSiteIDParams) error
 	UpdateProfile(ctx context.Context, arg UpdateProfileParams) (Profile, error)
+	UpdatePublishJob(ctx context.Context, arg UpdatePublishJobParams) error
 	UpdateSection(ctx context.Context, arg UpdateSectionParams) (Section, error)
 	UpdateSetting(ctx context.Context, arg UpdateSettingParams) (Setting, error)
+	UpdateSettingPosition(ctx context.Context, arg UpdateSettingPositionParams) error
+	UpdateSettingValue(ctx context.Context, arg UpdateSettingValueParams) error
 	UpdateSite(ctx context.Context, arg UpdateSiteParams) (Site, error)
 	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpsertContentField(ctx context.Context, arg UpsertContentFieldParams) error
+	UpsertContentReview(ctx context.Context, arg UpsertContentReviewParams) error
+	UpsertContentKind(ctx context.Context, arg UpsertContentKindParams) error
+	UpsertLoginFailure(ctx context.Context, arg UpsertLoginFailureParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -11,9 +11,9 @@ import (
 )
 
 const createSetting = `-- name: CreateSetting :one
-INSERT INTO setting (id, site_id, short_id, name, description, value, ref_key, category, position, system, type, constraints, ui_control, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at
+INSERT INTO setting (id, site_id, short_id, name, description, value, ref_key, category, position, system, type, constraints, ui_control, secret, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at, secret
 `
 
 type CreateSettingParams struct {
@@ -30,6 +30,7 @@ type CreateSettingParams struct {
 	Type        sql.NullString `json:"type"`
 	Constraints sql.NullString `json:"constraints"`
 	UiControl   sql.NullString `json:"ui_control"`
+	Secret      sql.NullInt64  `json:"secret"`
 	CreatedBy   sql.NullString `json:"created_by"`
 	UpdatedBy   sql.NullString `json:"updated_by"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
@@ -51,6 +52,7 @@ func (q *Queries) CreateSetting(ctx context.Context, arg CreateSettingParams) (S
 		arg.Type,
 		arg.Constraints,
 		arg.UiControl,
+		arg.Secret,
 		arg.CreatedBy,
 		arg.UpdatedBy,
 		arg.CreatedAt,
@@ -75,6 +77,7 @@ func (q *Queries) CreateSetting(ctx context.Context, arg CreateSettingParams) (S
 		&i.UiControl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Secret,
 	)
 	return i, err
 }
@@ -88,8 +91,54 @@ func (q *Queries) DeleteSetting(ctx context.Context, id string) error {
 	return err
 }
 
+const getSecretSettings = `-- name: GetSecretSettings :many
+SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at, secret FROM setting WHERE secret = 1 AND value != ''
+`
+
+func (q *Queries) GetSecretSettings(ctx context.Context) ([]Setting, error) {
+	rows, err := q.db.QueryContext(ctx, getSecretSettings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Setting
+	for rows.Next() {
+		var i Setting
+		if err := rows.Scan(
+			&i.ID,
+			&i.SiteID,
+			&i.ShortID,
+			&i.Name,
+			&i.Description,
+			&i.Value,
+			&i.RefKey,
+			&i.System,
+			&i.Category,
+			&i.Position,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.Type,
+			&i.Constraints,
+			&i.UiControl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSetting = `-- name: GetSetting :one
-SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at FROM setting WHERE id = ?
+SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at, secret FROM setting WHERE id = ?
 `
 
 func (q *Queries) GetSetting(ctx context.Context, id string) (Setting, error) {
@@ -113,12 +162,13 @@ func (q *Queries) GetSetting(ctx context.Context, id string) (Setting, error) {
 		&i.UiControl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Secret,
 	)
 	return i, err
 }
 
 const getSettingByName = `-- name: GetSettingByName :one
-SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at FROM setting WHERE site_id = ? AND name = ?
+SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at, secret FROM setting WHERE site_id = ? AND name = ?
 `
 
 type GetSettingByNameParams struct {
@@ -147,12 +197,13 @@ func (q *Queries) GetSettingByName(ctx context.Context, arg GetSettingByNamePara
 		&i.UiControl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Secret,
 	)
 	return i, err
 }
 
 const getSettingByRefKey = `-- name: GetSettingByRefKey :one
-SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at FROM setting WHERE site_id = ? AND ref_key = ?
+SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at, secret FROM setting WHERE site_id = ? AND ref_key = ?
 `
 
 type GetSettingByRefKeyParams struct {
@@ -181,12 +232,13 @@ func (q *Queries) GetSettingByRefKey(ctx context.Context, arg GetSettingByRefKey
 		&i.UiControl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Secret,
 	)
 	return i, err
 }
 
 const getSettingsBySiteID = `-- name: GetSettingsBySiteID :many
-SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at FROM setting WHERE site_id = ? ORDER BY category, position, name
+SELECT id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at, secret FROM setting WHERE site_id = ? ORDER BY category, position, name
 `
 
 func (q *Queries) GetSettingsBySiteID(ctx context.Context, siteID string) ([]Setting, error) {
@@ -216,6 +268,7 @@ func (q *Queries) GetSettingsBySiteID(ctx context.Context, siteID string) ([]Set
 			&i.UiControl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Secret,
 		); err != nil {
 			return nil, err
 		}
@@ -242,10 +295,11 @@ UPDATE setting SET
     type = ?,
     constraints = ?,
     ui_control = ?,
+    secret = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
-RETURNING id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at
+RETURNING id, site_id, short_id, name, description, value, ref_key, system, category, position, created_by, updated_by, type, constraints, ui_control, created_at, updated_at, secret
 `
 
 type UpdateSettingParams struct {
@@ -259,6 +313,7 @@ type UpdateSettingParams struct {
 	Type        sql.NullString `json:"type"`
 	Constraints sql.NullString `json:"constraints"`
 	UiControl   sql.NullString `json:"ui_control"`
+	Secret      sql.NullInt64  `json:"secret"`
 	UpdatedBy   sql.NullString `json:"updated_by"`
 	UpdatedAt   sql.NullTime   `json:"updated_at"`
 	ID          string         `json:"id"`
@@ -276,6 +331,7 @@ func (q *Queries) UpdateSetting(ctx context.Context, arg UpdateSettingParams) (S
 		arg.Type,
 		arg.Constraints,
 		arg.UiControl,
+		arg.Secret,
 		arg.UpdatedBy,
 		arg.UpdatedAt,
 		arg.ID,
@@ -299,6 +355,42 @@ func (q *Queries) UpdateSetting(ctx context.Context, arg UpdateSettingParams) (S
 		&i.UiControl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Secret,
 	)
 	return i, err
 }
+
+const updateSettingPosition = `-- name: UpdateSettingPosition :exec
+UPDATE setting SET position = ?, updated_at = ? WHERE id = ? AND site_id = ?
+`
+
+type UpdateSettingPositionParams struct {
+	Position  sql.NullInt64 `json:"position"`
+	UpdatedAt sql.NullTime  `json:"updated_at"`
+	ID        string        `json:"id"`
+	SiteID    string        `json:"site_id"`
+}
+
+func (q *Queries) UpdateSettingPosition(ctx context.Context, arg UpdateSettingPositionParams) error {
+	_, err := q.db.ExecContext(ctx, updateSettingPosition,
+		arg.Position,
+		arg.UpdatedAt,
+		arg.ID,
+		arg.SiteID,
+	)
+	return err
+}
+
+const updateSettingValue = `-- name: UpdateSettingValue :exec
+UPDATE setting SET value = ? WHERE id = ?
+`
+
+type UpdateSettingValueParams struct {
+	Value sql.NullString `json:"value"`
+	ID    string         `json:"id"`
+}
+
+func (q *Queries) UpdateSettingValue(ctx context.Context, arg UpdateSettingValueParams) error {
+	_, err := q.db.ExecContext(ctx, updateSettingValue, arg.Value, arg.ID)
+	return err
+}
@@ -14,7 +14,7 @@ import (
 const createSite = `-- name: CreateSite :one
 INSERT INTO site (id, short_id, name, slug, active, created_by, updated_by, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at
+RETURNING id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at, last_generated_at, last_published_commit
 `
 
 type CreateSiteParams struct {
@@ -55,6 +55,8 @@ func (q *Queries) CreateSite(ctx context.Context, arg CreateSiteParams) (Site, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastPublishedAt,
+		&i.LastGeneratedAt,
+		&i.LastPublishedCommit,
 	)
 	return i, err
 }
@@ -69,7 +71,7 @@ func (q *Queries) DeleteSite(ctx context.Context, id string) error {
 }
 
 const getSite = `-- name: GetSite :one
-SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at FROM site WHERE id = ?
+SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at, last_generated_at, last_published_commit FROM site WHERE id = ?
 `
 
 func (q *Queries) GetSite(ctx context.Context, id string) (Site, error) {
@@ -88,12 +90,14 @@ func (q *Queries) GetSite(ctx context.Context, id string) (Site, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastPublishedAt,
+		&i.LastGeneratedAt,
+		&i.LastPublishedCommit,
 	)
 	return i, err
 }
 
 const getSiteBySlug = `-- name: GetSiteBySlug :one
-SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at FROM site WHERE slug = ?
+SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at, last_generated_at, last_published_commit FROM site WHERE slug = ?
 `
 
 func (q *Queries) GetSiteBySlug(ctx context.Context, slug string) (Site, error) {
@@ -112,12 +116,41 @@ func (q *Queries) GetSiteBySlug(ctx context.Context, slug string) (Site, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastPublishedAt,
+		&i.LastGeneratedAt,
+		&i.LastPublishedCommit,
+	)
+	return i, err
+}
+
+const getSiteCounts = `-- name: GetSiteCounts :one
+SELECT
+    (SELECT COUNT(*) FROM section WHERE section.site_id = ?1) AS sections,
+    (SELECT COUNT(*) FROM tag WHERE tag.site_id = ?1) AS tags,
+    (SELECT COUNT(*) FROM image WHERE image.site_id = ?1) AS images,
+    (SELECT COUNT(*) FROM contributor WHERE contributor.site_id = ?1) AS contributors
+`
+
+type GetSiteCountsRow struct {
+	Sections     int64 `json:"sections"`
+	Tags         int64 `json:"tags"`
+	Images       int64 `json:"images"`
+	Contributors int64 `json:"contributors"`
+}
+
+func (q *Queries) GetSiteCounts(ctx context.Context, siteID string) (GetSiteCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getSiteCounts, siteID)
+	var i GetSiteCountsRow
+	err := row.Scan(
+		&i.Sections,
+		&i.Tags,
+		&i.Images,
+		&i.Contributors,
 	)
 	return i, err
 }
 
 const listAllSites = `-- name: ListAllSites :many
-SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at FROM site ORDER BY name
+SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at, last_generated_at, last_published_commit FROM site ORDER BY name
 `
 
 func (q *Queries) ListAllSites(ctx context.Context) ([]Site, error) {
@@ -142,6 +175,8 @@ func (q *Queries) ListAllSites(ctx context.Context) ([]Site, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.LastPublishedAt,
+			&i.LastGeneratedAt,
+			&i.LastPublishedCommit,
 		); err != nil {
 			return nil, err
 		}
@@ -157,7 +192,7 @@ func (q *Queries) ListAllSites(ctx context.Context) ([]Site, error) {
 }
 
 const listSites = `-- name: ListSites :many
-SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at FROM site WHERE active = 1 ORDER BY name
+SELECT id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at, last_generated_at, last_published_commit FROM site WHERE active = 1 ORDER BY name
 `
 
 func (q *Queries) ListSites(ctx context.Context) ([]Site, error) {
@@ -182,6 +217,8 @@ func (q *Queries) ListSites(ctx context.Context) ([]Site, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.LastPublishedAt,
+			&i.LastGeneratedAt,
+			&i.LastPublishedCommit,
 		); err != nil {
 			return nil, err
 		}
@@ -196,6 +233,38 @@ func (q *Queries) ListSites(ctx context.Context) ([]Site, error) {
 	return items, nil
 }
 
+const markSiteGenerated = `-- name: MarkSiteGenerated :exec
+UPDATE site SET last_generated_at = ? WHERE id = ?
+`
+
+type MarkSiteGeneratedParams struct {
+	LastGeneratedAt sql.NullTime `json:"last_generated_at"`
+	ID              string       `json:"id"`
+}
+
+func (q *Queries) MarkSiteGenerated(ctx context.Context, arg MarkSiteGeneratedParams) error {
+	_, err := q.db.ExecContext(ctx, markSiteGenerated, arg.LastGeneratedAt, arg.ID)
+	return err
+}
+
+const markSitePublished = `-- name: MarkSitePublished :exec
+UPDATE site SET
+    last_published_at = ?,
+    last_published_commit = COALESCE(?, last_published_commit)
+WHERE id = ?
+`
+
+type MarkSitePublishedParams struct {
+	LastPublishedAt     sql.NullTime   `json:"last_published_at"`
+	LastPublishedCommit sql.NullString `json:"last_published_commit"`
+	ID                  string         `json:"id"`
+}
+
+func (q *Queries) MarkSitePublished(ctx context.Context, arg MarkSitePublishedParams) error {
+	_, err := q.db.ExecContext(ctx, markSitePublished, arg.LastPublishedAt, arg.LastPublishedCommit, arg.ID)
+	return err
+}
+
 const updateSite = `-- name: UpdateSite :one
 UPDATE site SET
     name = ?,
@@ -207,7 +276,7 @@ UPDATE site SET
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
-RETURNING id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at
+RETURNING id, short_id, name, slug, active, default_layout_id, default_layout_name, created_by, updated_by, created_at, updated_at, last_published_at, last_generated_at, last_published_commit
 `
 
 type UpdateSiteParams struct {
@@ -248,6 +317,8 @@ func (q *Queries) UpdateSite(ctx context.Context, arg UpdateSiteParams) (Site, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastPublishedAt,
+		&i.LastGeneratedAt,
+		&i.LastPublishedCommit,
 	)
 	return i, err
 }
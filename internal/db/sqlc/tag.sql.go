@@ -33,21 +33,23 @@ func (q *Queries) AddTagToContent(ctx context.Context, arg AddTagToContentParams
 }
 
 const createTag = `-- name: CreateTag :one
-INSERT INTO tag (id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at
+INSERT INTO tag (id, site_id, short_id, name, slug, description, header_image_id, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at, description, header_image_id
 `
 
 type CreateTagParams struct {
-	ID        string         `json:"id"`
-	SiteID    string         `json:"site_id"`
-	ShortID   sql.NullString `json:"short_id"`
-	Name      string         `json:"name"`
-	Slug      string         `json:"slug"`
-	CreatedBy sql.NullString `json:"created_by"`
-	UpdatedBy sql.NullString `json:"updated_by"`
-	CreatedAt sql.NullTime   `json:"created_at"`
-	UpdatedAt sql.NullTime   `json:"updated_at"`
+	ID            string         `json:"id"`
+	SiteID        string         `json:"site_id"`
+	ShortID       sql.NullString `json:"short_id"`
+	Name          string         `json:"name"`
+	Slug          string         `json:"slug"`
+	Description   string         `json:"description"`
+	HeaderImageID sql.NullString `json:"header_image_id"`
+	CreatedBy     sql.NullString `json:"created_by"`
+	UpdatedBy     sql.NullString `json:"updated_by"`
+	CreatedAt     sql.NullTime   `json:"created_at"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
 }
 
 func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
@@ -57,6 +59,8 @@ func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, erro
 		arg.ShortID,
 		arg.Name,
 		arg.Slug,
+		arg.Description,
+		arg.HeaderImageID,
 		arg.CreatedBy,
 		arg.UpdatedBy,
 		arg.CreatedAt,
@@ -73,6 +77,8 @@ func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, erro
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Description,
+		&i.HeaderImageID,
 	)
 	return i, err
 }
@@ -87,7 +93,7 @@ func (q *Queries) DeleteTag(ctx context.Context, id string) error {
 }
 
 const getContentForTag = `-- name: GetContentForTag :many
-SELECT c.id, c.site_id, c.user_id, c.short_id, c.section_id, c.kind, c.heading, c.summary, c.body, c.draft, c.featured, c.series, c.series_order, c.published_at, c.created_by, c.updated_by, c.created_at, c.updated_at, c.contributor_id, c.contributor_handle, c.author_username, c.hero_title_dark, c.images_meta FROM content c
+SELECT c.id, c.site_id, c.user_id, c.short_id, c.section_id, c.kind, c.heading, c.summary, c.body, c.draft, c.featured, c.series, c.series_order, c.published_at, c.created_by, c.updated_by, c.created_at, c.updated_at, c.contributor_id, c.contributor_handle, c.author_username, c.hero_title_dark, c.images_meta, c.visibility, c.lang, c.translation_group, c.layout_id, c.link_url FROM content c
 JOIN content_tag ct ON c.id = ct.content_id
 WHERE ct.tag_id = ?
 ORDER BY c.created_at DESC
@@ -126,6 +132,11 @@ func (q *Queries) GetContentForTag(ctx context.Context, tagID string) ([]Content
 			&i.AuthorUsername,
 			&i.HeroTitleDark,
 			&i.ImagesMeta,
+			&i.Visibility,
+			&i.Lang,
+			&i.TranslationGroup,
+			&i.LayoutID,
+			&i.LinkURL,
 		); err != nil {
 			return nil, err
 		}
@@ -141,7 +152,7 @@ func (q *Queries) GetContentForTag(ctx context.Context, tagID string) ([]Content
 }
 
 const getTag = `-- name: GetTag :one
-SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at FROM tag WHERE id = ?
+SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at, description, header_image_id FROM tag WHERE id = ?
 `
 
 func (q *Queries) GetTag(ctx context.Context, id string) (Tag, error) {
@@ -157,12 +168,14 @@ func (q *Queries) GetTag(ctx context.Context, id string) (Tag, error) {
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Description,
+		&i.HeaderImageID,
 	)
 	return i, err
 }
 
 const getTagByName = `-- name: GetTagByName :one
-SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at FROM tag WHERE site_id = ? AND name = ?
+SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at, description, header_image_id FROM tag WHERE site_id = ? AND name = ?
 `
 
 type GetTagByNameParams struct {
@@ -183,12 +196,14 @@ func (q *Queries) GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Description,
+		&i.HeaderImageID,
 	)
 	return i, err
 }
 
 const getTagBySlug = `-- name: GetTagBySlug :one
-SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at FROM tag WHERE site_id = ? AND slug = ?
+SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at, description, header_image_id FROM tag WHERE site_id = ? AND slug = ?
 `
 
 type GetTagBySlugParams struct {
@@ -209,12 +224,14 @@ func (q *Queries) GetTagBySlug(ctx context.Context, arg GetTagBySlugParams) (Tag
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Description,
+		&i.HeaderImageID,
 	)
 	return i, err
 }
 
 const getTagsBySiteID = `-- name: GetTagsBySiteID :many
-SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at FROM tag WHERE site_id = ? ORDER BY name
+SELECT id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at, description, header_image_id FROM tag WHERE site_id = ? ORDER BY name
 `
 
 func (q *Queries) GetTagsBySiteID(ctx context.Context, siteID string) ([]Tag, error) {
@@ -236,6 +253,8 @@ func (q *Queries) GetTagsBySiteID(ctx context.Context, siteID string) ([]Tag, er
 			&i.UpdatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Description,
+			&i.HeaderImageID,
 		); err != nil {
 			return nil, err
 		}
@@ -251,21 +270,40 @@ func (q *Queries) GetTagsBySiteID(ctx context.Context, siteID string) ([]Tag, er
 }
 
 const getTagsForContent = `-- name: GetTagsForContent :many
-SELECT t.id, t.site_id, t.short_id, t.name, t.slug, t.created_by, t.updated_by, t.created_at, t.updated_at FROM tag t
+SELECT
+    t.id, t.site_id, t.short_id, t.name, t.slug, t.created_by, t.updated_by, t.created_at, t.updated_at, t.description, t.header_image_id,
+    hi.file_path as header_image_path
+FROM tag t
 JOIN content_tag ct ON t.id = ct.tag_id
+LEFT JOIN image hi ON t.header_image_id = hi.id
 WHERE ct.content_id = ?
 ORDER BY t.name
 `
 
-func (q *Queries) GetTagsForContent(ctx context.Context, contentID string) ([]Tag, error) {
+type GetTagsForContentRow struct {
+	ID              string         `json:"id"`
+	SiteID          string         `json:"site_id"`
+	ShortID         sql.NullString `json:"short_id"`
+	Name            string         `json:"name"`
+	Slug            string         `json:"slug"`
+	CreatedBy       sql.NullString `json:"created_by"`
+	UpdatedBy       sql.NullString `json:"updated_by"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	Description     string         `json:"description"`
+	HeaderImageID   sql.NullString `json:"header_image_id"`
+	HeaderImagePath sql.NullString `json:"header_image_path"`
+}
+
+func (q *Queries) GetTagsForContent(ctx context.Context, contentID string) ([]GetTagsForContentRow, error) {
 	rows, err := q.db.QueryContext(ctx, getTagsForContent, contentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Tag
+	var items []GetTagsForContentRow
 	for rows.Next() {
-		var i Tag
+		var i GetTagsForContentRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.SiteID,
@@ -276,6 +314,9 @@ func (q *Queries) GetTagsForContent(ctx context.Context, contentID string) ([]Ta
 			&i.UpdatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Description,
+			&i.HeaderImageID,
+			&i.HeaderImagePath,
 		); err != nil {
 			return nil, err
 		}
@@ -317,24 +358,30 @@ const updateTag = `-- name: UpdateTag :one
 UPDATE tag SET
     name = ?,
     slug = ?,
+    description = ?,
+    header_image_id = ?,
     updated_by = ?,
     updated_at = ?
 WHERE id = ?
-RETURNING id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at
+RETURNING id, site_id, short_id, name, slug, created_by, updated_by, created_at, updated_at, description, header_image_id
 `
 
 type UpdateTagParams struct {
-	Name      string         `json:"name"`
-	Slug      string         `json:"slug"`
-	UpdatedBy sql.NullString `json:"updated_by"`
-	UpdatedAt sql.NullTime   `json:"updated_at"`
-	ID        string         `json:"id"`
+	Name          string         `json:"name"`
+	Slug          string         `json:"slug"`
+	Description   string         `json:"description"`
+	HeaderImageID sql.NullString `json:"header_image_id"`
+	UpdatedBy     sql.NullString `json:"updated_by"`
+	UpdatedAt     sql.NullTime   `json:"updated_at"`
+	ID            string         `json:"id"`
 }
 
 func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error) {
 	row := q.db.QueryRowContext(ctx, updateTag,
 		arg.Name,
 		arg.Slug,
+		arg.Description,
+		arg.HeaderImageID,
 		arg.UpdatedBy,
 		arg.UpdatedAt,
 		arg.ID,
@@ -350,6 +397,8 @@ func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, erro
 		&i.UpdatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Description,
+		&i.HeaderImageID,
 	)
 	return i, err
 }
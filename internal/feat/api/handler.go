@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/cliossg/clio/internal/feat/ssg"
 	"github.com/cliossg/clio/pkg/cl/config"
+	"github.com/cliossg/clio/pkg/cl/git"
 	"github.com/cliossg/clio/pkg/cl/logger"
 	"github.com/cliossg/clio/pkg/cl/middleware"
 	"github.com/cliossg/clio/pkg/cl/render"
@@ -265,6 +267,8 @@ func (h *Handler) APICreatePost(w http.ResponseWriter, r *http.Request) {
 		Featured    bool   `json:"featured"`
 		Series      string `json:"series"`
 		SeriesOrder int    `json:"series_order"`
+		Visibility  string `json:"visibility"`
+		LinkURL     string `json:"link_url"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		jsonError(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
@@ -275,6 +279,10 @@ func (h *Handler) APICreatePost(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, http.StatusBadRequest, "validation_error", "Heading is required")
 		return
 	}
+	if req.Visibility != "" && !validVisibility(req.Visibility) {
+		jsonError(w, http.StatusBadRequest, "validation_error", "Visibility must be public, unlisted or private")
+		return
+	}
 
 	var sectionID uuid.UUID
 	if req.SectionID != "" {
@@ -297,6 +305,10 @@ func (h *Handler) APICreatePost(w http.ResponseWriter, r *http.Request) {
 	content.Featured = req.Featured
 	content.Series = req.Series
 	content.SeriesOrder = req.SeriesOrder
+	if req.Visibility != "" {
+		content.Visibility = req.Visibility
+	}
+	content.LinkURL = req.LinkURL
 
 	userIDStr := GetUserIDFromContext(r.Context())
 	if userID, err := uuid.Parse(userIDStr); err == nil {
@@ -306,6 +318,14 @@ func (h *Handler) APICreatePost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.ssgService.CreateContent(r.Context(), content); err != nil {
+		if errors.Is(err, ssg.ErrInvalidLinkURL) {
+			jsonError(w, http.StatusBadRequest, "validation_error", "Link URL must be an absolute http or https URL")
+			return
+		}
+		if errors.Is(err, ssg.ErrNotApproved) {
+			jsonError(w, http.StatusConflict, "not_approved", "Content must be approved by a reviewer before it is published")
+			return
+		}
 		h.log.Errorf("Cannot create post: %v", err)
 		jsonError(w, http.StatusInternalServerError, "internal_error", "Cannot create post")
 		return
@@ -337,6 +357,8 @@ func (h *Handler) APIUpdatePost(w http.ResponseWriter, r *http.Request) {
 		Featured    *bool   `json:"featured"`
 		Series      *string `json:"series"`
 		SeriesOrder *int    `json:"series_order"`
+		Visibility  *string `json:"visibility"`
+		LinkURL     *string `json:"link_url"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		jsonError(w, http.StatusBadRequest, "invalid_request", "Invalid JSON body")
@@ -367,6 +389,16 @@ func (h *Handler) APIUpdatePost(w http.ResponseWriter, r *http.Request) {
 	if req.SeriesOrder != nil {
 		existing.SeriesOrder = *req.SeriesOrder
 	}
+	if req.Visibility != nil {
+		if !validVisibility(*req.Visibility) {
+			jsonError(w, http.StatusBadRequest, "validation_error", "Visibility must be public, unlisted or private")
+			return
+		}
+		existing.Visibility = *req.Visibility
+	}
+	if req.LinkURL != nil {
+		existing.LinkURL = *req.LinkURL
+	}
 	if req.SectionID != nil {
 		if sid, err := uuid.Parse(*req.SectionID); err == nil {
 			existing.SectionID = sid
@@ -380,6 +412,14 @@ func (h *Handler) APIUpdatePost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.ssgService.UpdateContent(r.Context(), existing); err != nil {
+		if errors.Is(err, ssg.ErrInvalidLinkURL) {
+			jsonError(w, http.StatusBadRequest, "validation_error", "Link URL must be an absolute http or https URL")
+			return
+		}
+		if errors.Is(err, ssg.ErrNotApproved) {
+			jsonError(w, http.StatusConflict, "not_approved", "Content must be approved by a reviewer before it is published")
+			return
+		}
 		h.log.Errorf("Cannot update post: %v", err)
 		jsonError(w, http.StatusInternalServerError, "internal_error", "Cannot update post")
 		return
@@ -412,19 +452,46 @@ func (h *Handler) APIGenerate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.generateHTML(r.Context(), site)
+	force := r.URL.Query().Get("force") == "true"
+
+	result, err := h.generateHTML(r.Context(), site, force)
 	if err != nil {
 		h.log.Errorf("HTML generation failed: %v", err)
+		if errors.As(err, new(*ssg.PathConflictError)) {
+			jsonError(w, http.StatusConflict, "path_conflict", err.Error())
+			return
+		}
 		jsonError(w, http.StatusInternalServerError, "generation_error", err.Error())
 		return
 	}
+	if err := h.ssgService.MarkSiteGenerated(r.Context(), site.ID, time.Now()); err != nil {
+		h.log.Errorf("Cannot record generation time: %v", err)
+	}
 
 	jsonOK(w, map[string]any{
-		"status":          "generated",
-		"pages_generated": result.PagesGenerated,
-		"index_pages":     result.IndexPages,
-		"author_pages":    result.AuthorPages,
-		"errors":          len(result.Errors),
+		"status":             "generated",
+		"pages_generated":    result.PagesGenerated,
+		"index_pages":        result.IndexPages,
+		"author_pages":       result.AuthorPages,
+		"tag_pages":          result.TagPages,
+		"archive_pages":      result.ArchivePages,
+		"paginated_pages":    result.PaginatedPages,
+		"pages_skipped":      result.PagesSkipped,
+		"feeds":              result.Feeds,
+		"bytes_saved":        result.BytesSaved,
+		"files_removed":      result.FilesRemoved,
+		"ai_files":           result.AIFiles,
+		"changelog_entries":  result.ChangelogEntries,
+		"og_images":          result.OGImages,
+		"og_images_drawn":    result.OGImagesDrawn,
+		"peak_workers":       result.PeakWorkers,
+		"peak_image_workers": result.PeakImageWorkers,
+		"peak_heap_bytes":    result.PeakHeap,
+		"a11y_errors":        result.Accessibility.Errors(),
+		"a11y_warnings":      result.Accessibility.Warnings(),
+		"incremental":        result.Incremental,
+		"errors":             len(result.Errors),
+		"warnings":           result.Warnings,
 	})
 }
 
@@ -435,12 +502,23 @@ func (h *Handler) APIPublish(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate HTML first
-	_, err = h.generateHTML(r.Context(), site)
+	result, err := h.generateHTML(r.Context(), site, false)
 	if err != nil {
 		h.log.Errorf("HTML generation failed during publish: %v", err)
+		if errors.As(err, new(*ssg.PathConflictError)) {
+			jsonError(w, http.StatusConflict, "path_conflict", err.Error())
+			return
+		}
 		jsonError(w, http.StatusInternalServerError, "generation_error", err.Error())
 		return
 	}
+	if err := h.ssgService.MarkSiteGenerated(r.Context(), site.ID, time.Now()); err != nil {
+		h.log.Errorf("Cannot record generation time: %v", err)
+	}
+	if result.PublishBlocked {
+		jsonError(w, http.StatusConflict, "a11y_errors", fmt.Sprintf("Publishing blocked: %d accessibility errors", result.Accessibility.Errors()))
+		return
+	}
 
 	// Get publish settings
 	publishCfg, err := h.getPublishConfig(r.Context(), site.ID, "ssg.publish.repo.url", "ssg.publish.auth.token", "ssg.publish.branch", "gh-pages")
@@ -452,18 +530,23 @@ func (h *Handler) APIPublish(w http.ResponseWriter, r *http.Request) {
 	publishResult, err := h.publisher.Publish(r.Context(), publishCfg, site.Slug)
 	if err != nil {
 		h.log.Errorf("Publish failed: %v", err)
+		if errors.Is(err, git.ErrSigningFailed) {
+			jsonError(w, http.StatusInternalServerError, "signing_error", "Publish commit could not be signed")
+			return
+		}
 		jsonError(w, http.StatusInternalServerError, "publish_error", "Publish to git failed")
 		return
 	}
 
+	if err := h.ssgService.MarkSitePublished(r.Context(), site.ID, time.Now(), publishResult.CommitHash); err != nil {
+		h.log.Errorf("Cannot record publish time: %v", err)
+	}
+
 	if publishResult.NoChanges {
 		jsonOK(w, map[string]any{"status": "no_changes"})
 		return
 	}
 
-	site.LastPublishedAt = timePtr(time.Now())
-	_ = h.ssgService.UpdateSite(r.Context(), site)
-
 	jsonOK(w, map[string]any{
 		"status":      "published",
 		"commit_hash": publishResult.CommitHash,
@@ -488,8 +571,15 @@ func (h *Handler) APIBackup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loc, err := h.ssgService.GetTimezone(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get site timezone for backup: %v", err)
+		jsonError(w, http.StatusInternalServerError, "internal_error", "Cannot load settings")
+		return
+	}
+
 	generator := ssg.NewGenerator(h.workspace)
-	mdResult, err := generator.GenerateMarkdown(r.Context(), site.Slug, contents)
+	mdResult, err := generator.GenerateMarkdown(r.Context(), site.Slug, contents, loc)
 	if err != nil {
 		h.log.Errorf("Markdown generation failed: %v", err)
 		jsonError(w, http.StatusInternalServerError, "generation_error", "Markdown generation failed")
@@ -513,6 +603,10 @@ func (h *Handler) APIBackup(w http.ResponseWriter, r *http.Request) {
 	backupResult, err := h.publisher.Backup(r.Context(), backupCfg, site.Slug)
 	if err != nil {
 		h.log.Errorf("Backup to git failed: %v", err)
+		if errors.Is(err, git.ErrSigningFailed) {
+			jsonError(w, http.StatusInternalServerError, "signing_error", "Backup commit could not be signed")
+			return
+		}
 		jsonError(w, http.StatusInternalServerError, "backup_error", "Backup to git failed")
 		return
 	}
@@ -550,7 +644,7 @@ func (h *Handler) getSiteFromParam(w http.ResponseWriter, r *http.Request) (*ssg
 	return site, nil
 }
 
-func (h *Handler) generateHTML(ctx context.Context, site *ssg.Site) (*ssg.GenerateHTMLResult, error) {
+func (h *Handler) generateHTML(ctx context.Context, site *ssg.Site, force bool) (*ssg.GenerateHTMLResult, error) {
 	contents, err := h.ssgService.GetAllContentWithMeta(ctx, site.ID)
 	if err != nil {
 		return nil, fmt.Errorf("cannot load content: %w", err)
@@ -566,6 +660,8 @@ func (h *Handler) generateHTML(ctx context.Context, site *ssg.Site) (*ssg.Genera
 		layouts = []*ssg.Layout{}
 	}
 
+	kinds, _ := h.ssgService.ListContentKinds(ctx, site.ID)
+
 	params, err := h.ssgService.GetSettings(ctx, site.ID)
 	if err != nil {
 		params = []*ssg.Setting{}
@@ -578,7 +674,7 @@ func (h *Handler) generateHTML(ctx context.Context, site *ssg.Site) (*ssg.Genera
 
 	userAuthors := h.ssgService.BuildUserAuthorsMap(ctx, contents, contributors)
 
-	return h.htmlGen.GenerateHTML(ctx, site, contents, sections, layouts, params, contributors, userAuthors)
+	return h.htmlGen.GenerateHTML(ctx, site, contents, sections, layouts, kinds, params, contributors, userAuthors, force)
 }
 
 func (h *Handler) getPublishConfig(ctx context.Context, siteID uuid.UUID, repoKey, tokenKey, branchKey, defaultBranch string) (ssg.PublishConfig, error) {
@@ -587,7 +683,10 @@ func (h *Handler) getPublishConfig(ctx context.Context, siteID uuid.UUID, repoKe
 		return ssg.PublishConfig{}, fmt.Errorf("repository URL not configured (%s)", repoKey)
 	}
 
-	authToken, _ := h.ssgService.GetSettingByRefKey(ctx, siteID, tokenKey)
+	authToken, err := h.ssgService.GetSettingByRefKey(ctx, siteID, tokenKey)
+	if err != nil && !errors.Is(err, ssg.ErrNotFound) {
+		return ssg.PublishConfig{}, err
+	}
 	branch, _ := h.ssgService.GetSettingByRefKey(ctx, siteID, branchKey)
 	commitName, _ := h.ssgService.GetSettingByRefKey(ctx, siteID, "ssg.git.commit.user.name")
 	commitEmail, _ := h.ssgService.GetSettingByRefKey(ctx, siteID, "ssg.git.commit.user.email")
@@ -614,6 +713,12 @@ func (h *Handler) getPublishConfig(ctx context.Context, siteID uuid.UUID, repoKe
 		useSSH = false
 	}
 
+	// GetSettings reads undecryptable secrets as empty, which would fall
+	// back to the host SSH credentials and unsigned commits.
+	if err := ssg.CheckSecrets(ctx, h.ssgService, siteID); err != nil {
+		return ssg.PublishConfig{}, err
+	}
+	settings, _ := h.ssgService.GetSettings(ctx, siteID)
 	return ssg.PublishConfig{
 		RepoURL:     repoURL.Value,
 		Branch:      branchValue,
@@ -621,6 +726,8 @@ func (h *Handler) getPublishConfig(ctx context.Context, siteID uuid.UUID, repoKe
 		CommitName:  commitNameValue,
 		CommitEmail: commitEmailValue,
 		UseSSH:      useSSH,
+		SSH:         ssg.SSHOptionsFromSettings(settings),
+		Signing:     ssg.SigningFromSettings(settings),
 	}, nil
 }
 
@@ -628,6 +735,14 @@ func timePtr(t time.Time) *time.Time {
 	return &t
 }
 
+func validVisibility(v string) bool {
+	switch v {
+	case ssg.VisibilityPublic, ssg.VisibilityUnlisted, ssg.VisibilityPrivate:
+		return true
+	}
+	return false
+}
+
 // --- Token Management UI ---
 
 type tokenPageData struct {
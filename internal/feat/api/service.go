@@ -19,6 +19,7 @@ type Service interface {
 	ValidateToken(ctx context.Context, rawToken string) (*APIToken, error)
 	ListTokens(ctx context.Context, userID uuid.UUID) ([]*APIToken, error)
 	DeleteToken(ctx context.Context, id uuid.UUID) error
+	PurgeExpiredTokens(ctx context.Context, before time.Time, batch int) (int, error)
 }
 
 // DBProvider provides access to the database.
@@ -125,3 +126,25 @@ func (s *service) DeleteToken(ctx context.Context, id uuid.UUID) error {
 	}
 	return nil
 }
+
+// PurgeExpiredTokens deletes tokens that expired before the given time, batch
+// rows at a time. Tokens without an expiry are kept.
+func (s *service) PurgeExpiredTokens(ctx context.Context, before time.Time, batch int) (int, error) {
+	s.ensureQueries()
+
+	batch = max(batch, 1)
+	total := 0
+	for {
+		n, err := s.queries.PurgeExpiredAPITokens(ctx, sqlc.PurgeExpiredAPITokensParams{
+			ExpiresAt: sql.NullTime{Time: before, Valid: true},
+			Limit:     int64(batch),
+		})
+		if err != nil {
+			return total, fmt.Errorf("cannot purge expired API tokens: %w", err)
+		}
+		total += int(n)
+		if n < int64(batch) {
+			return total, nil
+		}
+	}
+}
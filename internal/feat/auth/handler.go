@@ -2,11 +2,13 @@ package auth
 
 import (
 	"context"
-	"crypto/rand"
 	"embed"
-	"encoding/base64"
+	"errors"
+	"fmt"
 	"html/template"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -93,6 +95,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Get("/admin/edit-user", h.HandleEditUser)
 			r.Post("/admin/update-user", h.HandleUpdateUser)
 			r.Post("/admin/delete-user", h.HandleDeleteUser)
+			r.Post("/admin/unlock-user", h.HandleUnlockUser)
 		})
 	})
 }
@@ -127,6 +130,7 @@ type PageData struct {
 	Success  string
 	Email    string
 	Site     interface{}
+	Policy   PasswordPolicy
 }
 
 // HandleSignIn handles both GET and POST for the sign in page.
@@ -145,6 +149,20 @@ func (h *Handler) HandleSignIn(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, err := h.service.Authenticate(r.Context(), email, password)
+	var locked *LockedError
+	if errors.As(err, &locked) {
+		h.log.Errorf("Authentication failed: %v", err)
+		wait := max(time.Until(locked.Until), time.Second)
+		minutes := int(math.Ceil(wait.Minutes()))
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		msg := "Too many failed sign-in attempts. Try again in 1 minute."
+		if minutes > 1 {
+			msg = fmt.Sprintf("Too many failed sign-in attempts. Try again in %d minutes.", minutes)
+		}
+		h.renderSignInForm(w, msg, email)
+		return
+	}
 	if err != nil {
 		h.log.Errorf("Authentication failed: %v", err)
 		h.renderSignInForm(w, "Invalid email or password", email)
@@ -284,17 +302,17 @@ func (h *Handler) HandleChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(newPassword) < 8 {
-		h.renderChangePasswordForm(w, "Password must be at least 8 characters", user.MustChangePassword)
-		return
-	}
-
 	if !user.CheckPassword(currentPassword) {
 		h.renderChangePasswordForm(w, "Current password is incorrect", user.MustChangePassword)
 		return
 	}
 
-	if err := user.UpdatePassword(newPassword); err != nil {
+	if err := h.service.SetPassword(user, newPassword); err != nil {
+		var pwErr *PasswordError
+		if errors.As(err, &pwErr) {
+			h.renderChangePasswordForm(w, pwErr.Error(), user.MustChangePassword)
+			return
+		}
 		h.log.Errorf("Cannot update password: %v", err)
 		h.renderChangePasswordForm(w, "Cannot update password", user.MustChangePassword)
 		return
@@ -318,6 +336,7 @@ func (h *Handler) renderChangePasswordForm(w http.ResponseWriter, errorMsg strin
 		HideNav:  mustChange,
 		AuthPage: true,
 		Error:    errorMsg,
+		Policy:   h.service.PasswordPolicy(),
 	}
 
 	if h.tmpl == nil {
@@ -356,7 +375,7 @@ h1 { margin: 0 0 24px 0; font-size: 1.5rem; text-align: center; }
 		}
 		html += `<form method="POST" action="/change-password">
 <div class="form-group"><label for="current_password">Current Password</label><input type="password" id="current_password" name="current_password" required></div>
-<div class="form-group"><label for="new_password">New Password</label><input type="password" id="new_password" name="new_password" required minlength="8"></div>
+<div class="form-group"><label for="new_password">New Password</label><input type="password" id="new_password" name="new_password" required></div>
 <div class="form-group"><label for="confirm_password">Confirm New Password</label><input type="password" id="confirm_password" name="confirm_password" required></div>
 <button type="submit" class="btn">Change Password</button>
 </form>
@@ -451,6 +470,8 @@ type AdminPageData struct {
 	User             *User
 	Users            []*User
 	GeneratedPass    string
+	LockedUntil      time.Time
+	Policy           PasswordPolicy
 	CurrentUserName  string
 	CurrentUserRoles string
 }
@@ -468,6 +489,8 @@ func (h *Handler) renderAdmin(w http.ResponseWriter, r *http.Request, templateNa
 		},
 	})
 
+	data.Policy = h.service.PasswordPolicy()
+
 	if data.CurrentUserName == "" || data.CurrentUserRoles == "" {
 		if user, err := h.GetCurrentUser(r.Context()); err == nil {
 			if data.CurrentUserName == "" {
@@ -498,12 +521,6 @@ func (h *Handler) renderAdmin(w http.ResponseWriter, r *http.Request, templateNa
 	}
 }
 
-func generatePassword(length int) string {
-	b := make([]byte, length)
-	rand.Read(b)
-	return base64.URLEncoding.EncodeToString(b)[:length]
-}
-
 func (h *Handler) HandleListUsers(w http.ResponseWriter, r *http.Request) {
 	users, err := h.service.ListUsers(r.Context())
 	if err != nil {
@@ -524,7 +541,7 @@ func (h *Handler) HandleListUsers(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) HandleNewUser(w http.ResponseWriter, r *http.Request) {
 	h.renderAdmin(w, r, "admin/users/new", AdminPageData{
 		Title:         "New User",
-		GeneratedPass: generatePassword(12),
+		GeneratedPass: GeneratePassword(h.service.PasswordPolicy()),
 	})
 }
 
@@ -547,10 +564,16 @@ func (h *Handler) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.service.CreateUser(r.Context(), email, password, name, roles, mustChangePassword)
 	if err != nil {
-		h.log.Errorf("Cannot create user: %v", err)
+		errMsg := "Cannot create user: " + err.Error()
+		var pwErr *PasswordError
+		if errors.As(err, &pwErr) {
+			errMsg = pwErr.Error()
+		} else {
+			h.log.Errorf("Cannot create user: %v", err)
+		}
 		h.renderAdmin(w, r, "admin/users/new", AdminPageData{
 			Title:         "New User",
-			Error:         "Cannot create user: " + err.Error(),
+			Error:         errMsg,
 			GeneratedPass: password,
 		})
 		return
@@ -585,9 +608,15 @@ func (h *Handler) HandleShowUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	lockedUntil, err := h.service.LockedUntil(r.Context(), id)
+	if err != nil {
+		h.log.Errorf("Cannot get lock state: %v", err)
+	}
+
 	h.renderAdmin(w, r, "admin/users/show", AdminPageData{
-		Title: user.Name,
-		User:  user,
+		Title:       user.Name,
+		User:        user,
+		LockedUntil: lockedUntil,
 	})
 }
 
@@ -640,12 +669,18 @@ func (h *Handler) HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	newPassword := r.FormValue("new_password")
 	if newPassword != "" {
-		if err := user.UpdatePassword(newPassword); err != nil {
-			h.log.Errorf("Cannot update password: %v", err)
+		if err := h.service.SetPassword(user, newPassword); err != nil {
+			errMsg := "Cannot update password"
+			var pwErr *PasswordError
+			if errors.As(err, &pwErr) {
+				errMsg = pwErr.Error()
+			} else {
+				h.log.Errorf("Cannot update password: %v", err)
+			}
 			h.renderAdmin(w, r, "admin/users/edit", AdminPageData{
 				Title: "Edit " + user.Name,
 				User:  user,
-				Error: "Cannot update password",
+				Error: errMsg,
 			})
 			return
 		}
@@ -703,6 +738,25 @@ func (h *Handler) HandleDeleteUser(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin/list-users", http.StatusSeeOther)
 }
 
+// HandleUnlockUser clears a user's failed sign ins so a locked account can
+// sign in again before the lock runs out.
+func (h *Handler) HandleUnlockUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ResetLoginFailures(r.Context(), id); err != nil {
+		h.log.Errorf("Cannot unlock user: %v", err)
+		http.Error(w, "Cannot unlock user", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Infof("User unlocked: %s", id)
+	http.Redirect(w, r, "/admin/get-user?id="+id.String(), http.StatusSeeOther)
+}
+
 func normalizeSlug(s string) string {
 	s = strings.ToLower(s)
 	var result strings.Builder
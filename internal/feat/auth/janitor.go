@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/logger"
+)
+
+const (
+	// DefaultCleanupInterval is how often the janitor runs when
+	// auth.cleanup_interval is not set.
+	DefaultCleanupInterval = time.Hour
+	// janitorBatch is the most rows a single purge statement deletes.
+	janitorBatch = 500
+)
+
+// PurgeFunc deletes rows that expired before the given time, at most batch
+// rows per statement, and returns how many it deleted.
+type PurgeFunc func(ctx context.Context, before time.Time, batch int) (int, error)
+
+type janitorTask struct {
+	name  string
+	purge PurgeFunc
+}
+
+// Janitor deletes expired rows, such as sessions and API tokens, so they don't
+// pile up in the database. It purges once when started and then on every
+// interval until stopped.
+type Janitor struct {
+	interval time.Duration
+	batch    int
+	log      logger.Logger
+	tasks    []janitorTask
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJanitor creates a janitor that runs every interval, or every
+// DefaultCleanupInterval when interval is not positive.
+func NewJanitor(interval time.Duration, log logger.Logger) *Janitor {
+	if interval <= 0 {
+		interval = DefaultCleanupInterval
+	}
+	return &Janitor{interval: interval, batch: janitorBatch, log: log}
+}
+
+// ParseCleanupInterval parses the auth.cleanup_interval setting. Empty means
+// DefaultCleanupInterval; values under a minute are rejected.
+func ParseCleanupInterval(s string) (time.Duration, error) {
+	if s == "" {
+		return DefaultCleanupInterval, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Minute {
+		return 0, fmt.Errorf("must be at least 1m, got %s", d)
+	}
+	return d, nil
+}
+
+// Add registers a purge to run on every pass. name is used in log messages,
+// e.g. "sessions". Tasks must be added before Start.
+func (j *Janitor) Add(name string, purge PurgeFunc) {
+	j.tasks = append(j.tasks, janitorTask{name: name, purge: purge})
+}
+
+func (j *Janitor) Start(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.cancel != nil {
+		return nil
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	j.cancel = cancel
+	j.done = make(chan struct{})
+	go j.run(runCtx, j.done)
+
+	j.log.Infof("Janitor: started with interval %s", j.interval)
+	return nil
+}
+
+// Stop cancels a running pass and waits for it to return, or for ctx to end.
+func (j *Janitor) Stop(ctx context.Context) error {
+	j.mu.Lock()
+	cancel, done := j.cancel, j.done
+	j.cancel, j.done = nil, nil
+	j.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	j.log.Info("Janitor: stopped")
+	return nil
+}
+
+func (j *Janitor) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	j.Purge(ctx)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.Purge(ctx)
+		}
+	}
+}
+
+// Purge runs every task once and returns the rows each one deleted, by name.
+// A failing task is logged and doesn't stop the others.
+func (j *Janitor) Purge(ctx context.Context) map[string]int {
+	removed := make(map[string]int, len(j.tasks))
+	now := time.Now()
+	for _, task := range j.tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		n, err := task.purge(ctx, now, j.batch)
+		removed[task.name] = n
+		if err != nil {
+			if ctx.Err() == nil {
+				j.log.Errorf("Janitor: cannot purge %s: %v", task.name, err)
+			}
+			continue
+		}
+		if n > 0 {
+			j.log.Infof("Janitor: removed %d expired %s", n, task.name)
+		}
+	}
+	return removed
+}
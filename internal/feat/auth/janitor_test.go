@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJanitorPurge(t *testing.T) {
+	j := NewJanitor(0, newTestLogger())
+	if j.interval != DefaultCleanupInterval {
+		t.Errorf("interval = %s, want the default", j.interval)
+	}
+
+	var ran []string
+	j.Add("sessions", func(ctx context.Context, before time.Time, batch int) (int, error) {
+		ran = append(ran, "sessions")
+		if batch != janitorBatch || time.Since(before) > time.Minute {
+			t.Errorf("purge called with before %s, batch %d", before, batch)
+		}
+		return 0, errors.New("database is locked")
+	})
+	j.Add("API tokens", func(ctx context.Context, before time.Time, batch int) (int, error) {
+		ran = append(ran, "API tokens")
+		return 3, nil
+	})
+
+	removed := j.Purge(context.Background())
+	if len(ran) != 2 {
+		t.Errorf("a failing task should not stop the others, ran %v", ran)
+	}
+	if removed["API tokens"] != 3 || removed["sessions"] != 0 {
+		t.Errorf("removed = %v", removed)
+	}
+}
+
+func TestJanitorStartStop(t *testing.T) {
+	j := NewJanitor(time.Hour, newTestLogger())
+	purged := make(chan struct{}, 1)
+	j.Add("sessions", func(ctx context.Context, before time.Time, batch int) (int, error) {
+		purged <- struct{}{}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	if err := j.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-purged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the janitor should purge right after starting")
+	}
+
+	// Stop cancels the pass in progress and waits for it.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := j.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := j.Stop(ctx); err != nil {
+		t.Fatalf("second Stop() error = %v", err)
+	}
+}
+
+func TestParseCleanupInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", DefaultCleanupInterval, false},
+		{"30m", 30 * time.Minute, false},
+		{"24h", 24 * time.Hour, false},
+		{"10s", 0, true},
+		{"-1h", 0, true},
+		{"hourly", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCleanupInterval(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCleanupInterval(%q) = %s, %v", tt.in, got, err)
+		}
+	}
+}
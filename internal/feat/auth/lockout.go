@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultLockoutPeriod is the failure window and lock duration used when
+// auth.lockout leaves them empty.
+const DefaultLockoutPeriod = 15 * time.Minute
+
+// ErrAccountLocked is returned, wrapped in a *LockedError, by Authenticate
+// while an account is locked.
+var ErrAccountLocked = errors.New("account locked")
+
+// LockedError tells when a locked account can sign in again.
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("account locked until %s", e.Until.Format(time.RFC3339))
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// LockoutPolicy locks an account for Duration after MaxFailures failed sign
+// ins within Window. MaxFailures 0 turns lockout off.
+type LockoutPolicy struct {
+	MaxFailures int
+	Window      time.Duration
+	Duration    time.Duration
+}
+
+// ParseLockout parses the auth.lockout settings. Empty durations default to
+// DefaultLockoutPeriod.
+func ParseLockout(cfg config.LockoutConfig) (LockoutPolicy, error) {
+	if cfg.MaxFailures < 0 {
+		return LockoutPolicy{}, fmt.Errorf("max_failures must not be negative")
+	}
+	window, err := parseLockoutPeriod("window", cfg.Window)
+	if err != nil {
+		return LockoutPolicy{}, err
+	}
+	duration, err := parseLockoutPeriod("duration", cfg.Duration)
+	if err != nil {
+		return LockoutPolicy{}, err
+	}
+	return LockoutPolicy{MaxFailures: cfg.MaxFailures, Window: window, Duration: duration}, nil
+}
+
+func parseLockoutPeriod(name, s string) (time.Duration, error) {
+	if s == "" {
+		return DefaultLockoutPeriod, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %s", name, d)
+	}
+	return d, nil
+}
+
+// Enabled reports whether failed sign ins can lock an account.
+func (p LockoutPolicy) Enabled() bool {
+	return p.MaxFailures > 0
+}
+
+// loginFailures is the failure count of one account.
+type loginFailures struct {
+	Failures      int
+	FirstFailedAt time.Time
+	LockedUntil   time.Time
+}
+
+func (f loginFailures) locked(now time.Time) bool {
+	return now.Before(f.LockedUntil)
+}
+
+// record adds a failure at now. The count starts over once the window has
+// passed since the first failure or a lock has run out.
+func (p LockoutPolicy) record(f loginFailures, now time.Time) loginFailures {
+	expiredLock := !f.LockedUntil.IsZero() && !f.locked(now)
+	if f.Failures == 0 || expiredLock || now.Sub(f.FirstFailedAt) > p.Window {
+		f = loginFailures{FirstFailedAt: now}
+	}
+	f.Failures++
+	if f.Failures >= p.MaxFailures {
+		f.LockedUntil = now.Add(p.Duration)
+	}
+	return f
+}
+
+// unknownLogins counts failures for emails with no account, by the same
+// policy, so sign in answers alike whether or not an account exists.
+type unknownLogins struct {
+	mu      sync.Mutex
+	entries map[string]loginFailures
+}
+
+// fail records a failure for email and returns when it is locked until, zero
+// if it is not.
+func (u *unknownLogins) fail(policy LockoutPolicy, email string, now time.Time) time.Time {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.entries == nil {
+		u.entries = make(map[string]loginFailures)
+	}
+	for key, f := range u.entries {
+		if !f.locked(now) && now.Sub(f.FirstFailedAt) > policy.Window {
+			delete(u.entries, key)
+		}
+	}
+
+	key := strings.ToLower(email)
+	if f, ok := u.entries[key]; ok && f.locked(now) {
+		return f.LockedUntil
+	}
+	f := policy.record(u.entries[key], now)
+	u.entries[key] = f
+	if f.locked(now) {
+		return f.LockedUntil
+	}
+	return time.Time{}
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// compareDummyHash takes as long as checking a real password, for emails
+// with no account.
+func compareDummyHash(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("clio-dummy-password"), bcrypt.DefaultCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/config"
+)
+
+func TestParseLockout(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.LockoutConfig
+		want    LockoutPolicy
+		wantErr bool
+	}{
+		{"defaults", config.LockoutConfig{MaxFailures: 5}, LockoutPolicy{5, DefaultLockoutPeriod, DefaultLockoutPeriod}, false},
+		{"set", config.LockoutConfig{MaxFailures: 3, Window: "1h", Duration: "30m"}, LockoutPolicy{3, time.Hour, 30 * time.Minute}, false},
+		{"disabled", config.LockoutConfig{}, LockoutPolicy{0, DefaultLockoutPeriod, DefaultLockoutPeriod}, false},
+		{"negative failures", config.LockoutConfig{MaxFailures: -1}, LockoutPolicy{}, true},
+		{"bad window", config.LockoutConfig{MaxFailures: 5, Window: "soon"}, LockoutPolicy{}, true},
+		{"zero duration", config.LockoutConfig{MaxFailures: 5, Duration: "0s"}, LockoutPolicy{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLockout(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLockout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLockout() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutPolicyRecord(t *testing.T) {
+	p := LockoutPolicy{MaxFailures: 3, Window: 10 * time.Minute, Duration: 15 * time.Minute}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var f loginFailures
+	f = p.record(f, start)
+	f = p.record(f, start.Add(time.Minute))
+	if f.Failures != 2 || f.locked(start.Add(time.Minute)) {
+		t.Fatalf("after 2 failures: %+v", f)
+	}
+
+	// The window has passed since the first failure, so counting starts over.
+	f = p.record(f, start.Add(11*time.Minute))
+	if f.Failures != 1 || !f.FirstFailedAt.Equal(start.Add(11*time.Minute)) {
+		t.Fatalf("failure after the window: %+v", f)
+	}
+
+	f = p.record(f, start.Add(12*time.Minute))
+	now := start.Add(13 * time.Minute)
+	f = p.record(f, now)
+	if !f.locked(now) || !f.LockedUntil.Equal(now.Add(15*time.Minute)) {
+		t.Fatalf("third failure in the window should lock: %+v", f)
+	}
+	if f.locked(f.LockedUntil) {
+		t.Error("lock should run out at LockedUntil")
+	}
+
+	// A failure after the lock ran out starts a new count.
+	f = p.record(f, f.LockedUntil.Add(time.Second))
+	if f.Failures != 1 || !f.LockedUntil.IsZero() {
+		t.Errorf("failure after the lock: %+v", f)
+	}
+}
+
+func TestUnknownLogins(t *testing.T) {
+	p := LockoutPolicy{MaxFailures: 2, Window: time.Minute, Duration: time.Minute}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var u unknownLogins
+	if until := u.fail(p, "ghost@example.com", now); !until.IsZero() {
+		t.Fatalf("first failure locked until %s", until)
+	}
+	until := u.fail(p, "Ghost@Example.com", now)
+	if !until.Equal(now.Add(time.Minute)) {
+		t.Fatalf("second failure locked until %s, want %s", until, now.Add(time.Minute))
+	}
+	if got := u.fail(p, "ghost@example.com", now.Add(30*time.Second)); !got.Equal(until) {
+		t.Errorf("failure while locked = %s, want the same lock %s", got, until)
+	}
+
+	u.fail(p, "other@example.com", now.Add(3*time.Minute))
+	if _, ok := u.entries["ghost@example.com"]; ok {
+		t.Error("stale entries should be pruned")
+	}
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/cliossg/clio/pkg/cl/config"
+)
+
+// PasswordPolicy is what a new password must meet. The zero value accepts any
+// non-empty password.
+type PasswordPolicy struct {
+	MinLength    int
+	RequireMixed bool // three of lowercase, uppercase, digits and symbols
+	RejectCommon bool
+}
+
+// NewPasswordPolicy returns the policy set in the auth.password config.
+func NewPasswordPolicy(cfg config.PasswordConfig) PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:    cfg.MinLength,
+		RequireMixed: cfg.RequireMixed,
+		RejectCommon: cfg.RejectCommon,
+	}
+}
+
+// Hint describes the policy for password forms, empty when it has no rules.
+func (p PasswordPolicy) Hint() string {
+	var rules []string
+	if p.MinLength > 0 {
+		rules = append(rules, fmt.Sprintf("At least %d characters", p.MinLength))
+	}
+	if p.RequireMixed {
+		rules = append(rules, "mix three of lowercase, uppercase, digits and symbols")
+	}
+	if p.RejectCommon {
+		rules = append(rules, "no common passwords")
+	}
+	if len(rules) == 0 {
+		return ""
+	}
+	hint := strings.Join(rules, ", ")
+	return strings.ToUpper(hint[:1]) + hint[1:] + "."
+}
+
+// PasswordError lists the rules a password breaks, as messages fit to show
+// next to the password field.
+type PasswordError struct {
+	Problems []string
+}
+
+func (e *PasswordError) Error() string {
+	return strings.Join(e.Problems, ". ")
+}
+
+//go:embed common-passwords.txt
+var commonPasswordsList string
+
+var commonPasswords = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, line := range strings.Split(commonPasswordsList, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			m[strings.ToLower(line)] = true
+		}
+	}
+	return m
+}()
+
+// ValidatePassword checks pw against the policy and returns a *PasswordError
+// naming every rule it breaks.
+func ValidatePassword(policy PasswordPolicy, pw string) error {
+	var problems []string
+	length := len([]rune(pw))
+	switch {
+	case length == 0:
+		problems = append(problems, "Password is required")
+	case length < policy.MinLength:
+		problems = append(problems, fmt.Sprintf("Password must be at least %d characters", policy.MinLength))
+	}
+	if policy.RequireMixed && length > 0 && characterClasses(pw) < 3 {
+		problems = append(problems, "Password must mix at least three of lowercase letters, uppercase letters, digits and symbols")
+	}
+	if policy.RejectCommon && commonPasswords[strings.ToLower(pw)] {
+		problems = append(problems, "Password is too common")
+	}
+	if len(problems) > 0 {
+		return &PasswordError{Problems: problems}
+	}
+	return nil
+}
+
+func characterClasses(pw string) int {
+	var lower, upper, digit, other int
+	for _, r := range pw {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
+
+const (
+	// generatedPasswordLength is the shortest password GeneratePassword makes.
+	generatedPasswordLength = 16
+	// passwordAlphabet leaves out characters that are easy to misread.
+	passwordAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// GeneratePassword returns a random password that meets the policy, at least
+// 16 characters long, with lowercase and uppercase letters and digits.
+func GeneratePassword(policy PasswordPolicy) string {
+	length := max(generatedPasswordLength, policy.MinLength)
+	for {
+		b := make([]byte, length)
+		for i := range b {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(passwordAlphabet))))
+			if err != nil {
+				panic(fmt.Sprintf("cannot read random bytes: %v", err))
+			}
+			b[i] = passwordAlphabet[n.Int64()]
+		}
+		pw := string(b)
+		if characterClasses(pw) == 3 && ValidatePassword(policy, pw) == nil {
+			return pw
+		}
+	}
+}
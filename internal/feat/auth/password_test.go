@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireMixed: true, RejectCommon: true}
+
+	tests := []struct {
+		name   string
+		policy PasswordPolicy
+		pw     string
+		want   []string
+	}{
+		{"strong password", strict, "Tr0ub4dor&3x", nil},
+		{"empty", strict, "", []string{"Password is required"}},
+		{"too short", strict, "Ab1!", []string{"Password must be at least 10 characters"}},
+		{"length counts characters, not bytes", PasswordPolicy{MinLength: 4}, "ñáéí", nil},
+		{"two classes", strict, "lowercase123", []string{"Password must mix at least three of lowercase letters, uppercase letters, digits and symbols"}},
+		{"symbols count as a class", strict, "lower-case-12", nil},
+		{"common", PasswordPolicy{RejectCommon: true}, "Password123", []string{"Password is too common"}},
+		{"every rule", strict, "admin", []string{
+			"Password must be at least 10 characters",
+			"Password must mix at least three of lowercase letters, uppercase letters, digits and symbols",
+			"Password is too common",
+		}},
+		{"zero policy accepts anything", PasswordPolicy{}, "a", nil},
+		{"zero policy still needs a password", PasswordPolicy{}, "", []string{"Password is required"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.policy, tt.pw)
+			var got []string
+			var pwErr *PasswordError
+			if errors.As(err, &pwErr) {
+				got = pwErr.Problems
+			} else if err != nil {
+				t.Fatalf("ValidatePassword() error = %v, want a *PasswordError", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidatePassword(%q) = %q, want %q", tt.pw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	for _, policy := range []PasswordPolicy{
+		{},
+		{MinLength: 10, RequireMixed: true, RejectCommon: true},
+		{MinLength: 32, RequireMixed: true},
+	} {
+		pw := GeneratePassword(policy)
+		if err := ValidatePassword(policy, pw); err != nil {
+			t.Errorf("GeneratePassword(%+v) = %q, which fails the policy: %v", policy, pw, err)
+		}
+		if len(pw) < max(16, policy.MinLength) {
+			t.Errorf("GeneratePassword(%+v) = %q, too short", policy, pw)
+		}
+	}
+	if GeneratePassword(PasswordPolicy{}) == GeneratePassword(PasswordPolicy{}) {
+		t.Error("GeneratePassword() returned the same password twice")
+	}
+}
+
+func TestPasswordPolicyHint(t *testing.T) {
+	if got := (PasswordPolicy{}).Hint(); got != "" {
+		t.Errorf("Hint() for the zero policy = %q, want empty", got)
+	}
+	want := "At least 10 characters, mix three of lowercase, uppercase, digits and symbols, no common passwords."
+	if got := (PasswordPolicy{MinLength: 10, RequireMixed: true, RejectCommon: true}).Hint(); got != want {
+		t.Errorf("Hint() = %q, want %q", got, want)
+	}
+}
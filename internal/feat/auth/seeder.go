@@ -56,7 +56,7 @@ func (s *Seeder) Start(ctx context.Context) error {
 
 	// Create default admin user
 	email := "admin@local"
-	password := generateRandomPassword()
+	password := GeneratePassword(s.service.PasswordPolicy())
 	name := "admin"
 
 	user, err := s.service.CreateUser(ctx, email, password, name, RoleAdmin, true)
@@ -115,12 +115,5 @@ func (s *Seeder) Depends() []string {
 	return nil
 }
 
-// generateRandomPassword generates a simple random password.
-func generateRandomPassword() string {
-	// For simplicity, use a fixed password for now
-	// In production, use crypto/rand
-	return "admin123"
-}
-
 // ErrUserExists is returned when trying to create a user that already exists.
 var ErrUserExists = errors.New("user already exists")
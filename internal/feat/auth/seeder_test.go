@@ -5,9 +5,12 @@ import (
 	"embed"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cliossg/clio/internal/feat/profile"
+	"github.com/cliossg/clio/internal/testutil"
+	"github.com/cliossg/clio/pkg/cl/config"
 	"github.com/cliossg/clio/pkg/cl/logger"
 	"github.com/google/uuid"
 )
@@ -244,14 +247,36 @@ func TestSeederStartWithProfileError(t *testing.T) {
 	}
 }
 
-func TestGenerateRandomPassword(t *testing.T) {
-	pwd := generateRandomPassword()
-	if pwd == "" {
-		t.Error("generateRandomPassword() returned empty string")
+func TestSeederPasswordMeetsPolicy(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	policy := config.PasswordConfig{MinLength: 20, RequireMixed: true, RejectCommon: true}
+	cfg := &config.Config{Auth: config.AuthConfig{SessionTTL: "1h", Password: policy}}
+	svc := NewService(&testutil.TestDBProvider{DB: db}, cfg, newTestLogger())
+	svc.Start(context.Background())
+
+	credPath := filepath.Join(t.TempDir(), "creds.txt")
+	seeder := NewSeeder(svc, &mockProfileService{}, embed.FS{}, logger.NewNoopLogger())
+	seeder.SetCredentialsPath(credPath)
+	if err := seeder.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	data, err := os.ReadFile(credPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, password, _ := strings.Cut(string(data), "Password: ")
+	password = strings.TrimSpace(password)
+	if err := ValidatePassword(NewPasswordPolicy(policy), password); err != nil {
+		t.Errorf("seeded password %q does not meet the policy: %v", password, err)
 	}
-	// Current implementation returns fixed password
-	if pwd != "admin123" {
-		t.Errorf("generateRandomPassword() = %q, want %q", pwd, "admin123")
+	if _, err := svc.Authenticate(context.Background(), "admin@local", password); err != nil {
+		t.Errorf("cannot sign in with the seeded password: %v", err)
 	}
 }
 
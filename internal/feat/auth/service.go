@@ -39,7 +39,13 @@ type Service interface {
 	CreateSession(ctx context.Context, userID uuid.UUID) (*Session, error)
 	ValidateSession(ctx context.Context, sessionID string) (*middleware.SessionInfo, error)
 	DeleteSession(ctx context.Context, sessionID string) error
+	PurgeExpiredSessions(ctx context.Context, before time.Time, batch int) (int, error)
 	GetSessionTTL() time.Duration
+	PasswordPolicy() PasswordPolicy
+	SetPassword(user *User, password string) error
+	RecordLoginFailure(ctx context.Context, userID uuid.UUID) (time.Time, error)
+	ResetLoginFailures(ctx context.Context, userID uuid.UUID) error
+	LockedUntil(ctx context.Context, userID uuid.UUID) (time.Time, error)
 }
 
 // DBProvider provides access to the database.
@@ -53,6 +59,8 @@ type service struct {
 	cfg        *config.Config
 	log        logger.Logger
 	sessionTTL time.Duration
+	lockout    LockoutPolicy
+	unknown    unknownLogins
 }
 
 // NewService creates a new auth service.
@@ -71,6 +79,12 @@ func (s *service) Start(ctx context.Context) error {
 		s.log.Infof("Invalid session TTL, using default: %v", ttl)
 	}
 	s.sessionTTL = ttl
+	lockout, err := ParseLockout(s.cfg.Auth.Lockout)
+	if err != nil {
+		lockout = LockoutPolicy{MaxFailures: 5, Window: DefaultLockoutPeriod, Duration: DefaultLockoutPeriod}
+		s.log.Infof("Invalid lockout settings, using defaults: %v", err)
+	}
+	s.lockout = lockout
 	s.log.Info("Auth service started")
 	return nil
 }
@@ -91,13 +105,39 @@ func (s *service) Authenticate(ctx context.Context, email, password string) (*Us
 
 	user, err := s.GetUserByEmail(ctx, email)
 	if err != nil {
+		compareDummyHash(password)
+		if s.lockout.Enabled() && email != "" {
+			if until := s.unknown.fail(s.lockout, email, time.Now()); !until.IsZero() {
+				return nil, &LockedError{Until: until}
+			}
+		}
 		return nil, ErrInvalidCredentials
 	}
 
-	if !user.CheckPassword(password) {
+	lockedUntil, err := s.LockedUntil(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	ok := user.CheckPassword(password)
+	if !lockedUntil.IsZero() {
+		return nil, &LockedError{Until: lockedUntil}
+	}
+
+	if !ok {
+		lockedUntil, err := s.RecordLoginFailure(ctx, user.ID)
+		if err != nil {
+			s.log.Errorf("Cannot record failed sign in: %v", err)
+		}
+		if !lockedUntil.IsZero() {
+			return nil, &LockedError{Until: lockedUntil}
+		}
 		return nil, ErrInvalidCredentials
 	}
 
+	if err := s.ResetLoginFailures(ctx, user.ID); err != nil {
+		s.log.Errorf("Cannot reset failed sign ins: %v", err)
+	}
+
 	if !user.IsActive() {
 		return nil, ErrUserNotActive
 	}
@@ -108,6 +148,10 @@ func (s *service) Authenticate(ctx context.Context, email, password string) (*Us
 func (s *service) CreateUser(ctx context.Context, email, password, name, roles string, mustChangePassword bool) (*User, error) {
 	s.ensureQueries()
 
+	if err := ValidatePassword(s.PasswordPolicy(), password); err != nil {
+		return nil, err
+	}
+
 	user, err := NewUser(email, password, name)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create user: %w", err)
@@ -298,10 +342,114 @@ func (s *service) DeleteSession(ctx context.Context, sessionID string) error {
 	return nil
 }
 
+// PurgeExpiredSessions deletes sessions that expired before the given time,
+// at most batch rows per statement so the SQLite write lock is held briefly.
+// It returns the number of sessions deleted.
+func (s *service) PurgeExpiredSessions(ctx context.Context, before time.Time, batch int) (int, error) {
+	s.ensureQueries()
+
+	batch = max(batch, 1)
+	total := 0
+	for {
+		n, err := s.queries.PurgeExpiredSessions(ctx, sqlc.PurgeExpiredSessionsParams{
+			ExpiresAt: before,
+			Limit:     int64(batch),
+		})
+		if err != nil {
+			return total, fmt.Errorf("cannot purge expired sessions: %w", err)
+		}
+		total += int(n)
+		if n < int64(batch) {
+			return total, nil
+		}
+	}
+}
+
 func (s *service) GetSessionTTL() time.Duration {
 	return s.sessionTTL
 }
 
+// PasswordPolicy returns the policy new passwords must meet.
+func (s *service) PasswordPolicy() PasswordPolicy {
+	return NewPasswordPolicy(s.cfg.Auth.Password)
+}
+
+// SetPassword checks password against the policy and sets it on user. The
+// change is saved with UpdateUser.
+func (s *service) SetPassword(user *User, password string) error {
+	if err := ValidatePassword(s.PasswordPolicy(), password); err != nil {
+		return err
+	}
+	if err := user.UpdatePassword(password); err != nil {
+		return fmt.Errorf("cannot hash password: %w", err)
+	}
+	return nil
+}
+
+// RecordLoginFailure counts a failed sign in for the user and returns when the
+// account is locked until, zero if this failure doesn't lock it.
+func (s *service) RecordLoginFailure(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	if !s.lockout.Enabled() {
+		return time.Time{}, nil
+	}
+	s.ensureQueries()
+
+	var current loginFailures
+	row, err := s.queries.GetLoginFailure(ctx, userID.String())
+	switch {
+	case err == nil:
+		current = fromSQLCLoginFailure(row)
+	case !errors.Is(err, sql.ErrNoRows):
+		return time.Time{}, fmt.Errorf("cannot get login failures: %w", err)
+	}
+
+	now := time.Now()
+	next := s.lockout.record(current, now)
+	err = s.queries.UpsertLoginFailure(ctx, sqlc.UpsertLoginFailureParams{
+		UserID:        userID.String(),
+		Failures:      int64(next.Failures),
+		FirstFailedAt: next.FirstFailedAt,
+		LockedUntil:   sql.NullTime{Time: next.LockedUntil, Valid: !next.LockedUntil.IsZero()},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot record login failure: %w", err)
+	}
+	if next.locked(now) {
+		s.log.Infof("User %s locked until %s after %d failed sign ins", userID, next.LockedUntil.Format(time.RFC3339), next.Failures)
+		return next.LockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+// ResetLoginFailures clears the user's failed sign ins, unlocking the account.
+func (s *service) ResetLoginFailures(ctx context.Context, userID uuid.UUID) error {
+	s.ensureQueries()
+
+	if err := s.queries.DeleteLoginFailure(ctx, userID.String()); err != nil {
+		return fmt.Errorf("cannot reset login failures: %w", err)
+	}
+	return nil
+}
+
+// LockedUntil returns when the user's account is locked until, zero if it is
+// not locked.
+func (s *service) LockedUntil(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	s.ensureQueries()
+
+	row, err := s.queries.GetLoginFailure(ctx, userID.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot get login failures: %w", err)
+	}
+	f := fromSQLCLoginFailure(row)
+	if !f.locked(time.Now()) {
+		return time.Time{}, nil
+	}
+	return f.LockedUntil, nil
+}
+
 func (s *service) SetUserProfile(ctx context.Context, userID, profileID uuid.UUID) error {
 	s.ensureQueries()
 
@@ -350,6 +498,14 @@ func fromSQLCUser(u sqlc.User) *User {
 	return user
 }
 
+func fromSQLCLoginFailure(f sqlc.LoginFailure) loginFailures {
+	return loginFailures{
+		Failures:      int(f.Failures),
+		FirstFailedAt: f.FirstFailedAt,
+		LockedUntil:   f.LockedUntil.Time,
+	}
+}
+
 func boolToInt(b bool) int64 {
 	if b {
 		return 1
@@ -1194,3 +1194,153 @@ func TestServiceValidateSessionExpired(t *testing.T) {
 		t.Errorf("Expected ErrSessionNotFound for expired session, got: %v", err)
 	}
 }
+
+func TestServicePurgeExpiredSessions(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, "purge@test.com", "password", "purgeuser", "", false)
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := db.Exec(`INSERT INTO session (id, user_id, expires_at, created_at) VALUES (?, ?, ?, ?)`,
+			uuid.New().String(), user.ID.String(), now.Add(-time.Duration(i+1)*time.Hour), now); err != nil {
+			t.Fatalf("Failed to create expired session: %v", err)
+		}
+	}
+	valid, err := svc.CreateSession(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// A batch smaller than the expired rows takes several statements.
+	removed, err := svc.PurgeExpiredSessions(ctx, now, 2)
+	if err != nil {
+		t.Fatalf("PurgeExpiredSessions failed: %v", err)
+	}
+	if removed != 5 {
+		t.Errorf("removed = %d, want 5", removed)
+	}
+
+	var left int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM session`).Scan(&left); err != nil {
+		t.Fatal(err)
+	}
+	if left != 1 {
+		t.Errorf("sessions left = %d, want 1", left)
+	}
+	if _, err := svc.ValidateSession(ctx, valid.ID); err != nil {
+		t.Errorf("valid session was purged: %v", err)
+	}
+}
+
+func TestServicePasswordPolicy(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{Auth: config.AuthConfig{
+		SessionTTL: "1h",
+		Password:   config.PasswordConfig{MinLength: 10, RequireMixed: true, RejectCommon: true},
+	}}
+	svc := NewService(&testutil.TestDBProvider{DB: db}, cfg, newTestLogger())
+	svc.Start(context.Background())
+	ctx := context.Background()
+
+	var pwErr *PasswordError
+	if _, err := svc.CreateUser(ctx, "weak@test.com", "password123", "weak", "", false); !errors.As(err, &pwErr) {
+		t.Fatalf("CreateUser() with a weak password error = %v, want a *PasswordError", err)
+	}
+
+	user, err := svc.CreateUser(ctx, "strong@test.com", "Correct-Horse-9", "strong", "", false)
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	if err := svc.SetPassword(user, "short"); !errors.As(err, &pwErr) {
+		t.Errorf("SetPassword() with a weak password error = %v, want a *PasswordError", err)
+	}
+	if !user.CheckPassword("Correct-Horse-9") {
+		t.Error("a rejected password should leave the old one in place")
+	}
+	if err := svc.SetPassword(user, "Battery-Staple-7"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	if !user.CheckPassword("Battery-Staple-7") {
+		t.Error("SetPassword() did not set the new password")
+	}
+}
+
+func TestServiceLoginLockout(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{Auth: config.AuthConfig{
+		SessionTTL: "1h",
+		Lockout:    config.LockoutConfig{MaxFailures: 3, Window: "15m", Duration: "15m"},
+	}}
+	svc := NewService(&testutil.TestDBProvider{DB: db}, cfg, newTestLogger())
+	svc.Start(context.Background())
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, "locked@test.com", "right-password", "locked", "", false)
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	// A success resets the count.
+	for i := 0; i < 2; i++ {
+		svc.Authenticate(ctx, "locked@test.com", "wrong")
+	}
+	if _, err := svc.Authenticate(ctx, "locked@test.com", "right-password"); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Authenticate(ctx, "locked@test.com", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("failure %d error = %v, want ErrInvalidCredentials", i+1, err)
+		}
+	}
+	_, err = svc.Authenticate(ctx, "locked@test.com", "wrong")
+	var locked *LockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("third failure error = %v, want a *LockedError", err)
+	}
+	if d := time.Until(locked.Until); d < 14*time.Minute || d > 15*time.Minute {
+		t.Errorf("locked for %s, want 15m", d)
+	}
+	if _, err := svc.Authenticate(ctx, "locked@test.com", "right-password"); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("right password while locked error = %v, want ErrAccountLocked", err)
+	}
+
+	if err := svc.ResetLoginFailures(ctx, user.ID); err != nil {
+		t.Fatalf("ResetLoginFailures() error = %v", err)
+	}
+	if until, err := svc.LockedUntil(ctx, user.ID); err != nil || !until.IsZero() {
+		t.Errorf("LockedUntil() after unlock = %s, %v", until, err)
+	}
+	if _, err := svc.Authenticate(ctx, "locked@test.com", "right-password"); err != nil {
+		t.Errorf("Authenticate() after unlock error = %v", err)
+	}
+
+	// Unknown emails answer the same way, so lockout doesn't tell whether an
+	// account exists.
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Authenticate(ctx, "nobody@test.com", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("unknown email failure %d error = %v, want ErrInvalidCredentials", i+1, err)
+		}
+	}
+	if _, err := svc.Authenticate(ctx, "nobody@test.com", "wrong"); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("unknown email third failure error = %v, want ErrAccountLocked", err)
+	}
+}
@@ -3,7 +3,6 @@ package profile
 import (
 	"context"
 	"embed"
-	"encoding/json"
 	"html/template"
 	"io"
 	"net/http"
@@ -12,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/cliossg/clio/pkg/cl/config"
+	"github.com/cliossg/clio/pkg/cl/imaging"
 	"github.com/cliossg/clio/pkg/cl/logger"
 	"github.com/cliossg/clio/pkg/cl/middleware"
 	"github.com/cliossg/clio/pkg/cl/render"
@@ -91,6 +91,8 @@ type PageData struct {
 	Site             interface{}
 	Profile          *Profile
 	SocialLinksMap   map[string]string
+	SocialErrors     map[string]string
+	SocialPlatforms  []SocialPlatform
 	CurrentUserRoles string
 	CurrentUserName  string
 	Error            string
@@ -131,7 +133,7 @@ func (h *Handler) HandleShowProfile(w http.ResponseWriter, r *http.Request) {
 		Title:            "My Profile",
 		Template:         "profile/show.html",
 		Profile:          profile,
-		SocialLinksMap:   parseSocialLinksJSON(profile.SocialLinks),
+		SocialLinksMap:   SocialLinksMap(profile.SocialLinks),
 		CurrentUserRoles: roles,
 		CurrentUserName:  userName,
 	})
@@ -160,7 +162,7 @@ func (h *Handler) HandleEditProfile(w http.ResponseWriter, r *http.Request) {
 		Title:            "Edit Profile",
 		Template:         "profile/edit.html",
 		Profile:          profile,
-		SocialLinksMap:   parseSocialLinksJSON(profile.SocialLinks),
+		SocialLinksMap:   SocialLinksMap(profile.SocialLinks),
 		CurrentUserRoles: roles,
 		CurrentUserName:  userName,
 	})
@@ -180,9 +182,21 @@ func (h *Handler) HandleCreateProfile(w http.ResponseWriter, r *http.Request) {
 	name := strings.TrimSpace(r.FormValue("name"))
 	surname := strings.TrimSpace(r.FormValue("surname"))
 	bio := strings.TrimSpace(r.FormValue("bio"))
-	socialLinks := buildSocialLinksJSON(r)
+	links, socialErrs := SocialLinksFromForm(r.Form, "social_")
+	if socialErrs != nil {
+		h.renderTemplate(w, "profile/new.html", PageData{
+			Title:            "Create Profile",
+			Template:         "profile/new.html",
+			SocialLinksMap:   SocialFormValues(r.Form, "social_"),
+			SocialErrors:     socialErrs,
+			CurrentUserRoles: middleware.GetUserRoles(ctx),
+			CurrentUserName:  middleware.GetUserName(ctx),
+			Error:            "Some social links are not valid",
+		})
+		return
+	}
 
-	profile, err := h.service.CreateProfile(ctx, uuid.Nil, slug, name, surname, bio, socialLinks, "", userID.String())
+	profile, err := h.service.CreateProfile(ctx, uuid.Nil, slug, name, surname, bio, EncodeSocialLinks(links), "", userID.String())
 	if err != nil {
 		h.log.Errorf("Cannot create profile: %v", err)
 		roles := middleware.GetUserRoles(ctx)
@@ -226,9 +240,24 @@ func (h *Handler) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 	profile.Name = strings.TrimSpace(r.FormValue("name"))
 	profile.Surname = strings.TrimSpace(r.FormValue("surname"))
 	profile.Bio = strings.TrimSpace(r.FormValue("bio"))
-	profile.SocialLinks = buildSocialLinksJSON(r)
 	profile.UpdatedBy = userID.String()
 
+	links, socialErrs := SocialLinksFromForm(r.Form, "social_")
+	if socialErrs != nil {
+		h.renderTemplate(w, "profile/edit.html", PageData{
+			Title:            "Edit Profile",
+			Template:         "profile/edit.html",
+			Profile:          profile,
+			SocialLinksMap:   SocialFormValues(r.Form, "social_"),
+			SocialErrors:     socialErrs,
+			CurrentUserRoles: middleware.GetUserRoles(ctx),
+			CurrentUserName:  middleware.GetUserName(ctx),
+			Error:            "Some social links are not valid",
+		})
+		return
+	}
+	profile.SocialLinks = EncodeSocialLinks(links)
+
 	err = h.service.UpdateProfile(ctx, profile)
 	if err != nil {
 		h.log.Errorf("Cannot update profile: %v", err)
@@ -238,7 +267,7 @@ func (h *Handler) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 			Title:            "Edit Profile",
 			Template:         "profile/edit.html",
 			Profile:          profile,
-			SocialLinksMap:   parseSocialLinksJSON(profile.SocialLinks),
+			SocialLinksMap:   SocialLinksMap(profile.SocialLinks),
 			CurrentUserRoles: roles,
 			CurrentUserName:  userName,
 			Error:            "Cannot update profile",
@@ -278,6 +307,20 @@ func (h *Handler) HandleUploadPhoto(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Cannot read file", http.StatusBadRequest)
+		return
+	}
+
+	// The current photo stays until the new one is processed and saved.
+	photo, err := imaging.Avatar(data, h.cfg.SSG.PhotoSize)
+	if err != nil {
+		h.log.Errorf("Cannot process photo %s: %v", header.Filename, err)
+		http.Error(w, "The file is not a JPEG, PNG or GIF image", http.StatusBadRequest)
+		return
+	}
+
 	usersPhotoPath := filepath.Join(profilesBasePath, "users")
 	if err := os.MkdirAll(usersPhotoPath, 0755); err != nil {
 		h.log.Errorf("Cannot create profiles directory: %v", err)
@@ -285,29 +328,24 @@ func (h *Handler) HandleUploadPhoto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if profile.PhotoPath != "" {
-		oldPath := filepath.Join(profilesBasePath, profile.PhotoPath)
-		os.Remove(oldPath)
-	}
-
-	ext := filepath.Ext(header.Filename)
-	fileName := filepath.Join("users", profile.ID.String()+ext)
+	fileName := filepath.Join("users", profile.ID.String()+".jpg")
 	filePath := filepath.Join(profilesBasePath, fileName)
-
-	dst, err := os.Create(filePath)
-	if err != nil {
-		h.log.Errorf("Cannot create file: %v", err)
+	if err := os.WriteFile(filePath+".tmp", photo, 0644); err != nil {
+		h.log.Errorf("Cannot write file: %v", err)
 		http.Error(w, "Cannot save file", http.StatusInternalServerError)
 		return
 	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
+	if err := os.Rename(filePath+".tmp", filePath); err != nil {
+		os.Remove(filePath + ".tmp")
 		h.log.Errorf("Cannot write file: %v", err)
 		http.Error(w, "Cannot save file", http.StatusInternalServerError)
 		return
 	}
 
+	if profile.PhotoPath != "" && profile.PhotoPath != fileName {
+		os.Remove(filepath.Join(profilesBasePath, profile.PhotoPath))
+	}
+
 	userID, _ := h.getCurrentUserID(ctx)
 	profile.PhotoPath = fileName
 	profile.UpdatedBy = userID.String()
@@ -380,6 +418,7 @@ func (h *Handler) HandleServePhoto(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) renderTemplate(w http.ResponseWriter, templateName string, data PageData) {
+	data.SocialPlatforms = SocialPlatforms
 	funcMap := render.MergeFuncMaps(render.FuncMap(), template.FuncMap{
 		"hasRole": func(roles, role string) bool {
 			for _, r := range splitRoles(roles) {
@@ -421,54 +460,6 @@ func splitRoles(roles string) []string {
 	return result
 }
 
-// socialPlatforms defines all supported social platforms
-var socialPlatforms = []string{
-	"facebook", "youtube", "instagram", "x", "tiktok", "linkedin", "github",
-	"whatsapp", "telegram", "reddit", "messenger", "snapchat", "pinterest",
-	"tumblr", "discord", "twitch", "signal", "viber", "line", "kakaotalk",
-	"wechat", "qq", "douyin", "kuaishou", "weibo", "xiaohongshu", "bilibili",
-	"zhihu", "vk", "odnoklassniki", "mastodon", "bluesky", "threads", "flickr",
-	"vimeo", "dailymotion", "quora",
-}
-
-type socialLink struct {
-	Platform string `json:"platform"`
-	URL      string `json:"url"`
-}
-
-func parseSocialLinksJSON(jsonStr string) map[string]string {
-	result := make(map[string]string)
-	if jsonStr == "" || jsonStr == "[]" {
-		return result
-	}
-	var links []socialLink
-	if err := json.Unmarshal([]byte(jsonStr), &links); err != nil {
-		return result
-	}
-	for _, link := range links {
-		result[link.Platform] = link.URL
-	}
-	return result
-}
-
-func buildSocialLinksJSON(r *http.Request) string {
-	var links []socialLink
-	for _, platform := range socialPlatforms {
-		url := strings.TrimSpace(r.FormValue("social_" + platform))
-		if url != "" {
-			links = append(links, socialLink{Platform: platform, URL: url})
-		}
-	}
-	if len(links) == 0 {
-		return "[]"
-	}
-	data, err := json.Marshal(links)
-	if err != nil {
-		return "[]"
-	}
-	return string(data)
-}
-
 func normalizeSlug(s string) string {
 	s = strings.ToLower(s)
 	var result strings.Builder
@@ -0,0 +1,194 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SocialPlatform is a network a profile can link to. The profile forms, URL
+// validation and the icons on generated author pages all come from
+// SocialPlatforms.
+type SocialPlatform struct {
+	ID          string   // form field suffix and stored platform, e.g. "github"
+	Name        string   // display name
+	Hosts       []string // accepted hosts, subdomains included; none accepts any host
+	Placeholder string
+	Icon        string // icon shown on author pages
+	Common      bool   // listed before the "More platforms" group
+}
+
+// SocialPlatforms lists the supported platforms in form order.
+var SocialPlatforms = []SocialPlatform{
+	{ID: "facebook", Name: "Facebook", Hosts: []string{"facebook.com", "fb.com"}, Placeholder: "https://facebook.com/...", Icon: "facebook", Common: true},
+	{ID: "youtube", Name: "YouTube", Hosts: []string{"youtube.com", "youtu.be"}, Placeholder: "https://youtube.com/...", Icon: "youtube", Common: true},
+	{ID: "instagram", Name: "Instagram", Hosts: []string{"instagram.com"}, Placeholder: "https://instagram.com/...", Icon: "instagram", Common: true},
+	{ID: "x", Name: "X", Hosts: []string{"x.com", "twitter.com"}, Placeholder: "https://x.com/...", Icon: "x", Common: true},
+	{ID: "tiktok", Name: "TikTok", Hosts: []string{"tiktok.com"}, Placeholder: "https://tiktok.com/...", Icon: "video", Common: true},
+	{ID: "linkedin", Name: "LinkedIn", Hosts: []string{"linkedin.com"}, Placeholder: "https://linkedin.com/in/...", Icon: "linkedin", Common: true},
+	{ID: "github", Name: "GitHub", Hosts: []string{"github.com"}, Placeholder: "https://github.com/...", Icon: "github", Common: true},
+	{ID: "whatsapp", Name: "WhatsApp", Hosts: []string{"wa.me", "whatsapp.com"}, Placeholder: "https://wa.me/...", Icon: "chat", Common: true},
+	{ID: "telegram", Name: "Telegram", Hosts: []string{"t.me", "telegram.me"}, Placeholder: "https://t.me/...", Icon: "send", Common: true},
+	{ID: "reddit", Name: "Reddit", Hosts: []string{"reddit.com"}, Placeholder: "https://reddit.com/u/...", Icon: "chat", Common: true},
+	{ID: "messenger", Name: "Messenger", Hosts: []string{"m.me", "messenger.com"}, Icon: "chat"},
+	{ID: "snapchat", Name: "Snapchat", Hosts: []string{"snapchat.com"}, Icon: "camera"},
+	{ID: "pinterest", Name: "Pinterest", Hosts: []string{"pinterest.com"}, Icon: "camera"},
+	{ID: "tumblr", Name: "Tumblr", Hosts: []string{"tumblr.com"}, Icon: "globe"},
+	{ID: "discord", Name: "Discord", Hosts: []string{"discord.gg", "discord.com"}, Icon: "chat"},
+	{ID: "twitch", Name: "Twitch", Hosts: []string{"twitch.tv"}, Icon: "twitch"},
+	{ID: "signal", Name: "Signal", Hosts: []string{"signal.me", "signal.group"}, Icon: "chat"},
+	{ID: "viber", Name: "Viber", Hosts: []string{"viber.com"}, Icon: "chat"},
+	{ID: "line", Name: "LINE", Hosts: []string{"line.me"}, Icon: "chat"},
+	{ID: "kakaotalk", Name: "KakaoTalk", Hosts: []string{"kakao.com"}, Icon: "chat"},
+	{ID: "wechat", Name: "WeChat", Hosts: []string{"wechat.com", "weixin.qq.com"}, Icon: "chat"},
+	{ID: "qq", Name: "QQ", Hosts: []string{"qq.com"}, Icon: "chat"},
+	{ID: "douyin", Name: "Douyin", Hosts: []string{"douyin.com"}, Icon: "video"},
+	{ID: "kuaishou", Name: "Kuaishou", Hosts: []string{"kuaishou.com"}, Icon: "video"},
+	{ID: "weibo", Name: "Weibo", Hosts: []string{"weibo.com", "weibo.cn"}, Icon: "globe"},
+	{ID: "xiaohongshu", Name: "Xiaohongshu", Hosts: []string{"xiaohongshu.com", "xhslink.com"}, Icon: "camera"},
+	{ID: "bilibili", Name: "Bilibili", Hosts: []string{"bilibili.com", "b23.tv"}, Icon: "video"},
+	{ID: "zhihu", Name: "Zhihu", Hosts: []string{"zhihu.com"}, Icon: "chat"},
+	{ID: "vk", Name: "VK", Hosts: []string{"vk.com"}, Icon: "globe"},
+	{ID: "odnoklassniki", Name: "Odnoklassniki", Hosts: []string{"ok.ru"}, Icon: "globe"},
+	{ID: "mastodon", Name: "Mastodon", Placeholder: "https://mastodon.social/@...", Icon: "chat"},
+	{ID: "bluesky", Name: "Bluesky", Hosts: []string{"bsky.app"}, Placeholder: "https://bsky.app/profile/...", Icon: "globe"},
+	{ID: "threads", Name: "Threads", Hosts: []string{"threads.net", "threads.com"}, Icon: "globe"},
+	{ID: "flickr", Name: "Flickr", Hosts: []string{"flickr.com"}, Icon: "camera"},
+	{ID: "vimeo", Name: "Vimeo", Hosts: []string{"vimeo.com"}, Icon: "video"},
+	{ID: "dailymotion", Name: "Dailymotion", Hosts: []string{"dailymotion.com"}, Icon: "video"},
+	{ID: "quora", Name: "Quora", Hosts: []string{"quora.com"}, Icon: "chat"},
+}
+
+// FindSocialPlatform returns the platform with the given ID.
+func FindSocialPlatform(id string) (SocialPlatform, bool) {
+	id = strings.ToLower(id)
+	for _, p := range SocialPlatforms {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return SocialPlatform{}, false
+}
+
+// SocialLink is a profile link as stored in the social_links JSON column.
+type SocialLink struct {
+	Platform string `json:"platform"`
+	Handle   string `json:"handle,omitempty"`
+	URL      string `json:"url"`
+}
+
+// NormalizeSocialLink checks that raw is an http(s) URL on one of the
+// platform's hosts and returns it with its handle. A missing scheme is taken
+// as https.
+func NormalizeSocialLink(p SocialPlatform, raw string) (SocialLink, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return SocialLink{}, fmt.Errorf("not a valid URL")
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if !p.acceptsHost(host) {
+		if len(p.Hosts) == 0 {
+			return SocialLink{}, fmt.Errorf("not a valid URL")
+		}
+		return SocialLink{}, fmt.Errorf("not a %s URL, expected %s", p.Name, strings.Join(p.Hosts, " or "))
+	}
+	u.Host = strings.ToLower(u.Host)
+	return SocialLink{Platform: p.ID, Handle: socialHandle(p, host, u.Path), URL: u.String()}, nil
+}
+
+func (p SocialPlatform) acceptsHost(host string) bool {
+	if len(p.Hosts) == 0 {
+		return strings.Contains(host, ".")
+	}
+	for _, h := range p.Hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// socialHandle takes the handle from the last path segment, e.g. "jdoe" for
+// linkedin.com/in/jdoe/. Mastodon handles keep their server: "jdoe@host".
+func socialHandle(p SocialPlatform, host, path string) string {
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+	if len(segments) == 0 {
+		return ""
+	}
+	handle := strings.TrimPrefix(segments[len(segments)-1], "@")
+	if handle == "" {
+		return ""
+	}
+	if p.ID == "mastodon" {
+		return handle + "@" + host
+	}
+	return handle
+}
+
+// SocialLinksFromForm reads the prefix+platform form fields. Invalid URLs are
+// left out of the links and reported by platform ID.
+func SocialLinksFromForm(form url.Values, prefix string) ([]SocialLink, map[string]string) {
+	var links []SocialLink
+	var errs map[string]string
+	for _, p := range SocialPlatforms {
+		raw := strings.TrimSpace(form.Get(prefix + p.ID))
+		if raw == "" {
+			continue
+		}
+		link, err := NormalizeSocialLink(p, raw)
+		if err != nil {
+			if errs == nil {
+				errs = make(map[string]string)
+			}
+			errs[p.ID] = p.Name + ": " + err.Error()
+			continue
+		}
+		links = append(links, link)
+	}
+	return links, errs
+}
+
+// SocialFormValues returns the submitted prefix+platform values by platform
+// ID, to show a rejected form again as it was typed.
+func SocialFormValues(form url.Values, prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, p := range SocialPlatforms {
+		if v := strings.TrimSpace(form.Get(prefix + p.ID)); v != "" {
+			values[p.ID] = v
+		}
+	}
+	return values
+}
+
+// EncodeSocialLinks returns the JSON stored for links, "[]" for none.
+func EncodeSocialLinks(links []SocialLink) string {
+	if len(links) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(links)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+// SocialLinksMap decodes stored links into URLs by platform ID.
+func SocialLinksMap(jsonStr string) map[string]string {
+	result := make(map[string]string)
+	if jsonStr == "" || jsonStr == "[]" {
+		return result
+	}
+	var links []SocialLink
+	if err := json.Unmarshal([]byte(jsonStr), &links); err != nil {
+		return result
+	}
+	for _, link := range links {
+		result[strings.ToLower(link.Platform)] = link.URL
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package profile
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSocialLink(t *testing.T) {
+	tests := []struct {
+		platform   string
+		raw        string
+		wantURL    string
+		wantHandle string
+		wantErr    string
+	}{
+		{"github", "https://github.com/jdoe", "https://github.com/jdoe", "jdoe", ""},
+		{"github", "github.com/jdoe/", "https://github.com/jdoe/", "jdoe", ""},
+		{"github", "https://WWW.GitHub.com/jdoe", "https://www.github.com/jdoe", "jdoe", ""},
+		{"github", "https://gitlab.com/jdoe", "", "", "not a GitHub URL, expected github.com"},
+		{"github", "https://github.com.evil.example/jdoe", "", "", "not a GitHub URL"},
+		{"github", "https://notgithub.com/jdoe", "", "", "not a GitHub URL"},
+		{"x", "https://twitter.com/jdoe", "https://twitter.com/jdoe", "jdoe", ""},
+		{"youtube", "https://www.youtube.com/@jdoe", "https://www.youtube.com/@jdoe", "jdoe", ""},
+		{"linkedin", "https://es.linkedin.com/in/jdoe/", "https://es.linkedin.com/in/jdoe/", "jdoe", ""},
+		{"telegram", "t.me/jdoe", "https://t.me/jdoe", "jdoe", ""},
+		{"mastodon", "https://fosstodon.org/@jdoe", "https://fosstodon.org/@jdoe", "jdoe@fosstodon.org", ""},
+		{"mastodon", "https://localhost/@jdoe", "", "", "not a valid URL"},
+		{"facebook", "https://facebook.com", "https://facebook.com", "", ""},
+		{"facebook", "javascript:alert(1)", "", "", "not a valid URL"},
+		{"facebook", "ftp://facebook.com/jdoe", "", "", "not a valid URL"},
+		{"facebook", "https://user@facebook.com/jdoe", "", "", "not a valid URL"},
+		{"facebook", "https://", "", "", "not a valid URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform+" "+tt.raw, func(t *testing.T) {
+			p, ok := FindSocialPlatform(tt.platform)
+			if !ok {
+				t.Fatalf("unknown platform %q", tt.platform)
+			}
+			link, err := NormalizeSocialLink(p, tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("NormalizeSocialLink() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeSocialLink() error = %v", err)
+			}
+			if link.Platform != tt.platform || link.URL != tt.wantURL || link.Handle != tt.wantHandle {
+				t.Errorf("NormalizeSocialLink() = %+v, want URL %q, handle %q", link, tt.wantURL, tt.wantHandle)
+			}
+		})
+	}
+}
+
+func TestSocialLinksFromForm(t *testing.T) {
+	form := url.Values{
+		"social_github":   {" github.com/jdoe "},
+		"social_x":        {"https://facebook.com/jdoe"},
+		"social_linkedin": {""},
+		"social_unknown":  {"https://example.com"},
+	}
+
+	links, errs := SocialLinksFromForm(form, "social_")
+	if len(links) != 1 || links[0].URL != "https://github.com/jdoe" || links[0].Handle != "jdoe" {
+		t.Errorf("links = %+v", links)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs["x"], "X: ") {
+		t.Errorf("errs = %v", errs)
+	}
+	if values := SocialFormValues(form, "social_"); values["x"] != "https://facebook.com/jdoe" || len(values) != 2 {
+		t.Errorf("SocialFormValues() = %v", values)
+	}
+
+	stored := EncodeSocialLinks(links)
+	if stored != `[{"platform":"github","handle":"jdoe","url":"https://github.com/jdoe"}]` {
+		t.Errorf("EncodeSocialLinks() = %s", stored)
+	}
+	if m := SocialLinksMap(stored); m["github"] != "https://github.com/jdoe" {
+		t.Errorf("SocialLinksMap() = %v", m)
+	}
+	if EncodeSocialLinks(nil) != "[]" || len(SocialLinksMap("[]")) != 0 {
+		t.Error("no links should round trip as []")
+	}
+}
+
+func TestSocialPlatformsAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, p := range SocialPlatforms {
+		if seen[p.ID] || p.Name == "" || p.Icon == "" {
+			t.Errorf("platform %+v is duplicated or incomplete", p)
+		}
+		seen[p.ID] = true
+	}
+}
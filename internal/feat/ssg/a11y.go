@@ -0,0 +1,248 @@
+package ssg
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Setting ref keys for the accessibility lint.
+const (
+	// A11yLintRefKey lints the generated pages after every generation.
+	A11yLintRefKey = "ssg.a11y.lint"
+	// A11yBlockPublishRefKey refuses to publish while the lint finds errors.
+	// Warnings never block.
+	A11yBlockPublishRefKey = "ssg.a11y.block_publish"
+)
+
+// Accessibility finding severities.
+const (
+	A11yError   = "error"
+	A11yWarning = "warning"
+)
+
+// A11yFinding is a problem found on a generated page.
+type A11yFinding struct {
+	Page     string `json:"page"` // path inside the output directory, e.g. blog/post/index.html
+	Line     int    `json:"line"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// A11yReport holds the findings for a site's generated pages, sorted by page
+// and line.
+type A11yReport struct {
+	Pages    int           `json:"pages"`
+	Findings []A11yFinding `json:"findings"`
+}
+
+// Errors returns the number of error findings.
+func (r *A11yReport) Errors() int {
+	return r.count(A11yError)
+}
+
+// Warnings returns the number of warning findings.
+func (r *A11yReport) Warnings() int {
+	return r.count(A11yWarning)
+}
+
+func (r *A11yReport) count(severity string) int {
+	if r == nil {
+		return 0
+	}
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// lintAccessibility checks every generated page under htmlPath. A missing
+// output directory gives an empty report.
+func lintAccessibility(htmlPath string) (*A11yReport, error) {
+	report := &A11yReport{}
+	err := filepath.WalkDir(htmlPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == htmlPath && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if filepath.Dir(path) == htmlPath && (d.Name() == "images" || d.Name() == "profiles") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".html" {
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(htmlPath, path)
+		if err != nil {
+			return err
+		}
+		report.Pages++
+		for _, f := range lintPage(src) {
+			f.Page = filepath.ToSlash(rel)
+			report.Findings = append(report.Findings, f)
+		}
+		return nil
+	})
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Page != b.Page {
+			return a.Page < b.Page
+		}
+		return a.Line < b.Line
+	})
+	return report, err
+}
+
+var (
+	altAttrRe       = attrRe("alt")
+	ariaLabelAttrRe = attrRe("aria-label")
+	titleAttrRe     = attrRe("title")
+	hrefAttrRe      = attrRe("href")
+)
+
+func attrRe(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\s` + regexp.QuoteMeta(name) + `(\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+))?(\s|/|>)`)
+}
+
+// attrValue returns the unquoted value of the attribute matched by re, and
+// whether the tag has it at all.
+func attrValue(tag []byte, re *regexp.Regexp) (string, bool) {
+	m := re.FindSubmatch(tag)
+	if m == nil {
+		return "", false
+	}
+	return html.UnescapeString(strings.Trim(string(m[2]), `"'`)), true
+}
+
+// lintPage runs the accessibility rules over one page. It is a heuristic
+// scan of the tags, not a full HTML parser: images without alt text, a
+// missing or empty title, links without text and skipped heading levels.
+func lintPage(src []byte) []A11yFinding {
+	var findings []A11yFinding
+	line, pos := 1, 0
+	lineAt := func(i int) int {
+		line += bytes.Count(src[pos:i], []byte("\n"))
+		pos = i
+		return line
+	}
+	add := func(line int, rule, severity, msg string) {
+		findings = append(findings, A11yFinding{Line: line, Rule: rule, Severity: severity, Message: msg})
+	}
+
+	var (
+		title      strings.Builder
+		inTitle    bool
+		hasTitle   bool
+		link       *strings.Builder
+		linkLine   int
+		lastLevel  int
+		textTarget = func(s string) {
+			if inTitle {
+				title.WriteString(s)
+			}
+			if link != nil {
+				link.WriteString(s)
+			}
+		}
+	)
+
+	for i := 0; i < len(src); {
+		if src[i] == '<' && bytes.HasPrefix(src[i:], []byte("<!--")) {
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+		if src[i] != '<' || i+1 >= len(src) || !isTagStart(src[i+1]) {
+			next := bytes.IndexByte(src[i+1:], '<')
+			end := len(src)
+			if next >= 0 {
+				end = i + 1 + next
+			}
+			textTarget(html.UnescapeString(string(src[i:end])))
+			i = end
+			continue
+		}
+
+		start := i
+		i = tagEnd(src, i)
+		tag := src[start:i]
+		name := tagName(tag)
+		closing := tag[1] == '/'
+
+		switch {
+		case name == "script" || name == "style":
+			if !closing {
+				if end := indexFold(src[i:], "</"+name); end >= 0 {
+					i += end
+				}
+			}
+
+		// Only the first title is the page's; later ones belong to SVG images.
+		case name == "title" && !closing && !hasTitle:
+			inTitle, hasTitle = true, true
+		case name == "title" && closing && inTitle:
+			inTitle = false
+			if strings.TrimSpace(title.String()) == "" {
+				add(lineAt(start), "page-title", A11yError, "Page title is empty")
+			}
+
+		case name == "img" && !closing:
+			alt, ok := attrValue(tag, altAttrRe)
+			if !ok {
+				add(lineAt(start), "img-alt", A11yError, "Image has no alt attribute; use alt=\"\" for decorative images")
+			}
+			if link != nil && strings.TrimSpace(alt) != "" {
+				link.WriteString(alt)
+			}
+
+		case name == "a" && !closing:
+			if _, ok := attrValue(tag, hrefAttrRe); ok {
+				label, _ := attrValue(tag, ariaLabelAttrRe)
+				if label == "" {
+					label, _ = attrValue(tag, titleAttrRe)
+				}
+				link, linkLine = &strings.Builder{}, lineAt(start)
+				link.WriteString(label)
+			}
+		case name == "a" && closing:
+			if link != nil && strings.TrimSpace(link.String()) == "" {
+				add(linkLine, "link-text", A11yError, "Link has no text or accessible label")
+			}
+			link = nil
+
+		case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' && !closing:
+			level := int(name[1] - '0')
+			if lastLevel > 0 && level > lastLevel+1 {
+				add(lineAt(start), "heading-order", A11yWarning, fmt.Sprintf("Heading level skipped: h%d followed by %s", lastLevel, name))
+			}
+			lastLevel = level
+		}
+	}
+
+	if !hasTitle {
+		add(1, "page-title", A11yError, "Page has no title")
+	}
+	return findings
+}
@@ -0,0 +1,112 @@
+package ssg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLintPage(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html>
+<head><title>Post - Site</title></head>
+<body>
+<h1>Post</h1>
+<h3>Skipped</h3>
+<img src="/a.png">
+<img src="/divider.png" alt="">
+<a href="/x"><img src="/logo.png" alt="Home"></a>
+<a href="/y" aria-label="Next"></a>
+<a href="/z"> </a>
+<a name="anchor"></a>
+<svg><title></title></svg>
+<script>var s = "<a href='/w'></a>";</script>
+<h2>Back up</h2>
+</body>
+</html>`
+
+	var got []string
+	for _, f := range lintPage([]byte(page)) {
+		got = append(got, fmt.Sprintf("%s:%s:%d", f.Rule, f.Severity, f.Line))
+	}
+	want := []string{"heading-order:warning:6", "img-alt:error:7", "link-text:error:11"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+
+	findings := lintPage([]byte("<html><head><title> </title></head><body></body></html>"))
+	if len(findings) != 1 || findings[0].Rule != "page-title" {
+		t.Errorf("empty title findings = %+v", findings)
+	}
+	findings = lintPage([]byte("<p>No head</p>"))
+	if len(findings) != 1 || findings[0].Rule != "page-title" || findings[0].Message != "Page has no title" {
+		t.Errorf("missing title findings = %+v", findings)
+	}
+}
+
+func TestLintAccessibility(t *testing.T) {
+	if report, err := lintAccessibility(filepath.Join(t.TempDir(), "missing")); err != nil || report.Pages != 0 {
+		t.Errorf("missing output = %+v, %v", report, err)
+	}
+
+	htmlPath := t.TempDir()
+	files := map[string]string{
+		"index.html":            "<title>Home</title><img src=x>",
+		"blog/post/index.html":  "<title>Post</title><h1>a</h1><h4>b</h4>",
+		"images/upload.html":    "<p>not a page</p>",
+		"static/css/theme.css":  "body{}",
+		"authors/jo/index.html": "<title>Jo</title>",
+	}
+	for name, data := range files {
+		path := filepath.Join(htmlPath, filepath.FromSlash(name))
+		if err := EnsureDir(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := lintAccessibility(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Pages != 3 || report.Errors() != 1 || report.Warnings() != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.Findings[0].Page != "blog/post/index.html" || report.Findings[1].Page != "index.html" {
+		t.Errorf("findings should be sorted by page: %+v", report.Findings)
+	}
+
+	var none *A11yReport
+	if none.Errors() != 0 {
+		t.Error("a nil report has no errors")
+	}
+}
+
+func TestDefaultTemplatesPassA11yLint(t *testing.T) {
+	tmpl := parseSiteTemplatesFromDisk(t)
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "Lint", Slug: "lint"}
+	section := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	published := time.Now().Add(-time.Hour)
+	content := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, SectionPath: "blog", Heading: "Post", Body: "## Intro\n\nText with ![a chart](/images/c.png).", PublishedAt: &published}
+
+	htmlPath := g.workspace.GetHTMLPath(site.Slug)
+	if _, err := g.renderContentPage(tmpl, nil, nil, htmlPath, site, content, nil, adjacentLinks{}, []*Section{section}, nil, map[string]string{}, nil, BlocksConfig{}); err != nil {
+		t.Fatalf("renderContentPage() error = %v", err)
+	}
+
+	report, err := lintAccessibility(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Pages != 1 || report.Errors() != 0 {
+		t.Errorf("built-in layout has accessibility errors: %+v", report.Findings)
+	}
+}
@@ -0,0 +1,153 @@
+package ssg
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ArchiveRefKey turns on the date archive: archive/ lists every year and
+// month with published content, and archive/2024/ and archive/2024/03/ list
+// the content of each.
+const ArchiveRefKey = "ssg.archive"
+
+// archiveDir is the output directory of the archive pages.
+const archiveDir = "archive"
+
+// ArchivePeriod is a year or a month of the archive.
+type ArchivePeriod struct {
+	Year   int
+	Month  time.Month // zero for a whole year
+	Title  string     // 2024, or March 2024
+	URL    string
+	Count  int              // listed content published in the period
+	Months []*ArchivePeriod // of a year, newest first
+
+	contents []*Content
+}
+
+// path returns the period's listing path, archive/2024 or archive/2024/03.
+func (p *ArchivePeriod) path() string {
+	if p.Month == 0 {
+		return fmt.Sprintf("%s/%d", archiveDir, p.Year)
+	}
+	return fmt.Sprintf("%s/%d/%02d", archiveDir, p.Year, int(p.Month))
+}
+
+// buildArchive groups the content shown in listings by the year and month of
+// its publication date in loc, newest first at every level. Content without
+// a publication date is left out.
+func buildArchive(contents []*Content, loc *time.Location) []*ArchivePeriod {
+	var archived []*Content
+	for _, c := range contents {
+		if c.PublishedAt != nil && inListings(c) {
+			archived = append(archived, c)
+		}
+	}
+	sort.SliceStable(archived, func(i, j int) bool {
+		return archived[i].PublishedAt.After(*archived[j].PublishedAt)
+	})
+
+	var years []*ArchivePeriod
+	var year, month *ArchivePeriod
+	for _, c := range archived {
+		published := c.PublishedAt.In(loc)
+		if year == nil || year.Year != published.Year() {
+			year = &ArchivePeriod{Year: published.Year(), Title: fmt.Sprint(published.Year())}
+			years = append(years, year)
+			month = nil
+		}
+		if month == nil || month.Month != published.Month() {
+			month = &ArchivePeriod{Year: published.Year(), Month: published.Month(), Title: published.Format("January 2006")}
+			year.Months = append(year.Months, month)
+		}
+		year.contents = append(year.contents, c)
+		year.Count++
+		month.contents = append(month.contents, c)
+		month.Count++
+	}
+	return years
+}
+
+// renderArchivePages renders the archive index and a paginated listing for
+// every year and month, when ssg.archive is on.
+// It returns the number of periods rendered and the number of extra pages generated.
+func (g *HTMLGenerator) renderArchivePages(embeddedTmpl *template.Template, siteDefaultLayout *Layout, build *buildState, htmlPath string, site *Site, contents []*Content, menu []*Section, params map[string]string) (int, int, error) {
+	if params[ArchiveRefKey] != "true" {
+		return 0, 0, nil
+	}
+	pageSize := g.getPageSize(params)
+	basePath := g.getAssetPath(params)
+	tmpl := g.getSiteDefaultTemplate(embeddedTmpl, siteDefaultLayout)
+
+	years := buildArchive(contents, siteLocation(params))
+	var periods []*ArchivePeriod
+	for _, year := range years {
+		periods = append(periods, year)
+		periods = append(periods, year.Months...)
+	}
+	for _, p := range periods {
+		p.URL = g.getPaginationURL(params, basePath, p.path(), 1)
+	}
+
+	count := 0
+	paged := 0
+	for _, p := range periods {
+		data := SSGPageData{
+			Site:          site,
+			Menu:          menu,
+			IsIndex:       true,
+			IsArchive:     true,
+			ArchivePeriod: p,
+		}
+		pages, err := g.renderListPages(tmpl, siteDefaultLayout, build, site, p.path(), p.contents, params, pageSize, data)
+		if err != nil {
+			return count, paged, err
+		}
+		count++
+		paged += pages - 1
+	}
+
+	if err := g.renderArchiveIndex(tmpl, siteDefaultLayout, build, htmlPath, site, years, menu, params); err != nil {
+		return count, paged, err
+	}
+	return count, paged, nil
+}
+
+// renderArchiveIndex writes archive/index.html listing every year and month
+// with its count.
+func (g *HTMLGenerator) renderArchiveIndex(tmpl *template.Template, layout *Layout, build *buildState, htmlPath string, site *Site, years []*ArchivePeriod, menu []*Section, params map[string]string) error {
+	basePath := g.getAssetPath(params)
+	data := SSGPageData{
+		Site:         site,
+		Menu:         menu,
+		IsIndex:      true,
+		IsArchive:    true,
+		Archive:      years,
+		CanonicalURL: g.getAbsoluteURL(params, g.getPaginationURL(params, basePath, archiveDir, 1)),
+		AssetPath:    basePath,
+		Params:       params,
+		Timezone:     siteLocation(params).String(),
+		DateFormat:   params[DateFormatRefKey],
+	}
+	g.setPageAssets(&data, layout, params)
+
+	outputPath := pageFile(params, htmlPath, filepath.Join(htmlPath, archiveDir, "index.html"))
+	if err := EnsureDir(outputPath); err != nil {
+		return err
+	}
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := g.writePage(f, tmpl, data); err != nil {
+		return err
+	}
+	build.record(outputPath, "", time.Time{})
+	return nil
+}
@@ -0,0 +1,137 @@
+package ssg
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func archiveContent(heading string, publishedAt *time.Time) *Content {
+	return &Content{ID: uuid.New(), ShortID: uuid.NewString()[:8], Heading: heading, PublishedAt: publishedAt}
+}
+
+func TestBuildArchive(t *testing.T) {
+	at := func(value string) *time.Time {
+		d, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &d
+	}
+	draft := archiveContent("Draft", at("2024-03-05T10:00:00Z"))
+	draft.Draft = true
+	unlisted := archiveContent("Unlisted", at("2024-03-06T10:00:00Z"))
+	unlisted.Visibility = VisibilityUnlisted
+	future := time.Now().Add(48 * time.Hour)
+
+	contents := []*Content{
+		archiveContent("March 2024", at("2024-03-10T10:00:00Z")),
+		archiveContent("New Year in Madrid", at("2023-12-31T23:30:00Z")), // 2024-01-01 in Madrid
+		archiveContent("December 2023", at("2023-12-02T10:00:00Z")),
+		archiveContent("Late March 2024", at("2024-03-28T10:00:00Z")),
+		archiveContent("Undated", nil),
+		archiveContent("Scheduled", &future),
+		draft,
+		unlisted,
+	}
+
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("no timezone data: %v", err)
+	}
+	years := buildArchive(contents, madrid)
+
+	type bucket struct {
+		title    string
+		headings string
+	}
+	var got []bucket
+	for _, year := range years {
+		got = append(got, bucket{year.Title, archiveHeadings(year)})
+		for _, month := range year.Months {
+			got = append(got, bucket{month.Title, archiveHeadings(month)})
+		}
+	}
+	want := []bucket{
+		{"2024", "Late March 2024,March 2024,New Year in Madrid"},
+		{"March 2024", "Late March 2024,March 2024"},
+		{"January 2024", "New Year in Madrid"},
+		{"2023", "December 2023"},
+		{"December 2023", "December 2023"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("buildArchive() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("period %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if years[0].Count != 3 || years[0].Months[0].Count != 2 {
+		t.Errorf("counts = %d and %d, want 3 and 2", years[0].Count, years[0].Months[0].Count)
+	}
+	if p := years[0].Months[1].path(); p != "archive/2024/01" {
+		t.Errorf("path() = %q, want archive/2024/01", p)
+	}
+}
+
+func archiveHeadings(p *ArchivePeriod) string {
+	var headings []string
+	for _, c := range p.contents {
+		headings = append(headings, c.Heading)
+	}
+	return strings.Join(headings, ",")
+}
+
+func TestRenderArchivePages(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	params := map[string]string{ArchiveRefKey: "true"}
+	tmpl, err := g.parseTemplates(g.siteTheme(site.Slug, params))
+	if err != nil {
+		t.Fatalf("parseTemplates() error = %v", err)
+	}
+
+	march := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2024, time.April, 2, 12, 0, 0, 0, time.UTC)
+	contents := []*Content{archiveContent("Spring post", &march), archiveContent("April post", &april)}
+	htmlPath := g.workspace.GetHTMLPath(site.Slug)
+	build := newBuildState(htmlPath, nil, "", true)
+
+	count, _, err := g.renderArchivePages(tmpl, nil, build, htmlPath, site, contents, nil, params)
+	if err != nil {
+		t.Fatalf("renderArchivePages() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("renderArchivePages() = %d periods, want 3", count)
+	}
+
+	read := func(listPath string) string {
+		t.Helper()
+		data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, listPath, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	index := read("archive")
+	for _, want := range []string{`href="/archive/2024/"`, `href="/archive/2024/03/"`, `href="/archive/2024/04/"`} {
+		if !strings.Contains(index, want) {
+			t.Errorf("archive index missing %s", want)
+		}
+	}
+	if month := read("archive/2024/03"); !strings.Contains(month, "Spring post") || strings.Contains(month, "April post") {
+		t.Errorf("March 2024 page does not list just its post:\n%s", month)
+	}
+	if year := read("archive/2024"); !strings.Contains(year, "Spring post") || !strings.Contains(year, "April post") {
+		t.Errorf("2024 page does not list both posts")
+	}
+
+	params[ArchiveRefKey] = "false"
+	if count, _, _ := g.renderArchivePages(tmpl, nil, build, htmlPath, site, contents, nil, params); count != 0 {
+		t.Errorf("renderArchivePages() with ssg.archive off = %d periods, want 0", count)
+	}
+}
@@ -0,0 +1,151 @@
+package ssg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FingerprintRefKey is the setting that adds a content hash to stylesheet
+// names, so browsers fetch them again whenever they change.
+const FingerprintRefKey = "ssg.assets.fingerprint"
+
+// AssetManifest maps asset paths relative to the output root, such as
+// static/css/theme.css, to their fingerprinted names.
+type AssetManifest map[string]string
+
+// Path returns the name to link for an asset: its fingerprinted name when it
+// has one, the path itself otherwise. Layouts call it as
+// {{ .AssetPath }}{{ .Assets.Path "static/css/theme.css" }}.
+func (m AssetManifest) Path(name string) string {
+	if hashed, ok := m[name]; ok {
+		return hashed
+	}
+	return name
+}
+
+func fingerprintEnabled(params map[string]string) bool {
+	return params[FingerprintRefKey] == "true"
+}
+
+// fingerprintName inserts a short content hash before the extension:
+// static/css/theme.css becomes static/css/theme.3f9a1c2e.css.
+func fingerprintName(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+}
+
+// layoutCSSName is the fingerprinted file a layout's CSS is linked from.
+// Layouts with the same CSS share it.
+func layoutCSSName(css string) string {
+	return fingerprintName("static/css/layout.css", []byte(css))
+}
+
+// staticAssets fingerprints the static assets of a theme. Embedded files do
+// not change while running, so the names are computed once per theme.
+func (g *HTMLGenerator) staticAssets(theme *Theme) AssetManifest {
+	g.staticMu.Lock()
+	defer g.staticMu.Unlock()
+	if m, ok := g.staticManifests[theme.key]; ok {
+		return m
+	}
+
+	m := AssetManifest{}
+	_ = fs.WalkDir(theme.fsys, "static", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		data, err := fs.ReadFile(theme.fsys, p)
+		if err != nil {
+			return nil
+		}
+		m[p] = fingerprintName(p, data)
+		return nil
+	})
+	if g.staticManifests == nil {
+		g.staticManifests = make(map[string]AssetManifest)
+	}
+	g.staticManifests[theme.key] = m
+	return m
+}
+
+// forgetStaticAssets drops the cached asset names of a theme, for themes
+// whose files can change.
+func (g *HTMLGenerator) forgetStaticAssets(theme *Theme) {
+	g.staticMu.Lock()
+	defer g.staticMu.Unlock()
+	delete(g.staticManifests, theme.key)
+}
+
+// setPageAssets fills the stylesheet fields of a page rendered with layout,
+// which may be nil. With fingerprinting on, bundled assets are linked by
+// their hashed names and layout CSS from its hashed file instead of inline.
+func (g *HTMLGenerator) setPageAssets(data *SSGPageData, layout *Layout, params map[string]string) {
+	if layout != nil {
+		data.CustomCSS = layout.CSS
+		data.ExcludeDefaultCSS = layout.ExcludeDefaultCSS
+	}
+	if !fingerprintEnabled(params) {
+		return
+	}
+	var siteSlug string
+	if data.Site != nil {
+		siteSlug = data.Site.Slug
+	}
+	data.Assets = g.staticAssets(g.siteTheme(siteSlug, params))
+	if data.CustomCSS != "" {
+		data.CustomCSSPath = layoutCSSName(data.CustomCSS)
+	}
+}
+
+// writeFingerprintedAssets writes the hashed copies of the theme's assets
+// next to the originals, and a hashed file per distinct layout CSS. The
+// original to hashed mapping is saved to manifestPath for debugging; the
+// generated pages do not need it.
+func (g *HTMLGenerator) writeFingerprintedAssets(build *buildState, htmlPath, manifestPath string, layouts []*Layout, theme *Theme) error {
+	manifest := AssetManifest{}
+	for name, hashed := range g.staticAssets(theme) {
+		data, err := fs.ReadFile(theme.fsys, name)
+		if err != nil {
+			return err
+		}
+		if err := writeAsset(htmlPath, hashed, data); err != nil {
+			return err
+		}
+		build.record(filepath.Join(htmlPath, filepath.FromSlash(hashed)), "", time.Time{})
+		manifest[name] = hashed
+	}
+
+	for _, l := range layouts {
+		if l.CSS == "" {
+			continue
+		}
+		hashed := layoutCSSName(l.CSS)
+		if err := writeAsset(htmlPath, hashed, []byte(l.CSS)); err != nil {
+			return err
+		}
+		build.record(filepath.Join(htmlPath, filepath.FromSlash(hashed)), "", time.Time{})
+		manifest["layout:"+l.Name] = hashed
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(manifestPath, data, 0644)
+}
+
+func writeAsset(htmlPath, name string, data []byte) error {
+	dest := filepath.Join(htmlPath, filepath.FromSlash(name))
+	if err := EnsureDir(dest); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0644)
+}
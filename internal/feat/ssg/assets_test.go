@@ -0,0 +1,103 @@
+package ssg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFingerprintName(t *testing.T) {
+	a := fingerprintName("static/css/theme.css", []byte("body{}"))
+	if !regexp.MustCompile(`^static/css/theme\.[0-9a-f]{8}\.css$`).MatchString(a) {
+		t.Errorf("fingerprintName() = %q", a)
+	}
+	if b := fingerprintName("static/css/theme.css", []byte("body{}")); b != a {
+		t.Errorf("same content gave %q and %q", a, b)
+	}
+	if b := fingerprintName("static/css/theme.css", []byte("body{color:red}")); b == a {
+		t.Error("changed content should change the name")
+	}
+
+	var none AssetManifest
+	if got := none.Path("static/css/core.css"); got != "static/css/core.css" {
+		t.Errorf("Path() without a manifest = %q", got)
+	}
+}
+
+func TestRenderContentPageFingerprint(t *testing.T) {
+	tmpl := parseSiteTemplatesFromDisk(t)
+	site := &Site{ID: uuid.New(), Name: "Hashed", Slug: "hashed"}
+	section := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	published := time.Now().Add(-time.Hour)
+	content := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, SectionPath: "blog", Heading: "Cached", Body: "Body", PublishedAt: &published}
+	layout := &Layout{Name: "Custom", CSS: "h1 { color: red; }"}
+
+	render := func(params map[string]string) string {
+		g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+		// The embedded assets are not available in tests.
+		g.staticManifests = map[string]AssetManifest{DefaultTheme: {
+			"static/css/core.css":  "static/css/core.11111111.css",
+			"static/css/theme.css": "static/css/theme.22222222.css",
+		}}
+		htmlPath := g.workspace.GetHTMLPath(site.Slug)
+		if _, err := g.renderContentPage(tmpl, layout, nil, htmlPath, site, content, nil, adjacentLinks{}, []*Section{section}, nil, params, nil, BlocksConfig{}); err != nil {
+			t.Fatalf("renderContentPage() error = %v", err)
+		}
+		out, err := os.ReadFile(g.workspace.GetContentHTMLPath(site.Slug, content.SectionPath, content.Slug()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	html := render(map[string]string{FingerprintRefKey: "true"})
+	for _, want := range []string{`href="/static/css/core.11111111.css"`, `href="/static/css/theme.22222222.css"`, `href="/` + layoutCSSName(layout.CSS) + `"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("fingerprinted page missing %s", want)
+		}
+	}
+	if strings.Contains(html, "<style>") {
+		t.Error("layout CSS should be linked, not inlined")
+	}
+
+	html = render(map[string]string{})
+	if !strings.Contains(html, `href="/static/css/theme.css"`) || !strings.Contains(html, "<style>h1 { color: red; }</style>") {
+		t.Error("without fingerprinting pages should link the original names and inline layout CSS")
+	}
+}
+
+func TestWriteFingerprintedAssets(t *testing.T) {
+	g := &HTMLGenerator{staticManifests: map[string]AssetManifest{DefaultTheme: {}}}
+	htmlPath := t.TempDir()
+	manifestPath := filepath.Join(t.TempDir(), "asset-manifest.json")
+	layouts := []*Layout{{Name: "Plain"}, {Name: "Dark", CSS: "body { background: #000; }"}}
+
+	theme, _ := g.LoadTheme("", DefaultTheme)
+	if err := g.writeFingerprintedAssets(nil, htmlPath, manifestPath, layouts, theme); err != nil {
+		t.Fatalf("writeFingerprintedAssets() error = %v", err)
+	}
+
+	hashed := layoutCSSName(layouts[1].CSS)
+	data, err := os.ReadFile(filepath.Join(htmlPath, hashed))
+	if err != nil || string(data) != layouts[1].CSS {
+		t.Errorf("layout CSS file = %q, %v", data, err)
+	}
+
+	var manifest AssetManifest
+	raw, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest) != 1 || manifest["layout:Dark"] != hashed {
+		t.Errorf("manifest = %v", manifest)
+	}
+}
@@ -0,0 +1,103 @@
+package ssg
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Setting ref keys for autosave on the content edit form.
+const (
+	// AutosaveRefKey turns saving while the author edits on and off. The
+	// Save button works either way.
+	AutosaveRefKey = "ssg.autosave.enabled"
+	// AutosaveIntervalRefKey is the number of seconds between the periodic
+	// saves of the edit form.
+	AutosaveIntervalRefKey = "ssg.autosave.interval"
+)
+
+const (
+	DefaultAutosaveInterval = 30
+	// MinAutosaveInterval keeps an open edit form from flooding the server.
+	MinAutosaveInterval = 5
+)
+
+// AutosaveSettings is how the content edit form saves on its own.
+type AutosaveSettings struct {
+	Enabled  bool
+	Interval int // seconds between periodic saves
+}
+
+// AutosaveSettingsFromParams reads the autosave settings of a site. Autosave
+// is on unless turned off, and intervals that are missing, invalid or below
+// MinAutosaveInterval are the default or the minimum.
+func AutosaveSettingsFromParams(params map[string]string) AutosaveSettings {
+	settings := AutosaveSettings{Enabled: params[AutosaveRefKey] != "false", Interval: DefaultAutosaveInterval}
+	if n, err := strconv.Atoi(params[AutosaveIntervalRefKey]); err == nil {
+		settings.Interval = max(n, MinAutosaveInterval)
+	}
+	return settings
+}
+
+// Trigger returns the hx-trigger of the edit form: a save half a second after
+// the author stops typing, and one every Interval seconds.
+func (a AutosaveSettings) Trigger() string {
+	return fmt.Sprintf("keyup changed delay:500ms, change delay:500ms, every %ds", a.Interval)
+}
+
+// Outcomes of reconciling a draft kept in the browser with the server.
+const (
+	// ReconcileSaved means the draft was newer and replaced the server copy.
+	ReconcileSaved = "saved"
+	// ReconcileCurrent means the server already has the draft's text.
+	ReconcileCurrent = "current"
+	// ReconcileStale means the server copy is as new or newer; the draft
+	// is dropped.
+	ReconcileStale = "stale"
+	// ReconcileConflict means the content was edited elsewhere after the
+	// draft was started and the draft was edited after that. Nothing is
+	// saved; the browser keeps the draft for the author to decide.
+	ReconcileConflict = "conflict"
+)
+
+// AutosaveDraft is an edit the browser buffered while autosave could not
+// reach the server.
+type AutosaveDraft struct {
+	ContentID uuid.UUID
+	Heading   string // empty keeps the server's heading
+	Body      string
+	UpdatedAt time.Time // when the draft was last edited
+	// BaseUpdatedAt is the server's updated time when the draft was
+	// started. Like the import time for reimports, it tells whether the
+	// server copy changed under the draft. Zero when the browser does not
+	// know it; then only UpdatedAt is compared.
+	BaseUpdatedAt time.Time
+	UpdatedBy     uuid.UUID
+}
+
+// AutosaveReconcile is the result of ReconcileAutosave.
+type AutosaveReconcile struct {
+	Status  string
+	Content *Content // the server copy after reconciling
+}
+
+// reconcileDraft decides what to do with a buffered draft against the
+// server copy. Times are compared in milliseconds, the precision the
+// browser keeps them in.
+func reconcileDraft(server *Content, draft AutosaveDraft) string {
+	if draft.Body == server.Body && (draft.Heading == "" || draft.Heading == server.Heading) {
+		return ReconcileCurrent
+	}
+
+	serverAt := server.UpdatedAt.Truncate(time.Millisecond)
+	draftAt := draft.UpdatedAt.Truncate(time.Millisecond)
+	if !draftAt.After(serverAt) {
+		return ReconcileStale
+	}
+	if base := draft.BaseUpdatedAt.Truncate(time.Millisecond); !base.IsZero() && serverAt.After(base) {
+		return ReconcileConflict
+	}
+	return ReconcileSaved
+}
@@ -0,0 +1,178 @@
+package ssg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestReconcileDraft(t *testing.T) {
+	saved := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	server := &Content{Heading: "Post", Body: "server text", UpdatedAt: saved}
+
+	tests := []struct {
+		name  string
+		draft AutosaveDraft
+		want  string
+	}{
+		{"newer client", AutosaveDraft{Body: "offline text", UpdatedAt: saved.Add(time.Minute)}, ReconcileSaved},
+		{"newer server", AutosaveDraft{Body: "offline text", UpdatedAt: saved.Add(-time.Minute)}, ReconcileStale},
+		{"equal times", AutosaveDraft{Body: "offline text", UpdatedAt: saved}, ReconcileStale},
+		{"equal times within a millisecond", AutosaveDraft{Body: "offline text", UpdatedAt: saved.Add(500 * time.Microsecond)}, ReconcileStale},
+		{"same text", AutosaveDraft{Body: "server text", UpdatedAt: saved.Add(time.Minute)}, ReconcileCurrent},
+		{"new heading", AutosaveDraft{Heading: "Renamed", Body: "server text", UpdatedAt: saved.Add(time.Minute)}, ReconcileSaved},
+		{"base is the server copy", AutosaveDraft{Body: "offline text", UpdatedAt: saved.Add(time.Minute), BaseUpdatedAt: saved}, ReconcileSaved},
+		{"server edited since base", AutosaveDraft{Body: "offline text", UpdatedAt: saved.Add(time.Minute), BaseUpdatedAt: saved.Add(-time.Hour)}, ReconcileConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reconcileDraft(server, tt.draft); got != tt.want {
+				t.Errorf("reconcileDraft() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceReconcileAutosave(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Autosave Site", "autosave-site")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection() error = %v", err)
+	}
+
+	newContent := func() *Content {
+		t.Helper()
+		content := NewContent(site.ID, section.ID, "Post", "server text")
+		content.CreatedBy = uuid.New()
+		content.UpdatedBy = content.CreatedBy
+		if err := svc.CreateContent(ctx, content); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+		stored, err := svc.GetContent(ctx, content.ID)
+		if err != nil {
+			t.Fatalf("GetContent() error = %v", err)
+		}
+		return stored
+	}
+
+	tests := []struct {
+		name     string
+		offset   time.Duration
+		want     string
+		wantBody string
+	}{
+		{"newer client", time.Hour, ReconcileSaved, "offline text"},
+		{"newer server", -time.Hour, ReconcileStale, "server text"},
+		{"equal", 0, ReconcileStale, "server text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := newContent()
+			draft := AutosaveDraft{
+				ContentID:     content.ID,
+				Body:          "offline text",
+				UpdatedAt:     content.UpdatedAt.Add(tt.offset),
+				BaseUpdatedAt: content.UpdatedAt,
+			}
+
+			result, err := svc.ReconcileAutosave(ctx, draft)
+			if err != nil {
+				t.Fatalf("ReconcileAutosave() error = %v", err)
+			}
+			if result.Status != tt.want {
+				t.Errorf("Status = %q, want %q", result.Status, tt.want)
+			}
+			if result.Content.Body != tt.wantBody {
+				t.Errorf("returned body = %q, want %q", result.Content.Body, tt.wantBody)
+			}
+			stored, err := svc.GetContent(ctx, content.ID)
+			if err != nil {
+				t.Fatalf("GetContent() error = %v", err)
+			}
+			if stored.Body != tt.wantBody {
+				t.Errorf("stored body = %q, want %q", stored.Body, tt.wantBody)
+			}
+		})
+	}
+
+	t.Run("stale draft does not overwrite newer server edits", func(t *testing.T) {
+		content := newContent()
+		base := content.UpdatedAt
+		content.Body = "edited elsewhere"
+		if err := svc.UpdateContent(ctx, content); err != nil {
+			t.Fatalf("UpdateContent() error = %v", err)
+		}
+
+		result, err := svc.ReconcileAutosave(ctx, AutosaveDraft{
+			ContentID:     content.ID,
+			Body:          "offline text",
+			UpdatedAt:     content.UpdatedAt.Add(time.Minute),
+			BaseUpdatedAt: base.Add(-time.Second),
+		})
+		if err != nil {
+			t.Fatalf("ReconcileAutosave() error = %v", err)
+		}
+		if result.Status != ReconcileConflict || result.Content.Body != "edited elsewhere" {
+			t.Errorf("ReconcileAutosave() = %q with %q, want a conflict keeping the server text", result.Status, result.Content.Body)
+		}
+	})
+
+	t.Run("unknown content", func(t *testing.T) {
+		if _, err := svc.ReconcileAutosave(ctx, AutosaveDraft{ContentID: uuid.New(), UpdatedAt: time.Now()}); err != ErrNotFound {
+			t.Errorf("ReconcileAutosave() error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestAutosaveSettingsFromParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   AutosaveSettings
+	}{
+		{"defaults", nil, AutosaveSettings{Enabled: true, Interval: DefaultAutosaveInterval}},
+		{"off", map[string]string{AutosaveRefKey: "false"}, AutosaveSettings{Enabled: false, Interval: DefaultAutosaveInterval}},
+		{"interval", map[string]string{AutosaveIntervalRefKey: "120"}, AutosaveSettings{Enabled: true, Interval: 120}},
+		{"interval below the minimum", map[string]string{AutosaveIntervalRefKey: "1"}, AutosaveSettings{Enabled: true, Interval: MinAutosaveInterval}},
+		{"invalid interval", map[string]string{AutosaveIntervalRefKey: "often"}, AutosaveSettings{Enabled: true, Interval: DefaultAutosaveInterval}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AutosaveSettingsFromParams(tt.params); got != tt.want {
+				t.Errorf("AutosaveSettingsFromParams() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditPageAutosaveSettings(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Autosave Site", "autosave-site")
+	h := &Handler{service: svc}
+
+	if got := h.autosaveSettings(ctx, site.ID); !got.Enabled || got.Trigger() != "keyup changed delay:500ms, change delay:500ms, every 30s" {
+		t.Errorf("autosaveSettings() without settings = %+v, trigger %q", got, got.Trigger())
+	}
+
+	for refKey, value := range map[string]string{AutosaveRefKey: "false", AutosaveIntervalRefKey: "90"} {
+		param := NewSetting(site.ID, refKey, value)
+		param.RefKey = refKey
+		if err := svc.CreateSetting(ctx, param); err != nil {
+			t.Fatalf("CreateSetting(%s) error = %v", refKey, err)
+		}
+	}
+	if got, want := h.autosaveSettings(ctx, site.ID), (AutosaveSettings{Enabled: false, Interval: 90}); got != want {
+		t.Errorf("autosaveSettings() = %+v, want %+v", got, want)
+	}
+}
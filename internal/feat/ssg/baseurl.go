@@ -0,0 +1,138 @@
+package ssg
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Setting ref keys for the site's public address.
+const (
+	BaseURLRefKey  = "ssg.site.base_url"
+	BasePathRefKey = "ssg.site.base_path"
+	DomainRefKey   = "ssg.site.domain"
+)
+
+// NormalizeBaseURL validates a site base URL and returns it without trailing
+// slashes, so paths starting with "/" can be appended directly. The URL must be
+// absolute http(s) with a host, and carry no query or fragment. An empty value
+// is returned as is: absolute URLs are then not generated.
+func NormalizeBaseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("base URL %q must start with http:// or https://", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("base URL %q has no host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("base URL %q must not have a query or fragment", raw)
+	}
+
+	return strings.TrimRight(raw, "/"), nil
+}
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// NormalizeDomain validates a custom domain for the CNAME file.
+func NormalizeDomain(raw string) (string, error) {
+	domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(raw), "."))
+	if domain == "" {
+		return "", nil
+	}
+	if !domainPattern.MatchString(domain) {
+		return "", fmt.Errorf("%q is not a valid domain name (e.g. blog.example.com)", raw)
+	}
+	return domain, nil
+}
+
+// siteBaseURL is the single source of the base URL during generation.
+// Invalid values are treated as missing; settings validation rejects them.
+func siteBaseURL(params map[string]string) string {
+	baseURL, err := NormalizeBaseURL(params[BaseURLRefKey])
+	if err != nil {
+		return ""
+	}
+	return baseURL
+}
+
+// PublicURL is the address content is published at.
+type PublicURL struct {
+	URL string
+	// Relative is set when the site has no base URL, so URL is only a path
+	// from the root of the server the site is deployed to.
+	Relative bool
+	// Provisional is set while the content is a draft or scheduled: nothing
+	// is published at URL yet, and it changes with the slug or section.
+	Provisional bool
+}
+
+// siteBasePath returns the path the site is served under, with leading and
+// trailing slashes, "/" when it is served at the root.
+func siteBasePath(params map[string]string) string {
+	basePath := params[BasePathRefKey]
+	if basePath == "" {
+		return "/"
+	}
+	if basePath[0] != '/' {
+		basePath = "/" + basePath
+	}
+	if basePath[len(basePath)-1] != '/' {
+		basePath = basePath + "/"
+	}
+	return basePath
+}
+
+// publicURL returns the address of the page generated at path, relative to
+// the site root, as the sitemap, feeds and canonical links give it. Sites
+// without a base URL get a path from the root of the server.
+func publicURL(params map[string]string, path string) string {
+	if path = strings.Trim(path, "/"); path != "" {
+		path += "/"
+	}
+	return siteBaseURL(params) + pageURL(params, siteBasePath(params)+path)
+}
+
+// cnameDomain returns the domain written to the CNAME file for GitHub Pages:
+// the custom domain setting, or else the base URL host. Local hosts get none.
+func cnameDomain(params map[string]string) string {
+	if domain, err := NormalizeDomain(params[DomainRefKey]); err == nil && domain != "" {
+		return domain
+	}
+
+	baseURL := siteBaseURL(params)
+	if baseURL == "" {
+		return ""
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	hostname := u.Hostname()
+	if hostname == "localhost" {
+		return ""
+	}
+	return hostname
+}
+
+// baseURLWarnings reports base URL problems that degrade the generated site
+// without failing the build.
+func baseURLWarnings(params map[string]string) []string {
+	raw := strings.TrimSpace(params[BaseURLRefKey])
+	if _, err := NormalizeBaseURL(raw); err != nil {
+		return []string{fmt.Sprintf("%v: sitemap is not generated and canonical URLs are relative", err)}
+	}
+	if raw == "" {
+		return []string{"site base URL is not set: sitemap is not generated and canonical URLs are relative"}
+	}
+	return nil
+}
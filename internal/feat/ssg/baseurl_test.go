@@ -0,0 +1,175 @@
+package ssg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"https://example.com", "https://example.com", false},
+		{"https://example.com/", "https://example.com", false},
+		{"https://example.com///", "https://example.com", false},
+		{"  https://user.github.io/blog/ ", "https://user.github.io/blog", false},
+		{"http://localhost:8080/", "http://localhost:8080", false},
+		{"", "", false},
+		{"example.com", "", true},
+		{"ftp://example.com", "", true},
+		{"https://", "", true},
+		{"https://example.com/?ref=x", "", true},
+		{"https://example.com/#top", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := NormalizeBaseURL(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeBaseURL(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeBaseURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbsoluteURLsUseNormalizedBaseURL(t *testing.T) {
+	g := &HTMLGenerator{}
+	for _, baseURL := range []string{"https://example.com", "https://example.com/", "https://example.com//"} {
+		params := map[string]string{BaseURLRefKey: baseURL}
+		if got := g.getAbsoluteURL(params, "/blog/"); got != "https://example.com/blog/" {
+			t.Errorf("getAbsoluteURL with base %q = %q, want https://example.com/blog/", baseURL, got)
+		}
+	}
+
+	if got := g.getAbsoluteURL(map[string]string{BaseURLRefKey: "not a url"}, "/blog/"); got != "/blog/" {
+		t.Errorf("invalid base URL should yield relative URLs, got %q", got)
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	for raw, want := range map[string]string{"blog.example.com": "blog.example.com", "Example.COM.": "example.com", "": ""} {
+		if got, err := NormalizeDomain(raw); err != nil || got != want {
+			t.Errorf("NormalizeDomain(%q) = %q, %v, want %q", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"https://example.com", "example", "exa mple.com", "example.com/blog"} {
+		if _, err := NormalizeDomain(raw); err == nil {
+			t.Errorf("NormalizeDomain(%q) expected error", raw)
+		}
+	}
+}
+
+func TestBaseURLWarnings(t *testing.T) {
+	if w := baseURLWarnings(map[string]string{BaseURLRefKey: "https://example.com"}); len(w) != 0 {
+		t.Errorf("unexpected warnings: %v", w)
+	}
+	if w := baseURLWarnings(map[string]string{}); len(w) != 1 || !strings.Contains(w[0], "not set") {
+		t.Errorf("missing base URL warnings = %v", w)
+	}
+	if w := baseURLWarnings(map[string]string{BaseURLRefKey: "example.com"}); len(w) != 1 || !strings.Contains(w[0], "http") {
+		t.Errorf("invalid base URL warnings = %v", w)
+	}
+}
+
+func TestServiceGetBaseURL(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Base URL Site", "base-url-site")
+
+	if got, err := svc.GetBaseURL(ctx, site.ID); err != nil || got != "" {
+		t.Errorf("GetBaseURL() without setting = %q, %v, want empty", got, err)
+	}
+
+	param := NewSetting(site.ID, "Site base URL", "https://example.com/")
+	param.RefKey = BaseURLRefKey
+	if err := svc.CreateSetting(ctx, param); err != nil {
+		t.Fatalf("CreateSetting() error = %v", err)
+	}
+	if got, err := svc.GetBaseURL(ctx, site.ID); err != nil || got != "https://example.com" {
+		t.Errorf("GetBaseURL() = %q, %v, want https://example.com", got, err)
+	}
+
+	param.Value = "example.com"
+	if err := svc.UpdateSetting(ctx, param); err == nil {
+		t.Error("UpdateSetting() with an invalid base URL should fail validation")
+	}
+}
+
+func TestPublicURL(t *testing.T) {
+	published := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	post := &Content{ShortID: "abc123", Heading: "Hello World", SectionPath: "blog", PublishedAt: &published}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   string
+	}{
+		{"default pattern", map[string]string{BaseURLRefKey: "https://example.com/"}, "https://example.com/blog/hello-world-abc123/"},
+		{"dated pattern", map[string]string{BaseURLRefKey: "https://example.com", PermalinkRefKey: "/:year/:month/:slug/"}, "https://example.com/2024/03/hello-world-abc123/"},
+		{"base path", map[string]string{BaseURLRefKey: "https://user.github.io", BasePathRefKey: "blog", PermalinkRefKey: "/:slug/"}, "https://user.github.io/blog/hello-world-abc123/"},
+		{"no base URL", map[string]string{PermalinkRefKey: "/posts/:slug/"}, "/posts/hello-world-abc123/"},
+		{"invalid base URL", map[string]string{BaseURLRefKey: "example.com"}, "/blog/hello-world-abc123/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := publicURL(tt.params, permalinkPattern(tt.params).contentPath(post)); got != tt.want {
+				t.Errorf("publicURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The public URL is the one generated pages link to.
+	g := &HTMLGenerator{}
+	params := tests[2].params
+	if got, want := g.getAbsoluteURL(params, g.getContentURL(post, g.getAssetPath(params), params)), tests[2].want; got != want {
+		t.Errorf("generated URL = %q, want %q", got, want)
+	}
+}
+
+func TestServiceGetPublicURL(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Public URL Site", "public-url-site")
+
+	blog := NewSection(site.ID, "Blog", "", "blog")
+	svc.CreateSection(ctx, blog)
+	draft := NewContent(site.ID, blog.ID, "Hello", "Body")
+	draft.ShortID = "abc123"
+	svc.CreateContent(ctx, draft)
+
+	got, err := svc.GetPublicURL(ctx, draft)
+	if err != nil {
+		t.Fatalf("GetPublicURL() error = %v", err)
+	}
+	if got.URL != "/blog/hello-abc123/" || !got.Relative || !got.Provisional {
+		t.Errorf("GetPublicURL() of a draft without base URL = %+v, want a provisional relative path", got)
+	}
+
+	for refKey, value := range map[string]string{BaseURLRefKey: "https://example.com", PermalinkRefKey: "/posts/:slug/"} {
+		param := NewSetting(site.ID, refKey, value)
+		param.RefKey = refKey
+		if err := svc.CreateSetting(ctx, param); err != nil {
+			t.Fatalf("CreateSetting(%s) error = %v", refKey, err)
+		}
+	}
+	published := time.Now().Add(-time.Hour)
+	draft.Draft = false
+	draft.PublishedAt = &published
+
+	got, err = svc.GetPublicURL(ctx, draft)
+	if err != nil {
+		t.Fatalf("GetPublicURL() error = %v", err)
+	}
+	if got.URL != "https://example.com/posts/hello-abc123/" || got.Relative || got.Provisional {
+		t.Errorf("GetPublicURL() of published content = %+v, want the final absolute URL", got)
+	}
+}
@@ -0,0 +1,136 @@
+package ssg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Changelog settings.
+const (
+	// ChangelogSectionRefKey is the path of the section holding the release
+	// notes of a product site. Its posts are still generated as pages, and
+	// also written to changelog.json for in-app widgets. Empty turns it off.
+	ChangelogSectionRefKey = "ssg.changelog.section"
+	// ChangelogLimitRefKey is the number of latest entries changelog.json holds.
+	ChangelogLimitRefKey = "ssg.changelog.limit"
+)
+
+const (
+	changelogFile         = "changelog.json"
+	defaultChangelogLimit = 20
+	// changelogVersionField is the custom field holding the version a
+	// release note is about. Entries without it use their heading.
+	changelogVersionField = "version"
+)
+
+// ChangelogEntry is a release note as changelog.json lists it.
+type ChangelogEntry struct {
+	Version string `json:"version"`
+	Title   string `json:"title"`
+	Date    string `json:"date"` // RFC 3339, in the site timezone
+	URL     string `json:"url"`
+	Body    string `json:"body"` // HTML
+}
+
+// changelogDoc is the root of changelog.json.
+type changelogDoc struct {
+	Title   string           `json:"title"`
+	URL     string           `json:"url"`
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+// changelogSection returns the section ssg.changelog.section names, or nil
+// when it is unset or names no section.
+func changelogSection(params map[string]string, sections []*Section) *Section {
+	path := strings.TrimSpace(params[ChangelogSectionRefKey])
+	if path == "" {
+		return nil
+	}
+	for _, s := range sections {
+		if normalizePath(s.Path) == normalizePath(path) {
+			return s
+		}
+	}
+	return nil
+}
+
+func changelogLimit(params map[string]string) int {
+	if n, err := strconv.Atoi(strings.TrimSpace(params[ChangelogLimitRefKey])); err == nil && n > 0 {
+		return n
+	}
+	return defaultChangelogLimit
+}
+
+// changelogContents returns the published content of section, newest first,
+// up to the ssg.changelog.limit setting.
+func changelogContents(contents []*Content, section *Section, params map[string]string) []*Content {
+	var entries []*Content
+	for _, c := range contents {
+		if c.SectionID == section.ID && isListed(c) {
+			entries = append(entries, c)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return publicationDate(entries[i]).After(publicationDate(entries[j]))
+	})
+	if limit := changelogLimit(params); len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// changelogVersion returns the version custom field of c, or its heading.
+func changelogVersion(c *Content) string {
+	if v, ok := c.Fields[changelogVersionField]; ok {
+		if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+			return s
+		}
+	}
+	return c.Heading
+}
+
+// generateChangelog writes changelog.json for the changelog section, if the
+// site has one, and returns the number of entries written. Links and images
+// are absolute when the site has a base URL.
+func (g *HTMLGenerator) generateChangelog(htmlPath, baseURL, basePath string, contents []*Content, sections []*Section, params map[string]string) (int, error) {
+	section := changelogSection(params, sections)
+	if section == nil {
+		return 0, nil
+	}
+
+	loc := siteLocation(params)
+	doc := changelogDoc{
+		Title:   section.Name,
+		URL:     baseURL + g.getPaginationURL(params, basePath, section.Path, 1),
+		Entries: []ChangelogEntry{},
+	}
+	for _, c := range changelogContents(contents, section, params) {
+		body, _ := g.processor.ProcessContent(c, params)
+		body = sanitizeFeedHTML(body)
+		if baseURL != "" {
+			body = absoluteFeedURLs(body, baseURL)
+		}
+		doc.Entries = append(doc.Entries, ChangelogEntry{
+			Version: changelogVersion(c),
+			Title:   c.Heading,
+			Date:    publicationDate(c).In(loc).Format(time.RFC3339),
+			URL:     baseURL + g.getContentURL(c, basePath, params),
+			Body:    body,
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(htmlPath, changelogFile), append(data, '\n'), 0644); err != nil {
+		return 0, err
+	}
+	return len(doc.Entries), nil
+}
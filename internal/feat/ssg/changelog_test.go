@@ -0,0 +1,134 @@
+package ssg
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateChangelog(t *testing.T) {
+	g := &HTMLGenerator{processor: NewProcessor()}
+	htmlPath := t.TempDir()
+
+	changelog := &Section{ID: uuid.New(), Name: "Changelog", Path: "changelog"}
+	blog := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	sections := []*Section{blog, changelog}
+
+	day := func(d int) *time.Time {
+		at := time.Date(2025, 3, d, 10, 0, 0, 0, time.UTC)
+		return &at
+	}
+	release := func(section *Section, heading, body, version string, publishedAt *time.Time) *Content {
+		c := &Content{ID: uuid.New(), ShortID: uuid.NewString()[:8], SectionID: section.ID, SectionPath: section.Path,
+			Heading: heading, Body: body, PublishedAt: publishedAt}
+		if version != "" {
+			c.Fields = fieldValues([]*ContentField{{Name: changelogVersionField, Type: FieldTypeString, Value: version}})
+		}
+		return c
+	}
+	draft := release(changelog, "Next", "Soon", "3.0.0", day(20))
+	draft.Draft = true
+	contents := []*Content{
+		release(changelog, "First release", "Hello", "1.0.0", day(1)),
+		release(changelog, "Faster exports", "Exports run [in the background](/docs/exports).", "1.2.0", day(10)),
+		release(blog, "A blog post", "Not a release", "", day(12)),
+		release(changelog, "Bug fixes", "Fewer bugs", "", day(5)),
+		release(changelog, "Scheduled", "Later", "", func() *time.Time { at := time.Now().Add(time.Hour); return &at }()),
+		draft,
+	}
+	params := map[string]string{
+		ChangelogSectionRefKey: "/changelog",
+		ChangelogLimitRefKey:   "2",
+		BaseURLRefKey:          "https://example.com",
+	}
+
+	n, err := g.generateChangelog(htmlPath, "https://example.com", "/", contents, sections, params)
+	if err != nil {
+		t.Fatalf("generateChangelog() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("generateChangelog() = %d entries, want 2", n)
+	}
+
+	data, err := os.ReadFile(filepath.Join(htmlPath, changelogFile))
+	if err != nil {
+		t.Fatalf("cannot read %s: %v", changelogFile, err)
+	}
+	var doc changelogDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("%s is not valid JSON: %v\n%s", changelogFile, err, data)
+	}
+	if doc.Title != "Changelog" || doc.URL != "https://example.com/changelog/" {
+		t.Errorf("changelog = %q at %q, want Changelog at https://example.com/changelog/", doc.Title, doc.URL)
+	}
+
+	want := []struct{ version, title, date string }{
+		{"1.2.0", "Faster exports", "2025-03-10T10:00:00Z"},
+		{"Bug fixes", "Bug fixes", "2025-03-05T10:00:00Z"},
+	}
+	if len(doc.Entries) != len(want) {
+		t.Fatalf("entries = %+v, want %d", doc.Entries, len(want))
+	}
+	for i, w := range want {
+		e := doc.Entries[i]
+		if e.Version != w.version || e.Title != w.title || e.Date != w.date {
+			t.Errorf("entry %d = %s %q %s, want %s %q %s", i, e.Version, e.Title, e.Date, w.version, w.title, w.date)
+		}
+	}
+	if got, want := doc.Entries[0].Body, `<a href="https://example.com/docs/exports/">`; !strings.Contains(got, want) {
+		t.Errorf("body = %q, want it to contain %q", got, want)
+	}
+
+	delete(params, ChangelogSectionRefKey)
+	os.Remove(filepath.Join(htmlPath, changelogFile))
+	if n, err := g.generateChangelog(htmlPath, "https://example.com", "/", contents, sections, params); err != nil || n != 0 {
+		t.Errorf("generateChangelog() without a changelog section = %d, %v", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(htmlPath, changelogFile)); !os.IsNotExist(err) {
+		t.Errorf("%s written without a changelog section", changelogFile)
+	}
+}
+
+func TestServiceGetChangelogEntries(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Product", "product")
+	section := NewSection(site.ID, "Changelog", "", "changelog")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatal(err)
+	}
+	for i, heading := range []string{"1.0", "1.1"} {
+		c := NewContent(site.ID, section.ID, heading, "Notes")
+		c.Draft = false
+		at := time.Now().Add(time.Duration(i-2) * time.Hour)
+		c.PublishedAt = &at
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if entries, err := svc.GetChangelogEntries(ctx, site.ID); err != nil || len(entries) != 0 {
+		t.Fatalf("GetChangelogEntries() without the setting = %d, %v; want none", len(entries), err)
+	}
+
+	setting := NewSetting(site.ID, "Changelog section", "changelog")
+	setting.RefKey = ChangelogSectionRefKey
+	if err := svc.CreateSetting(ctx, setting); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := svc.GetChangelogEntries(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("GetChangelogEntries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Heading != "1.1" || entries[1].Heading != "1.0" {
+		t.Errorf("GetChangelogEntries() = %v, want 1.1 then 1.0", entries)
+	}
+}
@@ -0,0 +1,421 @@
+package ssg
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookFormat is the output format of a compiled series or section.
+type BookFormat string
+
+const (
+	// BookEPUB is an EPUB 3 file: a zip of XHTML chapters with the images embedded.
+	BookEPUB BookFormat = "epub"
+	// BookHTML is a single self-contained HTML page, images inlined as data URIs.
+	BookHTML BookFormat = "html"
+)
+
+// ParseBookFormat validates a format name. An empty name is EPUB.
+func ParseBookFormat(s string) (BookFormat, error) {
+	switch BookFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case "", BookEPUB:
+		return BookEPUB, nil
+	case BookHTML:
+		return BookHTML, nil
+	}
+	return "", fmt.Errorf("unsupported book format %q (use epub or html)", s)
+}
+
+// Extension returns the file extension for the format, without the dot.
+func (f BookFormat) Extension() string {
+	return string(f)
+}
+
+// ContentType returns the MIME type of the format.
+func (f BookFormat) ContentType() string {
+	if f == BookHTML {
+		return "text/html; charset=utf-8"
+	}
+	return "application/epub+zip"
+}
+
+// bookImageTypes are the image formats EPUB readers are required to support,
+// plus WebP, which most handle. Other images keep their original URL.
+var bookImageTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".svg":  "image/svg+xml",
+	".webp": "image/webp",
+}
+
+var bookImageSrc = regexp.MustCompile(`src="/images/([^"]+)"`)
+
+// Book is content compiled into a single reading sequence.
+type Book struct {
+	ID       uuid.UUID
+	Title    string
+	Site     string
+	Authors  []string
+	Date     string // Publication date in the site timezone, for the title page
+	Modified time.Time
+	Chapters []*BookChapter
+	Images   []*BookImage
+}
+
+// BookChapter is one content item of a book.
+type BookChapter struct {
+	ID    string // File name without extension, also the anchor in HTML output
+	Title string
+	Body  string // XHTML fragment
+}
+
+// BookImage is a workspace image referenced by the chapters.
+type BookImage struct {
+	ID        string
+	Href      string // Path inside the book, e.g. images/photo.jpg
+	MediaType string
+	File      string // Path on disk
+}
+
+// newBook renders contents, in order, into a book. Images under /images/ are
+// looked up in imagesPath and embedded; missing ones keep their URL.
+func newBook(siteID uuid.UUID, siteName, title string, contents []*Content, imagesPath string, params map[string]string) (*Book, error) {
+	loc := siteLocation(params)
+	b := &Book{
+		ID:    uuid.NewSHA1(siteID, []byte(title)),
+		Title: title,
+		Site:  siteName,
+	}
+
+	processor := NewProcessor()
+	images := make(map[string]*BookImage)
+	authors := make(map[string]bool)
+	var published time.Time
+
+	for i, c := range contents {
+		body, err := processor.ProcessContent(c, params)
+		if err != nil {
+			return nil, fmt.Errorf("cannot render %q: %w", c.Heading, err)
+		}
+		body = bookImageSrc.ReplaceAllStringFunc(body, func(match string) string {
+			rel := bookImageSrc.FindStringSubmatch(match)[1]
+			img := bookImage(images, imagesPath, rel)
+			if img == nil {
+				return match
+			}
+			return `src="` + img.Href + `"`
+		})
+
+		b.Chapters = append(b.Chapters, &BookChapter{
+			ID:    fmt.Sprintf("chapter-%03d", i+1),
+			Title: c.Heading,
+			Body:  body,
+		})
+
+		if author := c.DisplayHandle(); author != "" && !authors[author] {
+			authors[author] = true
+			b.Authors = append(b.Authors, author)
+		}
+		if c.UpdatedAt.After(b.Modified) {
+			b.Modified = c.UpdatedAt
+		}
+		if c.PublishedAt != nil && c.PublishedAt.After(published) {
+			published = *c.PublishedAt
+		}
+	}
+
+	if !published.IsZero() {
+		b.Date = published.In(loc).Format("January 2, 2006")
+	}
+	for _, img := range images {
+		b.Images = append(b.Images, img)
+	}
+	sort.Slice(b.Images, func(i, j int) bool { return b.Images[i].Href < b.Images[j].Href })
+	for i, img := range b.Images {
+		img.ID = fmt.Sprintf("image-%03d", i+1)
+	}
+	return b, nil
+}
+
+// bookImage returns the embedded image for a path under /images/, adding it
+// on first use. It returns nil when the file is missing or of an unknown type.
+func bookImage(images map[string]*BookImage, imagesPath, rel string) *BookImage {
+	if img, ok := images[rel]; ok {
+		return img
+	}
+	clean := path.Clean("/" + rel)[1:]
+	mediaType, ok := bookImageTypes[strings.ToLower(path.Ext(clean))]
+	if !ok || clean == "" || clean != rel {
+		return nil
+	}
+	file := filepath.Join(imagesPath, filepath.FromSlash(clean))
+	if info, err := os.Stat(file); err != nil || info.IsDir() {
+		return nil
+	}
+	img := &BookImage{Href: "images/" + clean, MediaType: mediaType, File: file}
+	images[rel] = img
+	return img
+}
+
+// Render writes the book in the given format.
+func (b *Book) Render(format BookFormat) ([]byte, error) {
+	if format == BookHTML {
+		return b.HTML()
+	}
+	return b.EPUB()
+}
+
+// EPUB writes the book as an EPUB 3 file with a title page and a navigation
+// document listing the chapters. Output is reproducible: entries are written
+// in a fixed order and timestamped with the book's last modification.
+func (b *Book) EPUB() ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	modified := b.Modified.UTC()
+	if modified.IsZero() {
+		modified = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	add := func(name string, method uint16, data []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: modified})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	render := func(name string, tmpl *template.Template, data any) error {
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return fmt.Errorf("cannot render %s: %w", name, err)
+		}
+		return add(name, zip.Deflate, out.Bytes())
+	}
+
+	// The mimetype entry must come first and be stored uncompressed.
+	if err := add("mimetype", zip.Store, []byte("application/epub+zip")); err != nil {
+		return nil, err
+	}
+	if err := add("META-INF/container.xml", zip.Deflate, []byte(epubContainer)); err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		*Book
+		Modified string
+	}{b, modified.Format("2006-01-02T15:04:05Z")}
+	if err := render("OEBPS/content.opf", epubPackageTmpl, data); err != nil {
+		return nil, err
+	}
+	if err := render("OEBPS/nav.xhtml", epubNavTmpl, b); err != nil {
+		return nil, err
+	}
+	if err := render("OEBPS/title.xhtml", epubTitleTmpl, b); err != nil {
+		return nil, err
+	}
+	for _, ch := range b.Chapters {
+		if err := render("OEBPS/"+ch.ID+".xhtml", epubChapterTmpl, ch); err != nil {
+			return nil, err
+		}
+	}
+	for _, img := range b.Images {
+		data, err := os.ReadFile(img.File)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read image %s: %w", img.Href, err)
+		}
+		if err := add("OEBPS/"+img.Href, zip.Store, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// HTML writes the book as a single page with the title page, a table of
+// contents linking to each chapter, and the images inlined.
+func (b *Book) HTML() ([]byte, error) {
+	inline := make(map[string]string, len(b.Images))
+	for _, img := range b.Images {
+		data, err := os.ReadFile(img.File)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read image %s: %w", img.Href, err)
+		}
+		inline[`src="`+img.Href+`"`] = `src="data:` + img.MediaType + `;base64,` + base64.StdEncoding.EncodeToString(data) + `"`
+	}
+
+	type chapter struct {
+		ID    string
+		Title string
+		Body  htmltemplate.HTML
+	}
+	chapters := make([]chapter, len(b.Chapters))
+	for i, ch := range b.Chapters {
+		body := ch.Body
+		for src, dataURI := range inline {
+			body = strings.ReplaceAll(body, src, dataURI)
+		}
+		chapters[i] = chapter{ch.ID, ch.Title, htmltemplate.HTML(body)}
+	}
+
+	var out bytes.Buffer
+	err := bookHTMLTmpl.Execute(&out, struct {
+		*Book
+		Chapters []chapter
+	}{b, chapters})
+	if err != nil {
+		return nil, fmt.Errorf("cannot render book: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// The EPUB templates use text/template with explicit escaping: html/template
+// would treat the XML declarations and package document as HTML.
+var bookFuncs = template.FuncMap{"xml": htmltemplate.HTMLEscapeString}
+
+var epubPackageTmpl = template.Must(template.New("content.opf").Funcs(bookFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">urn:uuid:{{ .ID }}</dc:identifier>
+    <dc:title>{{ xml .Title }}</dc:title>
+    <dc:language>en</dc:language>
+    <dc:publisher>{{ xml .Site }}</dc:publisher>
+{{- range .Authors }}
+    <dc:creator>{{ xml . }}</dc:creator>
+{{- end }}
+    <meta property="dcterms:modified">{{ .Modified }}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="title" href="title.xhtml" media-type="application/xhtml+xml"/>
+{{- range .Chapters }}
+    <item id="{{ .ID }}" href="{{ .ID }}.xhtml" media-type="application/xhtml+xml"/>
+{{- end }}
+{{- range .Images }}
+    <item id="{{ .ID }}" href="{{ xml .Href }}" media-type="{{ .MediaType }}"/>
+{{- end }}
+  </manifest>
+  <spine>
+    <itemref idref="title"/>
+    <itemref idref="nav"/>
+{{- range .Chapters }}
+    <itemref idref="{{ .ID }}"/>
+{{- end }}
+  </spine>
+</package>
+`))
+
+var epubNavTmpl = template.Must(template.New("nav.xhtml").Funcs(bookFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head><title>Contents</title></head>
+<body>
+<nav epub:type="toc" id="toc">
+<h1>Contents</h1>
+<ol>
+{{- range .Chapters }}
+<li><a href="{{ .ID }}.xhtml">{{ xml .Title }}</a></li>
+{{- end }}
+</ol>
+</nav>
+</body>
+</html>
+`))
+
+var epubTitleTmpl = template.Must(template.New("title.xhtml").Funcs(bookFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" lang="en" xml:lang="en">
+<head><title>{{ xml .Title }}</title></head>
+<body>
+<section class="title-page">
+<h1>{{ xml .Title }}</h1>
+{{- if .Authors }}
+<p class="authors">{{ range $i, $a := .Authors }}{{ if $i }}, {{ end }}{{ xml $a }}{{ end }}</p>
+{{- end }}
+<p class="site">{{ xml .Site }}</p>
+{{- if .Date }}
+<p class="date">{{ .Date }}</p>
+{{- end }}
+</section>
+</body>
+</html>
+`))
+
+var epubChapterTmpl = template.Must(template.New("chapter.xhtml").Funcs(bookFuncs).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" lang="en" xml:lang="en">
+<head><title>{{ xml .Title }}</title></head>
+<body>
+<section>
+<h1>{{ xml .Title }}</h1>
+{{ .Body }}
+</section>
+</body>
+</html>
+`))
+
+var bookHTMLTmpl = htmltemplate.Must(htmltemplate.New("book.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { max-width: 40em; margin: 0 auto; padding: 1em; font-family: Georgia, serif; line-height: 1.6; }
+img { max-width: 100%; height: auto; }
+.title-page { text-align: center; padding: 4em 0; }
+.chapter { page-break-before: always; }
+</style>
+</head>
+<body>
+<section class="title-page">
+<h1>{{ .Title }}</h1>
+{{- if .Authors }}
+<p class="authors">{{ range $i, $a := .Authors }}{{ if $i }}, {{ end }}{{ $a }}{{ end }}</p>
+{{- end }}
+<p class="site">{{ .Site }}</p>
+{{- if .Date }}
+<p class="date">{{ .Date }}</p>
+{{- end }}
+</section>
+<nav class="toc">
+<h2>Contents</h2>
+<ol>
+{{- range .Chapters }}
+<li><a href="#{{ .ID }}">{{ .Title }}</a></li>
+{{- end }}
+</ol>
+</nav>
+{{- range .Chapters }}
+<section class="chapter" id="{{ .ID }}">
+<h1>{{ .Title }}</h1>
+{{ .Body }}
+</section>
+{{- end }}
+</body>
+</html>
+`))
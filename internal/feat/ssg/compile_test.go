@@ -0,0 +1,149 @@
+package ssg
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cliossg/clio/internal/testutil"
+	"github.com/cliossg/clio/pkg/cl/config"
+)
+
+func TestParseBookFormat(t *testing.T) {
+	for in, want := range map[string]BookFormat{"": BookEPUB, "epub": BookEPUB, " HTML ": BookHTML} {
+		if got, err := ParseBookFormat(in); err != nil || got != want {
+			t.Errorf("ParseBookFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseBookFormat("pdf"); err == nil {
+		t.Error("ParseBookFormat(pdf) should fail")
+	}
+}
+
+func TestServiceCompileSeries(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, cfg, newTestLogger())
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Travel Notes", "travel-notes")
+	svc.CreateSection(ctx, NewSection(site.ID, "Places", "", "places"))
+	section, _ := svc.GetSectionByPath(ctx, site.ID, "places")
+
+	imagesPath := NewWorkspace(cfg.SSG.SitesBasePath).GetImagesPath(site.Slug)
+	if err := os.MkdirAll(imagesPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	photo := []byte("\x89PNG fake image data")
+	if err := os.WriteFile(filepath.Join(imagesPath, "photo.png"), photo, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	published := time.Now().Add(-24 * time.Hour)
+	chapter := func(heading, body string, order int, draft bool) {
+		c := NewContent(site.ID, section.ID, heading, body)
+		c.Draft = draft
+		c.Series = "Around the World"
+		c.SeriesOrder = order
+		c.PublishedAt = &published
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+	}
+	chapter("Second Stop & More", "Rain in <em>Lima</em>.", 2, false)
+	chapter("First Stop", "Arrival.\n\n![Harbour](/images/photo.png)\n\n![Gone](/images/missing.png)", 1, false)
+	chapter("Unfinished", "Draft.", 3, true)
+
+	data, err := svc.CompileSeries(ctx, site.ID, "Around the World", BookEPUB)
+	if err != nil {
+		t.Fatalf("CompileSeries() error = %v", err)
+	}
+	files := readZip(t, data)
+
+	zr, _ := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if first := zr.File[0]; first.Name != "mimetype" || first.Method != zip.Store || files["mimetype"] != "application/epub+zip" {
+		t.Errorf("first entry = %s (method %d), want stored mimetype", first.Name, first.Method)
+	}
+	if !strings.Contains(files["META-INF/container.xml"], `full-path="OEBPS/content.opf"`) {
+		t.Error("container.xml does not point to the package document")
+	}
+
+	opf := files["OEBPS/content.opf"]
+	for _, want := range []string{"<dc:title>Around the World</dc:title>", "<dc:publisher>Travel Notes</dc:publisher>", `href="images/photo.png" media-type="image/png"`, `<itemref idref="chapter-002"/>`} {
+		if !strings.Contains(opf, want) {
+			t.Errorf("content.opf missing %q:\n%s", want, opf)
+		}
+	}
+	if strings.Contains(opf, "chapter-003") {
+		t.Error("draft content should not be compiled")
+	}
+
+	nav := files["OEBPS/nav.xhtml"]
+	if first, second := strings.Index(nav, "First Stop"), strings.Index(nav, "Second Stop &amp; More"); first < 0 || second < first {
+		t.Errorf("table of contents not in series order:\n%s", nav)
+	}
+	if !strings.Contains(files["OEBPS/title.xhtml"], "<h1>Around the World</h1>") {
+		t.Errorf("title page missing title:\n%s", files["OEBPS/title.xhtml"])
+	}
+	ch1 := files["OEBPS/chapter-001.xhtml"]
+	if !strings.Contains(ch1, `src="images/photo.png"`) || !strings.Contains(ch1, `src="/images/missing.png"`) {
+		t.Errorf("chapter images not rewritten as expected:\n%s", ch1)
+	}
+	if files["OEBPS/images/photo.png"] != string(photo) {
+		t.Error("image not embedded")
+	}
+
+	html, err := svc.CompileSeries(ctx, site.ID, "Around the World", BookHTML)
+	if err != nil {
+		t.Fatalf("CompileSeries(html) error = %v", err)
+	}
+	for _, want := range []string{`<a href="#chapter-001">First Stop</a>`, `src="data:image/png;base64,`, "Rain in <em>Lima</em>."} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("HTML book missing %q", want)
+		}
+	}
+
+	if _, err := svc.CompileSeries(ctx, site.ID, "No Such Series", BookEPUB); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CompileSeries() for an empty series error = %v, want ErrNotFound", err)
+	}
+
+	sectionBook, err := svc.CompileSection(ctx, site.ID, section.ID, BookEPUB)
+	if err != nil {
+		t.Fatalf("CompileSection() error = %v", err)
+	}
+	if opf := readZip(t, sectionBook)["OEBPS/content.opf"]; !strings.Contains(opf, "<dc:title>Places</dc:title>") || !strings.Contains(opf, "chapter-002") {
+		t.Errorf("section book content.opf:\n%s", opf)
+	}
+}
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("not a zip file: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	return files
+}
@@ -29,6 +29,12 @@ func siteFromSQLC(s sqlc.Site) *Site {
 	if s.LastPublishedAt.Valid {
 		site.LastPublishedAt = &s.LastPublishedAt.Time
 	}
+	if s.LastPublishedCommit.Valid {
+		site.LastPublishedCommit = s.LastPublishedCommit.String
+	}
+	if s.LastGeneratedAt.Valid {
+		site.LastGeneratedAt = &s.LastGeneratedAt.Time
+	}
 	return site
 }
 
@@ -36,17 +42,23 @@ func siteFromSQLC(s sqlc.Site) *Site {
 
 func contentFromSQLC(c sqlc.Content) *Content {
 	content := &Content{
-		ID:            parseUUID(c.ID),
-		SiteID:        parseUUID(c.SiteID),
-		ShortID:       c.ShortID.String,
-		Heading:       c.Heading,
-		Summary:       c.Summary.String,
-		Body:          c.Body.String,
-		Draft:         intToBool(c.Draft.Int64),
-		Featured:      intToBool(c.Featured.Int64),
-		Series:        c.Series.String,
-		Kind:          c.Kind.String,
-		HeroTitleDark: intToBool(c.HeroTitleDark.Int64),
+		ID:                parseUUID(c.ID),
+		SiteID:            parseUUID(c.SiteID),
+		ShortID:           c.ShortID.String,
+		ContributorHandle: c.ContributorHandle,
+		AuthorUsername:    c.AuthorUsername,
+		Heading:           c.Heading,
+		Summary:           c.Summary.String,
+		Body:              c.Body.String,
+		Draft:             intToBool(c.Draft.Int64),
+		Featured:          intToBool(c.Featured.Int64),
+		Series:            c.Series.String,
+		Kind:              c.Kind.String,
+		HeroTitleDark:     intToBool(c.HeroTitleDark.Int64),
+		Visibility:        normalizeVisibility(c.Visibility),
+		Lang:              c.Lang,
+		TranslationGroup:  c.TranslationGroup,
+		LinkURL:           c.LinkURL,
 	}
 
 	if c.UserID.Valid {
@@ -80,23 +92,141 @@ func contentFromSQLC(c sqlc.Content) *Content {
 	if c.ImagesMeta.Valid {
 		content.ImagesMeta = c.ImagesMeta.String
 	}
+	if c.LayoutID.Valid {
+		content.LayoutID = parseUUID(c.LayoutID.String)
+	}
 
 	return content
 }
 
+func contentRevisionFromSQLC(r sqlc.ContentRevision) *ContentRevision {
+	return &ContentRevision{
+		ID:        parseUUID(r.ID),
+		ContentID: parseUUID(r.ContentID),
+		Heading:   r.Heading,
+		Summary:   r.Summary,
+		Body:      r.Body,
+		CreatedBy: parseUUID(r.CreatedBy),
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func editLockFromSQLC(l sqlc.ContentEditLock) *EditLock {
+	return &EditLock{
+		ContentID:  parseUUID(l.ContentID),
+		UserID:     parseUUID(l.UserID),
+		UserName:   l.UserName,
+		AcquiredAt: l.AcquiredAt,
+		ExpiresAt:  l.ExpiresAt,
+	}
+}
+
+func publishJobFromSQLC(j sqlc.PublishJob) *PublishJob {
+	job := &PublishJob{
+		ID:         parseUUID(j.ID),
+		SiteID:     parseUUID(j.SiteID),
+		Status:     j.Status,
+		Progress:   j.Progress,
+		CommitHash: j.CommitHash,
+		CommitURL:  j.CommitUrl,
+		NoChanges:  j.NoChanges != 0,
+		Error:      j.Error,
+		CreatedBy:  parseUUID(j.CreatedBy),
+		CreatedAt:  j.CreatedAt,
+	}
+	if j.StartedAt.Valid {
+		job.StartedAt = &j.StartedAt.Time
+	}
+	if j.FinishedAt.Valid {
+		job.FinishedAt = &j.FinishedAt.Time
+	}
+	return job
+}
+
+func contentKindFromSQLC(k sqlc.ContentKind) *ContentKind {
+	kind := &ContentKind{
+		ID:        parseUUID(k.ID),
+		SiteID:    parseUUID(k.SiteID),
+		Name:      k.Name,
+		Label:     k.Label,
+		Permalink: k.Permalink,
+		Listed:    k.Listed == 1,
+		Feed:      k.Feed == 1,
+		Redirect:  k.Redirect == 1,
+		CreatedAt: k.CreatedAt,
+		UpdatedAt: k.UpdatedAt,
+	}
+	if k.LayoutID.Valid {
+		kind.LayoutID = parseUUID(k.LayoutID.String)
+	}
+	return kind
+}
+
+func contentFieldFromSQLC(f sqlc.ContentField) *ContentField {
+	return &ContentField{
+		ID:        parseUUID(f.ID),
+		ContentID: parseUUID(f.ContentID),
+		Name:      f.Name,
+		Type:      f.Type,
+		Value:     f.Value,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+func contentReviewFromSQLC(r sqlc.GetContentReviewRow) *ContentReview {
+	review := &ContentReview{
+		ContentID:     parseUUID(r.ContentID),
+		Status:        r.Status,
+		ReviewerID:    parseUUID(r.ReviewerID),
+		SubmittedBy:   parseUUID(r.SubmittedBy),
+		Comment:       r.Comment,
+		SubmittedAt:   r.SubmittedAt,
+		UpdatedAt:     r.UpdatedAt,
+		ReviewerName:  r.ReviewerName,
+		SubmitterName: r.SubmitterName,
+	}
+	if r.ReviewedAt.Valid {
+		review.ReviewedAt = &r.ReviewedAt.Time
+	}
+	return review
+}
+
+func openContentReviewFromSQLC(r sqlc.GetOpenContentReviewsBySiteIDRow) *ContentReview {
+	review := contentReviewFromSQLC(sqlc.GetContentReviewRow{
+		ContentID:     r.ContentID,
+		Status:        r.Status,
+		ReviewerID:    r.ReviewerID,
+		SubmittedBy:   r.SubmittedBy,
+		Comment:       r.Comment,
+		SubmittedAt:   r.SubmittedAt,
+		ReviewedAt:    r.ReviewedAt,
+		UpdatedAt:     r.UpdatedAt,
+		ReviewerName:  r.ReviewerName,
+		SubmitterName: r.SubmitterName,
+	})
+	review.ContentHeading = r.Heading
+	review.ContentShortID = r.ShortID.String
+	return review
+}
+
 func contentWithMetaFromSQLC(row sqlc.GetContentWithMetaRow) *Content {
 	content := &Content{
-		ID:            parseUUID(row.ID),
-		SiteID:        parseUUID(row.SiteID),
-		ShortID:       row.ShortID.String,
-		Heading:       row.Heading,
-		Summary:       row.Summary.String,
-		Body:          row.Body.String,
-		Draft:         intToBool(row.Draft.Int64),
-		Featured:      intToBool(row.Featured.Int64),
-		Series:        row.Series.String,
-		Kind:          row.Kind.String,
-		HeroTitleDark: intToBool(row.HeroTitleDark.Int64),
+		ID:               parseUUID(row.ID),
+		SiteID:           parseUUID(row.SiteID),
+		ShortID:          row.ShortID.String,
+		Heading:          row.Heading,
+		Summary:          row.Summary.String,
+		Body:             row.Body.String,
+		Draft:            intToBool(row.Draft.Int64),
+		Featured:         intToBool(row.Featured.Int64),
+		Series:           row.Series.String,
+		Kind:             row.Kind.String,
+		HeroTitleDark:    intToBool(row.HeroTitleDark.Int64),
+		Visibility:       normalizeVisibility(row.Visibility),
+		Lang:             row.Lang,
+		TranslationGroup: row.TranslationGroup,
+		LinkURL:          row.LinkURL,
 	}
 
 	if row.UserID.Valid {
@@ -147,6 +277,9 @@ func contentWithMetaFromSQLC(row sqlc.GetContentWithMetaRow) *Content {
 	if row.ImagesMeta.Valid {
 		content.ImagesMeta = row.ImagesMeta.String
 	}
+	if row.LayoutID.Valid {
+		content.LayoutID = parseUUID(row.LayoutID.String)
+	}
 	content.ContributorHandle = row.ContributorHandle
 	content.AuthorUsername = row.AuthorUsername
 
@@ -155,17 +288,21 @@ func contentWithMetaFromSQLC(row sqlc.GetContentWithMetaRow) *Content {
 
 func contentWithMetaFromSQLCAll(row sqlc.GetAllContentWithMetaRow) *Content {
 	content := &Content{
-		ID:            parseUUID(row.ID),
-		SiteID:        parseUUID(row.SiteID),
-		ShortID:       row.ShortID.String,
-		Heading:       row.Heading,
-		Summary:       row.Summary.String,
-		Body:          row.Body.String,
-		Draft:         intToBool(row.Draft.Int64),
-		Featured:      intToBool(row.Featured.Int64),
-		Series:        row.Series.String,
-		Kind:          row.Kind.String,
-		HeroTitleDark: intToBool(row.HeroTitleDark.Int64),
+		ID:               parseUUID(row.ID),
+		SiteID:           parseUUID(row.SiteID),
+		ShortID:          row.ShortID.String,
+		Heading:          row.Heading,
+		Summary:          row.Summary.String,
+		Body:             row.Body.String,
+		Draft:            intToBool(row.Draft.Int64),
+		Featured:         intToBool(row.Featured.Int64),
+		Series:           row.Series.String,
+		Kind:             row.Kind.String,
+		HeroTitleDark:    intToBool(row.HeroTitleDark.Int64),
+		Visibility:       normalizeVisibility(row.Visibility),
+		Lang:             row.Lang,
+		TranslationGroup: row.TranslationGroup,
+		LinkURL:          row.LinkURL,
 	}
 
 	if row.UserID.Valid {
@@ -200,13 +337,24 @@ func contentWithMetaFromSQLCAll(row sqlc.GetAllContentWithMetaRow) *Content {
 	if row.SectionName.Valid {
 		content.SectionName = row.SectionName.String
 	}
+	if row.ReviewStatus.Valid {
+		content.ReviewStatus = row.ReviewStatus.String
+	}
 
 	// Meta fields
-	if row.MetaSummary.Valid || row.MetaDescription.Valid || row.MetaKeywords.Valid {
+	if row.MetaSummary.Valid || row.MetaDescription.Valid || row.MetaKeywords.Valid || row.MetaTableOfContents.Valid {
 		content.Meta = &Meta{
-			Summary:     row.MetaSummary.String,
-			Description: row.MetaDescription.String,
-			Keywords:    row.MetaKeywords.String,
+			Summary:         row.MetaSummary.String,
+			Description:     row.MetaDescription.String,
+			Keywords:        row.MetaKeywords.String,
+			Robots:          row.MetaRobots.String,
+			CanonicalURL:    row.MetaCanonicalUrl.String,
+			Sitemap:         row.MetaSitemap.String,
+			TableOfContents: intToBool(row.MetaTableOfContents.Int64),
+			Share:           intToBool(row.MetaShare.Int64),
+			Comments:        intToBool(row.MetaComments.Int64),
+			KeepLinks:       intToBool(row.MetaKeepLinks.Int64),
+			ShowUpdated:     intToBool(row.MetaShowUpdated.Int64),
 		}
 	}
 	if row.ContributorID.Valid {
@@ -231,9 +379,14 @@ func contentWithMetaFromSQLCAll(row sqlc.GetAllContentWithMetaRow) *Content {
 	if row.HeaderImageAttributionUrl.Valid {
 		content.HeaderImageAttributionURL = row.HeaderImageAttributionUrl.String
 	}
+	content.HeaderImageWidth = int(row.HeaderImageWidth.Int64)
+	content.HeaderImageHeight = int(row.HeaderImageHeight.Int64)
 	if row.ImagesMeta.Valid {
 		content.ImagesMeta = row.ImagesMeta.String
 	}
+	if row.LayoutID.Valid {
+		content.LayoutID = parseUUID(row.LayoutID.String)
+	}
 
 	return content
 }
@@ -323,17 +476,48 @@ func layoutFromSQLC(l sqlc.Layout) *Layout {
 
 // Tag converters
 
+func collectionFromSQLC(c sqlc.Collection) *Collection {
+	collection := &Collection{
+		ID:     parseUUID(c.ID),
+		SiteID: parseUUID(c.SiteID),
+		Name:   c.Name,
+		Slug:   c.Slug,
+	}
+
+	if c.ShortID.Valid {
+		collection.ShortID = c.ShortID.String
+	}
+	if c.CreatedBy.Valid {
+		collection.CreatedBy = parseUUID(c.CreatedBy.String)
+	}
+	if c.UpdatedBy.Valid {
+		collection.UpdatedBy = parseUUID(c.UpdatedBy.String)
+	}
+	if c.CreatedAt.Valid {
+		collection.CreatedAt = c.CreatedAt.Time
+	}
+	if c.UpdatedAt.Valid {
+		collection.UpdatedAt = c.UpdatedAt.Time
+	}
+
+	return collection
+}
+
 func tagFromSQLC(t sqlc.Tag) *Tag {
 	tag := &Tag{
-		ID:     parseUUID(t.ID),
-		SiteID: parseUUID(t.SiteID),
-		Name:   t.Name,
-		Slug:   t.Slug,
+		ID:          parseUUID(t.ID),
+		SiteID:      parseUUID(t.SiteID),
+		Name:        t.Name,
+		Slug:        t.Slug,
+		Description: t.Description,
 	}
 
 	if t.ShortID.Valid {
 		tag.ShortID = t.ShortID.String
 	}
+	if t.HeaderImageID.Valid {
+		tag.HeaderImageID = parseUUID(t.HeaderImageID.String)
+	}
 	if t.CreatedBy.Valid {
 		tag.CreatedBy = parseUUID(t.CreatedBy.String)
 	}
@@ -350,6 +534,26 @@ func tagFromSQLC(t sqlc.Tag) *Tag {
 	return tag
 }
 
+func tagFromGetTagsForContentRow(row sqlc.GetTagsForContentRow) *Tag {
+	tag := tagFromSQLC(sqlc.Tag{
+		ID:            row.ID,
+		SiteID:        row.SiteID,
+		ShortID:       row.ShortID,
+		Name:          row.Name,
+		Slug:          row.Slug,
+		Description:   row.Description,
+		HeaderImageID: row.HeaderImageID,
+		CreatedBy:     row.CreatedBy,
+		UpdatedBy:     row.UpdatedBy,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	})
+	if row.HeaderImagePath.Valid {
+		tag.HeaderImageURL = "/images/" + row.HeaderImagePath.String
+	}
+	return tag
+}
+
 // Setting converters
 
 func settingFromSQLC(s sqlc.Setting) *Setting {
@@ -403,6 +607,9 @@ func settingFromSQLC(s sqlc.Setting) *Setting {
 	if s.UiControl.Valid {
 		setting.UIControl = s.UiControl.String
 	}
+	if s.Secret.Valid {
+		setting.Secret = intToBool(s.Secret.Int64)
+	}
 
 	return setting
 }
@@ -415,6 +622,7 @@ func imageFromSQLC(i sqlc.Image) *Image {
 		SiteID:   parseUUID(i.SiteID),
 		FileName: i.FileName,
 		FilePath: i.FilePath,
+		Stock:    i.Stock == 1,
 	}
 
 	if i.ShortID.Valid {
@@ -496,6 +704,12 @@ func metaFromSQLC(m sqlc.Meta) *Meta {
 	if m.Comments.Valid {
 		meta.Comments = m.Comments.Int64 == 1
 	}
+	if m.KeepLinks.Valid {
+		meta.KeepLinks = m.KeepLinks.Int64 == 1
+	}
+	if m.ShowUpdated.Valid {
+		meta.ShowUpdated = m.ShowUpdated.Int64 == 1
+	}
 	if m.CreatedBy.Valid {
 		meta.CreatedBy = parseUUID(m.CreatedBy.String)
 	}
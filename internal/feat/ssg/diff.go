@@ -0,0 +1,213 @@
+package ssg
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Diff operations. A change row pairs a removed line with the line that
+// replaced it; its spans mark the words that differ.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+	DiffChange = "change"
+)
+
+// RevisionDiff compares two versions of a content, field by field. Either
+// side may be the live content (see ContentRevision.IsCurrent).
+type RevisionDiff struct {
+	ContentID uuid.UUID
+	A, B      *ContentRevision
+	Fields    []FieldDiff
+}
+
+// Changed reports whether any field differs.
+func (d *RevisionDiff) Changed() bool {
+	for _, f := range d.Fields {
+		if f.Changed {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldDiff is the line diff of one content field.
+type FieldDiff struct {
+	Field   string // heading, summary or body
+	Label   string
+	Changed bool
+	Lines   []DiffLine
+}
+
+// DiffLine is a row of a side-by-side diff. Equal and change rows have both
+// sides, insert rows only New and delete rows only Old. Line numbers are
+// 1-based, 0 for a missing side.
+type DiffLine struct {
+	Op     string
+	OldNum int
+	NewNum int
+	Old    []DiffSpan
+	New    []DiffSpan
+}
+
+// DiffSpan is a run of text with the operation that produced it.
+type DiffSpan struct {
+	Op   string
+	Text string
+}
+
+func diffRevisions(a, b *ContentRevision) *RevisionDiff {
+	d := &RevisionDiff{ContentID: a.ContentID, A: a, B: b}
+	for _, f := range []struct{ field, label, old, new string }{
+		{"heading", "Heading", a.Heading, b.Heading},
+		{"summary", "Summary", a.Summary, b.Summary},
+		{"body", "Body", a.Body, b.Body},
+	} {
+		lines := diffLines(f.old, f.new)
+		changed := false
+		for _, l := range lines {
+			if l.Op != DiffEqual {
+				changed = true
+				break
+			}
+		}
+		d.Fields = append(d.Fields, FieldDiff{Field: f.field, Label: f.label, Changed: changed, Lines: lines})
+	}
+	return d
+}
+
+// diffLines diffs a and b line by line, then word by word within each pair
+// of removed and added lines.
+func diffLines(a, b string) []DiffLine {
+	dmp := diffmatchpatch.New()
+	ra, rb, lineArray := dmp.DiffLinesToRunes(normalizeDiffText(a), normalizeDiffText(b))
+	diffs := dmp.DiffCharsToLines(dmp.DiffMainRunes(ra, rb, false), lineArray)
+
+	var (
+		rows              []DiffLine
+		oldNum, newNum    int
+		deleted, inserted []string
+	)
+	flush := func() {
+		n := max(len(deleted), len(inserted))
+		for i := 0; i < n; i++ {
+			row := DiffLine{}
+			switch {
+			case i < len(deleted) && i < len(inserted):
+				oldNum++
+				newNum++
+				row = DiffLine{Op: DiffChange, OldNum: oldNum, NewNum: newNum}
+				row.Old, row.New = diffWords(deleted[i], inserted[i])
+			case i < len(deleted):
+				oldNum++
+				row = DiffLine{Op: DiffDelete, OldNum: oldNum, Old: []DiffSpan{{DiffDelete, deleted[i]}}}
+			default:
+				newNum++
+				row = DiffLine{Op: DiffInsert, NewNum: newNum, New: []DiffSpan{{DiffInsert, inserted[i]}}}
+			}
+			rows = append(rows, row)
+		}
+		deleted, inserted = nil, nil
+	}
+
+	for _, diff := range diffs {
+		lines := splitDiffLines(diff.Text)
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			deleted = append(deleted, lines...)
+		case diffmatchpatch.DiffInsert:
+			inserted = append(inserted, lines...)
+		default:
+			flush()
+			for _, line := range lines {
+				oldNum++
+				newNum++
+				span := []DiffSpan{{DiffEqual, line}}
+				rows = append(rows, DiffLine{Op: DiffEqual, OldNum: oldNum, NewNum: newNum, Old: span, New: span})
+			}
+		}
+	}
+	flush()
+	return rows
+}
+
+// normalizeDiffText ends every line, the last included, with \n so a missing
+// final newline doesn't show as a changed line.
+func normalizeDiffText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	return s
+}
+
+func splitDiffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffTokenRe splits text into words, runs of whitespace and single other
+// characters, the units of a word diff.
+var diffTokenRe = regexp.MustCompile(`[\p{L}\p{N}_]+|\s+|.`)
+
+// maxDiffTokens keeps token runes below the surrogate range.
+const maxDiffTokens = 0xD000
+
+// diffWords returns the spans of a and b, marking deleted and inserted words.
+func diffWords(a, b string) (old, new []DiffSpan) {
+	tokens := []string{""}
+	index := make(map[string]rune)
+	encode := func(s string) []rune {
+		var runes []rune
+		for _, tok := range diffTokenRe.FindAllString(s, -1) {
+			r, ok := index[tok]
+			if !ok {
+				r = rune(len(tokens))
+				index[tok] = r
+				tokens = append(tokens, tok)
+			}
+			runes = append(runes, r)
+		}
+		return runes
+	}
+	ra, rb := encode(a), encode(b)
+	if len(tokens) > maxDiffTokens {
+		return []DiffSpan{{DiffDelete, a}}, []DiffSpan{{DiffInsert, b}}
+	}
+
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffCleanupSemantic(dmp.DiffMainRunes(ra, rb, false))
+	for _, diff := range diffs {
+		var text strings.Builder
+		for _, r := range diff.Text {
+			text.WriteString(tokens[r])
+		}
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			old = appendSpan(old, DiffDelete, text.String())
+		case diffmatchpatch.DiffInsert:
+			new = appendSpan(new, DiffInsert, text.String())
+		default:
+			old = appendSpan(old, DiffEqual, text.String())
+			new = appendSpan(new, DiffEqual, text.String())
+		}
+	}
+	return old, new
+}
+
+func appendSpan(spans []DiffSpan, op, text string) []DiffSpan {
+	if text == "" {
+		return spans
+	}
+	if n := len(spans); n > 0 && spans[n-1].Op == op {
+		spans[n-1].Text += text
+		return spans
+	}
+	return append(spans, DiffSpan{Op: op, Text: text})
+}
@@ -0,0 +1,46 @@
+package ssg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	old := "Intro line\nThe quick brown fox\nRemoved line\nOutro"
+	new := "Intro line\nThe quick red fox\nOutro\nAdded line\n"
+
+	got := diffLines(old, new)
+	want := []DiffLine{
+		{Op: DiffEqual, OldNum: 1, NewNum: 1, Old: []DiffSpan{{DiffEqual, "Intro line"}}, New: []DiffSpan{{DiffEqual, "Intro line"}}},
+		{Op: DiffChange, OldNum: 2, NewNum: 2,
+			Old: []DiffSpan{{DiffEqual, "The quick "}, {DiffDelete, "brown"}, {DiffEqual, " fox"}},
+			New: []DiffSpan{{DiffEqual, "The quick "}, {DiffInsert, "red"}, {DiffEqual, " fox"}}},
+		{Op: DiffDelete, OldNum: 3, Old: []DiffSpan{{DiffDelete, "Removed line"}}},
+		{Op: DiffEqual, OldNum: 4, NewNum: 3, Old: []DiffSpan{{DiffEqual, "Outro"}}, New: []DiffSpan{{DiffEqual, "Outro"}}},
+		{Op: DiffInsert, NewNum: 4, New: []DiffSpan{{DiffInsert, "Added line"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffLines() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	a := &ContentRevision{Heading: "Title", Summary: "", Body: "one\r\ntwo"}
+	b := &ContentRevision{Heading: "Title", Summary: "New summary", Body: "one\ntwo\n"}
+
+	d := diffRevisions(a, b)
+	changed := map[string]bool{}
+	for _, f := range d.Fields {
+		changed[f.Field] = f.Changed
+	}
+	want := map[string]bool{"heading": false, "summary": true, "body": false}
+	if !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed fields = %v, want %v", changed, want)
+	}
+	if !d.Changed() {
+		t.Error("Changed() = false, want true")
+	}
+	if diffRevisions(a, a).Changed() {
+		t.Error("Changed() for identical versions = true")
+	}
+}
@@ -2,6 +2,8 @@ package fake
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/cliossg/clio/internal/feat/ssg"
 	"github.com/google/uuid"
@@ -52,6 +54,12 @@ func (s *Service) GetSettingByRefKey(_ context.Context, siteID uuid.UUID, refKey
 	}
 	return nil, nil
 }
+func (s *Service) GetBaseURL(_ context.Context, _ uuid.UUID) (string, error) {
+	return "", nil
+}
+func (s *Service) GetTimezone(_ context.Context, _ uuid.UUID) (*time.Location, error) {
+	return time.UTC, nil
+}
 
 func (s *Service) GetAllContentWithMeta(_ context.Context, siteID uuid.UUID) ([]*ssg.Content, error) {
 	return s.Contents[siteID], s.GetAllContentErr
@@ -68,6 +76,20 @@ func (s *Service) GetLayouts(_ context.Context, siteID uuid.UUID) ([]*ssg.Layout
 func (s *Service) GetSettings(_ context.Context, siteID uuid.UUID) ([]*ssg.Setting, error) {
 	return s.Settings[siteID], nil
 }
+func (s *Service) IsFeatureEnabled(_ context.Context, siteID uuid.UUID, key string) (bool, error) {
+	for _, setting := range s.Settings[siteID] {
+		if setting.RefKey == key {
+			return setting.Value != "false", nil
+		}
+	}
+	return true, nil
+}
+func (s *Service) GetSettingsByCategory(_ context.Context, _ uuid.UUID) ([]*ssg.SettingCategory, error) {
+	return nil, nil
+}
+func (s *Service) ReorderSettings(_ context.Context, _ uuid.UUID, _ string, _ []uuid.UUID) error {
+	return nil
+}
 
 func (s *Service) GetContributors(_ context.Context, siteID uuid.UUID) ([]*ssg.Contributor, error) {
 	return s.Contributors[siteID], nil
@@ -88,16 +110,122 @@ func (s *Service) CreateSite(_ context.Context, _ *ssg.Site) error
 func (s *Service) GetSite(_ context.Context, _ uuid.UUID) (*ssg.Site, error)          { return nil, nil }
 func (s *Service) GetSiteBySlug(_ context.Context, _ string) (*ssg.Site, error)       { return nil, nil }
 func (s *Service) DeleteSite(_ context.Context, _ uuid.UUID) error                    { return nil }
+func (s *Service) CloneSite(_ context.Context, _ uuid.UUID, name, slug string, _ ssg.CloneOptions) (*ssg.Site, error) {
+	return ssg.NewSite(name, slug), nil
+}
+func (s *Service) MarkSiteGenerated(_ context.Context, _ uuid.UUID, _ time.Time) error {
+	return nil
+}
+func (s *Service) MarkSitePublished(_ context.Context, _ uuid.UUID, _ time.Time, _ string) error {
+	return nil
+}
+func (s *Service) CreatePublishJob(_ context.Context, siteID, userID uuid.UUID) (*ssg.PublishJob, error) {
+	return &ssg.PublishJob{ID: uuid.New(), SiteID: siteID, Status: ssg.PublishJobQueued, CreatedBy: userID}, nil
+}
+func (s *Service) GetPublishJob(_ context.Context, _ uuid.UUID) (*ssg.PublishJob, error) {
+	return nil, nil
+}
+func (s *Service) ListPublishJobs(_ context.Context, _ uuid.UUID) ([]*ssg.PublishJob, error) {
+	return nil, nil
+}
+func (s *Service) UpdatePublishJob(_ context.Context, _ *ssg.PublishJob) error { return nil }
+func (s *Service) FailUnfinishedPublishJobs(_ context.Context, _ string) (int64, error) {
+	return 0, nil
+}
+func (s *Service) LintAccessibility(_ context.Context, _ uuid.UUID) (*ssg.A11yReport, error) {
+	return &ssg.A11yReport{}, nil
+}
+func (s *Service) ValidateFeeds(_ context.Context, _ uuid.UUID) (*ssg.FeedReport, error) {
+	return &ssg.FeedReport{}, nil
+}
+func (s *Service) GetContentEditedSince(_ context.Context, _ uuid.UUID, _ time.Time) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) FindStaleDrafts(_ context.Context, _ uuid.UUID, _ time.Duration) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) GetContentByDateRange(_ context.Context, _ uuid.UUID, _, _ time.Time) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) GetContentByContributor(_ context.Context, _ uuid.UUID) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) GetContentByKind(_ context.Context, _ uuid.UUID, _ string) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) GetFeaturedContent(_ context.Context, _ uuid.UUID, _ int) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) GetChangelogEntries(_ context.Context, _ uuid.UUID) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) GetTranslations(_ context.Context, _ uuid.UUID) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) CreateTranslationDraft(_ context.Context, _ uuid.UUID, _ string) (*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) DeriveSummary(_ context.Context, content *ssg.Content, _ ssg.SummaryOptions) (string, error) {
+	return content.Summary, nil
+}
+func (s *Service) ListContentRevisions(_ context.Context, _ uuid.UUID) ([]*ssg.ContentRevision, error) {
+	return nil, nil
+}
+func (s *Service) GetContentRevision(_ context.Context, _ uuid.UUID) (*ssg.ContentRevision, error) {
+	return nil, nil
+}
+func (s *Service) DiffRevisions(_ context.Context, _, _, _ uuid.UUID) (*ssg.RevisionDiff, error) {
+	return &ssg.RevisionDiff{}, nil
+}
+func (s *Service) AcquireEditLock(_ context.Context, contentID, userID uuid.UUID, userName string) (*ssg.EditLock, error) {
+	return &ssg.EditLock{ContentID: contentID, UserID: userID, UserName: userName}, nil
+}
+func (s *Service) GetEditLock(_ context.Context, _ uuid.UUID) (*ssg.EditLock, error) {
+	return nil, ssg.ErrNotFound
+}
+func (s *Service) ReleaseEditLock(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
+func (s *Service) BreakEditLock(_ context.Context, _ uuid.UUID) error {
+	return nil
+}
+func (s *Service) GetSiteStats(_ context.Context, _ uuid.UUID) (*ssg.SiteStats, error) {
+	return &ssg.SiteStats{}, nil
+}
 func (s *Service) CreateContent(_ context.Context, _ *ssg.Content) error              { return nil }
 func (s *Service) GetContent(_ context.Context, _ uuid.UUID) (*ssg.Content, error)    { return nil, nil }
 func (s *Service) GetContentWithMeta(_ context.Context, _ uuid.UUID) (*ssg.Content, error) {
 	return nil, nil
 }
-func (s *Service) GetContentWithPagination(_ context.Context, _ uuid.UUID, _, _ int, _ string) ([]*ssg.Content, int, error) {
+func (s *Service) GetContentWithPagination(_ context.Context, _ uuid.UUID, _, _ int, _ ssg.ContentFilter) ([]*ssg.Content, int, error) {
 	return nil, 0, nil
 }
+func (s *Service) ContentOutputPath(_ context.Context, _ *ssg.Content) (string, error) {
+	return "", nil
+}
+func (s *Service) GetPublicURL(_ context.Context, _ *ssg.Content) (*ssg.PublicURL, error) {
+	return &ssg.PublicURL{}, nil
+}
+func (s *Service) CheckPathCollision(_ context.Context, _ uuid.UUID, _ string, _ uuid.UUID) ([]*ssg.PathCollision, error) {
+	return nil, nil
+}
+func (s *Service) IsReservedPath(_ context.Context, _ uuid.UUID, _ string) (string, bool, error) {
+	return "", false, nil
+}
+func (s *Service) GetAdjacentContent(_ context.Context, _, _ uuid.UUID) (*ssg.Content, *ssg.Content, error) {
+	return nil, nil, nil
+}
 func (s *Service) UpdateContent(_ context.Context, _ *ssg.Content) error { return nil }
+func (s *Service) ReconcileAutosave(_ context.Context, _ ssg.AutosaveDraft) (*ssg.AutosaveReconcile, error) {
+	return nil, nil
+}
+func (s *Service) FindAndReplace(_ context.Context, _ uuid.UUID, find, replace string, opts ssg.FindReplaceOptions) (*ssg.FindReplaceResult, error) {
+	return &ssg.FindReplaceResult{Find: find, Replace: replace, Options: opts}, nil
+}
 func (s *Service) DeleteContent(_ context.Context, _ uuid.UUID) error    { return nil }
+func (s *Service) MoveContent(_ context.Context, _, _, _ uuid.UUID) (*ssg.ContentMove, error) {
+	return &ssg.ContentMove{}, nil
+}
 func (s *Service) CreateSection(_ context.Context, _ *ssg.Section) error { return nil }
 func (s *Service) GetSection(_ context.Context, _ uuid.UUID) (*ssg.Section, error) {
 	return nil, nil
@@ -113,6 +241,56 @@ func (s *Service) GetLayout(_ context.Context, _ uuid.UUID) (*ssg.Layout, error)
 }
 func (s *Service) UpdateLayout(_ context.Context, _ *ssg.Layout) error { return nil }
 func (s *Service) DeleteLayout(_ context.Context, _ uuid.UUID) error   { return nil }
+func (s *Service) ExportLayout(_ context.Context, _, _ uuid.UUID) (*ssg.LayoutExport, error) {
+	return nil, nil
+}
+func (s *Service) ImportLayout(_ context.Context, _ uuid.UUID, _ *ssg.LayoutExport, _ uuid.UUID) (*ssg.Layout, error) {
+	return nil, nil
+}
+func (s *Service) ResolveLayoutForContent(_ context.Context, _ *ssg.Content) (*ssg.Layout, error) {
+	return ssg.BuiltinLayout(), nil
+}
+func (s *Service) ListContentKinds(_ context.Context, _ uuid.UUID) ([]*ssg.ContentKind, error) {
+	return ssg.DefaultContentKinds(), nil
+}
+func (s *Service) GetContentKind(_ context.Context, _ uuid.UUID, _ string) (*ssg.ContentKind, error) {
+	return nil, ssg.ErrNotFound
+}
+func (s *Service) SaveContentKind(_ context.Context, _ *ssg.ContentKind) error { return nil }
+func (s *Service) DeleteContentKind(_ context.Context, _ uuid.UUID, _ string) error {
+	return nil
+}
+func (s *Service) GetContentFields(_ context.Context, _ uuid.UUID) ([]*ssg.ContentField, error) {
+	return nil, nil
+}
+func (s *Service) SetContentField(_ context.Context, _ *ssg.ContentField) error { return nil }
+func (s *Service) SetContentFields(_ context.Context, _ uuid.UUID, _ []*ssg.ContentField) error {
+	return nil
+}
+func (s *Service) DeleteContentField(_ context.Context, _ uuid.UUID, _ string) error {
+	return nil
+}
+func (s *Service) GetContentReview(_ context.Context, _ uuid.UUID) (*ssg.ContentReview, error) {
+	return nil, ssg.ErrNotFound
+}
+func (s *Service) SubmitForReview(_ context.Context, _, _ uuid.UUID) (*ssg.ContentReview, error) {
+	return nil, nil
+}
+func (s *Service) AssignReviewer(_ context.Context, _, _ uuid.UUID) (*ssg.ContentReview, error) {
+	return nil, nil
+}
+func (s *Service) ApproveContent(_ context.Context, _, _ uuid.UUID, _ string) (*ssg.ContentReview, error) {
+	return nil, nil
+}
+func (s *Service) RequestChanges(_ context.Context, _, _ uuid.UUID, _ string) (*ssg.ContentReview, error) {
+	return nil, nil
+}
+func (s *Service) ListReviewQueue(_ context.Context, _ uuid.UUID) ([]*ssg.ContentReview, error) {
+	return nil, nil
+}
+func (s *Service) ListReviewers(_ context.Context) ([]*ssg.Reviewer, error) {
+	return nil, nil
+}
 func (s *Service) CreateTag(_ context.Context, _ *ssg.Tag) error       { return nil }
 func (s *Service) GetTag(_ context.Context, _ uuid.UUID) (*ssg.Tag, error) {
 	return nil, nil
@@ -132,6 +310,12 @@ func (s *Service) RemoveAllTagsFromContent(_ context.Context, _ uuid.UUID) error
 func (s *Service) GetTagsForContent(_ context.Context, _ uuid.UUID) ([]*ssg.Tag, error) {
 	return nil, nil
 }
+func (s *Service) GetContentForTag(_ context.Context, _ uuid.UUID) ([]*ssg.Content, error) {
+	return nil, nil
+}
+func (s *Service) BulkTagContent(_ context.Context, _ uuid.UUID, _ []uuid.UUID, _ bool) (int, error) {
+	return 0, nil
+}
 func (s *Service) CreateSetting(_ context.Context, _ *ssg.Setting) error { return nil }
 func (s *Service) GetSetting(_ context.Context, _ uuid.UUID) (*ssg.Setting, error) {
 	return nil, nil
@@ -142,12 +326,20 @@ func (s *Service) GetSettingByName(_ context.Context, _ uuid.UUID, _ string) (*s
 func (s *Service) UpdateSetting(_ context.Context, _ *ssg.Setting) error { return nil }
 func (s *Service) DeleteSetting(_ context.Context, _ uuid.UUID) error    { return nil }
 func (s *Service) CreateImage(_ context.Context, _ *ssg.Image) error     { return nil }
+func (s *Service) CreateImages(_ context.Context, _ []*ssg.Image) error  { return nil }
 func (s *Service) GetImage(_ context.Context, _ uuid.UUID) (*ssg.Image, error) {
 	return nil, nil
 }
 func (s *Service) GetImages(_ context.Context, _ uuid.UUID) ([]*ssg.Image, error) {
 	return nil, nil
 }
+func (s *Service) GetImagesWithPagination(_ context.Context, _ uuid.UUID, _, _ int, _ ssg.ImageFilter) ([]*ssg.Image, int, error) {
+	return nil, 0, nil
+}
+
+func (s *Service) SearchImages(_ context.Context, _ uuid.UUID, _ string, _ uuid.UUID, _, _ int) ([]*ssg.Image, int, error) {
+	return nil, 0, nil
+}
 func (s *Service) GetImageByPath(_ context.Context, _ uuid.UUID, _ string) (*ssg.Image, error) {
 	return nil, nil
 }
@@ -173,11 +365,47 @@ func (s *Service) LinkImageToSection(_ context.Context, _, _ uuid.UUID, _ bool)
 func (s *Service) UnlinkImageFromSection(_ context.Context, _ uuid.UUID) error        { return nil }
 func (s *Service) UpdateImage(_ context.Context, _ *ssg.Image) error                  { return nil }
 func (s *Service) DeleteImage(_ context.Context, _ uuid.UUID) error                   { return nil }
+func (s *Service) GetImageUsage(_ context.Context, _ uuid.UUID) (*ssg.ImageUsage, error) {
+	return &ssg.ImageUsage{}, nil
+}
+func (s *Service) FindOrphanedImages(_ context.Context, _ uuid.UUID) ([]*ssg.Image, error) {
+	return nil, nil
+}
+func (s *Service) PurgeOrphanedImages(_ context.Context, _ uuid.UUID, _ []uuid.UUID) (*ssg.OrphanPurge, error) {
+	return &ssg.OrphanPurge{}, nil
+}
+func (s *Service) CreateCollection(_ context.Context, _ *ssg.Collection) error { return nil }
+func (s *Service) GetCollection(_ context.Context, _ uuid.UUID) (*ssg.Collection, error) {
+	return nil, nil
+}
+func (s *Service) GetCollections(_ context.Context, _ uuid.UUID) ([]*ssg.Collection, error) {
+	return nil, nil
+}
+func (s *Service) UpdateCollection(_ context.Context, _ *ssg.Collection) error { return nil }
+func (s *Service) DeleteCollection(_ context.Context, _ uuid.UUID) error       { return nil }
+func (s *Service) AddImageToCollection(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
+func (s *Service) RemoveImageFromCollection(_ context.Context, _, _ uuid.UUID) error {
+	return nil
+}
+func (s *Service) SetImageCollections(_ context.Context, _ uuid.UUID, _ []uuid.UUID) error {
+	return nil
+}
+func (s *Service) GetImagesByCollection(_ context.Context, _ uuid.UUID) ([]*ssg.Image, error) {
+	return nil, nil
+}
+func (s *Service) GetImageCollections(_ context.Context, _ uuid.UUID) ([]*ssg.Collection, error) {
+	return nil, nil
+}
 func (s *Service) GetMetaByContentID(_ context.Context, _ uuid.UUID) (*ssg.Meta, error) {
 	return nil, nil
 }
 func (s *Service) CreateMeta(_ context.Context, _ *ssg.Meta) error          { return nil }
 func (s *Service) UpdateMeta(_ context.Context, _ *ssg.Meta) error          { return nil }
+func (s *Service) SetContentRobots(_ context.Context, _ uuid.UUID, _ []uuid.UUID, _ string, _ uuid.UUID) (int, error) {
+	return 0, nil
+}
 func (s *Service) CreateContributor(_ context.Context, _ *ssg.Contributor) error { return nil }
 func (s *Service) GetContributor(_ context.Context, _ uuid.UUID) (*ssg.Contributor, error) {
 	return nil, nil
@@ -191,6 +419,24 @@ func (s *Service) SetContributorProfile(_ context.Context, _, _ uuid.UUID, _ str
 	return nil
 }
 func (s *Service) GenerateHTMLForSite(_ context.Context, _ string) error { return nil }
+func (s *Service) GenerateAllSites(_ context.Context) ([]*ssg.SiteGenerationResult, error) {
+	return nil, nil
+}
+func (s *Service) GenerateSiteTar(_ context.Context, _ uuid.UUID) (io.ReadCloser, error) {
+	return nil, nil
+}
+func (s *Service) RenderDraftPreview(_ context.Context, _ *ssg.Site, _ string) ([]byte, error) {
+	return nil, ssg.ErrNotFound
+}
+func (s *Service) RenderContentPreview(_ context.Context, _ *ssg.Site, _ uuid.UUID) ([]byte, error) {
+	return nil, ssg.ErrNotFound
+}
+func (s *Service) CompileSeries(_ context.Context, _ uuid.UUID, _ string, _ ssg.BookFormat) ([]byte, error) {
+	return nil, ssg.ErrNotFound
+}
+func (s *Service) CompileSection(_ context.Context, _, _ uuid.UUID, _ ssg.BookFormat) ([]byte, error) {
+	return nil, ssg.ErrNotFound
+}
 func (s *Service) CreateImport(_ context.Context, _ *ssg.Import) error   { return nil }
 func (s *Service) GetImport(_ context.Context, _ uuid.UUID) (*ssg.Import, error) {
 	return nil, nil
@@ -213,6 +459,12 @@ func (s *Service) ScanImportDirectory(_ context.Context, _ string) ([]ssg.Import
 func (s *Service) ImportFile(_ context.Context, _, _ uuid.UUID, _ ssg.ImportFile, _ uuid.UUID) (*ssg.Content, *ssg.Import, error) {
 	return nil, nil, nil
 }
+func (s *Service) QuickImport(_ context.Context, _, _, _ uuid.UUID, _, _ string) (*ssg.Content, error) {
+	return nil, nil
+}
 func (s *Service) ReimportFile(_ context.Context, _ uuid.UUID, _ bool) (*ssg.Content, error) {
 	return nil, nil
 }
+func (s *Service) DiffImport(_ context.Context, _ uuid.UUID) (*ssg.ImportDiff, error) {
+	return nil, nil
+}
@@ -0,0 +1,39 @@
+package ssg
+
+import "strconv"
+
+// FeaturedPinsRefKey is the most featured items pinned to the top of the home
+// and section index pages. Zero turns pinning off.
+const FeaturedPinsRefKey = "ssg.index.featured_pins"
+
+const defaultFeaturedPins = 3
+
+func featuredPins(params map[string]string) int {
+	if n, err := strconv.Atoi(params[FeaturedPinsRefKey]); err == nil && n >= 0 {
+		return n
+	}
+	return defaultFeaturedPins
+}
+
+// pinFeatured moves up to max featured items to the front of a listing, in
+// their listing order, followed by the rest. Pinned items are not repeated
+// further down. contents must already hold only listed content.
+func pinFeatured(contents []*Content, max int) []*Content {
+	if max <= 0 {
+		return contents
+	}
+
+	pinned := make([]*Content, 0, max)
+	rest := make([]*Content, 0, len(contents))
+	for _, c := range contents {
+		if c.Featured && len(pinned) < max {
+			pinned = append(pinned, c)
+			continue
+		}
+		rest = append(rest, c)
+	}
+	if len(pinned) == 0 {
+		return contents
+	}
+	return append(pinned, rest...)
+}
@@ -0,0 +1,18 @@
+package ssg
+
+// Feature flags hide admin sections a site does not use: their navigation
+// entries and forms are left out and their routes answer 404. Turning one off
+// keeps its data, and generation is not affected. Features are on unless
+// their setting is false, so sites created before the flags have them all.
+const (
+	FeatureContributors = "feature.contributors"
+	FeatureImports      = "feature.imports"
+	FeatureSeries       = "feature.series"
+)
+
+// Features are the feature flags of a site, as templates see them.
+type Features struct {
+	Contributors bool
+	Imports      bool
+	Series       bool
+}
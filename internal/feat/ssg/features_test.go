@@ -0,0 +1,63 @@
+package ssg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/cliossg/clio/pkg/cl/middleware"
+)
+
+func TestFeatureFlagGuardsRoutes(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Feature Site", "feature-site")
+	log := newTestLogger()
+
+	asAdmin := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.UserRolesKey, "admin")))
+		})
+	}
+	h := &Handler{service: svc, log: log, siteCtxMw: SiteContextMiddleware(svc, log), sessionMw: asAdmin}
+	router := chi.NewRouter()
+	h.RegisterRoutes(router)
+
+	status := func(path string) int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?site_id="+site.ID.String(), nil))
+		return rec.Code
+	}
+
+	if got := status("/ssg/list-contributors"); got == http.StatusNotFound {
+		t.Fatalf("contributors with the feature unset = %d, want it served", got)
+	}
+
+	flag := NewSetting(site.ID, "Contributors", "false")
+	flag.RefKey = FeatureContributors
+	flag.Type = SettingTypeBoolean
+	if err := svc.CreateSetting(ctx, flag); err != nil {
+		t.Fatalf("CreateSetting() error = %v", err)
+	}
+	for _, path := range []string{"/ssg/list-contributors", "/ssg/new-contributor"} {
+		if got := status(path); got != http.StatusNotFound {
+			t.Errorf("%s with contributors off = %d, want 404", path, got)
+		}
+	}
+	if enabled, err := svc.IsFeatureEnabled(ctx, site.ID, FeatureImports); err != nil || !enabled {
+		t.Errorf("IsFeatureEnabled(imports) = %v, %v, want true", enabled, err)
+	}
+
+	flag.Value = "true"
+	if err := svc.UpdateSetting(ctx, flag); err != nil {
+		t.Fatalf("UpdateSetting() error = %v", err)
+	}
+	if got := status("/ssg/list-contributors"); got == http.StatusNotFound {
+		t.Errorf("contributors turned back on = %d, want it served", got)
+	}
+}
@@ -0,0 +1,229 @@
+package ssg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FeedProblem is an error found in a generated feed file.
+type FeedProblem struct {
+	Feed    string `json:"feed"` // path inside the output directory, e.g. blog/feed/atom.xml
+	Message string `json:"message"`
+}
+
+// FeedReport holds the problems found in a site's generated feeds, sorted by
+// feed.
+type FeedReport struct {
+	Feeds    int           `json:"feeds"`
+	Problems []FeedProblem `json:"problems"`
+}
+
+// validateFeeds parses every feed file under htmlPath back and checks the
+// elements readers rely on. A missing output directory gives an empty report.
+func validateFeeds(htmlPath string) (*FeedReport, error) {
+	report := &FeedReport{}
+	err := filepath.WalkDir(htmlPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == htmlPath && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			if filepath.Dir(path) == htmlPath && (d.Name() == "images" || d.Name() == "profiles") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Base(filepath.Dir(path)) != "feed" {
+			return nil
+		}
+		var check func([]byte) []string
+		switch d.Name() {
+		case "atom.xml":
+			check = checkAtomFeed
+		case "rss.xml":
+			check = checkRSSFeed
+		case "feed.json":
+			check = checkJSONFeed
+		default:
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(htmlPath, path)
+		if err != nil {
+			return err
+		}
+		report.Feeds++
+		for _, msg := range check(data) {
+			report.Problems = append(report.Problems, FeedProblem{Feed: filepath.ToSlash(rel), Message: msg})
+		}
+		return nil
+	})
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		return report.Problems[i].Feed < report.Problems[j].Feed
+	})
+	return report, err
+}
+
+// feedChecker collects the problems of one feed.
+type feedChecker struct {
+	problems []string
+}
+
+func (c *feedChecker) addf(format string, args ...any) {
+	c.problems = append(c.problems, fmt.Sprintf(format, args...))
+}
+
+func (c *feedChecker) required(where, name, value string) {
+	if value == "" {
+		c.addf("%s has no %s", where, name)
+	}
+}
+
+func (c *feedChecker) absURL(where, name, value string) {
+	if value == "" {
+		c.addf("%s has no %s", where, name)
+		return
+	}
+	if u, err := url.Parse(value); err != nil || !u.IsAbs() || u.Host == "" {
+		c.addf("%s %s is not an absolute URL: %q", where, name, value)
+	}
+}
+
+func (c *feedChecker) date(where, name, value, layout string) {
+	if value == "" {
+		c.addf("%s has no %s", where, name)
+		return
+	}
+	if _, err := time.Parse(layout, value); err != nil {
+		c.addf("%s %s is not a valid date: %q", where, name, value)
+	}
+}
+
+// checkAtomFeed checks the feed id, title and updated date, a self link and
+// the id, title, link and dates of every entry.
+func checkAtomFeed(data []byte) []string {
+	var f atomFeed
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return []string{"not well-formed XML: " + err.Error()}
+	}
+	c := &feedChecker{}
+	if f.XMLName.Space != "http://www.w3.org/2005/Atom" {
+		c.addf("feed is not in the Atom namespace")
+	}
+	c.required("feed", "id", f.ID)
+	c.required("feed", "title", f.Title)
+	c.date("feed", "updated date", f.Updated, time.RFC3339)
+	self := false
+	for _, l := range f.Links {
+		if l.Rel == "self" {
+			self = true
+			c.absURL("feed", "self link", l.Href)
+		}
+	}
+	if !self {
+		c.addf("feed has no self link")
+	}
+	for i, e := range f.Entries {
+		where := fmt.Sprintf("entry %d", i+1)
+		c.required(where, "id", e.ID)
+		c.required(where, "title", e.Title)
+		if len(e.Links) == 0 {
+			c.absURL(where, "link", "")
+		}
+		for _, l := range e.Links {
+			c.absURL(where, "link", l.Href)
+		}
+		c.date(where, "updated date", e.Updated, time.RFC3339)
+		if e.Published != "" {
+			c.date(where, "published date", e.Published, time.RFC3339)
+		}
+	}
+	return c.problems
+}
+
+// rssDocument reads an RSS feed back. The channel's atom:link is matched by
+// namespace, and comes before Link since fields are tried in order.
+type rssDocument struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		AtomLinks   []atomLink `xml:"http://www.w3.org/2005/Atom link"`
+		Title       string     `xml:"title"`
+		Link        string     `xml:"link"`
+		Description string     `xml:"description"`
+		Items       []rssItem  `xml:"item"`
+	} `xml:"channel"`
+}
+
+// checkRSSFeed checks the channel title, link and description, the atom:link
+// to itself and the guid, title, link and date of every item.
+func checkRSSFeed(data []byte) []string {
+	var f rssDocument
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return []string{"not well-formed XML: " + err.Error()}
+	}
+	c := &feedChecker{}
+	if f.Version != "2.0" {
+		c.addf("rss version is %q, want 2.0", f.Version)
+	}
+	ch := f.Channel
+	c.required("channel", "title", ch.Title)
+	c.required("channel", "description", ch.Description)
+	c.absURL("channel", "link", ch.Link)
+	self := false
+	for _, l := range ch.AtomLinks {
+		if l.Rel == "self" {
+			self = true
+			c.absURL("channel", "self link", l.Href)
+		}
+	}
+	if !self {
+		c.addf("channel has no self link")
+	}
+	for i, it := range ch.Items {
+		where := fmt.Sprintf("item %d", i+1)
+		c.required(where, "guid", it.GUID.Value)
+		c.required(where, "title", it.Title)
+		c.absURL(where, "link", it.Link)
+		c.date(where, "pubDate", it.PubDate, time.RFC1123Z)
+	}
+	return c.problems
+}
+
+// checkJSONFeed checks the version, title and feed URL and the id, URL and
+// dates of every item.
+func checkJSONFeed(data []byte) []string {
+	var f jsonFeed
+	if err := json.Unmarshal(data, &f); err != nil {
+		return []string{"not valid JSON: " + err.Error()}
+	}
+	c := &feedChecker{}
+	if f.Version != "https://jsonfeed.org/version/1.1" {
+		c.addf("feed version is %q, want https://jsonfeed.org/version/1.1", f.Version)
+	}
+	c.required("feed", "title", f.Title)
+	c.absURL("feed", "feed_url", f.FeedURL)
+	for i, it := range f.Items {
+		where := fmt.Sprintf("item %d", i+1)
+		c.required(where, "id", it.ID)
+		c.absURL(where, "url", it.URL)
+		c.date(where, "date_published", it.DatePublished, time.RFC3339)
+		if it.DateModified != "" {
+			c.date(where, "date_modified", it.DateModified, time.RFC3339)
+		}
+	}
+	return c.problems
+}
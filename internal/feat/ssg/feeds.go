@@ -0,0 +1,634 @@
+package ssg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Feed settings.
+const (
+	// FeedFormatsRefKey lists the feed formats to write: atom, rss and json.
+	FeedFormatsRefKey = "ssg.feed.formats"
+	// FeedSectionsRefKey selects the sections with a feed of their own: empty
+	// for every section, "none" for none, or a list of section paths.
+	FeedSectionsRefKey = "ssg.feed.sections"
+	// FeedTagsRefKey adds a feed per tag when "true".
+	FeedTagsRefKey = "ssg.feed.tags"
+	// FeedMaxItemsRefKey is the number of latest entries a feed holds.
+	FeedMaxItemsRefKey = "ssg.feed.maxitems"
+	// FeedContentRefKey is what entries carry of each post: the full body,
+	// just its summary, or neither.
+	FeedContentRefKey = "ssg.feed.content"
+)
+
+// Feed content modes.
+const (
+	FeedContentFull    = "full"
+	FeedContentSummary = "summary"
+	FeedContentNone    = "none"
+)
+
+// Feed formats.
+const (
+	FeedFormatAtom = "atom"
+	FeedFormatRSS  = "rss"
+	FeedFormatJSON = "json"
+)
+
+const defaultFeedMaxItems = 20
+
+type feedFormat struct {
+	name      string
+	file      string
+	mediaType string
+	label     string
+}
+
+// feedFormats are in the order feed links are written in.
+var feedFormats = []feedFormat{
+	{FeedFormatAtom, "atom.xml", "application/atom+xml", "Atom"},
+	{FeedFormatRSS, "rss.xml", "application/rss+xml", "RSS"},
+	{FeedFormatJSON, "feed.json", "application/feed+json", "JSON Feed"},
+}
+
+// FeedLink is a feed advertised by a page with <link rel="alternate">.
+type FeedLink struct {
+	Type  string
+	Title string
+	URL   string
+}
+
+// enabledFeedFormats returns the formats selected by ssg.feed.formats. Sites
+// without the setting get Atom; an empty value turns feeds off.
+func enabledFeedFormats(params map[string]string) []feedFormat {
+	value, ok := params[FeedFormatsRefKey]
+	if !ok {
+		return feedFormats[:1]
+	}
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		selected[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	var formats []feedFormat
+	for _, f := range feedFormats {
+		if selected[f.name] {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// sectionFeedEnabled reports whether the section at path gets its own feed.
+func sectionFeedEnabled(params map[string]string, path string) bool {
+	value := strings.TrimSpace(params[FeedSectionsRefKey])
+	switch value {
+	case "":
+		return true
+	case "none":
+		return false
+	}
+	for _, p := range strings.Split(value, ",") {
+		if normalizePath(strings.TrimSpace(p)) == normalizePath(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func tagFeedsEnabled(params map[string]string) bool {
+	return params[FeedTagsRefKey] == "true"
+}
+
+func feedMaxItems(params map[string]string) int {
+	if n, err := strconv.Atoi(params[FeedMaxItemsRefKey]); err == nil && n > 0 {
+		return n
+	}
+	return defaultFeedMaxItems
+}
+
+// feedContentMode returns the ssg.feed.content setting. Sites without it,
+// and invalid values, get summaries.
+func feedContentMode(params map[string]string) string {
+	switch v := strings.ToLower(strings.TrimSpace(params[FeedContentRefKey])); v {
+	case FeedContentFull, FeedContentNone:
+		return v
+	}
+	return FeedContentSummary
+}
+
+// feedURL returns the absolute URL of a feed file for the listing at
+// listPath ("" for the whole site).
+func feedURL(baseURL, basePath, listPath, file string) string {
+	if listPath == "" || listPath == "/" {
+		return baseURL + basePath + "feed/" + file
+	}
+	return baseURL + basePath + listPath + "/feed/" + file
+}
+
+// feedLinks returns the feeds to advertise on the listing at listPath. Feeds
+// need absolute URLs, so there are none without a base URL.
+func (g *HTMLGenerator) feedLinks(params map[string]string, listPath, title string) []FeedLink {
+	baseURL := siteBaseURL(params)
+	if baseURL == "" {
+		return nil
+	}
+	basePath := g.getAssetPath(params)
+	var links []FeedLink
+	for _, f := range enabledFeedFormats(params) {
+		links = append(links, FeedLink{
+			Type:  f.mediaType,
+			Title: title + " (" + f.label + ")",
+			URL:   feedURL(baseURL, basePath, listPath, f.file),
+		})
+	}
+	return links
+}
+
+// feed is a listing written in every enabled format.
+type feed struct {
+	listPath string
+	title    string
+	contents []*Content
+	homePath string // Page the feed links to when there is no listing at listPath
+}
+
+// generateFeeds writes the site feed, a feed per selected section, a feed per
+// kind that has one and, when enabled, a feed per tag. Only published posts
+// are included, and listings without any get no feed. It returns the number
+// of feed files written.
+func (g *HTMLGenerator) generateFeeds(build *buildState, htmlPath string, site *Site, contents []*Content, sections []*Section, params map[string]string) (int, error) {
+	formats := enabledFeedFormats(params)
+	if len(formats) == 0 || siteBaseURL(params) == "" {
+		return 0, nil
+	}
+
+	var published []*Content
+	for _, c := range contents {
+		if inListings(c) {
+			published = append(published, c)
+		}
+	}
+	kindFeeds := kindFeeds(site, contents)
+	if len(published) == 0 && len(kindFeeds) == 0 {
+		return 0, nil
+	}
+
+	var feeds []feed
+	if len(published) > 0 {
+		feeds = append(feeds, feed{title: site.Name, contents: published})
+	}
+	feeds = append(feeds, kindFeeds...)
+	for _, section := range sections {
+		if section.Path == "" || section.Path == "/" || !sectionFeedEnabled(params, section.Path) {
+			continue
+		}
+		var sectionContents []*Content
+		for _, c := range published {
+			if c.SectionID == section.ID {
+				sectionContents = append(sectionContents, c)
+			}
+		}
+		if len(sectionContents) > 0 {
+			feeds = append(feeds, feed{listPath: section.Path, title: site.Name + " - " + section.Name, contents: sectionContents})
+		}
+	}
+
+	if tagFeedsEnabled(params) {
+		var tags []*Tag
+		tagContents := make(map[string][]*Content)
+		for _, c := range published {
+			for _, t := range c.Tags {
+				slug := tagSlug(t)
+				if slug == "" {
+					continue
+				}
+				if _, ok := tagContents[slug]; !ok {
+					tags = append(tags, t)
+				}
+				tagContents[slug] = append(tagContents[slug], c)
+			}
+		}
+		for _, t := range tags {
+			slug := tagSlug(t)
+			feeds = append(feeds, feed{listPath: "tags/" + slug, title: site.Name + " - #" + t.Name, contents: tagContents[slug]})
+		}
+	}
+
+	count := 0
+	for _, f := range feeds {
+		items := g.feedItems(f.contents, params)
+		for _, format := range formats {
+			dest := filepath.Join(htmlPath, filepath.FromSlash(f.listPath), "feed", format.file)
+			if err := g.writeFeed(dest, format.name, f, items, params); err != nil {
+				return count, err
+			}
+			build.record(dest, "", time.Time{})
+			count++
+		}
+	}
+	return count, nil
+}
+
+// kindFeeds returns a feed per kind set to have one, at kinds/<name>/feed/,
+// holding its published content whether or not the kind is in listings. They
+// link back to the site home page.
+func kindFeeds(site *Site, contents []*Content) []feed {
+	var feeds []feed
+	byKind := make(map[string]int)
+	for _, c := range contents {
+		kind := c.kindSettings()
+		if !kind.Feed || !isListed(c) {
+			continue
+		}
+		i, ok := byKind[kind.Name]
+		if !ok {
+			label := kind.Label
+			if label == "" {
+				label = kind.Name
+			}
+			i = len(feeds)
+			byKind[kind.Name] = i
+			feeds = append(feeds, feed{listPath: "kinds/" + kind.Name, title: site.Name + " - " + label, homePath: "/"})
+		}
+		feeds[i].contents = append(feeds[i].contents, c)
+	}
+	return feeds
+}
+
+// feedItem is a format independent feed entry.
+type feedItem struct {
+	id        string
+	url       string
+	external  string // Page a link post points to. url is then its permalink
+	title     string
+	summary   string
+	html      string // Full body, only when feeds carry it
+	author    string
+	tags      []string
+	published time.Time
+	updated   time.Time
+}
+
+// feedItems returns the latest contents as feed entries, newest first, with
+// the body or summary the ssg.feed.content setting asks for.
+func (g *HTMLGenerator) feedItems(contents []*Content, params map[string]string) []feedItem {
+	sorted := make([]*Content, len(contents))
+	copy(sorted, contents)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return publicationDate(sorted[i]).After(publicationDate(sorted[j]))
+	})
+	if limit := feedMaxItems(params); len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	baseURL := siteBaseURL(params)
+	basePath := g.getAssetPath(params)
+	mode := feedContentMode(params)
+	items := make([]feedItem, 0, len(sorted))
+	for _, c := range sorted {
+		item := feedItem{
+			id:        "urn:uuid:" + c.ID.String(),
+			url:       baseURL + g.getContentURL(c, basePath, params),
+			title:     c.Heading,
+			author:    c.DisplayHandle(),
+			published: publicationDate(c),
+			updated:   c.UpdatedAt,
+		}
+		if mode != FeedContentNone {
+			item.summary = c.Summary
+		}
+		if mode == FeedContentFull {
+			body, _ := g.processor.ProcessContent(c, params)
+			item.html = absoluteFeedURLs(sanitizeFeedHTML(body), baseURL)
+		}
+		if c.Contributor != nil && c.Contributor.FullName() != "" {
+			item.author = c.Contributor.FullName()
+		}
+		if c.LinkURL != "" || c.kindSettings().Redirect {
+			item.external = linkTarget(c)
+		}
+		if item.external != "" && mode == FeedContentFull {
+			// The entry links to the page the post points to, as its
+			// page does, and the commentary links back to the post.
+			item.html += `<p><a href="` + html.EscapeString(item.url) + `">&#8734; Permalink</a></p>`
+		}
+		if item.updated.Before(item.published) {
+			item.updated = item.published
+		}
+		for _, t := range c.Tags {
+			item.tags = append(item.tags, t.Name)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+var (
+	rootRelativeURLRe = regexp.MustCompile(`(\s(?:src|href|poster)=")/([^/"])`)
+	srcsetRe          = regexp.MustCompile(`(\ssrcset=")([^"]*)(")`)
+)
+
+// absoluteFeedURLs makes root relative links, images and their srcset
+// candidates absolute, since feed readers show entries away from the site.
+func absoluteFeedURLs(html, baseURL string) string {
+	html = rootRelativeURLRe.ReplaceAllString(html, "${1}"+baseURL+"/${2}")
+	return srcsetRe.ReplaceAllStringFunc(html, func(m string) string {
+		parts := srcsetRe.FindStringSubmatch(m)
+		candidates := strings.Split(parts[2], ",")
+		for i, cand := range candidates {
+			cand = strings.TrimSpace(cand)
+			if strings.HasPrefix(cand, "/") && !strings.HasPrefix(cand, "//") {
+				cand = baseURL + cand
+			}
+			candidates[i] = cand
+		}
+		return parts[1] + strings.Join(candidates, ", ") + parts[3]
+	})
+}
+
+// feedUnsafeElements are dropped from feed entries with their content. Feed
+// readers strip them anyway, and some refuse entries that carry them.
+var feedUnsafeElements = []string{"script", "style", "iframe", "object", "embed", "form"}
+
+var (
+	feedUnsafeElementRes = func() []*regexp.Regexp {
+		res := make([]*regexp.Regexp, 0, 2*len(feedUnsafeElements))
+		for _, name := range feedUnsafeElements {
+			res = append(res,
+				regexp.MustCompile(`(?is)<`+name+`\b[^>]*>.*?</`+name+`\s*>`),
+				regexp.MustCompile(`(?i)</?`+name+`\b[^>]*>`))
+		}
+		return res
+	}()
+	eventHandlerAttrRe = regexp.MustCompile(`(?i)\s+on[a-z]+\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>]+)`)
+	scriptURLAttrRe    = regexp.MustCompile(`(?i)\s+(?:href|src|action|formaction|poster)\s*=\s*(?:"\s*javascript:[^"]*"|'\s*javascript:[^']*'|javascript:[^\s>]*)`)
+)
+
+// sanitizeFeedHTML removes what feed readers must not run from a rendered
+// body: scripts, styles, frames, embeds and forms, event handler attributes
+// and javascript: URLs. Raw HTML in markdown is otherwise kept.
+func sanitizeFeedHTML(html string) string {
+	for _, re := range feedUnsafeElementRes {
+		html = re.ReplaceAllString(html, "")
+	}
+	html = eventHandlerAttrRe.ReplaceAllString(html, "")
+	return scriptURLAttrRe.ReplaceAllString(html, "")
+}
+
+func (g *HTMLGenerator) writeFeed(dest, format string, f feed, items []feedItem, params map[string]string) error {
+	baseURL := siteBaseURL(params)
+	basePath := g.getAssetPath(params)
+	self := feedURL(baseURL, basePath, f.listPath, filepath.Base(dest))
+	home := baseURL + g.getPaginationURL(params, basePath, f.listPath, 1)
+	if f.homePath != "" {
+		home = baseURL + g.getPaginationURL(params, basePath, f.homePath, 1)
+	}
+	loc := siteLocation(params)
+
+	var data []byte
+	var err error
+	switch format {
+	case FeedFormatRSS:
+		data, err = rssFeedXML(f.title, home, self, items, loc)
+	case FeedFormatJSON:
+		data, err = jsonFeedJSON(f.title, home, self, items, loc)
+	default:
+		data, err = atomFeedXML(f.title, home, self, items, loc)
+	}
+	if err != nil {
+		return err
+	}
+	if err := EnsureDir(dest); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0644)
+}
+
+// atomFeed is the root element of an Atom feed (RFC 4287).
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string         `xml:"title"`
+	ID        string         `xml:"id"`
+	Links     []atomLink     `xml:"link"`
+	Published string         `xml:"published"`
+	Updated   string         `xml:"updated"`
+	Author    *atomPerson    `xml:"author,omitempty"`
+	Category  []atomTerm     `xml:"category"`
+	Summary   string         `xml:"summary,omitempty"`
+	Content   *atomHTMLValue `xml:"content,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomTerm struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomHTMLValue struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func atomFeedXML(title, home, self string, items []feedItem, loc *time.Location) ([]byte, error) {
+	f := atomFeed{
+		XMLNS: "http://www.w3.org/2005/Atom",
+		Title: title,
+		ID:    self,
+		Links: []atomLink{{Href: self, Rel: "self", Type: "application/atom+xml"}, {Href: home, Rel: "alternate", Type: "text/html"}},
+	}
+	var updated time.Time
+	for _, it := range items {
+		entry := atomEntry{
+			Title:     it.title,
+			ID:        it.id,
+			Links:     []atomLink{{Href: it.url, Rel: "alternate", Type: "text/html"}},
+			Published: it.published.In(loc).Format(time.RFC3339),
+			Updated:   it.updated.In(loc).Format(time.RFC3339),
+			Summary:   it.summary,
+		}
+		if it.html != "" {
+			entry.Content = &atomHTMLValue{Type: "html", Body: it.html}
+		}
+		if it.external != "" {
+			entry.Links = []atomLink{{Href: it.external, Rel: "alternate", Type: "text/html"}, {Href: it.url, Rel: "related", Type: "text/html"}}
+		}
+		if it.author != "" {
+			entry.Author = &atomPerson{Name: it.author}
+		}
+		for _, t := range it.tags {
+			entry.Category = append(entry.Category, atomTerm{Term: t})
+		}
+		f.Entries = append(f.Entries, entry)
+		if it.updated.After(updated) {
+			updated = it.updated
+		}
+	}
+	f.Updated = updated.In(loc).Format(time.RFC3339)
+	return encodeFeedXML(f)
+}
+
+// rssFeed is the root element of an RSS 2.0 feed.
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	XMLNSAtom string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Category    []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func rssFeedXML(title, home, self string, items []feedItem, loc *time.Location) ([]byte, error) {
+	f := rssFeed{
+		Version:   "2.0",
+		XMLNSAtom: "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       title,
+			Link:        home,
+			Description: title,
+			AtomLink:    atomLink{Href: self, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	var updated time.Time
+	for _, it := range items {
+		link := it.url
+		if it.external != "" {
+			link = it.external
+		}
+		description := it.html
+		if description == "" {
+			description = html.EscapeString(it.summary)
+		}
+		f.Channel.Items = append(f.Channel.Items, rssItem{
+			Title:       it.title,
+			Link:        link,
+			GUID:        rssGUID{Value: it.id},
+			PubDate:     it.published.In(loc).Format(time.RFC1123Z),
+			Category:    it.tags,
+			Description: description,
+		})
+		if it.updated.After(updated) {
+			updated = it.updated
+		}
+	}
+	if !updated.IsZero() {
+		f.Channel.LastBuildDate = updated.In(loc).Format(time.RFC1123Z)
+	}
+	return encodeFeedXML(f)
+}
+
+func encodeFeedXML(v any) ([]byte, error) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// jsonFeed is a JSON Feed 1.1 document, see https://jsonfeed.org/version/1.1.
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	ExternalURL   string           `json:"external_url,omitempty"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   *string          `json:"content_text,omitempty"` // Set when there is no content_html, as items need one of them
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func jsonFeedJSON(title, home, self string, items []feedItem, loc *time.Location) ([]byte, error) {
+	f := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		HomePageURL: home,
+		FeedURL:     self,
+		Items:       []jsonFeedItem{},
+	}
+	for _, it := range items {
+		item := jsonFeedItem{
+			ID:            it.id,
+			URL:           it.url,
+			ExternalURL:   it.external,
+			Title:         it.title,
+			ContentHTML:   it.html,
+			Summary:       it.summary,
+			DatePublished: it.published.In(loc).Format(time.RFC3339),
+			DateModified:  it.updated.In(loc).Format(time.RFC3339),
+			Tags:          it.tags,
+		}
+		if it.html == "" {
+			text := it.summary
+			item.ContentText = &text
+		}
+		if it.author != "" {
+			item.Authors = []jsonFeedAuthor{{Name: it.author}}
+		}
+		f.Items = append(f.Items, item)
+	}
+	out, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,382 @@
+package ssg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEnabledFeedFormats(t *testing.T) {
+	names := func(params map[string]string) string {
+		var out []string
+		for _, f := range enabledFeedFormats(params) {
+			out = append(out, f.name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		params map[string]string
+		want   string
+	}{
+		{map[string]string{}, "atom"},
+		{map[string]string{FeedFormatsRefKey: ""}, ""},
+		{map[string]string{FeedFormatsRefKey: "JSON, atom"}, "atom,json"},
+		{map[string]string{FeedFormatsRefKey: "rss,xml"}, "rss"},
+	}
+	for _, tt := range tests {
+		if got := names(tt.params); got != tt.want {
+			t.Errorf("enabledFeedFormats(%v) = %q, want %q", tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestSectionFeedEnabled(t *testing.T) {
+	all := map[string]string{}
+	none := map[string]string{FeedSectionsRefKey: "none"}
+	some := map[string]string{FeedSectionsRefKey: "blog, /changelog"}
+
+	if !sectionFeedEnabled(all, "blog") {
+		t.Error("all sections should get feeds by default")
+	}
+	if sectionFeedEnabled(none, "blog") {
+		t.Error("none should disable section feeds")
+	}
+	if !sectionFeedEnabled(some, "changelog") || sectionFeedEnabled(some, "notes") {
+		t.Error("only listed sections should get feeds")
+	}
+}
+
+func TestGenerateFeeds(t *testing.T) {
+	g := &HTMLGenerator{processor: NewProcessor()}
+	htmlPath := t.TempDir()
+
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	blog := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	notes := &Section{ID: uuid.New(), Name: "Notes", Path: "notes"}
+	goTag := &Tag{ID: uuid.New(), Name: "Go", Slug: "go"}
+
+	older := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	future := time.Now().Add(24 * time.Hour)
+	contents := []*Content{
+		{ID: uuid.New(), ShortID: "a0000001", SectionID: blog.ID, SectionPath: "blog", Heading: "Older", Body: "![x](/images/x.png)", PublishedAt: &older, UpdatedAt: older, Tags: []*Tag{goTag}},
+		{ID: uuid.New(), ShortID: "a0000002", SectionID: blog.ID, SectionPath: "blog", Heading: "Newer", Body: "New", PublishedAt: &newer, UpdatedAt: newer, ContributorHandle: "jo"},
+		{ID: uuid.New(), ShortID: "a0000003", SectionID: blog.ID, SectionPath: "blog", Heading: "Draft", Draft: true},
+		{ID: uuid.New(), ShortID: "a0000004", SectionID: notes.ID, SectionPath: "notes", Heading: "Later", PublishedAt: &future},
+		{ID: uuid.New(), ShortID: "a0000005", SectionID: blog.ID, SectionPath: "blog", Heading: "About", Kind: "page", PublishedAt: &older},
+	}
+	params := map[string]string{
+		BaseURLRefKey:     "https://example.com",
+		FeedFormatsRefKey: "atom,rss,json",
+		FeedTagsRefKey:    "true",
+		FeedContentRefKey: FeedContentFull,
+	}
+
+	count, err := g.generateFeeds(nil, htmlPath, site, contents, []*Section{blog, notes}, params)
+	if err != nil {
+		t.Fatalf("generateFeeds() error = %v", err)
+	}
+	if count != 9 {
+		t.Errorf("count = %d, want 9 (site, blog and go tag in three formats)", count)
+	}
+	if _, err := os.Stat(filepath.Join(htmlPath, "notes", "feed")); err == nil {
+		t.Error("a section with only scheduled posts should get no feed")
+	}
+
+	read := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join(htmlPath, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("%s not written: %v", name, err)
+		}
+		return data
+	}
+
+	var atom struct {
+		Title   string `xml:"title"`
+		Links   []atomLink
+		Entries []struct {
+			Title   string   `xml:"title"`
+			Link    atomLink `xml:"link"`
+			Content string   `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(read("blog/feed/atom.xml"), &atom); err != nil {
+		t.Fatalf("atom feed does not parse: %v", err)
+	}
+	if atom.Title != "Test - Blog" || len(atom.Entries) != 2 {
+		t.Fatalf("blog atom feed = %q with %d entries, want 2", atom.Title, len(atom.Entries))
+	}
+	if atom.Entries[0].Title != "Newer" || atom.Entries[0].Link.Href != "https://example.com/blog/newer-a0000002/" {
+		t.Errorf("first entry = %+v, want the newest post", atom.Entries[0])
+	}
+	if !strings.Contains(atom.Entries[1].Content, `src="https://example.com/images/x.png"`) {
+		t.Errorf("image URLs should be absolute, got %q", atom.Entries[1].Content)
+	}
+
+	var rss struct {
+		Channel struct {
+			Items []struct {
+				Title string `xml:"title"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(read("feed/rss.xml"), &rss); err != nil {
+		t.Fatalf("rss feed does not parse: %v", err)
+	}
+	if len(rss.Channel.Items) != 2 {
+		t.Errorf("site rss feed has %d items, want 2", len(rss.Channel.Items))
+	}
+
+	var jf jsonFeed
+	if err := json.Unmarshal(read("tags/go/feed/feed.json"), &jf); err != nil {
+		t.Fatalf("json feed does not parse: %v", err)
+	}
+	if jf.Version != "https://jsonfeed.org/version/1.1" || jf.FeedURL != "https://example.com/tags/go/feed/feed.json" {
+		t.Errorf("json feed = %+v", jf)
+	}
+	if len(jf.Items) != 1 || jf.Items[0].Title != "Older" || jf.Items[0].Tags[0] != "Go" {
+		t.Errorf("json feed items = %+v", jf.Items)
+	}
+
+	delete(params, BaseURLRefKey)
+	if count, _ := g.generateFeeds(nil, t.TempDir(), site, contents, []*Section{blog}, params); count != 0 {
+		t.Errorf("without a base URL %d feeds were written, want 0", count)
+	}
+}
+
+func TestFeedLinkPost(t *testing.T) {
+	g := &HTMLGenerator{processor: NewProcessor()}
+	published := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	link := &Content{ID: uuid.New(), ShortID: "a0000001", Heading: "Worth reading", Kind: KindLink, LinkURL: "https://other.example/essay", Body: "A sharp take.", PublishedAt: &published, UpdatedAt: published}
+	post := &Content{ID: uuid.New(), ShortID: "a0000002", Heading: "Own post", Body: "Mine.", PublishedAt: &published, UpdatedAt: published}
+	applyContentKinds([]*Content{link, post}, nil)
+	params := map[string]string{BaseURLRefKey: "https://example.com", FeedContentRefKey: FeedContentFull}
+
+	items := g.feedItems([]*Content{link, post}, params)
+	permalink := "https://example.com/worth-reading-a0000001/"
+
+	atom, err := atomFeedXML("Test", "https://example.com/", "https://example.com/feed/atom.xml", items, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var af atomFeed
+	if err := xml.Unmarshal(atom, &af); err != nil {
+		t.Fatalf("atom feed does not parse: %v", err)
+	}
+	want := []atomLink{{Href: "https://other.example/essay", Rel: "alternate", Type: "text/html"}, {Href: permalink, Rel: "related", Type: "text/html"}}
+	if got := af.Entries[0].Links; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("link post entry links = %+v, want %+v", got, want)
+	}
+	if !strings.Contains(af.Entries[0].Content.Body, "A sharp take.") || !strings.Contains(af.Entries[0].Content.Body, `href="`+permalink+`"`) {
+		t.Errorf("link post content should keep the commentary and link to the permalink, got %q", af.Entries[0].Content.Body)
+	}
+	if got := af.Entries[1].Links; len(got) != 1 || got[0].Href != "https://example.com/own-post-a0000002/" {
+		t.Errorf("post entry links = %+v, want just its permalink", got)
+	}
+
+	rss, err := rssFeedXML("Test", "https://example.com/", "https://example.com/feed/rss.xml", items, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(rss), "<link>https://other.example/essay</link>") {
+		t.Errorf("rss item should link to the linked page:\n%s", rss)
+	}
+
+	out, err := jsonFeedJSON("Test", "https://example.com/", "https://example.com/feed/feed.json", items, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jf jsonFeed
+	if err := json.Unmarshal(out, &jf); err != nil {
+		t.Fatal(err)
+	}
+	if jf.Items[0].URL != permalink || jf.Items[0].ExternalURL != "https://other.example/essay" {
+		t.Errorf("json feed item = %+v, want the permalink and the linked page as external_url", jf.Items[0])
+	}
+	if jf.Items[1].ExternalURL != "" {
+		t.Errorf("post should have no external_url, got %q", jf.Items[1].ExternalURL)
+	}
+}
+
+func TestFeedContentModes(t *testing.T) {
+	g := &HTMLGenerator{processor: NewProcessor()}
+	published := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	post := &Content{ID: uuid.New(), ShortID: "a0000001", Heading: "Post", Summary: "A short take.", PublishedAt: &published, UpdatedAt: published,
+		Body: "![x](/images/x.png)\n\n<img src=\"/images/y.png\" srcset=\"/images/y.png 1x, /images/y@2x.png 2x\" onerror=\"steal()\">\n\n" +
+			"<script>steal()</script>\n\n<a href=\"javascript:steal()\">Click</a>"}
+
+	for _, tt := range []struct {
+		mode        string
+		wantSummary bool
+		wantContent bool
+	}{
+		{"", true, false},
+		{FeedContentSummary, true, false},
+		{FeedContentFull, true, true},
+		{FeedContentNone, false, false},
+	} {
+		t.Run("mode "+tt.mode, func(t *testing.T) {
+			params := map[string]string{BaseURLRefKey: "https://example.com", FeedContentRefKey: tt.mode}
+			out, err := atomFeedXML("Test", "https://example.com/", "https://example.com/feed/atom.xml", g.feedItems([]*Content{post}, params), time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var af atomFeed
+			if err := xml.Unmarshal(out, &af); err != nil {
+				t.Fatalf("atom feed does not parse: %v", err)
+			}
+			entry := af.Entries[0]
+			if got := entry.Summary == "A short take."; got != tt.wantSummary {
+				t.Errorf("summary = %q, want it %v", entry.Summary, tt.wantSummary)
+			}
+			if got := entry.Content != nil; got != tt.wantContent {
+				t.Fatalf("has content = %v, want %v:\n%s", got, tt.wantContent, out)
+			}
+			if !tt.wantContent {
+				return
+			}
+			body := entry.Content.Body
+			for _, want := range []string{`src="https://example.com/images/x.png"`, `src="https://example.com/images/y.png"`,
+				`srcset="https://example.com/images/y.png 1x, https://example.com/images/y@2x.png 2x"`, ">Click</a>"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("content has no %s:\n%s", want, body)
+				}
+			}
+			for _, unsafe := range []string{"<script", "onerror", "javascript:"} {
+				if strings.Contains(body, unsafe) {
+					t.Errorf("content keeps %s:\n%s", unsafe, body)
+				}
+			}
+		})
+	}
+
+	params := map[string]string{BaseURLRefKey: "https://example.com"}
+	out, err := jsonFeedJSON("Test", "https://example.com/", "https://example.com/feed/feed.json", g.feedItems([]*Content{post}, params), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jf jsonFeed
+	if err := json.Unmarshal(out, &jf); err != nil {
+		t.Fatal(err)
+	}
+	if it := jf.Items[0]; it.ContentHTML != "" || it.ContentText == nil || *it.ContentText != "A short take." {
+		t.Errorf("summary json feed item = %+v, want the summary as content_text", it)
+	}
+}
+
+func TestRenderIndexPagesFeedLinks(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	tmpl := template.Must(template.New("layout.html").Parse(`{{ range .Feeds }}{{ .URL }} {{ end }}`))
+
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	blog := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	changelog := &Section{ID: uuid.New(), Name: "Changelog", Path: "changelog"}
+	past := time.Now().Add(-time.Hour)
+	contents := []*Content{
+		{ID: uuid.New(), ShortID: "b0000001", SectionID: blog.ID, Heading: "Post", PublishedAt: &past},
+		{ID: uuid.New(), ShortID: "b0000002", SectionID: changelog.ID, Heading: "v1", PublishedAt: &past},
+	}
+	params := map[string]string{
+		BaseURLRefKey:      "https://example.com",
+		FeedFormatsRefKey:  "atom",
+		FeedSectionsRefKey: "blog",
+	}
+
+	if _, _, err := g.renderIndexPages(tmpl, nil, nil, nil, g.workspace.GetHTMLPath(site.Slug), site, contents, []*Section{blog, changelog}, nil, params); err != nil {
+		t.Fatalf("renderIndexPages() error = %v", err)
+	}
+
+	page := func(listPath string) string {
+		data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, listPath, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if got := page(""); got != "https://example.com/feed/atom.xml " {
+		t.Errorf("home feeds = %q", got)
+	}
+	if got := page("blog"); got != "https://example.com/blog/feed/atom.xml " {
+		t.Errorf("blog feeds = %q", got)
+	}
+	if got := page("changelog"); got != "" {
+		t.Errorf("changelog has no feed, got %q", got)
+	}
+}
+
+func TestValidateFeeds(t *testing.T) {
+	g := &HTMLGenerator{processor: NewProcessor()}
+	htmlPath := t.TempDir()
+	site := &Site{ID: uuid.New(), Name: "Q&A <Notes> 🚀", Slug: "test"}
+	blog := &Section{ID: uuid.New(), Name: "Tips & Tricks", Path: "blog"}
+	tag := &Tag{ID: uuid.New(), Name: "R&D", Slug: "r-d"}
+
+	published := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	contents := []*Content{
+		{ID: uuid.New(), ShortID: "c0000001", SectionID: blog.ID, SectionPath: "blog", Heading: "Fish & Chips", Summary: "Is 1 < 2?", Body: "AT&T <b>bold</b>", PublishedAt: &published, UpdatedAt: published, Tags: []*Tag{tag}},
+		{ID: uuid.New(), ShortID: "c0000002", SectionID: blog.ID, SectionPath: "blog", Heading: "Launch 🚀 <soon>", Body: "Emoji 🎉", PublishedAt: &published, UpdatedAt: published, ContributorHandle: "a&b"},
+	}
+	params := map[string]string{
+		BaseURLRefKey:     "https://example.com",
+		FeedFormatsRefKey: "atom,rss,json",
+		FeedTagsRefKey:    "true",
+	}
+	if _, err := g.generateFeeds(nil, htmlPath, site, contents, []*Section{blog}, params); err != nil {
+		t.Fatalf("generateFeeds() error = %v", err)
+	}
+
+	report, err := validateFeeds(htmlPath)
+	if err != nil {
+		t.Fatalf("validateFeeds() error = %v", err)
+	}
+	if report.Feeds != 9 || len(report.Problems) != 0 {
+		t.Fatalf("validateFeeds() = %d feeds, problems %v, want 9 and none", report.Feeds, report.Problems)
+	}
+
+	data, err := os.ReadFile(filepath.Join(htmlPath, "blog", "feed", "atom.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var atom atomFeed
+	if err := xml.Unmarshal(data, &atom); err != nil {
+		t.Fatal(err)
+	}
+	if atom.Title != "Q&A <Notes> 🚀 - Tips & Tricks" || atom.Entries[0].Title != "Fish & Chips" || atom.Entries[1].Title != "Launch 🚀 <soon>" {
+		t.Errorf("titles did not round-trip: %q, %+v", atom.Title, atom.Entries)
+	}
+
+	os.WriteFile(filepath.Join(htmlPath, "feed", "rss.xml"), []byte(`<rss version="2.0"><channel><title>Fish & Chips</title></channel></rss>`), 0644)
+	os.WriteFile(filepath.Join(htmlPath, "feed", "atom.xml"), []byte(`<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title><entry><title>E</title></entry></feed>`), 0644)
+
+	report, _ = validateFeeds(htmlPath)
+	got := map[string][]string{}
+	for _, p := range report.Problems {
+		got[p.Feed] = append(got[p.Feed], p.Message)
+	}
+	if len(got["feed/rss.xml"]) != 1 || !strings.HasPrefix(got["feed/rss.xml"][0], "not well-formed XML") {
+		t.Errorf("rss problems = %v, want the unescaped ampersand", got["feed/rss.xml"])
+	}
+	wantAtom := []string{
+		"feed has no id",
+		"feed has no updated date",
+		"feed has no self link",
+		"entry 1 has no id",
+		"entry 1 has no link",
+		"entry 1 has no updated date",
+	}
+	if strings.Join(got["feed/atom.xml"], "\n") != strings.Join(wantAtom, "\n") {
+		t.Errorf("atom problems = %q, want %q", got["feed/atom.xml"], wantAtom)
+	}
+
+	if report, err := validateFeeds(filepath.Join(htmlPath, "missing")); err != nil || report.Feeds != 0 {
+		t.Errorf("missing output = %+v, %v, want an empty report", report, err)
+	}
+}
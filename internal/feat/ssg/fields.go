@@ -0,0 +1,191 @@
+package ssg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Types of custom field values.
+const (
+	FieldTypeString = "string"
+	FieldTypeNumber = "number"
+	FieldTypeBool   = "bool"
+	FieldTypeDate   = "date"
+)
+
+// FieldTypes lists the field types in the order the content form offers them.
+var FieldTypes = []string{FieldTypeString, FieldTypeNumber, FieldTypeBool, FieldTypeDate}
+
+// fieldDateLayout is how date fields are entered and stored.
+const fieldDateLayout = "2006-01-02"
+
+// ErrInvalidField is returned for custom fields with an invalid name, type or value.
+var ErrInvalidField = errors.New("invalid field")
+
+// ContentField is a custom value of a content that the fixed fields do not
+// cover, such as the cook time of a recipe or the rating of a review.
+// Templates read them by name from .Fields, typed, see Content.Fields.
+type ContentField struct {
+	ID        uuid.UUID `json:"id"`
+	ContentID uuid.UUID `json:"content_id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`  // One of FieldTypes
+	Value     string    `json:"value"` // Canonical text of the value, see Normalize
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewContentField creates a new ContentField instance.
+func NewContentField(contentID uuid.UUID, name, fieldType, value string) *ContentField {
+	now := time.Now()
+	return &ContentField{
+		ID:        uuid.New(),
+		ContentID: contentID,
+		Name:      name,
+		Type:      fieldType,
+		Value:     value,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// fieldNameRe keeps names usable as .Fields.name in templates.
+var fieldNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Normalize checks the name and type of the field and rewrites its value in
+// the canonical form of its type: numbers without trailing zeros, booleans
+// as true or false and dates as YYYY-MM-DD. An empty type means string.
+func (f *ContentField) Normalize() error {
+	f.Name = strings.TrimSpace(f.Name)
+	if !fieldNameRe.MatchString(f.Name) {
+		return fmt.Errorf("%w %q: names start with a letter and use only letters, digits and underscores", ErrInvalidField, f.Name)
+	}
+	if f.Type == "" {
+		f.Type = FieldTypeString
+	}
+	value, err := NormalizeFieldValue(f.Type, f.Value)
+	if err != nil {
+		return fmt.Errorf("%w %s: %v", ErrInvalidField, f.Name, err)
+	}
+	f.Value = value
+	return nil
+}
+
+// NormalizeFieldValue checks raw is a value of fieldType and returns its
+// canonical text. Strings are kept as they are.
+func NormalizeFieldValue(fieldType, raw string) (string, error) {
+	v := strings.TrimSpace(raw)
+	switch fieldType {
+	case FieldTypeString:
+		return raw, nil
+	case FieldTypeNumber:
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return "", fmt.Errorf("%q is not a number", raw)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case FieldTypeBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", fmt.Errorf("%q is not true or false", raw)
+		}
+		return strconv.FormatBool(b), nil
+	case FieldTypeDate:
+		t, err := time.Parse(fieldDateLayout, v)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a date as YYYY-MM-DD", raw)
+		}
+		return t.Format(fieldDateLayout), nil
+	}
+	return "", fmt.Errorf("unknown type %q", fieldType)
+}
+
+// TypedValue returns the value as templates get it: a string, a float64, a
+// bool or a time.Time. Values that no longer parse are returned as text.
+func (f *ContentField) TypedValue() any {
+	switch f.Type {
+	case FieldTypeNumber:
+		if n, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			return n
+		}
+	case FieldTypeBool:
+		if b, err := strconv.ParseBool(f.Value); err == nil {
+			return b
+		}
+	case FieldTypeDate:
+		if t, err := time.Parse(fieldDateLayout, f.Value); err == nil {
+			return t
+		}
+	}
+	return f.Value
+}
+
+// fieldValues returns the typed values of fields by name, nil when there are none.
+func fieldValues(fields []*ContentField) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+	values := make(map[string]any, len(fields))
+	for _, f := range fields {
+		values[f.Name] = f.TypedValue()
+	}
+	return values
+}
+
+// frontmatterFields returns the typed values of a content for its
+// frontmatter. Dates are written as plain YAML dates so they read back as
+// dates rather than strings.
+func frontmatterFields(values map[string]any) map[string]any {
+	if len(values) == 0 {
+		return nil
+	}
+	fm := make(map[string]any, len(values))
+	for name, v := range values {
+		if t, ok := v.(time.Time); ok {
+			v = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: t.Format(fieldDateLayout)}
+		}
+		fm[name] = v
+	}
+	return fm
+}
+
+// fieldsFromFrontmatter turns the fields of a frontmatter back into content
+// fields, sorted by name, taking each type from its YAML value. Values that
+// are not a string, number, boolean or date, such as lists, are skipped.
+func fieldsFromFrontmatter(contentID uuid.UUID, values map[string]any) []*ContentField {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []*ContentField
+	for _, name := range names {
+		var fieldType, value string
+		switch v := values[name].(type) {
+		case string:
+			fieldType, value = FieldTypeString, v
+		case int:
+			fieldType, value = FieldTypeNumber, strconv.Itoa(v)
+		case float64:
+			fieldType, value = FieldTypeNumber, strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			fieldType, value = FieldTypeBool, strconv.FormatBool(v)
+		case time.Time:
+			fieldType, value = FieldTypeDate, v.Format(fieldDateLayout)
+		default:
+			continue
+		}
+		fields = append(fields, NewContentField(contentID, name, fieldType, value))
+	}
+	return fields
+}
@@ -0,0 +1,124 @@
+package ssg
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestContentFieldNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		fieldType string
+		value     string
+		want      string
+		wantErr   bool
+	}{
+		{"text is kept as is", "subtitle", FieldTypeString, " A side note ", " A side note ", false},
+		{"empty type is text", "subtitle", "", "x", "x", false},
+		{"number", "cook_time", FieldTypeNumber, " 45.50 ", "45.5", false},
+		{"not a number", "cook_time", FieldTypeNumber, "soon", "", true},
+		{"infinite number", "cook_time", FieldTypeNumber, "Inf", "", true},
+		{"bool", "vegan", FieldTypeBool, "TRUE", "true", false},
+		{"not a bool", "vegan", FieldTypeBool, "maybe", "", true},
+		{"date", "released", FieldTypeDate, "2024-03-07", "2024-03-07", false},
+		{"not a date", "released", FieldTypeDate, "07/03/2024", "", true},
+		{"unknown type", "rating", "stars", "5", "", true},
+		{"name with a hyphen", "cook-time", FieldTypeNumber, "45", "", true},
+		{"name starting with a digit", "2nd", FieldTypeString, "x", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewContentField(uuid.New(), tt.fieldName, tt.fieldType, tt.value)
+			err := f.Normalize()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidField) {
+					t.Errorf("Normalize() error = %v, want ErrInvalidField", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize() error = %v", err)
+			}
+			if f.Value != tt.want {
+				t.Errorf("Normalize() value = %q, want %q", f.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentFieldsFrontmatter(t *testing.T) {
+	released := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+	content := &Content{ID: uuid.New(), ShortID: "abc12345", Heading: "Pancakes", Body: "Mix and fry.",
+		Fields: fieldValues([]*ContentField{
+			{Name: "cook_time", Type: FieldTypeNumber, Value: "20"},
+			{Name: "rating", Type: FieldTypeNumber, Value: "4.5"},
+			{Name: "vegan", Type: FieldTypeBool, Value: "false"},
+			{Name: "released", Type: FieldTypeDate, Value: "2024-03-07"},
+			{Name: "serves", Type: FieldTypeString, Value: "4"},
+		})}
+
+	data, err := MarshalContentMarkdown(content, time.UTC)
+	if err != nil {
+		t.Fatalf("MarshalContentMarkdown() error = %v", err)
+	}
+	if !strings.Contains(string(data), "    released: 2024-03-07\n") || !strings.Contains(string(data), `    serves: "4"`) {
+		t.Errorf("frontmatter does not keep the field types:\n%s", data)
+	}
+
+	fm, _, err := UnmarshalContentMarkdown(string(data))
+	if err != nil {
+		t.Fatalf("UnmarshalContentMarkdown() error = %v", err)
+	}
+	got := make(map[string]*ContentField)
+	for _, f := range fieldsFromFrontmatter(content.ID, fm.Fields) {
+		got[f.Name] = f
+	}
+	want := map[string][2]string{
+		"cook_time": {FieldTypeNumber, "20"},
+		"rating":    {FieldTypeNumber, "4.5"},
+		"vegan":     {FieldTypeBool, "false"},
+		"released":  {FieldTypeDate, "2024-03-07"},
+		"serves":    {FieldTypeString, "4"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("read back %d fields, want %d", len(got), len(want))
+	}
+	for name, w := range want {
+		if f := got[name]; f == nil || f.Type != w[0] || f.Value != w[1] {
+			t.Errorf("field %s = %+v, want %s %s", name, f, w[0], w[1])
+		}
+	}
+	if v := fieldValues([]*ContentField{got["released"]})["released"]; v != released {
+		t.Errorf("released = %v, want %v", v, released)
+	}
+}
+
+func TestContentPageFields(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "Recipes", Slug: "recipes"}
+	content := &Content{ID: uuid.New(), SiteID: site.ID, Heading: "Pancakes",
+		Fields: fieldValues([]*ContentField{
+			{Name: "rating", Type: FieldTypeNumber, Value: "4.5"},
+			{Name: "vegan", Type: FieldTypeBool, Value: "true"},
+			{Name: "released", Type: FieldTypeDate, Value: "2024-03-07"},
+		})}
+	rendered := &RenderedContent{Content: content}
+
+	data, _ := g.contentPageData(nil, site, rendered, adjacentLinks{}, nil, nil, map[string]string{}, nil, BlocksConfig{})
+
+	tmpl := template.Must(template.New("page").Parse(
+		`{{ .Fields.rating }}|{{ if .Fields.vegan }}vegan{{ end }}|{{ .Fields.released.Format "2 Jan 2006" }}|{{ .Content.Fields.rating }}|{{ .Fields.missing }}`))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := b.String(), "4.5|vegan|7 Mar 2024|4.5|"; got != want {
+		t.Errorf("page = %q, want %q", got, want)
+	}
+}
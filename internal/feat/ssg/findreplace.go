@@ -0,0 +1,182 @@
+package ssg
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxFindReplaceChanges caps the content a find and replace run changes.
+// Running it again picks up the rest.
+const maxFindReplaceChanges = 100
+
+// findReplaceContext is how many characters around a match previews show.
+const findReplaceContext = 40
+
+// FindReplaceOptions tunes how FindAndReplace matches.
+type FindReplaceOptions struct {
+	CaseSensitive bool
+	WholeWord     bool // matches must not be part of a longer word
+	SkipCode      bool // leave fenced code blocks and inline code alone
+	Apply         bool // change the content; otherwise only report what would change
+	Limit         int  // most content changed per run, 0 for maxFindReplaceChanges
+	UserID        uuid.UUID
+}
+
+func (o FindReplaceOptions) limit() int {
+	if o.Limit > 0 {
+		return o.Limit
+	}
+	return maxFindReplaceChanges
+}
+
+// FindReplaceResult reports a find and replace run. In a dry run Matches
+// lists all matching content; when applied, the content changed and the
+// content skipped because someone is editing it.
+type FindReplaceResult struct {
+	Find         string
+	Replace      string
+	Options      FindReplaceOptions
+	Matches      []*FindReplaceMatch
+	Matched      int // content with at least one match
+	TotalMatches int
+	Changed      int // content updated, when applied
+	Remaining    int // matching content left for another run by the cap
+	Limit        int
+}
+
+// FindReplaceMatch is the content one find and replace run matches.
+type FindReplaceMatch struct {
+	Content *Content
+	Count   int
+	// Before, Match and After preview the first match in context.
+	Before, Match, After string
+	LockedBy             string // user editing the content, which is left alone
+}
+
+// textMatcher finds the places a find and replace run changes in a body.
+type textMatcher struct {
+	re   *regexp.Regexp
+	opts FindReplaceOptions
+}
+
+func newTextMatcher(find string, opts FindReplaceOptions) *textMatcher {
+	pattern := regexp.QuoteMeta(find)
+	if !opts.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return &textMatcher{re: regexp.MustCompile(pattern), opts: opts}
+}
+
+// matches returns the byte ranges of the matches in body.
+func (m *textMatcher) matches(body string) [][]int {
+	var skip [][]int
+	if m.opts.SkipCode {
+		skip = markdownCodeRanges(body)
+	}
+	var found [][]int
+	for _, loc := range m.re.FindAllStringIndex(body, -1) {
+		if m.opts.WholeWord && !wordBounded(body, loc[0], loc[1]) {
+			continue
+		}
+		if inRanges(skip, loc[0]) {
+			continue
+		}
+		found = append(found, loc)
+	}
+	return found
+}
+
+// replace returns body with every match replaced by replacement, taken
+// literally.
+func (m *textMatcher) replace(body, replacement string, matches [][]int) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range matches {
+		b.WriteString(body[last:loc[0]])
+		b.WriteString(replacement)
+		last = loc[1]
+	}
+	b.WriteString(body[last:])
+	return b.String()
+}
+
+// preview fills the context of the first match of body into match.
+func (m *textMatcher) preview(match *FindReplaceMatch, body string, loc []int) {
+	start := max(loc[0]-findReplaceContext, 0)
+	for start > 0 && !utf8.RuneStart(body[start]) {
+		start--
+	}
+	end := min(loc[1]+findReplaceContext, len(body))
+	for end < len(body) && !utf8.RuneStart(body[end]) {
+		end++
+	}
+	match.Before = body[start:loc[0]]
+	match.Match = body[loc[0]:loc[1]]
+	match.After = body[loc[1]:end]
+	if start > 0 {
+		match.Before = "…" + match.Before
+	}
+	if end < len(body) {
+		match.After += "…"
+	}
+}
+
+// wordBounded reports whether body[start:end] is not preceded or followed
+// by a letter, digit or underscore.
+func wordBounded(body string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(body[:start]); start > 0 && isWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(body[end:]); end < len(body) && isWordRune(r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+var inlineCodeRe = regexp.MustCompile("`[^`\n]+`")
+
+// markdownCodeRanges returns the byte ranges of the fenced code blocks and
+// inline code spans of a Markdown body.
+func markdownCodeRanges(body string) [][]int {
+	var ranges [][]int
+	fence := ""
+	fenceStart := 0
+	offset := 0
+	for _, line := range strings.SplitAfter(body, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+			fenceStart = offset
+		case fence != "" && strings.HasPrefix(trimmed, fence):
+			ranges = append(ranges, []int{fenceStart, offset + len(line)})
+			fence = ""
+		case fence == "":
+			for _, loc := range inlineCodeRe.FindAllStringIndex(line, -1) {
+				ranges = append(ranges, []int{offset + loc[0], offset + loc[1]})
+			}
+		}
+		offset += len(line)
+	}
+	if fence != "" {
+		ranges = append(ranges, []int{fenceStart, len(body)})
+	}
+	return ranges
+}
+
+func inRanges(ranges [][]int, pos int) bool {
+	for _, r := range ranges {
+		if pos >= r[0] && pos < r[1] {
+			return true
+		}
+	}
+	return false
+}
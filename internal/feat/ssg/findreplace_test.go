@@ -0,0 +1,136 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTextMatcher(t *testing.T) {
+	body := "Go is great. go, Golang and GO.\n\n```go\ngo run .\n```\n\nRun `go test` to go on."
+
+	tests := []struct {
+		name string
+		find string
+		opts FindReplaceOptions
+		want int
+	}{
+		{"case insensitive", "go", FindReplaceOptions{}, 8},
+		{"case sensitive", "go", FindReplaceOptions{CaseSensitive: true}, 5},
+		{"whole word", "go", FindReplaceOptions{WholeWord: true}, 7},
+		{"skip code", "go", FindReplaceOptions{SkipCode: true}, 5},
+		{"whole word outside code", "go", FindReplaceOptions{WholeWord: true, SkipCode: true}, 4},
+		{"regexp characters are literal", "great.", FindReplaceOptions{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTextMatcher(tt.find, tt.opts).matches(body)
+			if len(got) != tt.want {
+				t.Errorf("matches() found %d, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	t.Run("replace", func(t *testing.T) {
+		m := newTextMatcher("café", FindReplaceOptions{WholeWord: true})
+		body := "Café, cafés and café_bar at the café."
+		if got, want := m.replace(body, "$1 bar", m.matches(body)), "$1 bar, cafés and café_bar at the $1 bar."; got != want {
+			t.Errorf("replace() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("unclosed fence runs to the end", func(t *testing.T) {
+		m := newTextMatcher("go", FindReplaceOptions{SkipCode: true})
+		if got := m.matches("go\n~~~\ngo"); len(got) != 1 {
+			t.Errorf("matches() found %d, want 1", len(got))
+		}
+	})
+}
+
+func TestServiceFindAndReplace(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Replace Site", "replace-site")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection() error = %v", err)
+	}
+
+	bodies := []string{
+		"Clio is a CMS. Clio rocks.",
+		"We use clio daily.",
+		"Cliosg is not a match.",
+		"Write `clio` in code, and Clio outside.",
+	}
+	for _, body := range bodies {
+		content := NewContent(site.ID, section.ID, "Post", body)
+		content.CreatedBy = uuid.New()
+		content.UpdatedBy = content.CreatedBy
+		if err := svc.CreateContent(ctx, content); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+	}
+
+	opts := FindReplaceOptions{WholeWord: true, SkipCode: true, Limit: 2}
+	preview, err := svc.FindAndReplace(ctx, site.ID, "clio", "ClioSSG", opts)
+	if err != nil {
+		t.Fatalf("FindAndReplace() dry run error = %v", err)
+	}
+	if preview.Matched != 3 || preview.TotalMatches != 4 || len(preview.Matches) != 3 {
+		t.Fatalf("dry run matched %d items, %d matches, %d listed; want 3, 4, 3",
+			preview.Matched, preview.TotalMatches, len(preview.Matches))
+	}
+	for _, m := range preview.Matches {
+		stored, _ := svc.GetContent(ctx, m.Content.ID)
+		if stored.Body != m.Content.Body {
+			t.Errorf("dry run changed %q", m.Content.Body)
+		}
+	}
+
+	userID := uuid.New()
+	opts.Apply = true
+	opts.UserID = userID
+	applied, err := svc.FindAndReplace(ctx, site.ID, "clio", "ClioSSG", opts)
+	if err != nil {
+		t.Fatalf("FindAndReplace() error = %v", err)
+	}
+	if applied.Changed != 2 || applied.Remaining != 1 {
+		t.Errorf("applied changed %d, remaining %d; want 2, 1", applied.Changed, applied.Remaining)
+	}
+
+	changedMatches := 0
+	for _, m := range applied.Matches {
+		stored, _ := svc.GetContent(ctx, m.Content.ID)
+		if len(newTextMatcher("clio", opts).matches(stored.Body)) != 0 || stored.UpdatedBy != userID {
+			t.Errorf("content not updated: %q", stored.Body)
+		}
+		revisions, err := svc.ListContentRevisions(ctx, m.Content.ID)
+		if err != nil || len(revisions) != 1 {
+			t.Fatalf("ListContentRevisions() = %d, %v; want the previous body", len(revisions), err)
+		}
+		changedMatches += m.Count
+	}
+
+	rest, err := svc.FindAndReplace(ctx, site.ID, "clio", "ClioSSG", opts)
+	if err != nil {
+		t.Fatalf("FindAndReplace() second run error = %v", err)
+	}
+	if rest.Changed != 1 || rest.Remaining != 0 {
+		t.Errorf("second run changed %d, remaining %d; want 1, 0", rest.Changed, rest.Remaining)
+	}
+	if changedMatches+rest.Matches[0].Count != preview.TotalMatches {
+		t.Errorf("applied %d matches, dry run counted %d", changedMatches+rest.Matches[0].Count, preview.TotalMatches)
+	}
+
+	t.Run("empty find", func(t *testing.T) {
+		if _, err := svc.FindAndReplace(ctx, site.ID, "", "x", FindReplaceOptions{}); !errors.Is(err, ErrEmptyFind) {
+			t.Errorf("FindAndReplace() error = %v, want ErrEmptyFind", err)
+		}
+	})
+}
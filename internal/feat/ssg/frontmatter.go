@@ -0,0 +1,243 @@
+package ssg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ContentFrontmatter is the YAML frontmatter of a content Markdown file. It is
+// written by the Markdown backup and read back by import and restore, so a
+// backup can be restored without losing data.
+//
+// A file is laid out as:
+//
+//	---
+//	<frontmatter>
+//	---
+//
+//	<body>
+//
+// Exactly one blank line separates the closing delimiter from the body; any
+// further leading blank lines belong to the body. Optional fields are omitted
+// when empty. Dates use RFC 3339 with the offset of the site timezone.
+type ContentFrontmatter struct {
+	Title            string         `yaml:"title"`
+	Slug             string         `yaml:"slug"`
+	ShortID          string         `yaml:"short-id,omitempty"`
+	Section          string         `yaml:"section,omitempty"`
+	Author           string         `yaml:"author,omitempty"`
+	Contributor      string         `yaml:"contributor,omitempty"`
+	Tags             []string       `yaml:"tags,omitempty"`
+	Layout           string         `yaml:"layout,omitempty"` // Informational, ignored on import
+	Draft            bool           `yaml:"draft"`
+	Featured         bool           `yaml:"featured"`
+	Visibility       string         `yaml:"visibility,omitempty"` // Omitted when public
+	Summary          string         `yaml:"summary,omitempty"`
+	Description      string         `yaml:"description,omitempty"`
+	Image            string         `yaml:"image,omitempty"`
+	SocialImage      string         `yaml:"social-image,omitempty"`
+	PublishedAt      *time.Time     `yaml:"published-at,omitempty"`
+	CreatedAt        time.Time      `yaml:"created-at"`
+	UpdatedAt        time.Time      `yaml:"updated-at"`
+	Robots           string         `yaml:"robots,omitempty"`
+	Keywords         string         `yaml:"keywords,omitempty"`
+	CanonicalURL     string         `yaml:"canonical-url,omitempty"`
+	Sitemap          string         `yaml:"sitemap,omitempty"`
+	TableOfContents  bool           `yaml:"table-of-contents,omitempty"`
+	Comments         bool           `yaml:"comments,omitempty"`
+	Share            bool           `yaml:"share,omitempty"`
+	KeepLinks        bool           `yaml:"keep-links,omitempty"`
+	ShowUpdated      bool           `yaml:"show-updated,omitempty"`
+	Kind             string         `yaml:"kind,omitempty"`
+	LinkURL          string         `yaml:"link-url,omitempty"`
+	Series           string         `yaml:"series,omitempty"`
+	SeriesOrder      int            `yaml:"series-order,omitempty"`
+	Lang             string         `yaml:"lang,omitempty"`
+	TranslationGroup string         `yaml:"translation-group,omitempty"`
+	Fields           map[string]any `yaml:"fields,omitempty"` // Custom fields, typed by their YAML value
+}
+
+// NewContentFrontmatter builds the frontmatter for a content item, including
+// its tags, meta and custom fields when loaded.
+func NewContentFrontmatter(content *Content) *ContentFrontmatter {
+	fm := &ContentFrontmatter{
+		Title:            content.Heading,
+		Slug:             content.Slug(),
+		ShortID:          content.ShortID,
+		Section:          content.SectionPath,
+		Author:           content.AuthorUsername,
+		Contributor:      content.ContributorHandle,
+		Layout:           content.SectionName,
+		Draft:            content.Draft,
+		Featured:         content.Featured,
+		Summary:          content.Summary,
+		Image:            content.HeaderImageURL,
+		SocialImage:      content.HeaderImageURL,
+		PublishedAt:      content.PublishedAt,
+		CreatedAt:        content.CreatedAt,
+		UpdatedAt:        content.UpdatedAt,
+		Kind:             content.Kind,
+		LinkURL:          content.LinkURL,
+		Series:           content.Series,
+		SeriesOrder:      content.SeriesOrder,
+		Lang:             content.Lang,
+		TranslationGroup: content.TranslationGroup,
+		Fields:           frontmatterFields(content.Fields),
+	}
+
+	if content.Visibility != VisibilityPublic {
+		fm.Visibility = content.Visibility
+	}
+
+	if content.Meta != nil {
+		fm.Description = content.Meta.Description
+		fm.Robots = content.Meta.Robots
+		fm.Keywords = content.Meta.Keywords
+		fm.CanonicalURL = content.Meta.CanonicalURL
+		fm.Sitemap = content.Meta.Sitemap
+		fm.TableOfContents = content.Meta.TableOfContents
+		fm.Comments = content.Meta.Comments
+		fm.Share = content.Meta.Share
+		fm.KeepLinks = content.Meta.KeepLinks
+		fm.ShowUpdated = content.Meta.ShowUpdated
+	}
+
+	for _, tag := range content.Tags {
+		fm.Tags = append(fm.Tags, tag.Name)
+	}
+
+	return fm
+}
+
+// MarshalContentMarkdown renders a content item as a Markdown file with
+// frontmatter, with dates in loc. A nil loc means UTC.
+func MarshalContentMarkdown(content *Content, loc *time.Location) ([]byte, error) {
+	fm := NewContentFrontmatter(content)
+	fm.inLocation(loc)
+
+	yamlBytes, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal frontmatter: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(yamlBytes)
+	buf.WriteString("---\n\n")
+	buf.WriteString(content.Body)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalContentMarkdown parses a file written by MarshalContentMarkdown.
+// It returns nil frontmatter and the whole input as body when the file has none.
+func UnmarshalContentMarkdown(data string) (*ContentFrontmatter, string, error) {
+	raw, body, ok := splitFrontmatter(data)
+	if !ok {
+		return nil, data, nil
+	}
+
+	fm := &ContentFrontmatter{}
+	if err := yaml.Unmarshal([]byte(raw), fm); err != nil {
+		return nil, data, fmt.Errorf("cannot parse frontmatter: %w", err)
+	}
+	return fm, body, nil
+}
+
+// splitFrontmatter separates the frontmatter block from the body. Only the
+// first closing delimiter counts, so "---" rules in the body are kept.
+func splitFrontmatter(data string) (string, string, bool) {
+	rest, ok := cutLine(data, "---")
+	if !ok {
+		return "", data, false
+	}
+
+	var fm strings.Builder
+	for rest != "" {
+		line, next := nextLine(rest)
+		if strings.TrimRight(line, "\r") == "---" {
+			body, _ := strings.CutPrefix(next, "\r\n")
+			if body == next {
+				body, _ = strings.CutPrefix(next, "\n")
+			}
+			return fm.String(), body, true
+		}
+		fm.WriteString(line)
+		fm.WriteString("\n")
+		rest = next
+	}
+	return "", data, false
+}
+
+// cutLine removes the first line of s if it equals want, ignoring a trailing \r.
+func cutLine(s, want string) (string, bool) {
+	line, rest := nextLine(s)
+	if strings.TrimRight(line, "\r") != want || len(line) == len(s) {
+		return s, false
+	}
+	return rest, true
+}
+
+// nextLine splits s at the first newline, dropping the newline itself.
+func nextLine(s string) (string, string) {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+// inLocation converts the dates to loc, or UTC when nil. The instants are unchanged.
+func (fm *ContentFrontmatter) inLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if fm.PublishedAt != nil {
+		published := fm.PublishedAt.In(loc)
+		fm.PublishedAt = &published
+	}
+	fm.CreatedAt = fm.CreatedAt.In(loc)
+	fm.UpdatedAt = fm.UpdatedAt.In(loc)
+}
+
+// applyTo copies the frontmatter fields stored on the content row itself.
+// Tags, meta, contributor, images and fields need lookups and are left to the caller.
+func (fm *ContentFrontmatter) applyTo(content *Content) {
+	if fm.Title != "" {
+		content.Heading = fm.Title
+	}
+	if fm.ShortID != "" {
+		content.ShortID = fm.ShortID
+	}
+	if fm.Kind != "" {
+		content.Kind = fm.Kind
+	}
+	content.Summary = fm.Summary
+	content.Draft = fm.Draft
+	content.Featured = fm.Featured
+	content.Visibility = normalizeVisibility(fm.Visibility)
+	content.LinkURL = fm.LinkURL
+	content.Series = fm.Series
+	content.SeriesOrder = fm.SeriesOrder
+	content.Lang = fm.Lang
+	content.TranslationGroup = fm.TranslationGroup
+	content.AuthorUsername = fm.Author
+	content.ContributorHandle = fm.Contributor
+	if fm.PublishedAt != nil {
+		content.PublishedAt = fm.PublishedAt
+	}
+	if !fm.CreatedAt.IsZero() {
+		content.CreatedAt = fm.CreatedAt
+	}
+	if !fm.UpdatedAt.IsZero() {
+		content.UpdatedAt = fm.UpdatedAt
+	}
+}
+
+// hasMeta reports whether any SEO meta field is set.
+func (fm *ContentFrontmatter) hasMeta() bool {
+	return fm.Description != "" || fm.Robots != "" || fm.Keywords != "" ||
+		fm.CanonicalURL != "" || fm.Sitemap != "" || fm.TableOfContents || fm.Comments || fm.Share || fm.KeepLinks || fm.ShowUpdated
+}
@@ -0,0 +1,228 @@
+package ssg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cliossg/clio/internal/testutil"
+	"github.com/cliossg/clio/pkg/cl/config"
+	"github.com/google/uuid"
+)
+
+func TestContentMarkdownRoundTrip(t *testing.T) {
+	published := time.Date(2025, 12, 17, 19, 57, 16, 0, time.UTC)
+	created := time.Date(2026, 1, 9, 19, 37, 45, 123456789, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name    string
+		content *Content
+	}{
+		{
+			name: "all fields",
+			content: &Content{
+				ShortID: "smpl005", Heading: "Buenos Aires: A \"Love\" Letter #1 — ¿qué tal?", Kind: "article",
+				SectionPath: "places", AuthorUsername: "admin", ContributorHandle: "johndoe",
+				Summary: "Tango, steak,\nand conversations that last until dawn.", Draft: false, Featured: true,
+				Series: "Travels", SeriesOrder: 3, PublishedAt: &published, CreatedAt: created, UpdatedAt: created,
+				Body: "# Heading\n\nFirst paragraph.\n\n---\n\n```yaml\n---\nkey: value\n---\n```\n",
+				Tags: []*Tag{{Name: "travel"}, {Name: "food & drink"}},
+				Meta: &Meta{Description: "A city: loved", Robots: "noindex", Keywords: "tango, steak", CanonicalURL: "https://example.com/ba", Sitemap: "weekly", TableOfContents: true, Comments: true, Share: true},
+			},
+		},
+		{
+			name:    "empty optional fields",
+			content: &Content{ShortID: "a1b2c3d4", Heading: "Bare", Kind: "post", Draft: true, CreatedAt: created, UpdatedAt: created},
+		},
+		{
+			name:    "body with leading blank lines and CRLF",
+			content: &Content{ShortID: "a1b2c3d5", Heading: "Spaced", Kind: "page", CreatedAt: created, UpdatedAt: created, Body: "\n\nLine one\r\nLine two\r\n"},
+		},
+		{
+			name:    "title that looks like YAML",
+			content: &Content{ShortID: "a1b2c3d6", Heading: "- yes: [no]", Kind: "post", CreatedAt: created, UpdatedAt: created, Body: "Body"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MarshalContentMarkdown(tt.content, nil)
+			if err != nil {
+				t.Fatalf("MarshalContentMarkdown() error = %v", err)
+			}
+
+			fm, body, err := UnmarshalContentMarkdown(string(data))
+			if err != nil {
+				t.Fatalf("UnmarshalContentMarkdown() error = %v\n%s", err, data)
+			}
+			if body != tt.content.Body {
+				t.Errorf("body = %q, want %q", body, tt.content.Body)
+			}
+
+			want := NewContentFrontmatter(tt.content)
+			if !fm.CreatedAt.Equal(want.CreatedAt) || !fm.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("dates = %v/%v, want %v/%v", fm.CreatedAt, fm.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+			}
+			if (fm.PublishedAt == nil) != (want.PublishedAt == nil) || (fm.PublishedAt != nil && !fm.PublishedAt.Equal(*want.PublishedAt)) {
+				t.Errorf("PublishedAt = %v, want %v", fm.PublishedAt, want.PublishedAt)
+			}
+			fm.CreatedAt, fm.UpdatedAt, fm.PublishedAt = want.CreatedAt, want.UpdatedAt, want.PublishedAt
+			if !reflect.DeepEqual(fm, want) {
+				t.Errorf("frontmatter = %+v\nwant %+v", fm, want)
+			}
+
+			restored := &Content{}
+			fm.applyTo(restored)
+			restored.Body = body
+			if restored.Heading != tt.content.Heading || restored.ShortID != tt.content.ShortID || restored.Slug() != tt.content.Slug() {
+				t.Errorf("restored heading/slug = %q/%q, want %q/%q", restored.Heading, restored.Slug(), tt.content.Heading, tt.content.Slug())
+			}
+		})
+	}
+}
+
+func TestContentMarkdownOmitsEmptyFields(t *testing.T) {
+	data, err := MarshalContentMarkdown(&Content{ShortID: "a1b2c3d4", Heading: "Bare", Kind: "post"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"summary:", "tags:", "series:", "published-at:", "description:", "contributor:"} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("expected %q to be omitted:\n%s", key, data)
+		}
+	}
+	if !strings.HasSuffix(string(data), "---\n\n") {
+		t.Errorf("expected empty body after separator, got %q", data)
+	}
+}
+
+func TestUnmarshalContentMarkdownWithoutFrontmatter(t *testing.T) {
+	for _, in := range []string{"Just a body", "---\ntitle: unclosed\nbody", "---"} {
+		fm, body, err := UnmarshalContentMarkdown(in)
+		if err != nil || fm != nil || body != in {
+			t.Errorf("UnmarshalContentMarkdown(%q) = %v, %q, %v; want nil frontmatter and input body", in, fm, body, err)
+		}
+	}
+}
+
+func TestMarkdownBackupRestoreRoundTrip(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, cfg, newTestLogger())
+	ctx := context.Background()
+	workspace := NewWorkspace(cfg.SSG.SitesBasePath)
+
+	source := createTestSite(t, svc, "Source", "source")
+	target := createTestSite(t, svc, "Target", "target")
+	for _, site := range []*Site{source, target} {
+		svc.CreateSection(ctx, NewSection(site.ID, "Places", "", "places"))
+		svc.CreateContributor(ctx, NewContributor(site.ID, "johndoe", "John", "Doe"))
+	}
+	section, _ := svc.GetSectionByPath(ctx, source.ID, "places")
+	contributor, _ := svc.GetContributorByHandle(ctx, source.ID, "johndoe")
+
+	published := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	original := NewContent(source.ID, section.ID, "Buenos Aires: A \"Love\" Letter", "Intro\n\n---\n\nMore: text\n")
+	original.Summary = "Tango &\nsteak"
+	original.Draft = false
+	original.Featured = true
+	original.Kind = "article"
+	original.Series = "Travels"
+	original.SeriesOrder = 2
+	original.PublishedAt = &published
+	original.ContributorID = &contributor.ID
+	original.ContributorHandle = contributor.Handle
+	if err := svc.CreateContent(ctx, original); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	svc.AddTagToContent(ctx, original.ID, "travel", source.ID)
+	svc.AddTagToContent(ctx, original.ID, "food & drink", source.ID)
+	meta := NewMeta(source.ID, original.ID)
+	meta.Description = "A city: loved"
+	meta.TableOfContents = true
+	svc.CreateMeta(ctx, meta)
+
+	contents, err := svc.GetAllContentWithMeta(ctx, source.ID)
+	if err != nil {
+		t.Fatalf("GetAllContentWithMeta() error = %v", err)
+	}
+	result, err := NewGenerator(workspace).GenerateMarkdown(ctx, source.Slug, contents, nil)
+	if err != nil || result.FilesGenerated != 1 {
+		t.Fatalf("GenerateMarkdown() = %+v, %v", result, err)
+	}
+
+	files, err := NewImportScanner([]string{workspace.GetMarkdownPath(source.Slug)}).ScanFiles()
+	if err != nil || len(files) != 1 {
+		t.Fatalf("ScanFiles() = %d files, %v", len(files), err)
+	}
+	if _, err := os.Stat(filepath.Join(workspace.GetMarkdownPath(source.Slug), "places", original.Slug()+".md")); err != nil {
+		t.Errorf("expected file named after the content slug: %v", err)
+	}
+
+	userID := uuid.New()
+	if _, err := db.Exec(`INSERT INTO user (id, short_id, email, password_hash, name, status, roles, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		userID.String(), "u123", "editor@test.com", "hash", "editor", "active", "editor"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	restored, _, err := svc.ImportFile(ctx, target.ID, userID, files[0], uuid.Nil)
+	if err != nil {
+		t.Fatalf("ImportFile() error = %v", err)
+	}
+	got, err := svc.GetContentWithMeta(ctx, restored.ID)
+	if err != nil {
+		t.Fatalf("GetContentWithMeta() error = %v", err)
+	}
+
+	targetSection, _ := svc.GetSectionByPath(ctx, target.ID, "places")
+	checks := []struct {
+		field     string
+		got, want any
+	}{
+		{"heading", got.Heading, original.Heading},
+		{"slug", got.Slug(), original.Slug()},
+		{"body", got.Body, original.Body},
+		{"summary", got.Summary, original.Summary},
+		{"draft", got.Draft, original.Draft},
+		{"featured", got.Featured, original.Featured},
+		{"kind", got.Kind, original.Kind},
+		{"series", got.Series, original.Series},
+		{"series order", got.SeriesOrder, original.SeriesOrder},
+		{"contributor", got.ContributorHandle, original.ContributorHandle},
+		{"section", got.SectionID, targetSection.ID},
+		{"published at", got.PublishedAt != nil && got.PublishedAt.Equal(published), true},
+		{"created at", got.CreatedAt.Equal(original.CreatedAt), true},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+		}
+	}
+	if got.ContributorID == nil || *got.ContributorID == contributor.ID {
+		t.Errorf("contributor should resolve to the target site's contributor, got %v", got.ContributorID)
+	}
+	gotMeta, err := svc.GetMetaByContentID(ctx, got.ID)
+	if err != nil || gotMeta.Description != meta.Description || !gotMeta.TableOfContents {
+		t.Errorf("meta = %+v, %v; want description %q with table of contents", gotMeta, err, meta.Description)
+	}
+
+	tags, _ := svc.GetTagsForContent(ctx, got.ID)
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	if strings.Join(names, ",") != "food & drink,travel" && strings.Join(names, ",") != "travel,food & drink" {
+		t.Errorf("tags = %v, want travel and food & drink", names)
+	}
+}
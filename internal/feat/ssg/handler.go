@@ -1,13 +1,17 @@
 package ssg
 
 import (
+	"compress/gzip"
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -16,8 +20,11 @@ import (
 
 	"github.com/cliossg/clio/internal/feat/profile"
 	"github.com/cliossg/clio/pkg/cl/config"
+	"github.com/cliossg/clio/pkg/cl/imaging"
 	"github.com/cliossg/clio/pkg/cl/llm"
+	"github.com/cliossg/clio/pkg/cl/git"
 	"github.com/cliossg/clio/pkg/cl/logger"
+	"github.com/cliossg/clio/pkg/cl/mail"
 	"github.com/cliossg/clio/pkg/cl/middleware"
 	"github.com/cliossg/clio/pkg/cl/render"
 	"github.com/go-chi/chi/v5"
@@ -40,6 +47,9 @@ type Handler struct {
 	metaLoader     *MetaLoader
 	htmlGen        *HTMLGenerator
 	publisher      *Publisher
+	scheduler      *Scheduler
+	publishQueue   *PublishQueue
+	mailer         mail.Mailer
 	llmClient      *llm.Client
 	siteCtxMw      func(http.Handler) http.Handler
 	sessionMw      func(http.Handler) http.Handler
@@ -67,6 +77,31 @@ func NewHandler(service Service, profileService ProfileService, workspace *Works
 	}
 }
 
+// SetScheduler sets the scheduler to reload when scheduling params change.
+func (h *Handler) SetScheduler(s *Scheduler) {
+	h.scheduler = s
+}
+
+// SetPublishQueue sets the queue publish requests are handed to.
+func (h *Handler) SetPublishQueue(q *PublishQueue) {
+	h.publishQueue = q
+}
+
+// SetMailer sets the mailer review notifications are sent with.
+func (h *Handler) SetMailer(m mail.Mailer) {
+	h.mailer = m
+}
+
+// reloadScheduler re-applies cron params after a scheduling setting changes.
+func (h *Handler) reloadScheduler(ctx context.Context, refKey string) {
+	if h.scheduler == nil || refKey != CronPublishRefKey {
+		return
+	}
+	if err := h.scheduler.Reload(ctx); err != nil {
+		h.log.Errorf("Cannot reload scheduler: %v", err)
+	}
+}
+
 // Start initializes templates and other resources.
 func (h *Handler) Start(ctx context.Context) error {
 	h.log.Info("SSG handler started")
@@ -150,6 +185,21 @@ func (h *Handler) requireEditor(next http.Handler) http.Handler {
 	})
 }
 
+// requireFeature answers 404 on the routes of a feature the site has turned
+// off, see FeatureContributors.
+func (h *Handler) requireFeature(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			site := getSiteFromContext(r.Context())
+			if site != nil && !h.featureEnabled(r.Context(), site.ID, key) {
+				h.renderError(w, r, http.StatusNotFound, "Page not found")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func (h *Handler) requireAdmin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		roles := middleware.GetUserRoles(r.Context())
@@ -190,6 +240,15 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Get("/ssg/edit-site", h.HandleEditSite)
 			r.Post("/ssg/update-site", h.HandleUpdateSite)
 			r.Post("/ssg/delete-site", h.HandleDeleteSite)
+			r.Get("/ssg/clone-site", h.HandleCloneSiteForm)
+			r.Post("/ssg/clone-site", h.HandleCloneSite)
+			r.Post("/admin/regenerate-all", h.HandleRegenerateAll)
+		})
+
+		// Site builds (editor+)
+		r.Group(func(r chi.Router) {
+			r.Use(h.requireEditor)
+			r.Get("/ssg/download-build", h.HandleDownloadBuild)
 		})
 
 		// Routes that need site context middleware
@@ -199,10 +258,14 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			// Read-only routes (viewer+)
 			r.Get("/ssg/list-contents", h.HandleListContents)
 			r.Get("/ssg/get-content", h.HandleShowContent)
+			r.Get("/ssg/content-diff", h.HandleContentDiff)
 			r.Get("/ssg/list-tags", h.HandleListTags)
 			r.Get("/ssg/get-tag", h.HandleShowTag)
 			r.Get("/ssg/list-images", h.HandleListImages)
 			r.Get("/ssg/get-image", h.HandleShowImage)
+			r.Get("/ssg/list-collections", h.HandleListCollections)
+			r.Get("/ssg/compile-series", h.HandleCompileSeries)
+			r.Get("/ssg/a11y-report", h.HandleAccessibilityReport)
 
 			// Editor routes (editor+)
 			r.Group(func(r chi.Router) {
@@ -211,28 +274,56 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				// Contents
 				r.Get("/ssg/new-content", h.HandleNewContent)
 				r.Post("/ssg/create-content", h.HandleCreateContent)
+				r.With(h.requireFeature(FeatureImports)).Get("/ssg/quick-import", h.HandleQuickImportForm)
+				r.With(h.requireFeature(FeatureImports)).Post("/ssg/quick-import", h.HandleQuickImport)
 				r.Get("/ssg/edit-content", h.HandleEditContent)
+				r.Get("/ssg/preview-content", h.HandlePreviewContent)
 				r.Post("/ssg/update-content", h.HandleUpdateContent)
 				r.Post("/ssg/autosave-content", h.HandleAutosaveContent)
+				r.Post("/ssg/reconcile-autosave", h.HandleReconcileAutosave)
+				r.Get("/ssg/edit-lock", h.HandleEditLock)
+				r.Post("/ssg/release-edit-lock", h.HandleReleaseEditLock)
 				r.Post("/ssg/proofread-content", h.HandleProofreadContent)
+				r.Post("/ssg/translate-content", h.HandleTranslateContent)
+				r.Post("/ssg/create-translation", h.HandleCreateTranslation)
 				r.Post("/ssg/delete-content", h.HandleDeleteContent)
+				r.Get("/ssg/move-content", h.HandleMoveContentForm)
+				r.Post("/ssg/move-content", h.HandleMoveContent)
+				r.Post("/ssg/export-selected", h.HandleExportSelected)
+				r.Post("/ssg/set-content-robots", h.HandleSetContentRobots)
+
+				// Reviews
+				r.Get("/ssg/review-queue", h.HandleReviewQueue)
+				r.Post("/ssg/submit-for-review", h.HandleSubmitForReview)
+				r.Post("/ssg/assign-reviewer", h.HandleAssignReviewer)
+				r.Post("/ssg/approve-content", h.HandleApproveContent)
+				r.Post("/ssg/request-changes", h.HandleRequestChanges)
 
 				// Tags
 				r.Get("/ssg/new-tag", h.HandleNewTag)
 				r.Post("/ssg/create-tag", h.HandleCreateTag)
 				r.Get("/ssg/edit-tag", h.HandleEditTag)
 				r.Post("/ssg/update-tag", h.HandleUpdateTag)
+				r.Post("/ssg/bulk-tag-content", h.HandleBulkTagContent)
 				r.Post("/ssg/delete-tag", h.HandleDeleteTag)
 
 				// Images
 				r.Get("/ssg/new-image", h.HandleNewImage)
 				r.Post("/ssg/create-image", h.HandleCreateImage)
+				r.Post("/ssg/bulk-upload-images", h.HandleBulkUploadImages)
 				r.Get("/ssg/edit-image", h.HandleEditImage)
 				r.Post("/ssg/update-image", h.HandleUpdateImage)
 				r.Post("/ssg/delete-image", h.HandleDeleteImage)
 
+				// Collections
+				r.Post("/ssg/create-collection", h.HandleCreateCollection)
+				r.Post("/ssg/update-collection", h.HandleUpdateCollection)
+				r.Post("/ssg/delete-collection", h.HandleDeleteCollection)
+
 				// Content Images
 				r.Post("/ssg/upload-content-image", h.HandleUploadContentImage)
+				r.Get("/ssg/search-images", h.HandleSearchImages)
+				r.Post("/ssg/link-content-image", h.HandleLinkContentImage)
 				r.Post("/ssg/delete-content-image", h.HandleDeleteContentImage)
 				r.Post("/ssg/remove-header-image", h.HandleRemoveHeaderImage)
 
@@ -243,6 +334,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				r.Post("/ssg/backup-markdown", h.HandleBackupMarkdown)
 				r.Post("/ssg/generate-html", h.HandleGenerateHTML)
 				r.Post("/ssg/publish", h.HandlePublish)
+				r.Get("/ssg/publish-status", h.HandlePublishStatus)
 			})
 
 			// Admin-only routes
@@ -256,7 +348,15 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				r.Post("/ssg/create-setting", h.HandleCreateSetting)
 				r.Get("/ssg/edit-setting", h.HandleEditSetting)
 				r.Post("/ssg/update-setting", h.HandleUpdateSetting)
+				r.Post("/ssg/reorder-settings", h.HandleReorderSettings)
+				r.Post("/ssg/preview-snippet", h.HandlePreviewSnippet)
 				r.Post("/ssg/delete-setting", h.HandleDeleteSetting)
+				r.Post("/ssg/upload-theme", h.HandleUploadTheme)
+
+				// Contents
+				r.Post("/ssg/break-edit-lock", h.HandleBreakEditLock)
+				r.Get("/ssg/find-replace", h.HandleFindReplace)
+				r.Post("/ssg/find-replace", h.HandleRunFindReplace)
 
 				// Sections
 				r.Get("/ssg/list-sections", h.HandleListSections)
@@ -275,30 +375,50 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 				r.Get("/ssg/edit-layout", h.HandleEditLayout)
 				r.Post("/ssg/update-layout", h.HandleUpdateLayout)
 				r.Post("/ssg/delete-layout", h.HandleDeleteLayout)
+				r.Post("/ssg/preview-layout", h.HandlePreviewLayout)
+				r.Get("/ssg/export-layout", h.HandleExportLayout)
+				r.Post("/ssg/import-layout", h.HandleImportLayout)
+
+				// Content kinds
+				r.Get("/ssg/list-kinds", h.HandleListKinds)
+				r.Get("/ssg/edit-kind", h.HandleEditKind)
+				r.Post("/ssg/save-kind", h.HandleSaveKind)
+				r.Post("/ssg/delete-kind", h.HandleDeleteKind)
 
 				// Section Images
 				r.Post("/ssg/upload-section-image", h.HandleUploadSectionImage)
 				r.Post("/ssg/delete-section-image", h.HandleDeleteSectionImage)
 
+				// Orphaned images
+				r.Get("/ssg/orphaned-images", h.HandleListOrphanedImages)
+				r.Post("/ssg/purge-orphaned-images", h.HandlePurgeOrphanedImages)
+
 				// Contributors
-				r.Get("/ssg/list-contributors", h.HandleListContributors)
-				r.Get("/ssg/new-contributor", h.HandleNewContributor)
-				r.Post("/ssg/create-contributor", h.HandleCreateContributor)
-				r.Get("/ssg/get-contributor", h.HandleShowContributor)
-				r.Get("/ssg/edit-contributor", h.HandleEditContributor)
-				r.Post("/ssg/update-contributor", h.HandleUpdateContributor)
-				r.Post("/ssg/delete-contributor", h.HandleDeleteContributor)
-				r.Get("/ssg/edit-contributor-profile", h.HandleEditContributorProfile)
-				r.Post("/ssg/update-contributor-profile", h.HandleUpdateContributorProfile)
-				r.Post("/ssg/upload-contributor-photo", h.HandleUploadContributorPhoto)
-				r.Post("/ssg/remove-contributor-photo", h.HandleRemoveContributorPhoto)
+				r.Group(func(r chi.Router) {
+					r.Use(h.requireFeature(FeatureContributors))
+					r.Get("/ssg/list-contributors", h.HandleListContributors)
+					r.Get("/ssg/new-contributor", h.HandleNewContributor)
+					r.Post("/ssg/create-contributor", h.HandleCreateContributor)
+					r.Get("/ssg/get-contributor", h.HandleShowContributor)
+					r.Get("/ssg/edit-contributor", h.HandleEditContributor)
+					r.Post("/ssg/update-contributor", h.HandleUpdateContributor)
+					r.Post("/ssg/delete-contributor", h.HandleDeleteContributor)
+					r.Get("/ssg/edit-contributor-profile", h.HandleEditContributorProfile)
+					r.Post("/ssg/update-contributor-profile", h.HandleUpdateContributorProfile)
+					r.Post("/ssg/upload-contributor-photo", h.HandleUploadContributorPhoto)
+					r.Post("/ssg/remove-contributor-photo", h.HandleRemoveContributorPhoto)
+				})
 
 				// Import (file import from external directories)
-				r.Get("/ssg/import/list", h.HandleListImport)
-				r.Post("/ssg/import/scan", h.HandleScanImport)
-				r.Get("/ssg/import/preview", h.HandlePreviewImport)
-				r.Post("/ssg/import/do", h.HandleDoImport)
-				r.Post("/ssg/import/reimport", h.HandleReimport)
+				r.Group(func(r chi.Router) {
+					r.Use(h.requireFeature(FeatureImports))
+					r.Get("/ssg/import/list", h.HandleListImport)
+					r.Post("/ssg/import/scan", h.HandleScanImport)
+					r.Get("/ssg/import/preview", h.HandlePreviewImport)
+					r.Post("/ssg/import/do", h.HandleDoImport)
+					r.Post("/ssg/import/reimport", h.HandleReimport)
+					r.Get("/ssg/import/diff", h.HandleImportDiff)
+				})
 
 				// Restore (rehydrate site from backup)
 				r.Get("/ssg/restore-markdown", h.HandleShowRestore)
@@ -318,22 +438,38 @@ type PageData struct {
 	CurrentUserRoles string
 	Site             *Site
 	Sites           []*Site
+	Stats           *SiteStats
+	UnpublishedChanges []*Content
+	StaleDrafts     *StaleDrafts // drafts untouched for ssg.drafts.stale_days, on the site page
+	NoIndex         bool // site is kept out of search engines, see NoIndexRefKey
+	Features        Features // feature flags of Site, filled in by render
 	Section         *Section
 	Sections        []*Section
 	Content         *Content
 	Contents        []*Content
+	Translations    []*Content // other languages of Content
+	ContentFields   []*ContentField // custom fields of Content, on its show and edit pages
 	Layout          *Layout
 	Layouts         []*Layout
+	ContentKind     *ContentKind
+	ContentKinds    []*ContentKind
 	Tag             *Tag
 	Tags            []*Tag
+	TagCandidates   []*Content // content the tag page offers to tag, see HandleShowTag
 	Setting           *Setting
-	Settings        []*Setting
+	SettingCategories []*SettingCategory
+	Themes          []string // themes the site can use, see HTMLGenerator.Themes
 	Image           *Image
 	Images          []*Image
+	ImageUsage      *ImageUsage
+	Collections      []*Collection
+	ImageCollections map[uuid.UUID]bool // collections of Image, on its edit page
 	Contributor          *Contributor
 	Contributors         []*Contributor
 	ContributorProfile   *profile.Profile
 	ProfileSocialLinks   map[string]string
+	SocialErrors         map[string]string
+	SocialPlatforms      []profile.SocialPlatform
 	HeaderImage     *ContentImageWithDetails
 	ContentImages   []*ContentImageWithDetails
 	SectionImages   []*SectionImageWithDetails
@@ -347,6 +483,18 @@ type PageData struct {
 	HasPrev         bool
 	HasNext         bool
 	Search          string
+	Filter          ContentFilter
+	ImageFilter     ImageFilter
+	FilterQuery     template.URL
+	PathWarnings    []*PathCollision
+	PublicURL       *PublicURL
+	EditLock        *EditLock // held by another user, Content is read only
+	Review          *ContentReview // of Content, nil when it was never submitted
+	Reviews         []*ContentReview // the review queue
+	Reviewers       []*Reviewer
+	ReviewRequired  bool // drafts of Site are published only once approved
+	Autosave        AutosaveSettings
+	Timezone        string // Site timezone for formatInTZ, filled in by render
 
 	// Import fields
 	Import      *Import
@@ -356,12 +504,60 @@ type PageData struct {
 	ImportRows  []ImportRow
 	ImportPath  string
 	ImportType  ImportType
+	ImportDiff  *ImportDiff
 	HasMeta     bool
 
 	// Restore fields
 	RestorePath string
+
+	// Bulk image upload
+	UploadResults []ImageUploadResult
+
+	// Regenerate all sites
+	GenerationResults []*SiteGenerationResult
+
+	// Orphaned images
+	OrphanedImages  []OrphanedImage
+	A11yReport      *A11yReport
+	FeedReport      *FeedReport
+	ReclaimableSize string
+
+	// Content revisions
+	Revisions    []*ContentRevision
+	RevisionDiff *RevisionDiff
+
+	// Find and replace
+	FindReplace *FindReplaceResult
+
+	// Publish queue
+	PublishJob  *PublishJob // queued or running
+	PublishJobs []*PublishJob
+}
+
+// ImageUploadResult reports the outcome for one file of a bulk upload.
+type ImageUploadResult struct {
+	FileName string
+	Image    *Image // Stored image (nil on failure)
+	Error    string
 }
 
+// OrphanedImage is an unused image listed for review, with its file size.
+type OrphanedImage struct {
+	Image *Image
+	Size  string
+}
+
+const (
+	// maxImageUploadSize is the largest single image accepted.
+	maxImageUploadSize = 10 << 20
+	// maxBulkUploadSize caps the combined size of a bulk upload.
+	maxBulkUploadSize = 50 << 20
+	// maxThemeUploadSize is the largest theme bundle accepted.
+	maxThemeUploadSize = 20 << 20
+	// maxLayoutImportSize is the largest layout export accepted.
+	maxLayoutImportSize = 2 << 20
+)
+
 // ImportRow represents a unified row in the import table
 type ImportRow struct {
 	File   ImportFile // File info from scan
@@ -369,6 +565,16 @@ type ImportRow struct {
 	Status string     // "new", "synced", "updated", "conflict"
 }
 
+// hasRole reports whether role is in a comma separated list of roles.
+func hasRole(roles, role string) bool {
+	for _, r := range strings.Split(roles, ",") {
+		if strings.TrimSpace(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *Handler) render(w http.ResponseWriter, r *http.Request, templateName string, data PageData) {
 	funcMap := render.MergeFuncMaps(render.FuncMap(), template.FuncMap{
 		"add":      func(a, b int) int { return a + b },
@@ -380,14 +586,22 @@ func (h *Handler) render(w http.ResponseWriter, r *http.Request, templateName st
 			}
 			return *p
 		},
-		"hasRole": func(roles, role string) bool {
-			for _, r := range strings.Split(roles, ",") {
-				if strings.TrimSpace(r) == role {
-					return true
-				}
-			}
-			return false
+		"formatInTZ": formatInTZ,
+		"contributorRoles": func() []string { return ContributorRoles },
+		"pathWarnings": func(collisions []*PathCollision) template.HTML {
+			return renderPathWarnings(collisions, false)
+		},
+		"publicURL": renderPublicURL,
+		"editLockBanner": func(data PageData) template.HTML {
+			return renderEditLock(editLockView{
+				Lock:      data.EditLock,
+				ContentID: data.Content.ID,
+				SiteID:    data.Site.ID,
+				Admin:     hasRole(data.CurrentUserRoles, "admin"),
+			})
 		},
+		"hasRole":       hasRole,
+		"publishStatus": renderPublishStatus,
 	})
 
 	if data.CurrentUserName == "" {
@@ -396,9 +610,20 @@ func (h *Handler) render(w http.ResponseWriter, r *http.Request, templateName st
 	if data.CurrentUserRoles == "" {
 		data.CurrentUserRoles = middleware.GetUserRoles(r.Context())
 	}
+	if data.Timezone == "" && data.Site != nil {
+		data.Timezone = h.siteTimezone(r.Context(), data.Site.ID).String()
+	}
+	if data.Site != nil {
+		data.Features = Features{
+			Contributors: h.featureEnabled(r.Context(), data.Site.ID, FeatureContributors),
+			Imports:      h.featureEnabled(r.Context(), data.Site.ID, FeatureImports),
+			Series:       h.featureEnabled(r.Context(), data.Site.ID, FeatureSeries),
+		}
+	}
 
 	tmpl, err := template.New("").Funcs(funcMap).ParseFS(h.templatesFS,
 		"assets/templates/base.html",
+		"assets/templates/partials/*.html",
 		"assets/templates/"+templateName+".html",
 	)
 	if err != nil {
@@ -413,6 +638,26 @@ func (h *Handler) render(w http.ResponseWriter, r *http.Request, templateName st
 	}
 }
 
+// siteTimezone returns the site's timezone, or UTC when it cannot be loaded.
+func (h *Handler) siteTimezone(ctx context.Context, siteID uuid.UUID) *time.Location {
+	loc, err := h.service.GetTimezone(ctx, siteID)
+	if err != nil {
+		h.log.Errorf("Cannot get site timezone: %v", err)
+		return time.UTC
+	}
+	return loc
+}
+
+// featureEnabled reports whether a feature flag of the site is on. Flags that
+// cannot be read count as on.
+func (h *Handler) featureEnabled(ctx context.Context, siteID uuid.UUID, key string) bool {
+	enabled, err := h.service.IsFeatureEnabled(ctx, siteID, key)
+	if err != nil {
+		h.log.Errorf("Cannot get feature flag %s: %v", key, err)
+	}
+	return enabled
+}
+
 func (h *Handler) renderError(w http.ResponseWriter, r *http.Request, status int, message string) {
 	h.log.Errorf("HTTP %d: %s", status, message)
 	w.WriteHeader(status)
@@ -544,6 +789,39 @@ func (h *Handler) HandleShowSite(w http.ResponseWriter, r *http.Request) {
 		Site:  site,
 	}
 
+	if stats, err := h.service.GetSiteStats(r.Context(), siteID); err != nil {
+		h.log.Errorf("Cannot get site stats: %v", err)
+	} else {
+		data.Stats = stats
+	}
+
+	if site.LastPublishedAt != nil {
+		edited, err := h.service.GetContentEditedSince(r.Context(), siteID, *site.LastPublishedAt)
+		if err != nil {
+			h.log.Errorf("Cannot check for unpublished changes: %v", err)
+		}
+		data.UnpublishedChanges = edited
+	}
+
+	if param, err := h.service.GetSettingByRefKey(r.Context(), siteID, NoIndexRefKey); err == nil {
+		data.NoIndex = param.Value == "true"
+	}
+
+	if stale, err := findStaleDrafts(r.Context(), h.service, siteID); err != nil {
+		h.log.Errorf("Cannot find stale drafts: %v", err)
+	} else {
+		data.StaleDrafts = stale
+	}
+
+	jobs, err := h.service.ListPublishJobs(r.Context(), siteID)
+	if err != nil {
+		h.log.Errorf("Cannot list publish jobs: %v", err)
+	}
+	data.PublishJobs = jobs
+	if len(jobs) > 0 && !jobs[0].Done() {
+		data.PublishJob = jobs[0]
+	}
+
 	switch r.URL.Query().Get("success") {
 	case "markdown":
 		data.Success = "Markdown files generated successfully"
@@ -553,24 +831,31 @@ func (h *Handler) HandleShowSite(w http.ResponseWriter, r *http.Request) {
 		data.Success = "No changes to backup"
 	case "html":
 		data.Success = "HTML site generated successfully"
-	case "publish":
-		data.Success = "Site published successfully"
-	case "publish_no_changes":
-		data.Success = "No changes to publish"
 	}
 
-	switch r.URL.Query().Get("error") {
-	case "backup_failed":
-		data.Error = "Failed to backup markdown to git repository"
-	case "publish_not_configured":
-		data.Error = "Publish repository not configured"
-	case "publish_failed":
-		data.Error = "Failed to publish site to git repository"
-	}
+	data.Error = siteErrorMessages[r.URL.Query().Get("error")]
 
 	h.render(w, r, "ssg/sites/show", data)
 }
 
+// siteErrorMessages are the messages of the error codes the site page is
+// redirected with. Failed publish jobs show them too.
+var siteErrorMessages = map[string]string{
+	"backup_failed":             "Failed to backup markdown to git repository",
+	"publish_not_configured":    "Publish repository not configured",
+	"publish_a11y_blocked":      "Publishing blocked: the accessibility lint found errors. See the accessibility report",
+	"publish_generation_failed": "HTML generation failed. See the server log for details",
+	"publish_failed":            "Failed to publish site to git repository",
+	"publish_auth_failed":       "Git authentication failed. Check the auth token or SSH key settings",
+	"backup_auth_failed":        "Git authentication failed. Check the auth token or SSH key settings",
+	"publish_host_key":          "Git server host key could not be verified. Check the SSH known hosts and host key policy settings",
+	"backup_host_key":           "Git server host key could not be verified. Check the SSH known hosts and host key policy settings",
+	"publish_signing_failed":    "The commit could not be signed, so nothing was pushed. Check the signing key and format settings",
+	"backup_signing_failed":     "The commit could not be signed, so nothing was pushed. Check the signing key and format settings",
+	"publish_secret":            "A secret setting could not be decrypted. Check the encryption key Clio runs with",
+	"backup_secret":             "A secret setting could not be decrypted. Check the encryption key Clio runs with",
+}
+
 func (h *Handler) HandleEditSite(w http.ResponseWriter, r *http.Request) {
 	siteID, err := uuid.Parse(r.URL.Query().Get("id"))
 	if err != nil {
@@ -681,6 +966,108 @@ func (h *Handler) HandleDeleteSite(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/ssg/list-sites", http.StatusSeeOther)
 }
 
+// HandleRegenerateAll rebuilds every active site and shows how each went.
+func (h *Handler) HandleRegenerateAll(w http.ResponseWriter, r *http.Request) {
+	results, err := h.service.GenerateAllSites(r.Context())
+	if err != nil {
+		h.log.Errorf("Cannot regenerate sites: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot regenerate sites")
+		return
+	}
+
+	h.render(w, r, "ssg/sites/regenerate", PageData{
+		Title:             "Regenerate All Sites",
+		GenerationResults: results,
+	})
+}
+
+func (h *Handler) HandleCloneSiteForm(w http.ResponseWriter, r *http.Request) {
+	siteID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid site ID")
+		return
+	}
+
+	site, err := h.service.GetSite(r.Context(), siteID)
+	if err != nil {
+		h.log.Errorf("Cannot get site: %v", err)
+		h.renderError(w, r, http.StatusNotFound, "Site not found")
+		return
+	}
+
+	h.render(w, r, "ssg/sites/clone", PageData{
+		Title: "Clone " + site.Name,
+		Site:  site,
+	})
+}
+
+// HandleCloneSite creates a new site from an existing one, copying its
+// layouts, sections and params, and optionally contributors and content.
+func (h *Handler) HandleCloneSite(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	sourceID, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid site ID")
+		return
+	}
+
+	source, err := h.service.GetSite(r.Context(), sourceID)
+	if err != nil {
+		h.log.Errorf("Cannot get site to clone: %v", err)
+		h.renderError(w, r, http.StatusNotFound, "Site not found")
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	slug := strings.TrimSpace(r.FormValue("slug"))
+	opts := CloneOptions{
+		Contributors: r.FormValue("include_contributors") == "on",
+		Content:      r.FormValue("include_content") == "on",
+	}
+	if userID, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		opts.UserID = userID
+	}
+
+	renderFormError := func(msg string) {
+		h.render(w, r, "ssg/sites/clone", PageData{
+			Title: "Clone " + source.Name,
+			Site:  source,
+			Error: msg,
+		})
+	}
+
+	if name == "" || slug == "" {
+		renderFormError("Name and slug are required")
+		return
+	}
+
+	site, err := h.service.CloneSite(r.Context(), sourceID, name, slug, opts)
+	if err != nil {
+		h.log.Errorf("Cannot clone site: %v", err)
+		if errors.Is(err, ErrSlugTaken) {
+			renderFormError("Slug is already in use")
+			return
+		}
+		renderFormError("Cannot clone site")
+		return
+	}
+
+	if err := h.workspace.CreateSiteDirectories(site.Slug); err != nil {
+		h.log.Errorf("Cannot create site directories: %v", err)
+		// Rollback: delete site from DB
+		_ = h.service.DeleteSite(r.Context(), site.ID)
+		renderFormError("Cannot create site directories")
+		return
+	}
+
+	h.log.Infof("Cloned site %s into %s", source.Slug, site.Slug)
+	http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String(), http.StatusSeeOther)
+}
+
 // --- Section Handlers ---
 
 func (h *Handler) HandleListSections(w http.ResponseWriter, r *http.Request) {
@@ -757,11 +1144,15 @@ func (h *Handler) HandleCreateSection(w http.ResponseWriter, r *http.Request) {
 			Site:    site,
 			Section: section,
 			Layouts: layouts,
-			Error:   "Cannot create section",
+			Error:   sectionSaveError("Cannot create section", err),
 		})
 		return
 	}
 
+	if len(h.sectionPathWarnings(r.Context(), section)) > 0 {
+		h.siteRedirect(w, r, "/ssg/edit-section?id="+section.ID.String())
+		return
+	}
 	h.siteRedirect(w, r, "/ssg/get-section?id="+section.ID.String())
 }
 
@@ -832,6 +1223,7 @@ func (h *Handler) HandleEditSection(w http.ResponseWriter, r *http.Request) {
 		Layouts:       layouts,
 		SectionHeader: sectionHeader,
 		SectionImages: sectionImages,
+		PathWarnings:  h.sectionPathWarnings(r.Context(), section),
 	})
 }
 
@@ -889,11 +1281,15 @@ func (h *Handler) HandleUpdateSection(w http.ResponseWriter, r *http.Request) {
 			Site:    site,
 			Section: section,
 			Layouts: layouts,
-			Error:   "Cannot update section",
+			Error:   sectionSaveError("Cannot update section", err),
 		})
 		return
 	}
 
+	if len(h.sectionPathWarnings(r.Context(), section)) > 0 {
+		h.siteRedirect(w, r, "/ssg/edit-section?id="+section.ID.String())
+		return
+	}
 	h.siteRedirect(w, r, "/ssg/get-section?id="+section.ID.String())
 }
 
@@ -924,6 +1320,180 @@ func (h *Handler) HandleDeleteSection(w http.ResponseWriter, r *http.Request) {
 	h.siteRedirect(w, r, "/ssg/list-sections")
 }
 
+// pathWarningsTmpl renders save-time path collisions. Edit forms show it
+// through the pathWarnings template func; autosave swaps it in out of band.
+var pathWarningsTmpl = template.Must(template.New("path-warnings").Parse(
+	`<div id="path-warnings"{{ if .OOB }} hx-swap-oob="true"{{ end }}>` +
+		`{{ with .Collisions }}<div class="alert alert-warning">` +
+		`<strong>Path conflict.</strong> This is generated at <code>/{{ with (index . 0).Path }}{{ . }}/{{ end }}</code>, which is also used by:` +
+		`<ul>{{ range . }}<li>{{ if eq .Kind "reserved" }}{{ .Name }} (reserved){{ else }}{{ .Kind }} <a href="{{ .Link }}">{{ .Name }}</a>{{ end }}</li>{{ end }}</ul>` +
+		`Generation will overwrite one of them. Change the path to avoid it.</div>{{ end }}</div>`))
+
+func renderPathWarnings(collisions []*PathCollision, oob bool) template.HTML {
+	var buf strings.Builder
+	_ = pathWarningsTmpl.Execute(&buf, struct {
+		Collisions []*PathCollision
+		OOB        bool
+	}{collisions, oob})
+	return template.HTML(buf.String())
+}
+
+var publicURLTmpl = template.Must(template.New("publicURL").Parse(
+	`{{ with . }}<div class="public-url">` +
+		`<code id="public-url">{{ .URL }}</code> ` +
+		`<button type="button" class="btn btn-secondary btn-sm" onclick="copyPublicURL(this)">Copy link</button>` +
+		`{{ if .Provisional }} <span class="badge badge-warning" title="Nothing is published at this address until the content is published">Provisional</span>{{ end }}` +
+		`{{ if .Relative }}<small class="public-url-hint">The site base URL is not set, so this is only the path. Set it in Settings to get the full link.</small>{{ end }}` +
+		`</div>` +
+		`<script>function copyPublicURL(btn) {` +
+		`navigator.clipboard.writeText(document.getElementById('public-url').textContent).then(() => {` +
+		`btn.textContent = 'Copied'; setTimeout(() => { btn.textContent = 'Copy link'; }, 1500); }); }</script>` +
+		`{{ end }}`))
+
+// renderPublicURL renders the address content is published at with a button
+// to copy it, or nothing when it is unknown.
+func renderPublicURL(u *PublicURL) template.HTML {
+	var buf strings.Builder
+	_ = publicURLTmpl.Execute(&buf, u)
+	return template.HTML(buf.String())
+}
+
+// editLockView is the edit lock banner of the edit form. Released is set when
+// a read only form can be reloaded to edit.
+type editLockView struct {
+	Lock      *EditLock
+	ContentID uuid.UUID
+	SiteID    uuid.UUID
+	Admin     bool
+	Released  bool
+}
+
+// editLockTmpl renders the edit lock banner. It polls for the lock state,
+// which also keeps the lock of the editor alive, and stops once a read only
+// form can be reloaded.
+var editLockTmpl = template.Must(template.New("editLock").Parse(
+	`<div id="edit-lock"{{ if not .Released }} hx-get="/ssg/edit-lock?id={{ .ContentID }}&site_id={{ .SiteID }}{{ if .Lock }}&watch=1{{ end }}" hx-trigger="every 30s" hx-swap="outerHTML"{{ end }}>` +
+		`{{ with .Lock }}<div class="alert alert-warning"><strong>{{ .UserName }} is editing this content.</strong> ` +
+		`It is read only until they leave the page or stop editing for a few minutes.` +
+		`{{ if $.Admin }} <form method="POST" action="/ssg/break-edit-lock?site_id={{ $.SiteID }}" style="display:inline;">` +
+		`<input type="hidden" name="id" value="{{ $.ContentID }}">` +
+		`<button type="submit" class="btn btn-danger btn-sm" onclick="return confirm('Take over editing? Changes {{ .UserName }} saves afterwards are rejected.')">Break lock</button></form>{{ end }}` +
+		`</div>{{ end }}` +
+		`{{ if .Released }}<div class="alert alert-success">Nobody is editing this content now. ` +
+		`<a href="/ssg/edit-content?id={{ .ContentID }}&site_id={{ .SiteID }}">Reload to edit it</a>.</div>{{ end }}` +
+		`</div>`))
+
+func renderEditLock(v editLockView) template.HTML {
+	var buf strings.Builder
+	_ = editLockTmpl.Execute(&buf, v)
+	return template.HTML(buf.String())
+}
+
+// editLock locks content for the signed-in user and returns the lock of the
+// user holding it instead, if any. Locks only guard against accidental
+// concurrent edits, so lock failures are logged and leave content editable.
+func (h *Handler) editLock(r *http.Request, contentID uuid.UUID) *EditLock {
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		return nil
+	}
+	lock, err := h.service.AcquireEditLock(r.Context(), contentID, userID, middleware.GetUserName(r.Context()))
+	if errors.Is(err, ErrContentLocked) {
+		return lock
+	}
+	if err != nil {
+		h.log.Errorf("Cannot acquire edit lock: %v", err)
+	}
+	return nil
+}
+
+// contentPublicURL returns the address content is published at. Lookup
+// failures only cost the link, so they are logged and ignored.
+func (h *Handler) contentPublicURL(ctx context.Context, content *Content) *PublicURL {
+	u, err := h.service.GetPublicURL(ctx, content)
+	if err != nil {
+		h.log.Errorf("Cannot get content public URL: %v", err)
+		return nil
+	}
+	return u
+}
+
+// contentPathWarnings checks content's output path for collisions. Lookup
+// failures only cost the warning, so they are logged and ignored.
+func (h *Handler) contentPathWarnings(ctx context.Context, content *Content) []*PathCollision {
+	path, err := h.service.ContentOutputPath(ctx, content)
+	if err != nil {
+		h.log.Errorf("Cannot get content output path: %v", err)
+		return nil
+	}
+	collisions, err := h.service.CheckPathCollision(ctx, content.SiteID, path, content.ID)
+	if err != nil {
+		h.log.Errorf("Cannot check path collisions: %v", err)
+		return nil
+	}
+	return collisions
+}
+
+// contentSaveError is the form message for a failed content save: the error
+// itself when the user can fix it, fallback otherwise.
+func contentSaveError(fallback string, err error) string {
+	if errors.Is(err, ErrTranslationTaken) || errors.Is(err, ErrInvalidLang) || errors.Is(err, ErrInvalidLinkURL) || errors.Is(err, ErrInvalidField) || errors.Is(err, ErrNotApproved) {
+		return err.Error()
+	}
+	return fallback
+}
+
+// writeSummary has the LLM write the summary of content saved without one or
+// an excerpt, when the site's summaries are set to SummaryLLM. Other modes
+// derive it when the site is generated, so it keeps following the body.
+func (h *Handler) writeSummary(ctx context.Context, content *Content) {
+	if content.Summary != "" || !h.llmClient.IsConfigured() {
+		return
+	}
+	settings, err := h.service.GetSettings(ctx, content.SiteID)
+	if err != nil {
+		h.log.Errorf("Cannot get settings: %v", err)
+		return
+	}
+	params := make(map[string]string, len(settings))
+	for _, p := range settings {
+		params[p.RefKey] = p.Value
+	}
+	opts := SummaryOptionsFromParams(params)
+	if opts.Mode != SummaryLLM {
+		return
+	}
+	opts.Summarize = h.llmClient.Summarize
+
+	if meta, err := h.service.GetMetaByContentID(ctx, content.ID); err == nil && meta != nil && meta.Excerpt != "" {
+		return
+	}
+	summary, err := h.service.DeriveSummary(ctx, content, opts)
+	if err != nil {
+		h.log.Errorf("Cannot write summary: %v", err)
+		return
+	}
+	content.Summary = summary
+}
+
+// sectionSaveError returns the message for a failed section save: the error
+// itself when the user can fix it, the fallback otherwise.
+func sectionSaveError(fallback string, err error) string {
+	if errors.Is(err, ErrReservedPath) {
+		return err.Error()
+	}
+	return fallback
+}
+
+func (h *Handler) sectionPathWarnings(ctx context.Context, section *Section) []*PathCollision {
+	collisions, err := h.service.CheckPathCollision(ctx, section.SiteID, section.Path, section.ID)
+	if err != nil {
+		h.log.Errorf("Cannot check path collisions: %v", err)
+		return nil
+	}
+	return collisions
+}
+
 // --- Content Handlers ---
 
 func (h *Handler) HandleListContents(w http.ResponseWriter, r *http.Request) {
@@ -942,9 +1512,9 @@ func (h *Handler) HandleListContents(w http.ResponseWriter, r *http.Request) {
 
 	limit := 25
 	offset := (page - 1) * limit
-	search := r.URL.Query().Get("q")
+	filter := contentFilterFromQuery(r.URL.Query())
 
-	contents, total, err := h.service.GetContentWithPagination(r.Context(), site.ID, offset, limit, search)
+	contents, total, err := h.service.GetContentWithPagination(r.Context(), site.ID, offset, limit, filter)
 	if err != nil {
 		h.log.Errorf("Cannot list contents: %v", err)
 		h.renderError(w, r, http.StatusInternalServerError, "Cannot load contents")
@@ -953,18 +1523,84 @@ func (h *Handler) HandleListContents(w http.ResponseWriter, r *http.Request) {
 
 	totalPages := (total + limit - 1) / limit
 
+	// Rows show which items are kept out of search engines.
+	for _, c := range contents {
+		if meta, err := h.service.GetMetaByContentID(r.Context(), c.ID); err == nil {
+			c.Meta = meta
+		}
+	}
+
+	sections, _ := h.service.GetSections(r.Context(), site.ID)
+	tags, _ := h.service.GetTags(r.Context(), site.ID)
+	contributors, _ := h.service.GetContributors(r.Context(), site.ID)
+
 	h.render(w, r, "ssg/contents/list", PageData{
-		Title:       "Contents",
-		Site:        site,
-		Contents:    contents,
-		CurrentPage: page,
-		TotalPages:  totalPages,
-		HasPrev:     page > 1,
-		HasNext:     page < totalPages,
-		Search:      search,
+		Title:        "Contents",
+		Site:         site,
+		Contents:     contents,
+		Sections:     sections,
+		Tags:         tags,
+		Contributors: contributors,
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		HasPrev:      page > 1,
+		HasNext:      page < totalPages,
+		Search:       filter.Search,
+		Filter:       filter,
+		FilterQuery:  template.URL(filter.query().Encode()),
+		Error:        r.URL.Query().Get("error"),
+		Success:      r.URL.Query().Get("success"),
 	})
 }
 
+// contentFilterFromQuery reads the content list filters. Unknown values are
+// ignored rather than rejected, so a stale link still lists content.
+func contentFilterFromQuery(query url.Values) ContentFilter {
+	filter := ContentFilter{
+		Search: query.Get("q"),
+		Tag:    query.Get("tag"),
+	}
+	if id, err := uuid.Parse(query.Get("section_id")); err == nil {
+		filter.SectionID = id
+	}
+	if id, err := uuid.Parse(query.Get("contributor_id")); err == nil {
+		filter.ContributorID = id
+	}
+	switch status := query.Get("status"); status {
+	case ContentStatusDraft, ContentStatusPublished, ContentStatusScheduled:
+		filter.Status = status
+	}
+	switch visibility := query.Get("visibility"); visibility {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPrivate:
+		filter.Visibility = visibility
+	}
+	return filter
+}
+
+// query encodes the filter back into list parameters, for links that keep it.
+func (f ContentFilter) query() url.Values {
+	values := url.Values{}
+	if f.Search != "" {
+		values.Set("q", f.Search)
+	}
+	if f.Tag != "" {
+		values.Set("tag", f.Tag)
+	}
+	if f.SectionID != uuid.Nil {
+		values.Set("section_id", f.SectionID.String())
+	}
+	if f.ContributorID != uuid.Nil {
+		values.Set("contributor_id", f.ContributorID.String())
+	}
+	if f.Status != "" {
+		values.Set("status", f.Status)
+	}
+	if f.Visibility != "" {
+		values.Set("visibility", f.Visibility)
+	}
+	return values
+}
+
 func (h *Handler) HandleNewContent(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -975,13 +1611,17 @@ func (h *Handler) HandleNewContent(w http.ResponseWriter, r *http.Request) {
 	sections, _ := h.service.GetSections(r.Context(), site.ID)
 	tags, _ := h.service.GetTags(r.Context(), site.ID)
 	contributors, _ := h.service.GetContributors(r.Context(), site.ID)
+	kinds, _ := h.service.ListContentKinds(r.Context(), site.ID)
+	contents, _ := h.service.GetAllContentWithMeta(r.Context(), site.ID)
 
 	h.render(w, r, "ssg/contents/new", PageData{
 		Title:        "New Content",
 		Site:         site,
+		Contents:     contents,
 		Sections:     sections,
 		Tags:         tags,
 		Contributors: contributors,
+		ContentKinds: kinds,
 	})
 }
 
@@ -1012,8 +1652,12 @@ func (h *Handler) HandleCreateContent(w http.ResponseWriter, r *http.Request) {
 	}
 	content.Draft = r.FormValue("draft") == "on"
 	content.Featured = r.FormValue("featured") == "on"
+	content.Visibility = normalizeVisibility(r.FormValue("visibility"))
+	content.Lang = r.FormValue("lang")
+	content.TranslationGroup = r.FormValue("translation_group")
 	content.HeroTitleDark = r.FormValue("hero_title_dark") == "on"
 	content.Series = r.FormValue("series")
+	content.LinkURL = r.FormValue("link_url")
 
 	if cid := r.FormValue("contributor_id"); cid != "" {
 		if id, err := uuid.Parse(cid); err == nil {
@@ -1040,19 +1684,25 @@ func (h *Handler) HandleCreateContent(w http.ResponseWriter, r *http.Request) {
 	}
 	content.AuthorUsername = middleware.GetUserName(r.Context())
 
+	h.writeSummary(r.Context(), content)
+
 	if err := h.service.CreateContent(r.Context(), content); err != nil {
 		h.log.Errorf("Cannot create content: %v", err)
 		sections, _ := h.service.GetSections(r.Context(), site.ID)
 		tags, _ := h.service.GetTags(r.Context(), site.ID)
 		contributors, _ := h.service.GetContributors(r.Context(), site.ID)
+		kinds, _ := h.service.ListContentKinds(r.Context(), site.ID)
+		contents, _ := h.service.GetAllContentWithMeta(r.Context(), site.ID)
 		h.render(w, r, "ssg/contents/new", PageData{
 			Title:        "New Content",
 			Site:         site,
 			Content:      content,
+			Contents:     contents,
 			Sections:     sections,
 			Tags:         tags,
 			Contributors: contributors,
-			Error:        "Cannot create content",
+			ContentKinds: kinds,
+			Error:        contentSaveError("Cannot create content", err),
 		})
 		return
 	}
@@ -1060,50 +1710,213 @@ func (h *Handler) HandleCreateContent(w http.ResponseWriter, r *http.Request) {
 	// Handle tags (Tagify format)
 	h.processTagifyTags(r.Context(), site.ID, content.ID, r.FormValue("tags"))
 
+	if len(h.contentPathWarnings(r.Context(), content)) > 0 {
+		h.siteRedirect(w, r, "/ssg/edit-content?id="+content.ID.String())
+		return
+	}
 	h.siteRedirect(w, r, "/ssg/get-content?id="+content.ID.String())
 }
 
-func (h *Handler) HandleShowContent(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) HandleQuickImportForm(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
 		h.renderError(w, r, http.StatusBadRequest, "Site context required")
 		return
 	}
 
-	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
-	if err != nil {
-		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
-		return
-	}
-
-	content, err := h.service.GetContent(r.Context(), contentID)
-	if err != nil {
-		h.log.Errorf("Cannot get content: %v", err)
-		h.renderError(w, r, http.StatusNotFound, "Content not found")
-		return
-	}
-
-	// Load tags
-	content.Tags, _ = h.service.GetTagsForContent(r.Context(), contentID)
-
-	h.render(w, r, "ssg/contents/show", PageData{
-		Title:   content.Heading,
-		Site:    site,
-		Content: content,
+	sections, _ := h.service.GetSections(r.Context(), site.ID)
+	h.render(w, r, "ssg/contents/quick-import", PageData{
+		Title:    "Quick Import",
+		Site:     site,
+		Sections: sections,
 	})
 }
 
-func (h *Handler) HandleEditContent(w http.ResponseWriter, r *http.Request) {
+// HandleQuickImport creates a draft from pasted Markdown and opens it in the
+// editor.
+func (h *Handler) HandleQuickImport(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
 		h.renderError(w, r, http.StatusBadRequest, "Site context required")
 		return
 	}
 
-	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
-	if err != nil {
-		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
-		return
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	var sectionID uuid.UUID
+	if id, err := uuid.Parse(r.FormValue("section_id")); err == nil {
+		sectionID = id
+	}
+	markdown := r.FormValue("markdown")
+
+	var userID uuid.UUID
+	if id, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		userID = id
+	}
+
+	renderForm := func(msg string) {
+		sections, _ := h.service.GetSections(r.Context(), site.ID)
+		h.render(w, r, "ssg/contents/quick-import", PageData{
+			Title:    "Quick Import",
+			Site:     site,
+			Sections: sections,
+			Content:  &Content{SectionID: sectionID, Body: markdown},
+			Error:    msg,
+		})
+	}
+
+	if strings.TrimSpace(markdown) == "" {
+		renderForm("Paste some Markdown to import")
+		return
+	}
+	if _, _, err := ParseQuickImport(markdown); err != nil {
+		renderForm(err.Error())
+		return
+	}
+
+	content, err := h.service.QuickImport(r.Context(), site.ID, sectionID, userID, middleware.GetUserName(r.Context()), markdown)
+	if err != nil {
+		h.log.Errorf("Cannot quick import content: %v", err)
+		renderForm("Cannot import content")
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/edit-content?id="+content.ID.String())
+}
+
+func (h *Handler) HandleShowContent(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	content, err := h.service.GetContent(r.Context(), contentID)
+	if err != nil {
+		h.log.Errorf("Cannot get content: %v", err)
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	// Load tags
+	content.Tags, _ = h.service.GetTagsForContent(r.Context(), contentID)
+	fields, _ := h.service.GetContentFields(r.Context(), contentID)
+	review, _ := h.service.GetContentReview(r.Context(), contentID)
+	reviewers, _ := h.service.ListReviewers(r.Context())
+
+	h.render(w, r, "ssg/contents/show", PageData{
+		Title:          content.Heading,
+		Site:           site,
+		Content:        content,
+		ContentFields:  fields,
+		PublicURL:      h.contentPublicURL(r.Context(), content),
+		Review:         review,
+		Reviewers:      reviewers,
+		ReviewRequired: h.reviewRequired(r.Context(), site.ID),
+		Error:          r.URL.Query().Get("error"),
+		Success:        r.URL.Query().Get("success"),
+	})
+}
+
+// HandleContentDiff compares two versions of a content. The a and b
+// parameters take a revision ID or "current" for the live content; by default
+// the latest revision is compared with the live content.
+func (h *Handler) HandleContentDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	site := getSiteFromContext(ctx)
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.URL.Query().Get("content_id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	content, err := h.service.GetContent(ctx, contentID)
+	if err != nil || content.SiteID != site.ID {
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	revisions, err := h.service.ListContentRevisions(ctx, contentID)
+	if err != nil {
+		h.log.Errorf("Cannot list revisions: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load revisions")
+		return
+	}
+
+	data := PageData{
+		Title:     "Changes: " + content.Heading,
+		Site:      site,
+		Content:   content,
+		Revisions: revisions,
+	}
+	if len(revisions) == 0 {
+		h.render(w, r, "ssg/contents/diff", data)
+		return
+	}
+
+	revA, err := parseRevisionParam(r.URL.Query().Get("a"), revisions[0].ID)
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid revision ID")
+		return
+	}
+	revB, err := parseRevisionParam(r.URL.Query().Get("b"), uuid.Nil)
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid revision ID")
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(ctx, contentID, revA, revB)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			h.renderError(w, r, http.StatusNotFound, "Revision not found")
+			return
+		}
+		h.log.Errorf("Cannot diff revisions: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot compare revisions")
+		return
+	}
+	data.RevisionDiff = diff
+
+	h.render(w, r, "ssg/contents/diff", data)
+}
+
+// parseRevisionParam reads a revision ID, where "current" means the live
+// content (uuid.Nil) and an empty value means def.
+func parseRevisionParam(v string, def uuid.UUID) (uuid.UUID, error) {
+	switch v {
+	case "":
+		return def, nil
+	case "current":
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(v)
+}
+
+func (h *Handler) HandleEditContent(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
 	}
 
 	content, err := h.service.GetContent(r.Context(), contentID)
@@ -1117,6 +1930,8 @@ func (h *Handler) HandleEditContent(w http.ResponseWriter, r *http.Request) {
 	sections, _ := h.service.GetSections(r.Context(), site.ID)
 	tags, _ := h.service.GetTags(r.Context(), site.ID)
 	contributors, _ := h.service.GetContributors(r.Context(), site.ID)
+	kinds, _ := h.service.ListContentKinds(r.Context(), site.ID)
+	layouts, _ := h.service.GetLayouts(r.Context(), site.ID)
 
 	// Get content images and separate header from content images
 	allImages, _ := h.service.GetContentImagesWithDetails(r.Context(), contentID)
@@ -1133,19 +1948,44 @@ func (h *Handler) HandleEditContent(w http.ResponseWriter, r *http.Request) {
 	// Get meta for SEO/settings
 	meta, _ := h.service.GetMetaByContentID(r.Context(), contentID)
 
+	contents, _ := h.service.GetAllContentWithMeta(r.Context(), site.ID)
+	translations, _ := h.service.GetTranslations(r.Context(), contentID)
+	fields, _ := h.service.GetContentFields(r.Context(), contentID)
+
 	h.render(w, r, "ssg/contents/edit", PageData{
 		Title:         "Edit " + content.Heading,
 		Site:          site,
 		Content:       content,
+		Contents:      contents,
+		Translations:  translations,
+		ContentFields: fields,
 		Sections:      sections,
 		Tags:          tags,
 		Contributors:  contributors,
+		ContentKinds:  kinds,
+		Layouts:       layouts,
 		HeaderImage:   headerImage,
 		ContentImages: contentImages,
 		Meta:          meta,
+		PathWarnings:  h.contentPathWarnings(r.Context(), content),
+		PublicURL:     h.contentPublicURL(r.Context(), content),
+		EditLock:      h.editLock(r, contentID),
+		Autosave:      h.autosaveSettings(r.Context(), site.ID),
+		Collections:   h.siteCollections(r.Context(), site),
 	})
 }
 
+// autosaveSettings returns how the edit form of the site's content saves on its own.
+func (h *Handler) autosaveSettings(ctx context.Context, siteID uuid.UUID) AutosaveSettings {
+	params := make(map[string]string)
+	for _, refKey := range []string{AutosaveRefKey, AutosaveIntervalRefKey} {
+		if param, err := h.service.GetSettingByRefKey(ctx, siteID, refKey); err == nil && param != nil {
+			params[refKey] = param.Value
+		}
+	}
+	return AutosaveSettingsFromParams(params)
+}
+
 func (h *Handler) HandleUpdateContent(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -1171,14 +2011,23 @@ func (h *Handler) HandleUpdateContent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if lock := h.editLock(r, contentID); lock != nil {
+		h.renderError(w, r, http.StatusConflict, fmt.Sprintf("%s is editing this content, your changes were not saved", lock.UserName))
+		return
+	}
+
 	content.Heading = r.FormValue("heading")
 	content.Summary = r.FormValue("summary")
 	content.Body = r.FormValue("body")
 	content.Kind = r.FormValue("kind")
 	content.Draft = r.FormValue("draft") == "on"
 	content.Featured = r.FormValue("featured") == "on"
+	content.Visibility = normalizeVisibility(r.FormValue("visibility"))
+	content.Lang = r.FormValue("lang")
+	content.TranslationGroup = r.FormValue("translation_group")
 	content.HeroTitleDark = r.FormValue("hero_title_dark") == "on"
 	content.Series = r.FormValue("series")
+	content.LinkURL = r.FormValue("link_url")
 
 	if sid := r.FormValue("section_id"); sid != "" {
 		if id, err := uuid.Parse(sid); err == nil {
@@ -1186,6 +2035,11 @@ func (h *Handler) HandleUpdateContent(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	content.LayoutID = uuid.Nil
+	if id, err := uuid.Parse(r.FormValue("layout_id")); err == nil {
+		content.LayoutID = id
+	}
+
 	if cid := r.FormValue("contributor_id"); cid != "" {
 		if id, err := uuid.Parse(cid); err == nil {
 			content.ContributorID = &id
@@ -1205,7 +2059,7 @@ func (h *Handler) HandleUpdateContent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if pat := r.FormValue("published_at"); pat != "" {
-		if t, err := time.ParseInLocation("2006-01-02T15:04", pat, time.Local); err == nil {
+		if t, err := parseLocalDateTime(pat, h.siteTimezone(r.Context(), site.ID)); err == nil {
 			content.PublishedAt = &t
 		}
 	} else {
@@ -1219,19 +2073,30 @@ func (h *Handler) HandleUpdateContent(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.writeSummary(r.Context(), content)
+
 	if err := h.service.UpdateContent(r.Context(), content); err != nil {
 		h.log.Errorf("Cannot update content: %v", err)
 		sections, _ := h.service.GetSections(r.Context(), site.ID)
 		tags, _ := h.service.GetTags(r.Context(), site.ID)
 		contributors, _ := h.service.GetContributors(r.Context(), site.ID)
+		kinds, _ := h.service.ListContentKinds(r.Context(), site.ID)
+		layouts, _ := h.service.GetLayouts(r.Context(), site.ID)
+		contents, _ := h.service.GetAllContentWithMeta(r.Context(), site.ID)
+		translations, _ := h.service.GetTranslations(r.Context(), content.ID)
 		h.render(w, r, "ssg/contents/edit", PageData{
 			Title:        "Edit " + content.Heading,
 			Site:         site,
 			Content:      content,
+			Contents:     contents,
+			Translations: translations,
 			Sections:     sections,
 			Tags:         tags,
 			Contributors: contributors,
-			Error:        "Cannot update content",
+			ContentKinds: kinds,
+			Layouts:      layouts,
+			Collections:  h.siteCollections(r.Context(), site),
+			Error:        contentSaveError("Cannot update content", err),
 		})
 		return
 	}
@@ -1240,9 +2105,39 @@ func (h *Handler) HandleUpdateContent(w http.ResponseWriter, r *http.Request) {
 	_ = h.service.RemoveAllTagsFromContent(r.Context(), content.ID)
 	h.processTagifyTags(r.Context(), site.ID, content.ID, r.FormValue("tags"))
 
+	if fields, ok := formContentFields(r, content.ID); ok {
+		if err := h.service.SetContentFields(r.Context(), content.ID, fields); err != nil {
+			h.log.Errorf("Cannot save content fields: %v", err)
+			h.siteRedirect(w, r, "/ssg/edit-content?id="+content.ID.String()+"&error="+url.QueryEscape(contentSaveError("Cannot save custom fields", err)))
+			return
+		}
+	}
+
+	if len(h.contentPathWarnings(r.Context(), content)) > 0 {
+		h.siteRedirect(w, r, "/ssg/edit-content?id="+content.ID.String())
+		return
+	}
 	h.siteRedirect(w, r, "/ssg/get-content?id="+content.ID.String())
 }
 
+// formContentFields reads the custom field rows of the content form, skipping
+// rows without a name. It reports false for forms without the fields
+// section, which leave the fields of the content as they are.
+func formContentFields(r *http.Request, contentID uuid.UUID) ([]*ContentField, bool) {
+	if r.FormValue("fields_form") == "" {
+		return nil, false
+	}
+	types, values := r.Form["field_type"], r.Form["field_value"]
+	var fields []*ContentField
+	for i, name := range r.Form["field_name"] {
+		if strings.TrimSpace(name) == "" || i >= len(types) || i >= len(values) {
+			continue
+		}
+		fields = append(fields, NewContentField(contentID, name, types[i], values[i]))
+	}
+	return fields, true
+}
+
 func (h *Handler) HandleAutosaveContent(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -1281,6 +2176,11 @@ func (h *Handler) HandleAutosaveContent(w http.ResponseWriter, r *http.Request)
 			w.Write([]byte(`<div id="save-status" class="save-status error">Content not found</div>`))
 			return
 		}
+		if lock := h.editLock(r, contentID); lock != nil {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<div id="save-status" class="save-status error">Not saved: ` + template.HTMLEscapeString(lock.UserName) + ` is editing</div>`))
+			return
+		}
 	}
 
 	content.Heading = r.FormValue("heading")
@@ -1288,8 +2188,12 @@ func (h *Handler) HandleAutosaveContent(w http.ResponseWriter, r *http.Request)
 	content.Body = r.FormValue("body")
 	content.Draft = r.FormValue("draft") == "on"
 	content.Featured = r.FormValue("featured") == "on"
+	content.Visibility = normalizeVisibility(r.FormValue("visibility"))
+	content.Lang = r.FormValue("lang")
+	content.TranslationGroup = r.FormValue("translation_group")
 	content.HeroTitleDark = r.FormValue("hero_title_dark") == "on"
 	content.Series = r.FormValue("series")
+	content.LinkURL = r.FormValue("link_url")
 	content.Kind = r.FormValue("kind")
 
 	if sectionID := r.FormValue("section_id"); sectionID != "" {
@@ -1298,6 +2202,11 @@ func (h *Handler) HandleAutosaveContent(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	content.LayoutID = uuid.Nil
+	if id, err := uuid.Parse(r.FormValue("layout_id")); err == nil {
+		content.LayoutID = id
+	}
+
 	if cid := r.FormValue("contributor_id"); cid != "" {
 		if id, err := uuid.Parse(cid); err == nil {
 			content.ContributorID = &id
@@ -1317,7 +2226,7 @@ func (h *Handler) HandleAutosaveContent(w http.ResponseWriter, r *http.Request)
 	}
 
 	if pat := r.FormValue("published_at"); pat != "" {
-		if t, err := time.ParseInLocation("2006-01-02T15:04", pat, time.Local); err == nil {
+		if t, err := parseLocalDateTime(pat, h.siteTimezone(r.Context(), site.ID)); err == nil {
 			content.PublishedAt = &t
 		}
 	} else {
@@ -1354,111 +2263,182 @@ func (h *Handler) HandleAutosaveContent(w http.ResponseWriter, r *http.Request)
 	_ = h.service.RemoveAllTagsFromContent(r.Context(), content.ID)
 	h.processTagifyTags(r.Context(), site.ID, content.ID, r.FormValue("tags"))
 
+	if fields, ok := formContentFields(r, content.ID); ok {
+		if err := h.service.SetContentFields(r.Context(), content.ID, fields); err != nil {
+			h.log.Errorf("Autosave fields failed: %v", err)
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<div id="save-status" class="save-status error">` + template.HTMLEscapeString(contentSaveError("Custom fields not saved", err)) + `</div>`))
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "text/html")
 	timestamp := time.Now().Unix()
-	w.Write([]byte(fmt.Sprintf(`<div id="save-status" class="save-status saved" data-saved-at="%d" data-content-id="%s"><span id="save-indicator" class="htmx-indicator">Saving...</span><span id="save-text">Saved just now</span></div>`, timestamp, content.ID.String())))
+	w.Write([]byte(fmt.Sprintf(`<div id="save-status" class="save-status saved" data-saved-at="%d" data-updated-at="%d" data-content-id="%s"><span id="save-indicator" class="htmx-indicator">Saving...</span><span id="save-text">Saved just now</span></div>`, timestamp, content.UpdatedAt.UnixMilli(), content.ID.String())))
+	w.Write([]byte(renderPathWarnings(h.contentPathWarnings(r.Context(), content), true)))
 }
 
-func (h *Handler) HandleProofreadContent(w http.ResponseWriter, r *http.Request) {
-	if !h.llmClient.IsConfigured() {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		json.NewEncoder(w).Encode(map[string]string{"error": "LLM API key not configured"})
+// HandleReconcileAutosave takes a draft the edit form kept in the browser
+// while autosave failed and saves it if it is newer than the server copy.
+// Times are unix milliseconds. The save status it renders carries the
+// outcome in data-reconcile so the form knows whether to drop its draft.
+func (h *Handler) HandleReconcileAutosave(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	if err := r.ParseForm(); err != nil {
+		w.Write([]byte(`<div id="save-status" class="save-status error">Error parsing form</div>`))
 		return
 	}
 
-	var req llm.ProofreadRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request"})
+	contentID, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		w.Write([]byte(`<div id="save-status" class="save-status error">Invalid content ID</div>`))
+		return
+	}
+	updatedAt, err := strconv.ParseInt(r.FormValue("updated_at"), 10, 64)
+	if err != nil {
+		w.Write([]byte(`<div id="save-status" class="save-status error">Invalid draft time</div>`))
 		return
 	}
+	draft := AutosaveDraft{
+		ContentID: contentID,
+		Heading:   r.FormValue("heading"),
+		Body:      r.FormValue("body"),
+		UpdatedAt: time.UnixMilli(updatedAt),
+	}
+	if base, err := strconv.ParseInt(r.FormValue("base_updated_at"), 10, 64); err == nil && base > 0 {
+		draft.BaseUpdatedAt = time.UnixMilli(base)
+	}
+	if userID, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		draft.UpdatedBy = userID
+	}
 
-	if req.Text == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Text is required"})
+	if lock := h.editLock(r, contentID); lock != nil {
+		w.Write([]byte(`<div id="save-status" class="save-status error" data-reconcile="` + ReconcileConflict + `">Offline changes not saved: ` + template.HTMLEscapeString(lock.UserName) + ` is editing</div>`))
 		return
 	}
 
-	result, err := h.llmClient.Proofread(r.Context(), req.Text)
+	result, err := h.service.ReconcileAutosave(r.Context(), draft)
 	if err != nil {
-		h.log.Errorf("Proofread failed: %v", err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		if errors.Is(err, ErrNotFound) {
+			w.Write([]byte(`<div id="save-status" class="save-status error">Content not found</div>`))
+			return
+		}
+		h.log.Errorf("Autosave reconcile failed: %v", err)
+		w.Write([]byte(`<div id="save-status" class="save-status error">Save failed</div>`))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(result)
+	class, text := "saved", "Saved just now"
+	switch result.Status {
+	case ReconcileSaved:
+		text = "Offline changes saved"
+	case ReconcileStale:
+		text = "Newer version on the server, offline changes discarded"
+	case ReconcileConflict:
+		class, text = "error", "Offline changes conflict with newer edits"
+	}
+	w.Write([]byte(fmt.Sprintf(`<div id="save-status" class="save-status %s" data-reconcile="%s" data-saved-at="%d" data-updated-at="%d" data-content-id="%s"><span id="save-indicator" class="htmx-indicator">Saving...</span><span id="save-text">%s</span></div>`,
+		class, result.Status, time.Now().Unix(), result.Content.UpdatedAt.UnixMilli(), result.Content.ID.String(), text)))
 }
 
-func (h *Handler) HandleDeleteContent(w http.ResponseWriter, r *http.Request) {
+// HandleEditLock renders the edit lock banner polled by the edit form. The
+// editor's polls refresh their lock; read only forms pass watch and are told
+// when the lock is gone.
+func (h *Handler) HandleEditLock(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
-		h.renderError(w, r, http.StatusBadRequest, "Site context required")
-		return
-	}
-
-	if err := r.ParseForm(); err != nil {
-		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		http.Error(w, "Site context required", http.StatusBadRequest)
 		return
 	}
 
-	contentID, err := uuid.Parse(r.FormValue("id"))
+	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
 	if err != nil {
-		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		http.Error(w, "Invalid content ID", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.DeleteContent(r.Context(), contentID); err != nil {
-		h.log.Errorf("Cannot delete content: %v", err)
-		h.renderError(w, r, http.StatusInternalServerError, "Cannot delete content")
-		return
+	view := editLockView{
+		ContentID: contentID,
+		SiteID:    site.ID,
+		Admin:     hasRole(middleware.GetUserRoles(r.Context()), "admin"),
+	}
+	if r.URL.Query().Get("watch") != "" {
+		lock, err := h.service.GetEditLock(r.Context(), contentID)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			view.Released = true
+		case err != nil:
+			h.log.Errorf("Cannot get edit lock: %v", err)
+			http.Error(w, "Cannot get edit lock", http.StatusInternalServerError)
+			return
+		default:
+			view.Lock = lock
+		}
+	} else {
+		view.Lock = h.editLock(r, contentID)
 	}
 
-	h.siteRedirect(w, r, "/ssg/list-contents")
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(renderEditLock(view)))
 }
 
-// --- Layout Handlers ---
+// HandleReleaseEditLock releases the signed-in user's edit lock when they
+// leave the edit form.
+func (h *Handler) HandleReleaseEditLock(w http.ResponseWriter, r *http.Request) {
+	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid content ID", http.StatusBadRequest)
+		return
+	}
+	userID, err := uuid.Parse(middleware.GetUserID(r.Context()))
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-func (h *Handler) HandleListLayouts(w http.ResponseWriter, r *http.Request) {
-	site := getSiteFromContext(r.Context())
-	if site == nil {
-		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+	if err := h.service.ReleaseEditLock(r.Context(), contentID, userID); err != nil {
+		h.log.Errorf("Cannot release edit lock: %v", err)
+		http.Error(w, "Cannot release edit lock", http.StatusInternalServerError)
 		return
 	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	layouts, err := h.service.GetLayouts(r.Context(), site.ID)
+// HandleBreakEditLock removes another user's edit lock and opens the edit
+// form, which locks the content for the admin.
+func (h *Handler) HandleBreakEditLock(w http.ResponseWriter, r *http.Request) {
+	contentID, err := uuid.Parse(r.FormValue("id"))
 	if err != nil {
-		h.log.Errorf("Cannot list layouts: %v", err)
-		h.renderError(w, r, http.StatusInternalServerError, "Cannot load layouts")
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
 		return
 	}
 
-	h.render(w, r, "ssg/layouts/list", PageData{
-		Title:   "Layouts",
-		Site:    site,
-		Layouts: layouts,
-	})
+	if err := h.service.BreakEditLock(r.Context(), contentID); err != nil {
+		h.log.Errorf("Cannot break edit lock: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot break edit lock")
+		return
+	}
+	h.siteRedirect(w, r, "/ssg/edit-content?id="+contentID.String())
 }
 
-func (h *Handler) HandleNewLayout(w http.ResponseWriter, r *http.Request) {
+// HandleFindReplace shows the site-wide find and replace form.
+func (h *Handler) HandleFindReplace(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
 		h.renderError(w, r, http.StatusBadRequest, "Site context required")
 		return
 	}
 
-	h.render(w, r, "ssg/layouts/new", PageData{
-		Title: "New Layout",
-		Site:  site,
+	h.render(w, r, "ssg/contents/find-replace", PageData{
+		Title:       "Find and Replace",
+		Site:        site,
+		FindReplace: &FindReplaceResult{Options: FindReplaceOptions{WholeWord: true, SkipCode: true}, Limit: maxFindReplaceChanges},
 	})
 }
 
-func (h *Handler) HandleCreateLayout(w http.ResponseWriter, r *http.Request) {
+// HandleRunFindReplace previews a find and replace, listing the content it
+// would change, or applies the previewed one.
+func (h *Handler) HandleRunFindReplace(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
 		h.renderError(w, r, http.StatusBadRequest, "Site context required")
@@ -1470,36 +2450,731 @@ func (h *Handler) HandleCreateLayout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	layout := NewLayout(site.ID, r.FormValue("name"), r.FormValue("description"))
-	layout.Code = r.FormValue("code")
-	layout.CSS = r.FormValue("css")
-	layout.ExcludeDefaultCSS = r.FormValue("exclude_default_css") == "on"
-
-	userIDStr := middleware.GetUserID(r.Context())
-	if userIDStr != "" {
-		if userID, err := uuid.Parse(userIDStr); err == nil {
-			layout.CreatedBy = userID
-			layout.UpdatedBy = userID
-		}
+	find := r.FormValue("find")
+	replace := r.FormValue("replace")
+	opts := FindReplaceOptions{
+		CaseSensitive: r.FormValue("case_sensitive") == "on",
+		WholeWord:     r.FormValue("whole_word") == "on",
+		SkipCode:      r.FormValue("skip_code") == "on",
+		Apply:         r.FormValue("action") == "apply",
+	}
+	if userID, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		opts.UserID = userID
 	}
 
-	if err := h.service.CreateLayout(r.Context(), layout); err != nil {
-		h.log.Errorf("Cannot create layout: %v", err)
-		h.render(w, r, "ssg/layouts/new", PageData{
-			Title:  "New Layout",
-			Site:   site,
-			Layout: layout,
-			Error:  "Cannot create layout",
-		})
+	data := PageData{Title: "Find and Replace", Site: site}
+	result, err := h.service.FindAndReplace(r.Context(), site.ID, find, replace, opts)
+	switch {
+	case errors.Is(err, ErrEmptyFind):
+		data.Error = "Enter the text to find"
+		result = &FindReplaceResult{Replace: replace, Options: opts, Limit: maxFindReplaceChanges}
+	case err != nil:
+		h.log.Errorf("Cannot find and replace: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot find and replace")
 		return
+	case opts.Apply:
+		data.Success = fmt.Sprintf("Replaced %q in %d items", find, result.Changed)
+		if result.Remaining > 0 {
+			data.Success += fmt.Sprintf("; %d more match, run it again to change them", result.Remaining)
+		}
+		h.log.Infof("Find and replace on %s: %q with %q in %d items", site.Slug, find, replace, result.Changed)
 	}
+	data.FindReplace = result
 
-	h.siteRedirect(w, r, "/ssg/get-layout?id="+layout.ID.String())
+	h.render(w, r, "ssg/contents/find-replace", data)
 }
 
-func (h *Handler) HandleShowLayout(w http.ResponseWriter, r *http.Request) {
-	site := getSiteFromContext(r.Context())
-	if site == nil {
+func (h *Handler) HandleProofreadContent(w http.ResponseWriter, r *http.Request) {
+	if !h.llmClient.IsConfigured() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "LLM API key not configured"})
+		return
+	}
+
+	var req llm.ProofreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request"})
+		return
+	}
+
+	if req.Text == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Text is required"})
+		return
+	}
+
+	result, err := h.llmClient.Proofread(r.Context(), req.Text)
+	if err != nil {
+		h.log.Errorf("Proofread failed: %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// HandleTranslateContent creates a draft translation of a content into the
+// lang query parameter, in the content's translation group, and returns its
+// ID. The draft starts with a note saying it was machine-translated.
+func (h *Handler) HandleTranslateContent(w http.ResponseWriter, r *http.Request) {
+	fail := func(status int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	}
+
+	if !h.llmClient.IsConfigured() {
+		fail(http.StatusServiceUnavailable, "LLM API key not configured")
+		return
+	}
+
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		fail(http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	id, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		fail(http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	lang, err := NormalizeLang(r.URL.Query().Get("lang"))
+	if err != nil || lang == "" {
+		fail(http.StatusBadRequest, "A language such as es or pt-BR is required")
+		return
+	}
+
+	source, err := h.service.GetContent(r.Context(), id)
+	if err != nil || source.SiteID != site.ID {
+		fail(http.StatusNotFound, "Content not found")
+		return
+	}
+
+	ctx := r.Context()
+	params := map[string]string{}
+	if param, err := h.service.GetSettingByRefKey(ctx, site.ID, LanguageRefKey); err == nil {
+		params[LanguageRefKey] = param.Value
+	}
+	siteLang := siteLanguage(params)
+	from := contentLang(source, siteLang)
+	if from == lang {
+		fail(http.StatusBadRequest, "The content is already in "+lang)
+		return
+	}
+	// Check before calling the model, CreateContent checks again on save.
+	translations, _ := h.service.GetTranslations(ctx, source.ID)
+	for _, t := range translations {
+		if contentLang(t, siteLang) == lang {
+			fail(http.StatusConflict, fmt.Sprintf("The content already has a %s translation", lang))
+			return
+		}
+	}
+
+	heading, err := h.llmClient.Translate(ctx, source.Heading, lang)
+	var summary, body string
+	if err == nil {
+		summary, err = h.llmClient.Translate(ctx, source.Summary, lang)
+	}
+	if err == nil {
+		body, err = h.llmClient.Translate(ctx, source.Body, lang)
+	}
+	if err != nil {
+		h.log.Errorf("Translate failed: %v", err)
+		fail(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	note := fmt.Sprintf("> Machine-translated from %s. Review this translation before publishing.", from)
+	content := NewContent(site.ID, source.SectionID, heading, note+"\n\n"+body)
+	content.Summary = summary
+	content.Kind = source.Kind
+	content.Visibility = source.Visibility
+	content.Series = source.Series
+	content.SeriesOrder = source.SeriesOrder
+	content.ContributorID = source.ContributorID
+	content.ContributorHandle = source.ContributorHandle
+	content.HeroTitleDark = source.HeroTitleDark
+	content.LayoutID = source.LayoutID
+	content.LinkURL = source.LinkURL
+	content.Lang = lang
+	content.TranslationGroup = source.TranslationGroup
+	if content.TranslationGroup == "" {
+		content.TranslationGroup = source.ID.String()
+	}
+
+	if userID, err := uuid.Parse(middleware.GetUserID(ctx)); err == nil {
+		content.UserID = userID
+		content.CreatedBy = userID
+		content.UpdatedBy = userID
+	}
+	content.AuthorUsername = middleware.GetUserName(ctx)
+
+	if err := h.service.CreateContent(ctx, content); err != nil {
+		if errors.Is(err, ErrTranslationTaken) {
+			fail(http.StatusConflict, contentSaveError("", err))
+			return
+		}
+		h.log.Errorf("Cannot create translation: %v", err)
+		fail(http.StatusInternalServerError, "Cannot create translation")
+		return
+	}
+
+	if tags, err := h.service.GetTagsForContent(ctx, source.ID); err == nil {
+		for _, tag := range tags {
+			if err := h.service.AddTagToContentByID(ctx, content.ID, tag.ID); err != nil {
+				h.log.Errorf("Cannot copy tag %s to translation: %v", tag.Name, err)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"id":  content.ID.String(),
+		"url": "/ssg/edit-content?id=" + content.ID.String() + "&site_id=" + site.ID.String(),
+	})
+}
+
+// HandleCreateTranslation creates a draft copy of a content in the lang query
+// parameter, in the content's translation group, for a manual translation,
+// and returns the URL to edit it.
+func (h *Handler) HandleCreateTranslation(w http.ResponseWriter, r *http.Request) {
+	fail := func(status int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	}
+
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		fail(http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	id, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		fail(http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	source, err := h.service.GetContent(r.Context(), id)
+	if err != nil || source.SiteID != site.ID {
+		fail(http.StatusNotFound, "Content not found")
+		return
+	}
+
+	content, err := h.service.CreateTranslationDraft(r.Context(), source.ID, r.URL.Query().Get("lang"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidLang):
+			fail(http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrTranslationTaken):
+			fail(http.StatusConflict, err.Error())
+		default:
+			h.log.Errorf("Cannot create translation draft: %v", err)
+			fail(http.StatusInternalServerError, "Cannot create translation")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"id":  content.ID.String(),
+		"url": "/ssg/edit-content?id=" + content.ID.String() + "&site_id=" + site.ID.String(),
+	})
+}
+
+func (h *Handler) HandleDeleteContent(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	if err := h.service.DeleteContent(r.Context(), contentID); err != nil {
+		h.log.Errorf("Cannot delete content: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot delete content")
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/list-contents")
+}
+
+// HandleMoveContentForm lets the user pick the site and section to move content to.
+func (h *Handler) HandleMoveContentForm(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	content, err := h.service.GetContent(r.Context(), contentID)
+	if err != nil {
+		h.log.Errorf("Cannot get content: %v", err)
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	h.renderMoveContent(w, r, site, content, "")
+}
+
+// HandleMoveContent moves content to the selected site and section. The
+// target is posted as "<site id>/<section id>".
+func (h *Handler) HandleMoveContent(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	content, err := h.service.GetContent(r.Context(), contentID)
+	if err != nil {
+		h.log.Errorf("Cannot get content: %v", err)
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	siteIDStr, sectionIDStr, _ := strings.Cut(r.FormValue("target"), "/")
+	targetSiteID, err1 := uuid.Parse(siteIDStr)
+	targetSectionID, err2 := uuid.Parse(sectionIDStr)
+	if err1 != nil || err2 != nil {
+		h.renderMoveContent(w, r, site, content, "Please select a target section")
+		return
+	}
+
+	result, err := h.service.MoveContent(r.Context(), contentID, targetSiteID, targetSectionID)
+	if err != nil {
+		h.log.Errorf("Cannot move content: %v", err)
+		msg := "Cannot move content"
+		if errors.Is(err, ErrSectionNotInSite) {
+			msg = "The selected section does not belong to the selected site"
+		}
+		h.renderMoveContent(w, r, site, content, msg)
+		return
+	}
+
+	msg := fmt.Sprintf("Content moved: %d images copied, %d reused, %d tags mapped, %d created",
+		result.ImagesCopied, result.ImagesReused, result.TagsMapped, result.TagsCreated)
+	if result.ContributorDropped != "" {
+		msg += fmt.Sprintf(". Contributor %q does not exist on this site, the author username is used instead", result.ContributorDropped)
+	}
+	h.log.Infof("Content %s moved to site %s: %s", contentID, targetSiteID, msg)
+
+	http.Redirect(w, r, "/ssg/get-content?id="+contentID.String()+"&site_id="+targetSiteID.String()+"&success="+url.QueryEscape(msg), http.StatusSeeOther)
+}
+
+func (h *Handler) renderMoveContent(w http.ResponseWriter, r *http.Request, site *Site, content *Content, errMsg string) {
+	sites, err := h.service.ListSites(r.Context())
+	if err != nil {
+		h.log.Errorf("Cannot list sites: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load sites")
+		return
+	}
+
+	var sections []*Section
+	for _, s := range sites {
+		siteSections, err := h.service.GetSections(r.Context(), s.ID)
+		if err != nil {
+			h.log.Errorf("Cannot get sections for site %s: %v", s.Slug, err)
+			continue
+		}
+		sections = append(sections, siteSections...)
+	}
+
+	h.render(w, r, "ssg/contents/move", PageData{
+		Title:    "Move Content",
+		Site:     site,
+		Sites:    sites,
+		Sections: sections,
+		Content:  content,
+		Error:    errMsg,
+	})
+}
+
+// HandleExportSelected streams the selected content items as a zip of
+// markdown files with frontmatter and the images they use.
+func (h *Handler) HandleExportSelected(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	selected := make(map[uuid.UUID]bool)
+	for _, v := range r.Form["content_id"] {
+		if id, err := uuid.Parse(v); err == nil {
+			selected[id] = true
+		}
+	}
+	if len(selected) == 0 {
+		h.siteRedirect(w, r, "/ssg/list-contents?error="+url.QueryEscape("Select some content first"))
+		return
+	}
+
+	all, err := h.service.GetAllContentWithMeta(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get content for export: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load content")
+		return
+	}
+	var contents []*Content
+	for _, c := range all {
+		if selected[c.ID] {
+			contents = append(contents, c)
+		}
+	}
+	if len(contents) == 0 {
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	loc, err := h.service.GetTimezone(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get site timezone: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-content.zip"`, site.Slug))
+	if err := h.generator.ExportMarkdownZip(r.Context(), w, site.Slug, contents, loc); err != nil {
+		h.log.Errorf("Cannot export selected content of site %s: %v", site.Slug, err)
+	}
+}
+
+// HandleSetContentRobots sets the robots meta value of the content selected
+// on the content list, or of the one item a row toggle posts.
+func (h *Handler) HandleSetContentRobots(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, v := range r.Form["content_id"] {
+		if id, err := uuid.Parse(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	back := "/ssg/list-contents"
+	if len(ids) == 0 {
+		h.siteRedirect(w, r, back+"?error="+url.QueryEscape("Select some content first"))
+		return
+	}
+
+	var userID uuid.UUID
+	if id, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		userID = id
+	}
+
+	robots := strings.TrimSpace(r.FormValue("robots"))
+	count, err := h.service.SetContentRobots(r.Context(), site.ID, ids, robots, userID)
+	if errors.Is(err, ErrInvalidRobots) {
+		h.siteRedirect(w, r, back+"?error="+url.QueryEscape("Unknown robots value "+robots))
+		return
+	}
+	if err != nil {
+		h.log.Errorf("Cannot set content robots: %v", err)
+		h.siteRedirect(w, r, back+"?error="+url.QueryEscape("Cannot update robots"))
+		return
+	}
+
+	if robots == "" {
+		robots = "the site default"
+	}
+	h.siteRedirect(w, r, back+"?success="+url.QueryEscape(fmt.Sprintf("Set robots to %s on %d content items", robots, count)))
+}
+
+// --- Review Handlers ---
+
+// HandleReviewQueue lists the site's content waiting on a review or on the
+// changes a reviewer asked for.
+func (h *Handler) HandleReviewQueue(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	reviews, err := h.service.ListReviewQueue(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot list review queue: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load the review queue")
+		return
+	}
+
+	h.render(w, r, "ssg/contents/review-queue", PageData{
+		Title:   "Review Queue",
+		Site:    site,
+		Reviews: reviews,
+	})
+}
+
+// HandleSubmitForReview puts a draft in review, assigning the reviewer picked
+// in the form if there is one.
+func (h *Handler) HandleSubmitForReview(w http.ResponseWriter, r *http.Request) {
+	h.handleReview(w, r, func(ctx context.Context, contentID, userID uuid.UUID) (*ContentReview, error) {
+		review, err := h.service.SubmitForReview(ctx, contentID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if reviewerID, err := uuid.Parse(r.FormValue("reviewer_id")); err == nil {
+			return h.service.AssignReviewer(ctx, contentID, reviewerID)
+		}
+		return review, nil
+	}, "Submitted for review")
+}
+
+// HandleAssignReviewer assigns content in review to a reviewer.
+func (h *Handler) HandleAssignReviewer(w http.ResponseWriter, r *http.Request) {
+	h.handleReview(w, r, func(ctx context.Context, contentID, _ uuid.UUID) (*ContentReview, error) {
+		reviewerID, err := uuid.Parse(r.FormValue("reviewer_id"))
+		if err != nil {
+			return nil, ErrNotReviewer
+		}
+		return h.service.AssignReviewer(ctx, contentID, reviewerID)
+	}, "Reviewer assigned")
+}
+
+// HandleApproveContent approves content in review as the signed-in user.
+func (h *Handler) HandleApproveContent(w http.ResponseWriter, r *http.Request) {
+	h.handleReview(w, r, func(ctx context.Context, contentID, userID uuid.UUID) (*ContentReview, error) {
+		return h.service.ApproveContent(ctx, contentID, userID, r.FormValue("comment"))
+	}, "Content approved")
+}
+
+// HandleRequestChanges sends content in review back to its author with the
+// comment of the signed-in user.
+func (h *Handler) HandleRequestChanges(w http.ResponseWriter, r *http.Request) {
+	h.handleReview(w, r, func(ctx context.Context, contentID, userID uuid.UUID) (*ContentReview, error) {
+		return h.service.RequestChanges(ctx, contentID, userID, r.FormValue("comment"))
+	}, "Changes requested")
+}
+
+// handleReview runs a review action on the content of the form and returns to
+// the content page. When the action leaves the review with a newly assigned
+// reviewer, the reviewer is told by email.
+func (h *Handler) handleReview(w http.ResponseWriter, r *http.Request, action func(ctx context.Context, contentID, userID uuid.UUID) (*ContentReview, error), success string) {
+	ctx := r.Context()
+	site := getSiteFromContext(ctx)
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+	contentID, err := uuid.Parse(r.FormValue("content_id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+	userID, err := uuid.Parse(middleware.GetUserID(ctx))
+	if err != nil {
+		h.renderError(w, r, http.StatusUnauthorized, "Sign in required")
+		return
+	}
+	content, err := h.service.GetContent(ctx, contentID)
+	if err != nil || content.SiteID != site.ID {
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	previous, _ := h.service.GetContentReview(ctx, contentID)
+	back := "/ssg/get-content?id=" + contentID.String()
+	review, err := action(ctx, contentID, userID)
+	if err != nil {
+		msg := err.Error()
+		if !errors.Is(err, ErrReviewTransition) && !errors.Is(err, ErrReviewComment) && !errors.Is(err, ErrNotReviewer) {
+			h.log.Errorf("Cannot update review: %v", err)
+			msg = "Cannot update the review"
+		}
+		h.siteRedirect(w, r, back+"&error="+url.QueryEscape(msg))
+		return
+	}
+
+	if review.Status == ReviewInReview && review.ReviewerID != uuid.Nil &&
+		(previous == nil || previous.Status != ReviewInReview || previous.ReviewerID != review.ReviewerID) {
+		h.notifyReviewer(ctx, site, content, review, requestOrigin(r)+back+"&site_id="+site.ID.String())
+	}
+	h.siteRedirect(w, r, back+"&success="+url.QueryEscape(success))
+}
+
+// reviewRequired reports whether the site publishes drafts only once a
+// reviewer approves them, see ReviewRequiredRefKey.
+func (h *Handler) reviewRequired(ctx context.Context, siteID uuid.UUID) bool {
+	setting, err := h.service.GetSettingByRefKey(ctx, siteID, ReviewRequiredRefKey)
+	return err == nil && setting.Value == "true"
+}
+
+// notifyReviewer emails the reviewer assigned to content a link to it.
+// Failures are logged; the review stands either way.
+func (h *Handler) notifyReviewer(ctx context.Context, site *Site, content *Content, review *ContentReview, link string) {
+	if h.mailer == nil {
+		return
+	}
+	reviewers, err := h.service.ListReviewers(ctx)
+	if err != nil {
+		h.log.Errorf("Cannot list reviewers: %v", err)
+		return
+	}
+	for _, reviewer := range reviewers {
+		if reviewer.ID != review.ReviewerID || reviewer.Email == "" {
+			continue
+		}
+		msg := mail.Message{
+			To:      []string{reviewer.Email},
+			Subject: fmt.Sprintf("[%s] Review requested: %s", site.Name, content.Heading),
+			Body:    fmt.Sprintf("Hi %s,\n\n%q on %s is waiting for your review.\n\n%s\n", reviewer.Name, content.Heading, site.Name, link),
+		}
+		if err := h.mailer.Send(ctx, msg); err != nil {
+			h.log.Errorf("Cannot notify reviewer %s: %v", reviewer.Name, err)
+		}
+		return
+	}
+}
+
+// requestOrigin returns the scheme and host the dashboard was reached at.
+func requestOrigin(r *http.Request) string {
+	if r.TLS != nil {
+		return "https://" + r.Host
+	}
+	return "http://" + r.Host
+}
+
+// --- Layout Handlers ---
+
+func (h *Handler) HandleListLayouts(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	layouts, err := h.service.GetLayouts(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot list layouts: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load layouts")
+		return
+	}
+
+	h.render(w, r, "ssg/layouts/list", PageData{
+		Title:   "Layouts",
+		Site:    site,
+		Layouts: layouts,
+		Error:   r.URL.Query().Get("error"),
+		Success: r.URL.Query().Get("success"),
+	})
+}
+
+func (h *Handler) HandleNewLayout(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	contents, previewContent := h.layoutPreviewContents(r.Context(), site)
+
+	h.render(w, r, "ssg/layouts/new", PageData{
+		Title:    "New Layout",
+		Site:     site,
+		Contents: contents,
+		Content:  previewContent,
+	})
+}
+
+func (h *Handler) HandleCreateLayout(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	layout := NewLayout(site.ID, r.FormValue("name"), r.FormValue("description"))
+	layout.Code = r.FormValue("code")
+	layout.CSS = r.FormValue("css")
+	layout.ExcludeDefaultCSS = r.FormValue("exclude_default_css") == "on"
+
+	userIDStr := middleware.GetUserID(r.Context())
+	if userIDStr != "" {
+		if userID, err := uuid.Parse(userIDStr); err == nil {
+			layout.CreatedBy = userID
+			layout.UpdatedBy = userID
+		}
+	}
+
+	if err := h.service.CreateLayout(r.Context(), layout); err != nil {
+		h.log.Errorf("Cannot create layout: %v", err)
+		h.render(w, r, "ssg/layouts/new", PageData{
+			Title:  "New Layout",
+			Site:   site,
+			Layout: layout,
+			Error:  "Cannot create layout",
+		})
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/get-layout?id="+layout.ID.String())
+}
+
+func (h *Handler) HandleShowLayout(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
 		h.renderError(w, r, http.StatusBadRequest, "Site context required")
 		return
 	}
@@ -1544,10 +3219,14 @@ func (h *Handler) HandleEditLayout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	contents, previewContent := h.layoutPreviewContents(r.Context(), site)
+
 	h.render(w, r, "ssg/layouts/edit", PageData{
-		Title:  "Edit " + layout.Name,
-		Site:   site,
-		Layout: layout,
+		Title:    "Edit " + layout.Name,
+		Site:     site,
+		Layout:   layout,
+		Contents: contents,
+		Content:  previewContent,
 	})
 }
 
@@ -1603,6 +3282,124 @@ func (h *Handler) HandleUpdateLayout(w http.ResponseWriter, r *http.Request) {
 	h.siteRedirect(w, r, "/ssg/get-layout?id="+layout.ID.String())
 }
 
+// HandlePreviewLayout renders a content item with unsaved layout code.
+// The page is served sandboxed and nothing is written to disk; template
+// errors are shown inline so the author can fix them.
+func (h *Handler) HandlePreviewLayout(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.FormValue("preview_content_id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	content, err := h.service.GetContentWithMeta(r.Context(), contentID)
+	if err != nil || content.SiteID != site.ID {
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	contents, err := h.service.GetAllContentWithMeta(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get contents for layout preview: %v", err)
+		contents = []*Content{}
+	}
+
+	sections, err := h.service.GetSections(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get sections for layout preview: %v", err)
+		sections = []*Section{}
+	}
+
+	params, err := h.service.GetSettings(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get params for layout preview: %v", err)
+		params = []*Setting{}
+	}
+
+	layout := &Layout{
+		SiteID:            site.ID,
+		Code:              r.FormValue("code"),
+		CSS:               r.FormValue("css"),
+		ExcludeDefaultCSS: r.FormValue("exclude_default_css") == "on",
+	}
+
+	// Layout code runs against the admin origin: keep it in an opaque origin without scripts.
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	out, err := h.htmlGen.PreviewLayout(site, layout, content, contents, sections, params)
+	if err != nil {
+		if !errors.Is(err, ErrLayoutTemplate) {
+			h.log.Errorf("Cannot preview layout: %v", err)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprintf(w, "<!DOCTYPE html><html><body><h1>Layout preview failed</h1><pre>%s</pre></body></html>", template.HTMLEscapeString(err.Error()))
+		return
+	}
+
+	w.Write(out)
+}
+
+// HandlePreviewContent renders a single content item, drafts and scheduled
+// content included, as its page would be generated. Nothing is written to
+// disk; template errors are shown inline.
+func (h *Handler) HandlePreviewContent(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	contentID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid content ID")
+		return
+	}
+
+	out, err := h.service.RenderContentPreview(r.Context(), site, contentID)
+	if errors.Is(err, ErrNotFound) {
+		h.renderError(w, r, http.StatusNotFound, "Content not found")
+		return
+	}
+
+	// Bodies and layouts may carry raw HTML: keep them in an opaque origin without scripts.
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err != nil {
+		if !errors.Is(err, ErrLayoutTemplate) {
+			h.log.Errorf("Cannot preview content: %v", err)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprintf(w, "<!DOCTYPE html><html><body><h1>Content preview failed</h1><pre>%s</pre></body></html>", template.HTMLEscapeString(err.Error()))
+		return
+	}
+
+	w.Write(out)
+}
+
+// layoutPreviewContents returns the site's contents for the layout preview
+// picker, most recent first, and the item selected by default.
+func (h *Handler) layoutPreviewContents(ctx context.Context, site *Site) ([]*Content, *Content) {
+	contents, err := h.service.GetAllContentWithMeta(ctx, site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get contents for layout preview: %v", err)
+		return nil, nil
+	}
+	return previewCandidates(contents)
+}
+
 func (h *Handler) HandleDeleteLayout(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -1630,6 +3427,225 @@ func (h *Handler) HandleDeleteLayout(w http.ResponseWriter, r *http.Request) {
 	h.siteRedirect(w, r, "/ssg/list-layouts")
 }
 
+// HandleExportLayout downloads a layout as JSON, code and CSS included, for
+// import on another site.
+func (h *Handler) HandleExportLayout(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	layoutID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid layout ID")
+		return
+	}
+
+	export, err := h.service.ExportLayout(r.Context(), site.ID, layoutID)
+	if errors.Is(err, ErrNotFound) {
+		h.renderError(w, r, http.StatusNotFound, "Layout not found")
+		return
+	}
+	if err != nil {
+		h.log.Errorf("Cannot export layout: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot export layout")
+		return
+	}
+
+	filename := Slugify(export.Name)
+	if filename == "" {
+		filename = "layout"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-layout.json"`, filename))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(export)
+}
+
+// HandleImportLayout creates a layout on the site from an uploaded layout
+// export. The name in the file can be replaced to avoid a collision.
+func (h *Handler) HandleImportLayout(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	fail := func(msg string) {
+		h.siteRedirect(w, r, "/ssg/list-layouts?error="+url.QueryEscape(msg))
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxLayoutImportSize)
+	if err := r.ParseMultipartForm(maxLayoutImportSize); err != nil {
+		h.log.Errorf("Cannot parse multipart form: %v", err)
+		fail(fmt.Sprintf("Invalid form data or layout larger than %d MB", maxLayoutImportSize>>20))
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		fail("Please select a layout file (.json) to import")
+		return
+	}
+	defer file.Close()
+
+	var export LayoutExport
+	if err := json.NewDecoder(file).Decode(&export); err != nil {
+		fail("Cannot read layout: " + ErrLayoutExport.Error())
+		return
+	}
+	if name := strings.TrimSpace(r.FormValue("name")); name != "" {
+		export.Name = name
+	}
+
+	var userID uuid.UUID
+	if id, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		userID = id
+	}
+
+	layout, err := h.service.ImportLayout(r.Context(), site.ID, &export, userID)
+	if errors.Is(err, ErrLayoutExport) || errors.Is(err, ErrLayoutTemplate) || errors.Is(err, ErrLayoutNameTaken) {
+		fail("Cannot import layout: " + err.Error())
+		return
+	}
+	if err != nil {
+		h.log.Errorf("Cannot import layout: %v", err)
+		fail("Cannot import layout")
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/get-layout?id="+layout.ID.String())
+}
+
+// --- Content Kind Handlers ---
+
+func (h *Handler) HandleListKinds(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	kinds, err := h.service.ListContentKinds(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot list content kinds: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load kinds")
+		return
+	}
+
+	h.render(w, r, "ssg/kinds/list", PageData{
+		Title:        "Kinds",
+		Site:         site,
+		ContentKinds: kinds,
+	})
+}
+
+// HandleEditKind shows the settings of the named kind, or the form for a new
+// one when no name is given.
+func (h *Handler) HandleEditKind(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	kind := &ContentKind{SiteID: site.ID, Listed: true}
+	title := "New Kind"
+	if name := r.URL.Query().Get("name"); name != "" {
+		var err error
+		kind, err = h.service.GetContentKind(r.Context(), site.ID, name)
+		if err != nil {
+			h.log.Errorf("Cannot get content kind: %v", err)
+			h.renderError(w, r, http.StatusNotFound, "Kind not found")
+			return
+		}
+		title = "Edit " + kind.Label
+	}
+
+	layouts, _ := h.service.GetLayouts(r.Context(), site.ID)
+	h.render(w, r, "ssg/kinds/edit", PageData{
+		Title:       title,
+		Site:        site,
+		ContentKind: kind,
+		Layouts:     layouts,
+	})
+}
+
+func (h *Handler) HandleSaveKind(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	kind, err := h.service.GetContentKind(r.Context(), site.ID, name)
+	if errors.Is(err, ErrNotFound) {
+		kind = &ContentKind{SiteID: site.ID, Name: name}
+	} else if err != nil {
+		h.log.Errorf("Cannot get content kind: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot save kind")
+		return
+	}
+
+	kind.Label = strings.TrimSpace(r.FormValue("label"))
+	if kind.Label == "" {
+		kind.Label = kind.Name
+	}
+	kind.Permalink = strings.TrimSpace(r.FormValue("permalink"))
+	kind.Listed = r.FormValue("listed") == "on"
+	kind.Feed = r.FormValue("feed") == "on"
+	kind.Redirect = r.FormValue("redirect") == "on"
+	kind.LayoutID = uuid.Nil
+	if id, err := uuid.Parse(r.FormValue("layout_id")); err == nil {
+		kind.LayoutID = id
+	}
+
+	if err := h.service.SaveContentKind(r.Context(), kind); err != nil {
+		h.log.Errorf("Cannot save content kind: %v", err)
+		layouts, _ := h.service.GetLayouts(r.Context(), site.ID)
+		h.render(w, r, "ssg/kinds/edit", PageData{
+			Title:       "Edit " + kind.Label,
+			Site:        site,
+			ContentKind: kind,
+			Layouts:     layouts,
+			Error:       err.Error(),
+		})
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/list-kinds")
+}
+
+// HandleDeleteKind deletes a custom kind or resets a built-in one.
+func (h *Handler) HandleDeleteKind(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	if err := h.service.DeleteContentKind(r.Context(), site.ID, r.FormValue("name")); err != nil {
+		h.log.Errorf("Cannot delete content kind: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot delete kind")
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/list-kinds")
+}
+
 // --- Tag Handlers ---
 
 func (h *Handler) HandleListTags(w http.ResponseWriter, r *http.Request) {
@@ -1661,8 +3677,9 @@ func (h *Handler) HandleNewTag(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.render(w, r, "ssg/tags/new", PageData{
-		Title: "New Tag",
-		Site:  site,
+		Title:  "New Tag",
+		Site:   site,
+		Images: h.tagFormImages(r.Context(), site),
 	})
 }
 
@@ -1679,6 +3696,7 @@ func (h *Handler) HandleCreateTag(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tag := NewTag(site.ID, r.FormValue("name"))
+	parseTagForm(r, tag)
 
 	userIDStr := middleware.GetUserID(r.Context())
 	if userIDStr != "" {
@@ -1691,10 +3709,11 @@ func (h *Handler) HandleCreateTag(w http.ResponseWriter, r *http.Request) {
 	if err := h.service.CreateTag(r.Context(), tag); err != nil {
 		h.log.Errorf("Cannot create tag: %v", err)
 		h.render(w, r, "ssg/tags/new", PageData{
-			Title: "New Tag",
-			Site:  site,
-			Tag:   tag,
-			Error: "Cannot create tag",
+			Title:  "New Tag",
+			Site:   site,
+			Tag:    tag,
+			Images: h.tagFormImages(r.Context(), site),
+			Error:  "Cannot create tag",
 		})
 		return
 	}
@@ -1722,13 +3741,96 @@ func (h *Handler) HandleShowTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tagged, err := h.service.GetContentForTag(r.Context(), tag.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get content for tag: %v", err)
+	}
+
+	search := strings.TrimSpace(r.URL.Query().Get("q"))
+	found, _, err := h.service.GetContentWithPagination(r.Context(), site.ID, 0, tagPickerLimit, ContentFilter{Search: search})
+	if err != nil {
+		h.log.Errorf("Cannot search content for tag: %v", err)
+	}
+	isTagged := make(map[uuid.UUID]bool, len(tagged))
+	for _, c := range tagged {
+		isTagged[c.ID] = true
+	}
+	var candidates []*Content
+	for _, c := range found {
+		if !isTagged[c.ID] {
+			candidates = append(candidates, c)
+		}
+	}
+
 	h.render(w, r, "ssg/tags/show", PageData{
-		Title: tag.Name,
-		Site:  site,
-		Tag:   tag,
+		Title:         tag.Name,
+		Site:          site,
+		Tag:           tag,
+		Contents:      tagged,
+		TagCandidates: candidates,
+		Search:        search,
+		Success:       r.URL.Query().Get("success"),
+		Error:         r.URL.Query().Get("error"),
 	})
 }
 
+// tagPickerLimit caps the search results of the tag page content picker.
+const tagPickerLimit = 50
+
+// HandleBulkTagContent attaches the tag to, or detaches it from, the content
+// selected on the tag page.
+func (h *Handler) HandleBulkTagContent(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	tagID, err := uuid.Parse(r.FormValue("tag_id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid tag ID")
+		return
+	}
+
+	tag, err := h.service.GetTag(r.Context(), tagID)
+	if err != nil || tag.SiteID != site.ID {
+		h.renderError(w, r, http.StatusNotFound, "Tag not found")
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, v := range r.Form["content_id"] {
+		if id, err := uuid.Parse(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	back := "/ssg/get-tag?id=" + tag.ID.String()
+	if len(ids) == 0 {
+		h.siteRedirect(w, r, back+"&error="+url.QueryEscape("Select some content first"))
+		return
+	}
+
+	remove := r.FormValue("action") == "detach"
+	count, err := h.service.BulkTagContent(r.Context(), tag.ID, ids, remove)
+	if err != nil {
+		h.log.Errorf("Cannot bulk tag content: %v", err)
+		h.siteRedirect(w, r, back+"&error="+url.QueryEscape("Cannot update tagged content"))
+		return
+	}
+
+	msg := fmt.Sprintf("Tagged %d content items with #%s", count, tag.Name)
+	if remove {
+		msg = fmt.Sprintf("Removed #%s from %d content items", tag.Name, count)
+	}
+	h.siteRedirect(w, r, back+"&success="+url.QueryEscape(msg))
+}
+
 func (h *Handler) HandleEditTag(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -1750,9 +3852,10 @@ func (h *Handler) HandleEditTag(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.render(w, r, "ssg/tags/edit", PageData{
-		Title: "Edit " + tag.Name,
-		Site:  site,
-		Tag:   tag,
+		Title:  "Edit " + tag.Name,
+		Site:   site,
+		Tag:    tag,
+		Images: h.tagFormImages(r.Context(), site),
 	})
 }
 
@@ -1783,6 +3886,7 @@ func (h *Handler) HandleUpdateTag(w http.ResponseWriter, r *http.Request) {
 
 	tag.Name = r.FormValue("name")
 	tag.Slug = Slugify(tag.Name)
+	parseTagForm(r, tag)
 
 	userIDStr := middleware.GetUserID(r.Context())
 	if userIDStr != "" {
@@ -1794,15 +3898,35 @@ func (h *Handler) HandleUpdateTag(w http.ResponseWriter, r *http.Request) {
 	if err := h.service.UpdateTag(r.Context(), tag); err != nil {
 		h.log.Errorf("Cannot update tag: %v", err)
 		h.render(w, r, "ssg/tags/edit", PageData{
-			Title: "Edit " + tag.Name,
-			Site:  site,
-			Tag:   tag,
-			Error: "Cannot update tag",
+			Title:  "Edit " + tag.Name,
+			Site:   site,
+			Tag:    tag,
+			Images: h.tagFormImages(r.Context(), site),
+			Error:  "Cannot update tag",
 		})
 		return
 	}
 
-	h.siteRedirect(w, r, "/ssg/list-tags")
+	h.siteRedirect(w, r, "/ssg/list-tags")
+}
+
+// parseTagForm reads the tag page fields shared by the create and update forms.
+func parseTagForm(r *http.Request, tag *Tag) {
+	tag.Description = strings.TrimSpace(r.FormValue("description"))
+	tag.HeaderImageID = uuid.Nil
+	if id, err := uuid.Parse(r.FormValue("header_image_id")); err == nil {
+		tag.HeaderImageID = id
+	}
+}
+
+// tagFormImages lists the site images offered as a tag page header image.
+func (h *Handler) tagFormImages(ctx context.Context, site *Site) []*Image {
+	images, err := h.service.GetImages(ctx, site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get images for tag form: %v", err)
+		return nil
+	}
+	return images
 }
 
 func (h *Handler) HandleDeleteTag(w http.ResponseWriter, r *http.Request) {
@@ -1841,7 +3965,11 @@ func (h *Handler) HandleListSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	params, err := h.service.GetSettings(r.Context(), site.ID)
+	h.renderSettingsList(w, r, site, "")
+}
+
+func (h *Handler) renderSettingsList(w http.ResponseWriter, r *http.Request, site *Site, errMsg string) {
+	categories, err := h.service.GetSettingsByCategory(r.Context(), site.ID)
 	if err != nil {
 		h.log.Errorf("Cannot list params: %v", err)
 		h.renderError(w, r, http.StatusInternalServerError, "Cannot load params")
@@ -1849,12 +3977,93 @@ func (h *Handler) HandleListSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.render(w, r, "ssg/settings/list", PageData{
-		Title:  "Settings",
-		Site:   site,
-		Settings: params,
+		Title:             "Settings",
+		Site:              site,
+		SettingCategories: categories,
+		Themes:            h.htmlGen.Themes(site.Slug),
+		Error:             errMsg,
+		Success:           r.URL.Query().Get("success"),
 	})
 }
 
+// HandleReorderSettings saves the order settings were dragged into on the
+// settings page. The JSON body names the category and lists the IDs of all
+// its settings in their new order.
+func (h *Handler) HandleReorderSettings(w http.ResponseWriter, r *http.Request) {
+	fail := func(status int, msg string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	}
+
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		fail(http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	var req struct {
+		Category string      `json:"category"`
+		IDs      []uuid.UUID `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	err := h.service.ReorderSettings(r.Context(), site.ID, req.Category, req.IDs)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		fail(http.StatusNotFound, "Category not found")
+		return
+	case errors.Is(err, ErrSettingOrder):
+		fail(http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		h.log.Errorf("Cannot reorder settings: %v", err)
+		fail(http.StatusInternalServerError, "Cannot save the order")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUploadTheme installs a zip bundle as a theme of the site. The theme
+// is used once the ssg.theme setting names it.
+func (h *Handler) HandleUploadTheme(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxThemeUploadSize)
+	if err := r.ParseMultipartForm(maxThemeUploadSize); err != nil {
+		h.log.Errorf("Cannot parse multipart form: %v", err)
+		h.renderSettingsList(w, r, site, fmt.Sprintf("Invalid form data or theme larger than %d MB", maxThemeUploadSize>>20))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.renderSettingsList(w, r, site, "Please select a theme bundle (.zip) to upload")
+		return
+	}
+	defer file.Close()
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		name = strings.ToLower(strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename)))
+	}
+	if err := h.htmlGen.InstallTheme(site.Slug, name, file, header.Size); err != nil {
+		h.log.Errorf("Cannot install theme %q: %v", name, err)
+		h.renderSettingsList(w, r, site, "Cannot install theme: "+err.Error())
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/list-settings?success="+url.QueryEscape("Theme "+name+" uploaded"))
+}
+
 func (h *Handler) HandleNewSetting(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -1890,6 +4099,7 @@ func (h *Handler) HandleCreateSetting(w http.ResponseWriter, r *http.Request) {
 	}
 	param.Constraints = r.FormValue("constraints")
 	param.UIControl = r.FormValue("ui_control")
+	param.Secret = r.FormValue("secret") == "true"
 
 	userIDStr := middleware.GetUserID(r.Context())
 	if userIDStr != "" {
@@ -1899,17 +4109,34 @@ func (h *Handler) HandleCreateSetting(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if param.RefKey == CronPublishRefKey {
+		if err := ValidateCronSpec(param.Value); err != nil {
+			h.render(w, r, "ssg/settings/new", PageData{
+				Title:   "New Parameter",
+				Site:    site,
+				Setting: param,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
 	if err := h.service.CreateSetting(r.Context(), param); err != nil {
 		h.log.Errorf("Cannot create param: %v", err)
+		msg := "Cannot create parameter"
+		if isSecretError(err) {
+			msg = err.Error()
+		}
 		h.render(w, r, "ssg/settings/new", PageData{
 			Title: "New Parameter",
 			Site:  site,
 			Setting: param,
-			Error: "Cannot create parameter",
+			Error: msg,
 		})
 		return
 	}
 
+	h.reloadScheduler(r.Context(), param.RefKey)
 	h.siteRedirect(w, r, "/ssg/list-settings")
 }
 
@@ -1927,6 +4154,11 @@ func (h *Handler) HandleShowParam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	param, err := h.service.GetSetting(r.Context(), paramID)
+	if isSecretError(err) {
+		h.log.Errorf("Cannot get param: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
 	if err != nil {
 		h.log.Errorf("Cannot get param: %v", err)
 		h.renderError(w, r, http.StatusNotFound, "Parameter not found")
@@ -1954,6 +4186,11 @@ func (h *Handler) HandleEditSetting(w http.ResponseWriter, r *http.Request) {
 	}
 
 	param, err := h.service.GetSetting(r.Context(), paramID)
+	if isSecretError(err) {
+		h.log.Errorf("Cannot get param: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
 	if err != nil {
 		h.log.Errorf("Cannot get param: %v", err)
 		h.renderError(w, r, http.StatusNotFound, "Parameter not found")
@@ -1986,6 +4223,11 @@ func (h *Handler) HandleUpdateSetting(w http.ResponseWriter, r *http.Request) {
 	}
 
 	param, err := h.service.GetSetting(r.Context(), paramID)
+	if isSecretError(err) {
+		h.log.Errorf("Cannot get param: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
 	if err != nil {
 		h.log.Errorf("Cannot get param: %v", err)
 		h.renderError(w, r, http.StatusNotFound, "Parameter not found")
@@ -1995,6 +4237,7 @@ func (h *Handler) HandleUpdateSetting(w http.ResponseWriter, r *http.Request) {
 	if !param.System {
 		param.Name = r.FormValue("name")
 		param.Description = r.FormValue("description")
+		param.Secret = r.FormValue("secret") == "true"
 	}
 	if param.Type == SettingTypeBoolean {
 		if r.FormValue("value") == "true" {
@@ -2013,20 +4256,132 @@ func (h *Handler) HandleUpdateSetting(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if param.RefKey == CronPublishRefKey {
+		if err := ValidateCronSpec(param.Value); err != nil {
+			h.render(w, r, "ssg/settings/edit", PageData{
+				Title:   "Edit " + param.Name,
+				Site:    site,
+				Setting: param,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	if param.RefKey == HeadHTMLRefKey || param.RefKey == FooterHTMLRefKey {
+		if err := ValidateHTMLSnippet(param.Value); err != nil {
+			h.render(w, r, "ssg/settings/edit", PageData{
+				Title:   "Edit " + param.Name,
+				Site:    site,
+				Setting: param,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
 	if err := h.service.UpdateSetting(r.Context(), param); err != nil {
 		h.log.Errorf("Cannot update param: %v", err)
+		msg := "Cannot update parameter"
+		if isSecretError(err) {
+			msg = err.Error()
+		}
 		h.render(w, r, "ssg/settings/edit", PageData{
 			Title: "Edit " + param.Name,
 			Site:  site,
 			Setting: param,
-			Error: "Cannot update parameter",
+			Error: msg,
 		})
 		return
 	}
 
+	h.reloadScheduler(r.Context(), param.RefKey)
 	h.siteRedirect(w, r, "/ssg/list-settings")
 }
 
+// HandlePreviewSnippet renders the most recent post with an unsaved head or
+// footer HTML snippet in place, for the iframe on the setting's edit page.
+func (h *Handler) HandlePreviewSnippet(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	paramID, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid param ID")
+		return
+	}
+
+	param, err := h.service.GetSetting(r.Context(), paramID)
+	if err != nil || param.SiteID != site.ID || (param.RefKey != HeadHTMLRefKey && param.RefKey != FooterHTMLRefKey) {
+		h.renderError(w, r, http.StatusNotFound, "Parameter not found")
+		return
+	}
+
+	// Snippets run against the admin origin: keep them in an opaque origin without scripts.
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	previewFailed := func(status int, msg string) {
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "<!DOCTYPE html><html><body><h1>Snippet preview failed</h1><pre>%s</pre></body></html>", template.HTMLEscapeString(msg))
+	}
+
+	value := r.FormValue("value")
+	if err := ValidateHTMLSnippet(value); err != nil {
+		previewFailed(http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	contents, content := h.layoutPreviewContents(r.Context(), site)
+	if content == nil {
+		previewFailed(http.StatusUnprocessableEntity, "The site has no content to preview with")
+		return
+	}
+
+	sections, err := h.service.GetSections(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get sections for snippet preview: %v", err)
+		sections = []*Section{}
+	}
+
+	params, err := h.service.GetSettings(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get params for snippet preview: %v", err)
+		params = []*Setting{}
+	}
+	for i, p := range params {
+		if p.ID == param.ID {
+			preview := *p
+			preview.Value = value
+			params[i] = &preview
+		}
+	}
+
+	layout := &Layout{SiteID: site.ID}
+	if site.DefaultLayoutID != uuid.Nil {
+		if l, err := h.service.GetLayout(r.Context(), site.DefaultLayoutID); err == nil {
+			layout = l
+		}
+	}
+
+	out, err := h.htmlGen.PreviewLayout(site, layout, content, contents, sections, params)
+	if err != nil {
+		h.log.Errorf("Cannot preview snippet: %v", err)
+		previewFailed(http.StatusInternalServerError, "Cannot render the preview")
+		return
+	}
+
+	w.Write(out)
+}
+
 func (h *Handler) HandleDeleteSetting(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -2045,12 +4400,18 @@ func (h *Handler) HandleDeleteSetting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var refKey string
+	if param, err := h.service.GetSetting(r.Context(), paramID); err == nil {
+		refKey = param.RefKey
+	}
+
 	if err := h.service.DeleteSetting(r.Context(), paramID); err != nil {
 		h.log.Errorf("Cannot delete param: %v", err)
 		h.renderError(w, r, http.StatusInternalServerError, "Cannot delete parameter")
 		return
 	}
 
+	h.reloadScheduler(r.Context(), refKey)
 	h.siteRedirect(w, r, "/ssg/list-settings")
 }
 
@@ -2063,20 +4424,69 @@ func (h *Handler) HandleListImages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	images, err := h.service.GetImages(r.Context(), site.ID)
+	page := 1
+	if p := r.URL.Query().Get("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	limit := 48
+	offset := (page - 1) * limit
+	filter := imageFilterFromQuery(r.URL.Query())
+
+	images, total, err := h.service.GetImagesWithPagination(r.Context(), site.ID, offset, limit, filter)
 	if err != nil {
 		h.log.Errorf("Cannot list images: %v", err)
 		h.renderError(w, r, http.StatusInternalServerError, "Cannot load images")
 		return
 	}
 
+	totalPages := (total + limit - 1) / limit
+
 	h.render(w, r, "ssg/images/list", PageData{
-		Title:  "Images",
-		Site:   site,
-		Images: images,
+		Title:       "Images",
+		Site:        site,
+		Images:      images,
+		CurrentPage: page,
+		TotalPages:  totalPages,
+		HasPrev:     page > 1,
+		HasNext:     page < totalPages,
+		Search:      filter.Search,
+		ImageFilter: filter,
+		FilterQuery: template.URL(filter.query().Encode()),
+		Collections: h.siteCollections(r.Context(), site),
 	})
 }
 
+// imageFilterFromQuery reads the image list filters, ignoring unknown values.
+func imageFilterFromQuery(query url.Values) ImageFilter {
+	filter := ImageFilter{Search: query.Get("q")}
+	switch usage := query.Get("usage"); usage {
+	case ImageUsageUsed, ImageUsageUnused:
+		filter.Usage = usage
+	}
+	if id, err := uuid.Parse(query.Get("collection")); err == nil {
+		filter.CollectionID = id
+	}
+	return filter
+}
+
+// query encodes the filter back into list parameters, for links that keep it.
+func (f ImageFilter) query() url.Values {
+	values := url.Values{}
+	if f.Search != "" {
+		values.Set("q", f.Search)
+	}
+	if f.Usage != "" {
+		values.Set("usage", f.Usage)
+	}
+	if f.CollectionID != uuid.Nil {
+		values.Set("collection", f.CollectionID.String())
+	}
+	return values
+}
+
 func (h *Handler) HandleNewImage(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -2085,8 +4495,9 @@ func (h *Handler) HandleNewImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.render(w, r, "ssg/images/new", PageData{
-		Title: "New Image",
-		Site:  site,
+		Title:       "New Image",
+		Site:        site,
+		Collections: h.siteCollections(r.Context(), site),
 	})
 }
 
@@ -2098,7 +4509,7 @@ func (h *Handler) HandleCreateImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse multipart form (max 10MB)
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
+	if err := r.ParseMultipartForm(maxImageUploadSize); err != nil {
 		h.log.Errorf("Cannot parse multipart form: %v", err)
 		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
 		return
@@ -2109,9 +4520,10 @@ func (h *Handler) HandleCreateImage(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		h.log.Errorf("Cannot get uploaded file: %v", err)
 		h.render(w, r, "ssg/images/new", PageData{
-			Title: "Upload Image",
-			Site:  site,
-			Error: "Please select a file to upload",
+			Title:       "Upload Image",
+			Site:        site,
+			Collections: h.siteCollections(r.Context(), site),
+			Error:       "Please select a file to upload",
 		})
 		return
 	}
@@ -2132,11 +4544,28 @@ func (h *Handler) HandleCreateImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	uniqueID := uuid.New().String()[:8]
-	fileName := Slugify(strings.TrimSuffix(header.Filename, ext)) + "-" + uniqueID + ext
+	fileName := uniqueImageFileName(header.Filename)
 	filePath := filepath.Join(imagesPath, fileName)
 
+	// Create image record
+	image := NewImage(site.ID, header.Filename, fileName)
+	image.Title = title
+	image.AltText = altText
+	image.Attribution = attribution
+	image.AttributionURL = attributionURL
+	image.Stock = r.FormValue("stock") == "on"
+
+	if err := image.Validate(h.requireStockAttribution(r.Context(), site.ID)); err != nil {
+		h.render(w, r, "ssg/images/new", PageData{
+			Title:       "Upload Image",
+			Site:        site,
+			Image:       image,
+			Collections: h.siteCollections(r.Context(), site),
+			Error:       err.Error(),
+		})
+		return
+	}
+
 	// Create destination file
 	dst, err := os.Create(filePath)
 	if err != nil {
@@ -2153,13 +4582,6 @@ func (h *Handler) HandleCreateImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create image record
-	image := NewImage(site.ID, header.Filename, fileName)
-	image.Title = title
-	image.AltText = altText
-	image.Attribution = attribution
-	image.AttributionURL = attributionURL
-
 	// Get user ID from context
 	userIDStr := middleware.GetUserID(r.Context())
 	if userIDStr != "" {
@@ -2169,20 +4591,174 @@ func (h *Handler) HandleCreateImage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if err := h.service.CreateImage(r.Context(), image); err != nil {
-		h.log.Errorf("Cannot create image record: %v", err)
-		// Delete uploaded file on error
+	if err := h.service.CreateImage(r.Context(), image); err != nil {
+		h.log.Errorf("Cannot create image record: %v", err)
+		// Delete uploaded file on error
+		os.Remove(filePath)
+		h.render(w, r, "ssg/images/new", PageData{
+			Title:       "Upload Image",
+			Site:        site,
+			Collections: h.siteCollections(r.Context(), site),
+			Error:       "Cannot save image record",
+		})
+		return
+	}
+	h.addToUploadCollection(r, site, image)
+
+	h.log.Infof("Image uploaded: %s", fileName)
+	h.siteRedirect(w, r, "/ssg/list-images")
+}
+
+// HandleBulkUploadImages stores several images sent in one multipart request.
+// Files with an unsupported type or over the size limit are skipped and
+// reported; the accepted ones are recorded in a single transaction.
+func (h *Handler) HandleBulkUploadImages(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	data := PageData{
+		Title: "Upload Images",
+		Site:  site,
+	}
+
+	if r.MultipartForm == nil {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBulkUploadSize)
+	}
+	if err := r.ParseMultipartForm(maxImageUploadSize); err != nil {
+		h.log.Errorf("Cannot parse multipart form: %v", err)
+		data.Error = fmt.Sprintf("Invalid form data or upload larger than %d MB", maxBulkUploadSize>>20)
+		h.renderUploadResults(w, r, data)
+		return
+	}
+
+	headers := r.MultipartForm.File["files"]
+	if len(headers) == 0 {
+		data.Error = "Please select at least one file to upload"
+		h.renderUploadResults(w, r, data)
+		return
+	}
+
+	var total int64
+	for _, header := range headers {
+		total += header.Size
+	}
+	if total > maxBulkUploadSize {
+		data.Error = fmt.Sprintf("Upload larger than %d MB", maxBulkUploadSize>>20)
+		h.renderUploadResults(w, r, data)
+		return
+	}
+
+	imagesPath := h.workspace.GetImagesPath(site.Slug)
+	if err := os.MkdirAll(imagesPath, 0755); err != nil {
+		h.log.Errorf("Cannot create images directory: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot create images directory")
+		return
+	}
+
+	var userID uuid.UUID
+	if userIDStr := middleware.GetUserID(r.Context()); userIDStr != "" {
+		userID, _ = uuid.Parse(userIDStr)
+	}
+
+	var images []*Image
+	var written []string
+	for _, header := range headers {
+		result := ImageUploadResult{FileName: header.Filename}
+		image, filePath, err := saveUploadedImage(site.ID, imagesPath, header)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			image.CreatedBy = userID
+			image.UpdatedBy = userID
+			result.Image = image
+			images = append(images, image)
+			written = append(written, filePath)
+		}
+		data.UploadResults = append(data.UploadResults, result)
+	}
+
+	if len(images) > 0 {
+		if err := h.service.CreateImages(r.Context(), images); err != nil {
+			h.log.Errorf("Cannot create image records: %v", err)
+			for _, filePath := range written {
+				os.Remove(filePath)
+			}
+			for i := range data.UploadResults {
+				if data.UploadResults[i].Image != nil {
+					data.UploadResults[i].Image = nil
+					data.UploadResults[i].Error = "Cannot save image record"
+				}
+			}
+			images = nil
+		}
+	}
+	h.addToUploadCollection(r, site, images...)
+
+	h.log.Infof("Bulk upload: %d of %d images stored", len(images), len(headers))
+	h.renderUploadResults(w, r, data)
+}
+
+// saveUploadedImage writes one uploaded file to the images directory and
+// returns the image to record for it.
+func saveUploadedImage(siteID uuid.UUID, imagesPath string, header *multipart.FileHeader) (*Image, string, error) {
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !isImageExtension(ext) {
+		return nil, "", fmt.Errorf("unsupported file type %q", ext)
+	}
+	if header.Size > maxImageUploadSize {
+		return nil, "", fmt.Errorf("file larger than %d MB", maxImageUploadSize>>20)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read upload: %w", err)
+	}
+	defer file.Close()
+
+	fileName := uniqueImageFileName(header.Filename)
+	filePath := filepath.Join(imagesPath, fileName)
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot save file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
 		os.Remove(filePath)
-		h.render(w, r, "ssg/images/new", PageData{
-			Title: "Upload Image",
-			Site:  site,
-			Error: "Cannot save image record",
-		})
+		return nil, "", fmt.Errorf("cannot save file: %w", err)
+	}
+
+	return NewImage(siteID, header.Filename, fileName), filePath, nil
+}
+
+// uniqueImageFileName slugifies an uploaded file name and appends a short
+// random suffix so uploads sharing a name do not overwrite each other.
+func uniqueImageFileName(original string) string {
+	ext := filepath.Ext(original)
+	return Slugify(strings.TrimSuffix(original, ext)) + "-" + uuid.New().String()[:8] + ext
+}
+
+// renderUploadResults answers HTMX requests with the results fragment only,
+// and plain form posts with the full page.
+func (h *Handler) renderUploadResults(w http.ResponseWriter, r *http.Request, data PageData) {
+	if r.Header.Get("HX-Request") != "true" {
+		h.render(w, r, "ssg/images/upload-results", data)
 		return
 	}
 
-	h.log.Infof("Image uploaded: %s", fileName)
-	h.siteRedirect(w, r, "/ssg/list-images")
+	tmpl, err := template.ParseFS(h.templatesFS, "assets/templates/ssg/images/upload-results.html")
+	if err != nil {
+		h.log.Errorf("Template parse error for upload results: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	if err := tmpl.ExecuteTemplate(w, "upload-results", data); err != nil {
+		h.log.Errorf("Template execute error for upload results: %v", err)
+	}
 }
 
 func (h *Handler) HandleShowImage(w http.ResponseWriter, r *http.Request) {
@@ -2205,10 +4781,22 @@ func (h *Handler) HandleShowImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	usage, err := h.service.GetImageUsage(r.Context(), imageID)
+	if err != nil {
+		h.log.Errorf("Cannot get image usage: %v", err)
+	}
+
+	collections, err := h.service.GetImageCollections(r.Context(), imageID)
+	if err != nil {
+		h.log.Errorf("Cannot get image collections: %v", err)
+	}
+
 	h.render(w, r, "ssg/images/show", PageData{
-		Title: image.FileName,
-		Site:  site,
-		Image: image,
+		Title:       image.FileName,
+		Site:        site,
+		Image:       image,
+		ImageUsage:  usage,
+		Collections: collections,
 	})
 }
 
@@ -2232,10 +4820,18 @@ func (h *Handler) HandleEditImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	usage, err := h.service.GetImageUsage(r.Context(), imageID)
+	if err != nil {
+		h.log.Errorf("Cannot get image usage: %v", err)
+	}
+
 	h.render(w, r, "ssg/images/edit", PageData{
-		Title: "Edit " + image.FileName,
-		Site:  site,
-		Image: image,
+		Title:            "Edit " + image.FileName,
+		Site:             site,
+		Image:            image,
+		ImageUsage:       usage,
+		Collections:      h.siteCollections(r.Context(), site),
+		ImageCollections: h.imageCollectionSet(r.Context(), image.ID),
 	})
 }
 
@@ -2268,6 +4864,28 @@ func (h *Handler) HandleUpdateImage(w http.ResponseWriter, r *http.Request) {
 	image.Title = r.FormValue("title")
 	image.Attribution = r.FormValue("attribution")
 	image.AttributionURL = r.FormValue("attribution_url")
+	image.Stock = r.FormValue("stock") == "on"
+
+	collectionIDs := formCollectionIDs(r)
+	editPage := func(msg string) PageData {
+		selected := make(map[uuid.UUID]bool, len(collectionIDs))
+		for _, id := range collectionIDs {
+			selected[id] = true
+		}
+		return PageData{
+			Title:            "Edit " + image.FileName,
+			Site:             site,
+			Image:            image,
+			Collections:      h.siteCollections(r.Context(), site),
+			ImageCollections: selected,
+			Error:            msg,
+		}
+	}
+
+	if err := image.Validate(h.requireStockAttribution(r.Context(), site.ID)); err != nil {
+		h.render(w, r, "ssg/images/edit", editPage(err.Error()))
+		return
+	}
 
 	userIDStr := middleware.GetUserID(r.Context())
 	if userIDStr != "" {
@@ -2278,12 +4896,13 @@ func (h *Handler) HandleUpdateImage(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.UpdateImage(r.Context(), image); err != nil {
 		h.log.Errorf("Cannot update image: %v", err)
-		h.render(w, r, "ssg/images/edit", PageData{
-			Title: "Edit " + image.FileName,
-			Site:  site,
-			Image: image,
-			Error: "Cannot update image",
-		})
+		h.render(w, r, "ssg/images/edit", editPage("Cannot update image"))
+		return
+	}
+
+	if err := h.service.SetImageCollections(r.Context(), image.ID, collectionIDs); err != nil {
+		h.log.Errorf("Cannot set image collections: %v", err)
+		h.render(w, r, "ssg/images/edit", editPage("Cannot update the collections of the image"))
 		return
 	}
 
@@ -2315,6 +4934,23 @@ func (h *Handler) HandleDeleteImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	usage, err := h.service.GetImageUsage(r.Context(), imageID)
+	if err != nil {
+		h.log.Errorf("Cannot get image usage: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot check where the image is used")
+		return
+	}
+	if usage.InUse() {
+		h.render(w, r, "ssg/images/show", PageData{
+			Title:      image.FileName,
+			Site:       site,
+			Image:      image,
+			ImageUsage: usage,
+			Error:      "This image is still in use. Remove it from the places listed below before deleting it.",
+		})
+		return
+	}
+
 	if err := h.service.DeleteImage(r.Context(), imageID); err != nil {
 		h.log.Errorf("Cannot delete image: %v", err)
 		h.renderError(w, r, http.StatusInternalServerError, "Cannot delete image")
@@ -2329,8 +4965,352 @@ func (h *Handler) HandleDeleteImage(w http.ResponseWriter, r *http.Request) {
 	h.siteRedirect(w, r, "/ssg/list-images")
 }
 
+// HandleListOrphanedImages shows the images that no content, section or
+// layout uses, so they can be reviewed before purging.
+func (h *Handler) HandleListOrphanedImages(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	images, err := h.service.FindOrphanedImages(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot find orphaned images: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot find orphaned images")
+		return
+	}
+
+	imagesPath := h.workspace.GetImagesPath(site.Slug)
+	var total int64
+	orphans := make([]OrphanedImage, len(images))
+	for i, image := range images {
+		orphans[i] = OrphanedImage{Image: image}
+		if info, err := os.Stat(filepath.Join(imagesPath, image.FilePath)); err == nil {
+			orphans[i].Size = formatBytes(info.Size())
+			total += info.Size()
+		}
+	}
+
+	h.render(w, r, "ssg/images/orphaned", PageData{
+		Title:           "Orphaned Images",
+		Site:            site,
+		OrphanedImages:  orphans,
+		ReclaimableSize: formatBytes(total),
+		Error:           r.URL.Query().Get("error"),
+		Success:         r.URL.Query().Get("success"),
+	})
+}
+
+// HandlePurgeOrphanedImages deletes the reviewed orphaned images.
+func (h *Handler) HandlePurgeOrphanedImages(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	if r.FormValue("confirm") != "on" {
+		h.siteRedirect(w, r, "/ssg/orphaned-images?error="+url.QueryEscape("Confirm the deletion before purging"))
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, v := range r.Form["id"] {
+		if id, err := uuid.Parse(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	result, err := h.service.PurgeOrphanedImages(r.Context(), site.ID, ids)
+	if err != nil {
+		h.log.Errorf("Cannot purge orphaned images: %v", err)
+		h.siteRedirect(w, r, "/ssg/orphaned-images?error="+url.QueryEscape("Cannot purge orphaned images"))
+		return
+	}
+
+	msg := fmt.Sprintf("Deleted %d images, reclaimed %s", result.Deleted, formatBytes(result.ReclaimedBytes))
+	if result.Skipped > 0 {
+		msg += fmt.Sprintf(" (%d kept because they are in use again)", result.Skipped)
+	}
+	h.log.Infof("Orphaned images purged for %s: %s", site.Slug, msg)
+	h.siteRedirect(w, r, "/ssg/orphaned-images?success="+url.QueryEscape(msg))
+}
+
+// formatBytes renders a byte count for display, e.g. "1.5 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// --- Collection Handlers ---
+
+func (h *Handler) HandleListCollections(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	collections, err := h.service.GetCollections(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot list collections: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load collections")
+		return
+	}
+
+	h.render(w, r, "ssg/collections/list", PageData{
+		Title:       "Collections",
+		Site:        site,
+		Collections: collections,
+		Error:       r.URL.Query().Get("error"),
+		Success:     r.URL.Query().Get("success"),
+	})
+}
+
+func (h *Handler) HandleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	collection := NewCollection(site.ID, strings.TrimSpace(r.FormValue("name")))
+	if userID, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		collection.CreatedBy = userID
+		collection.UpdatedBy = userID
+	}
+
+	if err := h.service.CreateCollection(r.Context(), collection); err != nil {
+		h.log.Errorf("Cannot create collection: %v", err)
+		h.siteRedirect(w, r, "/ssg/list-collections?error="+url.QueryEscape(collectionError(err, "Cannot create collection")))
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/list-collections?success="+url.QueryEscape("Collection "+collection.Name+" created"))
+}
+
+func (h *Handler) HandleUpdateCollection(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	collection, ok := h.siteCollection(w, r, site, r.FormValue("id"))
+	if !ok {
+		return
+	}
+
+	collection.Name = strings.TrimSpace(r.FormValue("name"))
+	collection.Slug = Slugify(collection.Name)
+	collection.UpdatedAt = time.Now()
+	if userID, err := uuid.Parse(middleware.GetUserID(r.Context())); err == nil {
+		collection.UpdatedBy = userID
+	}
+
+	if err := h.service.UpdateCollection(r.Context(), collection); err != nil {
+		h.log.Errorf("Cannot update collection: %v", err)
+		h.siteRedirect(w, r, "/ssg/list-collections?error="+url.QueryEscape(collectionError(err, "Cannot rename collection")))
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/list-collections")
+}
+
+func (h *Handler) HandleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid form data")
+		return
+	}
+
+	collection, ok := h.siteCollection(w, r, site, r.FormValue("id"))
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCollection(r.Context(), collection.ID); err != nil {
+		h.log.Errorf("Cannot delete collection: %v", err)
+		h.siteRedirect(w, r, "/ssg/list-collections?error="+url.QueryEscape("Cannot delete collection"))
+		return
+	}
+
+	h.siteRedirect(w, r, "/ssg/list-collections?success="+url.QueryEscape("Collection "+collection.Name+" deleted, its images stay in the library"))
+}
+
+// siteCollection loads the collection with the given ID, answering with an
+// error when it is missing or belongs to another site.
+func (h *Handler) siteCollection(w http.ResponseWriter, r *http.Request, site *Site, rawID string) (*Collection, bool) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid collection ID")
+		return nil, false
+	}
+	collection, err := h.service.GetCollection(r.Context(), id)
+	if err != nil || collection == nil || collection.SiteID != site.ID {
+		h.renderError(w, r, http.StatusNotFound, "Collection not found")
+		return nil, false
+	}
+	return collection, true
+}
+
+// collectionError returns the message shown for a failed collection change.
+func collectionError(err error, fallback string) string {
+	if errors.Is(err, ErrSlugTaken) {
+		return "A collection with that name already exists"
+	}
+	if errors.Is(err, ErrCollectionName) {
+		return "Collection name is required"
+	}
+	return fallback
+}
+
+// siteCollections returns the collections of a site for the image forms and
+// filters, none when they cannot be loaded.
+func (h *Handler) siteCollections(ctx context.Context, site *Site) []*Collection {
+	collections, err := h.service.GetCollections(ctx, site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get collections: %v", err)
+		return nil
+	}
+	return collections
+}
+
+// imageCollectionSet returns the IDs of the collections an image is in.
+func (h *Handler) imageCollectionSet(ctx context.Context, imageID uuid.UUID) map[uuid.UUID]bool {
+	collections, err := h.service.GetImageCollections(ctx, imageID)
+	if err != nil {
+		h.log.Errorf("Cannot get image collections: %v", err)
+	}
+	set := make(map[uuid.UUID]bool, len(collections))
+	for _, c := range collections {
+		set[c.ID] = true
+	}
+	return set
+}
+
+// formCollectionIDs reads the collection_id values of a form, skipping
+// invalid ones.
+func formCollectionIDs(r *http.Request) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, v := range r.Form["collection_id"] {
+		if id, err := uuid.Parse(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// addToUploadCollection puts freshly uploaded images in the collection the
+// upload form targets, if any. Failures are logged: the images are stored
+// either way and can be added from their edit page.
+func (h *Handler) addToUploadCollection(r *http.Request, site *Site, images ...*Image) {
+	id, err := uuid.Parse(r.FormValue("collection_id"))
+	if err != nil || len(images) == 0 {
+		return
+	}
+	collection, err := h.service.GetCollection(r.Context(), id)
+	if err != nil || collection == nil || collection.SiteID != site.ID {
+		h.log.Errorf("Upload collection %s not found in site %s", id, site.Slug)
+		return
+	}
+	for _, image := range images {
+		if err := h.service.AddImageToCollection(r.Context(), collection.ID, image.ID); err != nil {
+			h.log.Errorf("Cannot add image %s to collection %s: %v", image.FileName, collection.Name, err)
+		}
+	}
+}
+
 // --- Workspace File Handlers ---
 
+// HandleCompileSeries downloads the published content of a series, or of a
+// whole section when section_id is given, as a single EPUB or HTML book.
+func (h *Handler) HandleCompileSeries(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	query := r.URL.Query()
+	format, err := ParseBookFormat(query.Get("format"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Unsupported format, use epub or html")
+		return
+	}
+
+	var name string
+	var book []byte
+	if rawID := query.Get("section_id"); rawID != "" {
+		sectionID, parseErr := uuid.Parse(rawID)
+		if parseErr != nil {
+			h.renderError(w, r, http.StatusBadRequest, "Invalid section ID")
+			return
+		}
+		if section, sectionErr := h.service.GetSection(r.Context(), sectionID); sectionErr == nil {
+			name = section.Name
+		}
+		book, err = h.service.CompileSection(r.Context(), site.ID, sectionID, format)
+	} else {
+		name = strings.TrimSpace(query.Get("series"))
+		if name == "" {
+			h.renderError(w, r, http.StatusBadRequest, "Series or section required")
+			return
+		}
+		if !h.featureEnabled(r.Context(), site.ID, FeatureSeries) {
+			h.renderError(w, r, http.StatusNotFound, "Page not found")
+			return
+		}
+		book, err = h.service.CompileSeries(r.Context(), site.ID, name, format)
+	}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrSectionNotInSite) {
+		h.renderError(w, r, http.StatusNotFound, "Nothing published to compile")
+		return
+	}
+	if err != nil {
+		h.log.Errorf("Cannot compile book: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot compile book")
+		return
+	}
+
+	filename := Slugify(name)
+	if filename == "" {
+		filename = site.Slug
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format.Extension()))
+	w.Write(book)
+}
+
 // HandleServeWorkspaceImage serves images from the workspace directory.
 func (h *Handler) HandleServeWorkspaceImage(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
@@ -2350,14 +5330,24 @@ func (h *Handler) HandleServeWorkspaceImage(w http.ResponseWriter, r *http.Reque
 	// Build the file path
 	filePath := filepath.Join(h.workspace.GetImagesPath(slug), filename)
 
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
 		http.Error(w, "Image not found", http.StatusNotFound)
 		return
 	}
 
-	// Serve the file
-	http.ServeFile(w, r, filePath)
+	maxAge, err := ParseCacheMaxAge(h.cfg.SSG.Preview)
+	if err != nil {
+		maxAge = DefaultCacheMaxAge
+	}
+	// The dashboard needs a session, so shared caches must not keep images.
+	serveCachedFile(w, r, filePath, info, cacheControl(filePath, maxAge, true))
+}
+
+// requireStockAttribution reports whether the site requires stock images to be credited.
+func (h *Handler) requireStockAttribution(ctx context.Context, siteID uuid.UUID) bool {
+	param, err := h.service.GetSettingByRefKey(ctx, siteID, RequireStockAttributionRefKey)
+	return err == nil && param != nil && param.Value == "true"
 }
 
 // --- Content Image Handlers ---
@@ -2408,11 +5398,22 @@ func (h *Handler) HandleUploadContentImage(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	uniqueID := uuid.New().String()[:8]
-	fileName := Slugify(strings.TrimSuffix(header.Filename, ext)) + "-" + uniqueID + ext
+	fileName := uniqueImageFileName(header.Filename)
 	filePath := filepath.Join(imagesPath, fileName)
 
+	// Create image record
+	image := NewImage(site.ID, header.Filename, fileName)
+	image.AltText = altText
+	image.Title = title
+	image.Attribution = attribution
+	image.AttributionURL = attributionURL
+	image.Stock = r.FormValue("stock") == "on"
+
+	if err := image.Validate(h.requireStockAttribution(r.Context(), site.ID)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Create destination file
 	dst, err := os.Create(filePath)
 	if err != nil {
@@ -2423,18 +5424,11 @@ func (h *Handler) HandleUploadContentImage(w http.ResponseWriter, r *http.Reques
 	defer dst.Close()
 
 	// Copy uploaded file
-	if _, err := io.Copy(dst, file); err != nil {
-		h.log.Errorf("Cannot write file: %v", err)
-		http.Error(w, "Cannot save file", http.StatusInternalServerError)
-		return
-	}
-
-	// Create image record
-	image := NewImage(site.ID, header.Filename, fileName)
-	image.AltText = altText
-	image.Title = title
-	image.Attribution = attribution
-	image.AttributionURL = attributionURL
+	if _, err := io.Copy(dst, file); err != nil {
+		h.log.Errorf("Cannot write file: %v", err)
+		http.Error(w, "Cannot save file", http.StatusInternalServerError)
+		return
+	}
 
 	// Get user ID from context
 	userIDStr := middleware.GetUserID(r.Context())
@@ -2451,6 +5445,7 @@ func (h *Handler) HandleUploadContentImage(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Cannot save image record", http.StatusInternalServerError)
 		return
 	}
+	h.addToUploadCollection(r, site, image)
 
 	// If this is a header image, remove existing header first
 	if isHeader {
@@ -2468,6 +5463,137 @@ func (h *Handler) HandleUploadContentImage(w http.ResponseWriter, r *http.Reques
 	w.WriteHeader(http.StatusOK)
 }
 
+// pickerPageSize is how many images a page of the content editor's library
+// picker shows.
+const pickerPageSize = 24
+
+// imagePickerView is a page of results of the library picker.
+type imagePickerView struct {
+	SiteID     uuid.UUID
+	SiteSlug   string
+	Images     []*Image
+	Total      int
+	Query      url.Values // search and collection, kept by the page links
+	Page       int
+	TotalPages int
+}
+
+func (v imagePickerView) PrevPage() int { return v.Page - 1 }
+func (v imagePickerView) NextPage() int { return v.Page + 1 }
+
+// imagePickerTmpl renders the results of the library picker. Picking an image
+// hands its data attributes to pickLibraryImage in the edit page.
+var imagePickerTmpl = template.Must(template.New("imagePicker").Parse(
+	`<div class="image-gallery">{{ range .Images }}` +
+		`<div class="gallery-image" title="{{ if .Title }}{{ .Title }}{{ else }}{{ .FileName }}{{ end }}" data-id="{{ .ID }}" data-file-path="{{ .FilePath }}" data-alt-text="{{ .AltText }}" onclick="pickLibraryImage(this.dataset)">` +
+		`<img src="/ssg/workspace/{{ $.SiteSlug }}/images/{{ .FilePath }}" alt="{{ .AltText }}" loading="lazy">` +
+		`<div class="overlay"><span class="overlay-alt">{{ if .AltText }}{{ .AltText }}{{ else }}{{ .FileName }}{{ end }}</span>` +
+		`<span class="overlay-title">{{ with .Title }}{{ . }} · {{ end }}{{ if .Width }}{{ .Width }}×{{ .Height }}{{ else }}Unknown size{{ end }}</span></div>` +
+		`</div>{{ else }}<p class="empty-state">{{ if .Query }}No images match{{ else }}No images yet{{ end }}</p>{{ end }}</div>` +
+		`{{ if gt .TotalPages 1 }}<div class="pagination">` +
+		`{{ if gt .Page 1 }}<button type="button" class="btn btn-sm" hx-get="/ssg/search-images?site_id={{ .SiteID }}&page={{ .PrevPage }}{{ with .Query.Encode }}&{{ . }}{{ end }}" hx-target="#library-images">&larr; Previous</button>{{ end }}` +
+		`<span>{{ .Total }} images, page {{ .Page }} of {{ .TotalPages }}</span>` +
+		`{{ if lt .Page .TotalPages }}<button type="button" class="btn btn-sm" hx-get="/ssg/search-images?site_id={{ .SiteID }}&page={{ .NextPage }}{{ with .Query.Encode }}&{{ . }}{{ end }}" hx-target="#library-images">Next &rarr;</button>{{ end }}` +
+		`</div>{{ end }}`))
+
+// HandleSearchImages answers the content editor's library picker with a page
+// of the site's images, as an HTMX fragment, matching the q query parameter
+// against their file name, title and alt text, within a collection if one is
+// given.
+func (h *Handler) HandleSearchImages(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		http.Error(w, "Site context required", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	page := 1
+	if parsed, err := strconv.Atoi(query.Get("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+	filter := imageFilterFromQuery(query)
+	filter.Usage = ""
+
+	images, total, err := h.service.SearchImages(r.Context(), site.ID, filter.Search, filter.CollectionID, (page-1)*pickerPageSize, pickerPageSize)
+	if err != nil {
+		h.log.Errorf("Cannot search images for picker: %v", err)
+		http.Error(w, "Cannot load images", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := imagePickerTmpl.Execute(w, imagePickerView{
+		SiteID:     site.ID,
+		SiteSlug:   site.Slug,
+		Images:     images,
+		Total:      total,
+		Query:      filter.query(),
+		Page:       page,
+		TotalPages: (total + pickerPageSize - 1) / pickerPageSize,
+	}); err != nil {
+		h.log.Errorf("Template execute error for image picker: %v", err)
+	}
+}
+
+// HandleLinkContentImage attaches a library image to content, as its header
+// image when purpose is "header", so picking it needs no new upload.
+func (h *Handler) HandleLinkContentImage(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		http.Error(w, "Site context required", http.StatusBadRequest)
+		return
+	}
+
+	contentID, err := uuid.Parse(r.URL.Query().Get("content_id"))
+	if err != nil {
+		http.Error(w, "Invalid content ID", http.StatusBadRequest)
+		return
+	}
+	imageID, err := uuid.Parse(r.FormValue("image_id"))
+	if err != nil {
+		http.Error(w, "Invalid image ID", http.StatusBadRequest)
+		return
+	}
+
+	content, err := h.service.GetContent(r.Context(), contentID)
+	if err != nil || content.SiteID != site.ID {
+		http.Error(w, "Content not found", http.StatusNotFound)
+		return
+	}
+	image, err := h.service.GetImage(r.Context(), imageID)
+	if err != nil || image.SiteID != site.ID {
+		http.Error(w, "Image not found", http.StatusNotFound)
+		return
+	}
+
+	// Content links an image once: keep a link that already fits, or drop it
+	// to link the image again with the other role.
+	isHeader := r.FormValue("purpose") == "header"
+	if links, err := h.service.GetContentImagesWithDetails(r.Context(), contentID); err == nil {
+		for _, link := range links {
+			if link.ID != image.ID {
+				continue
+			}
+			if link.IsHeader == isHeader {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			_ = h.service.UnlinkImageFromContent(r.Context(), link.ContentImageID)
+		}
+	}
+	if isHeader {
+		_ = h.service.UnlinkHeaderImageFromContent(r.Context(), contentID)
+	}
+	if err := h.service.LinkImageToContent(r.Context(), contentID, image.ID, isHeader); err != nil {
+		h.log.Errorf("Cannot link image to content: %v", err)
+		http.Error(w, "Cannot link image to content", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 func (h *Handler) HandleDeleteContentImage(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -2496,6 +5622,17 @@ func (h *Handler) HandleDeleteContentImage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Keep the image while anything else still uses it
+	usage, err := h.service.GetImageUsage(r.Context(), imageDetails.ImageID)
+	if err != nil || usage.InUse() {
+		if err != nil {
+			h.log.Errorf("Cannot get image usage: %v", err)
+		}
+		h.log.Infof("Content image unlinked, image still in use: %s", contentImageID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	// Delete the image record
 	if err := h.service.DeleteImage(r.Context(), imageDetails.ImageID); err != nil {
 		h.log.Errorf("Cannot delete image record: %v", err)
@@ -2573,11 +5710,21 @@ func (h *Handler) HandleUploadSectionImage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ext := filepath.Ext(header.Filename)
-	uniqueID := uuid.New().String()[:8]
-	fileName := Slugify(strings.TrimSuffix(header.Filename, ext)) + "-" + uniqueID + ext
+	fileName := uniqueImageFileName(header.Filename)
 	filePath := filepath.Join(imagesPath, fileName)
 
+	image := NewImage(site.ID, header.Filename, fileName)
+	image.AltText = altText
+	image.Title = title
+	image.Attribution = attribution
+	image.AttributionURL = attributionURL
+	image.Stock = r.FormValue("stock") == "on"
+
+	if err := image.Validate(h.requireStockAttribution(r.Context(), site.ID)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	dst, err := os.Create(filePath)
 	if err != nil {
 		h.log.Errorf("Cannot create file: %v", err)
@@ -2592,12 +5739,6 @@ func (h *Handler) HandleUploadSectionImage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	image := NewImage(site.ID, header.Filename, fileName)
-	image.AltText = altText
-	image.Title = title
-	image.Attribution = attribution
-	image.AttributionURL = attributionURL
-
 	userIDStr := middleware.GetUserID(r.Context())
 	if userIDStr != "" {
 		if userID, err := uuid.Parse(userIDStr); err == nil {
@@ -2651,6 +5792,17 @@ func (h *Handler) HandleDeleteSectionImage(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Keep the image while anything else still uses it
+	usage, err := h.service.GetImageUsage(r.Context(), imageDetails.ImageID)
+	if err != nil || usage.InUse() {
+		if err != nil {
+			h.log.Errorf("Cannot get image usage: %v", err)
+		}
+		h.log.Infof("Section image unlinked, image still in use: %s", sectionImageID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	if err := h.service.DeleteImage(r.Context(), imageDetails.ImageID); err != nil {
 		h.log.Errorf("Cannot delete image record: %v", err)
 	}
@@ -2704,12 +5856,19 @@ func (h *Handler) HandleUpdateMeta(w http.ResponseWriter, r *http.Request) {
 	meta.Description = r.FormValue("description")
 	meta.Keywords = r.FormValue("keywords")
 	meta.Robots = r.FormValue("robots")
-	meta.CanonicalURL = r.FormValue("canonical_url")
+	meta.CanonicalURL = strings.TrimSpace(r.FormValue("canonical_url"))
 	meta.TableOfContents = r.FormValue("table_of_contents") == "on"
 	meta.Share = r.FormValue("share") == "on"
 	meta.Comments = r.FormValue("comments") == "on"
+	meta.KeepLinks = r.FormValue("keep_links") == "on"
+	meta.ShowUpdated = r.FormValue("show_updated") == "on"
 	meta.UpdatedAt = time.Now()
 
+	if err := meta.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Get user ID from context
 	userIDStr := middleware.GetUserID(r.Context())
 	if userIDStr != "" {
@@ -2823,6 +5982,7 @@ func (h *Handler) HandleCreateContributor(w http.ResponseWriter, r *http.Request
 
 	contributor := NewContributor(site.ID, handle, name, surname)
 	contributor.Bio = bio
+	contributor.Role = formContributorRole(r.FormValue("role"), contributor.Role)
 	contributor.ProfileID = &contributorProfile.ID
 	contributor.CreatedBy = parseUUID(userIDStr)
 	contributor.UpdatedBy = contributor.CreatedBy
@@ -2860,13 +6020,31 @@ func (h *Handler) HandleShowContributor(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	contents, err := h.service.GetContentByContributor(r.Context(), contributor.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get contributor content: %v", err)
+	}
+
 	h.render(w, r, "ssg/contributors/show", PageData{
 		Title:       contributor.FullName(),
 		Site:        site,
 		Contributor: contributor,
+		Contents:    contents,
 	})
 }
 
+// formContributorRole returns the submitted role when it is one of
+// ContributorRoles, and current otherwise.
+func formContributorRole(value, current string) string {
+	value = strings.TrimSpace(value)
+	for _, role := range ContributorRoles {
+		if value == role {
+			return role
+		}
+	}
+	return current
+}
+
 func (h *Handler) HandleEditContributor(w http.ResponseWriter, r *http.Request) {
 	site := getSiteFromContext(r.Context())
 	if site == nil {
@@ -2892,7 +6070,7 @@ func (h *Handler) HandleEditContributor(w http.ResponseWriter, r *http.Request)
 	if contributor.ProfileID != nil {
 		contributorProfile, _ = h.profileService.GetProfile(r.Context(), *contributor.ProfileID)
 		if contributorProfile != nil {
-			socialLinksMap = parseSocialLinksToMap(contributorProfile.SocialLinks)
+			socialLinksMap = profile.SocialLinksMap(contributorProfile.SocialLinks)
 		}
 	}
 
@@ -2936,6 +6114,7 @@ func (h *Handler) HandleUpdateContributor(w http.ResponseWriter, r *http.Request
 	contributor.Name = r.FormValue("name")
 	contributor.Surname = r.FormValue("surname")
 	contributor.Bio = r.FormValue("bio")
+	contributor.Role = formContributorRole(r.FormValue("role"), contributor.Role)
 	contributor.UpdatedBy = parseUUID(userID)
 	contributor.UpdatedAt = time.Now()
 
@@ -2955,7 +6134,8 @@ func (h *Handler) HandleUpdateContributor(w http.ResponseWriter, r *http.Request
 	if profileSlug != "" && profileName != "" {
 		profileSurname := strings.TrimSpace(r.FormValue("profile_surname"))
 		profileBio := strings.TrimSpace(r.FormValue("profile_bio"))
-		socialLinks := buildSocialLinksJSON(r)
+		links, _ := profile.SocialLinksFromForm(r.Form, "profile_social_")
+		socialLinks := profile.EncodeSocialLinks(links)
 
 		if contributor.ProfileID != nil {
 			existingProfile, err := h.profileService.GetProfile(r.Context(), *contributor.ProfileID)
@@ -3043,7 +6223,8 @@ func (h *Handler) HandleEditContributorProfile(w http.ResponseWriter, r *http.Re
 		Site:               site,
 		Contributor:        contributor,
 		ContributorProfile: contributorProfile,
-		ProfileSocialLinks: parseSocialLinksToMap(contributorProfile.SocialLinks),
+		ProfileSocialLinks: profile.SocialLinksMap(contributorProfile.SocialLinks),
+		SocialPlatforms:    profile.SocialPlatforms,
 	})
 }
 
@@ -3089,16 +6270,32 @@ func (h *Handler) HandleUpdateContributorProfile(w http.ResponseWriter, r *http.
 	contributorProfile.Name = strings.TrimSpace(r.FormValue("name"))
 	contributorProfile.Surname = strings.TrimSpace(r.FormValue("surname"))
 	contributorProfile.Bio = strings.TrimSpace(r.FormValue("bio"))
-	contributorProfile.SocialLinks = buildSocialLinksJSON(r)
 	contributorProfile.UpdatedBy = userIDStr
 
+	links, socialErrs := profile.SocialLinksFromForm(r.Form, "social_")
+	if socialErrs != nil {
+		h.render(w, r, "ssg/contributors/edit-profile", PageData{
+			Title:              "Edit Profile: " + contributor.FullName(),
+			Site:               site,
+			Contributor:        contributor,
+			ContributorProfile: contributorProfile,
+			ProfileSocialLinks: profile.SocialFormValues(r.Form, "social_"),
+			SocialErrors:       socialErrs,
+			SocialPlatforms:    profile.SocialPlatforms,
+			Error:              "Some social links are not valid",
+		})
+		return
+	}
+	contributorProfile.SocialLinks = profile.EncodeSocialLinks(links)
+
 	if err := h.profileService.UpdateProfile(r.Context(), contributorProfile); err != nil {
 		h.render(w, r, "ssg/contributors/edit-profile", PageData{
 			Title:              "Edit Profile: " + contributor.FullName(),
 			Site:               site,
 			Contributor:        contributor,
 			ContributorProfile: contributorProfile,
-			ProfileSocialLinks: parseSocialLinksToMap(contributorProfile.SocialLinks),
+			ProfileSocialLinks: profile.SocialLinksMap(contributorProfile.SocialLinks),
+			SocialPlatforms:    profile.SocialPlatforms,
 			Error:              "Cannot update profile",
 		})
 		return
@@ -3151,6 +6348,20 @@ func (h *Handler) HandleUploadContributorPhoto(w http.ResponseWriter, r *http.Re
 	}
 	defer file.Close()
 
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Cannot read file", http.StatusBadRequest)
+		return
+	}
+
+	// The current photo stays until the new one is processed and saved.
+	photo, err := imaging.Avatar(data, h.cfg.SSG.PhotoSize)
+	if err != nil {
+		h.log.Errorf("Cannot process photo %s: %v", header.Filename, err)
+		http.Error(w, "The file is not a JPEG, PNG or GIF image", http.StatusBadRequest)
+		return
+	}
+
 	contributorsPhotoPath := filepath.Join(profilesBasePath, "contributors")
 	if err := os.MkdirAll(contributorsPhotoPath, 0755); err != nil {
 		h.log.Errorf("Cannot create profiles directory: %v", err)
@@ -3158,29 +6369,24 @@ func (h *Handler) HandleUploadContributorPhoto(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if contributorProfile.PhotoPath != "" {
-		oldPath := filepath.Join(profilesBasePath, contributorProfile.PhotoPath)
-		os.Remove(oldPath)
-	}
-
-	ext := filepath.Ext(header.Filename)
-	fileName := filepath.Join("contributors", contributorProfile.ID.String()+ext)
+	fileName := filepath.Join("contributors", contributorProfile.ID.String()+".jpg")
 	filePath := filepath.Join(profilesBasePath, fileName)
-
-	dst, err := os.Create(filePath)
-	if err != nil {
-		h.log.Errorf("Cannot create file: %v", err)
+	if err := os.WriteFile(filePath+".tmp", photo, 0644); err != nil {
+		h.log.Errorf("Cannot write file: %v", err)
 		http.Error(w, "Cannot save file", http.StatusInternalServerError)
 		return
 	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, file); err != nil {
+	if err := os.Rename(filePath+".tmp", filePath); err != nil {
+		os.Remove(filePath + ".tmp")
 		h.log.Errorf("Cannot write file: %v", err)
 		http.Error(w, "Cannot save file", http.StatusInternalServerError)
 		return
 	}
 
+	if contributorProfile.PhotoPath != "" && contributorProfile.PhotoPath != fileName {
+		os.Remove(filepath.Join(profilesBasePath, contributorProfile.PhotoPath))
+	}
+
 	userIDStr := middleware.GetUserID(r.Context())
 	contributorProfile.PhotoPath = fileName
 	contributorProfile.UpdatedBy = userIDStr
@@ -3266,8 +6472,15 @@ func (h *Handler) HandleBackupMarkdown(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loc, err := h.service.GetTimezone(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get site timezone: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot load settings")
+		return
+	}
+
 	// Generate markdown files
-	result, err := h.generator.GenerateMarkdown(r.Context(), site.Slug, contents)
+	result, err := h.generator.GenerateMarkdown(r.Context(), site.Slug, contents, loc)
 	if err != nil {
 		h.log.Errorf("Markdown generation failed: %v", err)
 		h.renderError(w, r, http.StatusInternalServerError, "Markdown generation failed")
@@ -3309,7 +6522,12 @@ func (h *Handler) HandleBackupMarkdown(w http.ResponseWriter, r *http.Request) {
 	repoURL, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.backup.repo.url")
 
 	if repoURL != nil && repoURL.Value != "" {
-		authToken, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.backup.auth.token")
+		authToken, err := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.backup.auth.token")
+		if isSecretError(err) {
+			h.log.Errorf("Markdown backup to git failed: %v", err)
+			http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&error="+gitErrorCode("backup", err), http.StatusSeeOther)
+			return
+		}
 		branch, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.backup.branch")
 		commitName, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.git.commit.user.name")
 		commitEmail, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.git.commit.user.email")
@@ -3336,6 +6554,7 @@ func (h *Handler) HandleBackupMarkdown(w http.ResponseWriter, r *http.Request) {
 			useSSH = false
 		}
 
+		settings, _ := h.service.GetSettings(r.Context(), site.ID)
 		cfg := PublishConfig{
 			RepoURL:     repoURL.Value,
 			Branch:      branchValue,
@@ -3343,12 +6562,14 @@ func (h *Handler) HandleBackupMarkdown(w http.ResponseWriter, r *http.Request) {
 			CommitName:  commitNameValue,
 			CommitEmail: commitEmailValue,
 			UseSSH:      useSSH,
+			SSH:         SSHOptionsFromSettings(settings),
+			Signing:     SigningFromSettings(settings),
 		}
 
 		backupResult, err := h.publisher.Backup(r.Context(), cfg, site.Slug)
 		if err != nil {
 			h.log.Errorf("Markdown backup to git failed: %v", err)
-			http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&error=backup_failed", http.StatusSeeOther)
+			http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&error="+gitErrorCode("backup", err), http.StatusSeeOther)
 			return
 		}
 
@@ -3423,6 +6644,11 @@ func (h *Handler) HandleGenerateHTML(w http.ResponseWriter, r *http.Request) {
 		layouts = []*Layout{}
 	}
 
+	kinds, err := h.service.ListContentKinds(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot get content kinds for HTML generation: %v", err)
+	}
+
 	params, err := h.service.GetSettings(r.Context(), site.ID)
 	if err != nil {
 		h.log.Errorf("Cannot get params for HTML generation: %v", err)
@@ -3437,180 +6663,199 @@ func (h *Handler) HandleGenerateHTML(w http.ResponseWriter, r *http.Request) {
 
 	userAuthors := h.service.BuildUserAuthorsMap(r.Context(), contents, contributors)
 
-	result, err := h.htmlGen.GenerateHTML(r.Context(), site, contents, sections, layouts, params, contributors, userAuthors)
+	force := r.FormValue("force") == "true"
+
+	result, err := h.htmlGen.GenerateHTML(r.Context(), site, contents, sections, layouts, kinds, params, contributors, userAuthors, force)
+	var conflicts *PathConflictError
+	if errors.As(err, &conflicts) {
+		h.log.Errorf("HTML generation failed: %v", err)
+		h.renderError(w, r, http.StatusConflict, "HTML generation failed: "+conflicts.Error())
+		return
+	}
 	if err != nil {
 		h.log.Errorf("HTML generation failed: %v", err)
 		h.renderError(w, r, http.StatusInternalServerError, "HTML generation failed")
 		return
 	}
 
-	h.log.Infof("HTML generation complete: %d pages, %d index pages, %d author pages", result.PagesGenerated, result.IndexPages, result.AuthorPages)
+	h.log.Infof("HTML generation complete: %d pages, %d index pages, %d tag pages, %d author pages, %d archive pages, %d paginated pages, %d feeds", result.PagesGenerated, result.IndexPages, result.TagPages, result.AuthorPages, result.ArchivePages, result.PaginatedPages, result.Feeds)
+	if result.Incremental {
+		h.log.Infof("Incremental build: %d pages rebuilt, %d unchanged pages skipped", result.PagesGenerated, result.PagesSkipped)
+	}
+	if len(result.AIFiles) > 0 {
+		h.log.Infof("Wrote %s", strings.Join(result.AIFiles, ", "))
+	}
+	if result.ChangelogEntries > 0 {
+		h.log.Infof("Changelog: %d entries", result.ChangelogEntries)
+	}
+	if result.OGImages > 0 {
+		h.log.Infof("Social images: %d written, %d drawn", result.OGImages, result.OGImagesDrawn)
+	}
+	h.log.Infof("Generation peaks: %d pages and %d social images at once, %d MB heap", result.PeakWorkers, result.PeakImageWorkers, result.PeakHeap>>20)
+	if result.BytesSaved > 0 {
+		h.log.Infof("Minification saved %d bytes", result.BytesSaved)
+	}
+	if result.FilesRemoved > 0 {
+		h.log.Infof("Removed %d files no longer generated", result.FilesRemoved)
+	}
+	if result.Accessibility != nil {
+		h.log.Infof("Accessibility lint: %d pages, %d errors, %d warnings", result.Accessibility.Pages, result.Accessibility.Errors(), result.Accessibility.Warnings())
+	}
 	if len(result.Errors) > 0 {
 		h.log.Infof("HTML generation had %d errors", len(result.Errors))
 	}
+	for _, warning := range result.Warnings {
+		h.log.Infof("HTML generation warning: %s", warning)
+	}
+
+	if err := h.service.MarkSiteGenerated(r.Context(), site.ID, time.Now()); err != nil {
+		h.log.Errorf("Cannot record generation time: %v", err)
+	}
 
 	// Redirect back to site with success message
 	http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&success=html", http.StatusSeeOther)
 }
 
-func (h *Handler) HandlePublish(w http.ResponseWriter, r *http.Request) {
-	site := getSiteFromContext(r.Context())
-	if site == nil {
-		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+// HandleDownloadBuild generates a site and streams its output as a .tar.gz,
+// for deploys like curl | tar xz and for offline copies.
+func (h *Handler) HandleDownloadBuild(w http.ResponseWriter, r *http.Request) {
+	siteID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid site ID")
 		return
 	}
 
-	contents, err := h.service.GetAllContentWithMeta(r.Context(), site.ID)
+	site, err := h.service.GetSite(r.Context(), siteID)
 	if err != nil {
-		h.log.Errorf("Cannot get content for publish: %v", err)
-		h.renderError(w, r, http.StatusInternalServerError, "Cannot load content")
+		h.log.Errorf("Cannot get site: %v", err)
+		h.renderError(w, r, http.StatusNotFound, "Site not found")
 		return
 	}
 
-	sections, err := h.service.GetSections(r.Context(), site.ID)
+	stream, err := h.service.GenerateSiteTar(r.Context(), site.ID)
 	if err != nil {
-		h.log.Errorf("Cannot get sections for publish: %v", err)
-		h.renderError(w, r, http.StatusInternalServerError, "Cannot load sections")
+		h.log.Errorf("Cannot build site %s for download: %v", site.Slug, err)
+		h.renderError(w, r, http.StatusInternalServerError, "HTML generation failed")
 		return
 	}
+	defer stream.Close()
 
-	layouts, err := h.service.GetLayouts(r.Context(), site.ID)
-	if err != nil {
-		h.log.Errorf("Cannot get layouts for publish: %v", err)
-		layouts = []*Layout{}
-	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.tar.gz"`, site.Slug))
 
-	params, err := h.service.GetSettings(r.Context(), site.ID)
-	if err != nil {
-		h.log.Errorf("Cannot get params for publish: %v", err)
-		params = []*Setting{}
+	// Once the body has started the status is sent, so a failure can only
+	// cut the archive short, which tar reports as truncated.
+	gz := gzip.NewWriter(w)
+	if _, err := io.Copy(gz, stream); err != nil {
+		h.log.Errorf("Cannot stream build of site %s: %v", site.Slug, err)
+		return
 	}
-
-	contributors, err := h.service.GetContributors(r.Context(), site.ID)
-	if err != nil {
-		h.log.Errorf("Cannot get contributors for publish: %v", err)
-		contributors = []*Contributor{}
+	if err := gz.Close(); err != nil {
+		h.log.Errorf("Cannot stream build of site %s: %v", site.Slug, err)
 	}
+}
 
-	userAuthors := h.service.BuildUserAuthorsMap(r.Context(), contents, contributors)
+// HandleAccessibilityReport lists the accessibility lint findings and feed
+// problems for the output of the last generation.
+func (h *Handler) HandleAccessibilityReport(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
 
-	htmlResult, err := h.htmlGen.GenerateHTML(r.Context(), site, contents, sections, layouts, params, contributors, userAuthors)
+	report, err := h.service.LintAccessibility(r.Context(), site.ID)
 	if err != nil {
-		h.log.Errorf("HTML generation failed: %v", err)
-		h.renderError(w, r, http.StatusInternalServerError, "HTML generation failed")
+		h.log.Errorf("Cannot lint accessibility: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot check accessibility")
 		return
 	}
-	h.log.Infof("HTML generation complete: %d pages", htmlResult.PagesGenerated)
-
-	repoURL, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.publish.repo.url")
 
-	if repoURL == nil || repoURL.Value == "" {
-		h.log.Error("Publish repo not configured")
-		http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&error=publish_not_configured", http.StatusSeeOther)
+	feeds, err := h.service.ValidateFeeds(r.Context(), site.ID)
+	if err != nil {
+		h.log.Errorf("Cannot validate feeds: %v", err)
+		h.renderError(w, r, http.StatusInternalServerError, "Cannot check feeds")
 		return
 	}
 
-	authToken, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.publish.auth.token")
-	branch, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.publish.branch")
-	commitName, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.git.commit.user.name")
-	commitEmail, _ := h.service.GetSettingByRefKey(r.Context(), site.ID, "ssg.git.commit.user.email")
+	h.render(w, r, "ssg/sites/a11y", PageData{
+		Title:      "Validation Report",
+		Site:       site,
+		A11yReport: report,
+		FeedReport: feeds,
+	})
+}
 
-	branchValue := "gh-pages"
-	if branch != nil && branch.Value != "" {
-		branchValue = branch.Value
+// HandlePublish queues a publish of the site and returns to the site page,
+// which shows the job's progress. While the site has a publish queued or
+// running, no other is queued and the site page shows that one.
+func (h *Handler) HandlePublish(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
 	}
-
-	commitNameValue := "Clio Bot"
-	if commitName != nil && commitName.Value != "" {
-		commitNameValue = commitName.Value
+	if h.publishQueue == nil {
+		h.renderError(w, r, http.StatusServiceUnavailable, "Publishing is not available")
+		return
 	}
 
-	commitEmailValue := "clio@localhost"
-	if commitEmail != nil && commitEmail.Value != "" {
-		commitEmailValue = commitEmail.Value
+	userID, _ := uuid.Parse(middleware.GetUserID(r.Context()))
+	job, err := h.publishQueue.Enqueue(r.Context(), site.ID, userID)
+	switch {
+	case errors.Is(err, ErrPublishQueued):
+		h.log.Infof("Publish of %s already queued as job %s", site.Slug, job.ID)
+	case err != nil:
+		h.log.Errorf("Cannot queue publish: %v", err)
+		http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&error=publish_failed", http.StatusSeeOther)
+		return
+	default:
+		h.log.Infof("Publish of %s queued as job %s", site.Slug, job.ID)
 	}
 
-	authTokenValue := ""
-	useSSH := true
-	if authToken != nil && authToken.Value != "" && strings.HasPrefix(repoURL.Value, "https://") {
-		authTokenValue = authToken.Value
-		useSSH = false
-	}
+	http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String(), http.StatusSeeOther)
+}
 
-	cfg := PublishConfig{
-		RepoURL:     repoURL.Value,
-		Branch:      branchValue,
-		AuthToken:   authTokenValue,
-		CommitName:  commitNameValue,
-		CommitEmail: commitEmailValue,
-		UseSSH:      useSSH,
+// HandlePublishStatus renders the status of a publish job. The site page
+// polls it until the job is done.
+func (h *Handler) HandlePublishStatus(w http.ResponseWriter, r *http.Request) {
+	site := getSiteFromContext(r.Context())
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
 	}
 
-	publishResult, err := h.publisher.Publish(r.Context(), cfg, site.Slug)
+	jobID, err := uuid.Parse(r.URL.Query().Get("job_id"))
 	if err != nil {
-		h.log.Errorf("Publish to git failed: %v", err)
-		http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&error=publish_failed", http.StatusSeeOther)
+		h.renderError(w, r, http.StatusBadRequest, "Invalid job ID")
 		return
 	}
 
-	if publishResult.NoChanges {
-		h.log.Info("Publish: no changes to commit")
-		http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&success=publish_no_changes", http.StatusSeeOther)
+	job, err := h.service.GetPublishJob(r.Context(), jobID)
+	if err != nil || job.SiteID != site.ID {
+		h.renderError(w, r, http.StatusNotFound, "Publish job not found")
 		return
 	}
 
-	site.LastPublishedAt = timePtr(time.Now())
-	_ = h.service.UpdateSite(r.Context(), site)
-
-	h.log.Infof("Publish complete: %s", publishResult.CommitURL)
-	http.Redirect(w, r, "/ssg/get-site?id="+site.ID.String()+"&success=publish", http.StatusSeeOther)
-}
-
-type profileSocialLink struct {
-	Platform string `json:"platform"`
-	URL      string `json:"url"`
-}
-
-func parseSocialLinksToMap(jsonStr string) map[string]string {
-	result := make(map[string]string)
-	if jsonStr == "" || jsonStr == "[]" {
-		return result
-	}
-	var links []profileSocialLink
-	if err := json.Unmarshal([]byte(jsonStr), &links); err != nil {
-		return result
-	}
-	for _, link := range links {
-		result[strings.ToLower(link.Platform)] = link.URL
-	}
-	return result
-}
-
-var socialPlatforms = []string{
-	"facebook", "youtube", "instagram", "x", "tiktok", "linkedin", "github",
-	"whatsapp", "telegram", "reddit", "messenger", "snapchat", "pinterest",
-	"tumblr", "discord", "twitch", "signal", "viber", "line", "kakaotalk",
-	"wechat", "qq", "douyin", "kuaishou", "weibo", "xiaohongshu", "bilibili",
-	"zhihu", "vk", "odnoklassniki", "mastodon", "bluesky", "threads", "flickr",
-	"vimeo", "dailymotion", "quora",
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(renderPublishStatus(job)))
 }
 
-func buildSocialLinksJSON(r *http.Request) string {
-	var links []profileSocialLink
-	for _, platform := range socialPlatforms {
-		url := strings.TrimSpace(r.FormValue("profile_social_" + platform))
-		if url != "" {
-			links = append(links, profileSocialLink{Platform: platform, URL: url})
-		}
-	}
-	if len(links) == 0 {
-		return "[]"
-	}
-	data, err := json.Marshal(links)
-	if err != nil {
-		return "[]"
-	}
-	return string(data)
+// publishStatusTmpl renders the status of a publish job. It polls while the
+// job is queued or running and stops once it is done.
+var publishStatusTmpl = template.Must(template.New("publishStatus").Parse(
+	`<div id="publish-status"{{ if not .Done }} hx-get="/ssg/publish-status?job_id={{ .ID }}&site_id={{ .SiteID }}" hx-trigger="every 2s" hx-swap="outerHTML"{{ end }}>` +
+		`{{ if eq .Status "queued" }}<div class="alert alert-warning"><strong>Publish queued.</strong> It starts when the publishes ahead of it finish.</div>` +
+		`{{ else if eq .Status "running" }}<div class="alert alert-warning"><strong>Publishing.</strong> {{ .Progress }}…</div>` +
+		`{{ else if eq .Status "succeeded" }}<div class="alert alert-success">` +
+		`{{ if .NoChanges }}No changes to publish.{{ else }}<strong>Site published.</strong>{{ with .CommitURL }} <a href="{{ . }}" target="_blank" rel="noopener">View commit</a>{{ end }}{{ end }}</div>` +
+		`{{ else }}<div class="alert alert-error"><strong>Publish failed.</strong> {{ .Error }}</div>{{ end }}` +
+		`</div>`))
+
+func renderPublishStatus(job *PublishJob) template.HTML {
+	var buf strings.Builder
+	_ = publishStatusTmpl.Execute(&buf, job)
+	return template.HTML(buf.String())
 }
 
 func normalizeSlug(s string) string {
@@ -3664,7 +6909,7 @@ func (h *Handler) HandleListImport(w http.ResponseWriter, r *http.Request) {
 		row := ImportRow{File: f}
 		if imp, exists := importedPaths[f.Path]; exists {
 			row.Import = imp
-			row.Status = ComputeImportStatus(imp, f.Mtime)
+			row.Status = ComputeImportStatus(imp, f)
 			imp.FileName = f.Name
 		} else {
 			row.Status = "new"
@@ -3704,6 +6949,8 @@ func (h *Handler) HandleListImport(w http.ResponseWriter, r *http.Request) {
 		ImportPath: importPath,
 		ImportType: importType,
 		HasMeta:    HasMetaDirectory(importPath),
+		Error:      r.URL.Query().Get("error"),
+		Success:    r.URL.Query().Get("success"),
 	}
 
 	h.render(w, r, "ssg/import/list", data)
@@ -3877,7 +7124,11 @@ func (h *Handler) HandleDoImport(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		_, err = h.service.ReimportFile(ctx, importID, true)
+		_, err = h.service.ReimportFile(ctx, importID, false)
+		if errors.Is(err, ErrImportConflict) {
+			importErrors = append(importErrors, fmt.Sprintf("Reimport skipped, review the conflict first: %v", err))
+			continue
+		}
 		if err != nil {
 			importErrors = append(importErrors, fmt.Sprintf("Reimport failed: %v", err))
 			continue
@@ -3895,7 +7146,11 @@ func (h *Handler) HandleDoImport(w http.ResponseWriter, r *http.Request) {
 	}
 	successMsg := strings.Join(msgs, ", ")
 
-	h.siteRedirect(w, r, fmt.Sprintf("/ssg/import/list?success=%s", successMsg))
+	redirect := "/ssg/import/list?success=" + url.QueryEscape(successMsg)
+	if len(importErrors) > 0 {
+		redirect += "&error=" + url.QueryEscape(strings.Join(importErrors, "; "))
+	}
+	h.siteRedirect(w, r, redirect)
 }
 
 func (h *Handler) HandleReimport(w http.ResponseWriter, r *http.Request) {
@@ -3921,14 +7176,48 @@ func (h *Handler) HandleReimport(w http.ResponseWriter, r *http.Request) {
 	force := r.FormValue("force") == "true"
 
 	_, err = h.service.ReimportFile(ctx, importID, force)
+	if errors.Is(err, ErrImportConflict) {
+		h.siteRedirect(w, r, "/ssg/import/diff?import_id="+importID.String())
+		return
+	}
 	if err != nil {
-		h.siteRedirect(w, r, "/ssg/import/list?error="+err.Error())
+		h.siteRedirect(w, r, "/ssg/import/list?error="+url.QueryEscape(err.Error()))
 		return
 	}
 
 	h.siteRedirect(w, r, "/ssg/import/list?success=File reimported successfully")
 }
 
+// HandleImportDiff shows what a reimport would change, so conflicting edits
+// can be reviewed before overwriting them.
+func (h *Handler) HandleImportDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	site := getSiteFromContext(ctx)
+	if site == nil {
+		h.renderError(w, r, http.StatusBadRequest, "Site context required")
+		return
+	}
+
+	importID, err := uuid.Parse(r.URL.Query().Get("import_id"))
+	if err != nil {
+		h.renderError(w, r, http.StatusBadRequest, "Invalid import ID")
+		return
+	}
+
+	diff, err := h.service.DiffImport(ctx, importID)
+	if err != nil {
+		h.log.Errorf("Cannot diff import: %v", err)
+		h.siteRedirect(w, r, "/ssg/import/list?error="+url.QueryEscape(err.Error()))
+		return
+	}
+
+	h.render(w, r, "ssg/import/diff", PageData{
+		Title:      "Review Reimport",
+		Site:       site,
+		ImportDiff: diff,
+	})
+}
+
 func (h *Handler) HandleShowRestore(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	site := getSiteFromContext(ctx)
@@ -4113,3 +7402,19 @@ func createImportDirectory(importPath string) error {
 	}
 	return os.MkdirAll(importPath, 0755)
 }
+
+// gitErrorCode maps a publish or backup failure to the error query code shown
+// on the site page, so auth problems get an actionable message.
+func gitErrorCode(op string, err error) string {
+	switch {
+	case errors.Is(err, git.ErrAuthFailed):
+		return op + "_auth_failed"
+	case errors.Is(err, git.ErrHostKey):
+		return op + "_host_key"
+	case errors.Is(err, git.ErrSigningFailed):
+		return op + "_signing_failed"
+	case isSecretError(err):
+		return op + "_secret"
+	}
+	return op + "_failed"
+}
@@ -0,0 +1,110 @@
+package ssg
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Home page settings.
+const (
+	// HomeModeRefKey chooses what the site root shows: the latest posts, as
+	// an index, or a single page picked with HomeContentRefKey.
+	HomeModeRefKey = "ssg.home.mode"
+	// HomeContentRefKey is the ID of the content the root shows in page mode.
+	HomeContentRefKey = "ssg.home.content_id"
+)
+
+// Home page modes.
+const (
+	HomeModePosts = "posts"
+	HomeModePage  = "page"
+)
+
+var ErrHomeContent = errors.New("home page content not found in this site")
+
+// homeContentID returns the ID of the content the root shows, or uuid.Nil
+// when the root lists the latest posts.
+func homeContentID(params map[string]string) uuid.UUID {
+	if strings.TrimSpace(params[HomeModeRefKey]) != HomeModePage {
+		return uuid.Nil
+	}
+	id, err := uuid.Parse(strings.TrimSpace(params[HomeContentRefKey]))
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// isHomeContent reports whether c is the page the root shows.
+func isHomeContent(c *Content, params map[string]string) bool {
+	id := homeContentID(params)
+	return id != uuid.Nil && c.ID == id
+}
+
+// homeContent returns the content the root shows in page mode. It is nil in
+// posts mode and when the page is not published, and the root then lists the
+// latest posts.
+func homeContent(params map[string]string, contents []*Content) *Content {
+	for _, c := range contents {
+		if isHomeContent(c, params) && isPublishable(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+// homeWarnings reports a home page that falls back to the latest posts
+// because its content cannot be shown.
+func homeWarnings(params map[string]string, contents []*Content) []string {
+	if strings.TrimSpace(params[HomeModeRefKey]) != HomeModePage || homeContent(params, contents) != nil {
+		return nil
+	}
+	return []string{"Home page mode is page, but its content is missing or not published. The home page lists the latest posts instead"}
+}
+
+// renderHomePage writes the home content to the site root, rendered as its
+// own page is, with the same layout. Both pages have the root as canonical
+// URL, see contentCanonicalURL.
+func (g *HTMLGenerator) renderHomePage(st sectionTemplate, build *buildState, htmlPath string, site *Site, home *Content, sections []*Section, menu []*Section, params map[string]string, allRendered []*RenderedContent, blocksCfg BlocksConfig) error {
+	var rendered *RenderedContent
+	var listed []*RenderedContent
+	for _, r := range allRendered {
+		if r.ID == home.ID {
+			rendered = r
+		}
+		if isListed(r.Content) {
+			listed = append(listed, r)
+		}
+	}
+	if rendered == nil {
+		return ErrHomeContent
+	}
+
+	adjacent := buildAdjacentIndex(allRendered, params)[home.ID]
+	data, blocks := g.contentPageData(st.layout, site, rendered, adjacent, sections, menu, params, listed, blocksCfg)
+	data.Feeds = g.feedLinks(params, "", site.Name)
+
+	outputPath := pageFile(params, htmlPath, g.workspace.GetPaginationHTMLPath(site.Slug, "", 1))
+	hash := hashInputs([]any{"home", contentPageHash(rendered, adjacent, blocks, data.Translations), data.Feeds})
+	if build.unchanged(outputPath, hash, home.UpdatedAt) {
+		return nil
+	}
+
+	if err := EnsureDir(outputPath); err != nil {
+		return err
+	}
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := g.writePage(f, st.tmpl, data); err != nil {
+		return err
+	}
+	build.record(outputPath, hash, home.UpdatedAt)
+	return nil
+}
@@ -0,0 +1,124 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateHTMLHomeMode(t *testing.T) {
+	site := &Site{ID: uuid.New(), Name: "Studio", Slug: "studio"}
+	day := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	main := &Section{ID: uuid.New(), SiteID: site.ID, Name: "Main", Path: ""}
+	blog := &Section{ID: uuid.New(), SiteID: site.ID, Name: "Blog", Path: "blog"}
+	landing := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: main.ID, ShortID: "land1234", Kind: "page",
+		Heading: "Welcome to the studio", Body: "We design things.", PublishedAt: &day, UpdatedAt: day}
+	post := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: blog.ID, SectionPath: "blog", ShortID: "post1234", Kind: "post",
+		Heading: "Latest news", Body: "Something happened.", PublishedAt: &day, UpdatedAt: day}
+
+	generate := func(t *testing.T, params []*Setting) (string, *GenerateHTMLResult) {
+		t.Helper()
+		g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+		result, err := g.GenerateHTML(context.Background(), site, []*Content{landing, post}, []*Section{main, blog}, nil, nil, params, nil, nil, true)
+		if err != nil {
+			t.Fatalf("GenerateHTML() error = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(g.workspace.GetHTMLPath(site.Slug), "index.html"))
+		if err != nil {
+			t.Fatalf("root index.html: %v", err)
+		}
+		return string(data), result
+	}
+
+	t.Run("posts", func(t *testing.T) {
+		root, _ := generate(t, []*Setting{{RefKey: HomeModeRefKey, Value: HomeModePosts}, {RefKey: HomeContentRefKey, Value: landing.ID.String()}})
+		if !strings.Contains(root, "Latest news") {
+			t.Errorf("root index.html does not list the latest posts:\n%s", root)
+		}
+		if strings.Contains(root, "We design things.") {
+			t.Errorf("root index.html shows the landing page in posts mode")
+		}
+	})
+
+	t.Run("page", func(t *testing.T) {
+		params := []*Setting{
+			{RefKey: HomeModeRefKey, Value: HomeModePage},
+			{RefKey: HomeContentRefKey, Value: landing.ID.String()},
+			{RefKey: BaseURLRefKey, Value: "https://studio.example"},
+		}
+		root, result := generate(t, params)
+		if !strings.Contains(root, "Welcome to the studio") || !strings.Contains(root, "We design things.") {
+			t.Errorf("root index.html does not show the landing page:\n%s", root)
+		}
+		if strings.Contains(root, "Latest news") {
+			t.Errorf("root index.html lists the latest posts in page mode")
+		}
+		if !strings.Contains(root, `<link rel="canonical" href="https://studio.example/">`) {
+			t.Errorf("root index.html canonical is not the site root:\n%s", root)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("warnings = %v, want none", result.Warnings)
+		}
+	})
+
+	t.Run("page without published content", func(t *testing.T) {
+		root, result := generate(t, []*Setting{{RefKey: HomeModeRefKey, Value: HomeModePage}, {RefKey: HomeContentRefKey, Value: uuid.NewString()}})
+		if !strings.Contains(root, "Latest news") {
+			t.Errorf("root index.html does not fall back to the latest posts:\n%s", root)
+		}
+		if len(result.Warnings) == 0 || !strings.HasPrefix(result.Warnings[0], "Home page mode is page") {
+			t.Errorf("warnings = %v, want the home page fallback", result.Warnings)
+		}
+	})
+}
+
+func TestServiceHomePageSettings(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Home", "home")
+	other := createTestSite(t, svc, "Other", "other")
+	section := NewSection(site.ID, "Pages", "", "pages")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatal(err)
+	}
+	landing := NewContent(site.ID, section.ID, "Landing", "Hello")
+	if err := svc.CreateContent(ctx, landing); err != nil {
+		t.Fatal(err)
+	}
+
+	mode := NewSetting(site.ID, "Home page", HomeModePage)
+	mode.RefKey = HomeModeRefKey
+	if err := svc.CreateSetting(ctx, mode); !errors.Is(err, ErrHomeContent) {
+		t.Errorf("page mode without content error = %v, want ErrHomeContent", err)
+	}
+
+	for _, value := range []string{"landing", uuid.NewString()} {
+		content := NewSetting(other.ID, "Home page content", value)
+		content.RefKey = HomeContentRefKey
+		if err := svc.CreateSetting(ctx, content); !errors.Is(err, ErrHomeContent) {
+			t.Errorf("home page content %q error = %v, want ErrHomeContent", value, err)
+		}
+	}
+	foreign := NewSetting(other.ID, "Home page content", landing.ID.String())
+	foreign.RefKey = HomeContentRefKey
+	if err := svc.CreateSetting(ctx, foreign); !errors.Is(err, ErrHomeContent) {
+		t.Errorf("content of another site error = %v, want ErrHomeContent", err)
+	}
+
+	content := NewSetting(site.ID, "Home page content", landing.ID.String())
+	content.RefKey = HomeContentRefKey
+	if err := svc.CreateSetting(ctx, content); err != nil {
+		t.Fatalf("CreateSetting() home page content error = %v", err)
+	}
+	if err := svc.CreateSetting(ctx, mode); err != nil {
+		t.Errorf("CreateSetting() page mode error = %v", err)
+	}
+}
@@ -7,33 +7,53 @@ import (
 	"fmt"
 	"html/template"
 	"io/fs"
-	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cliossg/clio/pkg/cl/metrics"
+	"github.com/cliossg/clio/pkg/cl/render"
 	"github.com/google/uuid"
 )
 
 // isPublishable returns true if the content should be included in the generated site.
 // Content is excluded if it is a draft or if its PublishedAt date is in the future.
 func isPublishable(c *Content) bool {
-	if c.Draft {
-		return false
-	}
-	if c.PublishedAt != nil && c.PublishedAt.After(time.Now()) {
-		return false
-	}
-	return true
+	return c.Visibility != VisibilityPrivate && c.Status(time.Now()) == ContentStatusPublished
+}
+
+// isListed returns true if publishable content also shows up in listings,
+// feeds, related blocks and the sitemap. Unlisted content is only reachable
+// through its URL.
+func isListed(c *Content) bool {
+	return isPublishable(c) && c.Visibility != VisibilityUnlisted
 }
 
 // HTMLGenerator handles static site generation.
 type HTMLGenerator struct {
 	workspace *Workspace
 	processor *Processor
-	assetsFS  embed.FS
+	assetsFS  fs.FS
+	workers   atomic.Int32
+	timezone  string
+
+	// Generation limits, see limits.go.
+	imageWorkers atomic.Int32
+	pageBuffer   atomic.Int64
+	memoryBudget atomic.Uint64
+
+	// Collectors, see metrics.go. Nil when metrics are off.
+	generationTime *metrics.Histogram
+	pagesWritten   *metrics.Counter
+
+	staticMu        sync.Mutex
+	staticManifests map[string]AssetManifest // by theme
 }
 
 // NewHTMLGenerator creates a new HTML generator.
@@ -45,6 +65,26 @@ func NewHTMLGenerator(workspace *Workspace, assetsFS embed.FS) *HTMLGenerator {
 	}
 }
 
+// SetWorkers sets how many content pages are rendered in parallel.
+// Zero or less uses GOMAXPROCS; one renders sequentially, which is handy for debugging.
+// It is safe to call while a generation is running, e.g. on config reload.
+func (g *HTMLGenerator) SetWorkers(n int) {
+	g.workers.Store(int32(n))
+}
+
+// SetTimezone sets the IANA timezone used for sites without their own
+// ssg.site.timezone setting. Call it before generating.
+func (g *HTMLGenerator) SetTimezone(name string) {
+	g.timezone = name
+}
+
+func (g *HTMLGenerator) workerCount() int {
+	if n := g.workers.Load(); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
 // SSGPageData holds data for rendering a page.
 type SSGPageData struct {
 	Site              *Site
@@ -54,10 +94,21 @@ type SSGPageData struct {
 	Sections          []*Section
 	Menu              []*Section
 	Author            *Contributor
+	AuthorGroups      []*AuthorGroup    // authors index, grouped by role
+	Feeds             []FeedLink        // feeds of this listing, linked with rel="alternate"
+	Translations      []TranslationLink // language versions of the page, linked with hreflang
+	IndexLang         string            // language of a per-language index, see Lang
 	Blocks            *GeneratedBlocks
+	NewerContent      *RenderedContent
+	OlderContent      *RenderedContent
 	IsIndex           bool
 	IsAuthor          bool
 	IsSearch          bool
+	IsTag             bool
+	Tag               *Tag
+	IsArchive         bool
+	Archive           []*ArchivePeriod // archive index, by year
+	ArchivePeriod     *ArchivePeriod   // year or month of an archive listing
 	IsPaginated       bool
 	CurrentPage       int
 	TotalPages        int
@@ -65,9 +116,19 @@ type SSGPageData struct {
 	HasNext           bool
 	PrevURL           string
 	NextURL           string
+	FirstURL          string
+	LastURL           string
+	CanonicalURL      string
+	SocialImage       string         // absolute og:image of content and tag pages, see socialImageURL
+	UpdatedAt         *time.Time     // last update shown on content pages, see updatedDate
+	Fields            map[string]any // custom fields of the content page, see ContentField
 	AssetPath         string
 	Params            map[string]string
+	Timezone          string // IANA name, for formatInTZ
+	DateFormat        string // ssg.date.format, for siteDate
 	CustomCSS         string
+	CustomCSSPath     string        // fingerprinted file for CustomCSS, linked instead of inlining it
+	Assets            AssetManifest // fingerprinted names of bundled assets, see AssetManifest.Path
 	ExcludeDefaultCSS bool
 }
 
@@ -76,33 +137,98 @@ type RenderedContent struct {
 	*Content
 	HTMLBody template.HTML
 	URL      string
+	OGImage  string // generated social image, relative to the site root
 }
 
 // GenerateHTMLResult contains the result of HTML generation.
 type GenerateHTMLResult struct {
-	TotalContent   int
-	PagesGenerated int
-	IndexPages     int
-	AuthorPages    int
-	Errors         []string
+	TotalContent     int
+	PagesGenerated   int
+	IndexPages       int
+	AuthorPages      int
+	TagPages         int
+	ArchivePages     int // year and month listings, when ssg.archive is on
+	PaginatedPages   int
+	PagesSkipped     int
+	RedirectPages    int
+	Feeds            int         // feed files, one per listing and format
+	AIFiles          []string    // llms.txt and ai.txt, when enabled
+	ChangelogEntries int         // in changelog.json, when ssg.changelog.section is set
+	OGImages         int         // social images of content without a header image
+	OGImagesDrawn    int         // of those, drawn anew rather than taken from the cache
+	BytesSaved       int64       // by minification, when ssg.minify is on
+	FilesRemoved     int         // previous output no longer generated
+	Accessibility    *A11yReport // when ssg.a11y.lint is on
+	PublishBlocked   bool        // accessibility errors with ssg.a11y.block_publish on
+	Incremental      bool
+	PeakWorkers      int    // most content pages rendered at once
+	PeakImageWorkers int    // most social images drawn at once
+	PeakHeap         uint64 // largest heap sampled during generation, in bytes
+	Errors           []string
+	Warnings         []string
 }
 
 // GenerateHTML generates the static HTML site.
-func (g *HTMLGenerator) GenerateHTML(ctx context.Context, site *Site, contents []*Content, sections []*Section, layouts []*Layout, params []*Setting, contributors []*Contributor, userAuthors map[string]*Contributor) (*GenerateHTMLResult, error) {
+// Unless force is set, pages whose inputs match the previous build manifest are
+// kept as they are; a change to any shared input (layouts, params, sections,
+// contributors, templates) rebuilds everything. Content resolving to the same
+// path fails the build with a *PathConflictError before any file is written.
+func (g *HTMLGenerator) GenerateHTML(ctx context.Context, site *Site, contents []*Content, sections []*Section, layouts []*Layout, kinds []*ContentKind, params []*Setting, contributors []*Contributor, userAuthors map[string]*Contributor, force bool) (*GenerateHTMLResult, error) {
+	start := time.Now()
 	result := &GenerateHTMLResult{
 		TotalContent: len(contents),
 	}
+	memory := watchMemory()
+	defer memory.finish()
 
 	htmlPath := g.workspace.GetHTMLPath(site.Slug)
 
-	// Best-effort cleanup and copy - don't fail on these, regeneration overwrites
-	_ = CleanDir(htmlPath)
-	_ = g.copyStaticAssets(htmlPath)
-	_ = g.copyUserImages(site.Slug, htmlPath)
+	paramsMap := withDefaultTimezone(params, g.timezone)
+	contents, result.Warnings = withoutUnapproved(contents, paramsMap)
+	applyContentKinds(contents, kinds)
+	applySummaries(contents, paramsMap)
+
+	// Pages sharing a path would overwrite each other, so check before
+	// anything is written.
+	var pages []*Content
+	for _, content := range contents {
+		if isPublishable(content) {
+			pages = append(pages, content)
+		}
+	}
+	permalinks, err := assignPermalinks(pages, paramsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	theme := g.siteTheme(site.Slug, paramsMap)
+	if theme.Uploaded {
+		// Uploaded themes can change between builds.
+		g.forgetStaticAssets(theme)
+	}
+
+	manifestPath := g.workspace.GetBuildManifestPath(site.Slug)
+	globalHash := g.globalBuildHash(site, sections, layouts, kinds, paramsMap, contributors, userAuthors, theme)
+	build := newBuildState(htmlPath, loadBuildManifest(manifestPath), globalHash, force)
+	result.Incremental = build.incremental
+
+	// Best-effort copy - don't fail on these, regeneration overwrites
+	if !build.incremental && outputCleanEnabled(paramsMap) {
+		if err := build.clean(g.workspace.GetImagesPath(site.Slug)); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("clean output: %v", err))
+		}
+	}
+	_ = g.copyStaticAssets(build, htmlPath, theme)
+	_ = g.copyUserImages(build, site.Slug, htmlPath)
+	if fingerprintEnabled(paramsMap) {
+		if err := g.writeFingerprintedAssets(build, htmlPath, g.workspace.GetAssetManifestPath(site.Slug), layouts, theme); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("fingerprinted assets: %v", err))
+		}
+	}
 
-	embeddedTmpl, err := g.parseTemplates()
+	embeddedTmpl, err := g.parseTemplates(theme)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse templates: %w", err)
+		return nil, fmt.Errorf("failed to parse templates of theme %s: %w", theme.Name, err)
 	}
 
 	// Build layout lookup map by section ID
@@ -121,93 +247,220 @@ func (g *HTMLGenerator) GenerateHTML(ctx context.Context, site *Site, contents [
 
 	menu := g.buildMenu(sections)
 
-	paramsMap := make(map[string]string)
-	for _, p := range params {
-		paramsMap[p.RefKey] = p.Value
-	}
-
 	basePath := g.getAssetPath(paramsMap)
 	allRendered := g.preRenderAllContent(contents, basePath, paramsMap)
 
-	blocksCfg := BlocksConfig{
-		Enabled:      paramsMap["ssg.blocks.enabled"] != "false",
-		MultiSection: paramsMap["ssg.blocks.multisection"] != "false",
-		MaxItems:     5,
-	}
-	if v, ok := paramsMap["ssg.blocks.maxitems"]; ok {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			blocksCfg.MaxItems = n
-		}
-	}
+	blocksCfg := getBlocksConfig(paramsMap)
 
-	for _, content := range contents {
-		if !isPublishable(content) {
-			continue
-		}
+	linkTranslations(pages)
 
-		if err := g.renderContentPage(embeddedTmpl, layoutsBySection, siteDefaultLayout, htmlPath, site, content, sections, menu, paramsMap, allRendered, blocksCfg); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("content %s: %v", content.Heading, err))
-			continue
+	ogStats, ogWarnings := g.generateOGImages(build, htmlPath, site, allRendered, paramsMap)
+	result.OGImages = ogStats.Written
+	result.OGImagesDrawn = ogStats.Drawn
+	result.Warnings = append(result.Warnings, ogWarnings...)
+
+	templates := g.resolveSectionTemplates(embeddedTmpl, layoutsBySection, siteDefaultLayout, pages)
+	kindTemplates := g.resolveKindTemplates(embeddedTmpl, kinds, layouts)
+	contentTemplates := g.resolveContentTemplates(embeddedTmpl, pages, layouts)
+	pagesGenerated, pageErrors := g.renderContentPages(templates, kindTemplates, contentTemplates, build, htmlPath, site, pages, sections, menu, paramsMap, allRendered, blocksCfg)
+	result.PagesGenerated = pagesGenerated
+	result.Errors = append(result.Errors, pageErrors...)
+
+	if home := homeContent(paramsMap, pages); home != nil {
+		st := contentTemplate(templates, kindTemplates, contentTemplates, home)
+		if err := g.renderHomePage(st, build, htmlPath, site, home, sections, menu, paramsMap, allRendered, blocksCfg); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("home page: %v", err))
 		}
-		result.PagesGenerated++
 	}
+	result.Warnings = append(result.Warnings, homeWarnings(paramsMap, pages)...)
 
-	indexCount, err := g.renderIndexPages(embeddedTmpl, layoutsBySection, siteDefaultLayout, htmlPath, site, contents, sections, menu, paramsMap)
+	indexCount, indexPaged, err := g.renderIndexPages(embeddedTmpl, layoutsBySection, siteDefaultLayout, build, htmlPath, site, contents, sections, menu, paramsMap)
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("index pages: %v", err))
 	}
 	result.IndexPages = indexCount
+	result.PaginatedPages += indexPaged
 
-	authorCount, err := g.renderAuthorPages(embeddedTmpl, siteDefaultLayout, htmlPath, site, contents, contributors, userAuthors, menu, paramsMap)
+	tagCount, tagPaged, err := g.renderTagPages(embeddedTmpl, siteDefaultLayout, build, site, contents, sections, menu, paramsMap)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("tag pages: %v", err))
+	}
+	result.TagPages = tagCount
+	result.PaginatedPages += tagPaged
+
+	authorCount, authorPaged, err := g.renderAuthorPages(embeddedTmpl, siteDefaultLayout, build, htmlPath, site, contents, contributors, userAuthors, menu, paramsMap)
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("author pages: %v", err))
 	}
 	result.AuthorPages = authorCount
+	result.PaginatedPages += authorPaged
 
-	if paramsMap["ssg.search.google.enabled"] == "true" && paramsMap["ssg.search.google.id"] != "" {
+	archiveCount, archivePaged, err := g.renderArchivePages(embeddedTmpl, siteDefaultLayout, build, htmlPath, site, contents, menu, paramsMap)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("archive pages: %v", err))
+	}
+	result.ArchivePages = archiveCount
+	result.PaginatedPages += archivePaged
+
+	if searchPageEnabled(paramsMap) {
 		if err := g.generateSearchPage(embeddedTmpl, siteDefaultLayout, htmlPath, site, menu, paramsMap); err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("search page: %v", err))
+		} else {
+			build.record(pageFile(paramsMap, htmlPath, filepath.Join(htmlPath, "search", "index.html")), "", time.Time{})
 		}
 	}
 
-	if err := g.copyProfilePhotos(htmlPath, contributors, userAuthors); err != nil {
+	if err := g.copyProfilePhotos(build, htmlPath, contributors, userAuthors); err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("profile photos: %v", err))
 	}
 
-	if baseURL, ok := paramsMap["ssg.site.base_url"]; ok && baseURL != "" {
-		if err := g.generateSitemap(htmlPath, baseURL, basePath, site, contents, sections); err != nil {
+	baseURL := siteBaseURL(paramsMap)
+	result.Warnings = append(result.Warnings, baseURLWarnings(paramsMap)...)
+	result.Warnings = append(result.Warnings, noIndexWarning(paramsMap)...)
+	if baseURL != "" {
+		sitemaps, err := g.generateSitemap(htmlPath, baseURL, basePath, site, contents, sections, paramsMap)
+		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("sitemap: %v", err))
 		}
-		if err := g.generateCNAME(htmlPath, baseURL); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("CNAME: %v", err))
+		for _, name := range sitemaps {
+			build.record(filepath.Join(htmlPath, name), "", time.Time{})
 		}
 	}
+	feedCount, err := g.generateFeeds(build, htmlPath, site, contents, sections, paramsMap)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("feeds: %v", err))
+	}
+	result.Feeds = feedCount
+	if feedCount > 0 {
+		feedReport, err := validateFeeds(htmlPath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("feed check: %v", err))
+		}
+		for _, p := range feedReport.Problems {
+			result.Errors = append(result.Errors, fmt.Sprintf("feed %s: %s", p.Feed, p.Message))
+		}
+	}
+	if err := g.generateCNAME(htmlPath, cnameDomain(paramsMap)); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("CNAME: %v", err))
+	} else if cnameDomain(paramsMap) != "" {
+		build.record(filepath.Join(htmlPath, "CNAME"), "", time.Time{})
+	}
 
-	if robotsTxt, ok := paramsMap["ssg.robots.txt"]; ok && robotsTxt != "" {
+	if siteNoIndex(paramsMap) {
+		if err := g.generateRobotsTxt(htmlPath, disallowAllRobotsTxt, ""); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("robots.txt: %v", err))
+		} else {
+			build.record(filepath.Join(htmlPath, "robots.txt"), "", time.Time{})
+		}
+	} else if robotsTxt, ok := paramsMap["ssg.robots.txt"]; ok && robotsTxt != "" {
 		sitemapURL := ""
-		if baseURL, ok := paramsMap["ssg.site.base_url"]; ok && baseURL != "" {
-			sitemapURL = strings.TrimRight(baseURL, "/") + basePath + "sitemap.xml"
+		if baseURL != "" {
+			sitemapURL = baseURL + basePath + "sitemap.xml"
 		}
 		if err := g.generateRobotsTxt(htmlPath, robotsTxt, sitemapURL); err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("robots.txt: %v", err))
+		} else {
+			build.record(filepath.Join(htmlPath, "robots.txt"), "", time.Time{})
+		}
+	}
+
+	aiFiles, err := g.generateLLMsTxt(htmlPath, baseURL, basePath, site, contents, sections, paramsMap)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("llms.txt: %v", err))
+	}
+	for _, name := range aiFiles {
+		build.record(filepath.Join(htmlPath, name), "", time.Time{})
+	}
+	result.AIFiles = aiFiles
+
+	changelogEntries, err := g.generateChangelog(htmlPath, baseURL, basePath, contents, sections, paramsMap)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", changelogFile, err))
+	} else if changelogSection(paramsMap, sections) != nil {
+		build.record(filepath.Join(htmlPath, changelogFile), "", time.Time{})
+	}
+	result.ChangelogEntries = changelogEntries
+
+	redirectPages, err := g.writeRedirects(build, htmlPath, permalinks, paramsMap)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("redirects: %v", err))
+	}
+	slashPages, err := g.writeSlashRedirects(build, htmlPath, paramsMap)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("trailing slash redirects: %v", err))
+	}
+	result.RedirectPages = redirectPages + slashPages
+	if err := g.writeRedirectsFile(build, htmlPath, g.redirectRules(build, pages, permalinks, paramsMap), paramsMap); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", redirectsFile, err))
+	}
+
+	if paramsMap[MinifyRefKey] == "true" {
+		saved, err := minifyOutput(htmlPath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("minify: %v", err))
 		}
+		result.BytesSaved = saved
 	}
 
+	result.FilesRemoved = build.removeStale()
+	if err := saveBuildManifest(manifestPath, build.manifest(globalHash)); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("build manifest: %v", err))
+	}
+
+	if paramsMap[A11yLintRefKey] == "true" {
+		report, err := lintAccessibility(htmlPath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("accessibility lint: %v", err))
+		}
+		result.Accessibility = report
+		result.PublishBlocked = paramsMap[A11yBlockPublishRefKey] == "true" && report.Errors() > 0
+	}
+	result.PagesSkipped = int(build.skipped.Load())
+	result.PeakWorkers = build.pageWorkers.highWater()
+	result.PeakImageWorkers = build.imageWorkers.highWater()
+	result.PeakHeap = memory.finish()
+	result.Warnings = append(result.Warnings, memoryBudgetWarning(result.PeakHeap, g.memoryBudget.Load())...)
+	g.recordGeneration(site, result, time.Since(start))
+
 	return result, nil
 }
 
-// parseTemplates parses the SSG templates from embedded filesystem.
-func (g *HTMLGenerator) parseTemplates() (*template.Template, error) {
-	funcMap := template.FuncMap{
-		"safeHTML": func(s string) template.HTML { return template.HTML(s) },
-		"add":      func(a, b int) int { return a + b },
-		"subtract": func(a, b int) int { return a - b },
-		"now":      func() time.Time { return time.Now() },
+// getBlocksConfig reads the related/series blocks settings.
+func getBlocksConfig(params map[string]string) BlocksConfig {
+	cfg := BlocksConfig{
+		Enabled:      params["ssg.blocks.enabled"] != "false",
+		MultiSection: params["ssg.blocks.multisection"] != "false",
+		MaxItems:     5,
+	}
+	if v, ok := params["ssg.blocks.maxitems"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.MaxItems = n
+		}
 	}
+	return cfg
+}
+
+// templateFuncMap returns the functions available to site templates: the
+// shared render helpers plus the site-only ones below. Custom layouts are code
+// supplied through the admin UI and run in the server process, so only pure
+// helpers belong here: nothing that reads files, the environment or the network.
+func templateFuncMap() template.FuncMap {
+	return render.MergeFuncMaps(render.Helpers(), template.FuncMap{
+		"safeHTML":   func(s string) template.HTML { return template.HTML(s) },
+		"safeCSS":    func(s string) template.CSS { return template.CSS(s) },
+		"subtract":   func(a, b int) int { return a - b },
+		"now":        func() time.Time { return time.Now() },
+		"formatInTZ": formatInTZ,
+		"siteDate":   siteDate,
+		"roleTitle":  RoleTitle,
+	})
+}
 
-	tmpl, err := template.New("").Funcs(funcMap).ParseFS(g.assetsFS,
-		"assets/ssg/layout.html",
-		"assets/ssg/partials/*.html",
+// parseTemplates parses the SSG templates of a theme.
+func (g *HTMLGenerator) parseTemplates(theme *Theme) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(templateFuncMap()).ParseFS(theme.fsys,
+		"layout.html",
+		"partials/*.html",
 	)
 	if err != nil {
 		return nil, err
@@ -216,21 +469,21 @@ func (g *HTMLGenerator) parseTemplates() (*template.Template, error) {
 	return tmpl, nil
 }
 
-// copyStaticAssets copies static assets to the output directory.
-func (g *HTMLGenerator) copyStaticAssets(htmlPath string) error {
+// copyStaticAssets copies the static assets of a theme to the output directory.
+func (g *HTMLGenerator) copyStaticAssets(build *buildState, htmlPath string, theme *Theme) error {
 	staticPath := filepath.Join(htmlPath, "static")
 	if err := os.MkdirAll(staticPath, 0755); err != nil {
 		return err
 	}
 
-	// Walk embedded static assets
-	return fs.WalkDir(g.assetsFS, "assets/ssg/static", func(path string, d fs.DirEntry, err error) error {
+	// Walk the theme's static assets
+	return fs.WalkDir(theme.fsys, "static", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Calculate relative path from assets/ssg/static
-		relPath, _ := filepath.Rel("assets/ssg/static", path)
+		// Calculate relative path from static
+		relPath, _ := filepath.Rel("static", path)
 		destPath := filepath.Join(staticPath, relPath)
 
 		if d.IsDir() {
@@ -238,16 +491,20 @@ func (g *HTMLGenerator) copyStaticAssets(htmlPath string) error {
 		}
 
 		// Copy file
-		data, err := g.assetsFS.ReadFile(path)
+		data, err := fs.ReadFile(theme.fsys, path)
 		if err != nil {
 			return err
 		}
 
-		return os.WriteFile(destPath, data, 0644)
+		if err := os.WriteFile(destPath, data, 0644); err != nil {
+			return err
+		}
+		build.record(destPath, "", time.Time{})
+		return nil
 	})
 }
 
-func (g *HTMLGenerator) copyUserImages(siteSlug, htmlPath string) error {
+func (g *HTMLGenerator) copyUserImages(build *buildState, siteSlug, htmlPath string) error {
 	srcPath := g.workspace.GetImagesPath(siteSlug)
 	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
 		return nil
@@ -281,12 +538,13 @@ func (g *HTMLGenerator) copyUserImages(siteSlug, htmlPath string) error {
 		if err := os.WriteFile(dstFile, data, 0644); err != nil {
 			return err
 		}
+		build.record(dstFile, "", time.Time{})
 	}
 
 	return nil
 }
 
-func (g *HTMLGenerator) copyProfilePhotos(htmlPath string, contributors []*Contributor, userAuthors map[string]*Contributor) error {
+func (g *HTMLGenerator) copyProfilePhotos(build *buildState, htmlPath string, contributors []*Contributor, userAuthors map[string]*Contributor) error {
 	profilesPath := filepath.Join(htmlPath, "profiles")
 	if err := os.MkdirAll(profilesPath, 0755); err != nil {
 		return err
@@ -310,6 +568,7 @@ func (g *HTMLGenerator) copyProfilePhotos(htmlPath string, contributors []*Contr
 		if err := os.WriteFile(dstPath, data, 0644); err != nil {
 			return err
 		}
+		build.record(dstPath, "", time.Time{})
 		copied[c.PhotoPath] = true
 	}
 
@@ -329,6 +588,7 @@ func (g *HTMLGenerator) copyProfilePhotos(htmlPath string, contributors []*Contr
 		if err := os.WriteFile(dstPath, data, 0644); err != nil {
 			return err
 		}
+		build.record(dstPath, "", time.Time{})
 		copied[u.PhotoPath] = true
 	}
 
@@ -374,32 +634,47 @@ func (g *HTMLGenerator) getTemplateForSection(embeddedTmpl *template.Template, l
 }
 
 // getTemplateAndLayoutForSection returns the template and the resolved layout for a section.
+// Layouts without code, or whose code does not parse, render with the embedded templates.
 func (g *HTMLGenerator) getTemplateAndLayoutForSection(embeddedTmpl *template.Template, layoutsBySection map[uuid.UUID]*Layout, siteDefaultLayout *Layout, sectionID uuid.UUID) (*template.Template, *Layout) {
-	layout, ok := layoutsBySection[sectionID]
-	if !ok || layout == nil || layout.Code == "" {
-		layout = siteDefaultLayout
-	}
-	if layout == nil || layout.Code == "" {
-		return embeddedTmpl, layout
+	layout := resolveLayout(layoutsBySection[sectionID], siteDefaultLayout)
+	return g.templateForLayout(embeddedTmpl, layout), layout
+}
+
+// templateForLayout returns the parsed code of layout, or the embedded
+// templates when it has none or it does not parse.
+func (g *HTMLGenerator) templateForLayout(embeddedTmpl *template.Template, layout *Layout) *template.Template {
+	if layout.Code == "" {
+		return embeddedTmpl
 	}
 
 	customTmpl, err := g.parseCustomLayout(layout.Code)
 	if err != nil {
-		return embeddedTmpl, layout
+		return embeddedTmpl
+	}
+	return customTmpl
+}
+
+// resolveLayout returns the effective layout for a section.
+// Resolution order: section layout → site default layout → built-in layout.
+// It never returns nil.
+func resolveLayout(sectionLayout, siteDefaultLayout *Layout) *Layout {
+	if sectionLayout.isSet() {
+		return sectionLayout
+	}
+	if siteDefaultLayout.isSet() {
+		return siteDefaultLayout
 	}
-	return customTmpl, layout
+	return BuiltinLayout()
 }
 
 // parseCustomLayout parses a custom layout code string into a template.
 func (g *HTMLGenerator) parseCustomLayout(code string) (*template.Template, error) {
-	funcMap := template.FuncMap{
-		"safeHTML": func(s string) template.HTML { return template.HTML(s) },
-		"add":      func(a, b int) int { return a + b },
-		"subtract": func(a, b int) int { return a - b },
-		"now":      func() time.Time { return time.Now() },
-	}
+	return parseLayoutCode(code)
+}
 
-	tmpl, err := template.New("layout.html").Funcs(funcMap).Parse(code)
+// parseLayoutCode parses layout code with the functions generation provides.
+func parseLayoutCode(code string) (*template.Template, error) {
+	tmpl, err := template.New("layout.html").Funcs(templateFuncMap()).Parse(code)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse custom layout: %w", err)
 	}
@@ -408,100 +683,275 @@ func (g *HTMLGenerator) parseCustomLayout(code string) (*template.Template, erro
 }
 
 func (g *HTMLGenerator) preRenderAllContent(contents []*Content, basePath string, params map[string]string) []*RenderedContent {
-	var rendered []*RenderedContent
+	var publishable []*Content
 	for _, c := range contents {
-		if !isPublishable(c) {
-			continue
+		if isPublishable(c) {
+			publishable = append(publishable, c)
 		}
+	}
+
+	// Markdown processing dominates generation time; each slot is written by one worker.
+	rendered := make([]*RenderedContent, len(publishable))
+	runParallel(len(publishable), g.workerCount(), func(i int) {
+		c := publishable[i]
 		htmlBody, _ := g.processor.ProcessContent(c, params)
-		rendered = append(rendered, &RenderedContent{
+		rendered[i] = &RenderedContent{
 			Content:  c,
 			HTMLBody: template.HTML(htmlBody),
-			URL:      g.getContentURL(c, basePath),
-		})
-	}
+			URL:      g.getContentURL(c, basePath, params),
+		}
+	})
 	return rendered
 }
 
-// renderContentPage renders a single content page.
-func (g *HTMLGenerator) renderContentPage(embeddedTmpl *template.Template, layoutsBySection map[uuid.UUID]*Layout, siteDefaultLayout *Layout, htmlPath string, site *Site, content *Content, sections []*Section, menu []*Section, params map[string]string, allRendered []*RenderedContent, blocksCfg BlocksConfig) error {
-	basePath := g.getAssetPath(params)
+// sectionTemplate pairs a parsed page template with the layout it was resolved from.
+type sectionTemplate struct {
+	tmpl   *template.Template
+	layout *Layout
+}
+
+// resolveSectionTemplates resolves and parses the template for every section
+// used by contents once, so page rendering only executes templates.
+func (g *HTMLGenerator) resolveSectionTemplates(embeddedTmpl *template.Template, layoutsBySection map[uuid.UUID]*Layout, siteDefaultLayout *Layout, contents []*Content) map[uuid.UUID]sectionTemplate {
+	templates := make(map[uuid.UUID]sectionTemplate)
+	for _, c := range contents {
+		if _, ok := templates[c.SectionID]; ok {
+			continue
+		}
+		tmpl, layout := g.getTemplateAndLayoutForSection(embeddedTmpl, layoutsBySection, siteDefaultLayout, c.SectionID)
+		templates[c.SectionID] = sectionTemplate{tmpl: tmpl, layout: layout}
+	}
+	return templates
+}
 
-	var rendered *RenderedContent
+// resolveKindTemplates parses the layout of every kind that overrides the
+// section layout, keyed by kind name. Kinds whose layout no longer exists
+// keep the section one.
+func (g *HTMLGenerator) resolveKindTemplates(embeddedTmpl *template.Template, kinds []*ContentKind, layouts []*Layout) map[string]sectionTemplate {
+	templates := make(map[string]sectionTemplate)
+	for _, k := range kinds {
+		if k.LayoutID == uuid.Nil {
+			continue
+		}
+		for _, l := range layouts {
+			if l.ID == k.LayoutID {
+				templates[k.Name] = sectionTemplate{tmpl: g.templateForLayout(embeddedTmpl, l), layout: l}
+				break
+			}
+		}
+	}
+	return templates
+}
+
+// resolveContentTemplates parses the layout of every content that overrides
+// the layouts of its kind and section, keyed by content ID.
+func (g *HTMLGenerator) resolveContentTemplates(embeddedTmpl *template.Template, contents []*Content, layouts []*Layout) map[uuid.UUID]sectionTemplate {
+	templates := make(map[uuid.UUID]sectionTemplate)
+	parsed := make(map[uuid.UUID]sectionTemplate)
+	for _, c := range contents {
+		if c.LayoutID == uuid.Nil {
+			continue
+		}
+		if st, ok := parsed[c.LayoutID]; ok {
+			templates[c.ID] = st
+			continue
+		}
+		for _, l := range layouts {
+			if l.ID == c.LayoutID {
+				st := sectionTemplate{tmpl: g.templateForLayout(embeddedTmpl, l), layout: l}
+				parsed[l.ID] = st
+				templates[c.ID] = st
+				break
+			}
+		}
+	}
+	return templates
+}
+
+// renderContentPages renders every content page on a bounded worker pool,
+// with its own layout when it has one, else that of its kind, else that of
+// its section. It returns the number of pages written (unchanged pages are
+// skipped) and the per-page errors, sorted.
+func (g *HTMLGenerator) renderContentPages(templates map[uuid.UUID]sectionTemplate, kindTemplates map[string]sectionTemplate, contentTemplates map[uuid.UUID]sectionTemplate, build *buildState, htmlPath string, site *Site, pages []*Content, sections []*Section, menu []*Section, params map[string]string, allRendered []*RenderedContent, blocksCfg BlocksConfig) (int, []string) {
+	renderedByID := make(map[uuid.UUID]*RenderedContent, len(allRendered))
 	for _, r := range allRendered {
-		if r.ID == content.ID {
-			rendered = r
-			break
+		renderedByID[r.ID] = r
+	}
+	adjacent := buildAdjacentIndex(allRendered, params)
+
+	// Unlisted pages are rendered but never suggested from other pages.
+	var listed []*RenderedContent
+	for _, r := range allRendered {
+		if isListed(r.Content) {
+			listed = append(listed, r)
+		}
+	}
+
+	var generated atomic.Int64
+	var errMu sync.Mutex
+	var errs []string
+
+	runParallel(len(pages), g.workerCount(), func(i int) {
+		build.pageGauge().enter()
+		defer build.pageGauge().leave()
+
+		content := pages[i]
+		st := contentTemplate(templates, kindTemplates, contentTemplates, content)
+		written, err := g.renderContentPage(st.tmpl, st.layout, build, htmlPath, site, content, renderedByID[content.ID], adjacent[content.ID], sections, menu, params, listed, blocksCfg)
+		if err != nil {
+			errMu.Lock()
+			errs = append(errs, fmt.Sprintf("content %s: %v", content.Heading, err))
+			errMu.Unlock()
+			return
+		}
+		if written {
+			generated.Add(1)
+		}
+	})
+
+	sort.Strings(errs)
+	return int(generated.Load()), errs
+}
+
+// contentTemplate returns the template of a content page: its own layout's,
+// else its kind's, else its section's.
+func contentTemplate(templates map[uuid.UUID]sectionTemplate, kindTemplates map[string]sectionTemplate, contentTemplates map[uuid.UUID]sectionTemplate, content *Content) sectionTemplate {
+	if st, ok := contentTemplates[content.ID]; ok {
+		return st
+	}
+	if st, ok := kindTemplates[content.kindSettings().Name]; ok {
+		return st
+	}
+	return templates[content.SectionID]
+}
+
+// runParallel calls fn for every index in [0, n) using at most workers goroutines.
+// With a single worker it runs sequentially on the calling goroutine.
+func runParallel(n, workers int, fn func(i int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
 		}
+		return
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
 	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// renderContentPage renders a single content page, reporting whether it was written.
+// rendered may be nil, in which case the body is processed here.
+func (g *HTMLGenerator) renderContentPage(tmpl *template.Template, layout *Layout, build *buildState, htmlPath string, site *Site, content *Content, rendered *RenderedContent, adjacent adjacentLinks, sections []*Section, menu []*Section, params map[string]string, allRendered []*RenderedContent, blocksCfg BlocksConfig) (bool, error) {
+	basePath := g.getAssetPath(params)
+
 	if rendered == nil {
 		htmlBody, err := g.processor.ProcessContent(content, params)
 		if err != nil {
-			return err
+			return false, err
 		}
 		rendered = &RenderedContent{
 			Content:  content,
 			HTMLBody: template.HTML(htmlBody),
-			URL:      g.getContentURL(content, basePath),
+			URL:      g.getContentURL(content, basePath, params),
 		}
 	}
 
-	var section *Section
-	for _, s := range sections {
-		if s.ID == content.SectionID {
-			section = s
-			break
+	outputPath := pageFile(params, htmlPath, g.workspace.GetPageHTMLPath(site.Slug, permalinkPattern(params).contentPath(content)))
+	if content.kindSettings().Redirect {
+		if target := linkTarget(content); target != "" {
+			return g.writeRedirectPage(build, outputPath, target, content.UpdatedAt)
 		}
 	}
 
-	blocks := BuildBlocks(rendered, allRendered, blocksCfg)
+	data, blocks := g.contentPageData(layout, site, rendered, adjacent, sections, menu, params, allRendered, blocksCfg)
 
-	tmpl, layout := g.getTemplateAndLayoutForSection(embeddedTmpl, layoutsBySection, siteDefaultLayout, content.SectionID)
+	hash := contentPageHash(rendered, adjacent, blocks, data.Translations)
+	if build.unchanged(outputPath, hash, content.UpdatedAt) {
+		return false, nil
+	}
 
-	data := SSGPageData{
-		Site:      site,
-		Content:   rendered,
-		Section:   section,
-		Sections:  sections,
-		Menu:      menu,
-		Blocks:    blocks,
-		IsIndex:   false,
-		AssetPath: basePath,
-		Params:    params,
-	}
-	if layout != nil {
-		data.CustomCSS = layout.CSS
-		data.ExcludeDefaultCSS = layout.ExcludeDefaultCSS
-	}
-
-	outputPath := g.workspace.GetContentHTMLPath(site.Slug, content.SectionPath, content.Slug())
 	if err := EnsureDir(outputPath); err != nil {
-		return err
+		return false, err
 	}
 
 	f, err := os.Create(outputPath)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer f.Close()
 
-	return tmpl.ExecuteTemplate(f, "layout.html", data)
+	if err := g.writePage(f, tmpl, data); err != nil {
+		return false, err
+	}
+	build.record(outputPath, hash, content.UpdatedAt)
+	return true, nil
 }
 
-// renderIndexPages renders index pages with pagination.
-func (g *HTMLGenerator) renderIndexPages(embeddedTmpl *template.Template, layoutsBySection map[uuid.UUID]*Layout, siteDefaultLayout *Layout, htmlPath string, site *Site, contents []*Content, sections []*Section, menu []*Section, params map[string]string) (int, error) {
-	pageSize := 9
-	if v, ok := params["ssg.index.maxitems"]; ok {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			pageSize = n
+// contentPageData builds the template data for a content page.
+func (g *HTMLGenerator) contentPageData(layout *Layout, site *Site, rendered *RenderedContent, adjacent adjacentLinks, sections []*Section, menu []*Section, params map[string]string, allRendered []*RenderedContent, blocksCfg BlocksConfig) (SSGPageData, *GeneratedBlocks) {
+	var section *Section
+	for _, s := range sections {
+		if s.ID == rendered.SectionID {
+			section = s
+			break
 		}
 	}
+
+	blocks := BuildBlocks(rendered, allRendered, blocksCfg)
+
+	data := SSGPageData{
+		Site:         site,
+		Content:      rendered,
+		Section:      section,
+		Sections:     sections,
+		Menu:         menu,
+		Blocks:       blocks,
+		NewerContent: adjacent.newer,
+		OlderContent: adjacent.older,
+		IsIndex:      false,
+		Translations: g.translationLinks(rendered, params),
+		CanonicalURL: g.contentCanonicalURL(rendered, params),
+		SocialImage:  socialImageURL(rendered, params),
+		UpdatedAt:    updatedDate(rendered.Content, params),
+		Fields:       rendered.Fields,
+		AssetPath:    g.getAssetPath(params),
+		Params:       params,
+		Timezone:     siteLocation(params).String(),
+		DateFormat:   params[DateFormatRefKey],
+	}
+	g.setPageAssets(&data, layout, params)
+	return data, blocks
+}
+
+// renderIndexPages renders the main and section indexes with pagination.
+// It returns the number of listings rendered and the number of extra pages
+// (page 2 onwards) those listings were split into.
+func (g *HTMLGenerator) renderIndexPages(embeddedTmpl *template.Template, layoutsBySection map[uuid.UUID]*Layout, siteDefaultLayout *Layout, build *buildState, htmlPath string, site *Site, contents []*Content, sections []*Section, menu []*Section, params map[string]string) (int, int, error) {
+	pageSize := g.getPageSize(params)
 	count := 0
+	paged := 0
 
 	// Filter non-draft articles (exclude pages from index listings)
 	var publishedContents []*Content
 	for _, c := range contents {
-		if isPublishable(c) && c.Kind != "page" {
+		if inListings(c) {
 			publishedContents = append(publishedContents, c)
 		}
 	}
@@ -521,10 +971,19 @@ func (g *HTMLGenerator) renderIndexPages(embeddedTmpl *template.Template, layout
 		mainSectionID = mainSection.ID
 	}
 	mainTmpl, mainLayout := g.getTemplateAndLayoutForSection(embeddedTmpl, layoutsBySection, siteDefaultLayout, mainSectionID)
-	if err := g.renderIndex(mainTmpl, mainLayout, htmlPath, site, "", mainSection, publishedContents, sections, menu, params, pageSize); err != nil {
-		return count, err
+	var feeds []FeedLink
+	if len(publishedContents) > 0 {
+		feeds = g.feedLinks(params, "", site.Name)
+	}
+	// In page mode the root shows the home content instead, see renderHomePage.
+	if homeContent(params, contents) == nil {
+		pages, err := g.renderIndex(mainTmpl, mainLayout, build, htmlPath, site, "", mainSection, publishedContents, sections, menu, feeds, params, pageSize)
+		if err != nil {
+			return count, paged, err
+		}
+		count++
+		paged += pages - 1
 	}
-	count++
 
 	// Render section indices (skip main section to avoid overwriting main index)
 	for _, section := range sections {
@@ -541,23 +1000,103 @@ func (g *HTMLGenerator) renderIndexPages(embeddedTmpl *template.Template, layout
 
 		if len(sectionContents) > 0 {
 			tmpl, layout := g.getTemplateAndLayoutForSection(embeddedTmpl, layoutsBySection, siteDefaultLayout, section.ID)
-			if err := g.renderIndex(tmpl, layout, htmlPath, site, section.Path, section, sectionContents, sections, menu, params, pageSize); err != nil {
-				return count, err
+			var feeds []FeedLink
+			if sectionFeedEnabled(params, section.Path) {
+				feeds = g.feedLinks(params, section.Path, site.Name+" - "+section.Name)
+			}
+			pages, err := g.renderIndex(tmpl, layout, build, htmlPath, site, section.Path, section, sectionContents, sections, menu, feeds, params, pageSize)
+			if err != nil {
+				return count, paged, err
 			}
 			count++
+			paged += pages - 1
 		}
 	}
 
-	return count, nil
+	langCount, langPaged, err := g.renderLanguageIndexes(mainTmpl, mainLayout, build, site, publishedContents, sections, menu, params, pageSize)
+	return count + langCount, paged + langPaged, err
 }
 
-func (g *HTMLGenerator) renderIndex(tmpl *template.Template, layout *Layout, htmlPath string, site *Site, indexPath string, section *Section, contents []*Content, sections []*Section, menu []*Section, params map[string]string, pageSize int) error {
+// renderLanguageIndexes renders an index per language, e.g. /es/, when listed
+// content is in more than one language. A section at the same path wins.
+func (g *HTMLGenerator) renderLanguageIndexes(tmpl *template.Template, layout *Layout, build *buildState, site *Site, contents []*Content, sections []*Section, menu []*Section, params map[string]string, pageSize int) (int, int, error) {
+	siteLang := siteLanguage(params)
+	langs := contentLanguages(contents, siteLang)
+	if langs == nil {
+		return 0, 0, nil
+	}
+
+	sectionPaths := make(map[string]bool, len(sections))
+	for _, s := range sections {
+		sectionPaths[s.Path] = true
+	}
+	basePath := g.getAssetPath(params)
+	var indexes []TranslationLink
+	for _, lang := range langs {
+		if listPath := languageIndexPath(lang); !sectionPaths[listPath] {
+			indexes = append(indexes, TranslationLink{
+				Lang:    lang,
+				Heading: site.Name,
+				URL:     g.getAbsoluteURL(params, g.getPaginationURL(params, basePath, listPath, 1)),
+			})
+		}
+	}
+
+	count, paged := 0, 0
+	for _, index := range indexes {
+		lang := index.Lang
+		listPath := languageIndexPath(lang)
+		var langContents []*Content
+		for _, c := range contents {
+			if contentLang(c, siteLang) == lang {
+				langContents = append(langContents, c)
+			}
+		}
+		data := SSGPageData{
+			Site:      site,
+			Sections:  sections,
+			Menu:      menu,
+			IsIndex:   true,
+			IndexLang: lang,
+		}
+		for _, link := range indexes {
+			link.Current = link.Lang == lang
+			data.Translations = append(data.Translations, link)
+		}
+		pages, err := g.renderListPages(tmpl, layout, build, site, listPath, langContents, params, pageSize, data)
+		if err != nil {
+			return count, paged, err
+		}
+		count++
+		paged += pages - 1
+	}
+	return count, paged, nil
+}
+
+func (g *HTMLGenerator) renderIndex(tmpl *template.Template, layout *Layout, build *buildState, htmlPath string, site *Site, indexPath string, section *Section, contents []*Content, sections []*Section, menu []*Section, feeds []FeedLink, params map[string]string, pageSize int) (int, error) {
+	data := SSGPageData{
+		Site:     site,
+		Section:  section,
+		Sections: sections,
+		Menu:     menu,
+		Feeds:    feeds,
+		IsIndex:  true,
+	}
+	contents = pinFeatured(contents, featuredPins(params))
+	return g.renderListPages(tmpl, layout, build, site, indexPath, contents, params, pageSize, data)
+}
+
+// renderListPages splits contents into pages of pageSize and renders each one
+// under listPath (index.html, page/2/index.html, ...) starting from base.
+// Pages unchanged since the last build are skipped. It returns the listing's page count.
+func (g *HTMLGenerator) renderListPages(tmpl *template.Template, layout *Layout, build *buildState, site *Site, listPath string, contents []*Content, params map[string]string, pageSize int, base SSGPageData) (int, error) {
 	totalPages := (len(contents) + pageSize - 1) / pageSize
 	if totalPages == 0 {
 		totalPages = 1
 	}
 
 	basePath := g.getAssetPath(params)
+	canonicalURL := g.getAbsoluteURL(params, g.getPaginationURL(params, basePath, listPath, 1))
 
 	for page := 1; page <= totalPages; page++ {
 		start := (page - 1) * pageSize
@@ -568,171 +1107,269 @@ func (g *HTMLGenerator) renderIndex(tmpl *template.Template, layout *Layout, htm
 
 		pageContents := contents[start:end]
 
+		outputPath := pageFile(params, g.workspace.GetHTMLPath(site.Slug), g.workspace.GetPaginationHTMLPath(site.Slug, listPath, page))
+		hash, updatedAt := listPageHash(listPath, page, totalPages, pageContents)
+		if base.Translations != nil {
+			hash = hashInputs([]any{hash, base.Translations})
+		}
+		if build.unchanged(outputPath, hash, updatedAt) {
+			continue
+		}
+
 		var renderedContents []*RenderedContent
 		for _, c := range pageContents {
 			htmlBody, _ := g.processor.ProcessContent(c, params)
 			renderedContents = append(renderedContents, &RenderedContent{
 				Content:  c,
 				HTMLBody: template.HTML(htmlBody),
-				URL:      g.getContentURL(c, basePath),
+				URL:      g.getContentURL(c, basePath, params),
 			})
 		}
 
-		data := SSGPageData{
-			Site:        site,
-			Contents:    renderedContents,
-			Section:     section,
-			Sections:    sections,
-			Menu:        menu,
-			IsIndex:     true,
-			IsPaginated: totalPages > 1,
-			CurrentPage: page,
-			TotalPages:  totalPages,
-			HasPrev:     page > 1,
-			HasNext:     page < totalPages,
-			AssetPath:   basePath,
-			Params:      params,
-		}
-		if layout != nil {
-			data.CustomCSS = layout.CSS
-			data.ExcludeDefaultCSS = layout.ExcludeDefaultCSS
-		}
+		data := base
+		data.Contents = renderedContents
+		data.IsPaginated = totalPages > 1
+		data.CurrentPage = page
+		data.TotalPages = totalPages
+		data.HasPrev = page > 1
+		data.HasNext = page < totalPages
+		data.FirstURL = g.getPaginationURL(params, basePath, listPath, 1)
+		data.LastURL = g.getPaginationURL(params, basePath, listPath, totalPages)
+		data.CanonicalURL = canonicalURL
+		data.AssetPath = basePath
+		data.Params = params
+		data.Timezone = siteLocation(params).String()
+		data.DateFormat = params[DateFormatRefKey]
+		g.setPageAssets(&data, layout, params)
 
 		if page > 1 {
-			data.PrevURL = g.getPaginationURL(basePath, indexPath, page-1)
+			data.PrevURL = g.getPaginationURL(params, basePath, listPath, page-1)
 		}
 		if page < totalPages {
-			data.NextURL = g.getPaginationURL(basePath, indexPath, page+1)
+			data.NextURL = g.getPaginationURL(params, basePath, listPath, page+1)
 		}
 
-		outputPath := g.workspace.GetPaginationHTMLPath(site.Slug, indexPath, page)
 		if err := EnsureDir(outputPath); err != nil {
-			return err
+			return page - 1, err
 		}
 
 		f, err := os.Create(outputPath)
 		if err != nil {
-			return err
+			return page - 1, err
 		}
 
-		if err := tmpl.ExecuteTemplate(f, "layout.html", data); err != nil {
+		if err := g.writePage(f, tmpl, data); err != nil {
 			f.Close()
-			return err
+			return page - 1, err
 		}
 		f.Close()
+		build.record(outputPath, hash, updatedAt)
 	}
 
-	return nil
+	return totalPages, nil
+}
+
+// renderTagPages renders a paginated listing for every tag used by published content.
+// It returns the number of tags rendered and the number of extra pages generated.
+func (g *HTMLGenerator) renderTagPages(embeddedTmpl *template.Template, siteDefaultLayout *Layout, build *buildState, site *Site, contents []*Content, sections []*Section, menu []*Section, params map[string]string) (int, int, error) {
+	pageSize := g.getPageSize(params)
+	tmpl := g.getSiteDefaultTemplate(embeddedTmpl, siteDefaultLayout)
+
+	var tags []*Tag
+	tagContents := make(map[string][]*Content)
+	for _, c := range contents {
+		if !inListings(c) {
+			continue
+		}
+		for _, t := range c.Tags {
+			slug := tagSlug(t)
+			if slug == "" {
+				continue
+			}
+			if _, ok := tagContents[slug]; !ok {
+				tags = append(tags, t)
+			}
+			tagContents[slug] = append(tagContents[slug], c)
+		}
+	}
+
+	count := 0
+	paged := 0
+	for _, t := range tags {
+		slug := tagSlug(t)
+		data := SSGPageData{
+			Site:        site,
+			Sections:    sections,
+			Menu:        menu,
+			IsIndex:     true,
+			IsTag:       true,
+			Tag:         t,
+			SocialImage: tagSocialImageURL(t, params),
+		}
+		if tagFeedsEnabled(params) {
+			data.Feeds = g.feedLinks(params, "tags/"+slug, site.Name+" - #"+t.Name)
+		}
+		pages, err := g.renderListPages(tmpl, siteDefaultLayout, build, site, "tags/"+slug, tagContents[slug], params, pageSize, data)
+		if err != nil {
+			return count, paged, err
+		}
+		count++
+		paged += pages - 1
+	}
+
+	return count, paged, nil
+}
+
+// tagSlug returns the URL segment used for a tag's listing pages.
+func tagSlug(t *Tag) string {
+	if t.Slug != "" {
+		return t.Slug
+	}
+	return Slugify(t.Name)
+}
+
+// getPageSize returns the number of items per listing page.
+// ssg.index.page_size takes precedence over the older ssg.index.maxitems.
+func (g *HTMLGenerator) getPageSize(params map[string]string) int {
+	for _, key := range []string{"ssg.index.page_size", "ssg.index.maxitems"} {
+		if v, ok := params[key]; ok {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return 9
+}
+
+// getSiteDefaultTemplate returns the site default layout template, or the embedded one.
+func (g *HTMLGenerator) getSiteDefaultTemplate(embeddedTmpl *template.Template, siteDefaultLayout *Layout) *template.Template {
+	if siteDefaultLayout != nil && siteDefaultLayout.Code != "" {
+		if customTmpl, err := g.parseCustomLayout(siteDefaultLayout.Code); err == nil {
+			return customTmpl
+		}
+	}
+	return embeddedTmpl
 }
 
-// getContentURL returns the URL for a content item.
-func (g *HTMLGenerator) getContentURL(content *Content, basePath string) string {
-	if content.SectionPath == "" || content.SectionPath == "/" {
-		return basePath + content.Slug() + "/"
+// getAbsoluteURL prefixes a site path with the base URL when configured.
+func (g *HTMLGenerator) getAbsoluteURL(params map[string]string, path string) string {
+	if baseURL := siteBaseURL(params); baseURL != "" {
+		return baseURL + path
 	}
-	return basePath + content.SectionPath + "/" + content.Slug() + "/"
+	return path
+}
+
+// contentCanonicalURL returns the manual canonical URL of a content page when
+// set, e.g. for a cross-post, the site root for the home page content and the
+// page's own absolute URL otherwise.
+func (g *HTMLGenerator) contentCanonicalURL(rendered *RenderedContent, params map[string]string) string {
+	if rendered.Meta != nil && rendered.Meta.CanonicalURL != "" {
+		return rendered.Meta.CanonicalURL
+	}
+	if isHomeContent(rendered.Content, params) {
+		return g.getAbsoluteURL(params, g.getPaginationURL(params, g.getAssetPath(params), "", 1))
+	}
+	return g.getAbsoluteURL(params, rendered.URL)
+}
+
+// getContentURL returns the URL for a content item, following the site's permalink pattern.
+func (g *HTMLGenerator) getContentURL(content *Content, basePath string, params map[string]string) string {
+	return pageURL(params, basePath+permalinkPattern(params).contentPath(content)+"/")
 }
 
 // getPaginationURL returns the URL for a pagination page.
-func (g *HTMLGenerator) getPaginationURL(basePath, indexPath string, page int) string {
+func (g *HTMLGenerator) getPaginationURL(params map[string]string, basePath, indexPath string, page int) string {
 	if page == 1 {
 		if indexPath == "" || indexPath == "/" {
 			return basePath
 		}
-		return basePath + indexPath + "/"
+		return pageURL(params, basePath+indexPath+"/")
 	}
 	if indexPath == "" || indexPath == "/" {
-		return fmt.Sprintf("%spage/%d/", basePath, page)
+		return pageURL(params, fmt.Sprintf("%spage/%d/", basePath, page))
 	}
-	return fmt.Sprintf("%s%s/page/%d/", basePath, indexPath, page)
+	return pageURL(params, fmt.Sprintf("%s%s/page/%d/", basePath, indexPath, page))
 }
 
 func (g *HTMLGenerator) getAssetPath(params map[string]string) string {
-	if basePath, ok := params["ssg.site.base_path"]; ok && basePath != "" {
-		if basePath[0] != '/' {
-			basePath = "/" + basePath
-		}
-		if basePath[len(basePath)-1] != '/' {
-			basePath = basePath + "/"
-		}
-		return basePath
-	}
-	return "/"
+	return siteBasePath(params)
+}
+
+// AuthorGroup is a heading on the authors index with the contributors
+// sharing a role.
+type AuthorGroup struct {
+	Role    string
+	Title   string
+	Authors []*AuthorEntry
 }
 
-func (g *HTMLGenerator) renderAuthorPages(embeddedTmpl *template.Template, siteDefaultLayout *Layout, htmlPath string, site *Site, contents []*Content, contributors []*Contributor, userAuthors map[string]*Contributor, menu []*Section, params map[string]string) (int, error) {
+// AuthorEntry is an author listed on the authors index.
+type AuthorEntry struct {
+	*Contributor
+	URL   string
+	Posts int
+}
+
+// hideEmptyAuthorsRefKey is the setting that skips contributors without
+// published content on author pages.
+const hideEmptyAuthorsRefKey = "ssg.authors.hide_empty"
+
+// renderAuthorPages renders a paginated listing for every contributor and user
+// author, and an authors index grouping them by role.
+// It returns the number of authors rendered and the number of extra pages generated.
+func (g *HTMLGenerator) renderAuthorPages(embeddedTmpl *template.Template, siteDefaultLayout *Layout, build *buildState, htmlPath string, site *Site, contents []*Content, contributors []*Contributor, userAuthors map[string]*Contributor, menu []*Section, params map[string]string) (int, int, error) {
 	count := 0
+	paged := 0
 	generatedHandles := make(map[string]bool)
+	pageSize := g.getPageSize(params)
+	hideEmpty := params[hideEmptyAuthorsRefKey] == "true"
 	basePath := g.getAssetPath(params)
 
 	// Use site default layout for author pages if set
-	tmpl := embeddedTmpl
-	if siteDefaultLayout != nil && siteDefaultLayout.Code != "" {
-		if customTmpl, err := g.parseCustomLayout(siteDefaultLayout.Code); err == nil {
-			tmpl = customTmpl
+	tmpl := g.getSiteDefaultTemplate(embeddedTmpl, siteDefaultLayout)
+
+	var published []*Content
+	for _, c := range contents {
+		if isListed(c) {
+			published = append(published, c)
 		}
 	}
 
-	for _, contributor := range contributors {
-		authorContents := g.getContentsByAuthor(contents, contributor.Handle)
-		generatedHandles[contributor.Handle] = true
-
-		var renderedContents []*RenderedContent
-		for _, c := range authorContents {
-			htmlBody, _ := g.processor.ProcessContent(c, params)
-			renderedContents = append(renderedContents, &RenderedContent{
-				Content:  c,
-				HTMLBody: template.HTML(htmlBody),
-				URL:      g.getContentURL(c, basePath),
-			})
+	var entries []*AuthorEntry
+	renderAuthor := func(author *Contributor) error {
+		authorContents := g.getContentsByAuthor(published, author.Handle)
+		if hideEmpty && len(authorContents) == 0 {
+			return nil
 		}
-
 		data := SSGPageData{
-			Site:      site,
-			Author:    contributor,
-			Contents:  renderedContents,
-			Menu:      menu,
-			IsAuthor:  true,
-			AssetPath: basePath,
-			Params:    params,
+			Site:     site,
+			Author:   author,
+			Menu:     menu,
+			IsAuthor: true,
 		}
-		if siteDefaultLayout != nil {
-			data.CustomCSS = siteDefaultLayout.CSS
-			data.ExcludeDefaultCSS = siteDefaultLayout.ExcludeDefaultCSS
-		}
-
-		outputPath := filepath.Join(htmlPath, "authors", contributor.Handle, "index.html")
-		if err := EnsureDir(outputPath); err != nil {
-			return count, err
-		}
-
-		f, err := os.Create(outputPath)
+		pages, err := g.renderListPages(tmpl, siteDefaultLayout, build, site, "authors/"+author.Handle, authorContents, params, pageSize, data)
 		if err != nil {
-			return count, err
-		}
-
-		if err := tmpl.ExecuteTemplate(f, "layout.html", data); err != nil {
-			f.Close()
-			return count, err
+			return err
 		}
-		f.Close()
 		count++
+		paged += pages - 1
+		entries = append(entries, &AuthorEntry{
+			Contributor: author,
+			URL:         g.getPaginationURL(params, basePath, "authors/"+author.Handle, 1),
+			Posts:       len(authorContents),
+		})
+		return nil
 	}
 
-	usernames := g.getUniqueUserAuthors(contents, generatedHandles)
-	for _, username := range usernames {
-		authorContents := g.getContentsByAuthor(contents, username)
-
-		var renderedContents []*RenderedContent
-		for _, c := range authorContents {
-			htmlBody, _ := g.processor.ProcessContent(c, params)
-			renderedContents = append(renderedContents, &RenderedContent{
-				Content:  c,
-				HTMLBody: template.HTML(htmlBody),
-				URL:      g.getContentURL(c, basePath),
-			})
+	for _, contributor := range contributors {
+		generatedHandles[contributor.Handle] = true
+		if err := renderAuthor(contributor); err != nil {
+			return count, paged, err
 		}
+	}
 
+	usernames := g.getUniqueUserAuthors(published, generatedHandles)
+	for _, username := range usernames {
 		userAuthor := userAuthors[username]
 		if userAuthor == nil {
 			userAuthor = &Contributor{
@@ -740,65 +1377,117 @@ func (g *HTMLGenerator) renderAuthorPages(embeddedTmpl *template.Template, siteD
 				Name:   username,
 			}
 		}
-
-		data := SSGPageData{
-			Site:      site,
-			Author:    userAuthor,
-			Contents:  renderedContents,
-			Menu:      menu,
-			IsAuthor:  true,
-			AssetPath: basePath,
-			Params:    params,
-		}
-		if siteDefaultLayout != nil {
-			data.CustomCSS = siteDefaultLayout.CSS
-			data.ExcludeDefaultCSS = siteDefaultLayout.ExcludeDefaultCSS
+		if err := renderAuthor(userAuthor); err != nil {
+			return count, paged, err
 		}
+	}
 
-		outputPath := filepath.Join(htmlPath, "authors", username, "index.html")
-		if err := EnsureDir(outputPath); err != nil {
-			return count, err
+	if len(entries) > 0 {
+		if err := g.renderAuthorsIndex(tmpl, siteDefaultLayout, build, htmlPath, site, groupAuthorsByRole(entries), menu, params); err != nil {
+			return count, paged, err
 		}
+	}
 
-		f, err := os.Create(outputPath)
-		if err != nil {
-			return count, err
-		}
+	return count, paged, nil
+}
 
-		if err := tmpl.ExecuteTemplate(f, "layout.html", data); err != nil {
-			f.Close()
-			return count, err
+// renderAuthorsIndex writes authors/index.html listing every author by role.
+func (g *HTMLGenerator) renderAuthorsIndex(tmpl *template.Template, layout *Layout, build *buildState, htmlPath string, site *Site, groups []*AuthorGroup, menu []*Section, params map[string]string) error {
+	basePath := g.getAssetPath(params)
+	data := SSGPageData{
+		Site:         site,
+		Menu:         menu,
+		IsAuthor:     true,
+		AuthorGroups: groups,
+		CanonicalURL: g.getAbsoluteURL(params, g.getPaginationURL(params, basePath, "authors", 1)),
+		AssetPath:    basePath,
+		Params:       params,
+		Timezone:     siteLocation(params).String(),
+		DateFormat:   params[DateFormatRefKey],
+	}
+	g.setPageAssets(&data, layout, params)
+
+	outputPath := pageFile(params, htmlPath, filepath.Join(htmlPath, "authors", "index.html"))
+	if err := EnsureDir(outputPath); err != nil {
+		return err
+	}
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := g.writePage(f, tmpl, data); err != nil {
+		return err
+	}
+	build.record(outputPath, "", time.Time{})
+	return nil
+}
+
+// groupAuthorsByRole groups authors by role, known roles first in
+// ContributorRoles order, then the rest alphabetically. Authors without a
+// role, such as user authors, are grouped as authors.
+func groupAuthorsByRole(entries []*AuthorEntry) []*AuthorGroup {
+	byRole := make(map[string]*AuthorGroup)
+	for _, e := range entries {
+		role := e.Role
+		if role == "" {
+			role = ContributorRoleAuthor
+		}
+		group, ok := byRole[role]
+		if !ok {
+			group = &AuthorGroup{Role: role, Title: RoleTitle(role)}
+			byRole[role] = group
 		}
-		f.Close()
-		count++
+		group.Authors = append(group.Authors, e)
 	}
 
-	return count, nil
+	rank := make(map[string]int, len(ContributorRoles))
+	for i, role := range ContributorRoles {
+		rank[role] = i + 1
+	}
+	groups := make([]*AuthorGroup, 0, len(byRole))
+	for _, group := range byRole {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		ri, rj := rank[groups[i].Role], rank[groups[j].Role]
+		if ri == 0 {
+			ri = len(rank) + 1
+		}
+		if rj == 0 {
+			rj = len(rank) + 1
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return groups[i].Role < groups[j].Role
+	})
+	return groups
+}
+
+// searchPageEnabled reports whether the site gets a search page, which needs
+// Google site search turned on with an engine ID.
+func searchPageEnabled(params map[string]string) bool {
+	return params["ssg.search.google.enabled"] == "true" && params["ssg.search.google.id"] != ""
 }
 
 func (g *HTMLGenerator) generateSearchPage(embeddedTmpl *template.Template, siteDefaultLayout *Layout, htmlPath string, site *Site, menu []*Section, params map[string]string) error {
 	basePath := g.getAssetPath(params)
-
-	tmpl := embeddedTmpl
-	if siteDefaultLayout != nil && siteDefaultLayout.Code != "" {
-		if customTmpl, err := g.parseCustomLayout(siteDefaultLayout.Code); err == nil {
-			tmpl = customTmpl
-		}
-	}
+	tmpl := g.getSiteDefaultTemplate(embeddedTmpl, siteDefaultLayout)
 
 	data := SSGPageData{
-		Site:      site,
-		Menu:      menu,
-		IsSearch:  true,
-		AssetPath: basePath,
-		Params:    params,
-	}
-	if siteDefaultLayout != nil {
-		data.CustomCSS = siteDefaultLayout.CSS
-		data.ExcludeDefaultCSS = siteDefaultLayout.ExcludeDefaultCSS
+		Site:       site,
+		Menu:       menu,
+		IsSearch:   true,
+		AssetPath:  basePath,
+		Params:     params,
+		Timezone:   siteLocation(params).String(),
+		DateFormat: params[DateFormatRefKey],
 	}
+	g.setPageAssets(&data, siteDefaultLayout, params)
 
-	outputPath := filepath.Join(htmlPath, "search", "index.html")
+	outputPath := pageFile(params, htmlPath, filepath.Join(htmlPath, "search", "index.html"))
 	if err := EnsureDir(outputPath); err != nil {
 		return err
 	}
@@ -809,7 +1498,7 @@ func (g *HTMLGenerator) generateSearchPage(embeddedTmpl *template.Template, site
 	}
 	defer f.Close()
 
-	return tmpl.ExecuteTemplate(f, "layout.html", data)
+	return g.writePage(f, tmpl, data)
 }
 
 func (g *HTMLGenerator) getUniqueUserAuthors(contents []*Content, excludeHandles map[string]bool) []string {
@@ -835,6 +1524,16 @@ func (g *HTMLGenerator) getContentsByAuthor(contents []*Content, handle string)
 }
 
 // sitemapURLSet is the root element of a sitemap XML file.
+// SitemapMaxURLsRefKey sets the most URLs sitemap.xml lists. Sites with more
+// get a sitemap index at sitemap.xml instead, pointing to sitemap-1.xml,
+// sitemap-2.xml and so on, each within the limit.
+const SitemapMaxURLsRefKey = "ssg.sitemap.max_urls"
+
+// sitemapURLLimit is the most URLs the sitemap protocol allows in one file.
+const sitemapURLLimit = 50000
+
+const sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
 type sitemapURLSet struct {
 	XMLName xml.Name     `xml:"urlset"`
 	XMLNS   string       `xml:"xmlns,attr"`
@@ -847,25 +1546,56 @@ type sitemapURL struct {
 	LastMod string `xml:"lastmod"`
 }
 
-// generateSitemap creates a sitemap.xml file in the output directory.
-func (g *HTMLGenerator) generateSitemap(htmlPath, baseURL, basePath string, site *Site, contents []*Content, sections []*Section) error {
-	fullBase := strings.TrimRight(baseURL, "/") + basePath
+// sitemapIndex lists the sitemap files of a site too large for one.
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapRef `xml:"sitemap"`
+}
 
-	now := time.Now()
-	urlSet := sitemapURLSet{
-		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+// sitemapRef is a sitemap file in the index.
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapMaxURLs returns the ssg.sitemap.max_urls setting, or the protocol
+// limit when it is unset, invalid or above it.
+func sitemapMaxURLs(params map[string]string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(params[SitemapMaxURLsRefKey]))
+	if err != nil || n < 1 || n > sitemapURLLimit {
+		return sitemapURLLimit
 	}
+	return n
+}
 
-	// Homepage
-	urlSet.URLs = append(urlSet.URLs, sitemapURL{
-		Loc:     strings.TrimRight(fullBase, "/") + "/",
-		LastMod: now.UTC().Format("2006-01-02"),
-	})
+// sitemapEntry is a page the sitemap lists: the home page, a section index or
+// a content page. Path is relative to the host and includes the base path.
+type sitemapEntry struct {
+	Path    string
+	LastMod time.Time
+	Section *Section
+	Content *Content
+}
+
+// sitemapEntries resolves the pages worth indexing: the home page, sections
+// with listed content and listed content that does not opt out of the
+// sitemap, is not marked noindex or only redirect elsewhere. A noindex site
+// default leaves the index pages out. The home page content is listed at the
+// root only.
+func (g *HTMLGenerator) sitemapEntries(basePath string, contents []*Content, sections []*Section, params map[string]string, now time.Time) []sitemapEntry {
+	root := strings.TrimRight(basePath, "/")
+	// Index pages have no robots value of their own and take the site default.
+	indexPages := !robotsNoIndex(params[RobotsDefaultRefKey])
+	var entries []sitemapEntry
+	if indexPages {
+		entries = append(entries, sitemapEntry{Path: root + "/", LastMod: now})
+	}
 
 	// Section pages: only sections with publishable content
 	sectionMaxUpdated := make(map[uuid.UUID]time.Time)
 	for _, c := range contents {
-		if !isPublishable(c) {
+		if !isListed(c) {
 			continue
 		}
 		if t, ok := sectionMaxUpdated[c.SectionID]; !ok || c.UpdatedAt.After(t) {
@@ -874,35 +1604,81 @@ func (g *HTMLGenerator) generateSitemap(htmlPath, baseURL, basePath string, site
 	}
 
 	for _, section := range sections {
-		if section.Path == "" || section.Path == "/" {
+		if !indexPages || section.Path == "" || section.Path == "/" {
 			continue
 		}
 		lastMod, ok := sectionMaxUpdated[section.ID]
 		if !ok {
 			continue
 		}
-		urlSet.URLs = append(urlSet.URLs, sitemapURL{
-			Loc:     strings.TrimRight(fullBase, "/") + "/" + section.Path + "/",
-			LastMod: lastMod.UTC().Format("2006-01-02"),
-		})
+		entries = append(entries, sitemapEntry{Path: pageURL(params, root+"/"+section.Path+"/"), LastMod: lastMod, Section: section})
 	}
 
 	// Individual content pages
 	for _, c := range contents {
-		if !isPublishable(c) {
+		if !isListed(c) {
 			continue
 		}
 		if c.Meta != nil && (c.Meta.Sitemap == "exclude" || c.Meta.Sitemap == "noindex") {
 			continue
 		}
-		contentURL := g.getContentURL(c, basePath)
-		urlSet.URLs = append(urlSet.URLs, sitemapURL{
-			Loc:     strings.TrimRight(baseURL, "/") + contentURL,
-			LastMod: c.UpdatedAt.UTC().Format("2006-01-02"),
+		if robotsNoIndex(contentRobots(c, params)) || isHomeContent(c, params) {
+			continue
+		}
+		if c.kindSettings().Redirect && linkTarget(c) != "" {
+			continue
+		}
+		entries = append(entries, sitemapEntry{Path: g.getContentURL(c, basePath, params), LastMod: c.UpdatedAt, Content: c})
+	}
+	return entries
+}
+
+// generateSitemap writes sitemap.xml to the output directory. When the site
+// has more URLs than ssg.sitemap.max_urls, the URLs are split over
+// sitemap-1.xml, sitemap-2.xml and so on, and sitemap.xml is their index, so
+// robots.txt and search consoles keep pointing at the same file. It returns
+// the names of the files written.
+func (g *HTMLGenerator) generateSitemap(htmlPath, baseURL, basePath string, site *Site, contents []*Content, sections []*Section, params map[string]string) ([]string, error) {
+	loc := siteLocation(params)
+	var urls []sitemapURL
+	for _, e := range g.sitemapEntries(basePath, contents, sections, params, time.Now()) {
+		urls = append(urls, sitemapURL{
+			Loc:     strings.TrimRight(baseURL, "/") + e.Path,
+			LastMod: e.LastMod.In(loc).Format("2006-01-02"),
 		})
 	}
 
-	f, err := os.Create(filepath.Join(htmlPath, "sitemap.xml"))
+	maxURLs := sitemapMaxURLs(params)
+	if len(urls) <= maxURLs {
+		return []string{"sitemap.xml"}, writeSitemapFile(filepath.Join(htmlPath, "sitemap.xml"), sitemapURLSet{XMLNS: sitemapXMLNS, URLs: urls})
+	}
+
+	index := sitemapIndex{XMLNS: sitemapXMLNS}
+	var files []string
+	for start := 0; start < len(urls); start += maxURLs {
+		part := urls[start:min(start+maxURLs, len(urls))]
+		name := fmt.Sprintf("sitemap-%d.xml", len(files)+1)
+		if err := writeSitemapFile(filepath.Join(htmlPath, name), sitemapURLSet{XMLNS: sitemapXMLNS, URLs: part}); err != nil {
+			return files, err
+		}
+		files = append(files, name)
+
+		// Dates are formatted as 2006-01-02, so the latest sorts last.
+		lastMod := ""
+		for _, u := range part {
+			lastMod = max(lastMod, u.LastMod)
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapRef{Loc: baseURL + basePath + name, LastMod: lastMod})
+	}
+	if err := writeSitemapFile(filepath.Join(htmlPath, "sitemap.xml"), index); err != nil {
+		return files, err
+	}
+	return append(files, "sitemap.xml"), nil
+}
+
+// writeSitemapFile writes v as an indented XML document to path.
+func writeSitemapFile(path string, v any) error {
+	f, err := os.Create(path)
 	if err != nil {
 		return err
 	}
@@ -914,22 +1690,21 @@ func (g *HTMLGenerator) generateSitemap(htmlPath, baseURL, basePath string, site
 
 	enc := xml.NewEncoder(f)
 	enc.Indent("", "  ")
-	return enc.Encode(urlSet)
+	return enc.Encode(v)
 }
 
 // generateCNAME creates a CNAME file in the output directory for GitHub Pages custom domains.
-func (g *HTMLGenerator) generateCNAME(htmlPath, baseURL string) error {
-	u, err := url.Parse(baseURL)
-	if err != nil {
-		return fmt.Errorf("invalid base URL: %w", err)
-	}
-
-	hostname := u.Hostname()
-	if hostname == "" || hostname == "localhost" {
+// generateCNAME writes the custom domain for GitHub Pages. With no domain,
+// a CNAME left by a previous build is removed.
+func (g *HTMLGenerator) generateCNAME(htmlPath, domain string) error {
+	path := filepath.Join(htmlPath, "CNAME")
+	if domain == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 		return nil
 	}
-
-	return os.WriteFile(filepath.Join(htmlPath, "CNAME"), []byte(hostname), 0644)
+	return os.WriteFile(path, []byte(domain), 0644)
 }
 
 func (g *HTMLGenerator) generateRobotsTxt(htmlPath, content, sitemapURL string) error {
@@ -2,8 +2,12 @@ package ssg
 
 import (
 	"encoding/xml"
+	"fmt"
+	"html/template"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -86,7 +90,7 @@ func TestGenerateSitemap(t *testing.T) {
 
 	site := &Site{ID: siteID, Name: "Test", Slug: "test"}
 
-	err := g.generateSitemap(tmpDir, "https://example.com", "/", site, contents, sections)
+	_, err := g.generateSitemap(tmpDir, "https://example.com", "/", site, contents, sections, nil)
 	if err != nil {
 		t.Fatalf("generateSitemap failed: %v", err)
 	}
@@ -166,7 +170,7 @@ func TestGenerateSitemapWithBasePath(t *testing.T) {
 
 	site := &Site{ID: siteID, Name: "Test", Slug: "test"}
 
-	err := g.generateSitemap(tmpDir, "https://example.com", "/blog/", site, contents, sections)
+	_, err := g.generateSitemap(tmpDir, "https://example.com", "/blog/", site, contents, sections, nil)
 	if err != nil {
 		t.Fatalf("generateSitemap failed: %v", err)
 	}
@@ -194,34 +198,124 @@ func TestGenerateSitemapWithBasePath(t *testing.T) {
 	}
 }
 
+func TestGenerateSitemapIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	g := &HTMLGenerator{}
+
+	siteID := uuid.New()
+	sectionMain := &Section{ID: uuid.New(), SiteID: siteID, Name: "main", Path: ""}
+	sections := []*Section{sectionMain}
+	site := &Site{ID: siteID, Name: "Test", Slug: "test"}
+
+	publishedAt := time.Now().Add(-time.Hour)
+	var contents []*Content
+	for i := 0; i < 4; i++ {
+		contents = append(contents, &Content{
+			ID:          uuid.New(),
+			SiteID:      siteID,
+			SectionID:   sectionMain.ID,
+			ShortID:     fmt.Sprintf("abc1234%d", i),
+			Heading:     fmt.Sprintf("Post %d", i),
+			PublishedAt: &publishedAt,
+			UpdatedAt:   publishedAt,
+		})
+	}
+
+	// The homepage and four posts are five URLs.
+	files, err := g.generateSitemap(tmpDir, "https://example.com", "/blog/", site, contents, sections, map[string]string{SitemapMaxURLsRefKey: "5"})
+	if err != nil {
+		t.Fatalf("generateSitemap() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "sitemap.xml" {
+		t.Errorf("generateSitemap() at the threshold wrote %v, want just sitemap.xml", files)
+	}
+
+	files, err = g.generateSitemap(tmpDir, "https://example.com", "/blog/", site, contents, sections, map[string]string{SitemapMaxURLsRefKey: "2"})
+	if err != nil {
+		t.Fatalf("generateSitemap() error = %v", err)
+	}
+	want := []string{"sitemap-1.xml", "sitemap-2.xml", "sitemap-3.xml", "sitemap.xml"}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("generateSitemap() wrote %v, want %v", files, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "sitemap.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index sitemapIndex
+	if err := xml.Unmarshal(data, &index); err != nil {
+		t.Fatalf("sitemap.xml is not a sitemap index: %v", err)
+	}
+	if len(index.Sitemaps) != 3 {
+		t.Fatalf("index lists %d sitemaps, want 3", len(index.Sitemaps))
+	}
+	if loc := index.Sitemaps[1].Loc; loc != "https://example.com/blog/sitemap-2.xml" {
+		t.Errorf("second sitemap loc = %s, want https://example.com/blog/sitemap-2.xml", loc)
+	}
+
+	total := 0
+	for i, name := range want[:3] {
+		data, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var urlSet sitemapURLSet
+		if err := xml.Unmarshal(data, &urlSet); err != nil {
+			t.Fatalf("%s: invalid XML: %v", name, err)
+		}
+		if len(urlSet.URLs) > 2 {
+			t.Errorf("%s lists %d URLs, want at most 2", name, len(urlSet.URLs))
+		}
+		if index.Sitemaps[i].LastMod == "" {
+			t.Errorf("index entry for %s has no lastmod", name)
+		}
+		total += len(urlSet.URLs)
+	}
+	if total != 5 {
+		t.Errorf("child sitemaps list %d URLs, want 5", total)
+	}
+}
+
 func TestGenerateCNAME(t *testing.T) {
 	tests := []struct {
 		name        string
-		baseURL     string
+		params      map[string]string
 		wantFile    bool
 		wantContent string
 	}{
 		{
 			name:        "standard domain",
-			baseURL:     "https://example.com",
+			params:      map[string]string{BaseURLRefKey: "https://example.com"},
 			wantFile:    true,
 			wantContent: "example.com",
 		},
 		{
 			name:        "domain with subdomain",
-			baseURL:     "https://blog.example.com",
+			params:      map[string]string{BaseURLRefKey: "https://blog.example.com"},
 			wantFile:    true,
 			wantContent: "blog.example.com",
 		},
 		{
 			name:        "domain with path",
-			baseURL:     "https://example.com/blog",
+			params:      map[string]string{BaseURLRefKey: "https://example.com/blog"},
 			wantFile:    true,
 			wantContent: "example.com",
 		},
 		{
 			name:     "localhost skipped",
-			baseURL:  "http://localhost:8080",
+			params:   map[string]string{BaseURLRefKey: "http://localhost:8080"},
+			wantFile: false,
+		},
+		{
+			name:        "custom domain setting wins",
+			params:      map[string]string{BaseURLRefKey: "https://user.github.io/blog", DomainRefKey: "Blog.Example.com"},
+			wantFile:    true,
+			wantContent: "blog.example.com",
+		},
+		{
+			name:     "no base URL or domain",
+			params:   map[string]string{},
 			wantFile: false,
 		},
 	}
@@ -231,12 +325,17 @@ func TestGenerateCNAME(t *testing.T) {
 			tmpDir := t.TempDir()
 			g := &HTMLGenerator{}
 
-			err := g.generateCNAME(tmpDir, tt.baseURL)
+			path := filepath.Join(tmpDir, "CNAME")
+			// A CNAME from a previous build must not survive when there is no domain.
+			if err := os.WriteFile(path, []byte("stale.example.com"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := g.generateCNAME(tmpDir, cnameDomain(tt.params))
 			if err != nil {
 				t.Fatalf("generateCNAME failed: %v", err)
 			}
 
-			path := filepath.Join(tmpDir, "CNAME")
 			data, err := os.ReadFile(path)
 			if tt.wantFile {
 				if err != nil {
@@ -253,3 +352,608 @@ func TestGenerateCNAME(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPageSize(t *testing.T) {
+	g := &HTMLGenerator{}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   int
+	}{
+		{"default", map[string]string{}, 9},
+		{"page size", map[string]string{"ssg.index.page_size": "5"}, 5},
+		{"legacy max items", map[string]string{"ssg.index.maxitems": "7"}, 7},
+		{"page size wins", map[string]string{"ssg.index.page_size": "3", "ssg.index.maxitems": "7"}, 3},
+		{"invalid falls back", map[string]string{"ssg.index.page_size": "zero"}, 9},
+		{"non-positive falls back", map[string]string{"ssg.index.page_size": "0"}, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.getPageSize(tt.params); got != tt.want {
+				t.Errorf("getPageSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderListPagesPagination(t *testing.T) {
+	tmpDir := t.TempDir()
+	g := &HTMLGenerator{workspace: NewWorkspace(tmpDir), processor: NewProcessor()}
+
+	tmpl := template.Must(template.New("layout.html").Parse(
+		`{{ .CurrentPage }}/{{ .TotalPages }}|{{ len .Contents }}|{{ .PrevURL }}|{{ .NextURL }}|{{ .FirstURL }}|{{ .LastURL }}|{{ .CanonicalURL }}`))
+
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	var contents []*Content
+	for i := 0; i < 5; i++ {
+		contents = append(contents, &Content{ID: uuid.New(), ShortID: fmt.Sprintf("c%07d", i), Heading: "Post"})
+	}
+	params := map[string]string{"ssg.site.base_url": "https://example.com/"}
+
+	pages, err := g.renderListPages(tmpl, nil, nil, site, "tags/go", contents, params, 2, SSGPageData{IsIndex: true})
+	if err != nil {
+		t.Fatalf("renderListPages failed: %v", err)
+	}
+	if pages != 3 {
+		t.Fatalf("pages = %d, want 3", pages)
+	}
+
+	want := map[int]string{
+		1: "1/3|2||/tags/go/page/2/|/tags/go/|/tags/go/page/3/|https://example.com/tags/go/",
+		2: "2/3|2|/tags/go/|/tags/go/page/3/|/tags/go/|/tags/go/page/3/|https://example.com/tags/go/",
+		3: "3/3|1|/tags/go/page/2/||/tags/go/|/tags/go/page/3/|https://example.com/tags/go/",
+	}
+	for page, expected := range want {
+		data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, "tags/go", page))
+		if err != nil {
+			t.Fatalf("page %d not written: %v", page, err)
+		}
+		if string(data) != expected {
+			t.Errorf("page %d = %q, want %q", page, string(data), expected)
+		}
+	}
+}
+
+func TestContentCanonicalURL(t *testing.T) {
+	g := &HTMLGenerator{}
+	rendered := &RenderedContent{Content: &Content{}, URL: "/blog/post-abc123/"}
+	params := map[string]string{BaseURLRefKey: "https://example.com"}
+
+	if got := g.contentCanonicalURL(rendered, params); got != "https://example.com/blog/post-abc123/" {
+		t.Errorf("auto canonical = %q", got)
+	}
+	if got := g.contentCanonicalURL(rendered, map[string]string{}); got != "/blog/post-abc123/" {
+		t.Errorf("auto canonical without base URL = %q", got)
+	}
+
+	rendered.Meta = &Meta{CanonicalURL: "https://medium.com/@jane/post"}
+	if got := g.contentCanonicalURL(rendered, params); got != "https://medium.com/@jane/post" {
+		t.Errorf("manual canonical = %q, want it verbatim", got)
+	}
+}
+
+func TestRenderTagPagesExcludesDrafts(t *testing.T) {
+	tmpDir := t.TempDir()
+	g := &HTMLGenerator{workspace: NewWorkspace(tmpDir), processor: NewProcessor()}
+	tmpl := template.Must(template.New("layout.html").Parse(`{{ .Tag.Name }}:{{ .TotalPages }}:{{ len .Contents }}`))
+
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	goTag := &Tag{ID: uuid.New(), Name: "Go", Slug: "go"}
+	draftTag := &Tag{ID: uuid.New(), Name: "Secret", Slug: "secret"}
+
+	past := time.Now().Add(-time.Hour)
+	var contents []*Content
+	for i := 0; i < 3; i++ {
+		contents = append(contents, &Content{ID: uuid.New(), ShortID: fmt.Sprintf("p%07d", i), Heading: "Post", PublishedAt: &past, Tags: []*Tag{goTag}})
+	}
+	for i := 0; i < 4; i++ {
+		contents = append(contents, &Content{ID: uuid.New(), ShortID: fmt.Sprintf("d%07d", i), Heading: "Draft", Draft: true, Tags: []*Tag{goTag, draftTag}})
+	}
+
+	count, paged, err := g.renderTagPages(tmpl, nil, nil, site, contents, nil, nil, map[string]string{"ssg.index.page_size": "2"})
+	if err != nil {
+		t.Fatalf("renderTagPages failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("tag count = %d, want 1 (draft-only tags are skipped)", count)
+	}
+	if paged != 1 {
+		t.Errorf("paginated pages = %d, want 1", paged)
+	}
+
+	data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, "tags/go", 1))
+	if err != nil {
+		t.Fatalf("tag index not written: %v", err)
+	}
+	if string(data) != "Go:2:2" {
+		t.Errorf("tag page = %q, want %q", string(data), "Go:2:2")
+	}
+	if _, err := os.Stat(g.workspace.GetPaginationHTMLPath(site.Slug, "tags/secret", 1)); err == nil {
+		t.Errorf("expected no page for a tag only used by drafts")
+	}
+}
+
+func TestRenderTagPageDescription(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	params := map[string]string{BaseURLRefKey: "https://example.com"}
+	tmpl, err := g.parseTemplates(g.siteTheme(site.Slug, params))
+	if err != nil {
+		t.Fatalf("parseTemplates() error = %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	render := func(tag *Tag) string {
+		contents := []*Content{{ID: uuid.New(), ShortID: "p0000001", Heading: "Post", PublishedAt: &past, Tags: []*Tag{tag}}}
+		if _, _, err := g.renderTagPages(tmpl, nil, nil, site, contents, nil, nil, params); err != nil {
+			t.Fatalf("renderTagPages() error = %v", err)
+		}
+		data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, "tags/"+tag.Slug, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	page := render(&Tag{ID: uuid.New(), Name: "Go", Slug: "go", Description: "Articles about the Go language.", HeaderImageURL: "/images/go.png"})
+	for _, want := range []string{
+		`<meta name="description" content="Articles about the Go language.">`,
+		`<meta property="og:description" content="Articles about the Go language.">`,
+		`<meta property="og:image" content="https://example.com/images/go.png">`,
+		`<p class="list-description">Articles about the Go language.</p>`,
+		`<img class="list-header-image" src="/images/go.png"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("tag page missing %s", want)
+		}
+	}
+
+	page = render(&Tag{ID: uuid.New(), Name: "Rust", Slug: "rust"})
+	if want := `<meta name="description" content="Posts tagged Rust on Test">`; !strings.Contains(page, want) {
+		t.Errorf("tag page missing %s", want)
+	}
+	if strings.Contains(page, "list-description") || strings.Contains(page, "og:image") {
+		t.Error("tag page without description or image should not render them")
+	}
+}
+
+func TestRenderIndexPagesPinsFeatured(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "Pins", Slug: "pins"}
+	blog := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	tmpl := template.Must(template.New("layout.html").Parse(`{{ range .Contents }}{{ .Heading }};{{ end }}`))
+
+	at := func(hours int) *time.Time {
+		t := time.Now().Add(-time.Duration(hours) * time.Hour)
+		return &t
+	}
+	contents := []*Content{
+		{ID: uuid.New(), SectionID: blog.ID, SectionPath: "blog", ShortID: "new00001", Heading: "Newest", PublishedAt: at(1)},
+		{ID: uuid.New(), SectionID: blog.ID, SectionPath: "blog", ShortID: "drf00001", Heading: "Featured draft", Featured: true, Draft: true, PublishedAt: at(2)},
+		{ID: uuid.New(), SectionID: blog.ID, SectionPath: "blog", ShortID: "mid00001", Heading: "Middle", PublishedAt: at(3)},
+		{ID: uuid.New(), SectionID: blog.ID, SectionPath: "blog", ShortID: "old00001", Heading: "Old featured", Featured: true, PublishedAt: at(48)},
+	}
+
+	read := func(listPath string, page int) string {
+		data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, listPath, page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	htmlPath := g.workspace.GetHTMLPath(site.Slug)
+	params := map[string]string{"ssg.index.page_size": "2"}
+	if _, _, err := g.renderIndexPages(tmpl, nil, nil, nil, htmlPath, site, contents, []*Section{blog}, nil, params); err != nil {
+		t.Fatalf("renderIndexPages() error = %v", err)
+	}
+	for _, listPath := range []string{"", "blog"} {
+		if got, want := read(listPath, 1)+read(listPath, 2), "Old featured;Newest;Middle;"; got != want {
+			t.Errorf("listing %q = %q, want %q", listPath, got, want)
+		}
+	}
+
+	params[FeaturedPinsRefKey] = "0"
+	if _, _, err := g.renderIndexPages(tmpl, nil, nil, nil, htmlPath, site, contents, []*Section{blog}, nil, params); err != nil {
+		t.Fatalf("renderIndexPages() error = %v", err)
+	}
+	if got, want := read("", 1)+read("", 2), "Newest;Middle;Old featured;"; got != want {
+		t.Errorf("listing without pins = %q, want %q", got, want)
+	}
+}
+
+func TestUnlistedContentRenderedButNotListed(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "Bench", Slug: "bench"}
+	blog := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	goTag := &Tag{ID: uuid.New(), Name: "Go", Slug: "go"}
+
+	older := time.Now().Add(-2 * time.Hour)
+	newer := time.Now().Add(-time.Hour)
+	public := &Content{ID: uuid.New(), SectionID: blog.ID, SectionPath: "blog", ShortID: "pub00001", Heading: "Public", PublishedAt: &newer, Visibility: VisibilityPublic, Tags: []*Tag{goTag}}
+	unlisted := &Content{ID: uuid.New(), SectionID: blog.ID, SectionPath: "blog", ShortID: "unl00001", Heading: "Unlisted", PublishedAt: &older, Visibility: VisibilityUnlisted, Tags: []*Tag{goTag}}
+	private := &Content{ID: uuid.New(), SectionID: blog.ID, SectionPath: "blog", ShortID: "prv00001", Heading: "Private", PublishedAt: &newer, Visibility: VisibilityPrivate, Tags: []*Tag{goTag}}
+	contents := []*Content{public, unlisted, private}
+
+	if !isPublishable(unlisted) || isListed(unlisted) {
+		t.Error("unlisted content should be published but not listed")
+	}
+	if isPublishable(private) {
+		t.Error("private content should not be published")
+	}
+
+	generated, errs := renderAllContentPages(t, g, []*Content{public, unlisted}, []*Section{blog})
+	if generated != 2 || len(errs) > 0 {
+		t.Fatalf("generated %d pages with errors %v, want 2", generated, errs)
+	}
+	page, err := os.ReadFile(g.workspace.GetContentHTMLPath(site.Slug, "blog", public.Slug()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(page), "prev") {
+		t.Error("unlisted content should not take part in prev/next navigation")
+	}
+
+	listTmpl := template.Must(template.New("layout.html").Parse(`{{ range .Contents }}{{ .Heading }};{{ end }}`))
+	htmlPath := g.workspace.GetHTMLPath(site.Slug)
+	if _, _, err := g.renderIndexPages(listTmpl, nil, nil, nil, htmlPath, site, contents, []*Section{blog}, nil, map[string]string{}); err != nil {
+		t.Fatalf("renderIndexPages() error = %v", err)
+	}
+	if _, _, err := g.renderTagPages(listTmpl, nil, nil, site, contents, nil, nil, map[string]string{}); err != nil {
+		t.Fatalf("renderTagPages() error = %v", err)
+	}
+	for _, listPath := range []string{"", "blog", "tags/go"} {
+		data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, listPath, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "Public;" {
+			t.Errorf("listing %q = %q, want only the public post", listPath, data)
+		}
+	}
+
+	if _, err := g.generateSitemap(htmlPath, "https://example.com", "/", site, contents, []*Section{blog}, nil); err != nil {
+		t.Fatalf("generateSitemap() error = %v", err)
+	}
+	sitemap, err := os.ReadFile(filepath.Join(htmlPath, "sitemap.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(sitemap), unlisted.ShortID) || strings.Contains(string(sitemap), private.ShortID) {
+		t.Error("sitemap should only list public content")
+	}
+}
+
+func TestRunParallel(t *testing.T) {
+	for _, workers := range []int{0, 1, 4, 100} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			const n = 50
+			var hits [n]int32
+			runParallel(n, workers, func(i int) {
+				atomic.AddInt32(&hits[i], 1)
+			})
+			for i, h := range hits {
+				if h != 1 {
+					t.Errorf("index %d visited %d times, want 1", i, h)
+				}
+			}
+		})
+	}
+}
+
+// newBenchContents returns n publishable posts spread across a few sections.
+func newBenchContents(n int, sections []*Section) []*Content {
+	published := time.Now().Add(-time.Hour)
+	body := strings.Repeat("Some **markdown** with a [link](https://example.com) and `code`.\n\n", 40)
+
+	contents := make([]*Content, n)
+	for i := range contents {
+		section := sections[i%len(sections)]
+		contents[i] = &Content{
+			ID:          uuid.New(),
+			SectionID:   section.ID,
+			SectionPath: section.Path,
+			ShortID:     fmt.Sprintf("%08d", i),
+			Kind:        "post",
+			Heading:     fmt.Sprintf("Post %d", i),
+			Body:        body,
+			PublishedAt: &published,
+		}
+	}
+	return contents
+}
+
+func renderAllContentPages(tb testing.TB, g *HTMLGenerator, contents []*Content, sections []*Section) (int, []string) {
+	tb.Helper()
+	tmpl := template.Must(template.New("layout.html").Parse(
+		`<h1>{{ .Content.Heading }}</h1>{{ .Content.HTMLBody }}{{ if .OlderContent }}<a href="{{ .OlderContent.URL }}">prev</a>{{ end }}`))
+	site := &Site{ID: uuid.New(), Name: "Bench", Slug: "bench"}
+	params := map[string]string{}
+
+	allRendered := g.preRenderAllContent(contents, "/", params)
+	templates := make(map[uuid.UUID]sectionTemplate)
+	for _, s := range sections {
+		templates[s.ID] = sectionTemplate{tmpl: tmpl}
+	}
+	return g.renderContentPages(templates, nil, nil, nil, g.workspace.GetHTMLPath(site.Slug), site, contents, sections, nil, params, allRendered, BlocksConfig{})
+}
+
+func TestRenderContentPagesParallelMatchesSequential(t *testing.T) {
+	sections := []*Section{
+		{ID: uuid.New(), Name: "Blog", Path: "blog"},
+		{ID: uuid.New(), Name: "Notes", Path: "notes"},
+	}
+	contents := newBenchContents(40, sections)
+
+	outputs := make(map[int]map[string]string)
+	for _, workers := range []int{1, 8} {
+		g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+		g.SetWorkers(workers)
+
+		generated, errs := renderAllContentPages(t, g, contents, sections)
+		if len(errs) > 0 {
+			t.Fatalf("workers=%d: unexpected errors: %v", workers, errs)
+		}
+		if generated != len(contents) {
+			t.Fatalf("workers=%d: generated %d pages, want %d", workers, generated, len(contents))
+		}
+
+		outputs[workers] = make(map[string]string)
+		for _, c := range contents {
+			data, err := os.ReadFile(g.workspace.GetContentHTMLPath("bench", c.SectionPath, c.Slug()))
+			if err != nil {
+				t.Fatalf("workers=%d: missing page for %s: %v", workers, c.Heading, err)
+			}
+			outputs[workers][c.Slug()] = string(data)
+		}
+	}
+
+	for slug, want := range outputs[1] {
+		if got := outputs[8][slug]; got != want {
+			t.Errorf("page %s differs between sequential and parallel rendering", slug)
+		}
+	}
+}
+
+func benchmarkRenderContentPages(b *testing.B, workers int) {
+	sections := []*Section{
+		{ID: uuid.New(), Name: "Blog", Path: "blog"},
+		{ID: uuid.New(), Name: "Notes", Path: "notes"},
+	}
+	contents := newBenchContents(500, sections)
+	g := &HTMLGenerator{workspace: NewWorkspace(b.TempDir()), processor: NewProcessor()}
+	g.SetWorkers(workers)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		renderAllContentPages(b, g, contents, sections)
+	}
+}
+
+func BenchmarkRenderContentPagesSequential(b *testing.B) {
+	benchmarkRenderContentPages(b, 1)
+}
+
+func BenchmarkRenderContentPagesParallel(b *testing.B) {
+	benchmarkRenderContentPages(b, 0)
+}
+
+func TestGetTemplateAndLayoutForSectionFallback(t *testing.T) {
+	g := &HTMLGenerator{}
+	embedded := template.Must(template.New("layout.html").Parse("embedded"))
+	sectionID := uuid.New()
+	sectionLayout := &Layout{ID: uuid.New(), Name: "Section", Code: "section"}
+	siteLayout := &Layout{ID: uuid.New(), Name: "Site", Code: "site"}
+	cssOnly := &Layout{ID: uuid.New(), Name: "CSS only", CSS: "body{}"}
+
+	render := func(tmpl *template.Template) string {
+		var b strings.Builder
+		if err := tmpl.ExecuteTemplate(&b, "layout.html", nil); err != nil {
+			t.Fatalf("ExecuteTemplate() error = %v", err)
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name       string
+		bySection  map[uuid.UUID]*Layout
+		siteLayout *Layout
+		wantOutput string
+		wantLayout string
+	}{
+		{"section layout", map[uuid.UUID]*Layout{sectionID: sectionLayout}, siteLayout, "section", "Section"},
+		{"site default", nil, siteLayout, "site", "Site"},
+		{"empty section layout skipped", map[uuid.UUID]*Layout{sectionID: {ID: uuid.New()}}, siteLayout, "site", "Site"},
+		{"css-only layout uses embedded templates", map[uuid.UUID]*Layout{sectionID: cssOnly}, siteLayout, "embedded", "CSS only"},
+		{"built-in when site has no layouts", nil, nil, "embedded", "Built-in"},
+		{"unparsable code uses embedded templates", nil, &Layout{ID: uuid.New(), Name: "Broken", Code: "{{ if }}"}, "embedded", "Broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, layout := g.getTemplateAndLayoutForSection(embedded, tt.bySection, tt.siteLayout, sectionID)
+			if layout == nil {
+				t.Fatal("expected a layout, got nil")
+			}
+			if layout.Name != tt.wantLayout {
+				t.Errorf("layout = %q, want %q", layout.Name, tt.wantLayout)
+			}
+			if got := render(tmpl); got != tt.wantOutput {
+				t.Errorf("output = %q, want %q", got, tt.wantOutput)
+			}
+		})
+	}
+}
+
+// parseSiteTemplatesFromDisk loads the shipped SSG templates; the embedded
+// copy lives in the main package and is not reachable from tests.
+func parseSiteTemplatesFromDisk(t *testing.T) *template.Template {
+	t.Helper()
+	tmpl, err := template.New("").Funcs(templateFuncMap()).ParseFiles("../../../assets/ssg/layout.html")
+	if err != nil {
+		t.Fatalf("cannot parse layout: %v", err)
+	}
+	tmpl, err = tmpl.ParseGlob("../../../assets/ssg/partials/*.html")
+	if err != nil {
+		t.Fatalf("cannot parse partials: %v", err)
+	}
+	return tmpl
+}
+
+func TestRenderAuthorPages(t *testing.T) {
+	tmpl := parseSiteTemplatesFromDisk(t)
+	site := &Site{ID: uuid.New(), Name: "Authors", Slug: "authors"}
+	published := time.Now().Add(-time.Hour)
+
+	editor := &Contributor{Handle: "ed", Name: "Ed", Role: ContributorRoleEditor, SocialLinks: []SocialLink{
+		{Platform: "github", URL: "https://github.com/ed"},
+		{Platform: "mastodon", Handle: "@ed"},
+	}}
+	guest := &Contributor{Handle: "gina", Name: "Gina", Role: ContributorRoleGuest}
+	idle := &Contributor{Handle: "idle", Name: "Idle", Role: ContributorRoleAuthor}
+	contents := []*Content{
+		{ID: uuid.New(), ShortID: "a1", Heading: "By Ed", SectionPath: "blog", ContributorHandle: "ed", PublishedAt: &published},
+		{ID: uuid.New(), ShortID: "a2", Heading: "By Gina", SectionPath: "blog", ContributorHandle: "gina", PublishedAt: &published},
+		{ID: uuid.New(), ShortID: "a3", Heading: "By a user", SectionPath: "blog", AuthorUsername: "admin", PublishedAt: &published},
+	}
+
+	render := func(params map[string]string) (*HTMLGenerator, int) {
+		g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+		count, _, err := g.renderAuthorPages(tmpl, nil, nil, g.workspace.GetHTMLPath(site.Slug), site, contents, []*Contributor{guest, idle, editor}, nil, nil, params)
+		if err != nil {
+			t.Fatalf("renderAuthorPages() error = %v", err)
+		}
+		return g, count
+	}
+	read := func(g *HTMLGenerator, path string) string {
+		data, err := os.ReadFile(g.workspace.GetIndexHTMLPath(site.Slug, path))
+		if err != nil {
+			t.Fatalf("cannot read %s: %v", path, err)
+		}
+		return string(data)
+	}
+
+	g, count := render(map[string]string{})
+	if count != 4 {
+		t.Errorf("count = %d, want 4 including the user author", count)
+	}
+
+	page := read(g, "authors/ed")
+	if !strings.Contains(page, `<a href="https://github.com/ed" class="social-link social-github" target="_blank" rel="noopener me"><svg class="social-icon"`) || !strings.Contains(page, `<span>GitHub</span></a>`) {
+		t.Errorf("expected the github link on the author page:\n%s", page)
+	}
+	if strings.Contains(page, "mastodon") {
+		t.Error("social links without a URL should not be rendered")
+	}
+	if !strings.Contains(page, "By Ed") || !strings.Contains(page, `authors/#editor">Editors</a>`) {
+		t.Errorf("expected the author's posts and role on the page:\n%s", page)
+	}
+
+	index := read(g, "authors")
+	var order []int
+	for _, title := range []string{"<h2>Editors</h2>", "<h2>Authors</h2>", "<h2>Guest Authors</h2>"} {
+		order = append(order, strings.Index(index, title))
+	}
+	if order[0] < 0 || order[0] > order[1] || order[1] > order[2] {
+		t.Errorf("expected role groups in order Editors, Authors, Guest Authors:\n%s", index)
+	}
+	if !strings.Contains(index, "@idle · 0 posts") || !strings.Contains(index, "@admin · 1 post<") {
+		t.Errorf("expected every author on the index:\n%s", index)
+	}
+
+	g, count = render(map[string]string{hideEmptyAuthorsRefKey: "true"})
+	if count != 3 {
+		t.Errorf("count with hide_empty = %d, want 3", count)
+	}
+	if _, err := os.Stat(g.workspace.GetIndexHTMLPath(site.Slug, "authors/idle")); !os.IsNotExist(err) {
+		t.Error("expected no page for an author without posts")
+	}
+	if strings.Contains(read(g, "authors"), "@idle") {
+		t.Error("expected the author without posts to be left out of the index")
+	}
+}
+
+func TestRenderContentPagesLayoutOrder(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	section := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	contents := newBenchContents(3, []*Section{section})
+	contents[1].Kind = KindNote
+	contentLayout := &Layout{ID: uuid.New(), Name: "Content", Code: `{{ define "layout.html" }}content{{ end }}`}
+	contents[2].Kind = KindNote
+	contents[2].LayoutID = contentLayout.ID
+
+	parse := func(out string) sectionTemplate {
+		return sectionTemplate{tmpl: template.Must(template.New("layout.html").Parse(out)), layout: BuiltinLayout()}
+	}
+	templates := map[uuid.UUID]sectionTemplate{section.ID: parse("section")}
+	kindTemplates := map[string]sectionTemplate{KindNote: parse("kind")}
+	contentTemplates := g.resolveContentTemplates(nil, contents, []*Layout{contentLayout})
+
+	site := &Site{ID: uuid.New(), Name: "Order", Slug: "order"}
+	params := map[string]string{}
+	allRendered := g.preRenderAllContent(contents, "/", params)
+	if _, errs := g.renderContentPages(templates, kindTemplates, contentTemplates, nil, g.workspace.GetHTMLPath(site.Slug), site, contents, []*Section{section}, nil, params, allRendered, BlocksConfig{}); len(errs) > 0 {
+		t.Fatalf("renderContentPages() errors = %v", errs)
+	}
+
+	for i, want := range []string{"section", "kind", "content"} {
+		c := contents[i]
+		data, err := os.ReadFile(g.workspace.GetContentHTMLPath(site.Slug, c.SectionPath, c.Slug()))
+		if err != nil {
+			t.Fatalf("missing page for %s: %v", c.Heading, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s rendered with %q, want the %s layout", c.Heading, data, want)
+		}
+	}
+}
+
+func TestRenderContentPageLayoutCSS(t *testing.T) {
+	tmpl := parseSiteTemplatesFromDisk(t)
+	site := &Site{ID: uuid.New(), Name: "Styled", Slug: "styled"}
+	section := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	published := time.Now().Add(-time.Hour)
+	content := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, SectionPath: "blog", Kind: "post", Heading: "Styled post", Body: "Body", PublishedAt: &published}
+
+	tests := []struct {
+		name      string
+		layout    *Layout
+		wantTheme bool
+		wantCSS   string
+	}{
+		{"default stylesheet kept", &Layout{CSS: "h1 { color: red; }"}, true, "<style>h1 { color: red; }</style>"},
+		{"default stylesheet excluded", &Layout{CSS: "body > h1 { color: blue; }", ExcludeDefaultCSS: true}, false, "<style>body > h1 { color: blue; }</style>"},
+		{"built-in layout", BuiltinLayout(), true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+			htmlPath := g.workspace.GetHTMLPath(site.Slug)
+			if _, err := g.renderContentPage(tmpl, tt.layout, nil, htmlPath, site, content, nil, adjacentLinks{}, []*Section{section}, nil, map[string]string{}, nil, BlocksConfig{}); err != nil {
+				t.Fatalf("renderContentPage() error = %v", err)
+			}
+
+			out, err := os.ReadFile(g.workspace.GetContentHTMLPath(site.Slug, content.SectionPath, content.Slug()))
+			if err != nil {
+				t.Fatalf("cannot read output: %v", err)
+			}
+			html := string(out)
+
+			if !strings.Contains(html, "static/css/core.css") {
+				t.Error("expected core stylesheet link")
+			}
+			if got := strings.Contains(html, "static/css/theme.css"); got != tt.wantTheme {
+				t.Errorf("theme stylesheet linked = %v, want %v", got, tt.wantTheme)
+			}
+			if tt.wantCSS != "" && !strings.Contains(html, tt.wantCSS) {
+				t.Errorf("expected custom CSS %q in output", tt.wantCSS)
+			}
+			if tt.wantCSS == "" && strings.Contains(html, "<style>") {
+				t.Error("expected no custom style block")
+			}
+		})
+	}
+}
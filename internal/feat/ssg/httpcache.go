@@ -0,0 +1,72 @@
+package ssg
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/config"
+)
+
+// DefaultCacheMaxAge is how long browsers may reuse preview images and assets
+// without asking again when ssg.preview.cache_max_age is empty.
+const DefaultCacheMaxAge = time.Minute
+
+// immutableMaxAge is the lifetime of fingerprinted assets, whose name changes
+// with their content.
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// ParseCacheMaxAge parses ssg.preview.cache_max_age. Zero makes browsers
+// check every file again on each use.
+func ParseCacheMaxAge(cfg config.PreviewConfig) (time.Duration, error) {
+	if cfg.CacheMaxAge == "" {
+		return DefaultCacheMaxAge, nil
+	}
+	d, err := time.ParseDuration(cfg.CacheMaxAge)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative, got %s", d)
+	}
+	return d, nil
+}
+
+// fingerprintedRe matches names made by fingerprintName, such as
+// theme.3f9a1c2e.css.
+var fingerprintedRe = regexp.MustCompile(`\.[0-9a-f]{8}\.[A-Za-z0-9]+$`)
+
+// cacheControl returns the Cache-Control value for a file: a year for
+// fingerprinted assets, maxAge for the rest. Files served behind auth are
+// kept out of shared caches.
+func cacheControl(name string, maxAge time.Duration, private bool) string {
+	scope := "public"
+	if private {
+		scope = "private"
+	}
+	if fingerprintedRe.MatchString(filepath.Base(name)) {
+		return fmt.Sprintf("%s, max-age=%d, immutable", scope, int(immutableMaxAge.Seconds()))
+	}
+	if maxAge <= 0 {
+		return scope + ", no-cache"
+	}
+	return fmt.Sprintf("%s, max-age=%d", scope, int(maxAge.Seconds()))
+}
+
+// fileETag derives a strong validator from the file's size and modification
+// time, which change whenever the file is written again.
+func fileETag(info os.FileInfo) string {
+	return fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size())
+}
+
+// serveCachedFile serves path with an ETag and the given Cache-Control.
+// http.ServeFile adds Last-Modified and answers conditional and range
+// requests from them.
+func serveCachedFile(w http.ResponseWriter, r *http.Request, path string, info os.FileInfo, cacheControl string) {
+	w.Header().Set("ETag", fileETag(info))
+	w.Header().Set("Cache-Control", cacheControl)
+	http.ServeFile(w, r, path)
+}
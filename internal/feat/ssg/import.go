@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -114,6 +115,16 @@ func (s *ImportScanner) parseFile(path string, info os.FileInfo) (*ImportFile, e
 	// Parse frontmatter and body
 	frontmatter, body := parseFrontmatter(string(content))
 
+	// Files in the backup format are also parsed into typed fields, keeping
+	// tags, dates and the exact body.
+	parsed, typedBody, err := UnmarshalContentMarkdown(string(content))
+	if err != nil {
+		parsed = nil // Not in the backup format, the untyped frontmatter still applies
+	}
+	if parsed != nil {
+		body = typedBody
+	}
+
 	// Extract title from frontmatter or first H1
 	title := ""
 	if fm, ok := frontmatter["title"]; ok {
@@ -126,6 +137,9 @@ func (s *ImportScanner) parseFile(path string, info os.FileInfo) (*ImportFile, e
 	if title == "" {
 		title = strings.TrimSuffix(info.Name(), ".md")
 	}
+	if parsed != nil {
+		parsed.Title = title
+	}
 
 	return &ImportFile{
 		Path:        path,
@@ -135,6 +149,7 @@ func (s *ImportScanner) parseFile(path string, info os.FileInfo) (*ImportFile, e
 		Title:       title,
 		Body:        body,
 		Frontmatter: frontmatter,
+		Parsed:      parsed,
 	}, nil
 }
 
@@ -265,25 +280,198 @@ func extractFirstH1(content string) string {
 	return ""
 }
 
-// ComputeImportStatus determines the status of an import based on timestamps.
-func ComputeImportStatus(imp *Import, fileMtime time.Time) string {
+// quickImportUntitled titles pasted Markdown that has no title of its own.
+const quickImportUntitled = "Untitled"
+
+// ParseQuickImport reads Markdown pasted for a quick import. Frontmatter, when
+// present, is parsed as in the Markdown backup. The title comes from its title
+// field or else the first H1, which is then dropped from the body so it is not
+// shown twice. Frontmatter is never nil.
+func ParseQuickImport(markdown string) (*ContentFrontmatter, string, error) {
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	fm, body, err := UnmarshalContentMarkdown(markdown)
+	if err != nil {
+		return nil, "", err
+	}
+	if fm == nil {
+		fm = &ContentFrontmatter{}
+	}
+	fm.Title = strings.TrimSpace(fm.Title)
+
+	if fm.Title == "" {
+		if loc := h1Regex.FindStringSubmatchIndex(body); loc != nil {
+			fm.Title = strings.TrimSpace(body[loc[2]:loc[3]])
+			body = body[:loc[0]] + strings.TrimLeft(body[loc[1]:], "\n")
+		}
+	}
+	if fm.Title == "" {
+		fm.Title = quickImportUntitled
+	}
+
+	return fm, strings.TrimSpace(body), nil
+}
+
+// ComputeImportStatus determines the status of an import from its scanned
+// file: updated when the file changed since the last import, conflict when
+// the content was also edited in Clio since then.
+func ComputeImportStatus(imp *Import, file ImportFile) string {
 	// If no content yet, it's pending
 	if imp.ContentID == nil {
 		return ImportStatusPending
 	}
 
-	// File was modified after last import
-	if imp.FileMtime != nil && fileMtime.After(*imp.FileMtime) {
-		// Check if content was also modified in web UI AFTER the import
-		if imp.ContentUpdatedAt != nil && imp.ImportedAt != nil && imp.ContentUpdatedAt.After(*imp.ImportedAt) {
-			// Both file and content were modified - conflict
-			return ImportStatusConflict
+	if !importFileChanged(imp, file) {
+		return ImportStatusSynced
+	}
+	if imp.ContentUpdatedAt != nil && importContentChanged(imp, *imp.ContentUpdatedAt) {
+		return ImportStatusConflict
+	}
+	return ImportStatusUpdated
+}
+
+// importFileChanged reports whether the file differs from the one last
+// imported. The hash is compared when recorded, so touching a file or
+// checking it out again does not count as a change.
+func importFileChanged(imp *Import, file ImportFile) bool {
+	if imp.FileHash != "" && file.Hash != "" {
+		return file.Hash != imp.FileHash
+	}
+	return imp.FileMtime != nil && file.Mtime.After(*imp.FileMtime)
+}
+
+// importContentChanged reports whether the content was edited after it was
+// last imported.
+func importContentChanged(imp *Import, contentUpdatedAt time.Time) bool {
+	return imp.ImportedAt != nil && contentUpdatedAt.After(*imp.ImportedAt)
+}
+
+// applyImportFile copies what a reimport takes from the file into content.
+func applyImportFile(content *Content, file *ImportFile) {
+	content.Heading = file.Title
+	content.Body = file.Body
+
+	if len(file.Frontmatter) == 0 {
+		return
+	}
+	fm := importFrontmatter(*file)
+	if fm.Summary != "" {
+		content.Summary = fm.Summary
+	}
+	if fm.Kind != "" {
+		content.Kind = fm.Kind
+	}
+	content.Draft = fm.Draft
+	content.Featured = fm.Featured
+	content.Visibility = normalizeVisibility(fm.Visibility)
+	if fm.Series != "" {
+		content.Series = fm.Series
+		content.SeriesOrder = fm.SeriesOrder
+	}
+	if fm.Lang != "" {
+		content.Lang = fm.Lang
+	}
+}
+
+// ImportDiff compares imported content with its file on disk.
+type ImportDiff struct {
+	Import         *Import
+	Content        *Content
+	FileChanged    bool // the file changed since the last import
+	ContentChanged bool // the content was edited in Clio since the last import
+	Fields         []ImportFieldDiff
+}
+
+// ImportFieldDiff is a field a reimport would change.
+type ImportFieldDiff struct {
+	Field    string
+	Current  string // value in Clio
+	Incoming string // value from the file
+	Changes  string // line counts for the body, e.g. "3 lines added, 1 removed"
+}
+
+// Conflict reports whether both sides changed, so a reimport would discard
+// edits made in Clio.
+func (d *ImportDiff) Conflict() bool {
+	return d.FileChanged && d.ContentChanged
+}
+
+// Summary describes the differences in one line.
+func (d *ImportDiff) Summary() string {
+	if len(d.Fields) == 0 {
+		return "no differences"
+	}
+	parts := make([]string, len(d.Fields))
+	for i, f := range d.Fields {
+		parts[i] = f.Field
+		if f.Changes != "" {
+			parts[i] += " (" + f.Changes + ")"
 		}
-		// Only file was modified - can reimport
-		return ImportStatusUpdated
 	}
+	return "differs in " + strings.Join(parts, ", ")
+}
 
-	return ImportStatusSynced
+// diffImport compares content with what reimporting file would make of it.
+func diffImport(imp *Import, content *Content, file *ImportFile) *ImportDiff {
+	incoming := *content
+	applyImportFile(&incoming, file)
+
+	diff := &ImportDiff{
+		Import:         imp,
+		Content:        content,
+		FileChanged:    importFileChanged(imp, *file),
+		ContentChanged: importContentChanged(imp, content.UpdatedAt),
+	}
+	fields := []ImportFieldDiff{
+		{Field: "Title", Current: content.Heading, Incoming: incoming.Heading},
+		{Field: "Summary", Current: content.Summary, Incoming: incoming.Summary},
+		{Field: "Kind", Current: content.Kind, Incoming: incoming.Kind},
+		{Field: "Language", Current: content.Lang, Incoming: incoming.Lang},
+		{Field: "Draft", Current: strconv.FormatBool(content.Draft), Incoming: strconv.FormatBool(incoming.Draft)},
+		{Field: "Featured", Current: strconv.FormatBool(content.Featured), Incoming: strconv.FormatBool(incoming.Featured)},
+		{Field: "Visibility", Current: content.Visibility, Incoming: incoming.Visibility},
+		{Field: "Series", Current: content.Series, Incoming: incoming.Series},
+		{Field: "Series order", Current: strconv.Itoa(content.SeriesOrder), Incoming: strconv.Itoa(incoming.SeriesOrder)},
+		{Field: "Body", Current: content.Body, Incoming: incoming.Body},
+	}
+	for _, f := range fields {
+		if f.Current == f.Incoming {
+			continue
+		}
+		if f.Field == "Body" {
+			f.Changes = lineChanges(f.Current, f.Incoming)
+		}
+		diff.Fields = append(diff.Fields, f)
+	}
+	return diff
+}
+
+// lineChanges counts the lines only in incoming and only in current,
+// ignoring order.
+func lineChanges(current, incoming string) string {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(current, "\n") {
+		counts[line]++
+	}
+	added := 0
+	for _, line := range strings.Split(incoming, "\n") {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+	removed := 0
+	for _, n := range counts {
+		removed += n
+	}
+	return fmt.Sprintf("%d %s added, %d removed", added, plural(added, "line", "lines"), removed)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
 }
 
 // ParseImportFrontmatter parses frontmatter into a ContentFrontmatter struct.
@@ -299,6 +487,9 @@ func ParseImportFrontmatter(fm map[string]string) *ContentFrontmatter {
 	if v, ok := fm["short-id"]; ok {
 		cf.ShortID = v
 	}
+	if v, ok := fm["section"]; ok {
+		cf.Section = v
+	}
 	if v, ok := fm["author"]; ok {
 		cf.Author = v
 	}
@@ -314,6 +505,9 @@ func ParseImportFrontmatter(fm map[string]string) *ContentFrontmatter {
 	if v, ok := fm["featured"]; ok {
 		cf.Featured = v == "true"
 	}
+	if v, ok := fm["visibility"]; ok {
+		cf.Visibility = v
+	}
 	if v, ok := fm["summary"]; ok {
 		cf.Summary = v
 	}
@@ -347,12 +541,24 @@ func ParseImportFrontmatter(fm map[string]string) *ContentFrontmatter {
 	if v, ok := fm["share"]; ok {
 		cf.Share = v == "true"
 	}
+	if v, ok := fm["keep-links"]; ok {
+		cf.KeepLinks = v == "true"
+	}
+	if v, ok := fm["show-updated"]; ok {
+		cf.ShowUpdated = v == "true"
+	}
 	if v, ok := fm["kind"]; ok {
 		cf.Kind = v
 	}
 	if v, ok := fm["series"]; ok {
 		cf.Series = v
 	}
+	if v, ok := fm["series-order"]; ok {
+		cf.SeriesOrder, _ = strconv.Atoi(v)
+	}
+	if v, ok := fm["lang"]; ok {
+		cf.Lang = v
+	}
 
 	return cf
 }
@@ -395,9 +601,12 @@ type ImportFrontmatter struct {
 	TableOfContents bool       `yaml:"table-of-contents"`
 	Comments        bool       `yaml:"comments"`
 	Share           bool       `yaml:"share"`
+	KeepLinks       bool       `yaml:"keep-links"`
+	ShowUpdated     bool       `yaml:"show-updated"`
 	Kind            string     `yaml:"kind"`
 	Series          string     `yaml:"series"`
 	SeriesOrder     int        `yaml:"series-order"`
+	Lang            string     `yaml:"lang"`
 }
 
 // ParseTypedFrontmatter extracts typed YAML frontmatter from markdown content.
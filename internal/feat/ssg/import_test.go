@@ -1,6 +1,7 @@
 package ssg
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -324,6 +325,65 @@ func TestExtractFirstH1(t *testing.T) {
 	}
 }
 
+func TestParseQuickImport(t *testing.T) {
+	tests := []struct {
+		name      string
+		markdown  string
+		wantTitle string
+		wantTags  []string
+		wantBody  string
+	}{
+		{
+			name:      "frontmatter title and tags",
+			markdown:  "---\ntitle: From Frontmatter\ntags: [go, notes]\n---\n\n# Heading Kept\n\nBody text",
+			wantTitle: "From Frontmatter",
+			wantTags:  []string{"go", "notes"},
+			wantBody:  "# Heading Kept\n\nBody text",
+		},
+		{
+			name:      "frontmatter without title",
+			markdown:  "---\ntags:\n  - reading\n---\n\n# Reading List\n\n- A book",
+			wantTitle: "Reading List",
+			wantTags:  []string{"reading"},
+			wantBody:  "- A book",
+		},
+		{
+			name:      "h1 without frontmatter",
+			markdown:  "Intro line\n\n# Later Title\n\nRest\r\n",
+			wantTitle: "Later Title",
+			wantBody:  "Intro line\n\nRest",
+		},
+		{
+			name:      "no title",
+			markdown:  "## Only a subheading\n\nText",
+			wantTitle: quickImportUntitled,
+			wantBody:  "## Only a subheading\n\nText",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := ParseQuickImport(tt.markdown)
+			if err != nil {
+				t.Fatalf("ParseQuickImport() error = %v", err)
+			}
+			if fm.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", fm.Title, tt.wantTitle)
+			}
+			if strings.Join(fm.Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("tags = %v, want %v", fm.Tags, tt.wantTags)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+
+	if _, _, err := ParseQuickImport("---\ntags: [unclosed\n---\n\nBody"); err == nil {
+		t.Error("ParseQuickImport() with invalid frontmatter should fail")
+	}
+}
+
 func TestExtractImagePaths(t *testing.T) {
 	tests := []struct {
 		name string
@@ -429,10 +489,10 @@ func TestComputeImportStatus(t *testing.T) {
 	future := now.Add(time.Hour)
 
 	tests := []struct {
-		name      string
-		imp       *Import
-		fileMtime time.Time
-		want      string
+		name string
+		imp  *Import
+		file ImportFile
+		want string
 	}{
 		{
 			name: "no content - pending",
@@ -445,8 +505,8 @@ func TestComputeImportStatus(t *testing.T) {
 				ContentID: ptrUUID(),
 				FileMtime: &past,
 			},
-			want:      ImportStatusSynced,
-			fileMtime: past,
+			want: ImportStatusSynced,
+			file: ImportFile{Mtime: past},
 		},
 		{
 			name: "file modified - updated",
@@ -454,14 +514,36 @@ func TestComputeImportStatus(t *testing.T) {
 				ContentID: ptrUUID(),
 				FileMtime: &past,
 			},
-			want:      ImportStatusUpdated,
-			fileMtime: future,
+			want: ImportStatusUpdated,
+			file: ImportFile{Mtime: future},
+		},
+		{
+			name: "file touched with same hash - synced",
+			imp: &Import{
+				ContentID: ptrUUID(),
+				FileHash:  "abc",
+				FileMtime: &past,
+			},
+			want: ImportStatusSynced,
+			file: ImportFile{Hash: "abc", Mtime: future},
+		},
+		{
+			name: "file and content modified - conflict",
+			imp: &Import{
+				ContentID:        ptrUUID(),
+				FileHash:         "abc",
+				FileMtime:        &past,
+				ImportedAt:       &past,
+				ContentUpdatedAt: &now,
+			},
+			want: ImportStatusConflict,
+			file: ImportFile{Hash: "def", Mtime: past},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ComputeImportStatus(tt.imp, tt.fileMtime)
+			got := ComputeImportStatus(tt.imp, tt.file)
 			if got != tt.want {
 				t.Errorf("ComputeImportStatus() = %v, want %v", got, tt.want)
 			}
@@ -469,6 +551,42 @@ func TestComputeImportStatus(t *testing.T) {
 	}
 }
 
+func TestDiffImport(t *testing.T) {
+	imported := time.Now().Add(-time.Hour)
+	imp := &Import{FileHash: "old", ImportedAt: &imported}
+	content := &Content{Heading: "Title", Body: "one\ntwo\nthree", Kind: "post", Visibility: VisibilityPublic, UpdatedAt: time.Now()}
+	file := &ImportFile{
+		Hash:        "new",
+		Title:       "New Title",
+		Body:        "one\nthree\nfour\nfive",
+		Frontmatter: map[string]string{"title": "New Title", "draft": "true"},
+	}
+
+	diff := diffImport(imp, content, file)
+	if !diff.FileChanged || !diff.ContentChanged || !diff.Conflict() {
+		t.Errorf("diff sides = file %v, content %v, want both changed", diff.FileChanged, diff.ContentChanged)
+	}
+
+	var fields []string
+	for _, f := range diff.Fields {
+		fields = append(fields, f.Field)
+	}
+	if strings.Join(fields, ",") != "Title,Draft,Body" {
+		t.Errorf("changed fields = %v, want Title, Draft and Body", fields)
+	}
+	if want := "differs in Title, Draft, Body (2 lines added, 1 removed)"; diff.Summary() != want {
+		t.Errorf("Summary() = %q, want %q", diff.Summary(), want)
+	}
+	if content.Heading != "Title" {
+		t.Error("diffImport() must not modify the content")
+	}
+
+	imp.FileHash = "new"
+	if diffImport(imp, content, file).Conflict() {
+		t.Error("an unchanged file is not a conflict")
+	}
+}
+
 func TestGetImportPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,453 @@
+package ssg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutputCleanRefKey is the setting that empties the output directory before
+// every full build. When off, a build only removes the files the previous one
+// generated and this one no longer does, so files added by hand are kept.
+const OutputCleanRefKey = "ssg.output.clean"
+
+func outputCleanEnabled(params map[string]string) bool {
+	return params[OutputCleanRefKey] != "false"
+}
+
+// buildManifestVersion is bumped whenever the hashed inputs change shape,
+// so manifests written by older versions trigger a full rebuild.
+const buildManifestVersion = 1
+
+// BuildManifest records what was generated for a site so the next run can
+// skip pages whose inputs have not changed and remove files no longer
+// generated. Copied and fixed files (assets, images, sitemap) have no hash.
+type BuildManifest struct {
+	Version    int                      `json:"version"`
+	GlobalHash string                   `json:"global_hash"`
+	Pages      map[string]ManifestEntry `json:"pages"`
+	// Permalinks maps content IDs to the path each was generated at, and
+	// Redirects maps former paths to the content now living elsewhere.
+	Permalinks map[string]string `json:"permalinks,omitempty"`
+	Redirects  map[string]string `json:"redirects,omitempty"`
+}
+
+// ManifestEntry describes a generated page, keyed by its path relative to the HTML output.
+type ManifestEntry struct {
+	Hash      string    `json:"hash"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// buildState tracks page hashes during a single generation run.
+// It is safe for concurrent use by the page rendering workers.
+type buildState struct {
+	htmlPath    string
+	incremental bool
+	previous    map[string]ManifestEntry // pages that may be kept, on incremental runs only
+
+	// Files the previous run generated, and the ones removed by cleaning
+	// the output before this run, to report what is no longer generated.
+	previousFiles map[string]ManifestEntry
+	cleaned       map[string]bool
+
+	// Content paths are carried across full rebuilds too, so a permalink
+	// pattern change can redirect from the old paths.
+	previousPermalinks map[string]string
+	previousRedirects  map[string]string
+	permalinks         map[string]string
+	redirectPaths      map[string]string
+
+	mu      sync.Mutex
+	current map[string]ManifestEntry
+	skipped atomic.Int64
+
+	// Most content pages rendered and social images drawn at once.
+	pageWorkers  concurrencyGauge
+	imageWorkers concurrencyGauge
+}
+
+// newBuildState prepares a run against the previous manifest. The run is
+// incremental only when not forced and the shared inputs hash is unchanged.
+func newBuildState(htmlPath string, previous *BuildManifest, globalHash string, force bool) *buildState {
+	b := &buildState{
+		htmlPath: htmlPath,
+		current:  make(map[string]ManifestEntry),
+	}
+	if !force && previous != nil && previous.Version == buildManifestVersion && previous.GlobalHash == globalHash {
+		b.incremental = true
+		b.previous = previous.Pages
+	}
+	if previous != nil {
+		b.previousFiles = previous.Pages
+		b.previousPermalinks = previous.Permalinks
+		b.previousRedirects = previous.Redirects
+	}
+	return b
+}
+
+// pageGauge and imageGauge measure the run's concurrency, see
+// GenerateHTMLResult.PeakWorkers. They are nil for a nil run.
+func (b *buildState) pageGauge() *concurrencyGauge {
+	if b == nil {
+		return nil
+	}
+	return &b.pageWorkers
+}
+
+func (b *buildState) imageGauge() *concurrencyGauge {
+	if b == nil {
+		return nil
+	}
+	return &b.imageWorkers
+}
+
+// unchanged reports whether a page's previous output can be kept as is.
+// Kept pages are carried over to the new manifest.
+func (b *buildState) unchanged(outputPath, hash string, updatedAt time.Time) bool {
+	if b == nil || !b.incremental {
+		return false
+	}
+
+	rel, err := filepath.Rel(b.htmlPath, outputPath)
+	if err != nil {
+		return false
+	}
+	prev, ok := b.previous[rel]
+	if !ok || prev.Hash != hash {
+		return false
+	}
+	if _, err := os.Stat(outputPath); err != nil {
+		return false
+	}
+
+	b.record(outputPath, hash, updatedAt)
+	b.skipped.Add(1)
+	return true
+}
+
+// record adds a successfully written page to the new manifest.
+func (b *buildState) record(outputPath, hash string, updatedAt time.Time) {
+	if b == nil {
+		return
+	}
+	rel, err := filepath.Rel(b.htmlPath, outputPath)
+	if err != nil {
+		return
+	}
+	b.mu.Lock()
+	b.current[rel] = ManifestEntry{Hash: hash, UpdatedAt: updatedAt}
+	b.mu.Unlock()
+}
+
+// clean empties the output directory before a full build, keeping track of
+// what it removed. It refuses to run when the output directory overlaps
+// imagesPath, the site images source, e.g. through a symlink.
+func (b *buildState) clean(imagesPath string) error {
+	if overlaps(b.htmlPath, imagesPath) {
+		return fmt.Errorf("output directory %s overlaps the site images, not cleaning it", b.htmlPath)
+	}
+	b.cleaned = make(map[string]bool)
+	_ = filepath.WalkDir(b.htmlPath, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if rel, err := filepath.Rel(b.htmlPath, path); err == nil {
+				b.cleaned[rel] = true
+			}
+		}
+		return nil
+	})
+	return CleanDir(b.htmlPath)
+}
+
+// removeStale deletes the files generated by the previous run that were not
+// produced by this one, e.g. deleted content or renamed slugs, along with the
+// directories left empty. It returns how many files are gone for good,
+// counting those already removed by clean.
+func (b *buildState) removeStale() int {
+	if b == nil {
+		return 0
+	}
+	removed := 0
+	for rel := range b.cleaned {
+		if _, ok := b.current[rel]; !ok {
+			removed++
+		}
+	}
+	for rel := range b.previousFiles {
+		if _, ok := b.current[rel]; ok || b.cleaned[rel] || !filepath.IsLocal(rel) {
+			continue
+		}
+		path := filepath.Join(b.htmlPath, rel)
+		if err := os.Remove(path); err != nil {
+			continue
+		}
+		removed++
+		for dir := filepath.Dir(path); dir != b.htmlPath && strings.HasPrefix(dir, b.htmlPath); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return removed
+}
+
+// overlaps reports whether either path is, or is inside, the other, once
+// symlinks are resolved.
+func overlaps(a, b string) bool {
+	resolve := func(p string) string {
+		if r, err := filepath.EvalSymlinks(p); err == nil {
+			p = r
+		}
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		return p
+	}
+	a, b = resolve(a), resolve(b)
+	within := func(parent, child string) bool {
+		rel, err := filepath.Rel(parent, child)
+		return err == nil && filepath.IsLocal(rel)
+	}
+	return within(a, b) || within(b, a)
+}
+
+// generated reports whether a page was written or kept by this run.
+func (b *buildState) generated(outputPath string) bool {
+	rel, err := filepath.Rel(b.htmlPath, outputPath)
+	if err != nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.current[rel]
+	return ok
+}
+
+// pageFiles returns the .html files written or kept by this run other than
+// index pages, sorted.
+func (b *buildState) pageFiles() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var files []string
+	for rel := range b.current {
+		if strings.HasSuffix(rel, ".html") && filepath.Base(rel) != "index.html" {
+			files = append(files, filepath.Join(b.htmlPath, rel))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// redirects returns the former paths still worth redirecting, keyed by path,
+// given the paths content is generated at in this run.
+func (b *buildState) redirects(permalinks map[uuid.UUID]string) map[string]uuid.UUID {
+	current := make(map[string]bool, len(permalinks))
+	for _, path := range permalinks {
+		current[path] = true
+	}
+
+	redirects := make(map[string]uuid.UUID)
+	add := func(path, rawID string) {
+		id, err := uuid.Parse(rawID)
+		if err != nil || current[path] {
+			return
+		}
+		if _, ok := permalinks[id]; ok {
+			redirects[path] = id
+		}
+	}
+	for path, id := range b.previousRedirects {
+		add(path, id)
+	}
+	for id, path := range b.previousPermalinks {
+		add(path, id)
+	}
+	return redirects
+}
+
+// setPermalinks stores the content paths and redirects for the new manifest.
+func (b *buildState) setPermalinks(permalinks map[uuid.UUID]string, redirects map[string]uuid.UUID) {
+	b.permalinks = make(map[string]string, len(permalinks))
+	for id, path := range permalinks {
+		b.permalinks[id.String()] = path
+	}
+	b.redirectPaths = make(map[string]string, len(redirects))
+	for path, id := range redirects {
+		b.redirectPaths[path] = id.String()
+	}
+}
+
+func (b *buildState) manifest(globalHash string) *BuildManifest {
+	return &BuildManifest{
+		Version:    buildManifestVersion,
+		GlobalHash: globalHash,
+		Pages:      b.current,
+		Permalinks: b.permalinks,
+		Redirects:  b.redirectPaths,
+	}
+}
+
+// loadBuildManifest reads a manifest, returning nil when missing or unreadable.
+func loadBuildManifest(path string) *BuildManifest {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var m BuildManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return &m
+}
+
+func saveBuildManifest(path string, m *BuildManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := EnsureDir(path); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// hashInputs returns a stable hash of the JSON encoding of v.
+func hashInputs(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Unhashable input: use a unique value so the page is always rebuilt.
+		data = []byte(uuid.NewString())
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// globalBuildHash covers inputs shared by every page. When any of them
+// changes, all pages are rebuilt.
+func (g *HTMLGenerator) globalBuildHash(site *Site, sections []*Section, layouts []*Layout, kinds []*ContentKind, params map[string]string, contributors []*Contributor, userAuthors map[string]*Contributor, theme *Theme) string {
+	type layoutInputs struct {
+		ID                uuid.UUID
+		Code              string
+		CSS               string
+		ExcludeDefaultCSS bool
+		HeaderImageID     uuid.UUID
+	}
+	layoutsIn := make([]layoutInputs, 0, len(layouts))
+	for _, l := range layouts {
+		layoutsIn = append(layoutsIn, layoutInputs{l.ID, l.Code, l.CSS, l.ExcludeDefaultCSS, l.HeaderImageID})
+	}
+
+	return hashInputs(struct {
+		SiteName        string
+		SiteSlug        string
+		DefaultLayoutID uuid.UUID
+		Sections        []*Section
+		Layouts         []layoutInputs
+		Kinds           []*ContentKind
+		Params          map[string]string
+		Contributors    []*Contributor
+		UserAuthors     map[string]*Contributor
+		Templates       string
+	}{site.Name, site.Slug, site.DefaultLayoutID, sections, layoutsIn, kinds, params, contributors, userAuthors, g.templatesHash(theme)})
+}
+
+// templatesHash fingerprints the templates and static assets of a theme.
+func (g *HTMLGenerator) templatesHash(theme *Theme) string {
+	h := sha256.New()
+	_ = fs.WalkDir(theme.fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		data, err := fs.ReadFile(theme.fsys, path)
+		if err != nil {
+			return nil
+		}
+		h.Write([]byte(path))
+		h.Write(data)
+		return nil
+	})
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pageRef is the part of a linked page that shows up in another page's output.
+type pageRef struct {
+	ID      uuid.UUID
+	Heading string
+	Summary string
+	URL     string
+}
+
+func refOf(r *RenderedContent) *pageRef {
+	if r == nil {
+		return nil
+	}
+	return &pageRef{ID: r.ID, Heading: r.Heading, Summary: r.Summary, URL: r.URL}
+}
+
+func refsOf(items []RenderedContent) []*pageRef {
+	refs := make([]*pageRef, len(items))
+	for i := range items {
+		refs[i] = refOf(&items[i])
+	}
+	return refs
+}
+
+// contentPageHash hashes everything a content page renders: the content itself,
+// its processed body and the headings/URLs of linked pages.
+func contentPageHash(rendered *RenderedContent, adjacent adjacentLinks, blocks *GeneratedBlocks, translations []TranslationLink) string {
+	in := struct {
+		Content      *Content
+		HTML         string
+		URL          string
+		Newer        *pageRef
+		Older        *pageRef
+		Related      []*pageRef
+		Next         *pageRef
+		Prev         *pageRef
+		Forward      []*pageRef
+		Back         []*pageRef
+		Translations []TranslationLink
+	}{
+		Content:      rendered.Content,
+		HTML:         string(rendered.HTMLBody),
+		URL:          rendered.URL,
+		Newer:        refOf(adjacent.newer),
+		Older:        refOf(adjacent.older),
+		Translations: translations,
+	}
+	if blocks != nil {
+		in.Related = refsOf(blocks.Related)
+		in.Next = refOf(blocks.SeriesNext)
+		in.Prev = refOf(blocks.SeriesPrev)
+		in.Forward = refsOf(blocks.SeriesIndexForward)
+		in.Back = refsOf(blocks.SeriesIndexBackward)
+	}
+	return hashInputs(in)
+}
+
+// listPageHash hashes a listing page: its position in the pagination and the
+// content it shows. It also returns the latest UpdatedAt among that content.
+func listPageHash(listPath string, page, totalPages int, contents []*Content) (string, time.Time) {
+	var latest time.Time
+	for _, c := range contents {
+		if c.UpdatedAt.After(latest) {
+			latest = c.UpdatedAt
+		}
+	}
+	return hashInputs(struct {
+		ListPath   string
+		Page       int
+		TotalPages int
+		Contents   []*Content
+	}{listPath, page, totalPages, contents}), latest
+}
@@ -0,0 +1,245 @@
+package ssg
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewBuildStateIncremental(t *testing.T) {
+	previous := &BuildManifest{Version: buildManifestVersion, GlobalHash: "abc", Pages: map[string]ManifestEntry{}}
+
+	tests := []struct {
+		name     string
+		previous *BuildManifest
+		hash     string
+		force    bool
+		want     bool
+	}{
+		{"no manifest", nil, "abc", false, false},
+		{"same global hash", previous, "abc", false, true},
+		{"global hash changed", previous, "def", false, false},
+		{"forced", previous, "abc", true, false},
+		{"old manifest version", &BuildManifest{Version: buildManifestVersion - 1, GlobalHash: "abc"}, "abc", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBuildState(t.TempDir(), tt.previous, tt.hash, tt.force)
+			if b.incremental != tt.want {
+				t.Errorf("incremental = %v, want %v", b.incremental, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildStateUnchanged(t *testing.T) {
+	htmlPath := t.TempDir()
+	kept := filepath.Join(htmlPath, "blog", "kept", "index.html")
+	missing := filepath.Join(htmlPath, "blog", "missing", "index.html")
+	if err := EnsureDir(kept); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(kept, []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := &BuildManifest{
+		Version:    buildManifestVersion,
+		GlobalHash: "g",
+		Pages: map[string]ManifestEntry{
+			filepath.Join("blog", "kept", "index.html"):    {Hash: "h1"},
+			filepath.Join("blog", "missing", "index.html"): {Hash: "h2"},
+		},
+	}
+	b := newBuildState(htmlPath, previous, "g", false)
+	now := time.Now()
+
+	if !b.unchanged(kept, "h1", now) {
+		t.Error("expected page with same hash to be unchanged")
+	}
+	if b.unchanged(kept, "other", now) {
+		t.Error("expected page with a different hash to be rebuilt")
+	}
+	if b.unchanged(missing, "h2", now) {
+		t.Error("expected page whose output is missing to be rebuilt")
+	}
+	if got := b.skipped.Load(); got != 1 {
+		t.Errorf("skipped = %d, want 1", got)
+	}
+
+	var nilState *buildState
+	if nilState.unchanged(kept, "h1", now) {
+		t.Error("nil build state should never skip pages")
+	}
+	nilState.record(kept, "h1", now)
+}
+
+func TestBuildStateRemoveStale(t *testing.T) {
+	htmlPath := t.TempDir()
+	keep := filepath.Join(htmlPath, "blog", "keep", "index.html")
+	stale := filepath.Join(htmlPath, "blog", "stale", "index.html")
+	for _, p := range []string{keep, stale} {
+		if err := EnsureDir(p); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	previous := &BuildManifest{
+		Version:    buildManifestVersion,
+		GlobalHash: "g",
+		Pages: map[string]ManifestEntry{
+			filepath.Join("blog", "keep", "index.html"):  {Hash: "k"},
+			filepath.Join("blog", "stale", "index.html"): {Hash: "s"},
+		},
+	}
+	b := newBuildState(htmlPath, previous, "g", true)
+	b.record(keep, "k", time.Now())
+	if got := b.removeStale(); got != 1 {
+		t.Errorf("removeStale() = %d, want 1", got)
+	}
+
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("expected kept page to remain: %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(stale)); !os.IsNotExist(err) {
+		t.Errorf("expected stale page and its directory to be removed, got %v", err)
+	}
+}
+
+func TestBuildStateClean(t *testing.T) {
+	site := t.TempDir()
+	htmlPath := filepath.Join(site, "html")
+	images := filepath.Join(site, "images")
+	for _, p := range []string{filepath.Join(htmlPath, "index.html"), filepath.Join(htmlPath, "old", "index.html"), filepath.Join(images, "a.png")} {
+		if err := EnsureDir(p); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := newBuildState(htmlPath, nil, "g", false)
+	if err := b.clean(htmlPath); err == nil {
+		t.Error("clean() should refuse when the output is the images directory")
+	}
+	linked := filepath.Join(site, "linked")
+	if err := os.Symlink(images, linked); err != nil {
+		t.Fatal(err)
+	}
+	if err := newBuildState(linked, nil, "g", false).clean(images); err == nil {
+		t.Error("clean() should refuse an output directory linked to the images")
+	}
+
+	if err := b.clean(images); err != nil {
+		t.Fatalf("clean() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(htmlPath); len(entries) != 0 {
+		t.Errorf("output not emptied: %v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(images, "a.png")); err != nil {
+		t.Errorf("images should be left alone: %v", err)
+	}
+
+	b.record(filepath.Join(htmlPath, "index.html"), "h", time.Now())
+	if got := b.removeStale(); got != 1 {
+		t.Errorf("removeStale() after clean = %d, want 1 (old/index.html)", got)
+	}
+}
+
+func TestValidateOutputDir(t *testing.T) {
+	for _, dir := range []string{"html", "public", "_site"} {
+		if err := ValidateOutputDir(dir); err != nil {
+			t.Errorf("ValidateOutputDir(%q) = %v", dir, err)
+		}
+	}
+	for _, dir := range []string{"", ".", "..", "images", "markdown", "a/b", "/tmp"} {
+		if err := ValidateOutputDir(dir); err == nil {
+			t.Errorf("ValidateOutputDir(%q) should fail", dir)
+		}
+	}
+
+	w := NewWorkspace("/ws")
+	w.SetOutputDir("images")
+	if got := w.GetHTMLPath("s"); got != filepath.Join("/ws", "s", DefaultOutputDir) {
+		t.Errorf("invalid output dir should be ignored, got %s", got)
+	}
+	w.SetOutputDir("public")
+	if got := w.GetHTMLPath("s"); filepath.Base(got) != "public" {
+		t.Errorf("GetHTMLPath() = %s", got)
+	}
+}
+
+func TestBuildManifestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "site", "build-manifest.json")
+	if loadBuildManifest(path) != nil {
+		t.Fatal("expected nil manifest when file is missing")
+	}
+
+	updated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := &BuildManifest{
+		Version:    buildManifestVersion,
+		GlobalHash: "g",
+		Pages:      map[string]ManifestEntry{"index.html": {Hash: "h", UpdatedAt: updated}},
+	}
+	if err := saveBuildManifest(path, m); err != nil {
+		t.Fatalf("saveBuildManifest: %v", err)
+	}
+
+	got := loadBuildManifest(path)
+	if got == nil || got.GlobalHash != "g" || got.Pages["index.html"].Hash != "h" || !got.Pages["index.html"].UpdatedAt.Equal(updated) {
+		t.Errorf("unexpected manifest after round trip: %+v", got)
+	}
+}
+
+func TestRenderContentPagesIncremental(t *testing.T) {
+	sections := []*Section{{ID: uuid.New(), Name: "Blog", Path: "blog"}}
+	contents := newBenchContents(5, sections)
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	htmlPath := g.workspace.GetHTMLPath("bench")
+
+	run := func(previous *BuildManifest) (*buildState, int) {
+		t.Helper()
+		b := newBuildState(htmlPath, previous, "g", false)
+		tmpl := template.Must(template.New("layout.html").Parse(
+			`<h1>{{ .Content.Heading }}</h1>{{ .Content.HTMLBody }}{{ if .OlderContent }}<a href="{{ .OlderContent.URL }}">prev</a>{{ end }}`))
+		params := map[string]string{}
+		allRendered := g.preRenderAllContent(contents, "/", params)
+		templates := map[uuid.UUID]sectionTemplate{sections[0].ID: {tmpl: tmpl}}
+		site := &Site{ID: uuid.New(), Name: "Bench", Slug: "bench"}
+		generated, errs := g.renderContentPages(templates, nil, nil, b, htmlPath, site, contents, sections, nil, params, allRendered, BlocksConfig{})
+		if len(errs) > 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		return b, generated
+	}
+
+	first, generated := run(nil)
+	if generated != len(contents) {
+		t.Fatalf("first run generated %d pages, want %d", generated, len(contents))
+	}
+
+	second, generated := run(first.manifest("g"))
+	if generated != 0 || second.skipped.Load() != int64(len(contents)) {
+		t.Fatalf("second run generated %d and skipped %d, want 0 and %d", generated, second.skipped.Load(), len(contents))
+	}
+
+	contents[2].Body = "Edited body"
+	contents[2].UpdatedAt = time.Now()
+	third, generated := run(second.manifest("g"))
+	// Neighbours only show the edited page's heading and URL, so they are kept.
+	if generated != 1 {
+		t.Errorf("third run generated %d pages, want 1", generated)
+	}
+	if third.skipped.Load() != int64(len(contents)-1) {
+		t.Errorf("third run skipped %d pages, want %d", third.skipped.Load(), len(contents)-1)
+	}
+}
@@ -0,0 +1,166 @@
+package ssg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Built-in content kinds.
+const (
+	KindPost  = "post"
+	KindPage  = "page"
+	KindNote  = "note"
+	KindLink  = "link"
+	KindPhoto = "photo"
+)
+
+// ContentKind sets how content of a kind is published: the URL it gets,
+// whether it appears in listings, whether it has a feed of its own and the
+// layout its pages use. Content of an unknown kind is published as a post.
+type ContentKind struct {
+	ID        uuid.UUID `json:"id"` // uuid.Nil for built-in kinds with default settings
+	SiteID    uuid.UUID `json:"site_id"`
+	Name      string    `json:"name"` // Content.Kind value, e.g. "note"
+	Label     string    `json:"label"`
+	Permalink string    `json:"permalink"` // Permalink pattern of the kind, empty for the site's
+	Listed    bool      `json:"listed"`    // In index, tag and author listings, site and section feeds and previous/next links
+	Feed      bool      `json:"feed"`      // Has a feed of its own at /kinds/<name>/feed/
+	Redirect  bool      `json:"redirect"`  // Pages send visitors on to the URL they link to
+	LayoutID  uuid.UUID `json:"layout_id"` // Layout of the kind's pages, overriding the section's
+	BuiltIn   bool      `json:"built_in"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultContentKinds returns the kinds every site has, with their default
+// settings, in the order they are offered in the content form.
+func DefaultContentKinds() []*ContentKind {
+	return []*ContentKind{
+		{Name: KindPage, Label: "Page", Permalink: DefaultPermalinkPattern, BuiltIn: true},
+		{Name: "article", Label: "Article", Listed: true, BuiltIn: true},
+		{Name: "series", Label: "Series", Listed: true, BuiltIn: true},
+		{Name: KindPost, Label: "Post", Listed: true, BuiltIn: true},
+		{Name: KindNote, Label: "Note", Listed: true, Feed: true, BuiltIn: true},
+		{Name: KindLink, Label: "Link", Listed: true, Redirect: true, BuiltIn: true},
+		{Name: KindPhoto, Label: "Photo", Listed: true, BuiltIn: true},
+	}
+}
+
+var kindNameRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Validate checks the kind name and permalink pattern.
+func (k *ContentKind) Validate() error {
+	if !kindNameRe.MatchString(k.Name) {
+		return fmt.Errorf("kind name %q must start with a letter and use only lowercase letters, digits and hyphens", k.Name)
+	}
+	if k.Permalink != "" {
+		if _, err := ParsePermalinkPattern(k.Permalink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Customized reports whether the kind has stored settings. Deleting those
+// turns a built-in kind back to its defaults.
+func (k *ContentKind) Customized() bool {
+	return k.ID != uuid.Nil
+}
+
+// mergeContentKinds returns the default kinds with stored settings applied,
+// followed by the custom kinds sorted by name.
+func mergeContentKinds(stored []*ContentKind) []*ContentKind {
+	byName := make(map[string]*ContentKind, len(stored))
+	for _, k := range stored {
+		byName[k.Name] = k
+	}
+
+	kinds := DefaultContentKinds()
+	for i, def := range kinds {
+		if k, ok := byName[def.Name]; ok {
+			k.BuiltIn = true
+			kinds[i] = k
+			delete(byName, def.Name)
+		}
+	}
+
+	var custom []*ContentKind
+	for _, k := range byName {
+		custom = append(custom, k)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	return append(kinds, custom...)
+}
+
+var defaultKinds = kindsByName(DefaultContentKinds())
+
+func kindsByName(kinds []*ContentKind) map[string]*ContentKind {
+	m := make(map[string]*ContentKind, len(kinds))
+	for _, k := range kinds {
+		m[k.Name] = k
+	}
+	return m
+}
+
+// applyContentKinds attaches to every content the settings of its kind. A nil
+// list uses the default kinds.
+func applyContentKinds(contents []*Content, kinds []*ContentKind) {
+	byName := defaultKinds
+	if kinds != nil {
+		byName = kindsByName(kinds)
+	}
+	for _, c := range contents {
+		c.KindInfo = byName[contentKindName(c)]
+	}
+}
+
+func contentKindName(c *Content) string {
+	if c.Kind == "" {
+		return KindPost
+	}
+	return c.Kind
+}
+
+// kindSettings returns the settings of the content's kind: the attached ones,
+// else the default ones, else those of a post.
+func (c *Content) kindSettings() *ContentKind {
+	if c.KindInfo != nil {
+		return c.KindInfo
+	}
+	if k, ok := defaultKinds[contentKindName(c)]; ok {
+		return k
+	}
+	return defaultKinds[KindPost]
+}
+
+// inListings reports whether listed content also appears in the listings and
+// feeds of the site, which depends on its kind.
+func inListings(c *Content) bool {
+	return isListed(c) && c.kindSettings().Listed
+}
+
+var linkTargetRe = regexp.MustCompile(`https?://[^\s<>()"'\]]+`)
+
+// linkTarget returns where a redirecting kind sends visitors: the link URL
+// when set, else the canonical URL, else the first absolute link in the body.
+// Empty when there is none of them.
+func linkTarget(c *Content) string {
+	if isWebURL(c.LinkURL) {
+		return c.LinkURL
+	}
+	if c.Meta != nil && isWebURL(c.Meta.CanonicalURL) {
+		return c.Meta.CanonicalURL
+	}
+	for _, m := range linkTargetRe.FindAllString(c.Body, -1) {
+		m = strings.TrimRight(m, ".,;:!?")
+		if isWebURL(m) {
+			return m
+		}
+	}
+	return ""
+}
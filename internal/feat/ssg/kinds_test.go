@@ -0,0 +1,178 @@
+package ssg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMergeContentKinds(t *testing.T) {
+	note := &ContentKind{ID: uuid.New(), Name: KindNote, Label: "Micro", Listed: false}
+	recipe := &ContentKind{ID: uuid.New(), Name: "recipe", Label: "Recipe", Listed: true}
+	audio := &ContentKind{ID: uuid.New(), Name: "audio", Label: "Audio"}
+
+	kinds := mergeContentKinds([]*ContentKind{recipe, note, audio})
+	defaults := DefaultContentKinds()
+	if len(kinds) != len(defaults)+2 {
+		t.Fatalf("got %d kinds, want %d", len(kinds), len(defaults)+2)
+	}
+	for i, def := range defaults {
+		if kinds[i].Name != def.Name || !kinds[i].BuiltIn {
+			t.Errorf("kinds[%d] = %q, want built-in %q", i, kinds[i].Name, def.Name)
+		}
+	}
+	byName := kindsByName(kinds)
+	if byName[KindNote] != note || byName[KindNote].Customized() == false {
+		t.Error("stored settings should replace the defaults of a built-in kind")
+	}
+	if got := kinds[len(kinds)-2:]; got[0] != audio || got[1] != recipe || got[1].BuiltIn {
+		t.Errorf("custom kinds = %q, %q, want audio then recipe, not built in", got[0].Name, got[1].Name)
+	}
+}
+
+func TestContentKindValidate(t *testing.T) {
+	tests := []struct {
+		kind    ContentKind
+		wantErr bool
+	}{
+		{ContentKind{Name: "recipe"}, false},
+		{ContentKind{Name: "how-to", Permalink: "/:kind/:slug/"}, false},
+		{ContentKind{Name: ""}, true},
+		{ContentKind{Name: "Recipe"}, true},
+		{ContentKind{Name: "1st"}, true},
+		{ContentKind{Name: "recipe", Permalink: "/:year/"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.kind.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q, %q) error = %v, wantErr %v", tt.kind.Name, tt.kind.Permalink, err, tt.wantErr)
+		}
+	}
+}
+
+func TestContentKindSettings(t *testing.T) {
+	day := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	post := &Content{Heading: "Post", PublishedAt: &day}
+	page := &Content{Heading: "Page", Kind: KindPage, PublishedAt: &day}
+	legacy := &Content{Heading: "Blog", Kind: "blog", PublishedAt: &day}
+
+	applyContentKinds([]*Content{post, page, legacy}, nil)
+	if post.KindInfo == nil || post.KindInfo.Name != KindPost {
+		t.Errorf("content without a kind should get post settings, got %+v", post.KindInfo)
+	}
+	if !inListings(post) || inListings(page) {
+		t.Error("posts should be in listings and pages should not")
+	}
+	if legacy.KindInfo != nil || !inListings(legacy) {
+		t.Error("content of an unknown kind should be published as a post")
+	}
+
+	custom := []*ContentKind{{Name: KindPage, Listed: true}}
+	applyContentKinds([]*Content{page}, custom)
+	if !inListings(page) {
+		t.Error("stored kind settings should apply")
+	}
+}
+
+func TestContentPathByKind(t *testing.T) {
+	day := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	params := map[string]string{PermalinkRefKey: "/:year/:month/:slug/"}
+	post := &Content{ID: uuid.New(), ShortID: "abc123", Heading: "Hello", SectionPath: "blog", PublishedAt: &day}
+	page := &Content{ID: uuid.New(), ShortID: "def456", Heading: "About", Kind: KindPage, SectionPath: "/", PublishedAt: &day}
+	note := &Content{ID: uuid.New(), ShortID: "ghi789", Heading: "Quick", Kind: KindNote, SectionPath: "blog", PublishedAt: &day}
+	applyContentKinds([]*Content{post, page, note}, []*ContentKind{
+		{Name: KindPage, Permalink: DefaultPermalinkPattern},
+		{Name: KindNote, Permalink: "/:kind/:slug/"},
+	})
+
+	paths, err := assignPermalinks([]*Content{post, page, note}, params)
+	if err != nil {
+		t.Fatalf("assignPermalinks() error = %v", err)
+	}
+	want := map[uuid.UUID]string{
+		post.ID: "2024/03/hello-abc123",
+		page.ID: "about-def456",
+		note.ID: "note/quick-ghi789",
+	}
+	for id, path := range want {
+		if paths[id] != path {
+			t.Errorf("path = %q, want %q", paths[id], path)
+		}
+	}
+}
+
+func TestLinkTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *Content
+		want    string
+	}{
+		{"link URL", &Content{LinkURL: "https://d.example/", Body: "See https://b.example/x", Meta: &Meta{CanonicalURL: "https://a.example/"}}, "https://d.example/"},
+		{"invalid link URL", &Content{LinkURL: "d.example", Body: "See https://b.example/x"}, "https://b.example/x"},
+		{"canonical", &Content{Body: "See https://b.example/x", Meta: &Meta{CanonicalURL: "https://a.example/"}}, "https://a.example/"},
+		{"markdown link", &Content{Body: "Worth reading: [this](https://b.example/post)."}, "https://b.example/post"},
+		{"bare URL", &Content{Body: "Via https://c.example/a, great."}, "https://c.example/a"},
+		{"none", &Content{Body: "No links, just [a local one](/about/)."}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linkTarget(tt.content); got != tt.want {
+				t.Errorf("linkTarget() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderContentPageRedirectKind(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	link := &Content{ID: uuid.New(), ShortID: "abc123", Heading: "Good read", Kind: KindLink, SectionPath: "blog", Body: "[Read it](https://example.org/article)"}
+	applyContentKinds([]*Content{link}, nil)
+
+	written, err := g.renderContentPage(nil, nil, nil, g.workspace.GetHTMLPath(site.Slug), site, link, nil, adjacentLinks{}, nil, nil, map[string]string{}, nil, BlocksConfig{})
+	if err != nil || !written {
+		t.Fatalf("renderContentPage() = %v, %v", written, err)
+	}
+	data, err := os.ReadFile(filepath.Join(g.workspace.GetHTMLPath(site.Slug), "blog", "good-read-abc123", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `url=https://example.org/article`) {
+		t.Errorf("link page should redirect to its target, got %s", data)
+	}
+
+	link.LinkURL = "https://example.org/elsewhere"
+	if _, err := g.renderContentPage(nil, nil, nil, g.workspace.GetHTMLPath(site.Slug), site, link, nil, adjacentLinks{}, nil, nil, map[string]string{}, nil, BlocksConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(filepath.Join(g.workspace.GetHTMLPath(site.Slug), "blog", "good-read-abc123", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `url=https://example.org/elsewhere`) {
+		t.Errorf("link page should redirect to its link URL, got %s", data)
+	}
+}
+
+func TestKindFeeds(t *testing.T) {
+	day := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	site := &Site{Name: "Test"}
+	contents := []*Content{
+		{Heading: "Post", PublishedAt: &day},
+		{Heading: "Note", Kind: KindNote, PublishedAt: &day},
+		{Heading: "Draft note", Kind: KindNote, Draft: true},
+	}
+	applyContentKinds(contents, nil)
+
+	feeds := kindFeeds(site, contents)
+	if len(feeds) != 1 {
+		t.Fatalf("got %d kind feeds, want 1", len(feeds))
+	}
+	f := feeds[0]
+	if f.listPath != "kinds/note" || f.title != "Test - Note" || len(f.contents) != 1 || f.homePath != "/" {
+		t.Errorf("note feed = %+v", f)
+	}
+}
@@ -0,0 +1,55 @@
+package ssg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// layoutExportFormat identifies layout documents and their version, so an
+// import can tell them from any other JSON.
+const layoutExportFormat = "clio-layout/1"
+
+var (
+	ErrLayoutExport    = errors.New("not a Clio layout export")
+	ErrLayoutNameTaken = errors.New("site already has a layout with this name")
+)
+
+// LayoutExport is a layout as it moves between sites. The header image
+// belongs to the site it was picked on and stays behind.
+type LayoutExport struct {
+	Format            string `json:"format"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	Code              string `json:"code"`
+	CSS               string `json:"css"`
+	ExcludeDefaultCSS bool   `json:"exclude_default_css"`
+}
+
+func exportLayout(layout *Layout) *LayoutExport {
+	return &LayoutExport{
+		Format:            layoutExportFormat,
+		Name:              layout.Name,
+		Description:       layout.Description,
+		Code:              layout.Code,
+		CSS:               layout.CSS,
+		ExcludeDefaultCSS: layout.ExcludeDefaultCSS,
+	}
+}
+
+// validate checks that e is a layout export with a name and code that
+// parses. Parse errors wrap ErrLayoutTemplate.
+func (e *LayoutExport) validate() error {
+	if e == nil || e.Format != layoutExportFormat {
+		return ErrLayoutExport
+	}
+	if strings.TrimSpace(e.Name) == "" {
+		return fmt.Errorf("%w: layout has no name", ErrLayoutExport)
+	}
+	if e.Code != "" {
+		if _, err := parseLayoutCode(e.Code); err != nil {
+			return fmt.Errorf("%w: %v", ErrLayoutTemplate, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package ssg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestLayoutExportImportRoundTrip(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	source := createTestSite(t, svc, "Source", "source")
+	target := createTestSite(t, svc, "Target", "target")
+
+	layout := NewLayout(source.ID, "Magazine", "Two columns")
+	layout.Code = `<html><head><title>{{ .Site.Name }}</title></head><body>{{ template "main" . }}</body></html>{{ define "main" }}<main></main>{{ end }}`
+	layout.CSS = "main { display: grid; grid-template-columns: 2fr 1fr; }"
+	layout.ExcludeDefaultCSS = true
+	layout.HeaderImageID = uuid.New()
+	if err := svc.CreateLayout(ctx, layout); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.ExportLayout(ctx, target.ID, layout.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("exporting another site's layout error = %v, want ErrNotFound", err)
+	}
+	export, err := svc.ExportLayout(ctx, source.ID, layout.ID)
+	if err != nil {
+		t.Fatalf("ExportLayout() error = %v", err)
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded LayoutExport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	userID := uuid.New()
+	imported, err := svc.ImportLayout(ctx, target.ID, &decoded, userID)
+	if err != nil {
+		t.Fatalf("ImportLayout() error = %v", err)
+	}
+
+	got, err := svc.GetLayout(ctx, imported.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SiteID != target.ID || got.ID == layout.ID {
+		t.Errorf("imported layout is %s on site %s, want a new layout on the target site", got.ID, got.SiteID)
+	}
+	if got.Name != layout.Name || got.Description != layout.Description || !got.ExcludeDefaultCSS {
+		t.Errorf("imported layout = %q %q exclude=%v, want the exported fields", got.Name, got.Description, got.ExcludeDefaultCSS)
+	}
+	if got.Code != layout.Code {
+		t.Errorf("imported code = %q, want %q", got.Code, layout.Code)
+	}
+	if got.CSS != layout.CSS {
+		t.Errorf("imported CSS = %q, want %q", got.CSS, layout.CSS)
+	}
+	if got.HeaderImageID != uuid.Nil {
+		t.Errorf("imported header image = %s, want none", got.HeaderImageID)
+	}
+
+	decoded.Name = " magazine "
+	if _, err := svc.ImportLayout(ctx, target.ID, &decoded, userID); !errors.Is(err, ErrLayoutNameTaken) {
+		t.Errorf("importing a taken name error = %v, want ErrLayoutNameTaken", err)
+	}
+
+	broken := *export
+	broken.Name = "Broken"
+	broken.Code = `{{ if .Site }}<p>never closed`
+	if _, err := svc.ImportLayout(ctx, target.ID, &broken, userID); !errors.Is(err, ErrLayoutTemplate) {
+		t.Errorf("importing code that does not parse error = %v, want ErrLayoutTemplate", err)
+	}
+	if _, err := svc.ImportLayout(ctx, target.ID, &LayoutExport{Name: "Plain"}, userID); !errors.Is(err, ErrLayoutExport) {
+		t.Errorf("importing JSON without the format error = %v, want ErrLayoutExport", err)
+	}
+
+	layouts, _ := svc.GetLayouts(ctx, target.ID)
+	if len(layouts) != 1 {
+		t.Errorf("target site has %d layouts, want only the imported one", len(layouts))
+	}
+}
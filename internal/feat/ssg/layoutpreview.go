@@ -0,0 +1,99 @@
+package ssg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+)
+
+// ErrLayoutTemplate marks preview failures caused by the layout code itself.
+var ErrLayoutTemplate = errors.New("layout template error")
+
+// PreviewLayout renders content with the given layout in memory, using the same
+// page data as generation. Nothing is written to disk. Parse and execution
+// errors wrap ErrLayoutTemplate so callers can show them to the author.
+func (g *HTMLGenerator) PreviewLayout(site *Site, layout *Layout, content *Content, contents []*Content, sections []*Section, params []*Setting) ([]byte, error) {
+	paramsMap := withDefaultTimezone(params, g.timezone)
+	// The layout being previewed may not have been generated yet, so its CSS
+	// has no fingerprinted file to link.
+	delete(paramsMap, FingerprintRefKey)
+	applySummaries(contents, paramsMap)
+	applySummaries([]*Content{content}, paramsMap)
+
+	var tmpl *template.Template
+	var err error
+	if layout.Code == "" {
+		tmpl, err = g.parseTemplates(g.siteTheme(site.Slug, paramsMap))
+	} else {
+		tmpl, err = g.parseCustomLayout(layout.Code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLayoutTemplate, err)
+	}
+
+	basePath := g.getAssetPath(paramsMap)
+	allRendered := g.preRenderAllContent(contents, basePath, paramsMap)
+
+	var rendered *RenderedContent
+	for _, r := range allRendered {
+		if r.ID == content.ID {
+			rendered = r
+			break
+		}
+	}
+	if rendered == nil {
+		// Drafts and scheduled content are not pre-rendered but can still be previewed.
+		htmlBody, err := g.processor.ProcessContent(content, paramsMap)
+		if err != nil {
+			return nil, fmt.Errorf("cannot process content: %w", err)
+		}
+		rendered = &RenderedContent{
+			Content:  content,
+			HTMLBody: template.HTML(htmlBody),
+			URL:      g.getContentURL(content, basePath, paramsMap),
+		}
+	}
+
+	adjacent := buildAdjacentIndex(allRendered, paramsMap)[content.ID]
+	data, _ := g.contentPageData(layout, site, rendered, adjacent, sections, g.buildMenu(sections), paramsMap, allRendered, getBlocksConfig(paramsMap))
+
+	var buf bytes.Buffer
+	if err := executeLayout(&buf, tmpl, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLayoutTemplate, err)
+	}
+	if !trailingSlash(paramsMap) {
+		return []byte(normalizeInternalLinks(buf.String(), paramsMap)), nil
+	}
+	return buf.Bytes(), nil
+}
+
+// previewCandidates orders contents most recent first for the preview picker
+// and returns the default choice: the most recent post, or the most recent
+// item of any kind when there are no posts.
+func previewCandidates(contents []*Content) ([]*Content, *Content) {
+	sorted := make([]*Content, len(contents))
+	copy(sorted, contents)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return recencyOf(sorted[i]).After(recencyOf(sorted[j]))
+	})
+
+	for _, c := range sorted {
+		if c.Kind == "post" {
+			return sorted, c
+		}
+	}
+	if len(sorted) > 0 {
+		return sorted, sorted[0]
+	}
+	return sorted, nil
+}
+
+func recencyOf(c *Content) time.Time {
+	if c.PublishedAt != nil {
+		return *c.PublishedAt
+	}
+	return c.CreatedAt
+}
@@ -0,0 +1,120 @@
+package ssg
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPreviewLayout(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "Preview", Slug: "preview"}
+	section := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	published := time.Now().Add(-time.Hour)
+	post := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, Kind: "post", Heading: "Hello", Body: "Some **bold** text", PublishedAt: &published}
+	draft := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, Kind: "post", Heading: "Unfinished", Body: "WIP", Draft: true}
+	contents := []*Content{post, draft}
+	sections := []*Section{section}
+
+	t.Run("renders content with layout code", func(t *testing.T) {
+		layout := &Layout{Code: `<h1>{{ .Content.Heading }}</h1>{{ .Content.HTMLBody }}<p>{{ .CustomCSS }}</p>`, CSS: "h1{color:red}"}
+		out, err := g.PreviewLayout(site, layout, post, contents, sections, nil)
+		if err != nil {
+			t.Fatalf("PreviewLayout() error = %v", err)
+		}
+		html := string(out)
+		for _, want := range []string{"<h1>Hello</h1>", "<strong>bold</strong>", "h1{color:red}"} {
+			if !strings.Contains(html, want) {
+				t.Errorf("output missing %q: %s", want, html)
+			}
+		}
+	})
+
+	t.Run("drafts can be previewed", func(t *testing.T) {
+		out, err := g.PreviewLayout(site, &Layout{Code: `{{ .Content.Heading }}`}, draft, contents, sections, nil)
+		if err != nil {
+			t.Fatalf("PreviewLayout() error = %v", err)
+		}
+		if string(out) != "Unfinished" {
+			t.Errorf("output = %q, want %q", out, "Unfinished")
+		}
+	})
+
+	t.Run("parse errors are template errors", func(t *testing.T) {
+		_, err := g.PreviewLayout(site, &Layout{Code: `{{ if }}`}, post, contents, sections, nil)
+		if !errors.Is(err, ErrLayoutTemplate) {
+			t.Errorf("error = %v, want ErrLayoutTemplate", err)
+		}
+	})
+
+	t.Run("execution errors are template errors", func(t *testing.T) {
+		_, err := g.PreviewLayout(site, &Layout{Code: `{{ .Content.NoSuchField }}`}, post, contents, sections, nil)
+		if !errors.Is(err, ErrLayoutTemplate) {
+			t.Errorf("error = %v, want ErrLayoutTemplate", err)
+		}
+	})
+
+	t.Run("nothing is written to disk", func(t *testing.T) {
+		if _, err := os.Stat(g.workspace.GetHTMLPath(site.Slug)); !os.IsNotExist(err) {
+			t.Errorf("expected no HTML output directory, got %v", err)
+		}
+	})
+}
+
+func TestTemplateFuncMapHasNoFileAccess(t *testing.T) {
+	var names []string
+	for name := range templateFuncMap() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Layout code is user supplied: any new function must be reviewed before being added here.
+	want := []string{
+		"absURL", "add", "contains", "div", "formatDate", "formatInTZ", "hasPrefix", "hasSuffix", "join", "lower",
+		"mul", "now", "plainText", "pluralize", "replace", "roleTitle", "safeCSS", "safeHTML", "seq", "siteDate", "split",
+		"sub", "subtract", "timeAgo", "title", "truncate", "truncateWords", "upper",
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("template functions = %v, want %v", names, want)
+	}
+
+	g := &HTMLGenerator{}
+	for _, code := range []string{`{{ readFile "/etc/passwd" }}`, `{{ env "HOME" }}`, `{{ template "/etc/passwd" }}{{ define "x" }}{{ end }}`} {
+		if tmpl, err := g.parseCustomLayout(code); err == nil {
+			if err := tmpl.ExecuteTemplate(&strings.Builder{}, "layout.html", nil); err == nil {
+				t.Errorf("expected %q to fail", code)
+			}
+		}
+	}
+}
+
+func TestPreviewCandidates(t *testing.T) {
+	older := time.Now().Add(-48 * time.Hour)
+	newer := time.Now().Add(-time.Hour)
+
+	page := &Content{ID: uuid.New(), Kind: "page", Heading: "About", PublishedAt: &newer}
+	oldPost := &Content{ID: uuid.New(), Kind: "post", Heading: "Old", PublishedAt: &older}
+	draftPost := &Content{ID: uuid.New(), Kind: "post", Heading: "Draft", CreatedAt: older.Add(time.Hour)}
+
+	sorted, selected := previewCandidates([]*Content{oldPost, page, draftPost})
+	if selected != draftPost {
+		t.Errorf("selected = %q, want most recent post %q", headingOf(selected), "Draft")
+	}
+	if sorted[0] != page {
+		t.Errorf("first = %q, want most recent item %q", headingOf(sorted[0]), "About")
+	}
+
+	_, selected = previewCandidates([]*Content{page})
+	if selected != page {
+		t.Errorf("without posts, selected = %q, want %q", headingOf(selected), "About")
+	}
+
+	if _, selected = previewCandidates(nil); selected != nil {
+		t.Errorf("empty input selected %v, want nil", selected)
+	}
+}
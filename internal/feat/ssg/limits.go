@@ -0,0 +1,167 @@
+package ssg
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"os"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultPageBufferSize is how much of a page is held in memory before it is
+// written out when no size is configured.
+const defaultPageBufferSize = 64 << 10
+
+// memorySampleInterval is how often the heap is sampled during generation.
+const memorySampleInterval = 250 * time.Millisecond
+
+// heapMetric is the memory held by live and not yet swept heap objects.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// SetImageWorkers sets how many social images are drawn at once. Drawing
+// holds full-size images in memory, so it can be kept below the page
+// workers on small containers. Zero or less uses the page worker count.
+func (g *HTMLGenerator) SetImageWorkers(n int) {
+	g.imageWorkers.Store(int32(n))
+}
+
+// SetPageBufferSize sets how many bytes of a page are buffered in memory
+// before it streams to its file. Zero or less uses 64 KB.
+func (g *HTMLGenerator) SetPageBufferSize(n int) {
+	g.pageBuffer.Store(int64(n))
+}
+
+// SetMemoryBudget sets a soft heap budget in bytes. It limits nothing: a
+// generation whose heap grows past it reports a warning, so the worker
+// counts can be lowered. Zero turns the warning off.
+func (g *HTMLGenerator) SetMemoryBudget(n uint64) {
+	g.memoryBudget.Store(n)
+}
+
+func (g *HTMLGenerator) imageWorkerCount() int {
+	if n := g.imageWorkers.Load(); n > 0 {
+		return int(n)
+	}
+	return g.workerCount()
+}
+
+func (g *HTMLGenerator) pageBufferSize() int {
+	if n := g.pageBuffer.Load(); n > 0 {
+		return int(n)
+	}
+	return defaultPageBufferSize
+}
+
+// writePage renders a page into f through a buffer of the configured size,
+// so large pages stream to disk instead of being held whole. Sites without
+// trailing slashes are the exception: layouts write page links with one, so
+// the page is rendered whole to take them off.
+func (g *HTMLGenerator) writePage(f *os.File, tmpl *template.Template, data SSGPageData) error {
+	if !trailingSlash(data.Params) {
+		var b strings.Builder
+		if err := executeLayout(&b, tmpl, data); err != nil {
+			return err
+		}
+		_, err := f.WriteString(normalizeInternalLinks(b.String(), data.Params))
+		return err
+	}
+	w := bufio.NewWriterSize(f, g.pageBufferSize())
+	if err := executeLayout(w, tmpl, data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// concurrencyGauge counts the goroutines inside a section of work and the
+// most seen at once. A nil gauge counts nothing.
+type concurrencyGauge struct {
+	current atomic.Int64
+	peak    atomic.Int64
+}
+
+func (c *concurrencyGauge) enter() {
+	if c == nil {
+		return
+	}
+	n := c.current.Add(1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			return
+		}
+	}
+}
+
+func (c *concurrencyGauge) leave() {
+	if c == nil {
+		return
+	}
+	c.current.Add(-1)
+}
+
+// highWater returns the most goroutines seen inside at once.
+func (c *concurrencyGauge) highWater() int {
+	return int(c.peak.Load())
+}
+
+// memoryWatch samples the heap in the background and keeps its peak.
+type memoryWatch struct {
+	quit chan struct{}
+	once sync.Once
+	done sync.WaitGroup
+	peak uint64
+}
+
+func watchMemory() *memoryWatch {
+	m := &memoryWatch{quit: make(chan struct{})}
+	m.sample()
+	m.done.Add(1)
+	go func() {
+		defer m.done.Done()
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.quit:
+				return
+			case <-ticker.C:
+				m.sample()
+			}
+		}
+	}()
+	return m
+}
+
+func (m *memoryWatch) sample() {
+	s := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(s)
+	if s[0].Value.Kind() == metrics.KindUint64 {
+		if v := s[0].Value.Uint64(); v > m.peak {
+			m.peak = v
+		}
+	}
+}
+
+// finish ends sampling and returns the peak heap in bytes. Calls after the
+// first return the same peak.
+func (m *memoryWatch) finish() uint64 {
+	m.once.Do(func() {
+		close(m.quit)
+		m.done.Wait()
+		m.sample()
+	})
+	return m.peak
+}
+
+// memoryBudgetWarning reports a peak heap over budget, or nothing when
+// within it or no budget is set.
+func memoryBudgetWarning(peak, budget uint64) []string {
+	if budget == 0 || peak <= budget {
+		return nil
+	}
+	return []string{fmt.Sprintf("memory: heap peaked at %d MB, over the %d MB budget; lower ssg.workers or ssg.image_workers", peak>>20, budget>>20)}
+}
@@ -0,0 +1,57 @@
+package ssg
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRunParallelHonorsWorkers(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		var gauge concurrencyGauge
+		runParallel(12, workers, func(int) {
+			gauge.enter()
+			defer gauge.leave()
+			time.Sleep(5 * time.Millisecond)
+		})
+		if got := gauge.highWater(); got != workers {
+			t.Errorf("runParallel with %d workers peaked at %d", workers, got)
+		}
+	}
+}
+
+func TestGenerateOGImagesHonorsImageWorkers(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	g.SetWorkers(8)
+	g.SetImageWorkers(2)
+	site := &Site{ID: uuid.New(), Name: "Busy Blog", Slug: "busy-blog"}
+	htmlPath := g.workspace.GetHTMLPath(site.Slug)
+
+	var pages []*RenderedContent
+	for i := 0; i < 16; i++ {
+		pages = append(pages, &RenderedContent{Content: &Content{ID: uuid.New(), Heading: fmt.Sprintf("Post number %d", i)}})
+	}
+
+	build := newBuildState(htmlPath, nil, "", true)
+	stats, warnings := g.generateOGImages(build, htmlPath, site, pages, map[string]string{OGImageRefKey: "true"})
+	if stats.Drawn != len(pages) || len(warnings) > 0 {
+		t.Fatalf("stats = %+v, warnings = %v, want every image drawn", stats, warnings)
+	}
+	if peak := build.imageWorkers.highWater(); peak < 1 || peak > 2 {
+		t.Errorf("social images drawn at once peaked at %d, want at most 2", peak)
+	}
+}
+
+func TestMemoryBudgetWarning(t *testing.T) {
+	if w := memoryBudgetWarning(600<<20, 0); w != nil {
+		t.Errorf("no budget warned: %v", w)
+	}
+	if w := memoryBudgetWarning(400<<20, 512<<20); w != nil {
+		t.Errorf("heap within budget warned: %v", w)
+	}
+	if w := memoryBudgetWarning(600<<20, 512<<20); len(w) != 1 {
+		t.Errorf("heap over budget = %v, want one warning", w)
+	}
+}
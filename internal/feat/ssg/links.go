@@ -0,0 +1,206 @@
+package ssg
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Setting ref keys for links to other sites in rendered content.
+const (
+	// ExternalLinkTargetRefKey is the target set on external links that do
+	// not have one, e.g. _blank. Empty leaves it alone.
+	ExternalLinkTargetRefKey = "ssg.links.external_target"
+	// ExternalLinkRelRefKey lists rel values added to external links, e.g.
+	// "nofollow sponsored". Values already on a link are kept.
+	ExternalLinkRelRefKey = "ssg.links.external_rel"
+	// ExternalLinkStripTrackingRefKey also takes tracking parameters off
+	// links to other sites when "true". Links to the site always lose them.
+	ExternalLinkStripTrackingRefKey = "ssg.links.external_strip_tracking"
+)
+
+// linkRewriteOptions is what rewriteExternalLinks adds to external links.
+type linkRewriteOptions struct {
+	Target string
+	Rel    []string
+}
+
+// linkRewriteOptionsFromParams reads the external link settings. Links
+// opened in a new tab also get noopener, so the opened page cannot reach
+// back through window.opener.
+func linkRewriteOptionsFromParams(params map[string]string) linkRewriteOptions {
+	opts := linkRewriteOptions{
+		Target: strings.TrimSpace(params[ExternalLinkTargetRefKey]),
+		Rel:    strings.Fields(strings.ReplaceAll(params[ExternalLinkRelRefKey], ",", " ")),
+	}
+	if opts.Target == "_blank" {
+		opts.Rel = mergeRel([]string{"noopener"}, opts.Rel)
+	}
+	return opts
+}
+
+func (o linkRewriteOptions) isSet() bool {
+	return o.Target != "" || len(o.Rel) > 0
+}
+
+var (
+	anchorTagRe  = regexp.MustCompile(`(?i)<a\s[^>]*>`)
+	relAttrRe    = attrRe("rel")
+	targetAttrRe = attrRe("target")
+	tagEndRe     = regexp.MustCompile(`\s*/?>$`)
+	hrefSchemeRe = regexp.MustCompile(`(?i)^[a-z][a-z0-9+.-]*:`)
+)
+
+// isExternalLink reports whether href leads to another site: an absolute
+// http(s) or protocol-relative URL whose host is not baseHost. Relative
+// links, fragments and other schemes such as mailto are not external.
+func isExternalLink(href, baseHost string) bool {
+	href = strings.TrimSpace(href)
+	if !strings.HasPrefix(href, "//") && !hrefSchemeRe.MatchString(href) {
+		return false
+	}
+	u, err := url.Parse(href)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "" && scheme != "http" && scheme != "https" {
+		return false
+	}
+	return !strings.EqualFold(u.Hostname(), baseHost)
+}
+
+// rewriteExternalLinks adds the target and rel values of opts to the links
+// in a rendered body that point to other sites. baseHost is the host of the
+// site's base URL; links to it are left alone. A target already on a link
+// is kept and existing rel values are merged with the new ones.
+func rewriteExternalLinks(body, baseHost string, opts linkRewriteOptions) string {
+	if !opts.isSet() {
+		return body
+	}
+	return anchorTagRe.ReplaceAllStringFunc(body, func(tag string) string {
+		href, ok := attrValue([]byte(tag), hrefAttrRe)
+		if !ok || !isExternalLink(href, baseHost) {
+			return tag
+		}
+		var attrs []string
+		if opts.Target != "" {
+			if _, ok := attrValue([]byte(tag), targetAttrRe); !ok {
+				attrs = append(attrs, `target="`+html.EscapeString(opts.Target)+`"`)
+			}
+		}
+		if len(opts.Rel) > 0 {
+			current, _ := attrValue([]byte(tag), relAttrRe)
+			if m := relAttrRe.FindStringSubmatchIndex(tag); m != nil {
+				tag = tag[:m[0]] + tag[m[6]:]
+			}
+			attrs = append(attrs, `rel="`+html.EscapeString(strings.Join(mergeRel(strings.Fields(current), opts.Rel), " "))+`"`)
+		}
+		if len(attrs) == 0 {
+			return tag
+		}
+		end := tagEndRe.FindString(tag)
+		return strings.TrimSuffix(tag, end) + " " + strings.Join(attrs, " ") + strings.TrimLeft(end, " \t\r\n")
+	})
+}
+
+// mergeRel appends the values of add missing from rel, ignoring case.
+func mergeRel(rel, add []string) []string {
+	merged := append([]string(nil), rel...)
+	for _, v := range add {
+		found := false
+		for _, r := range merged {
+			if strings.EqualFold(r, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
+
+// trackingParams are query parameters analytics and ad platforms append to
+// links, besides the utm_ ones. Kept on links to the site, they make the same
+// page known by several URLs and count visits twice.
+var trackingParams = []string{"fbclid", "gclid"}
+
+func isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, "utm_") {
+		return true
+	}
+	for _, p := range trackingParams {
+		if name == p {
+			return true
+		}
+	}
+	return false
+}
+
+// stripTrackingParams removes the tracking parameters from the query of
+// rawURL, keeping the other parameters in order, as written, and the fragment.
+func stripTrackingParams(rawURL string) string {
+	rest, fragment := rawURL, ""
+	if i := strings.Index(rest, "#"); i >= 0 {
+		rest, fragment = rest[:i], rest[i:]
+	}
+	i := strings.Index(rest, "?")
+	if i < 0 {
+		return rawURL
+	}
+	var kept []string
+	for _, pair := range strings.Split(rest[i+1:], "&") {
+		name, _, _ := strings.Cut(pair, "=")
+		if n, err := url.QueryUnescape(name); pair == "" || (err == nil && isTrackingParam(n)) {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	if len(kept) == 0 {
+		return rest[:i] + fragment
+	}
+	return rest[:i+1] + strings.Join(kept, "&") + fragment
+}
+
+// cleanInternalURL removes the tracking parameters from rawURL when it leads
+// to the site at host: a relative URL or an absolute one on host. Links to
+// other sites and other schemes, such as mailto, are returned as they are.
+func cleanInternalURL(rawURL, host string) string {
+	if isExternalLink(rawURL, host) || (hrefSchemeRe.MatchString(strings.TrimSpace(rawURL)) && !isWebURL(strings.TrimSpace(rawURL))) {
+		return rawURL
+	}
+	return stripTrackingParams(rawURL)
+}
+
+// cleanTrackingLinks removes the tracking parameters from the links in a
+// rendered body that lead to the site at baseHost, and from those to other
+// sites as well when external is set.
+func cleanTrackingLinks(body, baseHost string, external bool) string {
+	return anchorTagRe.ReplaceAllStringFunc(body, func(tag string) string {
+		m := hrefAttrRe.FindStringSubmatchIndex(tag)
+		if m == nil || m[4] < 0 {
+			return tag
+		}
+		href := html.UnescapeString(strings.Trim(tag[m[4]:m[5]], `"'`))
+		cleaned := cleanInternalURL(href, baseHost)
+		if external && isExternalLink(href, baseHost) {
+			cleaned = stripTrackingParams(href)
+		}
+		if cleaned == href {
+			return tag
+		}
+		return tag[:m[4]] + `"` + html.EscapeString(cleaned) + `"` + tag[m[5]:]
+	})
+}
+
+// siteHost returns the host of the site's base URL, or "" without one.
+func siteHost(params map[string]string) string {
+	u, err := url.Parse(siteBaseURL(params))
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
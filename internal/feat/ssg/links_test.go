@@ -0,0 +1,161 @@
+package ssg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsExternalLink(t *testing.T) {
+	tests := []struct {
+		href string
+		want bool
+	}{
+		{"https://other.com/post", true},
+		{"http://other.com", true},
+		{"//cdn.other.com/file.js", true},
+		{"HTTPS://Other.com/", true},
+		{"https://example.com/blog/", false},
+		{"https://EXAMPLE.com:443/blog/", false},
+		{"//example.com/about/", false},
+		{"/blog/post/", false},
+		{"blog/post/", false},
+		{"#section", false},
+		{"?page=2", false},
+		{"mailto:me@other.com", false},
+		{"tel:+123", false},
+		{"javascript:void(0)", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isExternalLink(tt.href, "example.com"); got != tt.want {
+			t.Errorf("isExternalLink(%q) = %v, want %v", tt.href, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteExternalLinks(t *testing.T) {
+	blank := linkRewriteOptionsFromParams(map[string]string{ExternalLinkTargetRefKey: "_blank", ExternalLinkRelRefKey: "nofollow"})
+
+	tests := []struct {
+		name string
+		opts linkRewriteOptions
+		in   string
+		want string
+	}{
+		{"external link", blank,
+			`<a href="https://other.com/">x</a>`,
+			`<a href="https://other.com/" target="_blank" rel="noopener nofollow">x</a>`},
+		{"internal absolute link", blank,
+			`<a href="https://example.com/about/">x</a>`,
+			`<a href="https://example.com/about/">x</a>`},
+		{"relative link", blank,
+			`<a href="/about/">x</a> <a href="#top">y</a>`,
+			`<a href="/about/">x</a> <a href="#top">y</a>`},
+		{"existing rel is merged", blank,
+			`<a rel="me" href="https://other.com/">x</a>`,
+			`<a href="https://other.com/" target="_blank" rel="me noopener nofollow">x</a>`},
+		{"existing rel values are not repeated", blank,
+			`<a href="https://other.com/" rel='NoFollow external'>x</a>`,
+			`<a href="https://other.com/" target="_blank" rel="NoFollow external noopener">x</a>`},
+		{"existing target is kept", blank,
+			`<a href="https://other.com/" target="_self">x</a>`,
+			`<a href="https://other.com/" target="_self" rel="noopener nofollow">x</a>`},
+		{"rel only", linkRewriteOptions{Rel: []string{"sponsored"}},
+			`<A HREF="https://other.com/" class="btn">x</A>`,
+			`<A HREF="https://other.com/" class="btn" rel="sponsored">x</A>`},
+		{"anchor without href", blank,
+			`<a name="top"></a>`,
+			`<a name="top"></a>`},
+		{"no options", linkRewriteOptions{},
+			`<a href="https://other.com/">x</a>`,
+			`<a href="https://other.com/">x</a>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteExternalLinks(tt.in, "example.com", tt.opts); got != tt.want {
+				t.Errorf("rewriteExternalLinks() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessContentExternalLinks(t *testing.T) {
+	p := NewProcessor()
+	params := map[string]string{
+		BaseURLRefKey:            "https://example.com",
+		ExternalLinkTargetRefKey: "_blank",
+		ExternalLinkRelRefKey:    "nofollow sponsored",
+	}
+	content := &Content{Body: "[shop](https://shop.other.com/item) and [home](https://example.com/)"}
+
+	got, err := p.ProcessContent(content, params)
+	if err != nil {
+		t.Fatalf("ProcessContent() error = %v", err)
+	}
+	if !strings.Contains(got, `<a href="https://shop.other.com/item" target="_blank" rel="noopener nofollow sponsored">shop</a>`) {
+		t.Errorf("external link not rewritten: %s", got)
+	}
+	if !strings.Contains(got, `<a href="https://example.com/">home</a>`) {
+		t.Errorf("internal link rewritten: %s", got)
+	}
+
+	content.Meta = &Meta{KeepLinks: true}
+	got, err = p.ProcessContent(content, params)
+	if err != nil {
+		t.Fatalf("ProcessContent() error = %v", err)
+	}
+	if strings.Contains(got, "nofollow") || strings.Contains(got, "target=") {
+		t.Errorf("links rewritten for content that keeps them: %s", got)
+	}
+}
+
+func TestCleanInternalURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"/blog/post/?utm_source=news&utm_medium=email", "/blog/post/"},
+		{"/search/?q=go&UTM_Campaign=x&page=2#results", "/search/?q=go&page=2#results"},
+		{"https://example.com/about/?fbclid=abc", "https://example.com/about/"},
+		{"//example.com/about/?gclid=abc&ref=home", "//example.com/about/?ref=home"},
+		{"post/?utm%5Fsource=x", "post/"},
+		{"?utm_source=x#top", "#top"},
+		{"/blog/?utmost=1", "/blog/?utmost=1"},
+		{"https://other.com/?utm_source=x", "https://other.com/?utm_source=x"},
+		{"mailto:me@example.com?subject=hi&utm_source=x", "mailto:me@example.com?subject=hi&utm_source=x"},
+	}
+	for _, tt := range tests {
+		if got := cleanInternalURL(tt.url, "example.com"); got != tt.want {
+			t.Errorf("cleanInternalURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestProcessContentTrackingParams(t *testing.T) {
+	p := NewProcessor()
+	params := map[string]string{BaseURLRefKey: "https://example.com"}
+	content := &Content{Body: "[post](/blog/post/?utm_source=news&id=3), [home](https://example.com/?fbclid=x) and [shop](https://shop.other.com/item?utm_source=example&id=3)"}
+
+	got, err := p.ProcessContent(content, params)
+	if err != nil {
+		t.Fatalf("ProcessContent() error = %v", err)
+	}
+	for _, want := range []string{
+		`<a href="/blog/post/?id=3">post</a>`,
+		`<a href="https://example.com/">home</a>`,
+		`<a href="https://shop.other.com/item?utm_source=example&amp;id=3">shop</a>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ProcessContent() = %s, want it to contain %s", got, want)
+		}
+	}
+
+	params[ExternalLinkStripTrackingRefKey] = "true"
+	got, err = p.ProcessContent(content, params)
+	if err != nil {
+		t.Fatalf("ProcessContent() error = %v", err)
+	}
+	if !strings.Contains(got, `<a href="https://shop.other.com/item?id=3">shop</a>`) {
+		t.Errorf("external link keeps its tracking parameters with %s on: %s", ExternalLinkStripTrackingRefKey, got)
+	}
+}
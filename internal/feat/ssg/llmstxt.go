@@ -0,0 +1,169 @@
+package ssg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Setting ref keys for the files that describe the site to AI crawlers.
+const (
+	// LLMsTxtRefKey writes llms.txt at the site root when "true".
+	LLMsTxtRefKey = "ssg.llmstxt"
+	// LLMsTxtDescriptionRefKey is the summary under the site name in
+	// llms.txt. Empty uses the site description.
+	LLMsTxtDescriptionRefKey = "ssg.llmstxt.description"
+	// LLMsTxtPolicyRefKey is allow or deny: whether AI crawlers may use the
+	// site's content at all.
+	LLMsTxtPolicyRefKey = "ssg.llmstxt.policy"
+	// LLMsTxtDisallowRefKey lists section paths AI crawlers should leave
+	// alone. They are kept out of llms.txt and disallowed in ai.txt.
+	LLMsTxtDisallowRefKey = "ssg.llmstxt.disallow"
+	// AITxtRefKey also writes the allow and deny directives to ai.txt.
+	AITxtRefKey = "ssg.llmstxt.aitxt"
+)
+
+// AI crawler policies.
+const (
+	AIPolicyAllow = "allow"
+	AIPolicyDeny  = "deny"
+)
+
+// siteDescriptionRefKey is the site description shown in the hero and meta.
+const siteDescriptionRefKey = "site_description"
+
+func llmsTxtEnabled(params map[string]string) bool {
+	return params[LLMsTxtRefKey] == "true"
+}
+
+func aiTxtEnabled(params map[string]string) bool {
+	return params[AITxtRefKey] == "true"
+}
+
+// aiPolicy returns the configured policy. Anything but deny allows, and
+// noindex sites deny whatever is configured.
+func aiPolicy(params map[string]string) string {
+	if siteNoIndex(params) || strings.TrimSpace(params[LLMsTxtPolicyRefKey]) == AIPolicyDeny {
+		return AIPolicyDeny
+	}
+	return AIPolicyAllow
+}
+
+// aiDisallowedSection reports whether the section at path is listed in
+// ssg.llmstxt.disallow.
+func aiDisallowedSection(params map[string]string, path string) bool {
+	for _, p := range strings.Split(params[LLMsTxtDisallowRefKey], ",") {
+		if p = strings.TrimSpace(p); p != "" && normalizePath(p) == normalizePath(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildLLMsTxt returns the llms.txt for the site: its name, a description and
+// one list of links per section, from the pages the sitemap lists. Links are
+// absolute when the site has a base URL. A deny policy lists no pages.
+func buildLLMsTxt(site *Site, sections []*Section, entries []sitemapEntry, baseURL string, params map[string]string) string {
+	var b strings.Builder
+	b.WriteString("# " + site.Name + "\n")
+
+	description := strings.TrimSpace(params[LLMsTxtDescriptionRefKey])
+	if description == "" {
+		description = strings.TrimSpace(params[siteDescriptionRefKey])
+	}
+	if description != "" {
+		b.WriteString("\n> " + strings.Join(strings.Fields(description), " ") + "\n")
+	}
+
+	if aiPolicy(params) == AIPolicyDeny {
+		b.WriteString("\nThe content of this site is not available for use by AI systems.\n")
+		return b.String()
+	}
+
+	link := func(title, path, summary string) string {
+		line := "- [" + strings.Join(strings.Fields(title), " ") + "](" + strings.TrimRight(baseURL, "/") + path + ")"
+		if summary = strings.Join(strings.Fields(summary), " "); summary != "" {
+			line += ": " + summary
+		}
+		return line + "\n"
+	}
+
+	sectionPath := make(map[uuid.UUID]string, len(sections))
+	for _, s := range sections {
+		sectionPath[s.ID] = s.Path
+	}
+	lines := make(map[uuid.UUID][]string)
+	var pages []string
+	var order []*Section
+	for _, e := range entries {
+		switch {
+		case e.Section != nil:
+			if aiDisallowedSection(params, e.Section.Path) {
+				continue
+			}
+			order = append(order, e.Section)
+			lines[e.Section.ID] = append(lines[e.Section.ID], link(e.Section.Name, e.Path, e.Section.Description))
+		case e.Content != nil:
+			path := sectionPath[e.Content.SectionID]
+			if path == "" || path == "/" {
+				pages = append(pages, link(e.Content.Heading, e.Path, e.Content.Summary))
+				continue
+			}
+			if aiDisallowedSection(params, path) {
+				continue
+			}
+			lines[e.Content.SectionID] = append(lines[e.Content.SectionID], link(e.Content.Heading, e.Path, e.Content.Summary))
+		}
+	}
+
+	if len(pages) > 0 {
+		b.WriteString("\n## Pages\n\n" + strings.Join(pages, ""))
+	}
+	for _, s := range order {
+		b.WriteString("\n## " + s.Name + "\n\n" + strings.Join(lines[s.ID], ""))
+	}
+	return b.String()
+}
+
+// buildAITxt returns the ai.txt directives for every AI crawler: the site
+// root allowed or denied, and the disallowed sections.
+func buildAITxt(basePath string, params map[string]string) string {
+	root := strings.TrimRight(basePath, "/")
+	var b strings.Builder
+	b.WriteString("User-Agent: *\n")
+	if aiPolicy(params) == AIPolicyDeny {
+		b.WriteString("Disallow: " + root + "/\n")
+		return b.String()
+	}
+	b.WriteString("Allow: " + root + "/\n")
+	for _, p := range strings.Split(params[LLMsTxtDisallowRefKey], ",") {
+		if p = strings.Trim(strings.TrimSpace(p), "/"); p != "" {
+			b.WriteString("Disallow: " + root + "/" + p + "/\n")
+		}
+	}
+	return b.String()
+}
+
+// generateLLMsTxt writes llms.txt and, when enabled, ai.txt, and returns the
+// names of the files written. Noindex sites get no llms.txt.
+func (g *HTMLGenerator) generateLLMsTxt(htmlPath, baseURL, basePath string, site *Site, contents []*Content, sections []*Section, params map[string]string) ([]string, error) {
+	var written []string
+	if llmsTxtEnabled(params) && !siteNoIndex(params) {
+		entries := g.sitemapEntries(basePath, contents, sections, params, time.Now())
+		body := buildLLMsTxt(site, sections, entries, baseURL, params)
+		if err := os.WriteFile(filepath.Join(htmlPath, "llms.txt"), []byte(body), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, "llms.txt")
+	}
+	if aiTxtEnabled(params) {
+		if err := os.WriteFile(filepath.Join(htmlPath, "ai.txt"), []byte(buildAITxt(basePath, params)), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, "ai.txt")
+	}
+	return written, nil
+}
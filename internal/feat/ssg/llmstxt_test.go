@@ -0,0 +1,120 @@
+package ssg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateLLMsTxt(t *testing.T) {
+	g := &HTMLGenerator{}
+
+	publishedAt := time.Now().Add(-time.Hour)
+	siteID := uuid.New()
+	main := &Section{ID: uuid.New(), SiteID: siteID, Name: "main", Path: "/"}
+	blog := &Section{ID: uuid.New(), SiteID: siteID, Name: "Blog", Path: "blog", Description: "Notes on programming"}
+	hidden := &Section{ID: uuid.New(), SiteID: siteID, Name: "Private notes", Path: "notes"}
+	sections := []*Section{main, blog, hidden}
+
+	content := func(section *Section, heading, summary string) *Content {
+		return &Content{ID: uuid.New(), SiteID: siteID, SectionID: section.ID, SectionPath: section.Path, ShortID: "abc123",
+			Heading: heading, Summary: summary, PublishedAt: &publishedAt, UpdatedAt: publishedAt}
+	}
+	draft := content(blog, "Draft post", "")
+	draft.Draft = true
+	unlisted := content(blog, "Unlisted post", "")
+	unlisted.Visibility = VisibilityUnlisted
+	private := content(blog, "Private post", "")
+	private.Visibility = VisibilityPrivate
+	excluded := content(blog, "Excluded post", "")
+	excluded.Meta = &Meta{Sitemap: "exclude"}
+	contents := []*Content{
+		content(main, "About", "Who writes here"),
+		content(blog, "First post", "What this\nblog is about"),
+		content(hidden, "Secret", ""),
+		draft, unlisted, private, excluded,
+	}
+	site := &Site{ID: siteID, Name: "My Blog", Slug: "my-blog"}
+
+	params := map[string]string{
+		LLMsTxtRefKey:         "true",
+		siteDescriptionRefKey: "A personal blog",
+		LLMsTxtDisallowRefKey: "notes",
+		AITxtRefKey:           "true",
+	}
+	dir := t.TempDir()
+	written, err := g.generateLLMsTxt(dir, "https://example.com", "/", site, contents, sections, params)
+	if err != nil {
+		t.Fatalf("generateLLMsTxt() error = %v", err)
+	}
+	if strings.Join(written, ",") != "llms.txt,ai.txt" {
+		t.Errorf("written = %v, want llms.txt and ai.txt", written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "llms.txt"))
+	if err != nil {
+		t.Fatalf("cannot read llms.txt: %v", err)
+	}
+	want := `# My Blog
+
+> A personal blog
+
+## Pages
+
+- [About](https://example.com/about-abc123/): Who writes here
+
+## Blog
+
+- [Blog](https://example.com/blog/): Notes on programming
+- [First post](https://example.com/blog/first-post-abc123/): What this blog is about
+`
+	if string(data) != want {
+		t.Errorf("llms.txt =\n%s\nwant\n%s", data, want)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "ai.txt"))
+	if err != nil {
+		t.Fatalf("cannot read ai.txt: %v", err)
+	}
+	if want := "User-Agent: *\nAllow: /\nDisallow: /notes/\n"; string(data) != want {
+		t.Errorf("ai.txt = %q, want %q", data, want)
+	}
+
+	t.Run("deny", func(t *testing.T) {
+		params := map[string]string{LLMsTxtRefKey: "true", LLMsTxtDescriptionRefKey: "Mine", LLMsTxtPolicyRefKey: AIPolicyDeny}
+		got := buildLLMsTxt(site, sections, g.sitemapEntries("/", contents, sections, params, time.Now()), "", params)
+		if strings.Contains(got, "](") || !strings.HasPrefix(got, "# My Blog\n\n> Mine\n") {
+			t.Errorf("llms.txt with deny policy =\n%s", got)
+		}
+		if got, want := buildAITxt("/docs/", params), "User-Agent: *\nDisallow: /docs/\n"; got != want {
+			t.Errorf("ai.txt = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("noindex", func(t *testing.T) {
+		dir := t.TempDir()
+		params := map[string]string{LLMsTxtRefKey: "true", AITxtRefKey: "true", NoIndexRefKey: "true"}
+		written, err := g.generateLLMsTxt(dir, "", "/", site, contents, sections, params)
+		if err != nil {
+			t.Fatalf("generateLLMsTxt() error = %v", err)
+		}
+		if strings.Join(written, ",") != "ai.txt" {
+			t.Errorf("written = %v, want only ai.txt", written)
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, "ai.txt"))
+		if !strings.Contains(string(data), "Disallow: /\n") {
+			t.Errorf("ai.txt on a noindex site = %q, want the whole site disallowed", data)
+		}
+	})
+
+	t.Run("off", func(t *testing.T) {
+		written, err := g.generateLLMsTxt(t.TempDir(), "", "/", site, contents, sections, nil)
+		if err != nil || len(written) != 0 {
+			t.Errorf("generateLLMsTxt() = %v, %v, want nothing written", written, err)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package ssg
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// markdownImageRef matches the workspace images a body links to, as
+// /images/... or as the editor's /ssg/workspace/{slug}/images/... URLs.
+var markdownImageRef = regexp.MustCompile(`/images/([^\s)"'?#<>]+)`)
+
+// ExportMarkdownZip writes contents to w as a zip of markdown files with
+// frontmatter, laid out as in the markdown backup, followed by the images
+// they reference under images/. Frontmatter dates are written in loc.
+//
+// The archive is written as it is built, so w can be the response itself.
+// Images missing from the workspace are left out.
+func (g *Generator) ExportMarkdownZip(ctx context.Context, w io.Writer, siteSlug string, contents []*Content, loc *time.Location) error {
+	zw := zip.NewWriter(w)
+
+	var images []string
+	seen := make(map[string]bool)
+	for _, content := range contents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := MarshalContentMarkdown(content, loc)
+		if err != nil {
+			return fmt.Errorf("cannot export %q: %w", content.Heading, err)
+		}
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: contentMarkdownPath(content), Method: zip.Deflate, Modified: content.UpdatedAt})
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+
+		for _, rel := range contentImagePaths(content) {
+			if !seen[rel] {
+				seen[rel] = true
+				images = append(images, rel)
+			}
+		}
+	}
+
+	imagesPath := g.workspace.GetImagesPath(siteSlug)
+	for _, rel := range images {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := addZipFile(zw, "images/"+rel, filepath.Join(imagesPath, filepath.FromSlash(rel))); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// contentImagePaths returns the workspace images content uses, header image
+// first, relative to the images directory.
+func contentImagePaths(content *Content) []string {
+	var refs []string
+	if rel, ok := strings.CutPrefix(content.HeaderImageURL, "/images/"); ok {
+		refs = append(refs, rel)
+	}
+	for _, m := range markdownImageRef.FindAllStringSubmatch(content.Body, -1) {
+		refs = append(refs, m[1])
+	}
+
+	var paths []string
+	for _, rel := range refs {
+		if clean := path.Clean("/" + rel)[1:]; clean != "" && clean == rel {
+			paths = append(paths, rel)
+		}
+	}
+	return paths
+}
+
+// addZipFile stores the file at name in the archive, skipping it when it does
+// not exist. Images are already compressed, so they are stored as they are.
+func addZipFile(zw *zip.Writer, name, file string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return err
+	}
+	out, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: info.ModTime()})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, f); err != nil {
+		return fmt.Errorf("cannot add %s: %w", name, err)
+	}
+	return nil
+}
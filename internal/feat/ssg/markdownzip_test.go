@@ -0,0 +1,75 @@
+package ssg
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestExportMarkdownZip(t *testing.T) {
+	g := NewGenerator(NewWorkspace(t.TempDir()))
+	imagesPath := g.workspace.GetImagesPath("test")
+	if err := os.MkdirAll(filepath.Join(imagesPath, "2024"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"cover.png": "cover", "2024/inline.jpg": "inline", "unused.png": "unused"} {
+		if err := os.WriteFile(filepath.Join(imagesPath, filepath.FromSlash(name)), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	updated := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	post := &Content{ID: uuid.New(), ShortID: "abc12345", Heading: "First post", SectionPath: "blog", HeaderImageURL: "/images/cover.png",
+		Body: "![Inline](/ssg/workspace/test/images/2024/inline.jpg)\n\n![Gone](/images/gone.png)", UpdatedAt: updated}
+	note := &Content{ID: uuid.New(), ShortID: "def67890", Heading: "A note", SectionPath: "notes", Draft: true,
+		Body: "Same cover: ![Cover](/images/cover.png)", UpdatedAt: updated}
+
+	var buf bytes.Buffer
+	if err := g.ExportMarkdownZip(context.Background(), &buf, "test", []*Content{post, note}, time.UTC); err != nil {
+		t.Fatalf("ExportMarkdownZip() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	var names []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(data)
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+
+	want := []string{"blog/first-post-abc12345.md", "images/2024/inline.jpg", "images/cover.png", "notes/a-note-def67890.md"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("archive files = %v, want %v", names, want)
+	}
+	if got := files["notes/a-note-def67890.md"]; !strings.Contains(got, "section: notes") || !strings.Contains(got, "draft: true") {
+		t.Errorf("note frontmatter does not keep its section and draft state:\n%s", got)
+	}
+	if got := files["blog/first-post-abc12345.md"]; !strings.Contains(got, "section: blog") || !strings.HasSuffix(strings.TrimSpace(got), "![Gone](/images/gone.png)") {
+		t.Errorf("post file = %s", got)
+	}
+	if files["images/cover.png"] != "cover" {
+		t.Errorf("images/cover.png = %q, want cover", files["images/cover.png"])
+	}
+}
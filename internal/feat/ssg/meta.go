@@ -37,6 +37,7 @@ type MetaImage struct {
 	Caption        string `yaml:"caption,omitempty"`
 	Attribution    string `yaml:"attribution,omitempty"`
 	AttributionURL string `yaml:"attribution_url,omitempty"`
+	Stock          bool   `yaml:"stock,omitempty"`
 }
 
 // MetaContentImage represents a content-image association for YAML export.
@@ -103,6 +104,7 @@ func ImageToMeta(i *Image) *MetaImage {
 		Caption:        i.Title,
 		Attribution:    i.Attribution,
 		AttributionURL: i.AttributionURL,
+		Stock:          i.Stock,
 	}
 }
 
@@ -142,7 +142,7 @@ func (g *MetaGenerator) GenerateMeta(
 		metaImages := make(map[string]*MetaImage)
 		for _, img := range images {
 			// Only include images that have metadata
-			if img.AltText != "" || img.Title != "" || img.Attribution != "" || img.AttributionURL != "" {
+			if img.AltText != "" || img.Title != "" || img.Attribution != "" || img.AttributionURL != "" || img.Stock {
 				metaImages[img.FilePath] = ImageToMeta(img)
 			}
 		}
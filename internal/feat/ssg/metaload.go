@@ -267,6 +267,7 @@ func (l *MetaLoader) HydrateImages(ctx context.Context, siteID uuid.UUID, siteSl
 				img.Title = mi.Caption
 				img.Attribution = mi.Attribution
 				img.AttributionURL = mi.AttributionURL
+				img.Stock = mi.Stock
 				img.UpdatedBy = userID
 
 				if err := l.service.UpdateImage(ctx, img); err != nil {
@@ -288,6 +289,7 @@ func (l *MetaLoader) HydrateImages(ctx context.Context, siteID uuid.UUID, siteSl
 				image.Title = mi.Caption
 				image.Attribution = mi.Attribution
 				image.AttributionURL = mi.AttributionURL
+				image.Stock = mi.Stock
 			}
 
 			if err := l.service.CreateImage(ctx, image); err != nil {
@@ -0,0 +1,46 @@
+package ssg
+
+import (
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/metrics"
+)
+
+// generationBuckets are upper bounds in seconds for whole site generations,
+// which take from a fraction of a second to minutes.
+var generationBuckets = []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// SetMetrics registers the generation collectors in reg. Without it nothing
+// is recorded.
+func (g *HTMLGenerator) SetMetrics(reg *metrics.Registry) {
+	g.generationTime = reg.Histogram("clio_generation_duration_seconds", "Time to generate a site's HTML, by site.", generationBuckets, "site")
+	g.pagesWritten = reg.Counter("clio_generated_pages_total", "Pages written by site generation, by site and kind of page.", "site", "kind")
+}
+
+func (g *HTMLGenerator) recordGeneration(site *Site, result *GenerateHTMLResult, elapsed time.Duration) {
+	g.generationTime.Observe(elapsed.Seconds(), site.Slug)
+	for kind, n := range map[string]int{
+		"content":    result.PagesGenerated,
+		"index":      result.IndexPages,
+		"author":     result.AuthorPages,
+		"tag":        result.TagPages,
+		"archive":    result.ArchivePages,
+		"pagination": result.PaginatedPages,
+		"redirect":   result.RedirectPages,
+	} {
+		g.pagesWritten.Add(float64(n), site.Slug, kind)
+	}
+}
+
+// SetMetrics registers the publish collector in reg. Without it nothing is
+// recorded.
+func (p *Publisher) SetMetrics(reg *metrics.Registry) {
+	p.publishes = reg.Counter("clio_publishes_total", "Site publishes to a git repository, by result: success or failure.", "result")
+}
+
+func publishOutcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
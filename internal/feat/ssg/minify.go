@@ -0,0 +1,289 @@
+package ssg
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MinifyRefKey is the setting that minifies generated HTML, CSS and JS.
+// It is off by default so diffs in the publish repository stay readable.
+const MinifyRefKey = "ssg.minify"
+
+// preservedElements keep their content byte for byte. Script and style
+// content is minified with the JS and CSS rules instead.
+var preservedElements = map[string]bool{
+	"pre":      true,
+	"code":     true,
+	"textarea": true,
+	"script":   true,
+	"style":    true,
+}
+
+// minifyOutput minifies the .html, .css and .js files under htmlPath in place
+// and returns the bytes saved. Uploaded images and profile photos are left
+// alone. Minifying is idempotent: pages kept from a previous build save nothing.
+func minifyOutput(htmlPath string) (int64, error) {
+	var saved int64
+	err := filepath.WalkDir(htmlPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if filepath.Dir(path) == htmlPath && (d.Name() == "images" || d.Name() == "profiles") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var minify func([]byte) []byte
+		switch filepath.Ext(path) {
+		case ".html":
+			minify = minifyHTML
+		case ".css":
+			minify = minifyCSS
+		case ".js":
+			minify = minifyJS
+		default:
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		out := minify(data)
+		if len(out) >= len(data) {
+			return nil
+		}
+		if err := os.WriteFile(path, out, 0644); err != nil {
+			return err
+		}
+		saved += int64(len(data) - len(out))
+		return nil
+	})
+	return saved, err
+}
+
+// minifyHTML strips comments and collapses whitespace runs between and around
+// tags to a single space, which renders the same outside preformatted
+// elements. Tags themselves are copied as is, and so is the content of
+// pre, code and textarea. Conditional comments are kept.
+func minifyHTML(src []byte) []byte {
+	var out bytes.Buffer
+	out.Grow(len(src))
+	space := false
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case bytes.HasPrefix(src[i:], []byte("<!--")):
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end < 0 {
+				end = len(src) - i - 4 - 3
+			}
+			comment := src[i : i+4+end+3]
+			if bytes.HasPrefix(comment, []byte("<!--[if")) || bytes.HasPrefix(comment, []byte("<!--<![endif")) {
+				writeSpace(&out, &space)
+				out.Write(comment)
+			}
+			i += len(comment)
+
+		case c == '<' && i+1 < len(src) && isTagStart(src[i+1]):
+			writeSpace(&out, &space)
+			end := tagEnd(src, i)
+			tag := src[i:end]
+			out.Write(tag)
+			i = end
+
+			name := tagName(tag)
+			if !preservedElements[name] || tag[1] == '/' || bytes.HasSuffix(tag, []byte("/>")) {
+				continue
+			}
+			closing := indexFold(src[i:], "</"+name)
+			if closing < 0 {
+				closing = len(src) - i
+			}
+			content := src[i : i+closing]
+			switch name {
+			case "script":
+				if isJavaScript(tag) {
+					content = minifyJS(content)
+				}
+			case "style":
+				content = minifyCSS(content)
+			}
+			out.Write(content)
+			i += closing
+
+		case isHTMLSpace(c):
+			space = true
+			i++
+
+		default:
+			writeSpace(&out, &space)
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.Bytes()
+}
+
+func writeSpace(out *bytes.Buffer, pending *bool) {
+	if *pending && out.Len() > 0 {
+		out.WriteByte(' ')
+	}
+	*pending = false
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// tagEnd returns the index just past the tag starting at start, skipping
+// '>' inside quoted attribute values.
+func tagEnd(src []byte, start int) int {
+	var quote byte
+	for i := start + 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i + 1
+		}
+	}
+	return len(src)
+}
+
+// tagName returns the lowercased element name of an opening or closing tag.
+func tagName(tag []byte) string {
+	name := bytes.TrimPrefix(tag[1:], []byte("/"))
+	end := bytes.IndexFunc(name, func(r rune) bool {
+		return r == '>' || r == '/' || isHTMLSpace(byte(r))
+	})
+	if end >= 0 {
+		name = name[:end]
+	}
+	return strings.ToLower(string(name))
+}
+
+func indexFold(s []byte, sub string) int {
+	return bytes.Index(bytes.ToLower(s), []byte(sub))
+}
+
+var scriptTypeRe = regexp.MustCompile(`(?i)\stype\s*=\s*["']?([^"'\s>]*)`)
+
+// isJavaScript reports whether a script tag holds JavaScript, as opposed to
+// data blocks such as JSON-LD or client-side templates.
+func isJavaScript(tag []byte) bool {
+	m := scriptTypeRe.FindSubmatch(tag)
+	if m == nil {
+		return true
+	}
+	switch strings.ToLower(string(m[1])) {
+	case "", "text/javascript", "application/javascript", "module":
+		return true
+	}
+	return false
+}
+
+// minifyCSS strips comments and collapses whitespace, dropping it next to
+// braces, semicolons, commas and child combinators, and drops the last
+// semicolon of each block. Strings are copied as is.
+func minifyCSS(src []byte) []byte {
+	const tight = "{};,>"
+	var out bytes.Buffer
+	out.Grow(len(src))
+	space := false
+	last := func() byte {
+		if out.Len() == 0 {
+			return 0
+		}
+		return out.Bytes()[out.Len()-1]
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(src))
+			if space && out.Len() > 0 && !strings.ContainsRune(tight, rune(last())) {
+				out.WriteByte(' ')
+			}
+			space = false
+			out.Write(src[i:end])
+			i = end
+
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(src)
+				break
+			}
+			i += 2 + end + 2
+			space = true
+
+		case isHTMLSpace(c):
+			space = true
+			i++
+
+		default:
+			if space && out.Len() > 0 && !strings.ContainsRune(tight, rune(last())) && !strings.ContainsRune(tight, rune(c)) {
+				out.WriteByte(' ')
+			}
+			space = false
+			if c == '}' && last() == ';' {
+				out.Truncate(out.Len() - 1)
+			}
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.Bytes()
+}
+
+// minifyJS trims every line and drops blank lines and whole-line // comments.
+// Line breaks are kept, so automatic semicolon insertion still applies.
+// Scripts with template literals are returned as is, since their lines may
+// be string content.
+func minifyJS(src []byte) []byte {
+	if bytes.IndexByte(src, '`') >= 0 {
+		return src
+	}
+	var out bytes.Buffer
+	out.Grow(len(src))
+	continued := false
+	for _, line := range bytes.Split(src, []byte("\n")) {
+		// A line continuing a string from the previous one is string content.
+		if !continued {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 || bytes.HasPrefix(line, []byte("//")) {
+				continue
+			}
+		}
+		if out.Len() > 0 {
+			out.WriteByte('\n')
+		}
+		out.Write(line)
+		continued = bytes.HasSuffix(bytes.TrimRight(line, "\r"), []byte("\\"))
+	}
+	return out.Bytes()
+}
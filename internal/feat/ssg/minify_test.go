@@ -0,0 +1,135 @@
+package ssg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMinifyHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			"whitespace and comments",
+			"<!DOCTYPE html>\n<html>\n  <body>\n    <!-- nav -->\n    <p>Hello,\n      <b>world</b>  !</p>\n  </body>\n</html>\n",
+			"<!DOCTYPE html> <html> <body> <p>Hello, <b>world</b> !</p> </body> </html>",
+		},
+		{
+			"preformatted content kept",
+			"<div>\n  <pre><code>func main() {\n    fmt.Println(\"a  b\")\n}</code></pre>\n  <textarea>  keep\n  me </textarea>\n  <code>x  =  1</code>\n</div>",
+			"<div> <pre><code>func main() {\n    fmt.Println(\"a  b\")\n}</code></pre> <textarea>  keep\n  me </textarea> <code>x  =  1</code> </div>",
+		},
+		{
+			"tags and attributes copied as is",
+			"<a  href=\"/a  b\"\n   title='x > y'>link</a>",
+			"<a  href=\"/a  b\"\n   title='x > y'>link</a>",
+		},
+		{
+			"inline script and style",
+			"<style>\n  body > p {\n    color: red;\n  }\n</style>\n<script>\n  // toggle\n  var a = 1\n  var b = \"//not a comment\"\n</script>",
+			"<style>body>p{color: red}</style> <script>var a = 1\nvar b = \"//not a comment\"</script>",
+		},
+		{
+			"data scripts kept",
+			"<script type=\"application/ld+json\">\n  {\"a\":  1}\n</script>",
+			"<script type=\"application/ld+json\">\n  {\"a\":  1}\n</script>",
+		},
+		{
+			"conditional comments kept",
+			"<!--[if IE]><p>old</p><![endif]-->",
+			"<!--[if IE]><p>old</p><![endif]-->",
+		},
+		{
+			"less than in text",
+			"<p>a < b</p>",
+			"<p>a < b</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(minifyHTML([]byte(tt.in))); got != tt.want {
+				t.Errorf("minifyHTML() =\n%q\nwant\n%q", got, tt.want)
+			}
+			if again := string(minifyHTML([]byte(tt.want))); again != tt.want {
+				t.Errorf("minifyHTML() is not idempotent: %q", again)
+			}
+		})
+	}
+}
+
+func TestMinifyCSS(t *testing.T) {
+	in := "/* theme */\n.a, .b > .c {\n  content: \"x  ;  }\";\n  margin: 0 auto;\n}\n@media (max-width: 600px) {\n  .a { display: none; }\n}\n"
+	want := `.a,.b>.c{content: "x  ;  }";margin: 0 auto}@media (max-width: 600px){.a{display: none}}`
+	if got := string(minifyCSS([]byte(in))); got != want {
+		t.Errorf("minifyCSS() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMinifyJS(t *testing.T) {
+	in := "  function f() {\n    // comment\n\n    return 1\n  }\n"
+	if got := string(minifyJS([]byte(in))); got != "function f() {\nreturn 1\n}" {
+		t.Errorf("minifyJS() = %q", got)
+	}
+
+	literal := "var s = `a\n    b`\n"
+	if got := string(minifyJS([]byte(literal))); got != literal {
+		t.Errorf("template literals should be left alone, got %q", got)
+	}
+
+	continued := "var s = \"a\\\n    b\"\n"
+	if got := string(minifyJS([]byte(continued))); got != "var s = \"a\\\n    b\"" {
+		t.Errorf("continued strings should keep their content, got %q", got)
+	}
+}
+
+func TestMinifyOutput(t *testing.T) {
+	htmlPath := t.TempDir()
+	files := map[string]string{
+		"index.html":           "<html>\n  <body>\n    <p>Hi</p>\n  </body>\n</html>\n",
+		"static/css/theme.css": "body {\n  margin: 0;\n}\n",
+		"images/photo.html":    "<p>\n  uploaded\n</p>",
+		"feed.xml":             "<rss>\n  <channel/>\n</rss>",
+	}
+	for name, data := range files {
+		path := filepath.Join(htmlPath, filepath.FromSlash(name))
+		if err := EnsureDir(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved, err := minifyOutput(htmlPath)
+	if err != nil {
+		t.Fatalf("minifyOutput() error = %v", err)
+	}
+
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(htmlPath, filepath.FromSlash(name)))
+		return string(data)
+	}
+	if got := read("index.html"); got != "<html> <body> <p>Hi</p> </body> </html>" {
+		t.Errorf("index.html = %q", got)
+	}
+	if got := read("static/css/theme.css"); got != "body{margin: 0}" {
+		t.Errorf("theme.css = %q", got)
+	}
+	for _, name := range []string{"images/photo.html", "feed.xml"} {
+		if read(name) != files[name] {
+			t.Errorf("%s should not be minified", name)
+		}
+	}
+
+	want := int64(len(files["index.html"]) - len(read("index.html")) + len(files["static/css/theme.css"]) - len(read("static/css/theme.css")))
+	if saved != want {
+		t.Errorf("saved = %d, want %d", saved, want)
+	}
+	if again, _ := minifyOutput(htmlPath); again != 0 {
+		t.Errorf("second pass saved %d bytes, want 0", again)
+	}
+}
@@ -3,6 +3,7 @@ package ssg
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -22,6 +23,8 @@ type Site struct {
 	DefaultLayoutID   uuid.UUID `json:"default_layout_id"`
 	DefaultLayoutName string     `json:"default_layout_name"`
 	LastPublishedAt   *time.Time `json:"last_published_at,omitempty"`
+	LastPublishedCommit string   `json:"last_published_commit,omitempty"`
+	LastGeneratedAt   *time.Time `json:"last_generated_at,omitempty"`
 	CreatedBy         uuid.UUID  `json:"-"`
 	UpdatedBy         uuid.UUID  `json:"-"`
 	CreatedAt         time.Time  `json:"created_at"`
@@ -64,6 +67,68 @@ func normalizePath(path string) string {
 	return strings.TrimLeft(path, "/")
 }
 
+// reservedPaths are top-level paths written by generation itself, mapped to
+// what lives there.
+var reservedPaths = map[string]string{
+	"tags":        "tag pages",
+	"authors":     "author pages",
+	"page":        "home page pagination",
+	"kinds":       "kind feeds",
+	"static":      "static assets",
+	"images":      "site images",
+	"profiles":    "contributor photos",
+	redirectsFile: "the redirects file",
+}
+
+// featurePaths are top-level paths generation writes only while the setting
+// of a feature turns it on.
+var featurePaths = []struct {
+	path    string
+	name    string
+	enabled func(params map[string]string) bool
+}{
+	{"feed", "feeds", func(p map[string]string) bool { return len(enabledFeedFormats(p)) > 0 && siteBaseURL(p) != "" }},
+	{"search", "the search page", searchPageEnabled},
+	{archiveDir, "date archives", func(p map[string]string) bool { return p[ArchiveRefKey] == "true" }},
+	{"sitemap.xml", "the sitemap", func(p map[string]string) bool { return siteBaseURL(p) != "" }},
+	{"robots.txt", "robots.txt", func(p map[string]string) bool { return siteNoIndex(p) || p["ssg.robots.txt"] != "" }},
+	{"llms.txt", "llms.txt", llmsTxtEnabled},
+	{"ai.txt", "ai.txt", aiTxtEnabled},
+	{changelogFile, "the changelog", func(p map[string]string) bool { return strings.TrimSpace(p[ChangelogSectionRefKey]) != "" }},
+}
+
+// reservedPath reports whether the first segment of path is used by generated
+// pages of a site with params, and by which.
+func reservedPath(params map[string]string, path string) (string, bool) {
+	first, _, _ := strings.Cut(strings.Trim(path, "/"), "/")
+	first = strings.ToLower(first)
+	if name, ok := reservedPaths[first]; ok {
+		return name, true
+	}
+	for _, f := range featurePaths {
+		if f.path == first && f.enabled(params) {
+			return f.name, true
+		}
+	}
+	return "", false
+}
+
+// Kinds of PathCollision.
+const (
+	PathCollisionContent  = "content"
+	PathCollisionSection  = "section"
+	PathCollisionReserved = "reserved"
+)
+
+// PathCollision is something else generated at the output path of an item
+// being saved. It is a warning: generation would overwrite one of them.
+type PathCollision struct {
+	Path string // Relative to the site root, without slashes around it
+	Kind string
+	Name string
+	Link string // Admin page of the conflicting item, empty for reserved paths
+}
+
 // NewSection creates a new Section instance.
 func NewSection(siteID uuid.UUID, name, description, path string) *Section {
 	now := time.Now()
@@ -79,6 +144,33 @@ func NewSection(siteID uuid.UUID, name, description, path string) *Section {
 	}
 }
 
+// SiteStats is an overview of a site for the dashboard.
+type SiteStats struct {
+	Published       int
+	Drafts          int
+	Scheduled       int
+	Sections        int
+	Tags            int
+	Images          int
+	Contributors    int
+	DiskUsage       int64 // Bytes used by the site workspace
+	RecentlyEdited  []*Content
+	LastPublishedAt *time.Time
+	ComputedAt      time.Time
+}
+
+// DiskUsageText returns the workspace size for display, e.g. "1.5 MB".
+func (s *SiteStats) DiskUsageText() string {
+	return formatBytes(s.DiskUsage)
+}
+
+// CloneOptions selects what CloneSite copies besides layouts, sections and settings.
+type CloneOptions struct {
+	Contributors bool
+	Content      bool      // Implies contributors, so authors still resolve
+	UserID       uuid.UUID // Recorded as creator of the new site
+}
+
 // Content represents a content item (article, page, etc.).
 type Content struct {
 	ID            uuid.UUID  `json:"id"`
@@ -89,7 +181,7 @@ type Content struct {
 	ContributorID     *uuid.UUID `json:"contributor_id,omitempty"`
 	ContributorHandle string     `json:"contributor_handle,omitempty"`
 	AuthorUsername    string     `json:"author_username,omitempty"`
-	Kind              string     `json:"kind"` // "post", "page", "series"
+	Kind              string     `json:"kind"` // "post", "page", "note"... see ContentKind
 	Heading       string     `json:"heading"`
 	Summary       string     `json:"summary"`
 	Body          string     `json:"body"`
@@ -98,13 +190,22 @@ type Content struct {
 	Series        string     `json:"series,omitempty"`
 	SeriesOrder   int        `json:"series_order,omitempty"`
 	PublishedAt   *time.Time `json:"published_at"`
+	Visibility    string     `json:"visibility"` // "public", "unlisted", "private"
+	Lang             string `json:"lang,omitempty"`              // Language code, e.g. "en" or "pt-BR". Empty uses the site language
+	TranslationGroup string `json:"translation_group,omitempty"` // Shared by the translations of the same content
+	LayoutID         uuid.UUID `json:"layout_id"` // Overrides the layouts of its kind and section. Nil inherits them
+	LinkURL          string    `json:"link_url,omitempty"` // Page a link post points to, see linkTarget
 
 	// Joined fields
 	SectionPath string       `json:"section_path,omitempty"`
 	SectionName string       `json:"section_name,omitempty"`
+	ReviewStatus string      `json:"review_status,omitempty"` // Status of its ContentReview, empty when never submitted
 	Tags        []*Tag       `json:"tags,omitempty"`
 	Meta        *Meta        `json:"meta,omitempty"`
 	Contributor *Contributor `json:"contributor,omitempty"`
+	KindInfo    *ContentKind `json:"-"` // Settings of Kind, see applyContentKinds
+	Translations []*Content  `json:"-"` // Other languages of the content, see linkTranslations
+	Fields       map[string]any `json:"fields,omitempty"` // Custom field values by name, typed, see ContentField
 
 	// Image fields (from relationships)
 	HeaderImageURL            string `json:"header_image_url,omitempty"`
@@ -112,8 +213,10 @@ type Content struct {
 	HeaderImageCaption        string `json:"header_image_caption,omitempty"`
 	HeaderImageAttribution    string `json:"header_image_attribution,omitempty"`
 	HeaderImageAttributionURL string `json:"header_image_attribution_url,omitempty"`
+	HeaderImageWidth          int    `json:"header_image_width,omitempty"`
+	HeaderImageHeight         int    `json:"header_image_height,omitempty"`
 
-	// Embedded images metadata (JSON map: path -> {title, alt, attribution, attribution_url})
+	// Embedded images metadata (JSON map: path -> {title, alt, attribution, attribution_url, width, height})
 	ImagesMeta string `json:"images_meta,omitempty"`
 
 	// Hero styling
@@ -129,16 +232,17 @@ type Content struct {
 func NewContent(siteID, sectionID uuid.UUID, heading, body string) *Content {
 	now := time.Now()
 	return &Content{
-		ID:        uuid.New(),
-		SiteID:    siteID,
-		SectionID: sectionID,
-		ShortID:   uuid.New().String()[:8],
-		Heading:   heading,
-		Body:      body,
-		Draft:     true,
-		Kind:      "post",
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:         uuid.New(),
+		SiteID:     siteID,
+		SectionID:  sectionID,
+		ShortID:    uuid.New().String()[:8],
+		Heading:    heading,
+		Body:       body,
+		Draft:      true,
+		Kind:       "post",
+		Visibility: VisibilityPublic,
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	}
 }
 
@@ -155,6 +259,122 @@ func (c *Content) DisplayHandle() string {
 	return c.AuthorUsername
 }
 
+// Status returns the content's publication status at now: draft, scheduled
+// while its publication date is ahead, or published.
+func (c *Content) Status(now time.Time) string {
+	if c.Draft {
+		return ContentStatusDraft
+	}
+	if c.PublishedAt != nil && c.PublishedAt.After(now) {
+		return ContentStatusScheduled
+	}
+	return ContentStatusPublished
+}
+
+// Content status values accepted by ContentFilter.
+const (
+	ContentStatusDraft     = "draft"
+	ContentStatusPublished = "published"
+	ContentStatusScheduled = "scheduled"
+)
+
+// Content visibility values. Unlisted content is generated but left out of
+// listings, feeds and the sitemap; private content is not generated at all.
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+)
+
+// ContentRevision is an earlier version of a content's text, saved when the
+// content is updated. A revision with a nil ID stands for the live content.
+type ContentRevision struct {
+	ID        uuid.UUID `json:"id"`
+	ContentID uuid.UUID `json:"content_id"`
+	Heading   string    `json:"heading"`
+	Summary   string    `json:"summary"`
+	Body      string    `json:"body"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsCurrent reports whether r is the live content rather than a saved revision.
+func (r *ContentRevision) IsCurrent() bool {
+	return r.ID == uuid.Nil
+}
+
+// EditLock marks content as being edited by a user, so others open it read
+// only. Locks are soft: they expire unless the editor's page keeps refreshing
+// them, so a closed tab or a crashed browser does not block the content.
+type EditLock struct {
+	ContentID  uuid.UUID `json:"content_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// HeldBy reports whether the lock belongs to userID.
+func (l *EditLock) HeldBy(userID uuid.UUID) bool {
+	return l.UserID == userID
+}
+
+// Publish job statuses.
+const (
+	PublishJobQueued    = "queued"
+	PublishJobRunning   = "running"
+	PublishJobSucceeded = "succeeded"
+	PublishJobFailed    = "failed"
+)
+
+// PublishJob is a generate-and-publish run of a site requested from the UI.
+// It runs in the background; Progress names the step in progress, and a
+// finished job keeps its commit or its error for the site's history.
+type PublishJob struct {
+	ID         uuid.UUID  `json:"id"`
+	SiteID     uuid.UUID  `json:"site_id"`
+	Status     string     `json:"status"`
+	Progress   string     `json:"progress"`
+	CommitHash string     `json:"commit_hash"`
+	CommitURL  string     `json:"commit_url"`
+	NoChanges  bool       `json:"no_changes"`
+	Error      string     `json:"error"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j *PublishJob) Done() bool {
+	return j.Status == PublishJobSucceeded || j.Status == PublishJobFailed
+}
+
+// normalizeVisibility returns v if it is a known visibility, public otherwise.
+func normalizeVisibility(v string) string {
+	switch v {
+	case VisibilityUnlisted, VisibilityPrivate:
+		return v
+	}
+	return VisibilityPublic
+}
+
+// ContentFilter narrows a content listing. Zero values match everything;
+// set fields are combined.
+type ContentFilter struct {
+	Search        string
+	Tag           string // Tag slug
+	SectionID     uuid.UUID
+	ContributorID uuid.UUID
+	Status        string
+	Visibility    string
+}
+
+// IsSet reports whether any filter, search included, is applied.
+func (f ContentFilter) IsSet() bool {
+	return f.Search != "" || f.Tag != "" || f.SectionID != uuid.Nil || f.ContributorID != uuid.Nil || f.Status != "" || f.Visibility != ""
+}
+
 // Layout represents a content layout template.
 type Layout struct {
 	ID                uuid.UUID `json:"id"`
@@ -186,17 +406,46 @@ func NewLayout(siteID uuid.UUID, name, description string) *Layout {
 	}
 }
 
+// BuiltinLayout returns the layout shipped with Clio. It is the last step of
+// layout resolution and has no code, so pages render with the embedded templates.
+func BuiltinLayout() *Layout {
+	return &Layout{
+		Name:        "Built-in",
+		Description: "Default layout shipped with Clio",
+	}
+}
+
+// IsBuiltin reports whether the layout is the built-in one.
+func (l *Layout) IsBuiltin() bool {
+	return l.ID == uuid.Nil
+}
+
+// Unstyled reports whether the layout drops the default stylesheet without
+// providing CSS of its own, which leaves pages with only core.css.
+func (l *Layout) Unstyled() bool {
+	return l.ExcludeDefaultCSS && strings.TrimSpace(l.CSS) == ""
+}
+
+// isSet reports whether a layout changes anything over the built-in one.
+// Empty layouts are skipped during resolution.
+func (l *Layout) isSet() bool {
+	return l != nil && (l.Code != "" || l.CSS != "" || l.ExcludeDefaultCSS)
+}
+
 // Tag represents a content tag.
 type Tag struct {
-	ID        uuid.UUID `json:"id"`
-	SiteID    uuid.UUID `json:"site_id"`
-	ShortID   string    `json:"short_id"`
-	Name      string    `json:"name"`
-	Slug      string    `json:"slug"`
-	CreatedBy uuid.UUID `json:"-"`
-	UpdatedBy uuid.UUID `json:"-"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             uuid.UUID `json:"id"`
+	SiteID         uuid.UUID `json:"site_id"`
+	ShortID        string    `json:"short_id"`
+	Name           string    `json:"name"`
+	Slug           string    `json:"slug"`
+	Description    string    `json:"description,omitempty"`
+	HeaderImageID  uuid.UUID `json:"header_image_id"`
+	HeaderImageURL string    `json:"header_image_url,omitempty"` // Populated only when listing tags for content
+	CreatedBy      uuid.UUID `json:"-"`
+	UpdatedBy      uuid.UUID `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 // NewTag creates a new Tag instance.
@@ -213,6 +462,54 @@ func NewTag(siteID uuid.UUID, name string) *Tag {
 	}
 }
 
+// Collection groups images of a site to find them in the library. It is only
+// organizational: image files stay where they are, whatever their collections.
+type Collection struct {
+	ID         uuid.UUID `json:"id"`
+	SiteID     uuid.UUID `json:"site_id"`
+	ShortID    string    `json:"short_id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	ImageCount int       `json:"image_count"` // Populated only when listing the collections of a site
+	CreatedBy  uuid.UUID `json:"-"`
+	UpdatedBy  uuid.UUID `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NewCollection creates a new Collection instance.
+func NewCollection(siteID uuid.UUID, name string) *Collection {
+	now := time.Now()
+	return &Collection{
+		ID:        uuid.New(),
+		SiteID:    siteID,
+		ShortID:   uuid.New().String()[:8],
+		Name:      name,
+		Slug:      Slugify(name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks the collection has a name to list it by.
+func (c *Collection) Validate() error {
+	if strings.TrimSpace(c.Name) == "" || c.Slug == "" {
+		return ErrCollectionName
+	}
+	return nil
+}
+
+// ContentMove reports what was remapped when moving content between sites.
+type ContentMove struct {
+	ImagesCopied int `json:"images_copied"`
+	ImagesReused int `json:"images_reused"` // Target site already had the file
+	TagsMapped   int `json:"tags_mapped"`
+	TagsCreated  int `json:"tags_created"`
+	// ContributorDropped is the handle of a contributor missing on the target
+	// site. The content keeps its author username instead.
+	ContributorDropped string `json:"contributor_dropped,omitempty"`
+}
+
 // Meta represents SEO metadata for content.
 type Meta struct {
 	ID              uuid.UUID `json:"id"`
@@ -229,6 +526,8 @@ type Meta struct {
 	TableOfContents bool      `json:"table_of_contents"`
 	Share           bool      `json:"share"`
 	Comments        bool      `json:"comments"`
+	KeepLinks       bool      `json:"keep_links"`   // Leave external links as written, see rewriteExternalLinks
+	ShowUpdated     bool      `json:"show_updated"` // Show when the content was last updated, see updatedDate
 	CreatedBy       uuid.UUID `json:"-"`
 	UpdatedBy       uuid.UUID `json:"-"`
 	CreatedAt       time.Time `json:"created_at"`
@@ -248,6 +547,22 @@ func NewMeta(siteID, contentID uuid.UUID) *Meta {
 	}
 }
 
+// NoIndex reports whether the content asks not to be indexed. A nil meta
+// does not.
+func (m *Meta) NoIndex() bool {
+	return m != nil && robotsNoIndex(m.Robots)
+}
+
+// Validate checks the meta fields. An empty canonical URL means the page's own
+// URL is used; a manual one, e.g. pointing a cross-post to the original, must
+// be an absolute http(s) URL and is used as is.
+func (m *Meta) Validate() error {
+	if m.CanonicalURL != "" && !isWebURL(m.CanonicalURL) {
+		return fmt.Errorf("canonical URL %q must be an absolute http or https URL", m.CanonicalURL)
+	}
+	return nil
+}
+
 // Setting type constants.
 const (
 	SettingTypeString  = "string"
@@ -282,6 +597,7 @@ type Setting struct {
 	Type        string    `json:"type"`
 	Constraints string    `json:"constraints"`
 	UIControl   string    `json:"ui_control"`
+	Secret      bool      `json:"secret"` // value encrypted at rest
 	CreatedBy   uuid.UUID `json:"-"`
 	UpdatedBy   uuid.UUID `json:"-"`
 	CreatedAt   time.Time `json:"created_at"`
@@ -308,7 +624,8 @@ func (p *Setting) MaskedValue() string {
 	}
 
 	lower := strings.ToLower(p.Name) + strings.ToLower(p.RefKey)
-	sensitive := strings.Contains(lower, "token") ||
+	sensitive := p.Secret ||
+		strings.Contains(lower, "token") ||
 		strings.Contains(lower, "pass") ||
 		strings.Contains(lower, "secret") ||
 		strings.Contains(lower, "key") ||
@@ -415,6 +732,33 @@ func (p *Setting) Validate() error {
 			}
 		}
 	}
+
+	switch p.RefKey {
+	case BaseURLRefKey:
+		if _, err := NormalizeBaseURL(p.Value); err != nil {
+			return err
+		}
+	case DomainRefKey:
+		if _, err := NormalizeDomain(p.Value); err != nil {
+			return err
+		}
+	case PermalinkRefKey:
+		if _, err := ParsePermalinkPattern(p.Value); err != nil {
+			return err
+		}
+	case TimezoneRefKey:
+		if _, err := LoadTimezone(p.Value); err != nil {
+			return err
+		}
+	case LanguageRefKey:
+		if _, err := NormalizeLang(p.Value); err != nil {
+			return err
+		}
+	case HeadHTMLRefKey, FooterHTMLRefKey:
+		if err := ValidateHTMLSnippet(p.Value); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -477,6 +821,7 @@ type Image struct {
 	Title          string    `json:"title"`
 	Attribution    string    `json:"attribution"`
 	AttributionURL string    `json:"attribution_url"`
+	Stock          bool      `json:"stock"`
 	Width          int       `json:"width"`
 	Height         int       `json:"height"`
 	CreatedBy      uuid.UUID `json:"-"`
@@ -499,6 +844,82 @@ func NewImage(siteID uuid.UUID, fileName, filePath string) *Image {
 	}
 }
 
+// RequireStockAttributionRefKey is the site param that makes attribution
+// mandatory for images marked as stock.
+const RequireStockAttributionRefKey = "ssg.images.stock.require_attribution"
+
+// Validate checks the image credit. The attribution URL must be an absolute
+// http(s) URL, and stock images need an attribution when requireStockAttribution is set.
+func (i *Image) Validate(requireStockAttribution bool) error {
+	if i.AttributionURL != "" && !isWebURL(i.AttributionURL) {
+		return fmt.Errorf("attribution URL %q must be an absolute http or https URL", i.AttributionURL)
+	}
+	if requireStockAttribution && i.Stock && strings.TrimSpace(i.Attribution) == "" {
+		return fmt.Errorf("stock image %q requires an attribution", i.FileName)
+	}
+	return nil
+}
+
+// Image usage values accepted by ImageFilter. Used images are attached to
+// content, a section or a layout; references from content bodies are not
+// counted, the orphaned images page checks those.
+const (
+	ImageUsageUsed   = "used"
+	ImageUsageUnused = "unused"
+)
+
+// ImageFilter narrows an image listing. Zero values match everything.
+type ImageFilter struct {
+	Search       string // Matched against the file name, title and alt text
+	Usage        string
+	CollectionID uuid.UUID
+}
+
+// IsSet reports whether any filter, search included, is applied.
+func (f ImageFilter) IsSet() bool {
+	return f.Search != "" || f.Usage != "" || f.CollectionID != uuid.Nil
+}
+
+// isWebURL reports whether raw is an absolute http or https URL.
+func isWebURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// OrphanPurge reports the outcome of purging orphaned images.
+type OrphanPurge struct {
+	Deleted        int   `json:"deleted"`
+	Skipped        int   `json:"skipped"` // No longer orphaned when purging
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
+}
+
+// ImageUsage lists the places an image appears. Deleting an image that is
+// still used leaves broken images behind.
+type ImageUsage struct {
+	Contents []ImageUse `json:"contents"` // Linked as header or content image
+	Sections []ImageUse `json:"sections"`
+	Layouts  []ImageUse `json:"layouts"` // Header image of the layout
+	Tags     []ImageUse `json:"tags"`    // Header image of the tag page
+	Bodies   []ImageUse `json:"bodies"`  // Content whose body references the image path
+}
+
+// ImageUse is a content, section, layout or tag that uses an image.
+type ImageUse struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Header bool      `json:"header"`
+}
+
+// Count returns the number of uses.
+func (u *ImageUsage) Count() int {
+	return len(u.Contents) + len(u.Sections) + len(u.Layouts) + len(u.Tags) + len(u.Bodies)
+}
+
+// InUse reports whether anything uses the image.
+func (u *ImageUsage) InUse() bool {
+	return u.Count() > 0
+}
+
 // ImageVariant represents a variant of an image (thumbnail, etc.).
 type ImageVariant struct {
 	ID            uuid.UUID `json:"id"`
@@ -619,7 +1040,34 @@ type Contributor struct {
 	UpdatedAt   time.Time    `json:"updated_at"`
 }
 
-const ContributorRoleEditor = "editor"
+// Contributor roles. The authors page lists them in this order, followed by
+// any other role alphabetically.
+const (
+	ContributorRoleEditor = "editor"
+	ContributorRoleAuthor = "author"
+	ContributorRoleGuest  = "guest"
+)
+
+// ContributorRoles are the roles offered when editing a contributor.
+var ContributorRoles = []string{ContributorRoleEditor, ContributorRoleAuthor, ContributorRoleGuest}
+
+var contributorRoleTitles = map[string]string{
+	ContributorRoleEditor: "Editors",
+	ContributorRoleAuthor: "Authors",
+	ContributorRoleGuest:  "Guest Authors",
+}
+
+// RoleTitle returns the heading for a group of contributors with the given
+// role, e.g. "Guest Authors".
+func RoleTitle(role string) string {
+	if title, ok := contributorRoleTitles[role]; ok {
+		return title
+	}
+	if role == "" {
+		return contributorRoleTitles[ContributorRoleAuthor]
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
 
 func NewContributor(siteID uuid.UUID, handle, name, surname string) *Contributor {
 	now := time.Now()
@@ -701,6 +1149,9 @@ type ImportFile struct {
 	Title       string            `json:"title"`
 	Body        string            `json:"body"`
 	Frontmatter map[string]string `json:"frontmatter,omitempty"`
+
+	// Parsed holds the typed frontmatter when it follows the backup format.
+	Parsed *ContentFrontmatter `json:"-"`
 }
 
 // --- Utility Functions ---
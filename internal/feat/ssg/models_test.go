@@ -343,6 +343,29 @@ func TestNewMeta(t *testing.T) {
 	}
 }
 
+func TestMetaValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"empty uses page url", "", false},
+		{"absolute https", "https://other.example/post", false},
+		{"absolute http", "http://other.example/post", false},
+		{"relative path", "/blog/post", true},
+		{"other scheme", "ftp://other.example/post", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Meta{CanonicalURL: tt.url}
+			if err := m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestNewSetting(t *testing.T) {
 	siteID := uuid.New()
 
@@ -442,6 +465,33 @@ func TestNewImage(t *testing.T) {
 	}
 }
 
+func TestImageValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   Image
+		require bool
+		wantErr bool
+	}{
+		{"no credit", Image{FileName: "a.jpg"}, false, false},
+		{"https url", Image{Attribution: "Jane", AttributionURL: "https://example.com/jane"}, false, false},
+		{"relative url", Image{AttributionURL: "/jane"}, false, true},
+		{"javascript url", Image{AttributionURL: "javascript:alert(1)"}, false, true},
+		{"stock without attribution allowed", Image{Stock: true}, false, false},
+		{"stock without attribution required", Image{Stock: true, Attribution: "  "}, true, true},
+		{"stock with attribution required", Image{Stock: true, Attribution: "Jane"}, true, false},
+		{"non stock without attribution required", Image{}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.image.Validate(tt.require)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestNewContributor(t *testing.T) {
 	siteID := uuid.New()
 
@@ -597,3 +647,56 @@ func TestNormalize(t *testing.T) {
 		})
 	}
 }
+
+func TestReservedPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"tags", true},
+		{"/tags/", true},
+		{"tags/go", true},
+		{"Authors", true},
+		{"page/2", true},
+		{"kinds/note/feed", true},
+		{"_redirects", true},
+		{"blog", false},
+		{"blog/tags", false},
+		{"tagsandmore", false},
+		{"search", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if _, got := reservedPath(map[string]string{}, tt.path); got != tt.want {
+				t.Errorf("reservedPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReservedPathFeatures(t *testing.T) {
+	tests := []struct {
+		path string
+		on   map[string]string
+		off  map[string]string
+	}{
+		{"archive", map[string]string{ArchiveRefKey: "true"}, map[string]string{ArchiveRefKey: "false"}},
+		{"search", map[string]string{"ssg.search.google.enabled": "true", "ssg.search.google.id": "abc"}, map[string]string{"ssg.search.google.enabled": "false", "ssg.search.google.id": "abc"}},
+		{"feed/atom.xml", map[string]string{BaseURLRefKey: "https://example.com"}, map[string]string{BaseURLRefKey: "https://example.com", FeedFormatsRefKey: ""}},
+		{"sitemap.xml", map[string]string{BaseURLRefKey: "https://example.com"}, map[string]string{}},
+		{"llms.txt", map[string]string{LLMsTxtRefKey: "true"}, map[string]string{}},
+		{"changelog.json", map[string]string{ChangelogSectionRefKey: "releases"}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if _, got := reservedPath(tt.on, tt.path); !got {
+				t.Errorf("reservedPath(%q) with the feature on = false, want true", tt.path)
+			}
+			if name, got := reservedPath(tt.off, tt.path); got {
+				t.Errorf("reservedPath(%q) with the feature off = %q, want not reserved", tt.path, name)
+			}
+		})
+	}
+}
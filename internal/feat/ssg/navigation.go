@@ -0,0 +1,112 @@
+package ssg
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Adjacent navigation scopes for the ssg.navigation.adjacent.scope param.
+const (
+	AdjacentScopeSection = "section"
+	AdjacentScopeSite    = "site"
+)
+
+// adjacentSectionID returns the section that bounds prev/next navigation for
+// content in sectionID, or uuid.Nil when the configured scope is the whole site.
+func adjacentSectionID(params map[string]string, sectionID uuid.UUID) uuid.UUID {
+	if params["ssg.navigation.adjacent.scope"] == AdjacentScopeSite {
+		return uuid.Nil
+	}
+	return sectionID
+}
+
+// findAdjacentContent returns the publishable items immediately newer and older
+// than the current one in reading order, limited to sectionID unless it is
+// uuid.Nil. Drafts, scheduled items and pages are not part of the chronology.
+// Items sharing the same PublishedAt are ordered by CreatedAt and then by ID so
+// the sequence is stable across generations.
+func findAdjacentContent(contents []*Content, currentID, sectionID uuid.UUID) (newer, older *Content) {
+	var timeline []*Content
+	for _, c := range contents {
+		if !isChronological(c) {
+			continue
+		}
+		if sectionID != uuid.Nil && c.SectionID != sectionID {
+			continue
+		}
+		timeline = append(timeline, c)
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return newerThan(timeline[i], timeline[j])
+	})
+
+	for i, c := range timeline {
+		if c.ID != currentID {
+			continue
+		}
+		if i > 0 {
+			newer = timeline[i-1]
+		}
+		if i < len(timeline)-1 {
+			older = timeline[i+1]
+		}
+		break
+	}
+
+	return newer, older
+}
+
+// isChronological reports whether content takes part in prev/next navigation.
+func isChronological(c *Content) bool {
+	return c.PublishedAt != nil && inListings(c)
+}
+
+// newerThan orders content newest first with deterministic tie-breaking.
+func newerThan(a, b *Content) bool {
+	if !a.PublishedAt.Equal(*b.PublishedAt) {
+		return a.PublishedAt.After(*b.PublishedAt)
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID.String() > b.ID.String()
+}
+
+// adjacentLinks holds the rendered neighbours of a content page.
+type adjacentLinks struct {
+	newer *RenderedContent
+	older *RenderedContent
+}
+
+// buildAdjacentIndex computes the newer and older neighbours of every
+// pre-rendered item in one pass, so templates get URLs alongside headings.
+func buildAdjacentIndex(allRendered []*RenderedContent, params map[string]string) map[uuid.UUID]adjacentLinks {
+	timelines := make(map[uuid.UUID][]*RenderedContent)
+	for _, r := range allRendered {
+		if !isChronological(r.Content) {
+			continue
+		}
+		key := adjacentSectionID(params, r.SectionID)
+		timelines[key] = append(timelines[key], r)
+	}
+
+	index := make(map[uuid.UUID]adjacentLinks, len(allRendered))
+	for _, timeline := range timelines {
+		sort.SliceStable(timeline, func(i, j int) bool {
+			return newerThan(timeline[i].Content, timeline[j].Content)
+		})
+		for i, r := range timeline {
+			var links adjacentLinks
+			if i > 0 {
+				links.newer = timeline[i-1]
+			}
+			if i < len(timeline)-1 {
+				links.older = timeline[i+1]
+			}
+			index[r.ID] = links
+		}
+	}
+	return index
+}
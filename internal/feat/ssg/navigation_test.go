@@ -0,0 +1,140 @@
+package ssg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func makeTimelineContent(heading string, sectionID uuid.UUID, publishedAt time.Time, createdAt time.Time) *Content {
+	return &Content{
+		ID:          uuid.New(),
+		SectionID:   sectionID,
+		Kind:        "post",
+		Heading:     heading,
+		PublishedAt: &publishedAt,
+		CreatedAt:   createdAt,
+	}
+}
+
+func headingOf(c *Content) string {
+	if c == nil {
+		return ""
+	}
+	return c.Heading
+}
+
+func TestFindAdjacentContent(t *testing.T) {
+	base := time.Now().Add(-72 * time.Hour)
+	sectionA := uuid.New()
+	sectionB := uuid.New()
+
+	oldest := makeTimelineContent("oldest", sectionA, base, base)
+	middle := makeTimelineContent("middle", sectionA, base.Add(time.Hour), base)
+	otherSection := makeTimelineContent("other", sectionB, base.Add(90*time.Minute), base)
+	newest := makeTimelineContent("newest", sectionA, base.Add(2*time.Hour), base)
+
+	draft := makeTimelineContent("draft", sectionA, base.Add(30*time.Minute), base)
+	draft.Draft = true
+	scheduled := makeTimelineContent("scheduled", sectionA, time.Now().Add(24*time.Hour), base)
+	page := makeTimelineContent("page", sectionA, base.Add(45*time.Minute), base)
+	page.Kind = "page"
+
+	contents := []*Content{newest, draft, oldest, scheduled, otherSection, page, middle}
+
+	tests := []struct {
+		name      string
+		current   *Content
+		sectionID uuid.UUID
+		wantNewer string
+		wantOlder string
+	}{
+		{"middle in section", middle, sectionA, "newest", "oldest"},
+		{"newest has no newer", newest, sectionA, "", "middle"},
+		{"oldest has no older", oldest, sectionA, "middle", ""},
+		{"site scope crosses sections", middle, uuid.Nil, "other", "oldest"},
+		{"site scope from other section", otherSection, uuid.Nil, "newest", "middle"},
+		{"draft is not navigable", draft, sectionA, "", ""},
+		{"page is not navigable", page, sectionA, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newer, older := findAdjacentContent(contents, tt.current.ID, tt.sectionID)
+			if got := headingOf(newer); got != tt.wantNewer {
+				t.Errorf("newer = %q, want %q", got, tt.wantNewer)
+			}
+			if got := headingOf(older); got != tt.wantOlder {
+				t.Errorf("older = %q, want %q", got, tt.wantOlder)
+			}
+		})
+	}
+}
+
+func TestFindAdjacentContentEqualTimestamps(t *testing.T) {
+	published := time.Now().Add(-time.Hour)
+	created := published.Add(-time.Hour)
+	sectionID := uuid.New()
+
+	first := makeTimelineContent("first", sectionID, published, created)
+	second := makeTimelineContent("second", sectionID, published, created.Add(time.Minute))
+	third := makeTimelineContent("third", sectionID, published, created.Add(2*time.Minute))
+
+	// Same PublishedAt: CreatedAt breaks the tie, newest first.
+	contents := []*Content{first, third, second}
+
+	newer, older := findAdjacentContent(contents, second.ID, sectionID)
+	if headingOf(newer) != "third" || headingOf(older) != "first" {
+		t.Errorf("got newer=%q older=%q, want third/first", headingOf(newer), headingOf(older))
+	}
+
+	// Fully identical timestamps still yield a stable order regardless of input order.
+	a := makeTimelineContent("a", sectionID, published, created)
+	b := makeTimelineContent("b", sectionID, published, created)
+	c := makeTimelineContent("c", sectionID, published, created)
+
+	wantNewer, wantOlder := findAdjacentContent([]*Content{a, b, c}, b.ID, sectionID)
+	for _, order := range [][]*Content{{c, b, a}, {b, a, c}, {a, c, b}} {
+		gotNewer, gotOlder := findAdjacentContent(order, b.ID, sectionID)
+		if gotNewer != wantNewer || gotOlder != wantOlder {
+			t.Errorf("order-dependent result: got %q/%q, want %q/%q",
+				headingOf(gotNewer), headingOf(gotOlder), headingOf(wantNewer), headingOf(wantOlder))
+		}
+	}
+
+	if wantNewer == nil && wantOlder == nil {
+		t.Errorf("expected at least one neighbour among three items")
+	}
+}
+
+func TestBuildAdjacentIndexUsesScopeParam(t *testing.T) {
+	base := time.Now().Add(-24 * time.Hour)
+	sectionA := uuid.New()
+	sectionB := uuid.New()
+
+	older := &RenderedContent{Content: makeTimelineContent("older", sectionA, base, base), URL: "/a/older/"}
+	between := &RenderedContent{Content: makeTimelineContent("between", sectionB, base.Add(time.Hour), base), URL: "/b/between/"}
+	current := &RenderedContent{Content: makeTimelineContent("current", sectionA, base.Add(2*time.Hour), base), URL: "/a/current/"}
+	all := []*RenderedContent{older, between, current}
+
+	index := buildAdjacentIndex(all, map[string]string{})
+	if got := index[current.ID].older; got != older {
+		t.Errorf("section scope: older = %v, want %q", got, "older")
+	}
+	if got := index[between.ID]; got.newer != nil || got.older != nil {
+		t.Errorf("section scope: lone item in section should have no neighbours, got %+v", got)
+	}
+
+	index = buildAdjacentIndex(all, map[string]string{"ssg.navigation.adjacent.scope": AdjacentScopeSite})
+	got := index[current.ID].older
+	if got != between {
+		t.Fatalf("site scope: older = %v, want %q", got, "between")
+	}
+	if got.URL != "/b/between/" {
+		t.Errorf("older URL = %q, want /b/between/", got.URL)
+	}
+	if index[older.ID].newer != between {
+		t.Errorf("site scope: newer of oldest should be %q", "between")
+	}
+}
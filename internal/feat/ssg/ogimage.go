@@ -0,0 +1,238 @@
+package ssg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/imaging"
+)
+
+// Setting ref keys for the social images of content without a header image.
+const (
+	// OGImageRefKey turns on the generated social images.
+	OGImageRefKey = "ssg.ogimage.enabled"
+	// OGImageBackgroundRefKey is a hex color, or an image of the site's
+	// library drawn under a tint of the default background.
+	OGImageBackgroundRefKey = "ssg.ogimage.background"
+	// OGImageTextColorRefKey is the hex color of the title.
+	OGImageTextColorRefKey = "ssg.ogimage.text_color"
+	// OGImageAccentColorRefKey is the hex color of the site name and the
+	// band along the bottom.
+	OGImageAccentColorRefKey = "ssg.ogimage.accent_color"
+	// OGImageTextSizeRefKey is the title height in pixels. Long titles
+	// are drawn smaller to fit.
+	OGImageTextSizeRefKey = "ssg.ogimage.text_size"
+	// OGImageDefaultRefKey is the social image of content without a header
+	// image when none is generated, or its generation fails: a path in
+	// the site or an absolute URL.
+	OGImageDefaultRefKey = "ssg.ogimage.default"
+)
+
+// ogImageDir is where generated images go in the output.
+const ogImageDir = "og"
+
+// ogImageVersion is part of every cache key; bump it when drawing changes
+// so cached images are drawn again.
+const ogImageVersion = "1"
+
+// ogImageStats counts the images written by generateOGImages.
+type ogImageStats struct {
+	Written int // images in the output
+	Drawn   int // of those, drawn in this build rather than taken from the cache
+}
+
+// generateOGImages draws a social image for every page without a header
+// image and writes it to the output, setting the page's OGImage. Images are
+// cached in the site's workspace under a hash of the title and the card
+// settings, so only new or renamed pages are drawn. Pages whose image
+// cannot be drawn keep OGImage empty and fall back to the default image.
+func (g *HTMLGenerator) generateOGImages(build *buildState, htmlPath string, site *Site, pages []*RenderedContent, params map[string]string) (ogImageStats, []string) {
+	var stats ogImageStats
+	if params[OGImageRefKey] != "true" {
+		return stats, nil
+	}
+
+	cachePath := g.workspace.GetOGImageCachePath(site.Slug)
+	outPath := filepath.Join(htmlPath, ogImageDir)
+	for _, dir := range []string{cachePath, outPath} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return stats, []string{fmt.Sprintf("social images: %v", err)}
+		}
+	}
+
+	card, settingsKey, warnings := g.ogImageCard(site, params)
+
+	var mu sync.Mutex
+	used := make(map[string]bool)
+	// Drawing is what takes memory; looking up and copying cached images
+	// runs on all the workers.
+	draws := make(chan struct{}, g.imageWorkerCount())
+	runParallel(len(pages), g.workerCount(), func(i int) {
+		page := pages[i]
+		if page.HeaderImageURL != "" || page.kindSettings().Redirect {
+			return
+		}
+		name := ogImageName(page.Heading, settingsKey)
+		cached := filepath.Join(cachePath, name)
+
+		_, err := os.Stat(cached)
+		drawn := false
+		if err != nil {
+			c := card
+			c.Title = page.Heading
+			draws <- struct{}{}
+			build.imageGauge().enter()
+			var data []byte
+			data, err = imaging.TitleCard(c)
+			build.imageGauge().leave()
+			<-draws
+			if err == nil {
+				err = os.WriteFile(cached, data, 0644)
+				drawn = true
+			}
+		}
+		dst := filepath.Join(outPath, name)
+		if err == nil {
+			err = copyIfMissing(cached, dst)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		used[name] = true
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("social image for %s: %v", page.Heading, err))
+			return
+		}
+		build.record(dst, "", time.Time{})
+		page.OGImage = ogImageDir + "/" + name
+		stats.Written++
+		if drawn {
+			stats.Drawn++
+		}
+	})
+
+	pruneOGImageCache(cachePath, used)
+	sort.Strings(warnings)
+	return stats, warnings
+}
+
+// ogImageCard builds the card settings shared by all pages and a key that
+// changes with them. Invalid colors are reported and left to the defaults.
+func (g *HTMLGenerator) ogImageCard(site *Site, params map[string]string) (imaging.Card, string, []string) {
+	card := imaging.Card{Footer: site.Name}
+	var warnings []string
+	key := []string{ogImageVersion, site.Name}
+
+	parseColor := func(refKey string) color.Color {
+		v := strings.TrimSpace(params[refKey])
+		key = append(key, v)
+		if v == "" {
+			return nil
+		}
+		c, err := imaging.ParseHexColor(v)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("social images: %s: %v", refKey, err))
+			return nil
+		}
+		return c
+	}
+
+	if bg := strings.TrimSpace(params[OGImageBackgroundRefKey]); bg != "" && !strings.HasPrefix(bg, "#") {
+		path := filepath.Join(g.workspace.GetImagesPath(site.Slug), filepath.Clean("/"+strings.TrimPrefix(bg, "/images/")))
+		info, err := os.Stat(path)
+		var data []byte
+		if err == nil {
+			data, err = os.ReadFile(path)
+		}
+		if err == nil {
+			card.BackgroundImage, err = imaging.Decode(data)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("social images: background %s: %v", bg, err))
+		} else {
+			key = append(key, bg, strconv.FormatInt(info.Size(), 10), info.ModTime().UTC().Format(time.RFC3339Nano))
+		}
+	} else {
+		card.Background = parseColor(OGImageBackgroundRefKey)
+	}
+	card.TextColor = parseColor(OGImageTextColorRefKey)
+	card.AccentColor = parseColor(OGImageAccentColorRefKey)
+
+	if v := strings.TrimSpace(params[OGImageTextSizeRefKey]); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			card.TextSize = n
+		}
+		key = append(key, v)
+	}
+	return card, strings.Join(key, "\x00"), warnings
+}
+
+// ogImageName is the file name of the image of a title drawn with the card
+// settings behind settingsKey.
+func ogImageName(title, settingsKey string) string {
+	sum := sha256.Sum256([]byte(settingsKey + "\x00" + title))
+	return hex.EncodeToString(sum[:8]) + ".png"
+}
+
+// copyIfMissing copies src to dst unless dst is already there. Names are
+// content hashes, so an existing file is the same image.
+func copyIfMissing(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+// pruneOGImageCache removes cached images no page used in this build.
+func pruneOGImageCache(cachePath string, used map[string]bool) {
+	entries, err := os.ReadDir(cachePath)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.IsDir() && !used[e.Name()] {
+			_ = os.Remove(filepath.Join(cachePath, e.Name()))
+		}
+	}
+}
+
+// socialImageURL returns the absolute address of a content page's social
+// image: its header image, its generated image, or the default image.
+func socialImageURL(content *RenderedContent, params map[string]string) string {
+	image := content.HeaderImageURL
+	if image == "" {
+		image = content.OGImage
+	}
+	return absoluteSocialImageURL(image, params)
+}
+
+// tagSocialImageURL returns the absolute address of a tag page's social
+// image: the tag's header image or the default image.
+func tagSocialImageURL(tag *Tag, params map[string]string) string {
+	return absoluteSocialImageURL(tag.HeaderImageURL, params)
+}
+
+// absoluteSocialImageURL falls back to the default image when image is empty
+// and prefixes site-relative paths with the base URL and path.
+func absoluteSocialImageURL(image string, params map[string]string) string {
+	if image == "" {
+		image = strings.TrimSpace(params[OGImageDefaultRefKey])
+	}
+	if image == "" || strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		return image
+	}
+	return siteBaseURL(params) + siteBasePath(params) + strings.TrimPrefix(image, "/")
+}
@@ -0,0 +1,143 @@
+package ssg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateOGImages(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "My Blog", Slug: "my-blog"}
+	htmlPath := g.workspace.GetHTMLPath(site.Slug)
+
+	page := func(heading, headerImage string) *RenderedContent {
+		return &RenderedContent{Content: &Content{ID: uuid.New(), Heading: heading, HeaderImageURL: headerImage}}
+	}
+	plain := page("A post without a header image", "")
+	withHeader := page("A post with a header image", "/images/blog/header.jpg")
+	unsupported := page("日本語", "")
+	pages := []*RenderedContent{plain, withHeader, unsupported}
+	params := map[string]string{OGImageRefKey: "true", OGImageAccentColorRefKey: "#c44536"}
+
+	stats, warnings := g.generateOGImages(nil, htmlPath, site, pages, params)
+	if stats.Written != 1 || stats.Drawn != 1 {
+		t.Errorf("stats = %+v, want one image written and drawn", stats)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "日本語") {
+		t.Errorf("warnings = %v, want one for the title the font cannot draw", warnings)
+	}
+	if withHeader.OGImage != "" || unsupported.OGImage != "" {
+		t.Errorf("OGImage set for pages that get none: %q, %q", withHeader.OGImage, unsupported.OGImage)
+	}
+	if !strings.HasPrefix(plain.OGImage, "og/") {
+		t.Fatalf("OGImage = %q, want a path under og/", plain.OGImage)
+	}
+	if _, err := os.Stat(filepath.Join(htmlPath, plain.OGImage)); err != nil {
+		t.Errorf("image not written to the output: %v", err)
+	}
+
+	t.Run("cached images are not drawn again", func(t *testing.T) {
+		first := plain.OGImage
+		if err := os.RemoveAll(htmlPath); err != nil {
+			t.Fatal(err)
+		}
+		stats, _ := g.generateOGImages(nil, htmlPath, site, pages, params)
+		if stats.Written != 1 || stats.Drawn != 0 {
+			t.Errorf("stats = %+v, want the image taken from the cache", stats)
+		}
+		if plain.OGImage != first {
+			t.Errorf("OGImage = %q, want %q", plain.OGImage, first)
+		}
+		if _, err := os.Stat(filepath.Join(htmlPath, first)); err != nil {
+			t.Errorf("cached image not copied to the output: %v", err)
+		}
+	})
+
+	t.Run("settings change the image", func(t *testing.T) {
+		first := plain.OGImage
+		params := map[string]string{OGImageRefKey: "true", OGImageAccentColorRefKey: "#2d6a4f"}
+		stats, _ := g.generateOGImages(nil, htmlPath, site, pages, params)
+		if stats.Drawn != 1 || plain.OGImage == first {
+			t.Errorf("stats = %+v, OGImage = %q, want a new image", stats, plain.OGImage)
+		}
+		entries, _ := os.ReadDir(g.workspace.GetOGImageCachePath(site.Slug))
+		if len(entries) != 1 {
+			t.Errorf("cache has %d images, want the unused one pruned", len(entries))
+		}
+	})
+
+	t.Run("invalid color", func(t *testing.T) {
+		params := map[string]string{OGImageRefKey: "true", OGImageTextColorRefKey: "white"}
+		_, warnings := g.generateOGImages(nil, htmlPath, site, []*RenderedContent{page("Post", "")}, params)
+		if len(warnings) != 1 || !strings.Contains(warnings[0], OGImageTextColorRefKey) {
+			t.Errorf("warnings = %v, want the invalid color reported", warnings)
+		}
+	})
+
+	t.Run("off", func(t *testing.T) {
+		p := page("Post", "")
+		stats, _ := g.generateOGImages(nil, htmlPath, site, []*RenderedContent{p}, nil)
+		if stats.Written != 0 || p.OGImage != "" {
+			t.Errorf("images generated with the setting off: %+v", stats)
+		}
+	})
+}
+
+func TestSocialImageURL(t *testing.T) {
+	params := map[string]string{BaseURLRefKey: "https://example.com", BasePathRefKey: "/blog/"}
+	tests := []struct {
+		name    string
+		content *RenderedContent
+		params  map[string]string
+		want    string
+	}{
+		{"header image", &RenderedContent{Content: &Content{HeaderImageURL: "/images/a.jpg"}, OGImage: "og/x.png"}, params, "https://example.com/blog/images/a.jpg"},
+		{"generated image", &RenderedContent{Content: &Content{}, OGImage: "og/x.png"}, params, "https://example.com/blog/og/x.png"},
+		{"default image", &RenderedContent{Content: &Content{}}, map[string]string{OGImageDefaultRefKey: "/images/og.png"}, "/images/og.png"},
+		{"absolute default image", &RenderedContent{Content: &Content{}}, map[string]string{OGImageDefaultRefKey: "https://cdn.example.com/og.png"}, "https://cdn.example.com/og.png"},
+		{"none", &RenderedContent{Content: &Content{}}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := socialImageURL(tt.content, tt.params); got != tt.want {
+				t.Errorf("socialImageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentPageOpenGraphTags(t *testing.T) {
+	tmpl := parseSiteTemplatesFromDisk(t)
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "My Blog", Slug: "my-blog"}
+	section := &Section{ID: uuid.New(), Name: "Blog", Path: "blog"}
+	published := time.Now().Add(-time.Hour)
+	content := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, SectionPath: "blog", ShortID: "abc123",
+		Heading: "Post", Summary: "About the post", Body: "Text", PublishedAt: &published}
+	params := map[string]string{BaseURLRefKey: "https://example.com"}
+	rendered := &RenderedContent{Content: content, URL: g.getContentURL(content, "/", params), OGImage: "og/x.png"}
+
+	htmlPath := g.workspace.GetHTMLPath(site.Slug)
+	if _, err := g.renderContentPage(tmpl, nil, nil, htmlPath, site, content, rendered, adjacentLinks{}, []*Section{section}, nil, params, nil, BlocksConfig{}); err != nil {
+		t.Fatalf("renderContentPage() error = %v", err)
+	}
+	data, err := os.ReadFile(g.workspace.GetPageHTMLPath(site.Slug, permalinkPattern(params).contentPath(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<meta property="og:title" content="Post">`,
+		`<meta property="og:description" content="About the post">`,
+		`<meta property="og:image" content="https://example.com/og/x.png">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("page has no %s", want)
+		}
+	}
+}
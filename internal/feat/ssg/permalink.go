@@ -0,0 +1,274 @@
+package ssg
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PermalinkRefKey is the setting holding the site's permalink pattern.
+const PermalinkRefKey = "ssg.permalink.pattern"
+
+// DefaultPermalinkPattern places content under its section, as sites have
+// always been generated. Root section content lives at /:slug/.
+const DefaultPermalinkPattern = "/:section/:slug/"
+
+var permalinkTokens = map[string]bool{
+	":section": true,
+	":slug":    true,
+	":year":    true,
+	":month":   true,
+	":day":     true,
+	":kind":    true,
+}
+
+var permalinkLiteral = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// PermalinkPattern is a parsed permalink pattern such as /:year/:month/:slug/.
+type PermalinkPattern struct {
+	segments []string
+	loc      *time.Location // Zone of date tokens, UTC when nil
+}
+
+// ParsePermalinkPattern validates a permalink pattern. Segments are separated
+// by "/" and are either lowercase literals or one of the tokens :section,
+// :slug, :year, :month, :day and :kind. The pattern must contain :slug, which
+// carries the short ID, so every content item gets its own path. An empty
+// pattern is the default one.
+func ParsePermalinkPattern(raw string) (*PermalinkPattern, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		raw = DefaultPermalinkPattern
+	}
+
+	p := &PermalinkPattern{}
+	hasSlug := false
+	for _, seg := range strings.Split(strings.Trim(raw, "/"), "/") {
+		switch {
+		case seg == "":
+			return nil, fmt.Errorf("permalink pattern %q has an empty segment", raw)
+		case strings.HasPrefix(seg, ":"):
+			if !permalinkTokens[seg] {
+				return nil, fmt.Errorf("permalink pattern %q has unknown token %q (use :section, :slug, :year, :month, :day or :kind)", raw, seg)
+			}
+			hasSlug = hasSlug || seg == ":slug"
+		case seg == "." || seg == ".." || !permalinkLiteral.MatchString(seg):
+			return nil, fmt.Errorf("permalink pattern %q has invalid segment %q", raw, seg)
+		}
+		p.segments = append(p.segments, seg)
+	}
+	if !hasSlug {
+		return nil, fmt.Errorf("permalink pattern %q must contain :slug", raw)
+	}
+	return p, nil
+}
+
+// String returns the pattern in its canonical form.
+func (p *PermalinkPattern) String() string {
+	return "/" + strings.Join(p.segments, "/") + "/"
+}
+
+// Path returns the content's path relative to the site root, without leading
+// or trailing slashes. Empty values, such as the section of root content or a
+// missing kind, are dropped. Dates come from PublishedAt, or CreatedAt while
+// the content has not been published, in the site timezone.
+func (p *PermalinkPattern) Path(content *Content) string {
+	date := content.CreatedAt
+	if content.PublishedAt != nil {
+		date = *content.PublishedAt
+	}
+	loc := p.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	date = date.In(loc)
+
+	parts := make([]string, 0, len(p.segments))
+	for _, seg := range p.segments {
+		var value string
+		switch seg {
+		case ":section":
+			value = strings.Trim(content.SectionPath, "/")
+		case ":slug":
+			value = content.Slug()
+		case ":year":
+			value = date.Format("2006")
+		case ":month":
+			value = date.Format("01")
+		case ":day":
+			value = date.Format("02")
+		case ":kind":
+			value = content.Kind
+		default:
+			value = seg
+		}
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// contentPath returns the content's path by the permalink pattern of its
+// kind, with dates in the same zone as p, or by p when the kind has none.
+func (p *PermalinkPattern) contentPath(content *Content) string {
+	raw := content.kindSettings().Permalink
+	if raw == "" {
+		return p.Path(content)
+	}
+	kp, err := ParsePermalinkPattern(raw)
+	if err != nil {
+		return p.Path(content)
+	}
+	kp.loc = p.loc
+	return kp.Path(content)
+}
+
+// permalinkPattern returns the site's pattern, with dates in the site
+// timezone. Invalid values fall back to the default; settings validation
+// rejects them on save.
+func permalinkPattern(params map[string]string) *PermalinkPattern {
+	p, err := ParsePermalinkPattern(params[PermalinkRefKey])
+	if err != nil {
+		p, _ = ParsePermalinkPattern(DefaultPermalinkPattern)
+	}
+	p.loc = siteLocation(params)
+	return p
+}
+
+// PathConflict is an output path that more than one content item resolves to.
+type PathConflict struct {
+	Path     string     // Relative to the site root, e.g. 2024/hello-abc123
+	Contents []*Content // In the order they were given
+}
+
+// PathConflictError is returned by GenerateHTML when content items resolve to
+// the same output path, which permalink patterns without the section allow
+// across sections. Nothing is written, so no page silently replaces another.
+type PathConflictError struct {
+	Conflicts []PathConflict // Sorted by path
+}
+
+func (e *PathConflictError) Error() string {
+	var b strings.Builder
+	if len(e.Conflicts) == 1 {
+		b.WriteString("content resolves to the same path:")
+	} else {
+		fmt.Fprintf(&b, "content resolves to the same path in %d places:", len(e.Conflicts))
+	}
+	for i, c := range e.Conflicts {
+		if i > 0 {
+			b.WriteString(";")
+		}
+		names := make([]string, len(c.Contents))
+		for j, content := range c.Contents {
+			names[j] = fmt.Sprintf("%q (%s)", content.Heading, content.ShortID)
+		}
+		fmt.Fprintf(&b, " /%s/ by %s", c.Path, strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+// assignPermalinks computes the path of each page by the pattern of its kind
+// or the site's. Pages sharing a path are reported with a *PathConflictError,
+// and the caller must not write any of them.
+func assignPermalinks(pages []*Content, params map[string]string) (map[uuid.UUID]string, error) {
+	pattern := permalinkPattern(params)
+	paths := make(map[uuid.UUID]string, len(pages))
+	owners := make(map[string][]*Content, len(pages))
+
+	for _, c := range pages {
+		path := pattern.contentPath(c)
+		owners[path] = append(owners[path], c)
+		paths[c.ID] = path
+	}
+
+	var conflicts []PathConflict
+	for path, contents := range owners {
+		if len(contents) > 1 {
+			conflicts = append(conflicts, PathConflict{Path: path, Contents: contents})
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+		return paths, &PathConflictError{Conflicts: conflicts}
+	}
+	return paths, nil
+}
+
+// writeRedirects leaves a redirect page at every path content was previously
+// published at, so links keep working after a permalink pattern change, a
+// section move or a slug rename. Redirects accumulate across builds and are
+// dropped once their content is gone or a page is generated at the same path.
+func (g *HTMLGenerator) writeRedirects(build *buildState, htmlPath string, permalinks map[uuid.UUID]string, params map[string]string) (int, error) {
+	basePath := siteBasePath(params)
+	redirects := build.redirects(permalinks)
+
+	written := 0
+	for oldPath, id := range redirects {
+		outputPath := pageFile(params, htmlPath, filepath.Join(htmlPath, filepath.FromSlash(oldPath), "index.html"))
+		if build.generated(outputPath) {
+			delete(redirects, oldPath)
+			continue
+		}
+
+		target := pageURL(params, basePath+permalinks[id]+"/")
+		if build.unchanged(outputPath, hashInputs(target), time.Time{}) {
+			continue
+		}
+		if err := EnsureDir(outputPath); err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(outputPath, []byte(redirectPage(target)), 0644); err != nil {
+			return written, err
+		}
+		build.record(outputPath, hashInputs(target), time.Time{})
+		written++
+	}
+
+	build.setPermalinks(permalinks, redirects)
+	return written, nil
+}
+
+// writeRedirectPage writes the page of content whose kind sends visitors on
+// to the URL it links to, reporting whether it was written.
+func (g *HTMLGenerator) writeRedirectPage(build *buildState, outputPath, target string, updatedAt time.Time) (bool, error) {
+	hash := hashInputs(target)
+	if build.unchanged(outputPath, hash, updatedAt) {
+		return false, nil
+	}
+	if err := EnsureDir(outputPath); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(outputPath, []byte(redirectPage(target)), 0644); err != nil {
+		return false, err
+	}
+	build.record(outputPath, hash, updatedAt)
+	return true, nil
+}
+
+// redirectPage is a minimal page sending visitors and crawlers to target.
+func redirectPage(target string) string {
+	u := html.EscapeString(target)
+	return `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Redirecting…</title>
+<link rel="canonical" href="` + u + `">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url=` + u + `">
+</head>
+<body>
+<p>This page has moved to <a href="` + u + `">` + u + `</a>.</p>
+</body>
+</html>
+`
+}
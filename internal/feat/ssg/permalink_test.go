@@ -0,0 +1,199 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParsePermalinkPattern(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"", DefaultPermalinkPattern, false},
+		{"/:section/:slug/", "/:section/:slug/", false},
+		{":year/:month/:slug", "/:year/:month/:slug/", false},
+		{"/blog/:kind/:slug", "/blog/:kind/:slug/", false},
+		{" /:slug ", "/:slug/", false},
+		{"/:year/:month/", "", true},
+		{"/:section//:slug/", "", true},
+		{"/:title/:slug/", "", true},
+		{"/../:slug/", "", true},
+		{"/Blog/:slug/", "", true},
+		{"/a b/:slug/", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p, err := ParsePermalinkPattern(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParsePermalinkPattern(%q) = %q, want error", tt.raw, p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePermalinkPattern(%q) error = %v", tt.raw, err)
+			}
+			if p.String() != tt.want {
+				t.Errorf("ParsePermalinkPattern(%q) = %q, want %q", tt.raw, p, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermalinkPatternPath(t *testing.T) {
+	published := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	created := time.Date(2023, 11, 20, 10, 0, 0, 0, time.UTC)
+	post := &Content{ShortID: "abc123", Heading: "Hello World", Kind: "article", SectionPath: "blog", PublishedAt: &published, CreatedAt: created}
+	root := &Content{ShortID: "def456", Heading: "About", SectionPath: "/", CreatedAt: created}
+
+	tests := []struct {
+		pattern string
+		content *Content
+		want    string
+	}{
+		{"", post, "blog/hello-world-abc123"},
+		{"", root, "about-def456"},
+		{"/:year/:month/:day/:slug/", post, "2024/03/07/hello-world-abc123"},
+		{"/:year/:slug/", root, "2023/about-def456"},
+		{"/:slug/", post, "hello-world-abc123"},
+		{"/:kind/:slug/", root, "about-def456"},
+		{"/posts/:section/:kind/:slug/", post, "posts/blog/article/hello-world-abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := ParsePermalinkPattern(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.Path(tt.content); got != tt.want {
+				t.Errorf("Path() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetContentURLDefaultPattern(t *testing.T) {
+	g := &HTMLGenerator{}
+	post := &Content{ShortID: "abc123", Heading: "Hello", SectionPath: "blog"}
+	if got := g.getContentURL(post, "/sub/", nil); got != "/sub/blog/hello-abc123/" {
+		t.Errorf("getContentURL() = %q", got)
+	}
+	if got := g.getContentURL(post, "/", map[string]string{PermalinkRefKey: "not valid"}); got != "/blog/hello-abc123/" {
+		t.Errorf("invalid pattern should fall back to the default, got %q", got)
+	}
+}
+
+func TestAssignPermalinksCollisions(t *testing.T) {
+	day := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	a := &Content{ID: uuid.New(), ShortID: "abc123", Heading: "Same", SectionPath: "blog", PublishedAt: &day}
+	b := &Content{ID: uuid.New(), ShortID: "abc123", Heading: "Same", SectionPath: "news", PublishedAt: &day}
+
+	paths, err := assignPermalinks([]*Content{a, b}, map[string]string{})
+	if err != nil || len(paths) != 2 {
+		t.Fatalf("default pattern: paths %v, error %v", paths, err)
+	}
+
+	_, err = assignPermalinks([]*Content{a, b}, map[string]string{PermalinkRefKey: "/:year/:slug/"})
+	var conflicts *PathConflictError
+	if !errors.As(err, &conflicts) {
+		t.Fatalf("colliding pattern: error = %v, want a PathConflictError", err)
+	}
+	if len(conflicts.Conflicts) != 1 || conflicts.Conflicts[0].Path != "2024/same-abc123" {
+		t.Fatalf("conflicts = %+v", conflicts.Conflicts)
+	}
+	if got := conflicts.Conflicts[0].Contents; len(got) != 2 || got[0] != a || got[1] != b {
+		t.Errorf("conflicting contents = %v, want both posts", got)
+	}
+}
+
+func TestGenerateHTMLPathConflict(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	day := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	blog := &Section{ID: uuid.New(), SiteID: site.ID, Name: "Blog", Path: "blog"}
+	news := &Section{ID: uuid.New(), SiteID: site.ID, Name: "News", Path: "news"}
+	post := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: blog.ID, SectionPath: "blog", ShortID: "abc12345", Heading: "Launch", PublishedAt: &day}
+	story := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: news.ID, SectionPath: "news", ShortID: "abc12345", Heading: "Launch", PublishedAt: &day}
+	params := []*Setting{{RefKey: PermalinkRefKey, Value: "/:year/:month/:slug/"}}
+
+	result, err := g.GenerateHTML(context.Background(), site, []*Content{post, story}, []*Section{blog, news}, nil, nil, params, nil, nil, true)
+	var conflicts *PathConflictError
+	if !errors.As(err, &conflicts) {
+		t.Fatalf("GenerateHTML() = %v, %v, want a PathConflictError", result, err)
+	}
+	if len(conflicts.Conflicts) != 1 || conflicts.Conflicts[0].Path != "2024/03/launch-abc12345" {
+		t.Fatalf("conflicts = %+v", conflicts.Conflicts)
+	}
+	if got := conflicts.Conflicts[0].Contents; len(got) != 2 || got[0].ID != post.ID || got[1].ID != story.ID {
+		t.Errorf("conflicting contents = %v, want the blog post and the news story", got)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "/2024/03/launch-abc12345/") || strings.Count(msg, `"Launch" (abc12345)`) != 2 {
+		t.Errorf("error = %q, want the path and both items", msg)
+	}
+	if _, err := os.Stat(filepath.Join(g.workspace.GetHTMLPath(site.Slug), "2024")); !os.IsNotExist(err) {
+		t.Errorf("pages were written despite the conflict: %v", err)
+	}
+}
+
+func TestWriteRedirects(t *testing.T) {
+	g := &HTMLGenerator{}
+	htmlPath := t.TempDir()
+	moved := uuid.New()
+	deleted := uuid.New()
+	previous := &BuildManifest{
+		Version:    buildManifestVersion,
+		GlobalHash: "old",
+		Permalinks: map[string]string{moved.String(): "blog/post-abc123", deleted.String(): "blog/gone-def456"},
+		Redirects:  map[string]string{"older/post-abc123": moved.String()},
+	}
+
+	// A pattern change alters the global hash, so this is a full rebuild.
+	build := newBuildState(htmlPath, previous, "new", false)
+	permalinks := map[uuid.UUID]string{moved: "2024/03/post-abc123"}
+	written, err := g.writeRedirects(build, htmlPath, permalinks, nil)
+	if err != nil {
+		t.Fatalf("writeRedirects() error = %v", err)
+	}
+	if written != 2 {
+		t.Errorf("written = %d, want 2", written)
+	}
+
+	for _, old := range []string{"blog/post-abc123", "older/post-abc123"} {
+		data, err := os.ReadFile(filepath.Join(htmlPath, old, "index.html"))
+		if err != nil {
+			t.Fatalf("expected redirect at %s: %v", old, err)
+		}
+		if !strings.Contains(string(data), `url=/2024/03/post-abc123/`) {
+			t.Errorf("redirect at %s does not point to the new path:\n%s", old, data)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(htmlPath, "blog", "gone-def456", "index.html")); !os.IsNotExist(err) {
+		t.Errorf("expected no redirect for deleted content, got %v", err)
+	}
+
+	m := build.manifest("new")
+	if m.Permalinks[moved.String()] != "2024/03/post-abc123" || len(m.Redirects) != 2 {
+		t.Errorf("manifest permalinks = %v, redirects = %v", m.Permalinks, m.Redirects)
+	}
+
+	// Moving back to the old path drops its redirect.
+	build = newBuildState(htmlPath, m, "newer", false)
+	if _, err := g.writeRedirects(build, htmlPath, map[uuid.UUID]string{moved: "blog/post-abc123"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	m = build.manifest("newer")
+	if _, ok := m.Redirects["blog/post-abc123"]; ok || len(m.Redirects) != 2 {
+		t.Errorf("redirects = %v, want older path and 2024 path only", m.Redirects)
+	}
+}
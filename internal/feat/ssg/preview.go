@@ -1,29 +1,49 @@
 package ssg
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"html"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/cliossg/clio/pkg/cl/config"
 	"github.com/cliossg/clio/pkg/cl/logger"
 )
 
+// previewAuthTTL is how long verified credentials are trusted before the
+// password is checked again. Browsers resend basic auth with every asset.
+const previewAuthTTL = 5 * time.Minute
+
+// PreviewAuthFunc verifies the credentials of a preview visitor.
+type PreviewAuthFunc func(ctx context.Context, email, password string) error
+
 type PreviewServer struct {
 	service      Service
 	workspace    *Workspace
 	server       *http.Server
 	formsHandler http.Handler
+	authFunc     PreviewAuthFunc
 	cfg          *config.Config
 	log          logger.Logger
+
+	authMu   sync.Mutex
+	verified map[[sha256.Size]byte]time.Time
 }
 
 func NewPreviewServer(service Service, cfg *config.Config, log logger.Logger) *PreviewServer {
+	workspace := NewWorkspace(cfg.SSG.SitesBasePath)
+	workspace.SetOutputDir(cfg.SSG.OutputDir)
 	return &PreviewServer{
 		service:   service,
-		workspace: NewWorkspace(cfg.SSG.SitesBasePath),
+		workspace: workspace,
 		cfg:       cfg,
 		log:       log,
 	}
@@ -34,6 +54,11 @@ func (s *PreviewServer) SetFormsHandler(h http.Handler) {
 	s.formsHandler = h
 }
 
+// SetAuthFunc sets how credentials are verified when SSG.Preview.RequireAuth is on.
+func (s *PreviewServer) SetAuthFunc(fn PreviewAuthFunc) {
+	s.authFunc = fn
+}
+
 func (s *PreviewServer) Start(ctx context.Context) error {
 	s.server = &http.Server{
 		Addr:    s.cfg.SSG.PreviewAddr,
@@ -65,6 +90,12 @@ func (s *PreviewServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.cfg.SSG.Preview.RequireAuth && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Clio preview", charset="UTF-8"`)
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
 	siteSlug := s.extractSiteSlug(r.Host)
 	if siteSlug == "" {
 		http.Error(w, "Invalid host. Use <site>.localhost:3000", http.StatusBadRequest)
@@ -99,6 +130,70 @@ func (s *PreviewServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.serveHTML(w, r, siteSlug, requestPath)
 }
 
+// authorized checks the request's basic auth credentials. Successful checks
+// are remembered for previewAuthTTL so assets don't each pay for a password hash.
+func (s *PreviewServer) authorized(r *http.Request) bool {
+	email, password, ok := r.BasicAuth()
+	if !ok || s.authFunc == nil {
+		return false
+	}
+
+	key := sha256.Sum256([]byte(email + "\x00" + password))
+	now := time.Now()
+
+	s.authMu.Lock()
+	expires, found := s.verified[key]
+	s.authMu.Unlock()
+	if found && now.Before(expires) {
+		return true
+	}
+
+	if err := s.authFunc(r.Context(), email, password); err != nil {
+		s.log.Infof("Preview authentication failed for %s", email)
+		return false
+	}
+
+	s.authMu.Lock()
+	if s.verified == nil {
+		s.verified = make(map[[sha256.Size]byte]time.Time)
+	}
+	for k, exp := range s.verified {
+		if now.After(exp) {
+			delete(s.verified, k)
+		}
+	}
+	s.verified[key] = now.Add(previewAuthTTL)
+	s.authMu.Unlock()
+	return true
+}
+
+// serveDraft renders unpublished content for authenticated previews.
+// It reports whether a draft was found for the path.
+func (s *PreviewServer) serveDraft(w http.ResponseWriter, r *http.Request, siteSlug string) bool {
+	if !s.cfg.SSG.Preview.RequireAuth {
+		return false
+	}
+
+	site, err := s.service.GetSiteBySlug(r.Context(), siteSlug)
+	if err != nil {
+		return false
+	}
+
+	out, err := s.service.RenderDraftPreview(r.Context(), site, r.URL.Path)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			s.log.Errorf("Cannot render draft preview for %s%s: %v", siteSlug, r.URL.Path, err)
+		}
+		return false
+	}
+
+	// Drafts must never be cached by proxies or the browser.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(out)
+	return true
+}
+
 func (s *PreviewServer) extractSiteSlug(host string) string {
 	host = strings.Split(host, ":")[0]
 
@@ -124,14 +219,25 @@ func (s *PreviewServer) serveHTML(w http.ResponseWriter, r *http.Request, siteSl
 		return
 	}
 
+	// Sites without trailing slashes have /blog/post at blog/post.html, which
+	// wins over a blog/post directory.
+	if !strings.HasSuffix(requestPath, "/") {
+		if pageInfo, err := os.Stat(cleanPath + ".html"); err == nil && !pageInfo.IsDir() {
+			serveCachedFile(w, r, cleanPath+".html", pageInfo, s.cacheControl(cleanPath+".html", 0))
+			return
+		}
+	}
+
 	info, err := os.Stat(cleanPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			indexPath := filepath.Join(cleanPath, "index.html")
-			if _, err := os.Stat(indexPath); err == nil {
-				cleanPath = indexPath
+			if indexInfo, err := os.Stat(indexPath); err == nil {
+				cleanPath, info = indexPath, indexInfo
 			} else {
-				http.NotFound(w, r)
+				if !s.serveDraft(w, r, siteSlug) {
+					http.NotFound(w, r)
+				}
 				return
 			}
 		} else {
@@ -140,13 +246,16 @@ func (s *PreviewServer) serveHTML(w http.ResponseWriter, r *http.Request, siteSl
 		}
 	} else if info.IsDir() {
 		cleanPath = filepath.Join(cleanPath, "index.html")
-		if _, err := os.Stat(cleanPath); err != nil {
-			http.NotFound(w, r)
+		if info, err = os.Stat(cleanPath); err != nil {
+			if !s.serveDraft(w, r, siteSlug) {
+				http.NotFound(w, r)
+			}
 			return
 		}
 	}
 
-	http.ServeFile(w, r, cleanPath)
+	// Pages are regenerated on every request, so they are always revalidated.
+	serveCachedFile(w, r, cleanPath, info, s.cacheControl(cleanPath, 0))
 }
 
 func (s *PreviewServer) serveStatic(w http.ResponseWriter, r *http.Request, siteSlug, requestPath string) {
@@ -159,12 +268,13 @@ func (s *PreviewServer) serveStatic(w http.ResponseWriter, r *http.Request, site
 		return
 	}
 
-	if _, err := os.Stat(cleanPath); err != nil {
+	info, err := os.Stat(cleanPath)
+	if err != nil || info.IsDir() {
 		http.NotFound(w, r)
 		return
 	}
 
-	http.ServeFile(w, r, cleanPath)
+	serveCachedFile(w, r, cleanPath, info, s.cacheControl(cleanPath, s.cacheMaxAge()))
 }
 
 func (s *PreviewServer) serveImage(w http.ResponseWriter, r *http.Request, siteSlug, imagePath string) {
@@ -177,12 +287,27 @@ func (s *PreviewServer) serveImage(w http.ResponseWriter, r *http.Request, siteS
 		return
 	}
 
-	if _, err := os.Stat(cleanPath); err != nil {
+	info, err := os.Stat(cleanPath)
+	if err != nil || info.IsDir() {
 		http.NotFound(w, r)
 		return
 	}
 
-	http.ServeFile(w, r, cleanPath)
+	serveCachedFile(w, r, cleanPath, info, s.cacheControl(cleanPath, s.cacheMaxAge()))
+}
+
+// cacheMaxAge returns ssg.preview.cache_max_age, the default when invalid;
+// startup rejects invalid values.
+func (s *PreviewServer) cacheMaxAge() time.Duration {
+	d, err := ParseCacheMaxAge(s.cfg.SSG.Preview)
+	if err != nil {
+		return DefaultCacheMaxAge
+	}
+	return d
+}
+
+func (s *PreviewServer) cacheControl(name string, maxAge time.Duration) string {
+	return cacheControl(name, maxAge, s.cfg.SSG.Preview.RequireAuth)
 }
 
 func (s *PreviewServer) getBasePath(ctx context.Context, siteSlug string) string {
@@ -191,10 +316,72 @@ func (s *PreviewServer) getBasePath(ctx context.Context, siteSlug string) string
 		return "/"
 	}
 
-	param, err := s.service.GetSettingByRefKey(ctx, site.ID, "ssg.site.base_path")
+	param, err := s.service.GetSettingByRefKey(ctx, site.ID, BasePathRefKey)
 	if err != nil || param == nil || param.Value == "" {
 		return "/"
 	}
 
 	return param.Value
 }
+
+// previewBannerHTML marks previews of unpublished content. It is fixed to the
+// top of the viewport so the page underneath keeps its published layout.
+// Closing it only removes it from the current page: it is back on reload.
+const previewBannerHTML = `<div id="clio-preview-banner" role="status" style="position:fixed;top:0;left:0;right:0;z-index:2147483647;margin:0;padding:8px 48px 8px 16px;background:%s;color:#fff;font:600 14px/1.4 system-ui,sans-serif;text-align:center;box-shadow:0 2px 6px rgba(0,0,0,.3)">%s<button type="button" aria-label="Dismiss" onclick="this.parentNode.remove()" style="position:absolute;top:50%%;right:12px;transform:translateY(-50%%);margin:0;padding:0 4px;background:none;border:0;color:inherit;font:inherit;font-size:20px;cursor:pointer">&times;</button></div>`
+
+// previewBanner returns the banner for content previewed before publication,
+// with scheduled dates shown in the site timezone. Published content gets none.
+func previewBanner(c *Content, tz string, now time.Time) string {
+	switch c.Status(now) {
+	case ContentStatusDraft:
+		return fmt.Sprintf(previewBannerHTML, "#b91c1c", "DRAFT &mdash; not published")
+	case ContentStatusScheduled:
+		when := html.EscapeString(formatInTZ(c.PublishedAt, tz, "Jan 02, 2006 15:04 MST"))
+		return fmt.Sprintf(previewBannerHTML, "#1d4ed8", "SCHEDULED for "+when)
+	}
+	if c.Visibility == VisibilityPrivate {
+		return fmt.Sprintf(previewBannerHTML, "#4b5563", "PRIVATE &mdash; not published")
+	}
+	return ""
+}
+
+// injectPreviewBanner inserts banner right after the opening body tag, or at
+// the start of page when it has none.
+func injectPreviewBanner(page []byte, banner string) []byte {
+	if banner == "" {
+		return page
+	}
+	at := 0
+	if i := bytes.Index(bytes.ToLower(page), []byte("<body")); i >= 0 {
+		if end := bytes.IndexByte(page[i:], '>'); end >= 0 {
+			at = i + end + 1
+		}
+	}
+	out := make([]byte, 0, len(page)+len(banner))
+	out = append(out, page[:at]...)
+	out = append(out, banner...)
+	return append(out, page[at:]...)
+}
+
+// injectBaseHref inserts a <base> element pointing at href right after the
+// opening head tag, so relative URLs resolve against the site wherever the
+// page is shown. Pages that set their own base are left as they are.
+func injectBaseHref(page []byte, href string) []byte {
+	lower := bytes.ToLower(page)
+	if bytes.Contains(lower, []byte("<base ")) {
+		return page
+	}
+	at := 0
+	if i := bytes.Index(lower, []byte("<head>")); i >= 0 {
+		at = i + len("<head>")
+	} else if i := bytes.Index(lower, []byte("<head ")); i >= 0 {
+		if end := bytes.IndexByte(page[i:], '>'); end >= 0 {
+			at = i + end + 1
+		}
+	}
+	base := `<base href="` + html.EscapeString(href) + `">`
+	out := make([]byte, 0, len(page)+len(base))
+	out = append(out, page[:at]...)
+	out = append(out, base...)
+	return append(out, page[at:]...)
+}
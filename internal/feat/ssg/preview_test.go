@@ -0,0 +1,307 @@
+package ssg
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cliossg/clio/internal/testutil"
+	"github.com/cliossg/clio/pkg/cl/config"
+)
+
+func TestPreviewServerRequireAuth(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	cfg.SSG.Preview.RequireAuth = true
+	workspace := NewWorkspace(cfg.SSG.SitesBasePath)
+	svc := NewService(&testutil.TestDBProvider{DB: db}, NewHTMLGenerator(workspace, embed.FS{}), cfg, newTestLogger())
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Preview", "preview")
+	layout := NewLayout(site.ID, "Plain", "")
+	layout.Code = `<h1>{{ .Content.Heading }}</h1>`
+	svc.CreateLayout(ctx, layout)
+	site.DefaultLayoutID = layout.ID
+	svc.UpdateSite(ctx, site)
+
+	section := NewSection(site.ID, "Blog", "", "blog")
+	svc.CreateSection(ctx, section)
+	draft := NewContent(site.ID, section.ID, "Secret Plans", "Not yet")
+	draft.Kind = "post"
+	draft.Draft = true
+	svc.CreateContent(ctx, draft)
+
+	image := filepath.Join(workspace.GetImagesPath(site.Slug), "header.png")
+	if err := EnsureDir(image); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(image, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewPreviewServer(svc, cfg, newTestLogger())
+	server.SetAuthFunc(func(_ context.Context, email, password string) error {
+		if email == "editor@example.com" && password == "secret" {
+			return nil
+		}
+		return errors.New("invalid credentials")
+	})
+
+	get := func(path, email, password string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://preview.localhost:3000"+path, nil)
+		if email != "" {
+			req.SetBasicAuth(email, password)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+	draftPath := "/blog/" + draft.Slug() + "/"
+
+	t.Run("anonymous requests are challenged", func(t *testing.T) {
+		rec := get(draftPath, "", "")
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("status = %d, want 401 with a challenge", rec.Code)
+		}
+	})
+
+	t.Run("wrong password is rejected", func(t *testing.T) {
+		if rec := get("/images/header.png", "editor@example.com", "wrong"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want 401", rec.Code)
+		}
+	})
+
+	t.Run("static assets load when authenticated", func(t *testing.T) {
+		rec := get("/images/header.png", "editor@example.com", "secret")
+		if rec.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200", rec.Code)
+		}
+	})
+
+	t.Run("drafts render when authenticated", func(t *testing.T) {
+		rec := get(draftPath, "editor@example.com", "secret")
+		body, _ := io.ReadAll(rec.Body)
+		if rec.Code != http.StatusOK || !strings.Contains(string(body), "<h1>Secret Plans</h1>") {
+			t.Errorf("status = %d, body = %q, want the rendered draft", rec.Code, body)
+		}
+		if !strings.Contains(string(body), "DRAFT &mdash; not published") {
+			t.Errorf("body = %q, want the draft banner", body)
+		}
+		if rec.Header().Get("Cache-Control") != "no-store" {
+			t.Errorf("Cache-Control = %q, want no-store", rec.Header().Get("Cache-Control"))
+		}
+		if _, err := os.Stat(filepath.Join(workspace.GetHTMLPath(site.Slug), "blog", draft.Slug())); !os.IsNotExist(err) {
+			t.Errorf("draft must not be written to the output, got %v", err)
+		}
+	})
+
+	t.Run("drafts are not served without the auth gate", func(t *testing.T) {
+		cfg.SSG.Preview.RequireAuth = false
+		defer func() { cfg.SSG.Preview.RequireAuth = true }()
+		if rec := get(draftPath, "", ""); rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want 404", rec.Code)
+		}
+	})
+}
+
+func TestPreviewBanner(t *testing.T) {
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	later := now.Add(26 * time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	if got := previewBanner(&Content{Draft: true, PublishedAt: &later}, "UTC", now); !strings.Contains(got, "DRAFT &mdash; not published") {
+		t.Errorf("draft banner = %q", got)
+	}
+	if got := previewBanner(&Content{PublishedAt: &later}, "America/New_York", now); !strings.Contains(got, "SCHEDULED for Mar 10, 2024 10:00 EDT") {
+		t.Errorf("scheduled banner = %q, want the date in the site timezone", got)
+	}
+	if got := previewBanner(&Content{PublishedAt: &earlier}, "UTC", now); got != "" {
+		t.Errorf("published content banner = %q, want none", got)
+	}
+
+	page := []byte(`<html><BODY class="post"><h1>Hi</h1></BODY></html>`)
+	if got := string(injectPreviewBanner(page, "<b>x</b>")); got != `<html><BODY class="post"><b>x</b><h1>Hi</h1></BODY></html>` {
+		t.Errorf("injectPreviewBanner() = %q", got)
+	}
+	if got := string(injectPreviewBanner([]byte("<h1>Hi</h1>"), "<b>x</b>")); got != "<b>x</b><h1>Hi</h1>" {
+		t.Errorf("injectPreviewBanner() without body = %q", got)
+	}
+	if got := string(injectPreviewBanner(page, "")); got != string(page) {
+		t.Errorf("injectPreviewBanner() without banner = %q", got)
+	}
+}
+
+func TestRenderContentPreview(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	cfg.SSG.PreviewAddr = ":4000"
+	svc := NewService(&testutil.TestDBProvider{DB: db}, NewHTMLGenerator(NewWorkspace(cfg.SSG.SitesBasePath), embed.FS{}), cfg, newTestLogger())
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Preview", "preview")
+	layout := NewLayout(site.ID, "Plain", "")
+	layout.Code = `<html><head><title>{{ .Content.Heading }}</title></head><body><h1>{{ .Content.Heading }}</h1>{{ .Content.HTMLBody }}</body></html>`
+	svc.CreateLayout(ctx, layout)
+	section := NewSection(site.ID, "Blog", "", "blog")
+	section.LayoutID = layout.ID
+	svc.CreateSection(ctx, section)
+
+	draft := NewContent(site.ID, section.ID, "Secret Plans", "Some **bold** text\n\n![Cover](/images/cover.png)")
+	draft.Kind = "post"
+	draft.Draft = true
+	svc.CreateContent(ctx, draft)
+
+	out, err := svc.RenderContentPreview(ctx, site, draft.ID)
+	if err != nil {
+		t.Fatalf("RenderContentPreview() error = %v", err)
+	}
+	page := string(out)
+	for _, want := range []string{
+		`<head><base href="http://preview.localhost:4000/"><title>Secret Plans</title>`,
+		"<h1>Secret Plans</h1>",
+		"<p>Some <strong>bold</strong> text</p>",
+		`<img src="/images/cover.png" alt="Cover"`,
+		"DRAFT &mdash; not published",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("preview has no %s:\n%s", want, page)
+		}
+	}
+
+	baseURL := NewSetting(site.ID, "Site base URL", "https://example.com")
+	baseURL.RefKey = BaseURLRefKey
+	svc.CreateSetting(ctx, baseURL)
+	if out, _ := svc.RenderContentPreview(ctx, site, draft.ID); !strings.Contains(string(out), `<base href="https://example.com/">`) {
+		t.Errorf("preview does not resolve URLs against the site base URL:\n%s", out)
+	}
+
+	layout.Code = `{{ .Content.NoSuchField }}`
+	svc.UpdateLayout(ctx, layout)
+	if _, err := svc.RenderContentPreview(ctx, site, draft.ID); !errors.Is(err, ErrLayoutTemplate) {
+		t.Errorf("RenderContentPreview() with a broken layout error = %v, want ErrLayoutTemplate", err)
+	}
+
+	other := createTestSite(t, svc, "Other", "other")
+	if _, err := svc.RenderContentPreview(ctx, other, draft.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RenderContentPreview() from another site error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPreviewServerCacheHeaders(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	cfg.SSG.Preview.CacheMaxAge = "10m"
+	workspace := NewWorkspace(cfg.SSG.SitesBasePath)
+	svc := NewService(&testutil.TestDBProvider{DB: db}, NewHTMLGenerator(workspace, embed.FS{}), cfg, newTestLogger())
+	server := NewPreviewServer(svc, cfg, newTestLogger())
+
+	files := map[string]string{
+		filepath.Join(workspace.GetImagesPath("cache"), "photo.jpg"):                         "jpeg data",
+		filepath.Join(workspace.GetHTMLPath("cache"), "static", "css", "theme.css"):          "body{}",
+		filepath.Join(workspace.GetHTMLPath("cache"), "static", "css", "theme.3f9a1c2e.css"): "body{}",
+		filepath.Join(workspace.GetHTMLPath("cache"), "index.html"):                          "<h1>Home</h1>",
+	}
+	for path, data := range files {
+		if err := EnsureDir(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://cache.localhost:3000"+path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		path         string
+		cacheControl string
+	}{
+		{"/images/photo.jpg", "public, max-age=600"},
+		{"/static/css/theme.css", "public, max-age=600"},
+		{"/static/css/theme.3f9a1c2e.css", "public, max-age=31536000, immutable"},
+		{"/", "public, no-cache"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := get(tt.path, nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+			etag := rec.Header().Get("ETag")
+			if etag == "" || rec.Header().Get("Last-Modified") == "" {
+				t.Fatalf("ETag = %q, Last-Modified = %q, want both", etag, rec.Header().Get("Last-Modified"))
+			}
+
+			if rec := get(tt.path, http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+				t.Errorf("If-None-Match: status = %d, want 304 without a body", rec.Code)
+			}
+			lastModified := rec.Header().Get("Last-Modified")
+			if rec := get(tt.path, http.Header{"If-Modified-Since": {lastModified}}); rec.Code != http.StatusNotModified {
+				t.Errorf("If-Modified-Since: status = %d, want 304", rec.Code)
+			}
+		})
+	}
+
+	t.Run("ranges", func(t *testing.T) {
+		rec := get("/images/photo.jpg", http.Header{"Range": {"bytes=0-3"}})
+		if rec.Code != http.StatusPartialContent || rec.Body.String() != "jpeg" {
+			t.Errorf("status = %d, body = %q, want 206 with the first four bytes", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("traversal", func(t *testing.T) {
+		if rec := get("/images/../../../etc/passwd", nil); rec.Code == http.StatusOK {
+			t.Errorf("status = %d, want the path refused", rec.Code)
+		}
+	})
+
+	t.Run("private behind auth", func(t *testing.T) {
+		cfg.SSG.Preview.RequireAuth = true
+		defer func() { cfg.SSG.Preview.RequireAuth = false }()
+		server.SetAuthFunc(func(context.Context, string, string) error { return nil })
+		req := httptest.NewRequest(http.MethodGet, "http://cache.localhost:3000/images/photo.jpg", nil)
+		req.SetBasicAuth("editor@example.com", "secret")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Cache-Control"); got != "private, max-age=600" {
+			t.Errorf("Cache-Control = %q, want private", got)
+		}
+	})
+}
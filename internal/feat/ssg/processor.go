@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	htmltemplate "html/template"
 	"regexp"
 	"strings"
 
@@ -13,11 +14,23 @@ import (
 	"github.com/yuin/goldmark/renderer/html"
 )
 
+// LazyImagesRefKey turns lazy-loading of content images on or off. Images
+// load lazily unless it is "false".
+const LazyImagesRefKey = "ssg.images.lazy"
+
 type ImageMeta struct {
 	Title          string `json:"title"`
 	Alt            string `json:"alt"`
 	Attribution    string `json:"attribution"`
 	AttributionURL string `json:"attribution_url"`
+	Width          int    `json:"width,omitempty"`
+	Height         int    `json:"height,omitempty"`
+}
+
+// imageLoading is how enhanceImages asks browsers to load images.
+type imageLoading struct {
+	Lazy       bool // loading="lazy" on the images
+	EagerFirst bool // the first image loads at once, as it is likely in view
 }
 
 // Processor handles markdown to HTML conversion.
@@ -64,7 +77,8 @@ func (p *Processor) ToHTMLString(markdown string) (string, error) {
 }
 
 // ProcessContent processes a Content's body and returns HTML.
-// Optional params map is used for form generation (ssg.forms.endpoint_url).
+// Optional params map is used for form generation (ssg.forms.endpoint_url)
+// and external link attributes (ssg.links.*).
 func (p *Processor) ProcessContent(content *Content, params ...map[string]string) (string, error) {
 	html, err := p.ToHTML([]byte(content.Body))
 	if err != nil {
@@ -80,21 +94,43 @@ func (p *Processor) ProcessContent(content *Content, params ...map[string]string
 		json.Unmarshal([]byte(content.ImagesMeta), &imagesMeta)
 	}
 
-	// Post-process images with captions (using |||long description syntax)
-	html = p.enhanceImages(html, imagesMeta)
+	var paramsMap map[string]string
+	if len(params) > 0 {
+		paramsMap = params[0]
+	}
+
+	// Post-process images with captions (using |||long description syntax).
+	// Without a header image, the first image of the body is the one in view.
+	html = p.enhanceImages(html, imagesMeta, imageLoading{
+		Lazy:       paramsMap[LazyImagesRefKey] != "false",
+		EagerFirst: content.HeaderImageURL == "",
+	})
 
 	// Process embed code blocks
 	html = processEmbeds(html)
 
 	// Process form code blocks
-	var paramsMap map[string]string
-	if len(params) > 0 {
-		paramsMap = params[0]
-	}
 	if paramsMap != nil && paramsMap["ssg.forms.enabled"] == "true" {
 		html = processForms(html, content.SiteID.String(), paramsMap["ssg.forms.endpoint_url"], true)
 	}
 
+	// Take tracking parameters off links to the site, and off links to other
+	// sites when the site asks for it and the content does not keep them
+	if paramsMap != nil {
+		external := paramsMap[ExternalLinkStripTrackingRefKey] == "true" && (content.Meta == nil || !content.Meta.KeepLinks)
+		html = cleanTrackingLinks(html, siteHost(paramsMap), external)
+	}
+
+	// Mark links to other sites, unless the content keeps them as written
+	if paramsMap != nil && (content.Meta == nil || !content.Meta.KeepLinks) {
+		html = rewriteExternalLinks(html, siteHost(paramsMap), linkRewriteOptionsFromParams(paramsMap))
+	}
+
+	// Give links to pages of the site the trailing slash policy of the site
+	if paramsMap != nil {
+		html = normalizeInternalLinks(html, paramsMap)
+	}
+
 	return html, nil
 }
 
@@ -107,10 +143,12 @@ func (p *Processor) transformImagePaths(html string) string {
 
 // enhanceImages post-processes HTML to enhance images with captions and credits.
 // Supports syntax: ![alt text|||caption](image.jpg)
-// Also adds attribution credits from imagesMeta if available.
-func (p *Processor) enhanceImages(html string, imagesMeta map[string]ImageMeta) string {
+// Also adds attribution credits and dimensions from imagesMeta if available.
+// Dimensions let browsers reserve the space of an image before it loads.
+func (p *Processor) enhanceImages(html string, imagesMeta map[string]ImageMeta, loading imageLoading) string {
 	imgRegex := regexp.MustCompile(`<img([^>]*?)alt="([^"]*?)"([^>]*?)>`)
 
+	n := 0
 	result := imgRegex.ReplaceAllStringFunc(html, func(match string) string {
 		srcRegex := regexp.MustCompile(`src="([^"]*)"`)
 		altRegex := regexp.MustCompile(`alt="([^"]*)"`)
@@ -134,19 +172,34 @@ func (p *Processor) enhanceImages(html string, imagesMeta map[string]ImageMeta)
 			altText = altValue
 		}
 
-		enhancedImg := fmt.Sprintf(`<img src="%s" alt="%s" class="content-img" loading="lazy">`, srcValue, altText)
+		meta := imagesMeta[srcValue]
+
+		var attrs string
+		if meta.Width > 0 && meta.Height > 0 {
+			attrs = fmt.Sprintf(` width="%d" height="%d"`, meta.Width, meta.Height)
+		}
+		switch {
+		case loading.EagerFirst && n == 0:
+			attrs += ` loading="eager"`
+		case loading.Lazy:
+			attrs += ` loading="lazy"`
+		}
+		n++
+
+		enhancedImg := fmt.Sprintf(`<img src="%s" alt="%s" class="content-img"%s decoding="async">`, srcValue, altText, attrs)
 
 		// Check for image metadata (attribution)
 		var credit string
-		if imagesMeta != nil {
-			if meta, ok := imagesMeta[srcValue]; ok && meta.Attribution != "" {
-				if meta.AttributionURL != "" {
-					credit = fmt.Sprintf(`<figcaption class="content-credit"><span class="content-credit-title">%s</span><span class="content-credit-attr"><a href="%s" target="_blank" rel="noopener">%s</a></span></figcaption>`,
-						meta.Title, meta.AttributionURL, meta.Attribution)
-				} else {
-					credit = fmt.Sprintf(`<figcaption class="content-credit"><span class="content-credit-title">%s</span><span class="content-credit-attr">%s</span></figcaption>`,
-						meta.Title, meta.Attribution)
-				}
+		if meta.Attribution != "" {
+			// Credits are user input: escape them and only link web URLs.
+			title := htmltemplate.HTMLEscapeString(meta.Title)
+			attribution := htmltemplate.HTMLEscapeString(meta.Attribution)
+			if isWebURL(meta.AttributionURL) {
+				credit = fmt.Sprintf(`<figcaption class="content-credit"><span class="content-credit-title">%s</span><span class="content-credit-attr"><a href="%s" target="_blank" rel="noopener">%s</a></span></figcaption>`,
+					title, htmltemplate.HTMLEscapeString(meta.AttributionURL), attribution)
+			} else {
+				credit = fmt.Sprintf(`<figcaption class="content-credit"><span class="content-credit-title">%s</span><span class="content-credit-attr">%s</span></figcaption>`,
+					title, attribution)
 			}
 		}
 
@@ -0,0 +1,70 @@
+package ssg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessContentImages(t *testing.T) {
+	p := NewProcessor()
+	content := &Content{
+		Body:       "![First](/images/a.png)\n\n![Second](/images/b.png)",
+		ImagesMeta: `{"/images/a.png":{"width":800,"height":600},"/images/b.png":{"width":1200,"height":675}}`,
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		params map[string]string
+		want   []string
+	}{
+		{
+			name: "first image eager without a header image",
+			want: []string{
+				`<img src="/images/a.png" alt="First" class="content-img" width="800" height="600" loading="eager" decoding="async">`,
+				`<img src="/images/b.png" alt="Second" class="content-img" width="1200" height="675" loading="lazy" decoding="async">`,
+			},
+		},
+		{
+			name:   "all lazy below a header image",
+			header: "/images/header.png",
+			want: []string{
+				`<img src="/images/a.png" alt="First" class="content-img" width="800" height="600" loading="lazy" decoding="async">`,
+				`<img src="/images/b.png" alt="Second" class="content-img" width="1200" height="675" loading="lazy" decoding="async">`,
+			},
+		},
+		{
+			name:   "lazy-loading off",
+			header: "/images/header.png",
+			params: map[string]string{LazyImagesRefKey: "false"},
+			want: []string{
+				`<img src="/images/a.png" alt="First" class="content-img" width="800" height="600" decoding="async">`,
+				`<img src="/images/b.png" alt="Second" class="content-img" width="1200" height="675" decoding="async">`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content.HeaderImageURL = tt.header
+			got, err := p.ProcessContent(content, tt.params)
+			if err != nil {
+				t.Fatalf("ProcessContent() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("ProcessContent() = %s, want %s", got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown dimensions are left out", func(t *testing.T) {
+		got, err := p.ProcessContent(&Content{HeaderImageURL: "/images/header.png", Body: "![Alt](/images/c.png)"})
+		if err != nil {
+			t.Fatalf("ProcessContent() error = %v", err)
+		}
+		if want := `<img src="/images/c.png" alt="Alt" class="content-img" loading="lazy" decoding="async">`; !strings.Contains(got, want) {
+			t.Errorf("ProcessContent() = %s, want %s", got, want)
+		}
+	})
+}
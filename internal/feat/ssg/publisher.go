@@ -2,6 +2,7 @@ package ssg
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/cliossg/clio/pkg/cl/git"
+	"github.com/cliossg/clio/pkg/cl/metrics"
 )
 
 type PublishConfig struct {
@@ -20,8 +22,84 @@ type PublishConfig struct {
 	CommitName  string
 	CommitEmail string
 	UseSSH      bool
+	// SSH sets an explicit key and host key policy for SSH repositories.
+	SSH git.SSHOptions
+	// Signing signs publish and backup commits when a key is set.
+	Signing git.Signing
+	// ShallowDepth is how many commits of the branch history are cloned.
+	// Zero uses defaultShallowDepth; a negative value clones the full history.
+	ShallowDepth int
 }
 
+// defaultShallowDepth is enough for publishing: each run replaces the whole
+// tree with a new commit on top of the branch head and force-pushes it.
+const defaultShallowDepth = 1
+
+func (cfg PublishConfig) gitAuth() git.Auth {
+	if cfg.UseSSH {
+		return git.Auth{Method: git.AuthSSH, SSH: cfg.SSH}
+	}
+	return git.Auth{Method: git.AuthToken, Token: cfg.AuthToken}
+}
+
+// SSHOptionsFromSettings reads the SSH settings shared by publish and backup.
+func SSHOptionsFromSettings(settings []*Setting) git.SSHOptions {
+	m := make(map[string]string)
+	for _, p := range settings {
+		m[p.RefKey] = p.Value
+	}
+	return git.SSHOptions{
+		KeyPath:       strings.TrimSpace(m[SSHKeyPathRefKey]),
+		Key:           m[SSHKeyRefKey],
+		Passphrase:    m[SSHPassphraseRefKey],
+		KnownHosts:    m[SSHKnownHostsRefKey],
+		HostKeyPolicy: git.HostKeyPolicy(m[SSHHostKeyPolicyRefKey]),
+	}
+}
+
+// SigningFromSettings reads the commit signing settings shared by publish and
+// backup.
+func SigningFromSettings(settings []*Setting) git.Signing {
+	m := make(map[string]string)
+	for _, p := range settings {
+		m[p.RefKey] = p.Value
+	}
+	return git.Signing{
+		Format:   git.SigningFormat(strings.TrimSpace(m[SigningFormatRefKey])),
+		Key:      strings.TrimSpace(m[SigningKeyRefKey]),
+		Required: m[SigningRequiredRefKey] == "true",
+	}
+}
+
+// cloneOptions limits clones to the target branch. There is no sparse
+// checkout: the site output is the repository root, so all of it is needed.
+func (cfg PublishConfig) cloneOptions() git.CloneOptions {
+	depth := cfg.ShallowDepth
+	if depth == 0 {
+		depth = defaultShallowDepth
+	}
+	if depth < 0 {
+		return git.CloneOptions{}
+	}
+	return git.CloneOptions{Branch: cfg.Branch, Depth: depth}
+}
+
+// Setting ref keys for SSH auth, shared by publish and backup.
+const (
+	SSHKeyPathRefKey       = "ssg.git.ssh.key_path"
+	SSHKeyRefKey           = "ssg.git.ssh.key"
+	SSHPassphraseRefKey    = "ssg.git.ssh.passphrase"
+	SSHKnownHostsRefKey    = "ssg.git.ssh.known_hosts"
+	SSHHostKeyPolicyRefKey = "ssg.git.ssh.host_key_policy"
+)
+
+// Setting ref keys for commit signing, shared by publish and backup.
+const (
+	SigningFormatRefKey   = "ssg.git.signing.format"
+	SigningKeyRefKey      = "ssg.git.signing.key"
+	SigningRequiredRefKey = "ssg.git.signing.required"
+)
+
 type PublishResult struct {
 	CommitHash string
 	CommitURL  string
@@ -42,6 +120,7 @@ type Publisher struct {
 	workspace *Workspace
 	gitClient git.Client
 	mu        sync.Mutex
+	publishes *metrics.Counter // by result, see metrics.go
 }
 
 func NewPublisher(workspace *Workspace, gitClient git.Client) *Publisher {
@@ -64,10 +143,19 @@ func (p *Publisher) Validate(cfg PublishConfig) error {
 	if cfg.CommitEmail == "" {
 		return fmt.Errorf("commit email is required")
 	}
+	if cfg.Signing.Required && cfg.Signing.Key == "" {
+		return fmt.Errorf("%w: signing is required but no signing key is set", git.ErrSigningFailed)
+	}
 	return nil
 }
 
 func (p *Publisher) Publish(ctx context.Context, cfg PublishConfig, siteSlug string) (*PublishResult, error) {
+	result, err := p.publish(ctx, cfg, siteSlug)
+	p.publishes.Inc(publishOutcome(err))
+	return result, err
+}
+
+func (p *Publisher) publish(ctx context.Context, cfg PublishConfig, siteSlug string) (*PublishResult, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -89,15 +177,9 @@ func (p *Publisher) Publish(ctx context.Context, cfg PublishConfig, siteSlug str
 	tempDir := filepath.Join(parentTempDir, "repo")
 	env := os.Environ()
 
-	auth := git.Auth{
-		Method: git.AuthToken,
-		Token:  cfg.AuthToken,
-	}
-	if cfg.UseSSH {
-		auth = git.Auth{Method: git.AuthSSH}
-	}
+	auth := cfg.gitAuth()
 
-	if err := p.gitClient.Clone(ctx, cfg.RepoURL, tempDir, auth, env); err != nil {
+	if err := p.gitClient.Clone(ctx, cfg.RepoURL, tempDir, auth, cfg.cloneOptions(), env); err != nil {
 		return nil, fmt.Errorf("cannot clone repo: %w", err)
 	}
 
@@ -138,9 +220,13 @@ func (p *Publisher) Publish(ctx context.Context, cfg PublishConfig, siteSlug str
 		UserName:  commitName,
 		UserEmail: cfg.CommitEmail,
 		Message:   fmt.Sprintf("Deploy site - %s", time.Now().Format("2006-01-02 15:04:05")),
+		Signing:   cfg.Signing,
 	}
 
 	commitHash, err := p.gitClient.Commit(ctx, tempDir, commit, env)
+	if errors.Is(err, git.ErrSigningFailed) {
+		return nil, fmt.Errorf("cannot sign commit: %w", err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("cannot commit: %w", err)
 	}
@@ -188,15 +274,9 @@ func (p *Publisher) Backup(ctx context.Context, cfg PublishConfig, siteSlug stri
 	tempDir := filepath.Join(parentTempDir, "repo")
 	env := os.Environ()
 
-	auth := git.Auth{
-		Method: git.AuthToken,
-		Token:  cfg.AuthToken,
-	}
-	if cfg.UseSSH {
-		auth = git.Auth{Method: git.AuthSSH}
-	}
+	auth := cfg.gitAuth()
 
-	if err := p.gitClient.Clone(ctx, cfg.RepoURL, tempDir, auth, env); err != nil {
+	if err := p.gitClient.Clone(ctx, cfg.RepoURL, tempDir, auth, cfg.cloneOptions(), env); err != nil {
 		return nil, fmt.Errorf("cannot clone repo: %w", err)
 	}
 
@@ -271,9 +351,13 @@ func (p *Publisher) Backup(ctx context.Context, cfg PublishConfig, siteSlug stri
 		UserName:  commitName,
 		UserEmail: cfg.CommitEmail,
 		Message:   fmt.Sprintf("Backup site - %s", time.Now().Format("2006-01-02 15:04:05")),
+		Signing:   cfg.Signing,
 	}
 
 	commitHash, err := p.gitClient.Commit(ctx, tempDir, commit, env)
+	if errors.Is(err, git.ErrSigningFailed) {
+		return nil, fmt.Errorf("cannot sign commit: %w", err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("cannot commit: %w", err)
 	}
@@ -314,15 +398,9 @@ func (p *Publisher) Plan(ctx context.Context, cfg PublishConfig, siteSlug string
 	tempDir := filepath.Join(parentTempDir, "repo")
 	env := os.Environ()
 
-	auth := git.Auth{
-		Method: git.AuthToken,
-		Token:  cfg.AuthToken,
-	}
-	if cfg.UseSSH {
-		auth = git.Auth{Method: git.AuthSSH}
-	}
+	auth := cfg.gitAuth()
 
-	if err := p.gitClient.Clone(ctx, cfg.RepoURL, tempDir, auth, env); err != nil {
+	if err := p.gitClient.Clone(ctx, cfg.RepoURL, tempDir, auth, cfg.cloneOptions(), env); err != nil {
 		return nil, fmt.Errorf("cannot clone repo: %w", err)
 	}
 
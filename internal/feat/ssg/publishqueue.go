@@ -0,0 +1,245 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/logger"
+	"github.com/google/uuid"
+)
+
+// publishQueueSize is how many jobs can wait for the worker. Each site has at
+// most one job waiting, so it only fills up with many sites.
+const publishQueueSize = 64
+
+var (
+	errGenerationFailed = errors.New("HTML generation failed")
+	errPublishBlocked   = errors.New("publish blocked")
+)
+
+// publishDeps is what generating and publishing a site takes. The scheduler
+// and the publish queue share it.
+type publishDeps struct {
+	service   Service
+	htmlGen   *HTMLGenerator
+	publisher *Publisher
+	log       logger.Logger
+}
+
+// generateAndPublish regenerates the site HTML, publishes it and records the
+// publish time. progress, if set, is called as each step starts.
+func generateAndPublish(ctx context.Context, d publishDeps, site *Site, contents []*Content, progress func(step string)) (*PublishResult, error) {
+	if progress == nil {
+		progress = func(string) {}
+	}
+
+	sections, err := d.service.GetSections(ctx, site.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sections for site %s: %w", site.Slug, err)
+	}
+
+	layouts, _ := d.service.GetLayouts(ctx, site.ID)
+	if layouts == nil {
+		layouts = []*Layout{}
+	}
+
+	kinds, _ := d.service.ListContentKinds(ctx, site.ID)
+
+	settings, _ := d.service.GetSettings(ctx, site.ID)
+	if settings == nil {
+		settings = []*Setting{}
+	}
+	if err := CheckSecrets(ctx, d.service, site.ID); err != nil {
+		return nil, fmt.Errorf("cannot read credentials for site %s: %w", site.Slug, err)
+	}
+
+	contributors, _ := d.service.GetContributors(ctx, site.ID)
+	if contributors == nil {
+		contributors = []*Contributor{}
+	}
+
+	userAuthors := d.service.BuildUserAuthorsMap(ctx, contents, contributors)
+
+	progress("Generating HTML")
+	htmlResult, err := d.htmlGen.GenerateHTML(ctx, site, contents, sections, layouts, kinds, settings, contributors, userAuthors, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w for site %s: %w", errGenerationFailed, site.Slug, err)
+	}
+	if err := d.service.MarkSiteGenerated(ctx, site.ID, time.Now()); err != nil {
+		d.log.Errorf("Cannot record generation time for site %s: %v", site.Slug, err)
+	}
+	if htmlResult.PublishBlocked {
+		return nil, fmt.Errorf("%w for site %s: %d accessibility errors", errPublishBlocked, site.Slug, htmlResult.Accessibility.Errors())
+	}
+
+	cfg, err := buildPublishConfigFromSettings(settings)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build publish config for site %s: %w", site.Slug, err)
+	}
+
+	progress(fmt.Sprintf("Publishing %d pages to Git", htmlResult.PagesGenerated))
+	result, err := d.publisher.Publish(ctx, cfg, site.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("publish failed for site %s: %w", site.Slug, err)
+	}
+
+	// With no changes the repository already matches the current content,
+	// so the site still counts as published now.
+	now := time.Now()
+	site.LastPublishedAt = &now
+	if err := d.service.MarkSitePublished(ctx, site.ID, now, result.CommitHash); err != nil {
+		return nil, fmt.Errorf("cannot record publish time for site %s: %w", site.Slug, err)
+	}
+	return result, nil
+}
+
+// publishErrorCode maps a publish failure to the error code of the site page
+// messages.
+func publishErrorCode(err error) string {
+	switch {
+	case errors.Is(err, errPublishNotConfigured):
+		return "publish_not_configured"
+	case errors.Is(err, errPublishBlocked):
+		return "publish_a11y_blocked"
+	case errors.Is(err, errGenerationFailed):
+		return "publish_generation_failed"
+	}
+	return gitErrorCode("publish", err)
+}
+
+// PublishQueue publishes sites in the background, one job at a time, so a
+// publish request returns at once. Jobs are stored with their progress and
+// outcome, so their status can be polled and outlives the page that asked.
+type PublishQueue struct {
+	publishDeps
+	jobs chan uuid.UUID
+	stop chan struct{}
+}
+
+func NewPublishQueue(service Service, htmlGen *HTMLGenerator, publisher *Publisher, log logger.Logger) *PublishQueue {
+	return &PublishQueue{
+		publishDeps: publishDeps{service: service, htmlGen: htmlGen, publisher: publisher, log: log},
+		jobs:        make(chan uuid.UUID, publishQueueSize),
+	}
+}
+
+// Start fails the jobs a previous run left unfinished and starts the worker.
+func (q *PublishQueue) Start(ctx context.Context) error {
+	n, err := q.service.FailUnfinishedPublishJobs(ctx, "Interrupted by a server restart")
+	if err != nil {
+		q.log.Errorf("Publish queue: %v", err)
+	} else if n > 0 {
+		q.log.Infof("Publish queue: %d unfinished jobs marked as failed", n)
+	}
+
+	q.stop = make(chan struct{})
+	go q.run(ctx, q.stop)
+	q.log.Info("Publish queue: started")
+	return nil
+}
+
+// Stop stops the worker. A publish in progress is not waited for; its job
+// is failed on the next start.
+func (q *PublishQueue) Stop(_ context.Context) error {
+	if q.stop != nil {
+		close(q.stop)
+		q.stop = nil
+		q.log.Info("Publish queue: stopped")
+	}
+	return nil
+}
+
+// Enqueue queues a publish of a site. While the site has a job queued or
+// running, that job is returned with ErrPublishQueued.
+func (q *PublishQueue) Enqueue(ctx context.Context, siteID, userID uuid.UUID) (*PublishJob, error) {
+	job, err := q.service.CreatePublishJob(ctx, siteID, userID)
+	if err != nil {
+		return job, err
+	}
+
+	select {
+	case q.jobs <- job.ID:
+	default:
+		now := time.Now()
+		job.Status = PublishJobFailed
+		job.Error = "Too many publishes are queued. Try again later"
+		job.FinishedAt = &now
+		q.save(ctx, job)
+	}
+	return job, nil
+}
+
+func (q *PublishQueue) run(ctx context.Context, stop <-chan struct{}) {
+	for {
+		select {
+		case id := <-q.jobs:
+			q.process(ctx, id)
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// process runs one job, saving its progress as it goes.
+func (q *PublishQueue) process(ctx context.Context, id uuid.UUID) {
+	job, err := q.service.GetPublishJob(ctx, id)
+	if err != nil {
+		q.log.Errorf("Publish queue: cannot load job %s: %v", id, err)
+		return
+	}
+
+	now := time.Now()
+	job.Status = PublishJobRunning
+	job.StartedAt = &now
+	q.save(ctx, job)
+
+	site, err := q.service.GetSite(ctx, job.SiteID)
+	if err != nil {
+		q.finish(ctx, job, nil, fmt.Errorf("cannot load site %s: %w", job.SiteID, err))
+		return
+	}
+
+	contents, err := q.service.GetAllContentWithMeta(ctx, site.ID)
+	if err != nil {
+		q.finish(ctx, job, nil, fmt.Errorf("cannot get content for site %s: %w", site.Slug, err))
+		return
+	}
+
+	result, err := generateAndPublish(ctx, q.publishDeps, site, contents, func(step string) {
+		job.Progress = step
+		q.save(ctx, job)
+	})
+	q.finish(ctx, job, result, err)
+	if err == nil {
+		q.log.Infof("Publish queue: published site %s in %s", site.Slug, job.FinishedAt.Sub(*job.StartedAt).Round(time.Millisecond))
+	}
+}
+
+// finish records the outcome of a job. The error shown to users is the site
+// page message for it; the full error goes to the log.
+func (q *PublishQueue) finish(ctx context.Context, job *PublishJob, result *PublishResult, err error) {
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Progress = ""
+	if err != nil {
+		q.log.Errorf("Publish queue: job %s failed: %v", job.ID, err)
+		job.Status = PublishJobFailed
+		job.Error = siteErrorMessages[publishErrorCode(err)]
+	} else {
+		job.Status = PublishJobSucceeded
+		job.CommitHash = result.CommitHash
+		job.CommitURL = result.CommitURL
+		job.NoChanges = result.NoChanges
+	}
+	q.save(ctx, job)
+}
+
+func (q *PublishQueue) save(ctx context.Context, job *PublishJob) {
+	if err := q.service.UpdatePublishJob(ctx, job); err != nil {
+		q.log.Errorf("Publish queue: %v", err)
+	}
+}
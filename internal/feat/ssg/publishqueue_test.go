@@ -0,0 +1,169 @@
+package ssg
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/git"
+	"github.com/google/uuid"
+)
+
+func TestServicePublishJobs(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Publish Site", "publish-site")
+	userID := uuid.New()
+
+	job, err := svc.CreatePublishJob(ctx, site.ID, userID)
+	if err != nil {
+		t.Fatalf("CreatePublishJob() error = %v", err)
+	}
+	if job.Status != PublishJobQueued || job.CreatedBy != userID {
+		t.Errorf("job = %+v, want queued by the user", job)
+	}
+
+	again, err := svc.CreatePublishJob(ctx, site.ID, userID)
+	if !errors.Is(err, ErrPublishQueued) || again.ID != job.ID {
+		t.Errorf("CreatePublishJob() with a job queued = %v, %v; want the queued job and ErrPublishQueued", again, err)
+	}
+
+	started := time.Now()
+	job.Status = PublishJobRunning
+	job.Progress = "Generating HTML"
+	job.StartedAt = &started
+	if err := svc.UpdatePublishJob(ctx, job); err != nil {
+		t.Fatalf("UpdatePublishJob() error = %v", err)
+	}
+	if _, err := svc.CreatePublishJob(ctx, site.ID, userID); !errors.Is(err, ErrPublishQueued) {
+		t.Errorf("CreatePublishJob() with a job running error = %v, want ErrPublishQueued", err)
+	}
+
+	finished := started.Add(time.Second)
+	job.Status = PublishJobSucceeded
+	job.Progress = ""
+	job.CommitHash = "abc1234def"
+	job.CommitURL = "https://example.com/commit/abc1234def"
+	job.FinishedAt = &finished
+	if err := svc.UpdatePublishJob(ctx, job); err != nil {
+		t.Fatalf("UpdatePublishJob() error = %v", err)
+	}
+	got, err := svc.GetPublishJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetPublishJob() error = %v", err)
+	}
+	if !got.Done() || got.CommitURL != job.CommitURL || got.StartedAt == nil || got.FinishedAt == nil {
+		t.Errorf("GetPublishJob() = %+v, want the saved outcome", got)
+	}
+
+	t.Run("history is capped", func(t *testing.T) {
+		for i := 0; i < publishJobHistory+2; i++ {
+			j, err := svc.CreatePublishJob(ctx, site.ID, userID)
+			if err != nil {
+				t.Fatalf("CreatePublishJob() error = %v", err)
+			}
+			j.Status = PublishJobFailed
+			if err := svc.UpdatePublishJob(ctx, j); err != nil {
+				t.Fatal(err)
+			}
+		}
+		jobs, err := svc.ListPublishJobs(ctx, site.ID)
+		if err != nil {
+			t.Fatalf("ListPublishJobs() error = %v", err)
+		}
+		if len(jobs) != publishJobHistory {
+			t.Errorf("ListPublishJobs() = %d jobs, want %d", len(jobs), publishJobHistory)
+		}
+		if _, err := svc.GetPublishJob(ctx, job.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("oldest job not pruned: %v", err)
+		}
+	})
+
+	t.Run("unfinished jobs fail on start", func(t *testing.T) {
+		j, err := svc.CreatePublishJob(ctx, site.ID, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := svc.FailUnfinishedPublishJobs(ctx, "Interrupted")
+		if err != nil || n != 1 {
+			t.Fatalf("FailUnfinishedPublishJobs() = %d, %v; want 1", n, err)
+		}
+		got, _ := svc.GetPublishJob(ctx, j.ID)
+		if got.Status != PublishJobFailed || got.Error != "Interrupted" || got.FinishedAt == nil {
+			t.Errorf("job = %+v, want failed", got)
+		}
+	})
+}
+
+func TestPublishQueue(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
+	defer cleanup()
+	// Each connection to an in-memory database is a database of its own,
+	// so the worker and the test must share one.
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := NewPublishQueue(svc, NewHTMLGenerator(NewWorkspace(t.TempDir()), embed.FS{}), nil, newTestLogger())
+	if err := q.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer q.Stop(ctx)
+
+	// The generator has no templates, so the job fails before anything is
+	// pushed.
+	site := createTestSite(t, svc, "No Templates", "no-templates")
+	job, err := q.Enqueue(ctx, site.ID, uuid.New())
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if _, err := q.Enqueue(ctx, site.ID, uuid.New()); !errors.Is(err, ErrPublishQueued) {
+		t.Errorf("second Enqueue() error = %v, want ErrPublishQueued", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := svc.GetPublishJob(ctx, job.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Done() {
+			if got.Status != PublishJobFailed || got.Error != siteErrorMessages["publish_generation_failed"] {
+				t.Errorf("job = %+v, want failed generating", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job still %s", got.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPublishErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errPublishNotConfigured, "publish_not_configured"},
+		{fmt.Errorf("%w for site s: 2 accessibility errors", errPublishBlocked), "publish_a11y_blocked"},
+		{fmt.Errorf("%w for site s: boom", errGenerationFailed), "publish_generation_failed"},
+		{fmt.Errorf("publish failed for site s: %w", git.ErrAuthFailed), "publish_auth_failed"},
+		{errors.New("boom"), "publish_failed"},
+	}
+	for _, tt := range tests {
+		code := publishErrorCode(tt.err)
+		if code != tt.want {
+			t.Errorf("publishErrorCode(%v) = %q, want %q", tt.err, code, tt.want)
+		}
+		if siteErrorMessages[code] == "" {
+			t.Errorf("no message for %q", code)
+		}
+	}
+}
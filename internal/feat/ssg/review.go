@@ -0,0 +1,172 @@
+package ssg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewRequiredRefKey turns on the approval step of a site: drafts are
+// published only once a reviewer approves them. Content published before it
+// was turned on is not affected.
+const ReviewRequiredRefKey = "ssg.review.required"
+
+// Review statuses of content, see ContentReview. Content never submitted has
+// no review.
+const (
+	ReviewInReview         = "in_review"
+	ReviewChangesRequested = "changes_requested"
+	ReviewApproved         = "approved"
+)
+
+var (
+	ErrReviewTransition = errors.New("invalid review transition")
+	ErrReviewComment    = errors.New("a comment is required to request changes")
+	ErrNotApproved      = errors.New("content must be approved by a reviewer before it is published")
+	ErrNotReviewer      = errors.New("user cannot review content")
+)
+
+// ContentReview is where a draft stands in the approval step. Authors submit
+// it, an editor or admin is assigned to review it, and the reviewer approves
+// it or sends it back with a comment. Submitting it again starts a new round.
+type ContentReview struct {
+	ContentID   uuid.UUID  `json:"content_id"`
+	Status      string     `json:"status"`
+	ReviewerID  uuid.UUID  `json:"reviewer_id"` // uuid.Nil until a reviewer is assigned
+	SubmittedBy uuid.UUID  `json:"submitted_by"`
+	Comment     string     `json:"comment"` // left by the reviewer
+	SubmittedAt time.Time  `json:"submitted_at"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Joined fields
+	ContentHeading string `json:"content_heading,omitempty"`
+	ContentShortID string `json:"content_short_id,omitempty"`
+	ReviewerName   string `json:"reviewer_name,omitempty"`
+	SubmitterName  string `json:"submitter_name,omitempty"`
+}
+
+// Approved reports whether the content can be published.
+func (r *ContentReview) Approved() bool {
+	return r != nil && r.Status == ReviewApproved
+}
+
+// Reviewer is a user who can review content: an admin or an editor.
+type Reviewer struct {
+	ID    uuid.UUID
+	Name  string
+	Email string
+}
+
+// isReviewerRole reports whether a comma separated list of roles holds a
+// role that reviews content.
+func isReviewerRole(roles string) bool {
+	for _, role := range strings.Split(roles, ",") {
+		if r := strings.TrimSpace(role); r == "admin" || r == "editor" {
+			return true
+		}
+	}
+	return false
+}
+
+// submitReview returns the review that submitting the draft content starts.
+// The reviewer of a previous round stays assigned.
+func submitReview(current *ContentReview, content *Content, userID uuid.UUID, now time.Time) (*ContentReview, error) {
+	if !content.Draft {
+		return nil, fmt.Errorf("%w: only drafts are submitted for review", ErrReviewTransition)
+	}
+	if current != nil && current.Status == ReviewInReview {
+		return nil, fmt.Errorf("%w: content is already in review", ErrReviewTransition)
+	}
+	review := &ContentReview{
+		ContentID:   content.ID,
+		Status:      ReviewInReview,
+		SubmittedBy: userID,
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+	if current != nil {
+		review.ReviewerID = current.ReviewerID
+	}
+	return review, nil
+}
+
+// assignReview assigns a reviewer to content in review. Submitters do not
+// review their own content.
+func assignReview(current *ContentReview, reviewerID uuid.UUID, now time.Time) (*ContentReview, error) {
+	if current == nil || current.Status != ReviewInReview {
+		return nil, fmt.Errorf("%w: only content in review gets a reviewer", ErrReviewTransition)
+	}
+	if reviewerID == current.SubmittedBy {
+		return nil, fmt.Errorf("%w: submitters do not review their own content", ErrNotReviewer)
+	}
+	review := *current
+	review.ReviewerID = reviewerID
+	review.UpdatedAt = now
+	return &review, nil
+}
+
+// decideReview closes the round of content in review with the reviewer's
+// decision, approved or changes requested. Changes need a comment. Only the
+// assigned reviewer decides, or any reviewer but the submitter while none is
+// assigned.
+func decideReview(current *ContentReview, status string, reviewerID uuid.UUID, comment string, now time.Time) (*ContentReview, error) {
+	if current == nil || current.Status != ReviewInReview {
+		return nil, fmt.Errorf("%w: content is not in review", ErrReviewTransition)
+	}
+	if reviewerID == current.SubmittedBy {
+		return nil, fmt.Errorf("%w: submitters do not review their own content", ErrNotReviewer)
+	}
+	if current.ReviewerID != uuid.Nil && reviewerID != current.ReviewerID {
+		return nil, fmt.Errorf("%w: content is assigned to another reviewer", ErrNotReviewer)
+	}
+	comment = strings.TrimSpace(comment)
+	if status == ReviewChangesRequested && comment == "" {
+		return nil, ErrReviewComment
+	}
+	review := *current
+	review.Status = status
+	review.ReviewerID = reviewerID
+	review.Comment = comment
+	review.ReviewedAt = &now
+	review.UpdatedAt = now
+	return &review, nil
+}
+
+// reopenReview returns approved content to review after its text changed,
+// so the approval always covers what gets published. It returns nil when
+// the review stays as it is.
+func reopenReview(current *ContentReview, now time.Time) *ContentReview {
+	if !current.Approved() {
+		return nil
+	}
+	review := *current
+	review.Status = ReviewInReview
+	review.Comment = ""
+	review.ReviewedAt = nil
+	review.UpdatedAt = now
+	return &review
+}
+
+// withoutUnapproved drops the published content whose review is open when
+// the site requires reviews, which only happens when it went live around
+// the approval step. Content never submitted was published before reviews
+// were required and is kept. Each dropped item adds a warning.
+func withoutUnapproved(contents []*Content, params map[string]string) ([]*Content, []string) {
+	if params[ReviewRequiredRefKey] != "true" {
+		return contents, nil
+	}
+	kept := make([]*Content, 0, len(contents))
+	var warnings []string
+	for _, c := range contents {
+		if !c.Draft && c.ReviewStatus != "" && c.ReviewStatus != ReviewApproved {
+			warnings = append(warnings, fmt.Sprintf("%q is not published: it is not approved", c.Heading))
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, warnings
+}
@@ -0,0 +1,304 @@
+package ssg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestReviewTransitions(t *testing.T) {
+	now := time.Now()
+	author, reviewer := uuid.New(), uuid.New()
+	draft := &Content{ID: uuid.New(), Draft: true}
+
+	review, err := submitReview(nil, draft, author, now)
+	if err != nil {
+		t.Fatalf("submitReview() error = %v", err)
+	}
+	if review.Status != ReviewInReview || review.SubmittedBy != author {
+		t.Errorf("submitReview() = %+v, want in review by the author", review)
+	}
+
+	if _, err := submitReview(review, draft, author, now); !errors.Is(err, ErrReviewTransition) {
+		t.Errorf("submitting content in review error = %v, want ErrReviewTransition", err)
+	}
+	if _, err := submitReview(nil, &Content{ID: uuid.New()}, author, now); !errors.Is(err, ErrReviewTransition) {
+		t.Errorf("submitting published content error = %v, want ErrReviewTransition", err)
+	}
+
+	if _, err := decideReview(review, ReviewApproved, author, "", now); !errors.Is(err, ErrNotReviewer) {
+		t.Errorf("approval by the submitter error = %v, want ErrNotReviewer", err)
+	}
+	if _, err := assignReview(review, author, now); !errors.Is(err, ErrNotReviewer) {
+		t.Errorf("assigning the submitter error = %v, want ErrNotReviewer", err)
+	}
+	review, err = assignReview(review, reviewer, now)
+	if err != nil || review.ReviewerID != reviewer {
+		t.Fatalf("assignReview() = %+v, %v", review, err)
+	}
+	if _, err := decideReview(review, ReviewApproved, uuid.New(), "", now); !errors.Is(err, ErrNotReviewer) {
+		t.Errorf("approval by another reviewer error = %v, want ErrNotReviewer", err)
+	}
+
+	if _, err := decideReview(review, ReviewChangesRequested, reviewer, "  ", now); !errors.Is(err, ErrReviewComment) {
+		t.Errorf("requesting changes without a comment error = %v, want ErrReviewComment", err)
+	}
+	sentBack, err := decideReview(review, ReviewChangesRequested, reviewer, "Shorten the intro", now)
+	if err != nil || sentBack.Status != ReviewChangesRequested || sentBack.ReviewedAt == nil {
+		t.Fatalf("decideReview() = %+v, %v", sentBack, err)
+	}
+
+	for name, fn := range map[string]func() error{
+		"assign":  func() error { _, err := assignReview(sentBack, reviewer, now); return err },
+		"approve": func() error { _, err := decideReview(sentBack, ReviewApproved, reviewer, "", now); return err },
+		"decide without a review": func() error {
+			_, err := decideReview(nil, ReviewApproved, reviewer, "", now)
+			return err
+		},
+	} {
+		if err := fn(); !errors.Is(err, ErrReviewTransition) {
+			t.Errorf("%s out of review error = %v, want ErrReviewTransition", name, err)
+		}
+	}
+
+	again, err := submitReview(sentBack, draft, author, now)
+	if err != nil || again.ReviewerID != reviewer || again.Comment != "" || again.ReviewedAt != nil {
+		t.Errorf("resubmitting = %+v, %v; want a new round with the same reviewer", again, err)
+	}
+
+	if reopenReview(sentBack, now) != nil {
+		t.Errorf("reopenReview() of content sent back, want it unchanged")
+	}
+	approved, err := decideReview(again, ReviewApproved, reviewer, "Fine", now)
+	if err != nil {
+		t.Fatalf("decideReview() error = %v", err)
+	}
+	reopened := reopenReview(approved, now)
+	if reopened == nil || reopened.Status != ReviewInReview || reopened.ReviewerID != reviewer || reopened.ReviewedAt != nil {
+		t.Errorf("reopenReview() = %+v, want it in review with the same reviewer", reopened)
+	}
+}
+
+func TestWithoutUnapproved(t *testing.T) {
+	old := &Content{Heading: "Old"}
+	approved := &Content{Heading: "Approved", ReviewStatus: ReviewApproved}
+	bypassed := &Content{Heading: "Bypassed", ReviewStatus: ReviewInReview}
+	draft := &Content{Heading: "Draft", Draft: true, ReviewStatus: ReviewChangesRequested}
+	contents := []*Content{old, approved, bypassed, draft}
+
+	if got, warnings := withoutUnapproved(contents, map[string]string{}); len(got) != 4 || warnings != nil {
+		t.Errorf("withoutUnapproved() without the policy = %d items, %v; want all of them", len(got), warnings)
+	}
+	got, warnings := withoutUnapproved(contents, map[string]string{ReviewRequiredRefKey: "true"})
+	if len(got) != 3 || got[0] != old || got[1] != approved || got[2] != draft {
+		t.Errorf("withoutUnapproved() = %v, want all but the unapproved published content", got)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("withoutUnapproved() warnings = %v, want one", warnings)
+	}
+}
+
+func TestPublishRequiresApproval(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Review", "review")
+	section := NewSection(site.ID, "Blog", "", "blog")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatal(err)
+	}
+	author := createReviewTestUser(t, db, "author", "viewer")
+	reviewer := createReviewTestUser(t, db, "reviewer", "editor")
+
+	publish := func(c *Content) error {
+		c.Draft = false
+		err := svc.UpdateContent(ctx, c)
+		c.Draft = true
+		return err
+	}
+
+	content := NewContent(site.ID, section.ID, "Hello", "Body")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	if err := publish(content); err != nil {
+		t.Fatalf("publishing without the policy error = %v", err)
+	}
+
+	setting := NewSetting(site.ID, "Require review", "true")
+	setting.RefKey = ReviewRequiredRefKey
+	if err := svc.CreateSetting(ctx, setting); err != nil {
+		t.Fatal(err)
+	}
+
+	content = NewContent(site.ID, section.ID, "Draft", "Body")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	if err := publish(content); !errors.Is(err, ErrNotApproved) {
+		t.Fatalf("publishing an unreviewed draft error = %v, want ErrNotApproved", err)
+	}
+	direct := NewContent(site.ID, section.ID, "Direct", "Body")
+	direct.Draft = false
+	if err := svc.CreateContent(ctx, direct); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("creating published content error = %v, want ErrNotApproved", err)
+	}
+
+	if _, err := svc.SubmitForReview(ctx, content.ID, author); err != nil {
+		t.Fatalf("SubmitForReview() error = %v", err)
+	}
+	if _, err := svc.AssignReviewer(ctx, content.ID, author); !errors.Is(err, ErrNotReviewer) {
+		t.Errorf("assigning a viewer error = %v, want ErrNotReviewer", err)
+	}
+	if _, err := svc.AssignReviewer(ctx, content.ID, reviewer); err != nil {
+		t.Fatalf("AssignReviewer() error = %v", err)
+	}
+	if err := publish(content); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("publishing content in review error = %v, want ErrNotApproved", err)
+	}
+
+	queue, err := svc.ListReviewQueue(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("ListReviewQueue() error = %v", err)
+	}
+	if len(queue) != 1 || queue[0].ContentHeading != "Draft" || queue[0].ReviewerName != "reviewer" || queue[0].SubmitterName != "author" {
+		t.Fatalf("ListReviewQueue() = %+v, want the draft assigned to reviewer", queue)
+	}
+
+	if _, err := svc.RequestChanges(ctx, content.ID, reviewer, "Add a conclusion"); err != nil {
+		t.Fatalf("RequestChanges() error = %v", err)
+	}
+	if err := publish(content); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("publishing content sent back error = %v, want ErrNotApproved", err)
+	}
+
+	if _, err := svc.SubmitForReview(ctx, content.ID, author); err != nil {
+		t.Fatalf("SubmitForReview() again error = %v", err)
+	}
+	if _, err := svc.ApproveContent(ctx, content.ID, author, ""); !errors.Is(err, ErrNotReviewer) {
+		t.Errorf("approval by a viewer error = %v, want ErrNotReviewer", err)
+	}
+	review, err := svc.ApproveContent(ctx, content.ID, reviewer, "Good to go")
+	if err != nil || !review.Approved() {
+		t.Fatalf("ApproveContent() = %+v, %v", review, err)
+	}
+
+	// Publishing along with an edit fails and leaves the approval alone.
+	content.Body = "Body edited while publishing"
+	if err := publish(content); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("publishing with an edit error = %v, want ErrNotApproved", err)
+	}
+	if review, err := svc.GetContentReview(ctx, content.ID); err != nil || !review.Approved() {
+		t.Fatalf("review after a failed update = %+v, %v; want it still approved", review, err)
+	}
+
+	content.Body = "Body edited after the approval"
+	if err := svc.UpdateContent(ctx, content); err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+	if review, err := svc.GetContentReview(ctx, content.ID); err != nil || review.Status != ReviewInReview {
+		t.Fatalf("review after the edit = %+v, %v; want it back in review", review, err)
+	}
+	if err := publish(content); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("publishing content edited after the approval error = %v, want ErrNotApproved", err)
+	}
+	if _, err := svc.ApproveContent(ctx, content.ID, reviewer, ""); err != nil {
+		t.Fatalf("ApproveContent() again error = %v", err)
+	}
+	if err := publish(content); err != nil {
+		t.Fatalf("publishing approved content error = %v", err)
+	}
+
+	if queue, _ := svc.ListReviewQueue(ctx, site.ID); len(queue) != 0 {
+		t.Errorf("review queue still lists %d items after approval", len(queue))
+	}
+
+	// Content published around the approval step, here by a direct write,
+	// is left out of the site, and so is its copy in a cloned site.
+	direct.Draft = true
+	if err := svc.CreateContent(ctx, direct); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SubmitForReview(ctx, direct.ID, author); err != nil {
+		t.Fatalf("SubmitForReview() error = %v", err)
+	}
+	if _, err := db.Exec("UPDATE content SET draft = 0 WHERE id = ?", direct.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := svc.CloneSite(ctx, site.ID, "Review copy", "review-copy", CloneOptions{Content: true})
+	if err != nil {
+		t.Fatalf("CloneSite() error = %v", err)
+	}
+	for _, siteID := range []uuid.UUID{site.ID, clone.ID} {
+		contents, err := svc.GetAllContentWithMeta(ctx, siteID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		settings, _ := svc.GetSettings(ctx, siteID)
+		kept, _ := withoutUnapproved(contents, withDefaultTimezone(settings, ""))
+		for _, c := range kept {
+			if c.Heading == "Direct" {
+				t.Errorf("content published around the approval step is kept in site %s", siteID)
+			}
+		}
+		if len(kept) != 2 {
+			t.Errorf("site %s keeps %d items, want the old and the approved content", siteID, len(kept))
+		}
+	}
+}
+
+func TestFindAndReplaceReopensReview(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Review", "review")
+	section := NewSection(site.ID, "Blog", "", "blog")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatal(err)
+	}
+	author := createReviewTestUser(t, db, "author", "viewer")
+	reviewer := createReviewTestUser(t, db, "reviewer", "editor")
+
+	approved := NewContent(site.ID, section.ID, "Approved", "Old name inside")
+	published := NewContent(site.ID, section.ID, "Published", "Old name inside")
+	published.Draft = false
+	for _, c := range []*Content{approved, published} {
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.SubmitForReview(ctx, approved.ID, author); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ApproveContent(ctx, approved.ID, reviewer, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.FindAndReplace(ctx, site.ID, "Old name", "New name", FindReplaceOptions{Apply: true})
+	if err != nil || result.Changed != 2 {
+		t.Fatalf("FindAndReplace() = %+v, %v; want both items changed", result, err)
+	}
+	if review, err := svc.GetContentReview(ctx, approved.ID); err != nil || review.Status != ReviewInReview {
+		t.Errorf("review of the approved draft = %+v, %v; want it back in review", review, err)
+	}
+	if _, err := svc.GetContentReview(ctx, published.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("review of published content error = %v, want none", err)
+	}
+}
+
+func createReviewTestUser(t *testing.T, db *sql.DB, name, roles string) uuid.UUID {
+	t.Helper()
+	id := uuid.New()
+	if _, err := db.Exec(`INSERT INTO user (id, short_id, email, password_hash, name, status, roles, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		id.String(), id.String()[:8], name+"@test.com", "hash", name, "active", roles); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	return id
+}
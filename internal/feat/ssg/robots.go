@@ -0,0 +1,88 @@
+package ssg
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Setting ref keys for search engine indexing.
+const (
+	// NoIndexRefKey keeps the whole site out of search engines, for staging
+	// copies. It overrides every per-content robots value.
+	NoIndexRefKey = "ssg.site.noindex"
+	// RobotsDefaultRefKey is the robots meta value for content that does not
+	// set its own.
+	RobotsDefaultRefKey = "ssg.robots.default"
+)
+
+// defaultRobots is what crawlers assume without a robots meta tag, so pages
+// with this value get none.
+const defaultRobots = "index, follow"
+
+// robotsValues are the robots meta values content can set. Empty falls back
+// to the site default.
+var robotsValues = []string{"", defaultRobots, "noindex", "nofollow", "noindex, nofollow"}
+
+var ErrInvalidRobots = errors.New("unknown robots value")
+
+// disallowAllRobotsTxt replaces the configured robots.txt on noindex sites.
+const disallowAllRobotsTxt = "User-agent: *\nDisallow: /\n"
+
+func siteNoIndex(params map[string]string) bool {
+	return params[NoIndexRefKey] == "true"
+}
+
+// Robots returns the robots meta value for the page, or "" for none. The site
+// noindex switch wins, then the content's robots value, see contentRobots,
+// and finally the site default.
+func (d SSGPageData) Robots() string {
+	if siteNoIndex(d.Params) {
+		return "noindex"
+	}
+	value := strings.TrimSpace(d.Params[RobotsDefaultRefKey])
+	if d.Content != nil && d.Content.Content != nil {
+		value = contentRobots(d.Content.Content, d.Params)
+	}
+	if value == defaultRobots {
+		return ""
+	}
+	return value
+}
+
+// contentRobots returns the robots value of content: noindex when it is
+// unlisted, which is never indexed, then its own value and then the site
+// default.
+func contentRobots(c *Content, params map[string]string) string {
+	if c.Visibility == VisibilityUnlisted {
+		return "noindex"
+	}
+	if c.Meta != nil && strings.TrimSpace(c.Meta.Robots) != "" {
+		return strings.TrimSpace(c.Meta.Robots)
+	}
+	return strings.TrimSpace(params[RobotsDefaultRefKey])
+}
+
+// robotsNoIndex reports whether a robots value keeps the page out of search
+// engines.
+func robotsNoIndex(value string) bool {
+	for _, directive := range strings.Split(value, ",") {
+		if d := strings.ToLower(strings.TrimSpace(directive)); d == "noindex" || d == "none" {
+			return true
+		}
+	}
+	return false
+}
+
+func validRobots(value string) bool {
+	return slices.Contains(robotsValues, value)
+}
+
+// noIndexWarning reminds that the site is hidden from search engines, so the
+// switch is not left on in production by accident.
+func noIndexWarning(params map[string]string) []string {
+	if !siteNoIndex(params) {
+		return nil
+	}
+	return []string{"No index is on: every page is marked noindex and robots.txt disallows all crawlers. Turn it off before publishing to production"}
+}
@@ -0,0 +1,143 @@
+package ssg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPageDataRobots(t *testing.T) {
+	page := func(visibility, robots string) *RenderedContent {
+		return &RenderedContent{Content: &Content{Visibility: visibility, Meta: &Meta{Robots: robots}}}
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		content *RenderedContent
+		want    string
+	}{
+		{"no settings", map[string]string{}, page(VisibilityPublic, ""), ""},
+		{"index page", map[string]string{RobotsDefaultRefKey: "nofollow"}, nil, "nofollow"},
+		{"site default", map[string]string{RobotsDefaultRefKey: "nofollow"}, page(VisibilityPublic, ""), "nofollow"},
+		{"default index, follow emits nothing", map[string]string{RobotsDefaultRefKey: "index, follow"}, page(VisibilityPublic, ""), ""},
+		{"content overrides default", map[string]string{RobotsDefaultRefKey: "noindex"}, page(VisibilityPublic, "index, follow"), ""},
+		{"content value", map[string]string{}, page(VisibilityPublic, "noindex, nofollow"), "noindex, nofollow"},
+		{"unlisted overrides content", map[string]string{}, page(VisibilityUnlisted, "index, follow"), "noindex"},
+		{"site noindex overrides content", map[string]string{NoIndexRefKey: "true"}, page(VisibilityPublic, "index, follow"), "noindex"},
+		{"site noindex on index pages", map[string]string{NoIndexRefKey: "true"}, nil, "noindex"},
+		{"site noindex off", map[string]string{NoIndexRefKey: "false"}, page(VisibilityPublic, "nofollow"), "nofollow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := SSGPageData{Params: tt.params, Content: tt.content}
+			if got := d.Robots(); got != tt.want {
+				t.Errorf("Robots() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateRobotsTxtNoIndex(t *testing.T) {
+	g := &HTMLGenerator{}
+	htmlPath := t.TempDir()
+	if err := g.generateRobotsTxt(htmlPath, disallowAllRobotsTxt, ""); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(htmlPath, "robots.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "User-agent: *\nDisallow: /\n" {
+		t.Errorf("robots.txt = %q", data)
+	}
+	if len(noIndexWarning(map[string]string{NoIndexRefKey: "true"})) != 1 || noIndexWarning(map[string]string{}) != nil {
+		t.Error("noindex sites should get a generation warning, others none")
+	}
+}
+
+func TestSetContentRobotsNoIndex(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Robots", "robots")
+	section := NewSection(site.ID, "Blog", "", "blog")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatal(err)
+	}
+	publish := func(heading string) *Content {
+		c := NewContent(site.ID, section.ID, heading, "Body")
+		c.Draft = false
+		at := time.Now().Add(-time.Hour)
+		c.PublishedAt = &at
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	hidden, shown := publish("Hidden"), publish("Shown")
+	other := createTestSite(t, svc, "Other", "other")
+
+	userID := uuid.New()
+	if _, err := svc.SetContentRobots(ctx, site.ID, []uuid.UUID{hidden.ID}, "noindex please", userID); !errors.Is(err, ErrInvalidRobots) {
+		t.Errorf("unknown robots value error = %v, want ErrInvalidRobots", err)
+	}
+	if n, err := svc.SetContentRobots(ctx, other.ID, []uuid.UUID{hidden.ID}, "noindex", userID); err != nil || n != 0 {
+		t.Errorf("SetContentRobots() from another site = %d, %v; want nothing changed", n, err)
+	}
+	n, err := svc.SetContentRobots(ctx, site.ID, []uuid.UUID{hidden.ID}, "noindex", userID)
+	if err != nil || n != 1 {
+		t.Fatalf("SetContentRobots() = %d, %v; want 1 changed", n, err)
+	}
+	if n, _ := svc.SetContentRobots(ctx, site.ID, []uuid.UUID{hidden.ID}, "noindex", userID); n != 0 {
+		t.Errorf("SetContentRobots() again = %d, want 0 changed", n)
+	}
+
+	contents, err := svc.GetAllContentWithMeta(ctx, site.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[uuid.UUID]*Content)
+	for _, c := range contents {
+		byID[c.ID] = c
+	}
+	if !byID[hidden.ID].Meta.NoIndex() || byID[shown.ID].Meta.NoIndex() {
+		t.Fatalf("noindex = %v and %v, want only the toggled item", byID[hidden.ID].Meta.NoIndex(), byID[shown.ID].Meta.NoIndex())
+	}
+
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	layout := &Layout{Code: `{{ with .Robots }}<meta name="robots" content="{{ . }}">{{ end }}`}
+	sections := []*Section{section}
+	out, err := g.PreviewLayout(site, layout, byID[hidden.ID], contents, sections, nil)
+	if err != nil {
+		t.Fatalf("PreviewLayout() error = %v", err)
+	}
+	if want := `<meta name="robots" content="noindex">`; !strings.Contains(string(out), want) {
+		t.Errorf("page = %q, want it to contain %q", out, want)
+	}
+	if out, _ := g.PreviewLayout(site, layout, byID[shown.ID], contents, sections, nil); strings.Contains(string(out), "robots") {
+		t.Errorf("page of indexed content = %q, want no robots meta", out)
+	}
+
+	var paths []string
+	for _, e := range g.sitemapEntries("/", contents, sections, map[string]string{}, time.Now()) {
+		paths = append(paths, e.Path)
+	}
+	sitemap := strings.Join(paths, " ")
+	if !strings.Contains(sitemap, "/blog/shown") || strings.Contains(sitemap, "/blog/hidden") {
+		t.Errorf("sitemap = %v, want the indexed item only", paths)
+	}
+
+	// The site default applies to index pages and content without a value.
+	noindexDefault := map[string]string{RobotsDefaultRefKey: "noindex"}
+	if entries := g.sitemapEntries("/", contents, sections, noindexDefault, time.Now()); len(entries) != 0 {
+		t.Errorf("sitemap with a noindex default = %+v, want no pages", entries)
+	}
+}
@@ -3,13 +3,20 @@ package ssg
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/cliossg/clio/pkg/cl/logger"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
 )
 
+// CronPublishRefKey is the per-site param holding a cron expression for
+// recurring generate-and-publish runs. Sites without it are not scheduled.
+const CronPublishRefKey = "ssg.publish.cron"
+
 type Scheduler struct {
 	service   Service
 	htmlGen   *HTMLGenerator
@@ -18,6 +25,17 @@ type Scheduler struct {
 	stop      chan struct{}
 	mu        sync.Mutex
 	running   bool
+
+	ctx      context.Context
+	cron     *cron.Cron
+	cronJobs map[uuid.UUID]cronJob
+	busyMu   sync.Mutex
+	busy     map[uuid.UUID]bool
+}
+
+type cronJob struct {
+	spec string
+	id   cron.EntryID
 }
 
 func NewScheduler(service Service, htmlGen *HTMLGenerator, publisher *Publisher, log logger.Logger) *Scheduler {
@@ -26,16 +44,36 @@ func NewScheduler(service Service, htmlGen *HTMLGenerator, publisher *Publisher,
 		htmlGen:   htmlGen,
 		publisher: publisher,
 		log:       log,
+		cronJobs:  make(map[uuid.UUID]cronJob),
+		busy:      make(map[uuid.UUID]bool),
 	}
 }
 
+// ValidateCronSpec checks a standard five-field cron expression (or a
+// descriptor such as @daily). An empty spec is valid and disables cron runs.
+func ValidateCronSpec(spec string) error {
+	if strings.TrimSpace(spec) == "" {
+		return nil
+	}
+	if _, err := cron.ParseStandard(strings.TrimSpace(spec)); err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
+	return nil
+}
+
 func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
 	sites, err := s.service.ListSites(ctx)
 	if err != nil {
 		s.log.Errorf("Scheduler: cannot list sites: %v", err)
 		return nil
 	}
 
+	s.reloadCron(ctx, ctx, sites)
+
 	for _, site := range sites {
 		enabled, _ := s.service.GetSettingByRefKey(ctx, site.ID, "ssg.scheduled.publish.enabled")
 		if enabled != nil && enabled.Value == "true" {
@@ -72,9 +110,151 @@ func (s *Scheduler) Stop(_ context.Context) error {
 		s.running = false
 		s.log.Info("Scheduler: stopped")
 	}
+	s.stopCron()
 	return nil
 }
 
+// Reload re-reads the ssg.publish.cron param of every site and reschedules
+// cron runs accordingly, so changes take effect without a restart.
+func (s *Scheduler) Reload(ctx context.Context) error {
+	sites, err := s.service.ListSites(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot list sites: %w", err)
+	}
+
+	s.mu.Lock()
+	baseCtx := s.ctx
+	s.mu.Unlock()
+	if baseCtx == nil {
+		baseCtx = context.Background()
+	}
+
+	// Settings are read with the caller's context, but jobs outlive it.
+	s.reloadCron(ctx, baseCtx, sites)
+	return nil
+}
+
+// reloadCron syncs the registered cron jobs with the sites' cron params.
+// Settings are read with ctx; scheduled runs use jobCtx.
+func (s *Scheduler) reloadCron(ctx, jobCtx context.Context, sites []*Site) {
+	wanted := make(map[uuid.UUID]string)
+	for _, site := range sites {
+		setting, _ := s.service.GetSettingByRefKey(ctx, site.ID, CronPublishRefKey)
+		if setting == nil {
+			continue
+		}
+		spec := strings.TrimSpace(setting.Value)
+		if spec == "" {
+			continue
+		}
+		if err := ValidateCronSpec(spec); err != nil {
+			s.log.Errorf("Scheduler: site %s: %v", site.Slug, err)
+			continue
+		}
+		wanted[site.ID] = spec
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for siteID, job := range s.cronJobs {
+		if wanted[siteID] != job.spec {
+			s.cron.Remove(job.id)
+			delete(s.cronJobs, siteID)
+		}
+	}
+
+	for siteID, spec := range wanted {
+		if _, ok := s.cronJobs[siteID]; ok {
+			continue
+		}
+		if s.cron == nil {
+			s.cron = cron.New()
+			s.cron.Start()
+		}
+		id := siteID
+		entryID, err := s.cron.AddFunc(spec, func() { s.runCron(jobCtx, id) })
+		if err != nil {
+			s.log.Errorf("Scheduler: cannot schedule site %s: %v", id, err)
+			continue
+		}
+		s.cronJobs[siteID] = cronJob{spec: spec, id: entryID}
+		s.log.Infof("Scheduler: site %s scheduled with cron %q", id, spec)
+	}
+
+	if len(s.cronJobs) == 0 {
+		s.stopCron()
+	}
+}
+
+// CronSchedules returns the active cron expression of every scheduled site.
+func (s *Scheduler) CronSchedules() map[uuid.UUID]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules := make(map[uuid.UUID]string, len(s.cronJobs))
+	for siteID, job := range s.cronJobs {
+		schedules[siteID] = job.spec
+	}
+	return schedules
+}
+
+// stopCron stops the cron runner. Callers must hold s.mu.
+func (s *Scheduler) stopCron() {
+	if s.cron == nil {
+		return
+	}
+	s.cron.Stop()
+	s.cron = nil
+	s.cronJobs = make(map[uuid.UUID]cronJob)
+}
+
+// acquire marks a site as being published. It returns false if a previous
+// run for the same site is still in progress.
+func (s *Scheduler) acquire(siteID uuid.UUID) bool {
+	s.busyMu.Lock()
+	defer s.busyMu.Unlock()
+	if s.busy[siteID] {
+		return false
+	}
+	s.busy[siteID] = true
+	return true
+}
+
+func (s *Scheduler) release(siteID uuid.UUID) {
+	s.busyMu.Lock()
+	defer s.busyMu.Unlock()
+	delete(s.busy, siteID)
+}
+
+// runCron generates and publishes a site on its cron schedule.
+func (s *Scheduler) runCron(ctx context.Context, siteID uuid.UUID) {
+	if !s.acquire(siteID) {
+		s.log.Infof("Scheduler: previous run for site %s still in progress, skipping", siteID)
+		return
+	}
+	defer s.release(siteID)
+
+	site, err := s.service.GetSite(ctx, siteID)
+	if err != nil || site == nil {
+		s.log.Errorf("Scheduler: cron run cannot load site %s: %v", siteID, err)
+		return
+	}
+
+	contents, err := s.service.GetAllContentWithMeta(ctx, site.ID)
+	if err != nil {
+		s.log.Errorf("Scheduler: cannot get content for site %s: %v", site.Slug, err)
+		return
+	}
+
+	started := time.Now()
+	if err := s.publishSite(ctx, site, contents); err != nil {
+		s.log.Errorf("Scheduler: cron run for site %s failed after %s: %v", site.Slug, time.Since(started).Round(time.Millisecond), err)
+		return
+	}
+	s.log.Infof("Scheduler: cron run for site %s completed in %s", site.Slug, time.Since(started).Round(time.Millisecond))
+}
+
 func (s *Scheduler) run(ctx context.Context, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -119,47 +299,24 @@ func (s *Scheduler) checkAndPublish(ctx context.Context, site *Site) {
 		return
 	}
 
-	s.log.Infof("Scheduler: pending content found for site %s, publishing", site.Slug)
-
-	sections, err := s.service.GetSections(ctx, site.ID)
-	if err != nil {
-		s.log.Errorf("Scheduler: cannot get sections for site %s: %v", site.Slug, err)
+	if !s.acquire(site.ID) {
+		s.log.Infof("Scheduler: previous run for site %s still in progress, skipping", site.Slug)
 		return
 	}
+	defer s.release(site.ID)
 
-	layouts, _ := s.service.GetLayouts(ctx, site.ID)
-	if layouts == nil {
-		layouts = []*Layout{}
-	}
-
-	settings, _ := s.service.GetSettings(ctx, site.ID)
-	if settings == nil {
-		settings = []*Setting{}
-	}
-
-	contributors, _ := s.service.GetContributors(ctx, site.ID)
-	if contributors == nil {
-		contributors = []*Contributor{}
-	}
-
-	userAuthors := s.service.BuildUserAuthorsMap(ctx, contents, contributors)
-
-	_, err = s.htmlGen.GenerateHTML(ctx, site, contents, sections, layouts, settings, contributors, userAuthors)
-	if err != nil {
-		s.log.Errorf("Scheduler: HTML generation failed for site %s: %v", site.Slug, err)
-		return
-	}
+	s.log.Infof("Scheduler: pending content found for site %s, publishing", site.Slug)
 
-	cfg, err := buildPublishConfigFromSettings(settings)
-	if err != nil {
-		s.log.Errorf("Scheduler: cannot build publish config for site %s: %v", site.Slug, err)
-		return
+	if err := s.publishSite(ctx, site, contents); err != nil {
+		s.log.Errorf("Scheduler: %v", err)
 	}
+}
 
-	result, err := s.publisher.Publish(ctx, cfg, site.Slug)
+// publishSite regenerates the site HTML, publishes it and records the publish time.
+func (s *Scheduler) publishSite(ctx context.Context, site *Site, contents []*Content) error {
+	result, err := generateAndPublish(ctx, publishDeps{s.service, s.htmlGen, s.publisher, s.log}, site, contents, nil)
 	if err != nil {
-		s.log.Errorf("Scheduler: publish failed for site %s: %v", site.Slug, err)
-		return
+		return err
 	}
 
 	if result.NoChanges {
@@ -167,10 +324,7 @@ func (s *Scheduler) checkAndPublish(ctx context.Context, site *Site) {
 	} else {
 		s.log.Infof("Scheduler: published site %s: %s", site.Slug, result.CommitURL)
 	}
-
-	now := time.Now()
-	site.LastPublishedAt = &now
-	_ = s.service.UpdateSite(ctx, site)
+	return nil
 }
 
 var errPublishNotConfigured = errors.New("publish not configured")
@@ -214,6 +368,8 @@ func buildPublishConfigFromSettings(settings []*Setting) (PublishConfig, error)
 		CommitName:  commitName,
 		CommitEmail: commitEmail,
 		UseSSH:      useSSH,
+		SSH:         SSHOptionsFromSettings(settings),
+		Signing:     SigningFromSettings(settings),
 	}, nil
 }
 
@@ -228,3 +228,101 @@ func TestSchedulerStartOnlyFirstEnabled(t *testing.T) {
 		t.Fatalf("unexpected stop error: %v", err)
 	}
 }
+
+func TestSchedulerCronOptInPerSite(t *testing.T) {
+	svc := fake.NewService()
+	siteA := uuid.New()
+	siteB := uuid.New()
+	siteC := uuid.New()
+	svc.Sites = []*ssg.Site{
+		{ID: siteA, Slug: "nightly"},
+		{ID: siteB, Slug: "no-cron"},
+		{ID: siteC, Slug: "broken"},
+	}
+	svc.Settings = map[uuid.UUID][]*ssg.Setting{
+		siteA: {{RefKey: ssg.CronPublishRefKey, Value: "0 3 * * *"}},
+		siteB: {{RefKey: ssg.CronPublishRefKey, Value: ""}},
+		siteC: {{RefKey: ssg.CronPublishRefKey, Value: "not a cron"}},
+	}
+
+	s := ssg.NewScheduler(svc, nil, nil, newTestLogger())
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	defer s.Stop(context.Background())
+
+	schedules := s.CronSchedules()
+	if len(schedules) != 1 || schedules[siteA] != "0 3 * * *" {
+		t.Errorf("schedules = %v, want only site A", schedules)
+	}
+}
+
+func TestSchedulerReloadPicksUpChanges(t *testing.T) {
+	svc := fake.NewService()
+	siteID := uuid.New()
+	svc.Sites = []*ssg.Site{{ID: siteID, Slug: "test"}}
+
+	s := ssg.NewScheduler(svc, nil, nil, newTestLogger())
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	defer s.Stop(context.Background())
+
+	if len(s.CronSchedules()) != 0 {
+		t.Fatalf("expected no schedules without a cron param")
+	}
+
+	svc.Settings[siteID] = []*ssg.Setting{{RefKey: ssg.CronPublishRefKey, Value: "@daily"}}
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := s.CronSchedules()[siteID]; got != "@daily" {
+		t.Errorf("after add: schedule = %q, want @daily", got)
+	}
+
+	svc.Settings[siteID][0].Value = "30 1 * * 1"
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := s.CronSchedules()[siteID]; got != "30 1 * * 1" {
+		t.Errorf("after change: schedule = %q, want 30 1 * * 1", got)
+	}
+
+	svc.Settings[siteID][0].Value = ""
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(s.CronSchedules()) != 0 {
+		t.Errorf("after clear: expected no schedules, got %v", s.CronSchedules())
+	}
+}
+
+func TestSchedulerReloadListSitesError(t *testing.T) {
+	svc := fake.NewService()
+	svc.ListSitesErr = errors.New("db down")
+	s := ssg.NewScheduler(svc, nil, nil, newTestLogger())
+
+	if err := s.Reload(context.Background()); err == nil {
+		t.Error("expected error when sites cannot be listed")
+	}
+}
+
+func TestSchedulerStopClearsCron(t *testing.T) {
+	svc := fake.NewService()
+	siteID := uuid.New()
+	svc.Sites = []*ssg.Site{{ID: siteID, Slug: "test"}}
+	svc.Settings = map[uuid.UUID][]*ssg.Setting{
+		siteID: {{RefKey: ssg.CronPublishRefKey, Value: "@hourly"}},
+	}
+
+	s := ssg.NewScheduler(svc, nil, nil, newTestLogger())
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("unexpected stop error: %v", err)
+	}
+	if len(s.CronSchedules()) != 0 {
+		t.Errorf("expected no schedules after stop, got %v", s.CronSchedules())
+	}
+}
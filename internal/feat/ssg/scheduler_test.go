@@ -3,6 +3,9 @@ package ssg
 import (
 	"testing"
 	"time"
+
+	"github.com/cliossg/clio/pkg/cl/git"
+	"github.com/google/uuid"
 )
 
 func TestIsPublishable(t *testing.T) {
@@ -160,6 +163,34 @@ func TestBuildPublishConfigFromSettings(t *testing.T) {
 				CommitName: "Clio Bot", CommitEmail: "clio@localhost", UseSSH: true,
 			},
 		},
+		{
+			name: "ssh key settings",
+			settings: []*Setting{
+				{RefKey: "ssg.publish.repo.url", Value: "git@github.com:u/r.git"},
+				{RefKey: SSHKeyPathRefKey, Value: " /keys/deploy "},
+				{RefKey: SSHPassphraseRefKey, Value: "pass"},
+				{RefKey: SSHHostKeyPolicyRefKey, Value: "accept-new"},
+			},
+			wantCfg: PublishConfig{
+				RepoURL: "git@github.com:u/r.git", Branch: "gh-pages",
+				CommitName: "Clio Bot", CommitEmail: "clio@localhost", UseSSH: true,
+				SSH: git.SSHOptions{KeyPath: "/keys/deploy", Passphrase: "pass", HostKeyPolicy: git.HostKeyAcceptNew},
+			},
+		},
+		{
+			name: "signing settings",
+			settings: []*Setting{
+				{RefKey: "ssg.publish.repo.url", Value: "git@github.com:u/r.git"},
+				{RefKey: SigningFormatRefKey, Value: "ssh"},
+				{RefKey: SigningKeyRefKey, Value: " /keys/signing "},
+				{RefKey: SigningRequiredRefKey, Value: "true"},
+			},
+			wantCfg: PublishConfig{
+				RepoURL: "git@github.com:u/r.git", Branch: "gh-pages",
+				CommitName: "Clio Bot", CommitEmail: "clio@localhost", UseSSH: true,
+				Signing: git.Signing{Format: git.SigningSSH, Key: "/keys/signing", Required: true},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -200,3 +231,49 @@ func TestSchedulerStopWhenNotRunning(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestValidateCronSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{"empty disables cron", "", false},
+		{"whitespace disables cron", "   ", false},
+		{"nightly", "0 3 * * *", false},
+		{"descriptor", "@daily", false},
+		{"every fifteen minutes", "*/15 * * * *", false},
+		{"too few fields", "0 3 *", true},
+		{"out of range", "61 * * * *", true},
+		{"garbage", "nightly please", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCronSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCronSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchedulerAcquirePreventsOverlap(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, nil)
+	siteID := uuid.New()
+
+	if !s.acquire(siteID) {
+		t.Fatal("first acquire should succeed")
+	}
+	if s.acquire(siteID) {
+		t.Error("second acquire should fail while a run is in progress")
+	}
+	if !s.acquire(uuid.New()) {
+		t.Error("other sites should not be blocked")
+	}
+
+	s.release(siteID)
+	if !s.acquire(siteID) {
+		t.Error("acquire should succeed after release")
+	}
+}
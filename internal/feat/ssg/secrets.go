@@ -0,0 +1,175 @@
+package ssg
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cliossg/clio/internal/db/sqlc"
+	"github.com/google/uuid"
+)
+
+// secretPrefix marks a setting value encrypted at rest. Secret settings
+// without it hold plaintext, stored before Clio had an encryption key.
+const secretPrefix = "enc:v1:"
+
+var (
+	ErrSecretKeyMissing = errors.New("no encryption key for secret settings: set ssg.secret_key in config.yaml or CLIO_SSG_SECRET_KEY")
+	ErrSecretDecrypt    = errors.New("cannot decrypt secret setting: the encryption key changed or the value is corrupt")
+)
+
+// secretRefKeys are the settings that hold credentials. They are always
+// secret, whatever the form they are saved from says.
+var secretRefKeys = map[string]bool{
+	"ssg.publish.auth.token": true,
+	"ssg.backup.auth.token":  true,
+	SSHKeyRefKey:             true,
+	SSHPassphraseRefKey:      true,
+	SigningKeyRefKey:         true, // can hold an SSH private key
+}
+
+// isSecretError reports whether err comes from a secret setting that
+// cannot be read.
+func isSecretError(err error) bool {
+	return errors.Is(err, ErrSecretKeyMissing) || errors.Is(err, ErrSecretDecrypt)
+}
+
+// CheckSecrets returns the error of the first credential setting of the site
+// that cannot be decrypted. GetSettings reads those as empty, which would
+// publish without credentials rather than fail.
+func CheckSecrets(ctx context.Context, svc Service, siteID uuid.UUID) error {
+	for refKey := range secretRefKeys {
+		if _, err := svc.GetSettingByRefKey(ctx, siteID, refKey); isSecretError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretCipher returns the AES-256-GCM cipher keyed by the configured
+// encryption key, which can be any string.
+func secretCipher(key string) (cipher.AEAD, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, ErrSecretKeyMissing
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func isEncrypted(value string) bool {
+	return strings.HasPrefix(value, secretPrefix)
+}
+
+func encryptSecret(key, plaintext string) (string, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("cannot generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return secretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key, value string) (string, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, secretPrefix))
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", ErrSecretDecrypt
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrSecretDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// storedValue returns the value of param as it goes into the database,
+// encrypted when the setting is secret. Saving a secret without a key
+// fails rather than falling back to plaintext.
+func (s *service) storedValue(param *Setting) (string, error) {
+	if secretRefKeys[param.RefKey] {
+		param.Secret = true
+	}
+	if !param.Secret || param.Value == "" {
+		return param.Value, nil
+	}
+	return encryptSecret(s.cfg.SSG.SecretKey, param.Value)
+}
+
+// openSetting decrypts the value of a secret setting in place. Errors
+// name the setting and wrap ErrSecretKeyMissing or ErrSecretDecrypt.
+func (s *service) openSetting(param *Setting) error {
+	if !param.Secret || !isEncrypted(param.Value) {
+		return nil
+	}
+	value, err := decryptSecret(s.cfg.SSG.SecretKey, param.Value)
+	if err != nil {
+		name := param.RefKey
+		if name == "" {
+			name = param.Name
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	param.Value = value
+	return nil
+}
+
+// encryptSecretSettings encrypts the secret settings of every site that are
+// still stored in plaintext, such as the credentials marked secret by the
+// migration that introduced encryption. Without a key it only warns.
+func (s *service) encryptSecretSettings(ctx context.Context) error {
+	s.ensureQueries()
+	if s.queries == nil {
+		return nil
+	}
+
+	settings, err := s.queries.GetSecretSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get secret settings: %w", err)
+	}
+	var plain []sqlc.Setting
+	for _, p := range settings {
+		if !isEncrypted(p.Value.String) {
+			plain = append(plain, p)
+		}
+	}
+	if len(plain) == 0 {
+		return nil
+	}
+	if strings.TrimSpace(s.cfg.SSG.SecretKey) == "" {
+		s.log.Infof("%d secret settings are stored in plaintext. Set ssg.secret_key to encrypt them", len(plain))
+		return nil
+	}
+
+	for _, p := range plain {
+		value, err := encryptSecret(s.cfg.SSG.SecretKey, p.Value.String)
+		if err != nil {
+			return fmt.Errorf("cannot encrypt setting %s: %w", p.Name, err)
+		}
+		err = s.queries.UpdateSettingValue(ctx, sqlc.UpdateSettingValueParams{
+			Value: nullString(value),
+			ID:    p.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot update setting %s: %w", p.Name, err)
+		}
+	}
+	s.log.Infof("Encrypted %d secret settings", len(plain))
+	return nil
+}
@@ -164,36 +164,107 @@ func (s *Seeder) seedDefaultParams(ctx context.Context, siteID uuid.UUID) error
 		// Site
 		{"Site description", "Site description shown in hero and meta", "A personal blog about coding, essays, and food", "site_description", "site", 1, true, SettingTypeText, ""},
 		{"Hero image", "Hero image filename", "", "hero_image", "site", 2, true, SettingTypeString, ""},
-		{"Site base path", "Base path for GitHub Pages subpath hosting", "/", "ssg.site.base_path", "site", 3, true, SettingTypeString, ""},
-		{"Site base URL", "Full base URL for the site (e.g. https://example.com)", "https://example.com", "ssg.site.base_url", "site", 4, true, SettingTypeString, ""},
-		// Display
-		{"Index max items", "Maximum items shown on index pages", "9", "ssg.index.maxitems", "display", 1, true, SettingTypeInteger, `{"min":1,"max":100}`},
-		{"Blocks enabled", "Enable related content blocks", "true", "ssg.blocks.enabled", "display", 2, true, SettingTypeBoolean, ""},
-		{"Blocks max items", "Maximum items shown in content blocks", "5", "ssg.blocks.maxitems", "display", 3, true, SettingTypeInteger, `{"min":1,"max":20}`},
-		{"Blocks multi-section", "Show related content from other sections", "true", "ssg.blocks.multisection", "display", 4, true, SettingTypeBoolean, ""},
-		{"Blocks background color", "Background color for related content blocks", "#f0f4f8", "ssg.blocks.bgcolor", "display", 5, true, SettingTypeString, ""},
+		{"Site base path", "Base path for GitHub Pages subpath hosting", "/", BasePathRefKey, "site", 3, true, SettingTypeString, ""},
+		{"Site base URL", "Full base URL for the site (e.g. https://example.com). Used for the sitemap, canonical and other absolute URLs", "https://example.com", BaseURLRefKey, "site", 4, true, SettingTypeString, ""},
+		{"Cookie banner enabled", "Show cookie consent banner", "true", "ssg.cookie.banner.enabled", "site", 5, true, SettingTypeBoolean, ""},
+		{"Cookie banner text", "Cookie banner consent message", "This site uses cookies to improve your experience. By continuing to use this site, you accept our use of cookies.", "ssg.cookie.banner.text", "site", 6, true, SettingTypeText, ""},
+		{"Site domain", "Custom domain written to the CNAME file for GitHub Pages (e.g. blog.example.com). Defaults to the base URL host", "", DomainRefKey, "site", 7, true, SettingTypeString, ""},
+		{"Permalink pattern", "URL pattern for content pages. Tokens: :section, :slug, :year, :month, :day, :kind (e.g. /:year/:month/:slug/)", DefaultPermalinkPattern, PermalinkRefKey, "site", 8, true, SettingTypeString, ""},
+		{"Site timezone", "IANA timezone for displayed dates and scheduled publishing (e.g. Europe/Berlin). Empty uses the server default, UTC unless configured", "", TimezoneRefKey, "site", 9, true, SettingTypeString, ""},
+		{"Site language", "Language code of the site's content (e.g. en, pt-BR). Content can set its own to link translations", DefaultLanguage, LanguageRefKey, "site", 10, true, SettingTypeString, ""},
+		{"Fingerprint assets", "Add a content hash to stylesheet file names so browsers fetch them again when they change", "true", FingerprintRefKey, "site", 11, true, SettingTypeBoolean, ""},
+		{"Minify output", "Minify generated HTML, CSS and JS. Off keeps diffs in the publish repository readable", "false", MinifyRefKey, "site", 12, true, SettingTypeBoolean, ""},
+		{"Clean output", "Empty the output directory before a full build. Off only removes files the previous build generated, keeping files added by hand", "true", OutputCleanRefKey, "site", 13, true, SettingTypeBoolean, ""},
+		{"Trailing slash", "Whether page URLs end in a slash: always gives /blog/post/, never gives /blog/post. Applies to links, canonicals, feeds and the sitemap", TrailingSlashAlways, TrailingSlashRefKey, "site", 14, true, SettingTypeEnum, `{"options":["always","never"]}`},
+		{"Redirect status", "Status of the rules in the _redirects file: 301 for permanent moves, 302 for temporary ones", DefaultRedirectStatus, RedirectStatusRefKey, "site", 15, true, SettingTypeEnum, `{"options":["301","302"]}`},
+		{"Home page", "What the site root shows: the latest posts or a single page, chosen with Home page content", HomeModePosts, HomeModeRefKey, "site", 16, true, SettingTypeEnum, `{"options":["posts","page"]}`},
+		{"Home page content", "ID of the content shown at the site root when Home page is page. It is shown there with its own layout", "", HomeContentRefKey, "site", 17, true, SettingTypeString, ""},
+		// SEO
+		{"Robots.txt", "Custom robots.txt content (Sitemap URL is appended automatically)", "User-agent: *\nAllow: /\n\nUser-agent: GPTBot\nDisallow: /\n\nUser-agent: ClaudeBot\nDisallow: /\n\nUser-agent: Google-Extended\nDisallow: /", "ssg.robots.txt", "seo", 1, true, SettingTypeText, ""},
+		{"No index", "Keep the whole site out of search engines: every page gets a noindex robots tag and robots.txt disallows all crawlers. For staging sites", "false", NoIndexRefKey, "seo", 2, true, SettingTypeBoolean, ""},
+		{"Default robots", "Robots meta value for content that does not set its own", "index, follow", RobotsDefaultRefKey, "seo", 3, true, SettingTypeEnum, `{"options":["index, follow","noindex","nofollow","noindex, nofollow"]}`},
+		{"llms.txt", "Write an llms.txt at the site root listing sections and pages for AI crawlers", "false", LLMsTxtRefKey, "seo", 4, true, SettingTypeBoolean, ""},
+		{"llms.txt description", "Summary under the site name in llms.txt. Empty uses the site description", "", LLMsTxtDescriptionRefKey, "seo", 5, true, SettingTypeText, ""},
+		{"AI crawler policy", "Whether AI crawlers may use the site's content. deny lists no pages in llms.txt and disallows the whole site in ai.txt", AIPolicyAllow, LLMsTxtPolicyRefKey, "seo", 6, true, SettingTypeEnum, `{"options":["allow","deny"]}`},
+		{"AI disallowed sections", "Comma-separated section paths left out of llms.txt and disallowed in ai.txt", "", LLMsTxtDisallowRefKey, "seo", 7, true, SettingTypeString, ""},
+		{"ai.txt", "Also write the AI crawler policy to ai.txt at the site root", "false", AITxtRefKey, "seo", 8, true, SettingTypeBoolean, ""},
+		{"Sitemap max URLs", "Most URLs in sitemap.xml. Larger sites get a sitemap index pointing to sitemap-1.xml, sitemap-2.xml and so on", "50000", SitemapMaxURLsRefKey, "seo", 9, true, SettingTypeInteger, `{"min":1,"max":50000}`},
+		// Appearance
+		{"Index page size", "Items per page on index, tag and author listings", "9", "ssg.index.page_size", "appearance", 1, true, SettingTypeInteger, `{"min":1,"max":100}`},
+		{"Blocks enabled", "Enable related content blocks", "true", "ssg.blocks.enabled", "appearance", 2, true, SettingTypeBoolean, ""},
+		{"Blocks max items", "Maximum items shown in content blocks", "5", "ssg.blocks.maxitems", "appearance", 3, true, SettingTypeInteger, `{"min":1,"max":20}`},
+		{"Blocks multi-section", "Show related content from other sections", "true", "ssg.blocks.multisection", "appearance", 4, true, SettingTypeBoolean, ""},
+		{"Blocks background color", "Background color for related content blocks", "#f0f4f8", "ssg.blocks.bgcolor", "appearance", 5, true, SettingTypeString, ""},
+		{"Adjacent navigation scope", "Whether previous/next links stay within a section or span the whole site", "section", "ssg.navigation.adjacent.scope", "appearance", 6, true, SettingTypeEnum, `{"options":["section","site"]}`},
+		{"Hide authors without posts", "Skip author pages and authors index entries for contributors with no published content", "false", hideEmptyAuthorsRefKey, "appearance", 7, true, SettingTypeBoolean, ""},
+		{"Auto summary", "How content without a summary or excerpt gets one: its first words, its first sentences, written by the LLM on save, or none", SummaryWords, SummaryAutoRefKey, "appearance", 8, true, SettingTypeEnum, `{"options":["words","sentences","llm","off"]}`},
+		{"Auto summary length", "Most words of an automatic summary", "30", SummaryLengthRefKey, "appearance", 9, true, SettingTypeInteger, `{"min":5,"max":200}`},
+		{"External link target", "Target added to links to other sites, e.g. _blank to open them in a new tab (with rel noopener). Empty leaves links as written", "", ExternalLinkTargetRefKey, "appearance", 10, true, SettingTypeString, ""},
+		{"External link rel", "Space-separated rel values added to links to other sites, e.g. nofollow sponsored. Values a link already has are kept", "", ExternalLinkRelRefKey, "appearance", 11, true, SettingTypeString, ""},
+		{"External link tracking", "Also remove tracking parameters such as utm_source, fbclid and gclid from links to other sites. Links to the site always lose them", "false", ExternalLinkStripTrackingRefKey, "appearance", 12, true, SettingTypeBoolean, ""},
+		{"Social images", "Draw a social preview image (title over the site's colors) for content without a header image", "false", OGImageRefKey, "appearance", 13, true, SettingTypeBoolean, ""},
+		{"Social image background", "Hex color, or an image of the library (e.g. /images/brand.png) drawn under a tint of the default background", "#1f2937", OGImageBackgroundRefKey, "appearance", 14, true, SettingTypeString, ""},
+		{"Social image text color", "Hex color of the title", "#ffffff", OGImageTextColorRefKey, "appearance", 15, true, SettingTypeString, ""},
+		{"Social image accent color", "Hex color of the site name and the bottom band", "#f59e0b", OGImageAccentColorRefKey, "appearance", 16, true, SettingTypeString, ""},
+		{"Social image text size", "Title height in pixels. Long titles are drawn smaller to fit", "72", OGImageTextSizeRefKey, "appearance", 17, true, SettingTypeInteger, `{"min":16,"max":200}`},
+		{"Default social image", "Image for content without a header image when none is drawn or drawing fails: a path in the site or an absolute URL", "", OGImageDefaultRefKey, "appearance", 18, true, SettingTypeString, ""},
+		{"Lazy images", "Let browsers load images below the fold as they scroll near them. The header image, or else the first image, always loads at once", "true", LazyImagesRefKey, "appearance", 19, true, SettingTypeBoolean, ""},
+		{"Date format", "How pages show dates: iso (2024-03-10), short (Mar 10, 2024), medium (March 10, 2024), long (Sunday, March 10, 2024), or a Go time layout such as 02.01.2006", DefaultDateFormat, DateFormatRefKey, "appearance", 21, true, SettingTypeString, ""},
+		{"Featured pins", "Most featured posts shown first on the home and section index pages, ahead of newer posts. 0 lists featured posts by date", "3", FeaturedPinsRefKey, "appearance", 20, true, SettingTypeInteger, `{"min":0,"max":20}`},
+		{"Archive pages", "Generate date archives at /archive/ with a page per year and month, linked from the navigation", "false", ArchiveRefKey, "appearance", 22, true, SettingTypeBoolean, ""},
+		{"Theme", "Theme the site is generated with: default, a built-in theme or one uploaded on the settings page", DefaultTheme, ThemeRefKey, "appearance", 23, true, SettingTypeString, ""},
+		{"Custom head HTML", "Raw HTML added before </head> on every page, such as analytics or verification tags. Not sanitized: it runs on your visitors' browsers as written", "", HeadHTMLRefKey, "appearance", 24, true, SettingTypeText, ""},
+		{"Custom footer HTML", "Raw HTML added before </body> on every page, such as chat widgets or scripts. Not sanitized: it runs on your visitors' browsers as written", "", FooterHTMLRefKey, "appearance", 25, true, SettingTypeText, ""},
+		{"Updated date minimum hours", "How long after publishing an edit must come for content showing its last updated date to show it. Keeps quick fixes from reading as updates. 0 shows any later edit", "24", UpdatedMinHoursRefKey, "appearance", 26, true, SettingTypeInteger, `{"min":0,"max":87600}`},
+		// Feeds
+		{"Feed formats", "Comma-separated feed formats to generate: atom, rss, json. Empty disables feeds", "atom,json", FeedFormatsRefKey, "feeds", 1, true, SettingTypeString, ""},
+		{"Section feeds", "Comma-separated section paths that get their own feed. Empty for all sections, none for no section feeds", "", FeedSectionsRefKey, "feeds", 2, true, SettingTypeString, ""},
+		{"Tag feeds", "Generate a feed for each tag", "false", FeedTagsRefKey, "feeds", 3, true, SettingTypeBoolean, ""},
+		{"Feed max items", "Number of latest posts in each feed", "20", FeedMaxItemsRefKey, "feeds", 4, true, SettingTypeInteger, `{"min":1,"max":100}`},
+		{"Feed content", "What feed entries carry of each post: full for the whole body, summary for its summary only, none for neither", FeedContentSummary, FeedContentRefKey, "feeds", 5, true, SettingTypeEnum, `{"options":["full","summary","none"]}`},
+		{"Changelog section", "Path of the section holding release notes, also written to changelog.json for in-app widgets. Empty for none", "", ChangelogSectionRefKey, "feeds", 6, true, SettingTypeString, ""},
+		{"Changelog max entries", "Number of latest release notes in changelog.json", "20", ChangelogLimitRefKey, "feeds", 7, true, SettingTypeInteger, `{"min":1,"max":100}`},
+		// Images
+		{"Require stock image attribution", "Refuse stock images without an attribution", "false", RequireStockAttributionRefKey, "images", 1, true, SettingTypeBoolean, ""},
+		// Editor
+		{"Autosave", "Save content while it is edited. Off leaves saving to the Save button", "true", AutosaveRefKey, "editor", 1, true, SettingTypeBoolean, ""},
+		{"Autosave interval", "Seconds between the periodic saves of the edit form. Changes are also saved half a second after typing stops", "30", AutosaveIntervalRefKey, "editor", 2, true, SettingTypeInteger, `{"min":5,"max":3600}`},
+		{"Require review", "Publish drafts only once a reviewer approves them", "false", ReviewRequiredRefKey, "editor", 3, true, SettingTypeBoolean, ""},
 		// Analytics
 		{"Google Analytics enabled", "Enable Google Analytics tracking", "true", "ssg.analytics.enabled", "analytics", 1, true, SettingTypeBoolean, ""},
 		{"Google Analytics ID", "Google Analytics measurement ID (e.g. G-XXXXXXXXXX)", "", "ssg.analytics.id", "analytics", 2, true, SettingTypeString, ""},
-		// Cookie banner
-		{"Cookie banner enabled", "Show cookie consent banner", "true", "ssg.cookie.banner.enabled", "site", 5, true, SettingTypeBoolean, ""},
-		{"Cookie banner text", "Cookie banner consent message", "This site uses cookies to improve your experience. By continuing to use this site, you accept our use of cookies.", "ssg.cookie.banner.text", "site", 6, true, SettingTypeText, ""},
-		{"Robots.txt", "Custom robots.txt content (Sitemap URL is appended automatically)", "User-agent: *\nAllow: /\n\nUser-agent: GPTBot\nDisallow: /\n\nUser-agent: ClaudeBot\nDisallow: /\n\nUser-agent: Google-Extended\nDisallow: /", "ssg.robots.txt", "site", 7, true, SettingTypeText, ""},
 		// Search
 		{"Google Search enabled", "Enable Google site search", "true", "ssg.search.google.enabled", "search", 1, true, SettingTypeBoolean, ""},
 		{"Google Search ID", "Google Custom Search Engine ID", "", "ssg.search.google.id", "search", 2, true, SettingTypeString, ""},
-		// Git
-		{"Publish repository URL", "Git repository URL for publishing", "", "ssg.publish.repo.url", "git", 1, true, SettingTypeString, ""},
-		{"Publish branch", "Git branch for publishing", "gh-pages", "ssg.publish.branch", "git", 2, true, SettingTypeString, ""},
-		{"Publish auth token", "Authentication token for publishing", "", "ssg.publish.auth.token", "git", 3, true, SettingTypeString, ""},
-		{"Backup repository URL", "Git repository URL for markdown backup", "", "ssg.backup.repo.url", "git", 4, true, SettingTypeString, ""},
-		{"Backup branch", "Git branch for markdown backup", "main", "ssg.backup.branch", "git", 5, true, SettingTypeString, ""},
-		{"Backup auth token", "Authentication token for backup", "", "ssg.backup.auth.token", "git", 6, true, SettingTypeString, ""},
-		{"Commit user name", "Git user name for commits", "Clio Bot", "ssg.git.commit.user.name", "git", 7, true, SettingTypeString, ""},
-		{"Commit user email", "Git user email for commits", "clio@localhost", "ssg.git.commit.user.email", "git", 8, true, SettingTypeString, ""},
+		// Accessibility
+		{"Accessibility lint", "Check generated pages for images without alt text, missing titles, links without text and skipped heading levels", "false", A11yLintRefKey, "accessibility", 1, true, SettingTypeBoolean, ""},
+		{"Block publish on accessibility errors", "Refuse to publish while the accessibility lint finds errors. Warnings never block", "false", A11yBlockPublishRefKey, "accessibility", 2, true, SettingTypeBoolean, ""},
+		// Publishing
+		{"Publish repository URL", "Git repository URL for publishing", "", "ssg.publish.repo.url", "publishing", 1, true, SettingTypeString, ""},
+		{"Publish branch", "Git branch for publishing", "gh-pages", "ssg.publish.branch", "publishing", 2, true, SettingTypeString, ""},
+		{"Publish auth token", "Authentication token for publishing", "", "ssg.publish.auth.token", "publishing", 3, true, SettingTypeString, ""},
+		{"Backup repository URL", "Git repository URL for markdown backup", "", "ssg.backup.repo.url", "publishing", 4, true, SettingTypeString, ""},
+		{"Backup branch", "Git branch for markdown backup", "main", "ssg.backup.branch", "publishing", 5, true, SettingTypeString, ""},
+		{"Backup auth token", "Authentication token for backup", "", "ssg.backup.auth.token", "publishing", 6, true, SettingTypeString, ""},
+		{"Commit user name", "Git user name for commits", "Clio Bot", "ssg.git.commit.user.name", "publishing", 7, true, SettingTypeString, ""},
+		{"Commit user email", "Git user email for commits", "clio@localhost", "ssg.git.commit.user.email", "publishing", 8, true, SettingTypeString, ""},
+		{"SSH key path", "Path to the private key used for SSH repositories. Leave empty to use the host's SSH setup", "", SSHKeyPathRefKey, "publishing", 9, true, SettingTypeString, ""},
+		{"SSH key", "Private key contents, used when no key path is set. Prefer a key path so the key stays out of the database", "", SSHKeyRefKey, "publishing", 10, true, SettingTypeText, ""},
+		{"SSH key passphrase", "Passphrase for an encrypted SSH key", "", SSHPassphraseRefKey, "publishing", 11, true, SettingTypeString, ""},
+		{"SSH known hosts", "known_hosts entries for the Git server. Leave empty to use the host's known_hosts file", "", SSHKnownHostsRefKey, "publishing", 12, true, SettingTypeText, ""},
+		{"SSH host key policy", "strict only trusts known hosts, accept-new trusts hosts on first connection, insecure skips verification", "strict", SSHHostKeyPolicyRefKey, "publishing", 13, true, SettingTypeEnum, `{"options":["strict","accept-new","insecure"]}`},
+		{"Commit signing format", "openpgp signs with a key from the server's gpg keyring, ssh with an SSH key", "openpgp", SigningFormatRefKey, "publishing", 14, true, SettingTypeEnum, `{"options":["openpgp","ssh"]}`},
+		{"Commit signing key", "GPG key ID for openpgp. For ssh, the path to a private key, the key itself, or a public key whose private half is in the ssh agent. Empty leaves commits unsigned", "", SigningKeyRefKey, "publishing", 15, true, SettingTypeText, ""},
+		{"Require commit signing", "Refuse to publish or back up without a signing key instead of pushing unsigned commits", "false", SigningRequiredRefKey, "publishing", 16, true, SettingTypeBoolean, ""},
 		// Scheduling
 		{"Scheduled publish enabled", "Enable automatic publishing of scheduled content", "true", "ssg.scheduled.publish.enabled", "scheduling", 1, true, SettingTypeBoolean, ""},
 		{"Scheduled publish interval", "How often to check for scheduled content (e.g. 1h, 30m)", "15m", "ssg.scheduled.publish.interval", "scheduling", 2, true, SettingTypeString, ""},
+		{"Publish cron", "Cron expression for recurring generate and publish runs (e.g. 0 3 * * * or @daily). Leave empty to disable", "", "ssg.publish.cron", "scheduling", 3, true, SettingTypeString, ""},
+		{"Stale draft days", "Days without an edit after which a draft is listed as stale on the site page and in a daily log reminder. 0 turns the reminders off. Drafts are never deleted", "30", StaleDraftDaysRefKey, "scheduling", 4, true, SettingTypeInteger, `{"min":0,"max":3650}`},
+		// Features
+		{"Contributors", "Show contributors in the admin. Off hides them without deleting any", "true", FeatureContributors, "features", 1, true, SettingTypeBoolean, ""},
+		{"Imports", "Show Markdown import and quick import in the admin", "true", FeatureImports, "features", 2, true, SettingTypeBoolean, ""},
+		{"Series", "Show the series fields of content and series book downloads in the admin. Off keeps the series of content as they are", "true", FeatureSeries, "features", 3, true, SettingTypeBoolean, ""},
 		// API
 		{"API enabled", "Enable the REST API for external clients", "false", "ssg.api.enabled", "api", 1, true, SettingTypeBoolean, ""},
 		// Forms
@@ -264,7 +264,7 @@ func TestSeederStartVerifySettings(t *testing.T) {
 		settingNames[s.Name] = true
 	}
 
-	expected := []string{"Site description", "Hero image", "Index max items", "Blocks enabled"}
+	expected := []string{"Site description", "Hero image", "Index page size", "Blocks enabled"}
 	for _, name := range expected {
 		if !settingNames[name] {
 			t.Errorf("Expected setting %q to exist", name)
@@ -6,8 +6,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cliossg/clio/internal/db/sqlc"
@@ -17,7 +23,35 @@ import (
 )
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound         = errors.New("not found")
+	ErrSectionNotInSite = errors.New("section does not belong to site")
+	ErrSlugTaken        = errors.New("slug already in use")
+	ErrImportConflict   = errors.New("file and content both changed since the last import")
+	ErrTranslationTaken = errors.New("translation group already has content in this language")
+	ErrContentLocked    = errors.New("content is being edited by another user")
+	ErrEmptyFind        = errors.New("nothing to find")
+	ErrPublishQueued    = errors.New("site already has a publish queued or running")
+	ErrSettingOrder     = errors.New("order must list every setting of the category once")
+	ErrInvalidLinkURL   = errors.New("link URL must be an absolute http or https URL")
+	ErrCollectionName   = errors.New("collection name is required")
+	ErrReservedPath     = errors.New("path is reserved for generated files")
+)
+
+const (
+	// siteStatsTTL keeps dashboard stats from being recomputed on every page load.
+	siteStatsTTL = 30 * time.Second
+	// recentlyEditedLimit is the number of items listed on the dashboard.
+	recentlyEditedLimit = 5
+	// revisionInterval coalesces autosaves: an update saves a revision only
+	// when the latest one is older than this.
+	revisionInterval = 10 * time.Minute
+	// maxRevisions is the number of revisions kept per content.
+	maxRevisions = 50
+	// publishJobHistory is the number of finished publish jobs kept per site.
+	publishJobHistory = 10
+	// editLockTTL is how long an edit lock lasts without a refresh. The edit
+	// page refreshes it well within this while it stays open.
+	editLockTTL = 2 * time.Minute
 )
 
 // Service defines the SSG service interface.
@@ -32,15 +66,51 @@ type Service interface {
 	ListSites(ctx context.Context) ([]*Site, error)
 	UpdateSite(ctx context.Context, site *Site) error
 	DeleteSite(ctx context.Context, id uuid.UUID) error
+	CloneSite(ctx context.Context, sourceSiteID uuid.UUID, newName, newSlug string, opts CloneOptions) (*Site, error)
+	GetSiteStats(ctx context.Context, siteID uuid.UUID) (*SiteStats, error)
+	MarkSiteGenerated(ctx context.Context, siteID uuid.UUID, at time.Time) error
+	MarkSitePublished(ctx context.Context, siteID uuid.UUID, at time.Time, commit string) error
+	CreatePublishJob(ctx context.Context, siteID, userID uuid.UUID) (*PublishJob, error)
+	GetPublishJob(ctx context.Context, id uuid.UUID) (*PublishJob, error)
+	ListPublishJobs(ctx context.Context, siteID uuid.UUID) ([]*PublishJob, error)
+	UpdatePublishJob(ctx context.Context, job *PublishJob) error
+	FailUnfinishedPublishJobs(ctx context.Context, reason string) (int64, error)
+	LintAccessibility(ctx context.Context, siteID uuid.UUID) (*A11yReport, error)
+	ValidateFeeds(ctx context.Context, siteID uuid.UUID) (*FeedReport, error)
 
 	// Content operations
 	CreateContent(ctx context.Context, content *Content) error
 	GetContent(ctx context.Context, id uuid.UUID) (*Content, error)
 	GetContentWithMeta(ctx context.Context, id uuid.UUID) (*Content, error)
 	GetAllContentWithMeta(ctx context.Context, siteID uuid.UUID) ([]*Content, error)
-	GetContentWithPagination(ctx context.Context, siteID uuid.UUID, offset, limit int, search string) ([]*Content, int, error)
+	GetContentWithPagination(ctx context.Context, siteID uuid.UUID, offset, limit int, filter ContentFilter) ([]*Content, int, error)
+	ContentOutputPath(ctx context.Context, content *Content) (string, error)
+	GetPublicURL(ctx context.Context, content *Content) (*PublicURL, error)
+	CheckPathCollision(ctx context.Context, siteID uuid.UUID, path string, excludeID uuid.UUID) ([]*PathCollision, error)
+	IsReservedPath(ctx context.Context, siteID uuid.UUID, path string) (string, bool, error)
+	GetAdjacentContent(ctx context.Context, sectionID, contentID uuid.UUID) (newer, older *Content, err error)
 	UpdateContent(ctx context.Context, content *Content) error
+	ReconcileAutosave(ctx context.Context, draft AutosaveDraft) (*AutosaveReconcile, error)
+	FindAndReplace(ctx context.Context, siteID uuid.UUID, find, replace string, opts FindReplaceOptions) (*FindReplaceResult, error)
 	DeleteContent(ctx context.Context, id uuid.UUID) error
+	MoveContent(ctx context.Context, contentID, targetSiteID, targetSectionID uuid.UUID) (*ContentMove, error)
+	GetContentEditedSince(ctx context.Context, siteID uuid.UUID, since time.Time) ([]*Content, error)
+	FindStaleDrafts(ctx context.Context, siteID uuid.UUID, olderThan time.Duration) ([]*Content, error)
+	GetContentByDateRange(ctx context.Context, siteID uuid.UUID, from, to time.Time) ([]*Content, error)
+	GetContentByContributor(ctx context.Context, contributorID uuid.UUID) ([]*Content, error)
+	GetContentByKind(ctx context.Context, siteID uuid.UUID, kind string) ([]*Content, error)
+	GetFeaturedContent(ctx context.Context, siteID uuid.UUID, limit int) ([]*Content, error)
+	GetChangelogEntries(ctx context.Context, siteID uuid.UUID) ([]*Content, error)
+	GetTranslations(ctx context.Context, contentID uuid.UUID) ([]*Content, error)
+	CreateTranslationDraft(ctx context.Context, sourceID uuid.UUID, lang string) (*Content, error)
+	DeriveSummary(ctx context.Context, content *Content, opts SummaryOptions) (string, error)
+	ListContentRevisions(ctx context.Context, contentID uuid.UUID) ([]*ContentRevision, error)
+	GetContentRevision(ctx context.Context, id uuid.UUID) (*ContentRevision, error)
+	DiffRevisions(ctx context.Context, contentID, revA, revB uuid.UUID) (*RevisionDiff, error)
+	AcquireEditLock(ctx context.Context, contentID, userID uuid.UUID, userName string) (*EditLock, error)
+	GetEditLock(ctx context.Context, contentID uuid.UUID) (*EditLock, error)
+	ReleaseEditLock(ctx context.Context, contentID, userID uuid.UUID) error
+	BreakEditLock(ctx context.Context, contentID uuid.UUID) error
 
 	// Section operations
 	CreateSection(ctx context.Context, section *Section) error
@@ -56,6 +126,30 @@ type Service interface {
 	GetLayouts(ctx context.Context, siteID uuid.UUID) ([]*Layout, error)
 	UpdateLayout(ctx context.Context, layout *Layout) error
 	DeleteLayout(ctx context.Context, id uuid.UUID) error
+	ExportLayout(ctx context.Context, siteID, id uuid.UUID) (*LayoutExport, error)
+	ImportLayout(ctx context.Context, siteID uuid.UUID, export *LayoutExport, userID uuid.UUID) (*Layout, error)
+	ResolveLayoutForContent(ctx context.Context, content *Content) (*Layout, error)
+
+	// Content kind operations
+	ListContentKinds(ctx context.Context, siteID uuid.UUID) ([]*ContentKind, error)
+	GetContentKind(ctx context.Context, siteID uuid.UUID, name string) (*ContentKind, error)
+	SaveContentKind(ctx context.Context, kind *ContentKind) error
+	DeleteContentKind(ctx context.Context, siteID uuid.UUID, name string) error
+
+	// Content field operations
+	GetContentFields(ctx context.Context, contentID uuid.UUID) ([]*ContentField, error)
+	SetContentField(ctx context.Context, field *ContentField) error
+	SetContentFields(ctx context.Context, contentID uuid.UUID, fields []*ContentField) error
+	DeleteContentField(ctx context.Context, contentID uuid.UUID, name string) error
+
+	// Review operations
+	GetContentReview(ctx context.Context, contentID uuid.UUID) (*ContentReview, error)
+	SubmitForReview(ctx context.Context, contentID, userID uuid.UUID) (*ContentReview, error)
+	AssignReviewer(ctx context.Context, contentID, reviewerID uuid.UUID) (*ContentReview, error)
+	ApproveContent(ctx context.Context, contentID, reviewerID uuid.UUID, comment string) (*ContentReview, error)
+	RequestChanges(ctx context.Context, contentID, reviewerID uuid.UUID, comment string) (*ContentReview, error)
+	ListReviewQueue(ctx context.Context, siteID uuid.UUID) ([]*ContentReview, error)
+	ListReviewers(ctx context.Context) ([]*Reviewer, error)
 
 	// Tag operations
 	CreateTag(ctx context.Context, tag *Tag) error
@@ -69,20 +163,30 @@ type Service interface {
 	RemoveTagFromContent(ctx context.Context, contentID, tagID uuid.UUID) error
 	RemoveAllTagsFromContent(ctx context.Context, contentID uuid.UUID) error
 	GetTagsForContent(ctx context.Context, contentID uuid.UUID) ([]*Tag, error)
+	GetContentForTag(ctx context.Context, tagID uuid.UUID) ([]*Content, error)
+	BulkTagContent(ctx context.Context, tagID uuid.UUID, contentIDs []uuid.UUID, remove bool) (int, error)
 
 	// Setting operations
 	CreateSetting(ctx context.Context, param *Setting) error
 	GetSetting(ctx context.Context, id uuid.UUID) (*Setting, error)
 	GetSettingByName(ctx context.Context, siteID uuid.UUID, name string) (*Setting, error)
 	GetSettingByRefKey(ctx context.Context, siteID uuid.UUID, refKey string) (*Setting, error)
+	GetBaseURL(ctx context.Context, siteID uuid.UUID) (string, error)
+	GetTimezone(ctx context.Context, siteID uuid.UUID) (*time.Location, error)
+	IsFeatureEnabled(ctx context.Context, siteID uuid.UUID, key string) (bool, error)
 	GetSettings(ctx context.Context, siteID uuid.UUID) ([]*Setting, error)
+	GetSettingsByCategory(ctx context.Context, siteID uuid.UUID) ([]*SettingCategory, error)
+	ReorderSettings(ctx context.Context, siteID uuid.UUID, category string, ids []uuid.UUID) error
 	UpdateSetting(ctx context.Context, param *Setting) error
 	DeleteSetting(ctx context.Context, id uuid.UUID) error
 
 	// Image operations
 	CreateImage(ctx context.Context, image *Image) error
+	CreateImages(ctx context.Context, images []*Image) error
 	GetImage(ctx context.Context, id uuid.UUID) (*Image, error)
 	GetImages(ctx context.Context, siteID uuid.UUID) ([]*Image, error)
+	GetImagesWithPagination(ctx context.Context, siteID uuid.UUID, offset, limit int, filter ImageFilter) ([]*Image, int, error)
+	SearchImages(ctx context.Context, siteID uuid.UUID, query string, collectionID uuid.UUID, offset, limit int) ([]*Image, int, error)
 	GetImageByPath(ctx context.Context, siteID uuid.UUID, filePath string) (*Image, error)
 	GetContentImagesWithDetails(ctx context.Context, contentID uuid.UUID) ([]*ContentImageWithDetails, error)
 	GetAllContentImages(ctx context.Context, siteID uuid.UUID) (map[string][]MetaContentImage, error)
@@ -96,11 +200,27 @@ type Service interface {
 	UnlinkImageFromSection(ctx context.Context, sectionImageID uuid.UUID) error
 	UpdateImage(ctx context.Context, image *Image) error
 	DeleteImage(ctx context.Context, id uuid.UUID) error
+	GetImageUsage(ctx context.Context, imageID uuid.UUID) (*ImageUsage, error)
+	FindOrphanedImages(ctx context.Context, siteID uuid.UUID) ([]*Image, error)
+	PurgeOrphanedImages(ctx context.Context, siteID uuid.UUID, ids []uuid.UUID) (*OrphanPurge, error)
+
+	// Collection operations
+	CreateCollection(ctx context.Context, collection *Collection) error
+	GetCollection(ctx context.Context, id uuid.UUID) (*Collection, error)
+	GetCollections(ctx context.Context, siteID uuid.UUID) ([]*Collection, error)
+	UpdateCollection(ctx context.Context, collection *Collection) error
+	DeleteCollection(ctx context.Context, id uuid.UUID) error
+	AddImageToCollection(ctx context.Context, collectionID, imageID uuid.UUID) error
+	RemoveImageFromCollection(ctx context.Context, collectionID, imageID uuid.UUID) error
+	SetImageCollections(ctx context.Context, imageID uuid.UUID, collectionIDs []uuid.UUID) error
+	GetImagesByCollection(ctx context.Context, collectionID uuid.UUID) ([]*Image, error)
+	GetImageCollections(ctx context.Context, imageID uuid.UUID) ([]*Collection, error)
 
 	// Meta operations
 	GetMetaByContentID(ctx context.Context, contentID uuid.UUID) (*Meta, error)
 	CreateMeta(ctx context.Context, meta *Meta) error
 	UpdateMeta(ctx context.Context, meta *Meta) error
+	SetContentRobots(ctx context.Context, siteID uuid.UUID, contentIDs []uuid.UUID, robots string, userID uuid.UUID) (int, error)
 
 	// Contributor operations
 	CreateContributor(ctx context.Context, contributor *Contributor) error
@@ -113,6 +233,12 @@ type Service interface {
 
 	// HTML generation
 	GenerateHTMLForSite(ctx context.Context, siteSlug string) error
+	GenerateAllSites(ctx context.Context) ([]*SiteGenerationResult, error)
+	GenerateSiteTar(ctx context.Context, siteID uuid.UUID) (io.ReadCloser, error)
+	RenderDraftPreview(ctx context.Context, site *Site, urlPath string) ([]byte, error)
+	RenderContentPreview(ctx context.Context, site *Site, contentID uuid.UUID) ([]byte, error)
+	CompileSeries(ctx context.Context, siteID uuid.UUID, series string, format BookFormat) ([]byte, error)
+	CompileSection(ctx context.Context, siteID, sectionID uuid.UUID, format BookFormat) ([]byte, error)
 	BuildUserAuthorsMap(ctx context.Context, contents []*Content, contributors []*Contributor) map[string]*Contributor
 
 	// Import operations
@@ -126,7 +252,9 @@ type Service interface {
 	DeleteImport(ctx context.Context, id uuid.UUID) error
 	ScanImportDirectory(ctx context.Context, importPath string) ([]ImportFile, error)
 	ImportFile(ctx context.Context, siteID, userID uuid.UUID, file ImportFile, sectionID uuid.UUID) (*Content, *Import, error)
+	QuickImport(ctx context.Context, siteID, sectionID, userID uuid.UUID, author, markdown string) (*Content, error)
 	ReimportFile(ctx context.Context, importID uuid.UUID, force bool) (*Content, error)
+	DiffImport(ctx context.Context, importID uuid.UUID) (*ImportDiff, error)
 }
 
 // DBProvider provides access to the database.
@@ -140,6 +268,9 @@ type service struct {
 	htmlGen    *HTMLGenerator
 	cfg        *config.Config
 	log        logger.Logger
+
+	statsMu    sync.Mutex
+	statsCache map[uuid.UUID]*SiteStats
 }
 
 // NewService creates a new SSG service.
@@ -153,6 +284,9 @@ func NewService(dbProvider DBProvider, htmlGen *HTMLGenerator, cfg *config.Confi
 }
 
 func (s *service) Start(ctx context.Context) error {
+	if err := s.encryptSecretSettings(ctx); err != nil {
+		return err
+	}
 	s.log.Info("SSG service started")
 	return nil
 }
@@ -264,6 +398,8 @@ func (s *service) UpdateSite(ctx context.Context, site *Site) error {
 		return fmt.Errorf("cannot update site: %w", err)
 	}
 
+	// Publishing updates the site, so show the new timestamp right away.
+	s.invalidateSiteStats(site.ID)
 	return nil
 }
 
@@ -278,442 +414,2957 @@ func (s *service) DeleteSite(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// --- Content Operations ---
-
-func (s *service) CreateContent(ctx context.Context, content *Content) error {
+// MarkSiteGenerated records when the site HTML was last generated.
+func (s *service) MarkSiteGenerated(ctx context.Context, siteID uuid.UUID, at time.Time) error {
 	s.ensureQueries()
 
-	var contributorID sql.NullString
-	if content.ContributorID != nil {
-		contributorID = nullString(content.ContributorID.String())
+	err := s.queries.MarkSiteGenerated(ctx, sqlc.MarkSiteGeneratedParams{
+		LastGeneratedAt: nullTime(&at),
+		ID:              siteID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot mark site generated: %w", err)
 	}
+	return nil
+}
 
-	imagesMeta := s.buildImagesMeta(ctx, content.SiteID, content.Body)
-
-	params := sqlc.CreateContentParams{
-		ID:                content.ID.String(),
-		SiteID:            content.SiteID.String(),
-		UserID:            nullString(content.UserID.String()),
-		ShortID:           nullString(content.ShortID),
-		SectionID:         nullString(content.SectionID.String()),
-		ContributorID:     contributorID,
-		ContributorHandle: content.ContributorHandle,
-		AuthorUsername:    content.AuthorUsername,
-		Kind:              nullString(content.Kind),
-		Heading:           content.Heading,
-		Summary:           nullString(content.Summary),
-		Body:              nullString(content.Body),
-		Draft:             nullInt(boolToInt(content.Draft)),
-		Featured:          nullInt(boolToInt(content.Featured)),
-		Series:            nullString(content.Series),
-		SeriesOrder:       nullInt(int64(content.SeriesOrder)),
-		PublishedAt:       nullTime(content.PublishedAt),
-		HeroTitleDark:     nullInt(boolToInt(content.HeroTitleDark)),
-		ImagesMeta:        nullString(imagesMeta),
-		CreatedBy:         nullString(content.CreatedBy.String()),
-		UpdatedBy:         nullString(content.UpdatedBy.String()),
-		CreatedAt:         nullTime(&content.CreatedAt),
-		UpdatedAt:         nullTime(&content.UpdatedAt),
-	}
+// MarkSitePublished records a publish. An empty commit, e.g. when there was
+// nothing to commit, keeps the previously published commit.
+func (s *service) MarkSitePublished(ctx context.Context, siteID uuid.UUID, at time.Time, commit string) error {
+	s.ensureQueries()
 
-	_, err := s.queries.CreateContent(ctx, params)
+	err := s.queries.MarkSitePublished(ctx, sqlc.MarkSitePublishedParams{
+		LastPublishedAt:     nullTime(&at),
+		LastPublishedCommit: nullString(commit),
+		ID:                  siteID.String(),
+	})
 	if err != nil {
-		return fmt.Errorf("cannot create content: %w", err)
+		return fmt.Errorf("cannot mark site published: %w", err)
 	}
 
+	s.invalidateSiteStats(siteID)
 	return nil
 }
 
-func (s *service) GetContent(ctx context.Context, id uuid.UUID) (*Content, error) {
+// CreatePublishJob queues a publish of a site. A site has at most one job
+// queued or running; while it has one, that job is returned with
+// ErrPublishQueued. Finished jobs beyond publishJobHistory are pruned.
+func (s *service) CreatePublishJob(ctx context.Context, siteID, userID uuid.UUID) (*PublishJob, error) {
 	s.ensureQueries()
 
-	sqlcContent, err := s.queries.GetContent(ctx, id.String())
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrNotFound
-		}
-		return nil, fmt.Errorf("cannot get content: %w", err)
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
 	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
 
-	return contentFromSQLC(sqlcContent), nil
+	active, err := qtx.GetActivePublishJob(ctx, siteID.String())
+	if err == nil {
+		return publishJobFromSQLC(active), ErrPublishQueued
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("cannot get active publish job: %w", err)
+	}
+
+	job, err := qtx.CreatePublishJob(ctx, sqlc.CreatePublishJobParams{
+		ID:        uuid.New().String(),
+		SiteID:    siteID.String(),
+		Status:    PublishJobQueued,
+		CreatedBy: userID.String(),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create publish job: %w", err)
+	}
+
+	err = qtx.PrunePublishJobs(ctx, sqlc.PrunePublishJobsParams{
+		SiteID:   siteID.String(),
+		SiteID_2: siteID.String(),
+		Limit:    publishJobHistory,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot prune publish jobs: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cannot commit publish job: %w", err)
+	}
+	return publishJobFromSQLC(job), nil
 }
 
-func (s *service) GetContentWithMeta(ctx context.Context, id uuid.UUID) (*Content, error) {
+func (s *service) GetPublishJob(ctx context.Context, id uuid.UUID) (*PublishJob, error) {
 	s.ensureQueries()
 
-	row, err := s.queries.GetContentWithMeta(ctx, id.String())
+	job, err := s.queries.GetPublishJob(ctx, id.String())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("cannot get content with meta: %w", err)
-	}
-
-	content := contentWithMetaFromSQLC(row)
-
-	// Get tags
-	tags, err := s.GetTagsForContent(ctx, id)
-	if err == nil {
-		content.Tags = tags
+		return nil, fmt.Errorf("cannot get publish job: %w", err)
 	}
-
-	return content, nil
+	return publishJobFromSQLC(job), nil
 }
 
-func (s *service) GetAllContentWithMeta(ctx context.Context, siteID uuid.UUID) ([]*Content, error) {
+// ListPublishJobs returns the recent publish jobs of a site, newest first.
+func (s *service) ListPublishJobs(ctx context.Context, siteID uuid.UUID) ([]*PublishJob, error) {
 	s.ensureQueries()
 
-	rows, err := s.queries.GetAllContentWithMeta(ctx, siteID.String())
+	rows, err := s.queries.ListPublishJobsBySiteID(ctx, sqlc.ListPublishJobsBySiteIDParams{
+		SiteID: siteID.String(),
+		Limit:  publishJobHistory,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("cannot get all content: %w", err)
+		return nil, fmt.Errorf("cannot list publish jobs: %w", err)
 	}
 
-	contents := make([]*Content, len(rows))
+	jobs := make([]*PublishJob, len(rows))
 	for i, row := range rows {
-		contents[i] = contentWithMetaFromSQLCAll(row)
-		// Load tags for each content
-		tags, err := s.GetTagsForContent(ctx, contents[i].ID)
-		if err == nil {
-			contents[i].Tags = tags
-		}
+		jobs[i] = publishJobFromSQLC(row)
 	}
-
-	return contents, nil
+	return jobs, nil
 }
 
-func (s *service) GetContentWithPagination(ctx context.Context, siteID uuid.UUID, offset, limit int, search string) ([]*Content, int, error) {
+// UpdatePublishJob saves the status, progress and outcome of a job.
+func (s *service) UpdatePublishJob(ctx context.Context, job *PublishJob) error {
 	s.ensureQueries()
 
-	var contents []*Content
-	var total int64
-
-	if search != "" {
-		searchPattern := "%" + search + "%"
-		rows, err := s.queries.SearchContent(ctx, sqlc.SearchContentParams{
-			SiteID:  siteID.String(),
-			Heading: searchPattern,
-			Limit:   int64(limit),
-			Offset:  int64(offset),
-		})
-		if err != nil {
-			return nil, 0, fmt.Errorf("cannot search content: %w", err)
-		}
-
-		contents = make([]*Content, len(rows))
-		for i, row := range rows {
-			contents[i] = contentFromSQLC(row)
-		}
-
-		total, _ = s.queries.CountSearchContent(ctx, sqlc.CountSearchContentParams{
-			SiteID:  siteID.String(),
-			Heading: searchPattern,
-		})
-	} else {
-		rows, err := s.queries.GetContentWithPagination(ctx, sqlc.GetContentWithPaginationParams{
-			SiteID: siteID.String(),
-			Limit:  int64(limit),
-			Offset: int64(offset),
-		})
-		if err != nil {
-			return nil, 0, fmt.Errorf("cannot get content: %w", err)
-		}
-
-		contents = make([]*Content, len(rows))
-		for i, row := range rows {
-			contents[i] = contentFromSQLC(row)
-		}
-
-		total, _ = s.queries.CountContent(ctx, siteID.String())
+	err := s.queries.UpdatePublishJob(ctx, sqlc.UpdatePublishJobParams{
+		Status:     job.Status,
+		Progress:   job.Progress,
+		CommitHash: job.CommitHash,
+		CommitUrl:  job.CommitURL,
+		NoChanges:  boolToInt(job.NoChanges),
+		Error:      job.Error,
+		StartedAt:  nullTime(job.StartedAt),
+		FinishedAt: nullTime(job.FinishedAt),
+		ID:         job.ID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update publish job: %w", err)
 	}
-
-	return contents, int(total), nil
+	return nil
 }
 
-func (s *service) UpdateContent(ctx context.Context, content *Content) error {
+// FailUnfinishedPublishJobs marks every queued or running job as failed with
+// reason. Jobs only run in the process that queued them, so on startup the
+// unfinished ones were interrupted and will never finish.
+func (s *service) FailUnfinishedPublishJobs(ctx context.Context, reason string) (int64, error) {
 	s.ensureQueries()
 
-	content.UpdatedAt = time.Now()
-
-	var contributorID sql.NullString
-	if content.ContributorID != nil {
-		contributorID = nullString(content.ContributorID.String())
+	now := time.Now()
+	n, err := s.queries.FailUnfinishedPublishJobs(ctx, sqlc.FailUnfinishedPublishJobsParams{
+		Error:      reason,
+		FinishedAt: nullTime(&now),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot fail unfinished publish jobs: %w", err)
 	}
+	return n, nil
+}
 
-	imagesMeta := s.buildImagesMeta(ctx, content.SiteID, content.Body)
+// GetSiteStats returns content counts, workspace disk usage and recent activity
+// for a site. Results are cached for siteStatsTTL.
+func (s *service) GetSiteStats(ctx context.Context, siteID uuid.UUID) (*SiteStats, error) {
+	s.ensureQueries()
 
-	params := sqlc.UpdateContentParams{
-		SectionID:         nullString(content.SectionID.String()),
-		ContributorID:     contributorID,
-		ContributorHandle: content.ContributorHandle,
-		AuthorUsername:    content.AuthorUsername,
-		Kind:              nullString(content.Kind),
-		Heading:           content.Heading,
-		Summary:           nullString(content.Summary),
-		Body:              nullString(content.Body),
-		Draft:             nullInt(boolToInt(content.Draft)),
-		Featured:          nullInt(boolToInt(content.Featured)),
-		Series:            nullString(content.Series),
-		SeriesOrder:       nullInt(int64(content.SeriesOrder)),
-		PublishedAt:       nullTime(content.PublishedAt),
-		HeroTitleDark:     nullInt(boolToInt(content.HeroTitleDark)),
-		ImagesMeta:        nullString(imagesMeta),
-		UpdatedBy:         nullString(content.UpdatedBy.String()),
-		UpdatedAt:         nullTime(&content.UpdatedAt),
-		ID:                content.ID.String(),
+	s.statsMu.Lock()
+	cached, ok := s.statsCache[siteID]
+	s.statsMu.Unlock()
+	if ok && time.Since(cached.ComputedAt) < siteStatsTTL {
+		return cached, nil
 	}
 
-	_, err := s.queries.UpdateContent(ctx, params)
+	site, err := s.GetSite(ctx, siteID)
 	if err != nil {
-		return fmt.Errorf("cannot update content: %w", err)
+		return nil, err
 	}
 
-	return nil
-}
+	now := time.Now()
+	byStatus, err := s.queries.CountContentByStatus(ctx, sqlc.CountContentByStatusParams{
+		Now:    now,
+		SiteID: siteID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot count content: %w", err)
+	}
 
-func (s *service) DeleteContent(ctx context.Context, id uuid.UUID) error {
-	s.ensureQueries()
+	counts, err := s.queries.GetSiteCounts(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot count site items: %w", err)
+	}
 
-	err := s.queries.DeleteContent(ctx, id.String())
+	rows, err := s.queries.GetRecentlyUpdatedContent(ctx, sqlc.GetRecentlyUpdatedContentParams{
+		SiteID: siteID.String(),
+		Limit:  recentlyEditedLimit,
+	})
 	if err != nil {
-		return fmt.Errorf("cannot delete content: %w", err)
+		return nil, fmt.Errorf("cannot get recently edited content: %w", err)
+	}
+	recent := make([]*Content, len(rows))
+	for i, row := range rows {
+		recent[i] = contentFromSQLC(row)
 	}
 
-	return nil
-}
+	stats := &SiteStats{
+		Published:       int(byStatus.Published),
+		Drafts:          int(byStatus.Drafts),
+		Scheduled:       int(byStatus.Scheduled),
+		Sections:        int(counts.Sections),
+		Tags:            int(counts.Tags),
+		Images:          int(counts.Images),
+		Contributors:    int(counts.Contributors),
+		DiskUsage:       dirSize(NewWorkspace(s.cfg.SSG.SitesBasePath).GetSiteBasePath(site.Slug)),
+		RecentlyEdited:  recent,
+		LastPublishedAt: site.LastPublishedAt,
+		ComputedAt:      now,
+	}
 
-// --- Section Operations ---
+	s.statsMu.Lock()
+	if s.statsCache == nil {
+		s.statsCache = make(map[uuid.UUID]*SiteStats)
+	}
+	s.statsCache[siteID] = stats
+	s.statsMu.Unlock()
 
-func (s *service) CreateSection(ctx context.Context, section *Section) error {
-	s.ensureQueries()
+	return stats, nil
+}
 
-	params := sqlc.CreateSectionParams{
-		ID:            section.ID.String(),
-		SiteID:        section.SiteID.String(),
-		ShortID:       nullString(section.ShortID),
-		Name:          section.Name,
-		Description:   nullString(section.Description),
-		Path:          sql.NullString{String: section.Path, Valid: true},
-		LayoutID:      nullString(section.LayoutID.String()),
-		LayoutName:    nullString(section.LayoutName),
-		HeroTitleDark: nullInt(boolToInt(section.HeroTitleDark)),
-		CreatedBy:     nullString(section.CreatedBy.String()),
-		UpdatedBy:     nullString(section.UpdatedBy.String()),
-		CreatedAt:     nullTime(&section.CreatedAt),
-		UpdatedAt:     nullTime(&section.UpdatedAt),
+// LintAccessibility checks the site pages left by the last generation for
+// images without alt text, missing titles, links without text and skipped
+// heading levels.
+func (s *service) LintAccessibility(ctx context.Context, siteID uuid.UUID) (*A11yReport, error) {
+	site, err := s.GetSite(ctx, siteID)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := s.queries.CreateSection(ctx, params)
+	workspace := NewWorkspace(s.cfg.SSG.SitesBasePath)
+	workspace.SetOutputDir(s.cfg.SSG.OutputDir)
+	report, err := lintAccessibility(workspace.GetHTMLPath(site.Slug))
 	if err != nil {
-		return fmt.Errorf("cannot create section: %w", err)
+		return nil, fmt.Errorf("cannot lint generated pages: %w", err)
 	}
-
-	return nil
+	return report, nil
 }
 
-func (s *service) GetSection(ctx context.Context, id uuid.UUID) (*Section, error) {
-	s.ensureQueries()
+// ValidateFeeds parses the feeds left by the last generation back and
+// reports missing or malformed ids, dates and links.
+func (s *service) ValidateFeeds(ctx context.Context, siteID uuid.UUID) (*FeedReport, error) {
+	site, err := s.GetSite(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
 
-	sqlcSection, err := s.queries.GetSection(ctx, id.String())
+	workspace := NewWorkspace(s.cfg.SSG.SitesBasePath)
+	workspace.SetOutputDir(s.cfg.SSG.OutputDir)
+	report, err := validateFeeds(workspace.GetHTMLPath(site.Slug))
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrNotFound
-		}
-		return nil, fmt.Errorf("cannot get section: %w", err)
+		return nil, fmt.Errorf("cannot validate feeds: %w", err)
 	}
+	return report, nil
+}
 
-	return sectionFromSQLC(sqlcSection), nil
+func (s *service) invalidateSiteStats(siteID uuid.UUID) {
+	s.statsMu.Lock()
+	delete(s.statsCache, siteID)
+	s.statsMu.Unlock()
 }
 
-func (s *service) GetSectionByPath(ctx context.Context, siteID uuid.UUID, path string) (*Section, error) {
+// dirSize returns the total size of the regular files under root.
+// A missing directory counts as empty.
+func dirSize(root string) int64 {
+	var total int64
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+// CloneSite creates a new site with copies of the source site's layouts,
+// sections and settings, so it can serve as a starter template. Contributors
+// and content (with meta, tags and images) are copied when requested. All
+// records get fresh IDs and the copy is made in a single transaction.
+func (s *service) CloneSite(ctx context.Context, sourceSiteID uuid.UUID, newName, newSlug string, opts CloneOptions) (*Site, error) {
 	s.ensureQueries()
 
-	sqlcSection, err := s.queries.GetSectionByPath(ctx, sqlc.GetSectionByPathParams{
-		SiteID: siteID.String(),
-		Path:   sql.NullString{String: path, Valid: true},
-	})
+	source, err := s.GetSite(ctx, sourceSiteID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrNotFound
-		}
-		return nil, fmt.Errorf("cannot get section by path: %w", err)
+		return nil, err
+	}
+	if _, err := s.GetSiteBySlug(ctx, newSlug); err == nil {
+		return nil, ErrSlugTaken
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
 	}
 
-	return sectionFromSQLC(sqlcSection), nil
-}
-
-func (s *service) GetSections(ctx context.Context, siteID uuid.UUID) ([]*Section, error) {
-	s.ensureQueries()
+	site := NewSite(newName, newSlug)
+	site.CreatedBy, site.UpdatedBy = opts.UserID, opts.UserID
+	now := site.CreatedAt
+	src := source.ID.String()
+	dst := site.ID.String()
 
-	rows, err := s.queries.GetSectionsWithHeaderImage(ctx, siteID.String())
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("cannot get sections: %w", err)
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
 	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
 
-	sections := make([]*Section, len(rows))
-	for i, row := range rows {
-		section := &Section{
-			ID:     parseUUID(row.ID),
-			SiteID: parseUUID(row.SiteID),
-			Name:   row.Name,
-		}
-		if row.ShortID.Valid {
-			section.ShortID = row.ShortID.String
+	if _, err := qtx.CreateSite(ctx, sqlc.CreateSiteParams{
+		ID:        dst,
+		ShortID:   site.ShortID,
+		Name:      site.Name,
+		Slug:      site.Slug,
+		Active:    boolToInt(site.Active),
+		CreatedBy: site.CreatedBy.String(),
+		UpdatedBy: site.UpdatedBy.String(),
+		CreatedAt: site.CreatedAt,
+		UpdatedAt: site.UpdatedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("cannot create site: %w", err)
+	}
+
+	// Images are copied on demand, the first time a cloned record uses them.
+	workspace := NewWorkspace(s.cfg.SSG.SitesBasePath)
+	srcImages := workspace.GetImagesPath(source.Slug)
+	dstImages := workspace.GetImagesPath(site.Slug)
+	imageIDs := make(map[string]string)
+	var copied []string
+	committed := false
+	defer func() {
+		if !committed {
+			for _, path := range copied {
+				os.Remove(path)
+			}
 		}
-		if row.Description.Valid {
-			section.Description = row.Description.String
+	}()
+	cloneImage := func(id string) (string, error) {
+		if newID, ok := imageIDs[id]; ok {
+			return newID, nil
 		}
-		if row.Path.Valid {
-			section.Path = row.Path.String
+		img, err := qtx.GetImage(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("cannot get image: %w", err)
 		}
-		if row.LayoutID.Valid {
-			section.LayoutID = parseUUID(row.LayoutID.String)
+		image := imageFromSQLC(img)
+		image.ID = uuid.New()
+		image.SiteID = site.ID
+		image.ShortID = uuid.New().String()[:8]
+		image.CreatedAt, image.UpdatedAt = now, now
+		if _, err := qtx.CreateImage(ctx, createImageParams(image)); err != nil {
+			return "", fmt.Errorf("cannot create image: %w", err)
 		}
-		if row.LayoutName.Valid {
-			section.LayoutName = row.LayoutName.String
+
+		to := filepath.Join(dstImages, img.FilePath)
+		if err := EnsureDir(to); err != nil {
+			return "", fmt.Errorf("cannot create images directory: %w", err)
 		}
-		if row.HeaderImagePath.Valid {
-			section.HeaderImageURL = "/images/" + row.HeaderImagePath.String
+		if err := copyFile(filepath.Join(srcImages, img.FilePath), to); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("cannot copy image %s: %w", img.FilePath, err)
+		} else if err == nil {
+			copied = append(copied, to)
+		}
+
+		imageIDs[id] = image.ID.String()
+		return imageIDs[id], nil
+	}
+	newRef := func() (string, sql.NullString) {
+		return uuid.New().String(), nullString(uuid.New().String()[:8])
+	}
+
+	layouts, err := qtx.GetLayoutsBySiteID(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get layouts: %w", err)
+	}
+	layoutIDs := make(map[string]string, len(layouts))
+	for _, l := range layouts {
+		id, shortID := newRef()
+		if l.HeaderImageID.Valid && l.HeaderImageID.String != "" {
+			newImageID, err := cloneImage(l.HeaderImageID.String)
+			if err != nil {
+				return nil, err
+			}
+			l.HeaderImageID = nullString(newImageID)
+		}
+		if _, err := qtx.CreateLayout(ctx, sqlc.CreateLayoutParams{
+			ID:                id,
+			SiteID:            dst,
+			ShortID:           shortID,
+			Name:              l.Name,
+			Description:       l.Description,
+			Code:              l.Code,
+			Css:               l.Css,
+			ExcludeDefaultCss: l.ExcludeDefaultCss,
+			HeaderImageID:     l.HeaderImageID,
+			CreatedBy:         nullString(site.CreatedBy.String()),
+			UpdatedBy:         nullString(site.UpdatedBy.String()),
+			CreatedAt:         nullTime(&now),
+			UpdatedAt:         nullTime(&now),
+		}); err != nil {
+			return nil, fmt.Errorf("cannot create layout: %w", err)
+		}
+		layoutIDs[l.ID] = id
+	}
+
+	kinds, err := qtx.GetContentKindsBySiteID(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content kinds: %w", err)
+	}
+	for _, k := range kinds {
+		if k.LayoutID.Valid {
+			k.LayoutID = nullString(layoutIDs[k.LayoutID.String])
+		}
+		if err := qtx.UpsertContentKind(ctx, sqlc.UpsertContentKindParams{
+			ID:        uuid.New().String(),
+			SiteID:    dst,
+			Name:      k.Name,
+			Label:     k.Label,
+			Permalink: k.Permalink,
+			Listed:    k.Listed,
+			Feed:      k.Feed,
+			Redirect:  k.Redirect,
+			LayoutID:  k.LayoutID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err != nil {
+			return nil, fmt.Errorf("cannot create content kind: %w", err)
+		}
+	}
+
+	if source.DefaultLayoutID != uuid.Nil {
+		site.DefaultLayoutID = parseUUID(layoutIDs[source.DefaultLayoutID.String()])
+		site.DefaultLayoutName = source.DefaultLayoutName
+		if _, err := qtx.UpdateSite(ctx, sqlc.UpdateSiteParams{
+			Name:              site.Name,
+			Slug:              site.Slug,
+			Active:            boolToInt(site.Active),
+			DefaultLayoutID:   nullString(layoutIDs[source.DefaultLayoutID.String()]),
+			DefaultLayoutName: nullString(site.DefaultLayoutName),
+			UpdatedBy:         site.UpdatedBy.String(),
+			UpdatedAt:         now,
+			ID:                dst,
+		}); err != nil {
+			return nil, fmt.Errorf("cannot set default layout: %w", err)
+		}
+	}
+
+	sections, err := qtx.GetSectionsBySiteID(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sections: %w", err)
+	}
+	sectionIDs := make(map[string]string, len(sections))
+	for _, sec := range sections {
+		id, shortID := newRef()
+		layoutID := sec.LayoutID
+		if layoutID.Valid {
+			layoutID = nullString(layoutIDs[layoutID.String])
+		}
+		if _, err := qtx.CreateSection(ctx, sqlc.CreateSectionParams{
+			ID:            id,
+			SiteID:        dst,
+			ShortID:       shortID,
+			Name:          sec.Name,
+			Description:   sec.Description,
+			Path:          sec.Path,
+			LayoutID:      layoutID,
+			LayoutName:    sec.LayoutName,
+			HeroTitleDark: sec.HeroTitleDark,
+			CreatedBy:     nullString(site.CreatedBy.String()),
+			UpdatedBy:     nullString(site.UpdatedBy.String()),
+			CreatedAt:     nullTime(&now),
+			UpdatedAt:     nullTime(&now),
+		}); err != nil {
+			return nil, fmt.Errorf("cannot create section: %w", err)
+		}
+		sectionIDs[sec.ID] = id
+
+		links, err := qtx.GetSectionImagesBySectionID(ctx, sec.ID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get section images: %w", err)
+		}
+		for _, link := range links {
+			imageID, err := cloneImage(link.ImageID)
+			if err != nil {
+				return nil, err
+			}
+			if err := qtx.CreateSectionImage(ctx, sqlc.CreateSectionImageParams{
+				ID:         uuid.New().String(),
+				SectionID:  id,
+				ImageID:    imageID,
+				IsHeader:   link.IsHeader,
+				IsFeatured: link.IsFeatured,
+				OrderNum:   link.OrderNum,
+				CreatedAt:  nullTime(&now),
+			}); err != nil {
+				return nil, fmt.Errorf("cannot link section image: %w", err)
+			}
+		}
+	}
+
+	settings, err := qtx.GetSettingsBySiteID(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get params: %w", err)
+	}
+	for _, p := range settings {
+		id, shortID := newRef()
+		if _, err := qtx.CreateSetting(ctx, sqlc.CreateSettingParams{
+			ID:          id,
+			SiteID:      dst,
+			ShortID:     shortID,
+			Name:        p.Name,
+			Description: p.Description,
+			Value:       p.Value,
+			RefKey:      p.RefKey,
+			Category:    p.Category,
+			Position:    p.Position,
+			System:      p.System,
+			Type:        p.Type,
+			Constraints: p.Constraints,
+			UiControl:   p.UiControl,
+			Secret:      p.Secret,
+			CreatedBy:   nullString(site.CreatedBy.String()),
+			UpdatedBy:   nullString(site.UpdatedBy.String()),
+			CreatedAt:   nullTime(&now),
+			UpdatedAt:   nullTime(&now),
+		}); err != nil {
+			return nil, fmt.Errorf("cannot create param: %w", err)
+		}
+	}
+
+	contributorIDs := make(map[string]string)
+	if opts.Contributors || opts.Content {
+		contributors, err := qtx.ListContributorsBySiteID(ctx, src)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get contributors: %w", err)
+		}
+		for _, c := range contributors {
+			id := uuid.New().String()
+			if _, err := qtx.CreateContributor(ctx, sqlc.CreateContributorParams{
+				ID:          id,
+				ShortID:     uuid.New().String()[:8],
+				SiteID:      dst,
+				ProfileID:   c.ProfileID,
+				Handle:      c.Handle,
+				Name:        c.Name,
+				Surname:     c.Surname,
+				Bio:         c.Bio,
+				SocialLinks: c.SocialLinks,
+				Role:        c.Role,
+				CreatedBy:   site.CreatedBy.String(),
+				UpdatedBy:   site.UpdatedBy.String(),
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}); err != nil {
+				return nil, fmt.Errorf("cannot create contributor: %w", err)
+			}
+			contributorIDs[c.ID] = id
+		}
+	}
+
+	if opts.Content {
+		if err := s.cloneContent(ctx, qtx, src, dst, now, sectionIDs, contributorIDs, layoutIDs, cloneImage); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cannot commit site clone: %w", err)
+	}
+	committed = true
+
+	return site, nil
+}
+
+// cloneContent copies every content item of a site with its meta, tags and
+// linked images, as part of CloneSite.
+func (s *service) cloneContent(ctx context.Context, qtx *sqlc.Queries, src, dst string, now time.Time,
+	sectionIDs, contributorIDs, layoutIDs map[string]string, cloneImage func(string) (string, error)) error {
+	contents, err := qtx.GetContentBySiteID(ctx, src)
+	if err != nil {
+		return fmt.Errorf("cannot get contents: %w", err)
+	}
+
+	tagIDs := make(map[string]string)
+	tags, err := qtx.GetTagsBySiteID(ctx, src)
+	if err != nil {
+		return fmt.Errorf("cannot get tags: %w", err)
+	}
+	for _, t := range tags {
+		id := uuid.New().String()
+		if t.HeaderImageID.Valid && t.HeaderImageID.String != "" {
+			newImageID, err := cloneImage(t.HeaderImageID.String)
+			if err != nil {
+				return err
+			}
+			t.HeaderImageID = nullString(newImageID)
+		}
+		if _, err := qtx.CreateTag(ctx, sqlc.CreateTagParams{
+			ID:            id,
+			SiteID:        dst,
+			ShortID:       nullString(uuid.New().String()[:8]),
+			Name:          t.Name,
+			Slug:          t.Slug,
+			Description:   t.Description,
+			HeaderImageID: t.HeaderImageID,
+			CreatedBy:     t.CreatedBy,
+			UpdatedBy:     t.UpdatedBy,
+			CreatedAt:     nullTime(&now),
+			UpdatedAt:     nullTime(&now),
+		}); err != nil {
+			return fmt.Errorf("cannot create tag: %w", err)
+		}
+		tagIDs[t.ID] = id
+	}
+
+	for _, c := range contents {
+		id := uuid.New().String()
+		sectionID := c.SectionID
+		if sectionID.Valid {
+			sectionID = nullString(sectionIDs[sectionID.String])
+		}
+		contributorID := c.ContributorID
+		if contributorID.Valid {
+			contributorID = nullString(contributorIDs[contributorID.String])
+		}
+		layoutID := c.LayoutID
+		if layoutID.Valid {
+			layoutID = nullString(layoutIDs[layoutID.String])
+		}
+		if _, err := qtx.CreateContent(ctx, sqlc.CreateContentParams{
+			ID:                id,
+			SiteID:            dst,
+			UserID:            c.UserID,
+			ShortID:           nullString(uuid.New().String()[:8]),
+			SectionID:         sectionID,
+			ContributorID:     contributorID,
+			ContributorHandle: c.ContributorHandle,
+			AuthorUsername:    c.AuthorUsername,
+			Kind:              c.Kind,
+			Heading:           c.Heading,
+			Summary:           c.Summary,
+			Body:              c.Body,
+			Draft:             c.Draft,
+			Featured:          c.Featured,
+			Series:            c.Series,
+			SeriesOrder:       c.SeriesOrder,
+			PublishedAt:       c.PublishedAt,
+			HeroTitleDark:     c.HeroTitleDark,
+			ImagesMeta:        c.ImagesMeta,
+			Visibility:        c.Visibility,
+			Lang:              c.Lang,
+			TranslationGroup:  c.TranslationGroup,
+			LinkURL:           c.LinkURL,
+			LayoutID:          layoutID,
+			CreatedBy:         c.CreatedBy,
+			UpdatedBy:         c.UpdatedBy,
+			CreatedAt:         nullTime(&now),
+			UpdatedAt:         nullTime(&now),
+		}); err != nil {
+			return fmt.Errorf("cannot create content: %w", err)
+		}
+
+		// The review goes along so the copy is not published around the
+		// approval step.
+		review, err := qtx.GetContentReview(ctx, c.ID)
+		if err == nil {
+			if err := qtx.UpsertContentReview(ctx, sqlc.UpsertContentReviewParams{
+				ContentID:   id,
+				Status:      review.Status,
+				ReviewerID:  review.ReviewerID,
+				SubmittedBy: review.SubmittedBy,
+				Comment:     review.Comment,
+				SubmittedAt: review.SubmittedAt,
+				ReviewedAt:  review.ReviewedAt,
+				UpdatedAt:   review.UpdatedAt,
+			}); err != nil {
+				return fmt.Errorf("cannot create review: %w", err)
+			}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("cannot get review: %w", err)
+		}
+
+		meta, err := qtx.GetMetaByContentID(ctx, c.ID)
+		if err == nil {
+			if _, err := qtx.CreateMeta(ctx, sqlc.CreateMetaParams{
+				ID:              uuid.New().String(),
+				SiteID:          dst,
+				ShortID:         nullString(uuid.New().String()[:8]),
+				ContentID:       id,
+				Summary:         meta.Summary,
+				Excerpt:         meta.Excerpt,
+				Description:     meta.Description,
+				Keywords:        meta.Keywords,
+				Robots:          meta.Robots,
+				CanonicalUrl:    meta.CanonicalUrl,
+				Sitemap:         meta.Sitemap,
+				TableOfContents: meta.TableOfContents,
+				Share:           meta.Share,
+				Comments:        meta.Comments,
+				KeepLinks:       meta.KeepLinks,
+				ShowUpdated:     meta.ShowUpdated,
+				CreatedBy:       meta.CreatedBy,
+				UpdatedBy:       meta.UpdatedBy,
+				CreatedAt:       nullTime(&now),
+				UpdatedAt:       nullTime(&now),
+			}); err != nil {
+				return fmt.Errorf("cannot create meta: %w", err)
+			}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("cannot get meta: %w", err)
+		}
+
+		contentTags, err := qtx.GetTagsForContent(ctx, c.ID)
+		if err != nil {
+			return fmt.Errorf("cannot get tags: %w", err)
+		}
+		for _, t := range contentTags {
+			if err := qtx.AddTagToContent(ctx, sqlc.AddTagToContentParams{
+				ID:        uuid.New().String(),
+				ContentID: id,
+				TagID:     tagIDs[t.ID],
+				CreatedAt: nullTime(&now),
+			}); err != nil {
+				return fmt.Errorf("cannot add tag: %w", err)
+			}
+		}
+
+		fields, err := qtx.GetContentFieldsByContentID(ctx, c.ID)
+		if err != nil {
+			return fmt.Errorf("cannot get content fields: %w", err)
+		}
+		for _, f := range fields {
+			if err := qtx.UpsertContentField(ctx, sqlc.UpsertContentFieldParams{
+				ID:        uuid.New().String(),
+				ContentID: id,
+				Name:      f.Name,
+				Type:      f.Type,
+				Value:     f.Value,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}); err != nil {
+				return fmt.Errorf("cannot add content field: %w", err)
+			}
+		}
+
+		links, err := qtx.GetContentImagesByContentID(ctx, c.ID)
+		if err != nil {
+			return fmt.Errorf("cannot get content images: %w", err)
+		}
+		for _, link := range links {
+			imageID, err := cloneImage(link.ImageID)
+			if err != nil {
+				return err
+			}
+			if err := qtx.CreateContentImage(ctx, sqlc.CreateContentImageParams{
+				ID:         uuid.New().String(),
+				ContentID:  id,
+				ImageID:    imageID,
+				IsHeader:   link.IsHeader,
+				IsFeatured: link.IsFeatured,
+				OrderNum:   link.OrderNum,
+				CreatedAt:  nullTime(&now),
+			}); err != nil {
+				return fmt.Errorf("cannot link content image: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// --- Content Operations ---
+
+func (s *service) CreateContent(ctx context.Context, content *Content) error {
+	s.ensureQueries()
+
+	var contributorID sql.NullString
+	if content.ContributorID != nil {
+		contributorID = nullString(content.ContributorID.String())
+	}
+
+	if err := s.checkTranslation(ctx, content); err != nil {
+		return err
+	}
+	if err := checkLinkURL(content); err != nil {
+		return err
+	}
+	if err := s.checkApproval(ctx, content); err != nil {
+		return err
+	}
+
+	imagesMeta := s.buildImagesMeta(ctx, content.SiteID, content.Body)
+
+	params := sqlc.CreateContentParams{
+		ID:                content.ID.String(),
+		SiteID:            content.SiteID.String(),
+		UserID:            nullString(content.UserID.String()),
+		ShortID:           nullString(content.ShortID),
+		SectionID:         nullString(content.SectionID.String()),
+		ContributorID:     contributorID,
+		ContributorHandle: content.ContributorHandle,
+		AuthorUsername:    content.AuthorUsername,
+		Kind:              nullString(content.Kind),
+		Heading:           content.Heading,
+		Summary:           nullString(content.Summary),
+		Body:              nullString(content.Body),
+		Draft:             nullInt(boolToInt(content.Draft)),
+		Featured:          nullInt(boolToInt(content.Featured)),
+		Series:            nullString(content.Series),
+		SeriesOrder:       nullInt(int64(content.SeriesOrder)),
+		PublishedAt:       nullTime(content.PublishedAt),
+		HeroTitleDark:     nullInt(boolToInt(content.HeroTitleDark)),
+		ImagesMeta:        nullString(imagesMeta),
+		Visibility:        normalizeVisibility(content.Visibility),
+		Lang:              content.Lang,
+		TranslationGroup:  content.TranslationGroup,
+		LinkURL:           content.LinkURL,
+		LayoutID:          nullString(content.LayoutID.String()),
+		CreatedBy:         nullString(content.CreatedBy.String()),
+		UpdatedBy:         nullString(content.UpdatedBy.String()),
+		CreatedAt:         nullTime(&content.CreatedAt),
+		UpdatedAt:         nullTime(&content.UpdatedAt),
+	}
+
+	_, err := s.queries.CreateContent(ctx, params)
+	if err != nil {
+		return fmt.Errorf("cannot create content: %w", err)
+	}
+
+	return s.joinTranslationGroup(ctx, content)
+}
+
+func (s *service) GetContent(ctx context.Context, id uuid.UUID) (*Content, error) {
+	s.ensureQueries()
+
+	sqlcContent, err := s.queries.GetContent(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get content: %w", err)
+	}
+
+	return contentFromSQLC(sqlcContent), nil
+}
+
+func (s *service) GetContentWithMeta(ctx context.Context, id uuid.UUID) (*Content, error) {
+	s.ensureQueries()
+
+	row, err := s.queries.GetContentWithMeta(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get content with meta: %w", err)
+	}
+
+	content := contentWithMetaFromSQLC(row)
+
+	// Get tags
+	tags, err := s.GetTagsForContent(ctx, id)
+	if err == nil {
+		content.Tags = tags
+	}
+	if fields, err := s.GetContentFields(ctx, id); err == nil {
+		content.Fields = fieldValues(fields)
+	}
+
+	return content, nil
+}
+
+func (s *service) GetAllContentWithMeta(ctx context.Context, siteID uuid.UUID) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetAllContentWithMeta(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get all content: %w", err)
+	}
+
+	// Custom fields of the whole site in one query, by content.
+	fieldRows, _ := s.queries.GetContentFieldsBySiteID(ctx, siteID.String())
+	fields := make(map[string][]*ContentField)
+	for _, row := range fieldRows {
+		fields[row.ContentID] = append(fields[row.ContentID], contentFieldFromSQLC(row))
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentWithMetaFromSQLCAll(row)
+		// Load tags for each content
+		tags, err := s.GetTagsForContent(ctx, contents[i].ID)
+		if err == nil {
+			contents[i].Tags = tags
+		}
+		contents[i].Fields = fieldValues(fields[row.ID])
+	}
+
+	return contents, nil
+}
+
+// GetContentWithPagination returns a page of the site's content matching filter,
+// most recently created first, and the number of matching items.
+func (s *service) GetContentWithPagination(ctx context.Context, siteID uuid.UUID, offset, limit int, filter ContentFilter) ([]*Content, int, error) {
+	s.ensureQueries()
+
+	search := ""
+	if filter.Search != "" {
+		search = "%" + filter.Search + "%"
+	}
+	sectionID := ""
+	if filter.SectionID != uuid.Nil {
+		sectionID = filter.SectionID.String()
+	}
+	contributorID := ""
+	if filter.ContributorID != uuid.Nil {
+		contributorID = filter.ContributorID.String()
+	}
+	now := time.Now()
+
+	rows, err := s.queries.ListFilteredContent(ctx, sqlc.ListFilteredContentParams{
+		SiteID:        siteID.String(),
+		Search:        search,
+		SectionID:     sectionID,
+		ContributorID: contributorID,
+		Tag:           filter.Tag,
+		Status:        filter.Status,
+		Now:           now,
+		Visibility:    filter.Visibility,
+		Limit:         int64(limit),
+		Offset:        int64(offset),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot get content: %w", err)
+	}
+
+	total, err := s.queries.CountFilteredContent(ctx, sqlc.CountFilteredContentParams{
+		SiteID:        siteID.String(),
+		Search:        search,
+		SectionID:     sectionID,
+		ContributorID: contributorID,
+		Tag:           filter.Tag,
+		Status:        filter.Status,
+		Now:           now,
+		Visibility:    filter.Visibility,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot count content: %w", err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+
+	return contents, int(total), nil
+}
+
+// GetContentEditedSince returns the site's content updated after since,
+// most recent first.
+func (s *service) GetContentEditedSince(ctx context.Context, siteID uuid.UUID, since time.Time) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetContentEditedSince(ctx, sqlc.GetContentEditedSinceParams{
+		SiteID: siteID.String(),
+		Since:  since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content edited since %s: %w", since.Format(time.RFC3339), err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+	return contents, nil
+}
+
+// FindStaleDrafts returns the site's drafts nobody has updated for longer than
+// olderThan, least recently updated first.
+func (s *service) FindStaleDrafts(ctx context.Context, siteID uuid.UUID, olderThan time.Duration) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetStaleDrafts(ctx, sqlc.GetStaleDraftsParams{
+		SiteID: siteID.String(),
+		Before: time.Now().Add(-olderThan),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot find stale drafts: %w", err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+	return contents, nil
+}
+
+// GetContentByDateRange returns the site's listed, published content with a
+// publication date from from up to but not including to, newest first. Drafts,
+// scheduled, unlisted and private content and content without a publication
+// date are left out, as they are from the archive pages.
+func (s *service) GetContentByDateRange(ctx context.Context, siteID uuid.UUID, from, to time.Time) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetContentByDateRange(ctx, sqlc.GetContentByDateRangeParams{
+		SiteID:   siteID.String(),
+		FromDate: from,
+		ToDate:   to,
+		Now:      time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content from %s to %s: %w", from.Format(time.RFC3339), to.Format(time.RFC3339), err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+	return contents, nil
+}
+
+// GetContentByContributor returns the content credited to a contributor,
+// newest first, drafts included.
+func (s *service) GetContentByContributor(ctx context.Context, contributorID uuid.UUID) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetContentByContributor(ctx, nullString(contributorID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content by contributor: %w", err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+	return contents, nil
+}
+
+// GetContentByKind returns the site's content of the given kind, newest
+// first. Content without a kind is a post.
+func (s *service) GetContentByKind(ctx context.Context, siteID uuid.UUID, kind string) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetContentBySiteIDAndKind(ctx, sqlc.GetContentBySiteIDAndKindParams{
+		SiteID: siteID.String(),
+		Kind:   kind,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content by kind: %w", err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+	return contents, nil
+}
+
+// GetFeaturedContent returns up to limit featured items of the site that are
+// published and public, newest first.
+func (s *service) GetFeaturedContent(ctx context.Context, siteID uuid.UUID, limit int) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetFeaturedContent(ctx, sqlc.GetFeaturedContentParams{
+		SiteID: siteID.String(),
+		Now:    time.Now(),
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get featured content: %w", err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+	return contents, nil
+}
+
+// GetChangelogEntries returns the published content of the site's changelog
+// section, newest first, as many as changelog.json holds. Sites without a
+// changelog section have none.
+func (s *service) GetChangelogEntries(ctx context.Context, siteID uuid.UUID) ([]*Content, error) {
+	params := make(map[string]string)
+	for _, refKey := range []string{ChangelogSectionRefKey, ChangelogLimitRefKey} {
+		param, err := s.GetSettingByRefKey(ctx, siteID, refKey)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		params[refKey] = param.Value
+	}
+	if strings.TrimSpace(params[ChangelogSectionRefKey]) == "" {
+		return nil, nil
+	}
+
+	sections, err := s.GetSections(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	section := changelogSection(params, sections)
+	if section == nil {
+		return nil, nil
+	}
+	contents, err := s.GetAllContentWithMeta(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	return changelogContents(contents, section, params), nil
+}
+
+// GetTranslations returns the other contents of the content's translation
+// group, sorted by language. Content outside a group has none.
+func (s *service) GetTranslations(ctx context.Context, contentID uuid.UUID) ([]*Content, error) {
+	s.ensureQueries()
+
+	content, err := s.GetContent(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if content.TranslationGroup == "" {
+		return nil, nil
+	}
+
+	members, err := s.translationGroupMembers(ctx, content.SiteID, content.TranslationGroup)
+	if err != nil {
+		return nil, err
+	}
+	var translations []*Content
+	for _, m := range members {
+		if m.ID != content.ID {
+			translations = append(translations, m)
+		}
+	}
+	return translations, nil
+}
+
+// CreateTranslationDraft starts a manual translation of the source content
+// into lang: a draft in the source's translation group with the source's
+// section, author, tags, images and meta. Heading, summary and body hold the
+// source text for the translator to write over. The source joins the group
+// too when it had none.
+func (s *service) CreateTranslationDraft(ctx context.Context, sourceID uuid.UUID, lang string) (*Content, error) {
+	s.ensureQueries()
+
+	lang, err := NormalizeLang(lang)
+	if err != nil {
+		return nil, err
+	}
+	if lang == "" {
+		return nil, fmt.Errorf("%w: a language such as es or pt-BR is required", ErrInvalidLang)
+	}
+
+	source, err := s.GetContent(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	content := NewContent(source.SiteID, source.SectionID, source.Heading, source.Body)
+	content.Summary = source.Summary
+	content.Kind = source.Kind
+	content.Visibility = source.Visibility
+	content.Series = source.Series
+	content.SeriesOrder = source.SeriesOrder
+	content.UserID = source.UserID
+	content.AuthorUsername = source.AuthorUsername
+	content.ContributorID = source.ContributorID
+	content.ContributorHandle = source.ContributorHandle
+	content.HeroTitleDark = source.HeroTitleDark
+	content.LayoutID = source.LayoutID
+	content.LinkURL = source.LinkURL
+	content.CreatedBy = source.UpdatedBy
+	content.UpdatedBy = source.UpdatedBy
+	content.Lang = lang
+	content.TranslationGroup = source.TranslationGroup
+	if content.TranslationGroup == "" {
+		content.TranslationGroup = source.ID.String()
+	}
+
+	// CreateContent refuses a language the group already has, the
+	// source's own included.
+	if err := s.CreateContent(ctx, content); err != nil {
+		return nil, err
+	}
+
+	meta, err := s.GetMetaByContentID(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		copied := *meta
+		copied.ID = uuid.New()
+		copied.ShortID = uuid.New().String()[:8]
+		copied.ContentID = content.ID
+		copied.CreatedAt = content.CreatedAt
+		copied.UpdatedAt = content.CreatedAt
+		if err := s.CreateMeta(ctx, &copied); err != nil {
+			return nil, fmt.Errorf("cannot copy meta to translation: %w", err)
+		}
+		content.Meta = &copied
+	}
+
+	tags, err := s.GetTagsForContent(ctx, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get tags of translation source: %w", err)
+	}
+	for _, tag := range tags {
+		if err := s.AddTagToContentByID(ctx, content.ID, tag.ID); err != nil {
+			return nil, fmt.Errorf("cannot copy tag %s to translation: %w", tag.Name, err)
+		}
+	}
+	content.Tags = tags
+
+	fields, err := s.GetContentFields(ctx, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get fields of translation source: %w", err)
+	}
+	copies := make([]*ContentField, len(fields))
+	for i, f := range fields {
+		copies[i] = NewContentField(content.ID, f.Name, f.Type, f.Value)
+	}
+	if err := s.SetContentFields(ctx, content.ID, copies); err != nil {
+		return nil, fmt.Errorf("cannot copy fields to translation: %w", err)
+	}
+	content.Fields = fieldValues(copies)
+
+	links, err := s.queries.GetContentImagesByContentID(ctx, source.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get images of translation source: %w", err)
+	}
+	for _, link := range links {
+		if err := s.queries.CreateContentImage(ctx, sqlc.CreateContentImageParams{
+			ID:         uuid.New().String(),
+			ContentID:  content.ID.String(),
+			ImageID:    link.ImageID,
+			IsHeader:   link.IsHeader,
+			IsFeatured: link.IsFeatured,
+			OrderNum:   link.OrderNum,
+			CreatedAt:  nullTime(&content.CreatedAt),
+		}); err != nil {
+			return nil, fmt.Errorf("cannot copy image to translation: %w", err)
+		}
+	}
+
+	return content, nil
+}
+
+// DeriveSummary returns the summary for content: its own, else the excerpt of
+// its meta, else one derived from the body as opts says. In SummaryLLM mode
+// opts.Summarize writes it, cut to opts.Words words.
+func (s *service) DeriveSummary(ctx context.Context, content *Content, opts SummaryOptions) (string, error) {
+	c := *content
+	if c.Meta == nil && c.ID != uuid.Nil {
+		meta, err := s.GetMetaByContentID(ctx, c.ID)
+		if err != nil {
+			return "", err
+		}
+		c.Meta = meta
+	}
+
+	summary := displaySummary(&c, opts)
+	if opts.Mode != SummaryLLM || opts.Summarize == nil || summary == "" {
+		return summary, nil
+	}
+	if strings.TrimSpace(c.Summary) != "" || (c.Meta != nil && strings.TrimSpace(c.Meta.Excerpt) != "") {
+		return summary, nil
+	}
+
+	written, err := opts.Summarize(ctx, plainText(c.Body), opts.Words)
+	if err != nil {
+		return "", fmt.Errorf("cannot summarize content: %w", err)
+	}
+	return firstWords(written, opts.Words), nil
+}
+
+// translationGroupMembers returns the contents of a translation group, sorted
+// by language. A group is named after the ID of the content it started from,
+// which may not carry the group yet: joinTranslationGroup adds it once a
+// translation links to it.
+func (s *service) translationGroupMembers(ctx context.Context, siteID uuid.UUID, group string) ([]*Content, error) {
+	rows, err := s.queries.GetContentByTranslationGroup(ctx, sqlc.GetContentByTranslationGroupParams{
+		SiteID:           siteID.String(),
+		TranslationGroup: group,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get translations: %w", err)
+	}
+
+	members := make([]*Content, 0, len(rows)+1)
+	for _, row := range rows {
+		members = append(members, contentFromSQLC(row))
+	}
+	if origin, err := s.queries.GetContent(ctx, group); err == nil && origin.SiteID == siteID.String() && origin.TranslationGroup == "" {
+		members = append(members, contentFromSQLC(origin))
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].Lang < members[j].Lang })
+	return members, nil
+}
+
+// checkLinkURL trims the link URL of content and makes sure it is a web
+// address, since pages of redirecting kinds send visitors there.
+func checkLinkURL(content *Content) error {
+	content.LinkURL = strings.TrimSpace(content.LinkURL)
+	if content.LinkURL != "" && !isWebURL(content.LinkURL) {
+		return fmt.Errorf("%w: %q", ErrInvalidLinkURL, content.LinkURL)
+	}
+	return nil
+}
+
+// checkTranslation normalizes the language of content and makes sure no other
+// content of its translation group is in the same language.
+func (s *service) checkTranslation(ctx context.Context, content *Content) error {
+	lang, err := NormalizeLang(content.Lang)
+	if err != nil {
+		return err
+	}
+	content.Lang = lang
+	content.TranslationGroup = strings.TrimSpace(content.TranslationGroup)
+	if content.TranslationGroup == "" {
+		return nil
+	}
+
+	siteLang, err := s.siteLanguage(ctx, content.SiteID)
+	if err != nil {
+		return err
+	}
+	members, err := s.translationGroupMembers(ctx, content.SiteID, content.TranslationGroup)
+	if err != nil {
+		return err
+	}
+	lang = contentLang(content, siteLang)
+	for _, m := range members {
+		if m.ID != content.ID && contentLang(m, siteLang) == lang {
+			return fmt.Errorf("%w: %q is already in %s", ErrTranslationTaken, m.Heading, lang)
+		}
+	}
+	return nil
+}
+
+// joinTranslationGroup adds the content a translation group is named after to
+// the group, when content is its first translation.
+func (s *service) joinTranslationGroup(ctx context.Context, content *Content) error {
+	group := content.TranslationGroup
+	if group == "" || group == content.ID.String() {
+		return nil
+	}
+	origin, err := s.queries.GetContent(ctx, group)
+	if err != nil || origin.SiteID != content.SiteID.String() || origin.TranslationGroup != "" {
+		return nil
+	}
+	if err := s.queries.SetContentTranslationGroup(ctx, sqlc.SetContentTranslationGroupParams{
+		TranslationGroup: group,
+		ID:               origin.ID,
+	}); err != nil {
+		return fmt.Errorf("cannot link translation: %w", err)
+	}
+	return nil
+}
+
+// siteLanguage returns the site's language, DefaultLanguage when unset.
+func (s *service) siteLanguage(ctx context.Context, siteID uuid.UUID) (string, error) {
+	param, err := s.GetSettingByRefKey(ctx, siteID, LanguageRefKey)
+	if errors.Is(err, ErrNotFound) {
+		return DefaultLanguage, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return siteLanguage(map[string]string{LanguageRefKey: param.Value}), nil
+}
+
+// ContentOutputPath returns the path content is generated at, relative to the
+// site root, following the permalink pattern of its kind or the site's.
+func (s *service) ContentOutputPath(ctx context.Context, content *Content) (string, error) {
+	s.ensureQueries()
+
+	c := *content
+	if c.SectionPath == "" && c.SectionID != uuid.Nil {
+		section, err := s.GetSection(ctx, c.SectionID)
+		if err != nil {
+			return "", fmt.Errorf("cannot get section: %w", err)
+		}
+		c.SectionPath = section.Path
+	}
+
+	pattern, err := s.permalinkPattern(ctx, c.SiteID)
+	if err != nil {
+		return "", err
+	}
+	kinds, err := s.ListContentKinds(ctx, c.SiteID)
+	if err != nil {
+		return "", err
+	}
+	applyContentKinds([]*Content{&c}, kinds)
+	return pattern.contentPath(&c), nil
+}
+
+// GetPublicURL returns the address content is published at, composed like
+// the links of the generated site from its base URL, base path and permalink
+// pattern. Unpublished content gets the address it will have.
+func (s *service) GetPublicURL(ctx context.Context, content *Content) (*PublicURL, error) {
+	s.ensureQueries()
+
+	path, err := s.ContentOutputPath(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]string)
+	for _, refKey := range []string{BaseURLRefKey, BasePathRefKey, TrailingSlashRefKey} {
+		param, err := s.GetSettingByRefKey(ctx, content.SiteID, refKey)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		params[refKey] = param.Value
+	}
+
+	return &PublicURL{
+		URL:         publicURL(params, path),
+		Relative:    siteBaseURL(params) == "",
+		Provisional: content.Status(time.Now()) != ContentStatusPublished,
+	}, nil
+}
+
+// CheckPathCollision returns the sections, content and generated pages that
+// share an output path with the item being saved. excludeID is that item, so
+// it does not collide with itself.
+func (s *service) CheckPathCollision(ctx context.Context, siteID uuid.UUID, path string, excludeID uuid.UUID) ([]*PathCollision, error) {
+	s.ensureQueries()
+
+	path = strings.Trim(path, "/")
+	var collisions []*PathCollision
+	name, reserved, err := s.IsReservedPath(ctx, siteID, path)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		collisions = append(collisions, &PathCollision{Path: path, Kind: PathCollisionReserved, Name: name})
+	}
+
+	sections, err := s.GetSections(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	sectionPaths := make(map[uuid.UUID]string, len(sections))
+	for _, section := range sections {
+		sectionPaths[section.ID] = section.Path
+		if section.ID != excludeID && strings.Trim(section.Path, "/") == path {
+			collisions = append(collisions, &PathCollision{
+				Path: path,
+				Kind: PathCollisionSection,
+				Name: section.Name,
+				Link: "/ssg/get-section?id=" + section.ID.String() + "&site_id=" + siteID.String(),
+			})
+		}
+	}
+
+	pattern, err := s.permalinkPattern(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	kinds, err := s.ListContentKinds(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.GetContentBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content: %w", err)
+	}
+	for _, row := range rows {
+		c := contentFromSQLC(row)
+		if c.ID == excludeID {
+			continue
+		}
+		c.SectionPath = sectionPaths[c.SectionID]
+		applyContentKinds([]*Content{c}, kinds)
+		if pattern.contentPath(c) == path {
+			collisions = append(collisions, &PathCollision{
+				Path: path,
+				Kind: PathCollisionContent,
+				Name: c.Heading,
+				Link: "/ssg/get-content?id=" + c.ID.String() + "&site_id=" + siteID.String(),
+			})
+		}
+	}
+
+	return collisions, nil
+}
+
+// IsReservedPath reports whether generation writes to path itself, and what
+// it writes there. Paths such as tags are always reserved; others, such as
+// archive or sitemap.xml, only while the settings of the site enable the
+// feature writing them.
+func (s *service) IsReservedPath(ctx context.Context, siteID uuid.UUID, path string) (string, bool, error) {
+	settings, err := s.GetSettings(ctx, siteID)
+	if err != nil {
+		return "", false, err
+	}
+	name, ok := reservedPath(withDefaultTimezone(settings, ""), path)
+	return name, ok, nil
+}
+
+// checkSectionPath rejects sections at a reserved path, as generation would
+// overwrite their pages.
+func (s *service) checkSectionPath(ctx context.Context, section *Section) error {
+	name, reserved, err := s.IsReservedPath(ctx, section.SiteID, normalizePath(section.Path))
+	if err != nil {
+		return err
+	}
+	if reserved {
+		return fmt.Errorf("%w: /%s is taken by %s, choose another section path", ErrReservedPath, strings.Trim(section.Path, "/"), name)
+	}
+	return nil
+}
+
+// permalinkPattern returns the site's permalink pattern, or the default one.
+func (s *service) permalinkPattern(ctx context.Context, siteID uuid.UUID) (*PermalinkPattern, error) {
+	loc, err := s.GetTimezone(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{TimezoneRefKey: loc.String()}
+
+	param, err := s.GetSettingByRefKey(ctx, siteID, PermalinkRefKey)
+	if errors.Is(err, ErrNotFound) {
+		return permalinkPattern(params), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	params[PermalinkRefKey] = param.Value
+	return permalinkPattern(params), nil
+}
+
+// GetAdjacentContent returns the published items immediately newer and older
+// than the given content. Navigation stays within sectionID unless the site's
+// ssg.navigation.adjacent.scope param is set to "site".
+func (s *service) GetAdjacentContent(ctx context.Context, sectionID, contentID uuid.UUID) (*Content, *Content, error) {
+	s.ensureQueries()
+
+	content, err := s.GetContent(ctx, contentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := make(map[string]string)
+	if scope, err := s.GetSettingByRefKey(ctx, content.SiteID, "ssg.navigation.adjacent.scope"); err == nil {
+		params[scope.RefKey] = scope.Value
+	}
+
+	contents, err := s.GetAllContentWithMeta(ctx, content.SiteID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newer, older := findAdjacentContent(contents, contentID, adjacentSectionID(params, sectionID))
+	return newer, older, nil
+}
+
+func (s *service) UpdateContent(ctx context.Context, content *Content) error {
+	s.ensureQueries()
+
+	content.UpdatedAt = time.Now()
+
+	var contributorID sql.NullString
+	if content.ContributorID != nil {
+		contributorID = nullString(content.ContributorID.String())
+	}
+
+	if err := s.checkTranslation(ctx, content); err != nil {
+		return err
+	}
+	if err := checkLinkURL(content); err != nil {
+		return err
+	}
+	if err := s.checkApproval(ctx, content); err != nil {
+		return err
+	}
+	old, err := s.GetContent(ctx, content.ID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if err := s.saveRevision(ctx, content); err != nil {
+		return fmt.Errorf("cannot update content: %w", err)
+	}
+
+	imagesMeta := s.buildImagesMeta(ctx, content.SiteID, content.Body)
+
+	params := sqlc.UpdateContentParams{
+		SectionID:         nullString(content.SectionID.String()),
+		ContributorID:     contributorID,
+		ContributorHandle: content.ContributorHandle,
+		AuthorUsername:    content.AuthorUsername,
+		Kind:              nullString(content.Kind),
+		Heading:           content.Heading,
+		Summary:           nullString(content.Summary),
+		Body:              nullString(content.Body),
+		Draft:             nullInt(boolToInt(content.Draft)),
+		Featured:          nullInt(boolToInt(content.Featured)),
+		Series:            nullString(content.Series),
+		SeriesOrder:       nullInt(int64(content.SeriesOrder)),
+		PublishedAt:       nullTime(content.PublishedAt),
+		HeroTitleDark:     nullInt(boolToInt(content.HeroTitleDark)),
+		ImagesMeta:        nullString(imagesMeta),
+		Visibility:        normalizeVisibility(content.Visibility),
+		Lang:              content.Lang,
+		TranslationGroup:  content.TranslationGroup,
+		LinkURL:           content.LinkURL,
+		LayoutID:          nullString(content.LayoutID.String()),
+		UpdatedBy:         nullString(content.UpdatedBy.String()),
+		UpdatedAt:         nullTime(&content.UpdatedAt),
+		ID:                content.ID.String(),
+	}
+
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := qtx.UpdateContent(ctx, params); err != nil {
+		return fmt.Errorf("cannot update content: %w", err)
+	}
+	if old != nil {
+		if err := s.reopenEditedReview(ctx, qtx, old, content); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit content update: %w", err)
+	}
+
+	return s.joinTranslationGroup(ctx, content)
+}
+
+// saveRevision stores the stored text of content as a revision before it is
+// overwritten. Nothing is saved when heading, summary and body are unchanged
+// or when the latest revision is younger than revisionInterval, so frequent
+// autosaves collapse into one revision.
+func (s *service) saveRevision(ctx context.Context, content *Content) error {
+	old, err := s.queries.GetContent(ctx, content.ID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("cannot get content: %w", err)
+	}
+	if old.Heading == content.Heading && old.Summary.String == content.Summary && old.Body.String == content.Body {
+		return nil
+	}
+
+	latest, err := s.queries.GetLatestContentRevision(ctx, old.ID)
+	if err == nil && time.Since(latest.CreatedAt) < revisionInterval {
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("cannot get latest revision: %w", err)
+	}
+
+	_, err = s.queries.CreateContentRevision(ctx, sqlc.CreateContentRevisionParams{
+		ID:        uuid.New().String(),
+		ContentID: old.ID,
+		Heading:   old.Heading,
+		Summary:   old.Summary.String,
+		Body:      old.Body.String,
+		CreatedBy: old.UpdatedBy.String,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create revision: %w", err)
+	}
+
+	err = s.queries.PruneContentRevisions(ctx, sqlc.PruneContentRevisionsParams{
+		ContentID:   old.ID,
+		ContentID_2: old.ID,
+		Limit:       maxRevisions,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot prune revisions: %w", err)
+	}
+	return nil
+}
+
+// ListContentRevisions returns the saved revisions of a content, newest first.
+func (s *service) ListContentRevisions(ctx context.Context, contentID uuid.UUID) ([]*ContentRevision, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.ListContentRevisions(ctx, contentID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot list revisions: %w", err)
+	}
+
+	revisions := make([]*ContentRevision, len(rows))
+	for i, r := range rows {
+		revisions[i] = contentRevisionFromSQLC(r)
+	}
+	return revisions, nil
+}
+
+func (s *service) GetContentRevision(ctx context.Context, id uuid.UUID) (*ContentRevision, error) {
+	s.ensureQueries()
+
+	r, err := s.queries.GetContentRevision(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get revision: %w", err)
+	}
+	return contentRevisionFromSQLC(r), nil
+}
+
+// DiffRevisions compares two versions of a content. uuid.Nil on either side
+// stands for the live content. Both revisions must belong to contentID.
+func (s *service) DiffRevisions(ctx context.Context, contentID, revA, revB uuid.UUID) (*RevisionDiff, error) {
+	s.ensureQueries()
+
+	a, err := s.contentVersion(ctx, contentID, revA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.contentVersion(ctx, contentID, revB)
+	if err != nil {
+		return nil, err
+	}
+	return diffRevisions(a, b), nil
+}
+
+func (s *service) contentVersion(ctx context.Context, contentID, revisionID uuid.UUID) (*ContentRevision, error) {
+	if revisionID != uuid.Nil {
+		rev, err := s.GetContentRevision(ctx, revisionID)
+		if err != nil {
+			return nil, err
+		}
+		if rev.ContentID != contentID {
+			return nil, ErrNotFound
+		}
+		return rev, nil
+	}
+
+	content, err := s.GetContent(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	return &ContentRevision{
+		ContentID: content.ID,
+		Heading:   content.Heading,
+		Summary:   content.Summary,
+		Body:      content.Body,
+		CreatedBy: content.UpdatedBy,
+		CreatedAt: content.UpdatedAt,
+	}, nil
+}
+
+// AcquireEditLock locks content for editing by a user, or extends the lock
+// the user already holds. While another user holds an unexpired lock it
+// returns that lock with ErrContentLocked.
+func (s *service) AcquireEditLock(ctx context.Context, contentID, userID uuid.UUID, userName string) (*EditLock, error) {
+	s.ensureQueries()
+
+	now := time.Now().UTC()
+	acquired, err := s.queries.AcquireContentEditLock(ctx, sqlc.AcquireContentEditLockParams{
+		ContentID:  contentID.String(),
+		UserID:     userID.String(),
+		UserName:   userName,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(editLockTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot acquire edit lock: %w", err)
+	}
+
+	lock, err := s.queries.GetContentEditLock(ctx, contentID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get edit lock: %w", err)
+	}
+	if acquired == 0 {
+		return editLockFromSQLC(lock), ErrContentLocked
+	}
+	return editLockFromSQLC(lock), nil
+}
+
+// GetEditLock returns the unexpired edit lock of content, or ErrNotFound when
+// nobody is editing it.
+func (s *service) GetEditLock(ctx context.Context, contentID uuid.UUID) (*EditLock, error) {
+	s.ensureQueries()
+
+	lock, err := s.queries.GetContentEditLock(ctx, contentID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get edit lock: %w", err)
+	}
+	if !lock.ExpiresAt.After(time.Now()) {
+		return nil, ErrNotFound
+	}
+	return editLockFromSQLC(lock), nil
+}
+
+// ReleaseEditLock removes the edit lock of content if userID holds it.
+func (s *service) ReleaseEditLock(ctx context.Context, contentID, userID uuid.UUID) error {
+	s.ensureQueries()
+
+	err := s.queries.ReleaseContentEditLock(ctx, sqlc.ReleaseContentEditLockParams{
+		ContentID: contentID.String(),
+		UserID:    userID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot release edit lock: %w", err)
+	}
+	return nil
+}
+
+// BreakEditLock removes the edit lock of content whoever holds it.
+func (s *service) BreakEditLock(ctx context.Context, contentID uuid.UUID) error {
+	s.ensureQueries()
+
+	if err := s.queries.DeleteContentEditLock(ctx, contentID.String()); err != nil {
+		return fmt.Errorf("cannot break edit lock: %w", err)
+	}
+	return nil
+}
+
+// MoveContent moves content to another site and section. Linked images are
+// re-homed to the target site, copying their files, tags are remapped by slug
+// (creating missing ones) and the contributor is matched by handle. When the
+// target site has no such contributor the content keeps its author username.
+// Database changes run in a single transaction.
+func (s *service) MoveContent(ctx context.Context, contentID, targetSiteID, targetSectionID uuid.UUID) (*ContentMove, error) {
+	s.ensureQueries()
+
+	content, err := s.queries.GetContent(ctx, contentID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get content: %w", err)
+	}
+
+	source, err := s.GetSite(ctx, contentFromSQLC(content).SiteID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.GetSite(ctx, targetSiteID)
+	if err != nil {
+		return nil, err
+	}
+	section, err := s.GetSection(ctx, targetSectionID)
+	if err != nil {
+		return nil, err
+	}
+	if section.SiteID != targetSiteID {
+		return nil, ErrSectionNotInSite
+	}
+
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	result := &ContentMove{}
+	now := time.Now()
+
+	workspace := NewWorkspace(s.cfg.SSG.SitesBasePath)
+	sourceImages := workspace.GetImagesPath(source.Slug)
+	targetImages := workspace.GetImagesPath(target.Slug)
+	var copied []string
+	committed := false
+	defer func() {
+		if !committed {
+			for _, path := range copied {
+				os.Remove(path)
+			}
+		}
+	}()
+
+	links, err := qtx.GetContentImagesByContentID(ctx, content.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content images: %w", err)
+	}
+	for _, link := range links {
+		img, err := qtx.GetImage(ctx, link.ImageID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get image: %w", err)
+		}
+		if img.SiteID == target.ID.String() {
+			continue
+		}
+
+		existing, err := qtx.GetImageByPath(ctx, sqlc.GetImageByPathParams{SiteID: target.ID.String(), FilePath: img.FilePath})
+		switch {
+		case err == nil:
+			img = existing
+			result.ImagesReused++
+		case errors.Is(err, sql.ErrNoRows):
+			dst := filepath.Join(targetImages, img.FilePath)
+			if _, statErr := os.Stat(dst); os.IsNotExist(statErr) {
+				if err := EnsureDir(dst); err != nil {
+					return nil, fmt.Errorf("cannot create images directory: %w", err)
+				}
+				if err := copyFile(filepath.Join(sourceImages, img.FilePath), dst); err != nil {
+					return nil, fmt.Errorf("cannot copy image %s: %w", img.FilePath, err)
+				}
+				copied = append(copied, dst)
+			}
+
+			image := imageFromSQLC(img)
+			image.ID = uuid.New()
+			image.SiteID = target.ID
+			image.ShortID = uuid.New().String()[:8]
+			image.CreatedAt, image.UpdatedAt = now, now
+			if img, err = qtx.CreateImage(ctx, createImageParams(image)); err != nil {
+				return nil, fmt.Errorf("cannot create image: %w", err)
+			}
+			result.ImagesCopied++
+		default:
+			return nil, fmt.Errorf("cannot get image: %w", err)
+		}
+
+		if err := qtx.UpdateContentImageImageID(ctx, sqlc.UpdateContentImageImageIDParams{ImageID: img.ID, ID: link.ID}); err != nil {
+			return nil, fmt.Errorf("cannot relink image: %w", err)
+		}
+	}
+
+	tags, err := qtx.GetTagsForContent(ctx, content.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get tags: %w", err)
+	}
+	if err := qtx.RemoveAllTagsFromContent(ctx, content.ID); err != nil {
+		return nil, fmt.Errorf("cannot remove tags: %w", err)
+	}
+	for _, t := range tags {
+		tag, err := qtx.GetTagBySlug(ctx, sqlc.GetTagBySlugParams{SiteID: target.ID.String(), Slug: t.Slug})
+		if errors.Is(err, sql.ErrNoRows) {
+			newTag := NewTag(target.ID, t.Name)
+			tag, err = qtx.CreateTag(ctx, sqlc.CreateTagParams{
+				ID:          newTag.ID.String(),
+				SiteID:      newTag.SiteID.String(),
+				ShortID:     nullString(newTag.ShortID),
+				Name:        newTag.Name,
+				Slug:        t.Slug,
+				Description: t.Description,
+				CreatedAt:   nullTime(&newTag.CreatedAt),
+				UpdatedAt:   nullTime(&newTag.UpdatedAt),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("cannot create tag: %w", err)
+			}
+			result.TagsCreated++
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot get tag: %w", err)
+		} else {
+			result.TagsMapped++
+		}
+
+		if err := qtx.AddTagToContent(ctx, sqlc.AddTagToContentParams{
+			ID:        uuid.New().String(),
+			ContentID: content.ID,
+			TagID:     tag.ID,
+			CreatedAt: nullTime(&now),
+		}); err != nil {
+			return nil, fmt.Errorf("cannot add tag: %w", err)
+		}
+	}
+
+	contributorID := content.ContributorID
+	contributorHandle := content.ContributorHandle
+	if content.ContributorHandle != "" && content.SiteID != target.ID.String() {
+		contributor, err := qtx.GetContributorByHandle(ctx, sqlc.GetContributorByHandleParams{SiteID: target.ID.String(), Handle: content.ContributorHandle})
+		switch {
+		case err == nil:
+			contributorID = nullString(contributor.ID)
+		case errors.Is(err, sql.ErrNoRows):
+			result.ContributorDropped = content.ContributorHandle
+			contributorID = sql.NullString{}
+			contributorHandle = ""
+		default:
+			return nil, fmt.Errorf("cannot get contributor: %w", err)
+		}
+	}
+
+	if err := qtx.MoveContent(ctx, sqlc.MoveContentParams{
+		SiteID:            target.ID.String(),
+		SectionID:         nullString(section.ID.String()),
+		ContributorID:     contributorID,
+		ContributorHandle: contributorHandle,
+		AuthorUsername:    content.AuthorUsername,
+		UpdatedBy:         content.UpdatedBy,
+		UpdatedAt:         nullTime(&now),
+		ID:                content.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("cannot move content: %w", err)
+	}
+
+	if err := qtx.UpdateMetaSiteID(ctx, sqlc.UpdateMetaSiteIDParams{SiteID: target.ID.String(), ContentID: content.ID}); err != nil {
+		return nil, fmt.Errorf("cannot update meta: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cannot commit content move: %w", err)
+	}
+	committed = true
+
+	return result, nil
+}
+
+// ReconcileAutosave saves a draft the browser buffered while offline when
+// it is newer than the server copy. A draft started before edits made
+// elsewhere is not saved over them; see reconcileDraft.
+func (s *service) ReconcileAutosave(ctx context.Context, draft AutosaveDraft) (*AutosaveReconcile, error) {
+	content, err := s.GetContent(ctx, draft.ContentID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := reconcileDraft(content, draft)
+	if status == ReconcileSaved {
+		content.Body = draft.Body
+		if draft.Heading != "" {
+			content.Heading = draft.Heading
+		}
+		if draft.UpdatedBy != uuid.Nil {
+			content.UpdatedBy = draft.UpdatedBy
+		}
+		if err := s.UpdateContent(ctx, content); err != nil {
+			return nil, fmt.Errorf("cannot save autosave draft: %w", err)
+		}
+	}
+	return &AutosaveReconcile{Status: status, Content: content}, nil
+}
+
+// FindAndReplace replaces find with replace in the body of every content of
+// a site. Without opts.Apply it only reports the content that matches. When
+// applied, the previous body of each changed content is saved as a revision
+// so the change can be undone, content someone is editing is left alone,
+// and at most opts.Limit items change; the rest are left for another run.
+func (s *service) FindAndReplace(ctx context.Context, siteID uuid.UUID, find, replace string, opts FindReplaceOptions) (*FindReplaceResult, error) {
+	s.ensureQueries()
+
+	if find == "" {
+		return nil, ErrEmptyFind
+	}
+
+	rows, err := s.queries.GetContentBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content: %w", err)
+	}
+
+	result := &FindReplaceResult{Find: find, Replace: replace, Options: opts, Limit: opts.limit()}
+	matcher := newTextMatcher(find, opts)
+	type change struct {
+		content    *Content
+		body       string
+		imagesMeta string
+	}
+	var changes []change
+	for _, row := range rows {
+		content := contentFromSQLC(row)
+		locs := matcher.matches(content.Body)
+		if len(locs) == 0 {
+			continue
+		}
+		match := &FindReplaceMatch{Content: content, Count: len(locs)}
+		matcher.preview(match, content.Body, locs[0])
+		result.Matched++
+		result.TotalMatches += len(locs)
+
+		if !opts.Apply {
+			result.Matches = append(result.Matches, match)
+			continue
+		}
+		if lock, err := s.GetEditLock(ctx, content.ID); err == nil && !lock.HeldBy(opts.UserID) {
+			match.LockedBy = lock.UserName
+			result.Matches = append(result.Matches, match)
+			continue
+		}
+		if len(changes) == result.Limit {
+			result.Remaining++
+			continue
+		}
+		body := matcher.replace(content.Body, replace, locs)
+		changes = append(changes, change{content, body, s.buildImagesMeta(ctx, siteID, body)})
+		result.Matches = append(result.Matches, match)
+	}
+	if len(changes) == 0 {
+		return result, nil
+	}
+
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	now := time.Now()
+	for _, c := range changes {
+		_, err := qtx.CreateContentRevision(ctx, sqlc.CreateContentRevisionParams{
+			ID:        uuid.New().String(),
+			ContentID: c.content.ID.String(),
+			Heading:   c.content.Heading,
+			Summary:   c.content.Summary,
+			Body:      c.content.Body,
+			CreatedBy: c.content.UpdatedBy.String(),
+			CreatedAt: now,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot create revision: %w", err)
+		}
+
+		err = qtx.UpdateContentBody(ctx, sqlc.UpdateContentBodyParams{
+			Body:       nullString(c.body),
+			ImagesMeta: nullString(c.imagesMeta),
+			UpdatedBy:  nullString(opts.UserID.String()),
+			UpdatedAt:  nullTime(&now),
+			ID:         c.content.ID.String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot update content: %w", err)
+		}
+
+		edited := *c.content
+		edited.Body = c.body
+		if err := s.reopenEditedReview(ctx, qtx, c.content, &edited); err != nil {
+			return nil, err
+		}
+
+		err = qtx.PruneContentRevisions(ctx, sqlc.PruneContentRevisionsParams{
+			ContentID:   c.content.ID.String(),
+			ContentID_2: c.content.ID.String(),
+			Limit:       maxRevisions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot prune revisions: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cannot commit find and replace: %w", err)
+	}
+
+	for _, c := range changes {
+		c.content.Body = c.body
+		c.content.UpdatedBy = opts.UserID
+		c.content.UpdatedAt = now
+	}
+	result.Changed = len(changes)
+	return result, nil
+}
+
+func (s *service) DeleteContent(ctx context.Context, id uuid.UUID) error {
+	s.ensureQueries()
+
+	err := s.queries.DeleteContent(ctx, id.String())
+	if err != nil {
+		return fmt.Errorf("cannot delete content: %w", err)
+	}
+
+	return nil
+}
+
+// --- Section Operations ---
+
+func (s *service) CreateSection(ctx context.Context, section *Section) error {
+	s.ensureQueries()
+
+	if err := s.checkSectionPath(ctx, section); err != nil {
+		return err
+	}
+
+	params := sqlc.CreateSectionParams{
+		ID:            section.ID.String(),
+		SiteID:        section.SiteID.String(),
+		ShortID:       nullString(section.ShortID),
+		Name:          section.Name,
+		Description:   nullString(section.Description),
+		Path:          sql.NullString{String: section.Path, Valid: true},
+		LayoutID:      nullString(section.LayoutID.String()),
+		LayoutName:    nullString(section.LayoutName),
+		HeroTitleDark: nullInt(boolToInt(section.HeroTitleDark)),
+		CreatedBy:     nullString(section.CreatedBy.String()),
+		UpdatedBy:     nullString(section.UpdatedBy.String()),
+		CreatedAt:     nullTime(&section.CreatedAt),
+		UpdatedAt:     nullTime(&section.UpdatedAt),
+	}
+
+	_, err := s.queries.CreateSection(ctx, params)
+	if err != nil {
+		return fmt.Errorf("cannot create section: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) GetSection(ctx context.Context, id uuid.UUID) (*Section, error) {
+	s.ensureQueries()
+
+	sqlcSection, err := s.queries.GetSection(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get section: %w", err)
+	}
+
+	return sectionFromSQLC(sqlcSection), nil
+}
+
+func (s *service) GetSectionByPath(ctx context.Context, siteID uuid.UUID, path string) (*Section, error) {
+	s.ensureQueries()
+
+	sqlcSection, err := s.queries.GetSectionByPath(ctx, sqlc.GetSectionByPathParams{
+		SiteID: siteID.String(),
+		Path:   sql.NullString{String: path, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get section by path: %w", err)
+	}
+
+	return sectionFromSQLC(sqlcSection), nil
+}
+
+func (s *service) GetSections(ctx context.Context, siteID uuid.UUID) ([]*Section, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetSectionsWithHeaderImage(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sections: %w", err)
+	}
+
+	sections := make([]*Section, len(rows))
+	for i, row := range rows {
+		section := &Section{
+			ID:     parseUUID(row.ID),
+			SiteID: parseUUID(row.SiteID),
+			Name:   row.Name,
+		}
+		if row.ShortID.Valid {
+			section.ShortID = row.ShortID.String
+		}
+		if row.Description.Valid {
+			section.Description = row.Description.String
+		}
+		if row.Path.Valid {
+			section.Path = row.Path.String
+		}
+		if row.LayoutID.Valid {
+			section.LayoutID = parseUUID(row.LayoutID.String)
+		}
+		if row.LayoutName.Valid {
+			section.LayoutName = row.LayoutName.String
+		}
+		if row.HeaderImagePath.Valid {
+			section.HeaderImageURL = "/images/" + row.HeaderImagePath.String
 		}
 		if row.HeroTitleDark.Valid {
 			section.HeroTitleDark = row.HeroTitleDark.Int64 == 1
 		}
-		if row.CreatedAt.Valid {
-			section.CreatedAt = row.CreatedAt.Time
+		if row.CreatedAt.Valid {
+			section.CreatedAt = row.CreatedAt.Time
+		}
+		if row.UpdatedAt.Valid {
+			section.UpdatedAt = row.UpdatedAt.Time
+		}
+		sections[i] = section
+	}
+
+	return sections, nil
+}
+
+func (s *service) UpdateSection(ctx context.Context, section *Section) error {
+	s.ensureQueries()
+
+	if err := s.checkSectionPath(ctx, section); err != nil {
+		return err
+	}
+
+	params := sqlc.UpdateSectionParams{
+		Name:          section.Name,
+		Description:   nullString(section.Description),
+		Path:          sql.NullString{String: section.Path, Valid: true},
+		LayoutID:      nullString(section.LayoutID.String()),
+		LayoutName:    nullString(section.LayoutName),
+		HeroTitleDark: nullInt(boolToInt(section.HeroTitleDark)),
+		UpdatedBy:     nullString(section.UpdatedBy.String()),
+		UpdatedAt:     nullTime(&section.UpdatedAt),
+		ID:            section.ID.String(),
+	}
+
+	_, err := s.queries.UpdateSection(ctx, params)
+	if err != nil {
+		return fmt.Errorf("cannot update section: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) DeleteSection(ctx context.Context, id uuid.UUID) error {
+	s.ensureQueries()
+
+	err := s.queries.DeleteSection(ctx, id.String())
+	if err != nil {
+		return fmt.Errorf("cannot delete section: %w", err)
+	}
+
+	return nil
+}
+
+// --- Layout Operations ---
+
+func (s *service) CreateLayout(ctx context.Context, layout *Layout) error {
+	s.ensureQueries()
+
+	params := sqlc.CreateLayoutParams{
+		ID:                layout.ID.String(),
+		SiteID:            layout.SiteID.String(),
+		ShortID:           nullString(layout.ShortID),
+		Name:              layout.Name,
+		Description:       nullString(layout.Description),
+		Code:              nullString(layout.Code),
+		Css:               nullString(layout.CSS),
+		ExcludeDefaultCss: nullInt(boolToInt(layout.ExcludeDefaultCSS)),
+		HeaderImageID:     nullString(layout.HeaderImageID.String()),
+		CreatedBy:         nullString(layout.CreatedBy.String()),
+		UpdatedBy:         nullString(layout.UpdatedBy.String()),
+		CreatedAt:         nullTime(&layout.CreatedAt),
+		UpdatedAt:         nullTime(&layout.UpdatedAt),
+	}
+
+	_, err := s.queries.CreateLayout(ctx, params)
+	if err != nil {
+		return fmt.Errorf("cannot create layout: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) GetLayout(ctx context.Context, id uuid.UUID) (*Layout, error) {
+	s.ensureQueries()
+
+	sqlcLayout, err := s.queries.GetLayout(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get layout: %w", err)
+	}
+
+	return layoutFromSQLC(sqlcLayout), nil
+}
+
+func (s *service) GetLayouts(ctx context.Context, siteID uuid.UUID) ([]*Layout, error) {
+	s.ensureQueries()
+
+	sqlcLayouts, err := s.queries.GetLayoutsBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get layouts: %w", err)
+	}
+
+	layouts := make([]*Layout, len(sqlcLayouts))
+	for i, sqlcLayout := range sqlcLayouts {
+		layouts[i] = layoutFromSQLC(sqlcLayout)
+	}
+
+	return layouts, nil
+}
+
+func (s *service) UpdateLayout(ctx context.Context, layout *Layout) error {
+	s.ensureQueries()
+
+	params := sqlc.UpdateLayoutParams{
+		Name:              layout.Name,
+		Description:       nullString(layout.Description),
+		Code:              nullString(layout.Code),
+		Css:               nullString(layout.CSS),
+		ExcludeDefaultCss: nullInt(boolToInt(layout.ExcludeDefaultCSS)),
+		HeaderImageID:     nullString(layout.HeaderImageID.String()),
+		UpdatedBy:         nullString(layout.UpdatedBy.String()),
+		UpdatedAt:         nullTime(&layout.UpdatedAt),
+		ID:                layout.ID.String(),
+	}
+
+	_, err := s.queries.UpdateLayout(ctx, params)
+	if err != nil {
+		return fmt.Errorf("cannot update layout: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) DeleteLayout(ctx context.Context, id uuid.UUID) error {
+	s.ensureQueries()
+
+	err := s.queries.DeleteLayout(ctx, id.String())
+	if err != nil {
+		return fmt.Errorf("cannot delete layout: %w", err)
+	}
+
+	return nil
+}
+
+// ExportLayout returns a layout of the site as a document other sites can
+// import.
+func (s *service) ExportLayout(ctx context.Context, siteID, id uuid.UUID) (*LayoutExport, error) {
+	layout, err := s.GetLayout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if layout.SiteID != siteID {
+		return nil, ErrNotFound
+	}
+	return exportLayout(layout), nil
+}
+
+// ImportLayout creates a layout on the site from an exported one. Its code
+// must parse, and its name must not be used by another layout of the site.
+func (s *service) ImportLayout(ctx context.Context, siteID uuid.UUID, export *LayoutExport, userID uuid.UUID) (*Layout, error) {
+	if err := export.validate(); err != nil {
+		return nil, err
+	}
+
+	layouts, err := s.GetLayouts(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(export.Name)
+	for _, l := range layouts {
+		if strings.EqualFold(strings.TrimSpace(l.Name), name) {
+			return nil, fmt.Errorf("%w: %s", ErrLayoutNameTaken, name)
 		}
-		if row.UpdatedAt.Valid {
-			section.UpdatedAt = row.UpdatedAt.Time
+	}
+
+	layout := NewLayout(siteID, name, export.Description)
+	layout.Code = export.Code
+	layout.CSS = export.CSS
+	layout.ExcludeDefaultCSS = export.ExcludeDefaultCSS
+	layout.CreatedBy = userID
+	layout.UpdatedBy = userID
+	if err := s.CreateLayout(ctx, layout); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// ResolveLayoutForContent returns the layout used to render content: its own
+// layout, then its kind's layout, then its section's layout, then the site
+// default layout, then the built-in layout. References to deleted layouts or
+// sections are skipped.
+func (s *service) ResolveLayoutForContent(ctx context.Context, content *Content) (*Layout, error) {
+	s.ensureQueries()
+
+	contentLayout, err := s.findLayout(ctx, content.LayoutID)
+	if err != nil {
+		return nil, err
+	}
+	if contentLayout != nil {
+		return contentLayout, nil
+	}
+
+	kind, err := s.GetContentKind(ctx, content.SiteID, contentKindName(content))
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if kind != nil {
+		kindLayout, err := s.findLayout(ctx, kind.LayoutID)
+		if err != nil {
+			return nil, err
+		}
+		if kindLayout != nil {
+			return kindLayout, nil
 		}
-		sections[i] = section
 	}
 
-	return sections, nil
+	var sectionLayout *Layout
+	if content.SectionID != uuid.Nil {
+		section, err := s.GetSection(ctx, content.SectionID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if section != nil {
+			sectionLayout, err = s.findLayout(ctx, section.LayoutID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	site, err := s.GetSite(ctx, content.SiteID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	var siteLayout *Layout
+	if site != nil {
+		siteLayout, err = s.findLayout(ctx, site.DefaultLayoutID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resolveLayout(sectionLayout, siteLayout), nil
 }
 
-func (s *service) UpdateSection(ctx context.Context, section *Section) error {
+// findLayout returns the layout with the given ID, or nil when unset or missing.
+func (s *service) findLayout(ctx context.Context, id uuid.UUID) (*Layout, error) {
+	if id == uuid.Nil {
+		return nil, nil
+	}
+	layout, err := s.GetLayout(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return layout, err
+}
+
+// --- Content Kind Operations ---
+
+// ListContentKinds returns the site's kinds: the built-in ones, with any
+// settings saved for them, followed by the custom ones.
+func (s *service) ListContentKinds(ctx context.Context, siteID uuid.UUID) ([]*ContentKind, error) {
 	s.ensureQueries()
 
-	params := sqlc.UpdateSectionParams{
-		Name:          section.Name,
-		Description:   nullString(section.Description),
-		Path:          sql.NullString{String: section.Path, Valid: true},
-		LayoutID:      nullString(section.LayoutID.String()),
-		LayoutName:    nullString(section.LayoutName),
-		HeroTitleDark: nullInt(boolToInt(section.HeroTitleDark)),
-		UpdatedBy:     nullString(section.UpdatedBy.String()),
-		UpdatedAt:     nullTime(&section.UpdatedAt),
-		ID:            section.ID.String(),
+	rows, err := s.queries.GetContentKindsBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content kinds: %w", err)
 	}
 
-	_, err := s.queries.UpdateSection(ctx, params)
+	stored := make([]*ContentKind, len(rows))
+	for i, row := range rows {
+		stored[i] = contentKindFromSQLC(row)
+	}
+	kinds := mergeContentKinds(stored)
+	for _, k := range kinds {
+		k.SiteID = siteID
+	}
+	return kinds, nil
+}
+
+// GetContentKind returns the named kind of the site, ErrNotFound when it is
+// neither built in nor saved.
+func (s *service) GetContentKind(ctx context.Context, siteID uuid.UUID, name string) (*ContentKind, error) {
+	kinds, err := s.ListContentKinds(ctx, siteID)
 	if err != nil {
-		return fmt.Errorf("cannot update section: %w", err)
+		return nil, err
+	}
+	for _, k := range kinds {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// SaveContentKind creates a kind or updates the settings of an existing one,
+// matched by site and name.
+func (s *service) SaveContentKind(ctx context.Context, kind *ContentKind) error {
+	s.ensureQueries()
+
+	if err := kind.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if kind.ID == uuid.Nil {
+		kind.ID = uuid.New()
+		kind.CreatedAt = now
+	}
+	kind.UpdatedAt = now
+
+	err := s.queries.UpsertContentKind(ctx, sqlc.UpsertContentKindParams{
+		ID:        kind.ID.String(),
+		SiteID:    kind.SiteID.String(),
+		Name:      kind.Name,
+		Label:     kind.Label,
+		Permalink: kind.Permalink,
+		Listed:    boolToInt(kind.Listed),
+		Feed:      boolToInt(kind.Feed),
+		Redirect:  boolToInt(kind.Redirect),
+		LayoutID:  nullString(kind.LayoutID.String()),
+		CreatedAt: kind.CreatedAt,
+		UpdatedAt: kind.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot save content kind: %w", err)
+	}
+	return nil
+}
+
+// DeleteContentKind removes a custom kind, or turns a built-in one back to
+// its default settings. Content of a removed kind is published as a post.
+func (s *service) DeleteContentKind(ctx context.Context, siteID uuid.UUID, name string) error {
+	s.ensureQueries()
+
+	err := s.queries.DeleteContentKind(ctx, sqlc.DeleteContentKindParams{
+		SiteID: siteID.String(),
+		Name:   name,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete content kind: %w", err)
+	}
+	return nil
+}
+
+// --- Content Field Operations ---
+
+// GetContentFields returns the custom fields of a content by name.
+func (s *service) GetContentFields(ctx context.Context, contentID uuid.UUID) ([]*ContentField, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetContentFieldsByContentID(ctx, contentID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content fields: %w", err)
+	}
+
+	fields := make([]*ContentField, len(rows))
+	for i, row := range rows {
+		fields[i] = contentFieldFromSQLC(row)
+	}
+	return fields, nil
+}
+
+// SetContentField adds a custom field to a content, or replaces the value and
+// type of the field with the same name.
+func (s *service) SetContentField(ctx context.Context, field *ContentField) error {
+	s.ensureQueries()
+
+	if err := field.Normalize(); err != nil {
+		return err
+	}
+	field.UpdatedAt = time.Now()
+
+	if err := s.queries.UpsertContentField(ctx, contentFieldParams(field)); err != nil {
+		return fmt.Errorf("cannot save content field: %w", err)
+	}
+	return nil
+}
+
+// SetContentFields makes fields the custom fields of a content, removing the
+// ones left out. Fields keeping their name keep their creation time. Nothing
+// is saved when a field is invalid or two share a name.
+func (s *service) SetContentFields(ctx context.Context, contentID uuid.UUID, fields []*ContentField) error {
+	s.ensureQueries()
+
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		f.ContentID = contentID
+		if err := f.Normalize(); err != nil {
+			return err
+		}
+		if keep[f.Name] {
+			return fmt.Errorf("%w %s: the name is used twice", ErrInvalidField, f.Name)
+		}
+		keep[f.Name] = true
+	}
+
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	current, err := qtx.GetContentFieldsByContentID(ctx, contentID.String())
+	if err != nil {
+		return fmt.Errorf("cannot get content fields: %w", err)
+	}
+	existing := make(map[string]*ContentField, len(current))
+	for _, row := range current {
+		f := contentFieldFromSQLC(row)
+		if !keep[f.Name] {
+			err := qtx.DeleteContentField(ctx, sqlc.DeleteContentFieldParams{ContentID: row.ContentID, Name: row.Name})
+			if err != nil {
+				return fmt.Errorf("cannot delete content field: %w", err)
+			}
+			continue
+		}
+		existing[f.Name] = f
+	}
+
+	now := time.Now()
+	for _, f := range fields {
+		if old, ok := existing[f.Name]; ok {
+			f.ID = old.ID
+			f.CreatedAt = old.CreatedAt
+		}
+		f.UpdatedAt = now
+		if err := qtx.UpsertContentField(ctx, contentFieldParams(f)); err != nil {
+			return fmt.Errorf("cannot save content field: %w", err)
+		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit content fields: %w", err)
+	}
+	return nil
+}
+
+func (s *service) DeleteContentField(ctx context.Context, contentID uuid.UUID, name string) error {
+	s.ensureQueries()
+
+	err := s.queries.DeleteContentField(ctx, sqlc.DeleteContentFieldParams{
+		ContentID: contentID.String(),
+		Name:      name,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete content field: %w", err)
+	}
 	return nil
 }
 
-func (s *service) DeleteSection(ctx context.Context, id uuid.UUID) error {
+func contentFieldParams(f *ContentField) sqlc.UpsertContentFieldParams {
+	return sqlc.UpsertContentFieldParams{
+		ID:        f.ID.String(),
+		ContentID: f.ContentID.String(),
+		Name:      f.Name,
+		Type:      f.Type,
+		Value:     f.Value,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+// --- Content Review Operations ---
+
+// GetContentReview returns where content stands in the approval step, or
+// ErrNotFound when it was never submitted.
+func (s *service) GetContentReview(ctx context.Context, contentID uuid.UUID) (*ContentReview, error) {
+	s.ensureQueries()
+
+	row, err := s.queries.GetContentReview(ctx, contentID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get review: %w", err)
+	}
+	return contentReviewFromSQLC(row), nil
+}
+
+// SubmitForReview puts a draft in review on behalf of userID. Content sent
+// back with changes can be submitted again.
+func (s *service) SubmitForReview(ctx context.Context, contentID, userID uuid.UUID) (*ContentReview, error) {
+	content, err := s.GetContent(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.currentReview(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := submitReview(current, content, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return review, s.saveReview(ctx, review)
+}
+
+// AssignReviewer assigns content in review to a user who can review it.
+func (s *service) AssignReviewer(ctx context.Context, contentID, reviewerID uuid.UUID) (*ContentReview, error) {
+	if err := s.checkReviewer(ctx, reviewerID); err != nil {
+		return nil, err
+	}
+	current, err := s.currentReview(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := assignReview(current, reviewerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return review, s.saveReview(ctx, review)
+}
+
+// ApproveContent approves content in review, which lets it be published
+// when the site requires reviews. The comment is optional.
+func (s *service) ApproveContent(ctx context.Context, contentID, reviewerID uuid.UUID, comment string) (*ContentReview, error) {
+	return s.decideReview(ctx, contentID, reviewerID, ReviewApproved, comment)
+}
+
+// RequestChanges sends content in review back to its author with a comment
+// saying what to change.
+func (s *service) RequestChanges(ctx context.Context, contentID, reviewerID uuid.UUID, comment string) (*ContentReview, error) {
+	return s.decideReview(ctx, contentID, reviewerID, ReviewChangesRequested, comment)
+}
+
+func (s *service) decideReview(ctx context.Context, contentID, reviewerID uuid.UUID, status, comment string) (*ContentReview, error) {
+	if err := s.checkReviewer(ctx, reviewerID); err != nil {
+		return nil, err
+	}
+	current, err := s.currentReview(ctx, contentID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := decideReview(current, status, reviewerID, comment, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return review, s.saveReview(ctx, review)
+}
+
+// ListReviewQueue returns the site's content waiting on a review or on the
+// changes asked for, oldest submission first.
+func (s *service) ListReviewQueue(ctx context.Context, siteID uuid.UUID) ([]*ContentReview, error) {
 	s.ensureQueries()
 
-	err := s.queries.DeleteSection(ctx, id.String())
+	rows, err := s.queries.GetOpenContentReviewsBySiteID(ctx, siteID.String())
 	if err != nil {
-		return fmt.Errorf("cannot delete section: %w", err)
+		return nil, fmt.Errorf("cannot get review queue: %w", err)
 	}
-
-	return nil
+	reviews := make([]*ContentReview, len(rows))
+	for i, row := range rows {
+		reviews[i] = openContentReviewFromSQLC(row)
+	}
+	return reviews, nil
 }
 
-// --- Layout Operations ---
-
-func (s *service) CreateLayout(ctx context.Context, layout *Layout) error {
+// ListReviewers returns the active users who can review content, by name.
+func (s *service) ListReviewers(ctx context.Context) ([]*Reviewer, error) {
 	s.ensureQueries()
 
-	params := sqlc.CreateLayoutParams{
-		ID:                layout.ID.String(),
-		SiteID:            layout.SiteID.String(),
-		ShortID:           nullString(layout.ShortID),
-		Name:              layout.Name,
-		Description:       nullString(layout.Description),
-		Code:              nullString(layout.Code),
-		Css:               nullString(layout.CSS),
-		ExcludeDefaultCss: nullInt(boolToInt(layout.ExcludeDefaultCSS)),
-		HeaderImageID:     nullString(layout.HeaderImageID.String()),
-		CreatedBy:         nullString(layout.CreatedBy.String()),
-		UpdatedBy:         nullString(layout.UpdatedBy.String()),
-		CreatedAt:         nullTime(&layout.CreatedAt),
-		UpdatedAt:         nullTime(&layout.UpdatedAt),
-	}
-
-	_, err := s.queries.CreateLayout(ctx, params)
+	users, err := s.queries.ListUsers(ctx)
 	if err != nil {
-		return fmt.Errorf("cannot create layout: %w", err)
+		return nil, fmt.Errorf("cannot list users: %w", err)
+	}
+	var reviewers []*Reviewer
+	for _, u := range users {
+		if u.Status == "active" && isReviewerRole(u.Roles) {
+			reviewers = append(reviewers, &Reviewer{ID: parseUUID(u.ID), Name: u.Name, Email: u.Email})
+		}
 	}
+	sort.Slice(reviewers, func(i, j int) bool { return reviewers[i].Name < reviewers[j].Name })
+	return reviewers, nil
+}
 
-	return nil
+// currentReview returns the review of content, nil when it has none.
+func (s *service) currentReview(ctx context.Context, contentID uuid.UUID) (*ContentReview, error) {
+	review, err := s.GetContentReview(ctx, contentID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return review, err
 }
 
-func (s *service) GetLayout(ctx context.Context, id uuid.UUID) (*Layout, error) {
+// checkReviewer returns ErrNotReviewer unless userID is an active admin or
+// editor.
+func (s *service) checkReviewer(ctx context.Context, userID uuid.UUID) error {
 	s.ensureQueries()
 
-	sqlcLayout, err := s.queries.GetLayout(ctx, id.String())
+	user, err := s.queries.GetUser(ctx, userID.String())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrNotFound
+			return ErrNotReviewer
 		}
-		return nil, fmt.Errorf("cannot get layout: %w", err)
+		return fmt.Errorf("cannot get user: %w", err)
 	}
-
-	return layoutFromSQLC(sqlcLayout), nil
+	if user.Status != "active" || !isReviewerRole(user.Roles) {
+		return ErrNotReviewer
+	}
+	return nil
 }
 
-func (s *service) GetLayouts(ctx context.Context, siteID uuid.UUID) ([]*Layout, error) {
+func (s *service) saveReview(ctx context.Context, review *ContentReview) error {
 	s.ensureQueries()
+	return upsertReview(ctx, s.queries, review)
+}
 
-	sqlcLayouts, err := s.queries.GetLayoutsBySiteID(ctx, siteID.String())
+func upsertReview(ctx context.Context, qtx *sqlc.Queries, review *ContentReview) error {
+	reviewerID := ""
+	if review.ReviewerID != uuid.Nil {
+		reviewerID = review.ReviewerID.String()
+	}
+	err := qtx.UpsertContentReview(ctx, sqlc.UpsertContentReviewParams{
+		ContentID:   review.ContentID.String(),
+		Status:      review.Status,
+		ReviewerID:  reviewerID,
+		SubmittedBy: review.SubmittedBy.String(),
+		Comment:     review.Comment,
+		SubmittedAt: review.SubmittedAt,
+		ReviewedAt:  nullTime(review.ReviewedAt),
+		UpdatedAt:   review.UpdatedAt,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("cannot get layouts: %w", err)
-	}
-
-	layouts := make([]*Layout, len(sqlcLayouts))
-	for i, sqlcLayout := range sqlcLayouts {
-		layouts[i] = layoutFromSQLC(sqlcLayout)
+		return fmt.Errorf("cannot save review: %w", err)
 	}
-
-	return layouts, nil
+	return nil
 }
 
-func (s *service) UpdateLayout(ctx context.Context, layout *Layout) error {
-	s.ensureQueries()
+// checkApproval keeps a draft from being published without an approved
+// review when its site requires one. Content that is already published stays
+// editable.
+func (s *service) checkApproval(ctx context.Context, content *Content) error {
+	if content.Draft {
+		return nil
+	}
+	setting, err := s.GetSettingByRefKey(ctx, content.SiteID, ReviewRequiredRefKey)
+	if err != nil || setting.Value != "true" {
+		return nil
+	}
 
-	params := sqlc.UpdateLayoutParams{
-		Name:              layout.Name,
-		Description:       nullString(layout.Description),
-		Code:              nullString(layout.Code),
-		Css:               nullString(layout.CSS),
-		ExcludeDefaultCss: nullInt(boolToInt(layout.ExcludeDefaultCSS)),
-		HeaderImageID:     nullString(layout.HeaderImageID.String()),
-		UpdatedBy:         nullString(layout.UpdatedBy.String()),
-		UpdatedAt:         nullTime(&layout.UpdatedAt),
-		ID:                layout.ID.String(),
+	old, err := s.queries.GetContent(ctx, content.ID.String())
+	if err == nil && old.Draft.Int64 == 0 {
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("cannot get content: %w", err)
 	}
 
-	_, err := s.queries.UpdateLayout(ctx, params)
+	review, err := s.currentReview(ctx, content.ID)
 	if err != nil {
-		return fmt.Errorf("cannot update layout: %w", err)
+		return err
+	}
+	// An edit saved along with publishing is not covered by the approval.
+	if !review.Approved() || (old.ID != "" && textEdited(contentFromSQLC(old), content)) {
+		return ErrNotApproved
 	}
-
 	return nil
 }
 
-func (s *service) DeleteLayout(ctx context.Context, id uuid.UUID) error {
-	s.ensureQueries()
+// reopenEditedReview returns an approved draft to review when content
+// changes the heading, summary or body it had as old. Published content keeps
+// its review. It runs in the transaction that saves the edit, so a failed
+// save keeps the approval.
+func (s *service) reopenEditedReview(ctx context.Context, qtx *sqlc.Queries, old, content *Content) error {
+	if !old.Draft || !textEdited(old, content) {
+		return nil
+	}
 
-	err := s.queries.DeleteLayout(ctx, id.String())
+	row, err := qtx.GetContentReview(ctx, content.ID.String())
 	if err != nil {
-		return fmt.Errorf("cannot delete layout: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("cannot get review: %w", err)
+	}
+	review := reopenReview(contentReviewFromSQLC(row), time.Now())
+	if review == nil {
+		return nil
 	}
+	return upsertReview(ctx, qtx, review)
+}
 
-	return nil
+// textEdited reports whether content has another heading, summary or body
+// than old.
+func textEdited(old, content *Content) bool {
+	return old.Heading != content.Heading || old.Summary != content.Summary || old.Body != content.Body
 }
 
 // --- Tag Operations ---
@@ -722,15 +3373,17 @@ func (s *service) CreateTag(ctx context.Context, tag *Tag) error {
 	s.ensureQueries()
 
 	params := sqlc.CreateTagParams{
-		ID:        tag.ID.String(),
-		SiteID:    tag.SiteID.String(),
-		ShortID:   nullString(tag.ShortID),
-		Name:      tag.Name,
-		Slug:      tag.Slug,
-		CreatedBy: nullString(tag.CreatedBy.String()),
-		UpdatedBy: nullString(tag.UpdatedBy.String()),
-		CreatedAt: nullTime(&tag.CreatedAt),
-		UpdatedAt: nullTime(&tag.UpdatedAt),
+		ID:            tag.ID.String(),
+		SiteID:        tag.SiteID.String(),
+		ShortID:       nullString(tag.ShortID),
+		Name:          tag.Name,
+		Slug:          tag.Slug,
+		Description:   tag.Description,
+		HeaderImageID: nullString(tag.HeaderImageID.String()),
+		CreatedBy:     nullString(tag.CreatedBy.String()),
+		UpdatedBy:     nullString(tag.UpdatedBy.String()),
+		CreatedAt:     nullTime(&tag.CreatedAt),
+		UpdatedAt:     nullTime(&tag.UpdatedAt),
 	}
 
 	_, err := s.queries.CreateTag(ctx, params)
@@ -792,11 +3445,13 @@ func (s *service) UpdateTag(ctx context.Context, tag *Tag) error {
 	s.ensureQueries()
 
 	params := sqlc.UpdateTagParams{
-		Name:      tag.Name,
-		Slug:      tag.Slug,
-		UpdatedBy: nullString(tag.UpdatedBy.String()),
-		UpdatedAt: nullTime(&tag.UpdatedAt),
-		ID:        tag.ID.String(),
+		Name:          tag.Name,
+		Slug:          tag.Slug,
+		Description:   tag.Description,
+		HeaderImageID: nullString(tag.HeaderImageID.String()),
+		UpdatedBy:     nullString(tag.UpdatedBy.String()),
+		UpdatedAt:     nullTime(&tag.UpdatedAt),
+		ID:            tag.ID.String(),
 	}
 
 	_, err := s.queries.UpdateTag(ctx, params)
@@ -898,13 +3553,99 @@ func (s *service) GetTagsForContent(ctx context.Context, contentID uuid.UUID) ([
 	}
 
 	tags := make([]*Tag, len(sqlcTags))
-	for i, sqlcTag := range sqlcTags {
-		tags[i] = tagFromSQLC(sqlcTag)
+	for i, row := range sqlcTags {
+		tags[i] = tagFromGetTagsForContentRow(row)
 	}
 
 	return tags, nil
 }
 
+// GetContentForTag returns the content carrying a tag, newest first.
+func (s *service) GetContentForTag(ctx context.Context, tagID uuid.UUID) ([]*Content, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetContentForTag(ctx, tagID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get content for tag: %w", err)
+	}
+
+	contents := make([]*Content, len(rows))
+	for i, row := range rows {
+		contents[i] = contentFromSQLC(row)
+	}
+	return contents, nil
+}
+
+// BulkTagContent attaches a tag to the given content, or detaches it when
+// remove is set, in a single transaction. Content that already is in the
+// requested state, is missing or belongs to another site is skipped. It
+// returns how many content items changed.
+func (s *service) BulkTagContent(ctx context.Context, tagID uuid.UUID, contentIDs []uuid.UUID, remove bool) (int, error) {
+	s.ensureQueries()
+
+	tag, err := s.GetTag(ctx, tagID)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+	rows, err := qtx.GetContentForTag(ctx, tagID.String())
+	if err != nil {
+		return 0, fmt.Errorf("cannot get content for tag: %w", err)
+	}
+	tagged := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		tagged[row.ID] = true
+	}
+
+	now := time.Now()
+	changed := 0
+	for _, contentID := range contentIDs {
+		id := contentID.String()
+		if tagged[id] != remove {
+			continue
+		}
+
+		if remove {
+			if err := qtx.RemoveTagFromContent(ctx, sqlc.RemoveTagFromContentParams{ContentID: id, TagID: tagID.String()}); err != nil {
+				return 0, fmt.Errorf("cannot remove tag from content: %w", err)
+			}
+		} else {
+			content, err := qtx.GetContent(ctx, id)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return 0, fmt.Errorf("cannot get content: %w", err)
+			}
+			if content.SiteID != tag.SiteID.String() {
+				continue
+			}
+			if err := qtx.AddTagToContent(ctx, sqlc.AddTagToContentParams{
+				ID:        uuid.New().String(),
+				ContentID: id,
+				TagID:     tagID.String(),
+				CreatedAt: nullTime(&now),
+			}); err != nil {
+				return 0, fmt.Errorf("cannot add tag to content: %w", err)
+			}
+		}
+		tagged[id] = !remove
+		changed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("cannot commit tag changes: %w", err)
+	}
+	return changed, nil
+}
+
 // --- Setting Operations ---
 
 func (s *service) CreateSetting(ctx context.Context, param *Setting) error {
@@ -913,6 +3654,16 @@ func (s *service) CreateSetting(ctx context.Context, param *Setting) error {
 	if err := param.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if err := s.checkTheme(ctx, param); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkHomePage(ctx, param); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	value, err := s.storedValue(param)
+	if err != nil {
+		return fmt.Errorf("cannot store param %s: %w", param.Name, err)
+	}
 
 	params := sqlc.CreateSettingParams{
 		ID:          param.ID.String(),
@@ -920,7 +3671,7 @@ func (s *service) CreateSetting(ctx context.Context, param *Setting) error {
 		ShortID:     nullString(param.ShortID),
 		Name:        param.Name,
 		Description: nullString(param.Description),
-		Value:       nullString(param.Value),
+		Value:       nullString(value),
 		RefKey:      nullString(param.RefKey),
 		Category:    nullString(param.Category),
 		Position:    nullInt(int64(param.Position)),
@@ -928,13 +3679,14 @@ func (s *service) CreateSetting(ctx context.Context, param *Setting) error {
 		Type:        nullString(param.Type),
 		Constraints: nullString(param.Constraints),
 		UiControl:   nullString(param.UIControl),
+		Secret:      nullInt(boolToInt(param.Secret)),
 		CreatedBy:   nullString(param.CreatedBy.String()),
 		UpdatedBy:   nullString(param.UpdatedBy.String()),
 		CreatedAt:   nullTime(&param.CreatedAt),
 		UpdatedAt:   nullTime(&param.UpdatedAt),
 	}
 
-	_, err := s.queries.CreateSetting(ctx, params)
+	_, err = s.queries.CreateSetting(ctx, params)
 	if err != nil {
 		return fmt.Errorf("cannot create param: %w", err)
 	}
@@ -953,7 +3705,11 @@ func (s *service) GetSetting(ctx context.Context, id uuid.UUID) (*Setting, error
 		return nil, fmt.Errorf("cannot get param: %w", err)
 	}
 
-	return settingFromSQLC(sqlcParam), nil
+	param := settingFromSQLC(sqlcParam)
+	if err := s.openSetting(param); err != nil {
+		return nil, err
+	}
+	return param, nil
 }
 
 func (s *service) GetSettingByName(ctx context.Context, siteID uuid.UUID, name string) (*Setting, error) {
@@ -970,7 +3726,11 @@ func (s *service) GetSettingByName(ctx context.Context, siteID uuid.UUID, name s
 		return nil, fmt.Errorf("cannot get param by name: %w", err)
 	}
 
-	return settingFromSQLC(sqlcParam), nil
+	param := settingFromSQLC(sqlcParam)
+	if err := s.openSetting(param); err != nil {
+		return nil, err
+	}
+	return param, nil
 }
 
 func (s *service) GetSettingByRefKey(ctx context.Context, siteID uuid.UUID, refKey string) (*Setting, error) {
@@ -987,7 +3747,61 @@ func (s *service) GetSettingByRefKey(ctx context.Context, siteID uuid.UUID, refK
 		return nil, fmt.Errorf("cannot get param by ref key: %w", err)
 	}
 
-	return settingFromSQLC(sqlcParam), nil
+	param := settingFromSQLC(sqlcParam)
+	if err := s.openSetting(param); err != nil {
+		return nil, err
+	}
+	return param, nil
+}
+
+// GetBaseURL returns the site's normalized base URL, without a trailing
+// slash, or an empty string when none is configured.
+func (s *service) GetBaseURL(ctx context.Context, siteID uuid.UUID) (string, error) {
+	s.ensureQueries()
+
+	param, err := s.GetSettingByRefKey(ctx, siteID, BaseURLRefKey)
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return NormalizeBaseURL(param.Value)
+}
+
+// GetTimezone returns the site's timezone, falling back to the configured
+// default and then to UTC.
+func (s *service) GetTimezone(ctx context.Context, siteID uuid.UUID) (*time.Location, error) {
+	s.ensureQueries()
+
+	name := s.cfg.SSG.Timezone
+	param, err := s.GetSettingByRefKey(ctx, siteID, TimezoneRefKey)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && strings.TrimSpace(param.Value) != "" {
+		name = param.Value
+	}
+	loc, err := LoadTimezone(name)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// IsFeatureEnabled reports whether the feature flag key, e.g.
+// FeatureContributors, is on for the site. Flags without a setting are on.
+func (s *service) IsFeatureEnabled(ctx context.Context, siteID uuid.UUID, key string) (bool, error) {
+	s.ensureQueries()
+
+	param, err := s.GetSettingByRefKey(ctx, siteID, key)
+	if errors.Is(err, ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	return strings.TrimSpace(param.Value) != "false", nil
 }
 
 func (s *service) GetSettings(ctx context.Context, siteID uuid.UUID) ([]*Setting, error) {
@@ -1001,22 +3815,156 @@ func (s *service) GetSettings(ctx context.Context, siteID uuid.UUID) ([]*Setting
 	params := make([]*Setting, len(sqlcParams))
 	for i, sqlcParam := range sqlcParams {
 		params[i] = settingFromSQLC(sqlcParam)
+		// A secret that cannot be decrypted reads as empty, so generation
+		// and publishing never use the ciphertext as a credential.
+		if err := s.openSetting(params[i]); err != nil {
+			s.log.Errorf("Cannot read secret setting %v", err)
+			params[i].Value = ""
+		}
 	}
 
 	return params, nil
 }
 
+// GetSettingsByCategory returns the settings of a site grouped by category,
+// in the order the settings page lists them.
+func (s *service) GetSettingsByCategory(ctx context.Context, siteID uuid.UUID) ([]*SettingCategory, error) {
+	settings, err := s.GetSettings(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	return groupSettings(settings), nil
+}
+
+// ReorderSettings gives the settings of a category the positions of their
+// order in ids. ids must list every setting of the category once, or
+// ErrSettingOrder is returned.
+func (s *service) ReorderSettings(ctx context.Context, siteID uuid.UUID, category string, ids []uuid.UUID) error {
+	s.ensureQueries()
+
+	categories, err := s.GetSettingsByCategory(ctx, siteID)
+	if err != nil {
+		return err
+	}
+	var group *SettingCategory
+	for _, c := range categories {
+		if c.Name == settingCategoryName(category) {
+			group = c
+			break
+		}
+	}
+	if group == nil {
+		return ErrNotFound
+	}
+
+	pending := make(map[uuid.UUID]bool, len(group.Settings))
+	for _, setting := range group.Settings {
+		pending[setting.ID] = true
+	}
+	if len(ids) != len(pending) {
+		return ErrSettingOrder
+	}
+	for _, id := range ids {
+		if !pending[id] {
+			return ErrSettingOrder
+		}
+		delete(pending, id)
+	}
+
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := s.queries.WithTx(tx)
+
+	now := time.Now()
+	for i, id := range ids {
+		err := qtx.UpdateSettingPosition(ctx, sqlc.UpdateSettingPositionParams{
+			Position:  nullInt(int64(i + 1)),
+			UpdatedAt: nullTime(&now),
+			ID:        id.String(),
+			SiteID:    siteID.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("cannot update setting position: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit transaction: %w", err)
+	}
+	return nil
+}
+
+// checkTheme makes sure a theme setting names a theme the site has.
+func (s *service) checkTheme(ctx context.Context, param *Setting) error {
+	if param.RefKey != ThemeRefKey || s.htmlGen == nil {
+		return nil
+	}
+	site, err := s.GetSite(ctx, param.SiteID)
+	if err != nil {
+		return err
+	}
+	_, err = s.htmlGen.LoadTheme(site.Slug, param.Value)
+	return err
+}
+
+// checkHomePage makes sure the home page settings point at content of the
+// site: the content setting when it is set, and the content already chosen
+// when page mode is turned on.
+func (s *service) checkHomePage(ctx context.Context, param *Setting) error {
+	value := strings.TrimSpace(param.Value)
+	switch {
+	case param.RefKey == HomeContentRefKey && value != "":
+	case param.RefKey == HomeModeRefKey && value == HomeModePage:
+		setting, err := s.GetSettingByRefKey(ctx, param.SiteID, HomeContentRefKey)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		value = ""
+		if setting != nil {
+			value = strings.TrimSpace(setting.Value)
+		}
+	default:
+		return nil
+	}
+
+	if value == "" {
+		return fmt.Errorf("%w: set the home page content before page mode", ErrHomeContent)
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a content ID", ErrHomeContent, value)
+	}
+	content, err := s.GetContent(ctx, id)
+	if errors.Is(err, ErrNotFound) || (err == nil && content.SiteID != param.SiteID) {
+		return fmt.Errorf("%w: %s", ErrHomeContent, value)
+	}
+	return err
+}
+
 func (s *service) UpdateSetting(ctx context.Context, param *Setting) error {
 	s.ensureQueries()
 
 	if err := param.Validate(); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if err := s.checkTheme(ctx, param); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if err := s.checkHomePage(ctx, param); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	value, err := s.storedValue(param)
+	if err != nil {
+		return fmt.Errorf("cannot store param %s: %w", param.Name, err)
+	}
 
 	params := sqlc.UpdateSettingParams{
 		Name:        param.Name,
 		Description: nullString(param.Description),
-		Value:       nullString(param.Value),
+		Value:       nullString(value),
 		RefKey:      nullString(param.RefKey),
 		Category:    nullString(param.Category),
 		Position:    nullInt(int64(param.Position)),
@@ -1024,12 +3972,13 @@ func (s *service) UpdateSetting(ctx context.Context, param *Setting) error {
 		Type:        nullString(param.Type),
 		Constraints: nullString(param.Constraints),
 		UiControl:   nullString(param.UIControl),
+		Secret:      nullInt(boolToInt(param.Secret)),
 		UpdatedBy:   nullString(param.UpdatedBy.String()),
 		UpdatedAt:   nullTime(&param.UpdatedAt),
 		ID:          param.ID.String(),
 	}
 
-	_, err := s.queries.UpdateSetting(ctx, params)
+	_, err = s.queries.UpdateSetting(ctx, params)
 	if err != nil {
 		return fmt.Errorf("cannot update param: %w", err)
 	}
@@ -1053,7 +4002,40 @@ func (s *service) DeleteSetting(ctx context.Context, id uuid.UUID) error {
 func (s *service) CreateImage(ctx context.Context, image *Image) error {
 	s.ensureQueries()
 
-	params := sqlc.CreateImageParams{
+	_, err := s.queries.CreateImage(ctx, createImageParams(image))
+	if err != nil {
+		return fmt.Errorf("cannot create image: %w", err)
+	}
+
+	return nil
+}
+
+// CreateImages creates several image records in a single transaction:
+// either all of them are stored or none is.
+func (s *service) CreateImages(ctx context.Context, images []*Image) error {
+	s.ensureQueries()
+
+	tx, err := s.dbProvider.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+	for _, image := range images {
+		if _, err := qtx.CreateImage(ctx, createImageParams(image)); err != nil {
+			return fmt.Errorf("cannot create image %s: %w", image.FileName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit images: %w", err)
+	}
+	return nil
+}
+
+func createImageParams(image *Image) sqlc.CreateImageParams {
+	return sqlc.CreateImageParams{
 		ID:             image.ID.String(),
 		SiteID:         image.SiteID.String(),
 		ShortID:        nullString(image.ShortID),
@@ -1065,18 +4047,12 @@ func (s *service) CreateImage(ctx context.Context, image *Image) error {
 		AttributionUrl: nullString(image.AttributionURL),
 		Width:          nullInt(int64(image.Width)),
 		Height:         nullInt(int64(image.Height)),
+		Stock:          boolToInt(image.Stock),
 		CreatedBy:      nullString(image.CreatedBy.String()),
 		UpdatedBy:      nullString(image.UpdatedBy.String()),
 		CreatedAt:      nullTime(&image.CreatedAt),
 		UpdatedAt:      nullTime(&image.UpdatedAt),
 	}
-
-	_, err := s.queries.CreateImage(ctx, params)
-	if err != nil {
-		return fmt.Errorf("cannot create image: %w", err)
-	}
-
-	return nil
 }
 
 func (s *service) GetImage(ctx context.Context, id uuid.UUID) (*Image, error) {
@@ -1093,20 +4069,76 @@ func (s *service) GetImage(ctx context.Context, id uuid.UUID) (*Image, error) {
 	return imageFromSQLC(sqlcImage), nil
 }
 
-func (s *service) GetImages(ctx context.Context, siteID uuid.UUID) ([]*Image, error) {
-	s.ensureQueries()
+func (s *service) GetImages(ctx context.Context, siteID uuid.UUID) ([]*Image, error) {
+	s.ensureQueries()
+
+	sqlcImages, err := s.queries.GetImagesBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get images: %w", err)
+	}
+
+	images := make([]*Image, len(sqlcImages))
+	for i, sqlcImage := range sqlcImages {
+		images[i] = imageFromSQLC(sqlcImage)
+	}
+
+	return images, nil
+}
+
+// GetImagesWithPagination returns a page of the site's images, newest first,
+// and the number of images matching the filter.
+func (s *service) GetImagesWithPagination(ctx context.Context, siteID uuid.UUID, offset, limit int, filter ImageFilter) ([]*Image, int, error) {
+	s.ensureQueries()
+
+	search := ""
+	if filter.Search != "" {
+		search = "%" + filter.Search + "%"
+	}
+
+	collectionID := ""
+	if filter.CollectionID != uuid.Nil {
+		collectionID = filter.CollectionID.String()
+	}
 
-	sqlcImages, err := s.queries.GetImagesBySiteID(ctx, siteID.String())
+	rows, err := s.queries.ListFilteredImages(ctx, sqlc.ListFilteredImagesParams{
+		SiteID:       siteID.String(),
+		Search:       search,
+		Usage:        filter.Usage,
+		CollectionID: collectionID,
+		Limit:        int64(limit),
+		Offset:       int64(offset),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("cannot get images: %w", err)
+		return nil, 0, fmt.Errorf("cannot get images: %w", err)
 	}
 
-	images := make([]*Image, len(sqlcImages))
-	for i, sqlcImage := range sqlcImages {
-		images[i] = imageFromSQLC(sqlcImage)
+	total, err := s.queries.CountFilteredImages(ctx, sqlc.CountFilteredImagesParams{
+		SiteID:       siteID.String(),
+		Search:       search,
+		Usage:        filter.Usage,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot count images: %w", err)
 	}
 
-	return images, nil
+	images := make([]*Image, len(rows))
+	for i, row := range rows {
+		images[i] = imageFromSQLC(row)
+	}
+
+	return images, int(total), nil
+}
+
+// SearchImages returns a page of the site's images whose file name, title or
+// alt text contains query, newest first, and the number of matches. An empty
+// query matches every image, and collectionID, unless nil, narrows the search
+// to one collection.
+func (s *service) SearchImages(ctx context.Context, siteID uuid.UUID, query string, collectionID uuid.UUID, offset, limit int) ([]*Image, int, error) {
+	return s.GetImagesWithPagination(ctx, siteID, offset, limit, ImageFilter{
+		Search:       strings.TrimSpace(query),
+		CollectionID: collectionID,
+	})
 }
 
 func (s *service) GetImageByPath(ctx context.Context, siteID uuid.UUID, filePath string) (*Image, error) {
@@ -1341,15 +4373,18 @@ func (s *service) UpdateImage(ctx context.Context, image *Image) error {
 	s.ensureQueries()
 
 	params := sqlc.UpdateImageParams{
-		FileName:  image.FileName,
-		FilePath:  image.FilePath,
-		AltText:   nullString(image.AltText),
-		Title:     nullString(image.Title),
-		Width:     nullInt(int64(image.Width)),
-		Height:    nullInt(int64(image.Height)),
-		UpdatedBy: nullString(image.UpdatedBy.String()),
-		UpdatedAt: nullTime(&image.UpdatedAt),
-		ID:        image.ID.String(),
+		FileName:       image.FileName,
+		FilePath:       image.FilePath,
+		AltText:        nullString(image.AltText),
+		Title:          nullString(image.Title),
+		Attribution:    nullString(image.Attribution),
+		AttributionUrl: nullString(image.AttributionURL),
+		Width:          nullInt(int64(image.Width)),
+		Height:         nullInt(int64(image.Height)),
+		Stock:          boolToInt(image.Stock),
+		UpdatedBy:      nullString(image.UpdatedBy.String()),
+		UpdatedAt:      nullTime(&image.UpdatedAt),
+		ID:             image.ID.String(),
 	}
 
 	_, err := s.queries.UpdateImage(ctx, params)
@@ -1371,6 +4406,404 @@ func (s *service) DeleteImage(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// GetImageUsage returns the contents and sections linking the image, the
+// layouts using it as header and the contents whose body references its path.
+func (s *service) GetImageUsage(ctx context.Context, imageID uuid.UUID) (*ImageUsage, error) {
+	s.ensureQueries()
+
+	image, err := s.GetImage(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &ImageUsage{}
+	contents, err := s.queries.GetContentUsingImage(ctx, imageID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get contents using image: %w", err)
+	}
+	for _, c := range contents {
+		usage.Contents = append(usage.Contents, ImageUse{ID: parseUUID(c.ID), Name: c.Heading, Header: c.IsHeader.Int64 == 1})
+	}
+
+	sections, err := s.queries.GetSectionsUsingImage(ctx, imageID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sections using image: %w", err)
+	}
+	for _, sec := range sections {
+		usage.Sections = append(usage.Sections, ImageUse{ID: parseUUID(sec.ID), Name: sec.Name, Header: sec.IsHeader.Int64 == 1})
+	}
+
+	layouts, err := s.queries.GetLayoutsUsingImage(ctx, nullString(imageID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get layouts using image: %w", err)
+	}
+	for _, l := range layouts {
+		usage.Layouts = append(usage.Layouts, ImageUse{ID: parseUUID(l.ID), Name: l.Name, Header: true})
+	}
+
+	tags, err := s.queries.GetTagsUsingImage(ctx, nullString(imageID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get tags using image: %w", err)
+	}
+	for _, t := range tags {
+		usage.Tags = append(usage.Tags, ImageUse{ID: parseUUID(t.ID), Name: t.Name, Header: true})
+	}
+
+	bodies, err := s.queries.GetContentBySiteID(ctx, image.SiteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get contents: %w", err)
+	}
+	for _, c := range bodies {
+		if strings.Contains(c.Body.String, image.FilePath) {
+			usage.Bodies = append(usage.Bodies, ImageUse{ID: parseUUID(c.ID), Name: c.Heading})
+		}
+	}
+
+	return usage, nil
+}
+
+// FindOrphanedImages returns the site images that are not linked to any
+// content, section, layout or tag and whose file name does not appear in any
+// content body, images meta, section description, layout code or setting.
+func (s *service) FindOrphanedImages(ctx context.Context, siteID uuid.UUID) ([]*Image, error) {
+	s.ensureQueries()
+
+	unlinked, err := s.queries.GetUnlinkedImagesBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get unlinked images: %w", err)
+	}
+	if len(unlinked) == 0 {
+		return nil, nil
+	}
+
+	texts, err := s.imageReferenceTexts(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []*Image
+	for _, i := range unlinked {
+		if referencesFile(texts, i.FilePath) {
+			continue
+		}
+		orphans = append(orphans, imageFromSQLC(i))
+	}
+	return orphans, nil
+}
+
+// PurgeOrphanedImages deletes the records and files of the given images.
+// Each one is checked again and skipped if it is no longer orphaned.
+func (s *service) PurgeOrphanedImages(ctx context.Context, siteID uuid.UUID, ids []uuid.UUID) (*OrphanPurge, error) {
+	s.ensureQueries()
+
+	site, err := s.GetSite(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans, err := s.FindOrphanedImages(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*Image, len(orphans))
+	for _, o := range orphans {
+		byID[o.ID] = o
+	}
+
+	imagesPath := NewWorkspace(s.cfg.SSG.SitesBasePath).GetImagesPath(site.Slug)
+	result := &OrphanPurge{}
+	for _, id := range ids {
+		image, ok := byID[id]
+		if !ok {
+			result.Skipped++
+			continue
+		}
+
+		if err := s.queries.DeleteImage(ctx, image.ID.String()); err != nil {
+			return result, fmt.Errorf("cannot delete image: %w", err)
+		}
+		result.Deleted++
+
+		filePath := filepath.Join(imagesPath, image.FilePath)
+		if info, err := os.Stat(filePath); err == nil {
+			if err := os.Remove(filePath); err != nil {
+				s.log.Errorf("Cannot delete image file %s: %v", filePath, err)
+				continue
+			}
+			result.ReclaimedBytes += info.Size()
+		}
+	}
+
+	return result, nil
+}
+
+// imageReferenceTexts collects every stored text that may reference an image by path.
+func (s *service) imageReferenceTexts(ctx context.Context, siteID uuid.UUID) ([]string, error) {
+	var texts []string
+
+	contents, err := s.queries.GetContentBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get contents: %w", err)
+	}
+	for _, c := range contents {
+		texts = append(texts, c.Body.String, c.Summary.String, c.ImagesMeta.String)
+	}
+
+	sections, err := s.queries.GetSectionsBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sections: %w", err)
+	}
+	for _, sec := range sections {
+		texts = append(texts, sec.Description.String)
+	}
+
+	layouts, err := s.queries.GetLayoutsBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get layouts: %w", err)
+	}
+	for _, l := range layouts {
+		texts = append(texts, l.Code.String, l.Css.String)
+	}
+
+	settings, err := s.queries.GetSettingsBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get params: %w", err)
+	}
+	for _, p := range settings {
+		texts = append(texts, p.Value.String)
+	}
+
+	return texts, nil
+}
+
+// referencesFile reports whether any text mentions fileName. Uploaded file
+// names carry a random suffix, so a plain substring match is specific enough
+// and errs on the side of keeping the image.
+func referencesFile(texts []string, fileName string) bool {
+	for _, t := range texts {
+		if strings.Contains(t, fileName) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Collection Operations ---
+
+func (s *service) CreateCollection(ctx context.Context, collection *Collection) error {
+	s.ensureQueries()
+
+	if err := collection.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.queries.CreateCollection(ctx, sqlc.CreateCollectionParams{
+		ID:        collection.ID.String(),
+		SiteID:    collection.SiteID.String(),
+		ShortID:   nullString(collection.ShortID),
+		Name:      collection.Name,
+		Slug:      collection.Slug,
+		CreatedBy: nullString(collection.CreatedBy.String()),
+		UpdatedBy: nullString(collection.UpdatedBy.String()),
+		CreatedAt: nullTime(&collection.CreatedAt),
+		UpdatedAt: nullTime(&collection.UpdatedAt),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return ErrSlugTaken
+		}
+		return fmt.Errorf("cannot create collection: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) GetCollection(ctx context.Context, id uuid.UUID) (*Collection, error) {
+	s.ensureQueries()
+
+	sqlcCollection, err := s.queries.GetCollection(ctx, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("cannot get collection: %w", err)
+	}
+
+	return collectionFromSQLC(sqlcCollection), nil
+}
+
+// GetCollections returns the collections of a site by name, with the number
+// of images in each.
+func (s *service) GetCollections(ctx context.Context, siteID uuid.UUID) ([]*Collection, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetCollectionsBySiteID(ctx, siteID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get collections: %w", err)
+	}
+
+	collections := make([]*Collection, len(rows))
+	for i, row := range rows {
+		collections[i] = collectionFromSQLC(sqlc.Collection{
+			ID:        row.ID,
+			SiteID:    row.SiteID,
+			ShortID:   row.ShortID,
+			Name:      row.Name,
+			Slug:      row.Slug,
+			CreatedBy: row.CreatedBy,
+			UpdatedBy: row.UpdatedBy,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+		collections[i].ImageCount = int(row.ImageCount)
+	}
+
+	return collections, nil
+}
+
+func (s *service) UpdateCollection(ctx context.Context, collection *Collection) error {
+	s.ensureQueries()
+
+	if err := collection.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.queries.UpdateCollection(ctx, sqlc.UpdateCollectionParams{
+		Name:      collection.Name,
+		Slug:      collection.Slug,
+		UpdatedBy: nullString(collection.UpdatedBy.String()),
+		UpdatedAt: nullTime(&collection.UpdatedAt),
+		ID:        collection.ID.String(),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint") {
+			return ErrSlugTaken
+		}
+		return fmt.Errorf("cannot update collection: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCollection deletes a collection. Its images stay in the library.
+func (s *service) DeleteCollection(ctx context.Context, id uuid.UUID) error {
+	s.ensureQueries()
+
+	if err := s.queries.DeleteCollection(ctx, id.String()); err != nil {
+		return fmt.Errorf("cannot delete collection: %w", err)
+	}
+
+	return nil
+}
+
+// AddImageToCollection puts an image in a collection. Adding it again is a
+// no-op.
+func (s *service) AddImageToCollection(ctx context.Context, collectionID, imageID uuid.UUID) error {
+	s.ensureQueries()
+
+	err := s.queries.AddImageToCollection(ctx, sqlc.AddImageToCollectionParams{
+		ID:           uuid.New().String(),
+		CollectionID: collectionID.String(),
+		ImageID:      imageID.String(),
+		CreatedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot add image to collection: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) RemoveImageFromCollection(ctx context.Context, collectionID, imageID uuid.UUID) error {
+	s.ensureQueries()
+
+	err := s.queries.RemoveImageFromCollection(ctx, sqlc.RemoveImageFromCollectionParams{
+		CollectionID: collectionID.String(),
+		ImageID:      imageID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot remove image from collection: %w", err)
+	}
+
+	return nil
+}
+
+// SetImageCollections makes collectionIDs the collections of an image, adding
+// and removing it as needed. Collections must belong to the site of the image.
+func (s *service) SetImageCollections(ctx context.Context, imageID uuid.UUID, collectionIDs []uuid.UUID) error {
+	image, err := s.GetImage(ctx, imageID)
+	if err != nil {
+		return err
+	}
+
+	want := make(map[uuid.UUID]bool, len(collectionIDs))
+	for _, id := range collectionIDs {
+		collection, err := s.GetCollection(ctx, id)
+		if err != nil {
+			return err
+		}
+		if collection.SiteID != image.SiteID {
+			return ErrNotFound
+		}
+		want[id] = true
+	}
+
+	current, err := s.GetImageCollections(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	for _, collection := range current {
+		if want[collection.ID] {
+			delete(want, collection.ID)
+			continue
+		}
+		if err := s.RemoveImageFromCollection(ctx, collection.ID, imageID); err != nil {
+			return err
+		}
+	}
+	for id := range want {
+		if err := s.AddImageToCollection(ctx, id, imageID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetImagesByCollection returns the images of a collection, newest first.
+func (s *service) GetImagesByCollection(ctx context.Context, collectionID uuid.UUID) ([]*Image, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetImagesByCollectionID(ctx, collectionID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get collection images: %w", err)
+	}
+
+	images := make([]*Image, len(rows))
+	for i, row := range rows {
+		images[i] = imageFromSQLC(row)
+	}
+
+	return images, nil
+}
+
+// GetImageCollections returns the collections an image is in, by name.
+func (s *service) GetImageCollections(ctx context.Context, imageID uuid.UUID) ([]*Collection, error) {
+	s.ensureQueries()
+
+	rows, err := s.queries.GetCollectionsByImageID(ctx, imageID.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get image collections: %w", err)
+	}
+
+	collections := make([]*Collection, len(rows))
+	for i, row := range rows {
+		collections[i] = collectionFromSQLC(row)
+	}
+
+	return collections, nil
+}
+
 // --- Meta Operations ---
 
 func (s *service) GetMetaByContentID(ctx context.Context, contentID uuid.UUID) (*Meta, error) {
@@ -1390,6 +4823,10 @@ func (s *service) GetMetaByContentID(ctx context.Context, contentID uuid.UUID) (
 func (s *service) CreateMeta(ctx context.Context, meta *Meta) error {
 	s.ensureQueries()
 
+	if err := meta.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
 	params := sqlc.CreateMetaParams{
 		ID:              meta.ID.String(),
 		SiteID:          meta.SiteID.String(),
@@ -1405,6 +4842,8 @@ func (s *service) CreateMeta(ctx context.Context, meta *Meta) error {
 		TableOfContents: nullInt(boolToInt(meta.TableOfContents)),
 		Share:           nullInt(boolToInt(meta.Share)),
 		Comments:        nullInt(boolToInt(meta.Comments)),
+		KeepLinks:       nullInt(boolToInt(meta.KeepLinks)),
+		ShowUpdated:     nullInt(boolToInt(meta.ShowUpdated)),
 		CreatedBy:       nullString(meta.CreatedBy.String()),
 		UpdatedBy:       nullString(meta.UpdatedBy.String()),
 		CreatedAt:       nullTime(&meta.CreatedAt),
@@ -1422,6 +4861,10 @@ func (s *service) CreateMeta(ctx context.Context, meta *Meta) error {
 func (s *service) UpdateMeta(ctx context.Context, meta *Meta) error {
 	s.ensureQueries()
 
+	if err := meta.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
 	params := sqlc.UpdateMetaParams{
 		Summary:         nullString(meta.Summary),
 		Excerpt:         nullString(meta.Excerpt),
@@ -1433,17 +4876,71 @@ func (s *service) UpdateMeta(ctx context.Context, meta *Meta) error {
 		TableOfContents: nullInt(boolToInt(meta.TableOfContents)),
 		Share:           nullInt(boolToInt(meta.Share)),
 		Comments:        nullInt(boolToInt(meta.Comments)),
+		KeepLinks:       nullInt(boolToInt(meta.KeepLinks)),
+		ShowUpdated:     nullInt(boolToInt(meta.ShowUpdated)),
 		UpdatedBy:       nullString(meta.UpdatedBy.String()),
 		UpdatedAt:       nullTime(&meta.UpdatedAt),
 		ID:              meta.ID.String(),
 	}
 
-	_, err := s.queries.UpdateMeta(ctx, params)
-	if err != nil {
-		return fmt.Errorf("cannot update meta: %w", err)
+	_, err := s.queries.UpdateMeta(ctx, params)
+	if err != nil {
+		return fmt.Errorf("cannot update meta: %w", err)
+	}
+
+	return nil
+}
+
+// SetContentRobots sets the robots meta value of the site's content, creating
+// its meta when it has none, and returns the number of items changed. An
+// empty value falls back to the site default. Content of other sites is
+// skipped.
+func (s *service) SetContentRobots(ctx context.Context, siteID uuid.UUID, contentIDs []uuid.UUID, robots string, userID uuid.UUID) (int, error) {
+	robots = strings.TrimSpace(robots)
+	if !validRobots(robots) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRobots, robots)
+	}
+
+	changed := 0
+	for _, id := range contentIDs {
+		content, err := s.GetContent(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+		if content.SiteID != siteID {
+			continue
+		}
+
+		meta, err := s.GetMetaByContentID(ctx, id)
+		if err != nil {
+			return changed, err
+		}
+		if meta == nil {
+			meta = NewMeta(siteID, id)
+			meta.Robots = robots
+			meta.CreatedBy = userID
+			meta.UpdatedBy = userID
+			if err := s.CreateMeta(ctx, meta); err != nil {
+				return changed, err
+			}
+			changed++
+			continue
+		}
+		if meta.Robots == robots {
+			continue
+		}
+		meta.Robots = robots
+		meta.UpdatedBy = userID
+		meta.UpdatedAt = time.Now()
+		if err := s.UpdateMeta(ctx, meta); err != nil {
+			return changed, err
+		}
+		changed++
 	}
-
-	return nil
+	return changed, nil
 }
 
 // --- Helper Functions ---
@@ -1682,6 +5179,19 @@ func contributorWithProfileFromSQLC(row sqlc.ListContributorsWithProfileRow) (*C
 		photoPath = row.ProfilePhotoPath.String
 	}
 
+	// Social links and bio edited in the admin live on the profile.
+	if row.ProfileSocialLinks.Valid && row.ProfileSocialLinks.String != "" && row.ProfileSocialLinks.String != "[]" {
+		var profileLinks []SocialLink
+		if err := json.Unmarshal([]byte(row.ProfileSocialLinks.String), &profileLinks); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal profile social links: %w", err)
+		}
+		socialLinks = profileLinks
+	}
+	bio := row.Bio
+	if bio == "" && row.ProfileBio.Valid {
+		bio = row.ProfileBio.String
+	}
+
 	return &Contributor{
 		ID:          parseUUID(row.ID),
 		SiteID:      parseUUID(row.SiteID),
@@ -1690,7 +5200,7 @@ func contributorWithProfileFromSQLC(row sqlc.ListContributorsWithProfileRow) (*C
 		Handle:      row.Handle,
 		Name:        row.Name,
 		Surname:     row.Surname,
-		Bio:         row.Bio,
+		Bio:         bio,
 		SocialLinks: socialLinks,
 		Role:        row.Role,
 		PhotoPath:   photoPath,
@@ -1707,9 +5217,117 @@ func (s *service) GenerateHTMLForSite(ctx context.Context, siteSlug string) erro
 		return fmt.Errorf("cannot get site: %w", err)
 	}
 
+	_, err = s.generateSite(ctx, site, false)
+	return err
+}
+
+// SiteGenerationResult is the outcome of regenerating one site in a batch.
+type SiteGenerationResult struct {
+	Site     *Site
+	Result   *GenerateHTMLResult // nil when generation failed
+	Duration time.Duration
+	Err      error
+}
+
+// GenerateAllSites rebuilds every active site, one after the other, after a
+// template or generator change. Builds are forced, since a change in the
+// generator itself does not show in the build manifest. A failing site is
+// recorded in its result and the rest still run.
+func (s *service) GenerateAllSites(ctx context.Context) ([]*SiteGenerationResult, error) {
+	sites, err := s.ListSites(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []*Site
+	for _, site := range sites {
+		if site.Active {
+			active = append(active, site)
+		}
+	}
+
+	results := make([]*SiteGenerationResult, 0, len(active))
+	for i, site := range active {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		s.log.Infof("Regenerating site %s (%d/%d)", site.Slug, i+1, len(active))
+		started := time.Now()
+		result, err := s.generateSite(ctx, site, true)
+		r := &SiteGenerationResult{Site: site, Result: result, Duration: time.Since(started).Round(time.Millisecond), Err: err}
+		results = append(results, r)
+
+		if err != nil {
+			s.log.Errorf("Regenerating site %s failed after %s: %v", site.Slug, r.Duration, err)
+			continue
+		}
+		if err := s.MarkSiteGenerated(ctx, site.ID, time.Now()); err != nil {
+			s.log.Errorf("Cannot record generation time for site %s: %v", site.Slug, err)
+		}
+		s.log.Infof("Regenerated site %s in %s: %d pages, %d errors", site.Slug, r.Duration, result.PagesGenerated, len(result.Errors))
+	}
+
+	return results, nil
+}
+
+// generateSite loads everything a site's HTML is generated from and runs
+// the generator. Unless force is set, unchanged pages are kept.
+func (s *service) generateSite(ctx context.Context, site *Site, force bool) (*GenerateHTMLResult, error) {
+	in, err := s.loadBuildInputs(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.htmlGen.GenerateHTML(ctx, site, in.contents, in.sections, in.layouts, in.kinds, in.params, in.contributors, in.userAuthors, force)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate HTML: %w", err)
+	}
+
+	return result, nil
+}
+
+// GenerateSiteTar generates the site and returns its output as a tar
+// stream, see HTMLGenerator.GenerateToTar. The caller must close it.
+func (s *service) GenerateSiteTar(ctx context.Context, siteID uuid.UUID) (io.ReadCloser, error) {
+	site, err := s.GetSite(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := s.loadBuildInputs(ctx, site)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := s.htmlGen.GenerateToTar(ctx, site, in.contents, in.sections, in.layouts, in.kinds, in.params, in.contributors, in.userAuthors)
+	if err != nil {
+		return nil, fmt.Errorf("cannot generate HTML: %w", err)
+	}
+
+	if err := s.MarkSiteGenerated(ctx, site.ID, time.Now()); err != nil {
+		s.log.Errorf("Cannot record generation time for site %s: %v", site.Slug, err)
+	}
+	return stream, nil
+}
+
+// buildInputs is everything a site's HTML is generated from.
+type buildInputs struct {
+	contents     []*Content
+	sections     []*Section
+	layouts      []*Layout
+	kinds        []*ContentKind
+	params       []*Setting
+	contributors []*Contributor
+	userAuthors  map[string]*Contributor
+}
+
+// loadBuildInputs loads the generation inputs of a site. Only content and
+// sections are required; the rest fall back to none.
+func (s *service) loadBuildInputs(ctx context.Context, site *Site) (*buildInputs, error) {
 	contents, err := s.GetAllContentWithMeta(ctx, site.ID)
 	if err != nil {
-		return fmt.Errorf("cannot get contents: %w", err)
+		return nil, fmt.Errorf("cannot get contents: %w", err)
 	}
 
 	for _, c := range contents {
@@ -1727,7 +5345,7 @@ func (s *service) GenerateHTMLForSite(ctx context.Context, siteSlug string) erro
 
 	sections, err := s.GetSections(ctx, site.ID)
 	if err != nil {
-		return fmt.Errorf("cannot get sections: %w", err)
+		return nil, fmt.Errorf("cannot get sections: %w", err)
 	}
 
 	layouts, err := s.GetLayouts(ctx, site.ID)
@@ -1735,6 +5353,9 @@ func (s *service) GenerateHTMLForSite(ctx context.Context, siteSlug string) erro
 		layouts = []*Layout{}
 	}
 
+	// Without them content is published with the default kind settings.
+	kinds, _ := s.ListContentKinds(ctx, site.ID)
+
 	params, err := s.GetSettings(ctx, site.ID)
 	if err != nil {
 		params = []*Setting{}
@@ -1745,14 +5366,217 @@ func (s *service) GenerateHTMLForSite(ctx context.Context, siteSlug string) erro
 		contributors = []*Contributor{}
 	}
 
-	userAuthors := s.BuildUserAuthorsMap(ctx, contents, contributors)
+	return &buildInputs{
+		contents:     contents,
+		sections:     sections,
+		layouts:      layouts,
+		kinds:        kinds,
+		params:       params,
+		contributors: contributors,
+		userAuthors:  s.BuildUserAuthorsMap(ctx, contents, contributors),
+	}, nil
+}
+
+// RenderDraftPreview renders the unpublished content (draft or scheduled)
+// whose URL is urlPath, in memory. Generated output never contains such
+// content, so this is the only way to view it. Returns ErrNotFound when no
+// unpublished content has that URL.
+func (s *service) RenderDraftPreview(ctx context.Context, site *Site, urlPath string) ([]byte, error) {
+	s.ensureQueries()
+
+	contents, err := s.GetAllContentWithMeta(ctx, site.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get contents: %w", err)
+	}
 
-	_, err = s.htmlGen.GenerateHTML(ctx, site, contents, sections, layouts, params, contributors, userAuthors)
+	params, err := s.GetSettings(ctx, site.ID)
 	if err != nil {
-		return fmt.Errorf("cannot generate HTML: %w", err)
+		params = []*Setting{}
 	}
+	paramsMap := withDefaultTimezone(params, s.htmlGen.timezone)
+	basePath := s.htmlGen.getAssetPath(paramsMap)
 
-	return nil
+	urlPath = strings.TrimSuffix(urlPath, "/")
+	var draft *Content
+	for _, c := range contents {
+		if !isPublishable(c) && strings.TrimSuffix(s.htmlGen.getContentURL(c, basePath, paramsMap), "/") == urlPath {
+			draft = c
+			break
+		}
+	}
+	if draft == nil {
+		return nil, ErrNotFound
+	}
+
+	out, err := s.renderPreview(ctx, site, draft, contents, params)
+	if err != nil {
+		return nil, err
+	}
+	return injectPreviewBanner(out, previewBanner(draft, paramsMap[TimezoneRefKey], time.Now())), nil
+}
+
+// RenderContentPreview renders a content item of site through its resolved
+// layout, in memory, whatever its status. The page is meant to be shown away
+// from the site, so a <base> element points its relative URLs and images at
+// the site base URL, or at the preview server when the site has none.
+// Returns ErrNotFound when the content is not in site.
+func (s *service) RenderContentPreview(ctx context.Context, site *Site, contentID uuid.UUID) ([]byte, error) {
+	s.ensureQueries()
+
+	contents, err := s.GetAllContentWithMeta(ctx, site.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get contents: %w", err)
+	}
+	var content *Content
+	for _, c := range contents {
+		if c.ID == contentID {
+			content = c
+			break
+		}
+	}
+	if content == nil {
+		return nil, ErrNotFound
+	}
+
+	params, err := s.GetSettings(ctx, site.ID)
+	if err != nil {
+		params = []*Setting{}
+	}
+	paramsMap := withDefaultTimezone(params, s.htmlGen.timezone)
+
+	out, err := s.renderPreview(ctx, site, content, contents, params)
+	if err != nil {
+		return nil, err
+	}
+	out = injectPreviewBanner(out, previewBanner(content, paramsMap[TimezoneRefKey], time.Now()))
+	return injectBaseHref(out, s.previewBaseHref(site, paramsMap)), nil
+}
+
+// renderPreview loads the tags and contributor of content and renders it
+// through its resolved layout, see HTMLGenerator.PreviewLayout.
+func (s *service) renderPreview(ctx context.Context, site *Site, content *Content, contents []*Content, params []*Setting) ([]byte, error) {
+	if tags, err := s.GetTagsForContent(ctx, content.ID); err == nil {
+		content.Tags = tags
+	}
+	if content.ContributorID != nil {
+		if contributor, err := s.GetContributor(ctx, *content.ContributorID); err == nil {
+			content.Contributor = contributor
+		}
+	}
+
+	sections, err := s.GetSections(ctx, site.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get sections: %w", err)
+	}
+
+	layout, err := s.ResolveLayoutForContent(ctx, content)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve layout: %w", err)
+	}
+
+	return s.htmlGen.PreviewLayout(site, layout, content, contents, sections, params)
+}
+
+// previewBaseHref returns where previews of site resolve relative URLs: the
+// site base URL when set, else the site on the local preview server.
+func (s *service) previewBaseHref(site *Site, params map[string]string) string {
+	basePath := siteBasePath(params)
+	if baseURL := siteBaseURL(params); baseURL != "" {
+		return baseURL + basePath
+	}
+	port := ":3000"
+	if s.cfg != nil {
+		if _, p, err := net.SplitHostPort(s.cfg.SSG.PreviewAddr); err == nil && p != "" {
+			port = ":" + p
+		}
+	}
+	return "http://" + site.Slug + ".localhost" + port + basePath
+}
+
+// CompileSeries renders the published content of a series, in series order,
+// as a single book. Returns ErrNotFound when the series has no published content.
+func (s *service) CompileSeries(ctx context.Context, siteID uuid.UUID, series string, format BookFormat) ([]byte, error) {
+	s.ensureQueries()
+
+	contents, err := s.GetAllContentWithMeta(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	var chapters []*Content
+	for _, c := range contents {
+		if c.Series == series && isPublishable(c) {
+			chapters = append(chapters, c)
+		}
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		if chapters[i].SeriesOrder != chapters[j].SeriesOrder {
+			return chapters[i].SeriesOrder < chapters[j].SeriesOrder
+		}
+		return publicationDate(chapters[i]).Before(publicationDate(chapters[j]))
+	})
+
+	return s.compileBook(ctx, siteID, series, chapters, format)
+}
+
+// CompileSection renders the published content of a section, oldest first,
+// as a single book. Returns ErrNotFound when the section has no published content.
+func (s *service) CompileSection(ctx context.Context, siteID, sectionID uuid.UUID, format BookFormat) ([]byte, error) {
+	s.ensureQueries()
+
+	section, err := s.GetSection(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if section.SiteID != siteID {
+		return nil, ErrSectionNotInSite
+	}
+
+	contents, err := s.GetAllContentWithMeta(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	var chapters []*Content
+	for _, c := range contents {
+		if c.SectionID == sectionID && isPublishable(c) {
+			chapters = append(chapters, c)
+		}
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return publicationDate(chapters[i]).Before(publicationDate(chapters[j]))
+	})
+
+	return s.compileBook(ctx, siteID, section.Name, chapters, format)
+}
+
+func (s *service) compileBook(ctx context.Context, siteID uuid.UUID, title string, chapters []*Content, format BookFormat) ([]byte, error) {
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("nothing published in %q: %w", title, ErrNotFound)
+	}
+
+	site, err := s.GetSite(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.GetSettings(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	params := withDefaultTimezone(settings, s.cfg.SSG.Timezone)
+	imagesPath := NewWorkspace(s.cfg.SSG.SitesBasePath).GetImagesPath(site.Slug)
+
+	book, err := newBook(siteID, site.Name, title, chapters, imagesPath, params)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile %q: %w", title, err)
+	}
+	return book.Render(format)
+}
+
+// publicationDate is when content was published, or created while unpublished.
+func publicationDate(c *Content) time.Time {
+	if c.PublishedAt != nil {
+		return *c.PublishedAt
+	}
+	return c.CreatedAt
 }
 
 func (s *service) BuildUserAuthorsMap(ctx context.Context, contents []*Content, contributors []*Contributor) map[string]*Contributor {
@@ -1837,13 +5661,18 @@ func (s *service) buildImagesMeta(ctx context.Context, siteID uuid.UUID, body st
 			continue
 		}
 
+		m := ImageMeta{
+			Width:  int(img.Width.Int64),
+			Height: int(img.Height.Int64),
+		}
 		if img.Attribution.Valid && img.Attribution.String != "" {
-			meta[fullPath] = ImageMeta{
-				Title:          img.Title.String,
-				Alt:            img.AltText.String,
-				Attribution:    img.Attribution.String,
-				AttributionURL: img.AttributionUrl.String,
-			}
+			m.Title = img.Title.String
+			m.Alt = img.AltText.String
+			m.Attribution = img.Attribution.String
+			m.AttributionURL = img.AttributionUrl.String
+		}
+		if m.Attribution != "" || (m.Width > 0 && m.Height > 0) {
+			meta[fullPath] = m
 		}
 	}
 
@@ -2026,14 +5855,12 @@ func (s *service) ImportFile(ctx context.Context, siteID, userID uuid.UUID, file
 	}
 
 	resolvedSectionID := sectionID
-	var typedFM *ImportFrontmatter
 
 	if len(file.Frontmatter) > 0 {
-		fm := ParseImportFrontmatter(file.Frontmatter)
-		typedFM, _, _ = ParseTypedFrontmatter("---\n" + joinFrontmatter(file.Frontmatter) + "\n---\n")
+		fm := importFrontmatter(file)
 
-		if sectionPath, ok := file.Frontmatter["section"]; ok && sectionPath != "" {
-			if section, err := s.GetSectionByPath(ctx, siteID, sectionPath); err == nil {
+		if fm.Section != "" {
+			if section, err := s.GetSectionByPath(ctx, siteID, fm.Section); err == nil {
 				resolvedSectionID = section.ID
 			}
 		}
@@ -2042,37 +5869,9 @@ func (s *service) ImportFile(ctx context.Context, siteID, userID uuid.UUID, file
 		content.UserID = userID
 		content.CreatedBy = userID
 		content.UpdatedBy = userID
+		fm.applyTo(content)
 
-		if typedFM != nil {
-			if typedFM.CreatedAt != nil {
-				content.CreatedAt = *typedFM.CreatedAt
-			}
-			if typedFM.PublishedAt != nil {
-				content.PublishedAt = typedFM.PublishedAt
-			}
-		}
-
-		if fm.ShortID != "" {
-			content.ShortID = fm.ShortID
-		}
-
-		if fm.Summary != "" {
-			content.Summary = fm.Summary
-		}
-		if fm.Kind != "" {
-			content.Kind = fm.Kind
-		}
-		content.Draft = fm.Draft
-		content.Featured = fm.Featured
-		if fm.Series != "" {
-			content.Series = fm.Series
-			content.SeriesOrder = fm.SeriesOrder
-		}
-		if fm.Author != "" {
-			content.AuthorUsername = fm.Author
-		}
 		if fm.Contributor != "" {
-			content.ContributorHandle = fm.Contributor
 			if contributor, err := s.GetContributorByHandle(ctx, siteID, fm.Contributor); err == nil {
 				content.ContributorID = &contributor.ID
 			}
@@ -2081,14 +5880,16 @@ func (s *service) ImportFile(ctx context.Context, siteID, userID uuid.UUID, file
 		if err := s.CreateContent(ctx, content); err != nil {
 			return nil, nil, fmt.Errorf("cannot create content: %w", err)
 		}
-		if typedFM != nil && len(typedFM.Tags) > 0 {
-			for _, tagName := range typedFM.Tags {
-				_ = s.AddTagToContent(ctx, content.ID, tagName, siteID)
+		for _, tagName := range fm.Tags {
+			_ = s.AddTagToContent(ctx, content.ID, tagName, siteID)
+		}
+		if fields := fieldsFromFrontmatter(content.ID, fm.Fields); len(fields) > 0 {
+			if err := s.SetContentFields(ctx, content.ID, fields); err != nil {
+				s.log.Errorf("Cannot import fields of %s: %v", file.Path, err)
 			}
 		}
 
-		if fm.Description != "" || fm.Robots != "" || fm.Keywords != "" ||
-			fm.CanonicalURL != "" || fm.Sitemap != "" || fm.TableOfContents || fm.Comments || fm.Share {
+		if fm.hasMeta() {
 			meta := NewMeta(siteID, content.ID)
 			meta.Description = fm.Description
 			meta.Robots = fm.Robots
@@ -2098,8 +5899,14 @@ func (s *service) ImportFile(ctx context.Context, siteID, userID uuid.UUID, file
 			meta.TableOfContents = fm.TableOfContents
 			meta.Comments = fm.Comments
 			meta.Share = fm.Share
+			meta.KeepLinks = fm.KeepLinks
+			meta.ShowUpdated = fm.ShowUpdated
 			meta.CreatedBy = userID
 			meta.UpdatedBy = userID
+			if meta.Validate() != nil {
+				// Keep the rest of the meta; the page falls back to its own URL.
+				meta.CanonicalURL = ""
+			}
 			_ = s.CreateMeta(ctx, meta)
 		}
 
@@ -2165,6 +5972,64 @@ func (s *service) ImportFile(ctx context.Context, siteID, userID uuid.UUID, file
 	return content, imp, nil
 }
 
+// QuickImport creates a draft from pasted Markdown, see ParseQuickImport. A
+// section path in the frontmatter takes precedence over sectionID, and its
+// tags are added, creating the missing ones. Other frontmatter fields are
+// ignored: the draft is meant to be finished in the editor.
+func (s *service) QuickImport(ctx context.Context, siteID, sectionID, userID uuid.UUID, author, markdown string) (*Content, error) {
+	s.ensureQueries()
+
+	fm, body, err := ParseQuickImport(markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	if fm.Section != "" {
+		if section, err := s.GetSectionByPath(ctx, siteID, fm.Section); err == nil {
+			sectionID = section.ID
+		}
+	}
+
+	content := NewContent(siteID, sectionID, fm.Title, body)
+	content.UserID = userID
+	content.CreatedBy = userID
+	content.UpdatedBy = userID
+	content.AuthorUsername = author
+
+	if err := s.CreateContent(ctx, content); err != nil {
+		return nil, fmt.Errorf("cannot create content: %w", err)
+	}
+	for _, tagName := range fm.Tags {
+		if tagName = strings.TrimSpace(tagName); tagName != "" {
+			_ = s.AddTagToContent(ctx, content.ID, tagName, siteID)
+		}
+	}
+
+	return content, nil
+}
+
+// importFrontmatter returns the typed frontmatter of an import file. Files
+// not in the backup format fall back to the loosely parsed key/value pairs.
+func importFrontmatter(file ImportFile) *ContentFrontmatter {
+	if file.Parsed != nil {
+		return file.Parsed
+	}
+
+	fm := ParseImportFrontmatter(file.Frontmatter)
+	fm.Title = file.Title
+	if typed, _, err := ParseTypedFrontmatter("---\n" + joinFrontmatter(file.Frontmatter) + "\n---\n"); err == nil && typed != nil {
+		fm.Tags = typed.Tags
+		fm.PublishedAt = typed.PublishedAt
+		if typed.CreatedAt != nil {
+			fm.CreatedAt = *typed.CreatedAt
+		}
+		if typed.UpdatedAt != nil {
+			fm.UpdatedAt = *typed.UpdatedAt
+		}
+	}
+	return fm
+}
+
 func joinFrontmatter(fm map[string]string) string {
 	var lines []string
 	for k, v := range fm {
@@ -2176,61 +6041,22 @@ func joinFrontmatter(fm map[string]string) string {
 	return strings.Join(lines, "\n")
 }
 
+// ReimportFile updates imported content from its file. When the content was
+// also edited since the last import, it fails with ErrImportConflict unless
+// force is set; DiffImport shows what would be overwritten.
 func (s *service) ReimportFile(ctx context.Context, importID uuid.UUID, force bool) (*Content, error) {
-	s.ensureQueries()
-
-	// Get the import
-	imp, err := s.GetImport(ctx, importID)
-	if err != nil {
-		return nil, fmt.Errorf("cannot get import: %w", err)
-	}
-
-	if imp.ContentID == nil {
-		return nil, fmt.Errorf("import has no associated content")
-	}
-
-	// Get the content
-	content, err := s.GetContent(ctx, *imp.ContentID)
-	if err != nil {
-		return nil, fmt.Errorf("cannot get content: %w", err)
-	}
-
-	// Re-scan the file
-	scanner := NewImportScanner([]string{})
-	fileInfo, err := scanner.parseFile(imp.FilePath, nil)
+	imp, content, fileInfo, err := s.loadImport(ctx, importID)
 	if err != nil {
-		return nil, fmt.Errorf("cannot re-parse file: %w", err)
+		return nil, err
 	}
 
-	// Check for conflicts
-	if imp.ContentUpdatedAt != nil && imp.FileMtime != nil {
-		if imp.ContentUpdatedAt.After(*imp.FileMtime) && !force {
-			return nil, fmt.Errorf("conflict detected: content was modified in web UI after last import, use force=true to override")
-		}
+	if diff := diffImport(imp, content, fileInfo); diff.Conflict() && !force {
+		return nil, fmt.Errorf("%w: %s", ErrImportConflict, diff.Summary())
 	}
 
-	// Update content
-	content.Heading = fileInfo.Title
-	content.Body = fileInfo.Body
+	applyImportFile(content, fileInfo)
 	content.UpdatedAt = time.Now()
 
-	// Apply frontmatter updates
-	if len(fileInfo.Frontmatter) > 0 {
-		fm := ParseImportFrontmatter(fileInfo.Frontmatter)
-		if fm.Summary != "" {
-			content.Summary = fm.Summary
-		}
-		if fm.Kind != "" {
-			content.Kind = fm.Kind
-		}
-		content.Draft = fm.Draft
-		content.Featured = fm.Featured
-		if fm.Series != "" {
-			content.Series = fm.Series
-			content.SeriesOrder = fm.SeriesOrder
-		}
-	}
-
 	if err := s.UpdateContent(ctx, content); err != nil {
 		return nil, fmt.Errorf("cannot update content: %w", err)
 	}
@@ -2249,3 +6075,40 @@ func (s *service) ReimportFile(ctx context.Context, importID uuid.UUID, force bo
 
 	return content, nil
 }
+
+// DiffImport returns the fields a reimport would change, and which sides
+// changed since the last import.
+func (s *service) DiffImport(ctx context.Context, importID uuid.UUID) (*ImportDiff, error) {
+	imp, content, fileInfo, err := s.loadImport(ctx, importID)
+	if err != nil {
+		return nil, err
+	}
+	return diffImport(imp, content, fileInfo), nil
+}
+
+// loadImport returns an import with its content and its file parsed again.
+func (s *service) loadImport(ctx context.Context, importID uuid.UUID) (*Import, *Content, *ImportFile, error) {
+	s.ensureQueries()
+
+	imp, err := s.GetImport(ctx, importID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot get import: %w", err)
+	}
+
+	if imp.ContentID == nil {
+		return nil, nil, nil, fmt.Errorf("import has no associated content")
+	}
+
+	content, err := s.GetContent(ctx, *imp.ContentID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot get content: %w", err)
+	}
+
+	scanner := NewImportScanner([]string{})
+	fileInfo, err := scanner.parseFile(imp.FilePath, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot re-parse file: %w", err)
+	}
+
+	return imp, content, fileInfo, nil
+}
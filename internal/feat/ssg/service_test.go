@@ -3,7 +3,13 @@ package ssg
 import (
 	"context"
 	"database/sql"
+	"embed"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -549,7 +555,7 @@ func TestServiceGetContentWithPagination(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			contents, total, err := svc.GetContentWithPagination(ctx, site.ID, tt.offset, tt.limit, tt.search)
+			contents, total, err := svc.GetContentWithPagination(ctx, site.ID, tt.offset, tt.limit, ContentFilter{Search: tt.search})
 			if err != nil {
 				t.Errorf("GetContentWithPagination() error = %v", err)
 				return
@@ -564,6 +570,79 @@ func TestServiceGetContentWithPagination(t *testing.T) {
 	}
 }
 
+func TestServiceGetContentWithPaginationFilters(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Filter Site", "filter-site")
+
+	blog := NewSection(site.ID, "Blog", "", "blog")
+	news := NewSection(site.ID, "News", "", "news")
+	svc.CreateSection(ctx, blog)
+	svc.CreateSection(ctx, news)
+	contributor := NewContributor(site.ID, "jane", "Jane", "Doe")
+	svc.CreateContributor(ctx, contributor)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	for i := 0; i < 6; i++ {
+		c := NewContent(site.ID, blog.ID, fmt.Sprintf("Go tip %d", i), "Body")
+		c.Draft = i%2 == 0
+		c.PublishedAt = &past
+		if i == 5 {
+			c.PublishedAt = &future
+		}
+		if i < 3 {
+			c.ContributorID = &contributor.ID
+		}
+		svc.CreateContent(ctx, c)
+		if i%3 == 0 {
+			svc.AddTagToContent(ctx, c.ID, "golang", site.ID)
+		}
+	}
+	other := NewContent(site.ID, news.ID, "Go news", "Body")
+	other.Draft = false
+	other.Visibility = VisibilityUnlisted
+	svc.CreateContent(ctx, other)
+	svc.AddTagToContent(ctx, other.ID, "golang", site.ID)
+
+	tests := []struct {
+		name      string
+		filter    ContentFilter
+		offset    int
+		limit     int
+		wantCount int
+		wantTotal int
+	}{
+		{"no filter", ContentFilter{}, 0, 10, 7, 7},
+		{"section", ContentFilter{SectionID: news.ID}, 0, 10, 1, 1},
+		{"tag", ContentFilter{Tag: "golang"}, 0, 10, 3, 3},
+		{"drafts", ContentFilter{Status: ContentStatusDraft}, 0, 10, 3, 3},
+		{"published", ContentFilter{Status: ContentStatusPublished}, 0, 10, 3, 3},
+		{"scheduled", ContentFilter{Status: ContentStatusScheduled}, 0, 10, 1, 1},
+		{"contributor", ContentFilter{ContributorID: contributor.ID}, 0, 10, 3, 3},
+		{"public", ContentFilter{Visibility: VisibilityPublic}, 0, 10, 6, 6},
+		{"unlisted", ContentFilter{Visibility: VisibilityUnlisted}, 0, 10, 1, 1},
+		{"search and section", ContentFilter{Search: "tip", SectionID: news.ID}, 0, 10, 0, 0},
+		{"search, tag and status", ContentFilter{Search: "Go", Tag: "golang", Status: ContentStatusPublished}, 0, 10, 2, 2},
+		{"filtered second page", ContentFilter{Search: "tip", SectionID: blog.ID}, 4, 4, 2, 6},
+		{"unknown tag", ContentFilter{Tag: "rust"}, 0, 10, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents, total, err := svc.GetContentWithPagination(ctx, site.ID, tt.offset, tt.limit, tt.filter)
+			if err != nil {
+				t.Fatalf("GetContentWithPagination() error = %v", err)
+			}
+			if len(contents) != tt.wantCount || total != tt.wantTotal {
+				t.Errorf("got %d contents of %d, want %d of %d", len(contents), total, tt.wantCount, tt.wantTotal)
+			}
+		})
+	}
+}
+
 func TestServiceUpdateContent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
@@ -595,6 +674,67 @@ func TestServiceUpdateContent(t *testing.T) {
 	}
 }
 
+func TestServiceContentLinkURL(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Link Site", "link-site")
+
+	content := NewContent(site.ID, uuid.Nil, "Worth reading", "A sharp take.")
+	content.Kind = KindLink
+	content.LinkURL = " https://other.example/essay "
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	got, err := svc.GetContent(ctx, content.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LinkURL != "https://other.example/essay" {
+		t.Errorf("LinkURL = %q, want it trimmed", got.LinkURL)
+	}
+
+	got.LinkURL = "other.example/essay"
+	if err := svc.UpdateContent(ctx, got); !errors.Is(err, ErrInvalidLinkURL) {
+		t.Errorf("UpdateContent() with a relative link URL error = %v, want ErrInvalidLinkURL", err)
+	}
+}
+
+func TestServiceFindStaleDrafts(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Drafts Site", "drafts-site")
+
+	create := func(heading string, draft bool, age time.Duration) *Content {
+		t.Helper()
+		c := NewContent(site.ID, uuid.Nil, heading, "Body")
+		c.Draft = draft
+		c.UpdatedAt = time.Now().Add(-age)
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+		return c
+	}
+	stale := create("Forgotten draft", true, 45*24*time.Hour)
+	create("Fresh draft", true, 2*24*time.Hour)
+	create("Old post", false, 90*24*time.Hour)
+
+	drafts, err := svc.FindStaleDrafts(ctx, site.ID, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("FindStaleDrafts() error = %v", err)
+	}
+	if len(drafts) != 1 || drafts[0].ID != stale.ID {
+		var headings []string
+		for _, d := range drafts {
+			headings = append(headings, d.Heading)
+		}
+		t.Errorf("FindStaleDrafts() = %v, want just the forgotten draft", headings)
+	}
+}
+
 func TestServiceDeleteContent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
@@ -994,6 +1134,79 @@ func TestServiceRemoveAllTagsFromContent(t *testing.T) {
 	}
 }
 
+func TestServiceBulkTagContent(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Bulk Tag Site", "bulk-tag-site")
+	other := createTestSite(t, svc, "Other Bulk Tag Site", "other-bulk-tag-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
+	otherSection := NewSection(other.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, otherSection)
+
+	var posts []*Content
+	for i := 0; i < 3; i++ {
+		post := NewContent(site.ID, section.ID, fmt.Sprintf("Post %d", i), "Body")
+		if err := svc.CreateContent(ctx, post); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+		posts = append(posts, post)
+	}
+	foreign := NewContent(other.ID, otherSection.ID, "Foreign", "Body")
+	svc.CreateContent(ctx, foreign)
+
+	tag := NewTag(site.ID, "Golang")
+	if err := svc.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if err := svc.AddTagToContentByID(ctx, posts[0].ID, tag.ID); err != nil {
+		t.Fatalf("AddTagToContentByID() error = %v", err)
+	}
+
+	ids := []uuid.UUID{posts[0].ID, posts[1].ID, posts[2].ID, posts[2].ID, foreign.ID, uuid.New()}
+	count, err := svc.BulkTagContent(ctx, tag.ID, ids, false)
+	if err != nil {
+		t.Fatalf("BulkTagContent() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("BulkTagContent() = %d, want 2 (already tagged, duplicate, foreign and missing skipped)", count)
+	}
+	for _, post := range posts {
+		tags, err := svc.GetTagsForContent(ctx, post.ID)
+		if err != nil {
+			t.Fatalf("GetTagsForContent() error = %v", err)
+		}
+		if len(tags) != 1 || tags[0].ID != tag.ID {
+			t.Errorf("GetTagsForContent(%s) = %v, want only %s", post.Heading, tags, tag.Name)
+		}
+	}
+	if tags, _ := svc.GetTagsForContent(ctx, foreign.ID); len(tags) != 0 {
+		t.Errorf("content of another site got tagged: %v", tags)
+	}
+
+	count, err = svc.BulkTagContent(ctx, tag.ID, []uuid.UUID{posts[0].ID, posts[1].ID, foreign.ID}, true)
+	if err != nil {
+		t.Fatalf("BulkTagContent() remove error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("BulkTagContent() remove = %d, want 2", count)
+	}
+	tagged, err := svc.GetContentForTag(ctx, tag.ID)
+	if err != nil {
+		t.Fatalf("GetContentForTag() error = %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].ID != posts[2].ID {
+		t.Errorf("GetContentForTag() = %d items, want only %s", len(tagged), posts[2].Heading)
+	}
+
+	if _, err := svc.BulkTagContent(ctx, uuid.New(), ids, false); !errors.Is(err, ErrNotFound) {
+		t.Errorf("BulkTagContent() for a missing tag error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestServiceCreateSetting(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
@@ -1176,6 +1389,41 @@ func TestServiceCreateImage(t *testing.T) {
 	}
 }
 
+func TestServiceCreateImages(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Bulk Image Site", "bulk-image-site")
+
+	first := NewImage(site.ID, "one.jpg", "one.jpg")
+	second := NewImage(site.ID, "two.jpg", "two.jpg")
+	if err := svc.CreateImages(ctx, []*Image{first, second}); err != nil {
+		t.Fatalf("CreateImages() error = %v", err)
+	}
+
+	images, err := svc.GetImages(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("GetImages() error = %v", err)
+	}
+	if len(images) != 2 {
+		t.Fatalf("GetImages() count = %d, want 2", len(images))
+	}
+
+	// A failing record rolls back the whole batch.
+	third := NewImage(site.ID, "three.jpg", "three.jpg")
+	duplicate := NewImage(site.ID, "dup.jpg", "dup.jpg")
+	duplicate.ID = first.ID
+	if err := svc.CreateImages(ctx, []*Image{third, duplicate}); err == nil {
+		t.Fatal("CreateImages() expected error for duplicate ID")
+	}
+
+	images, _ = svc.GetImages(ctx, site.ID)
+	if len(images) != 2 {
+		t.Errorf("GetImages() count after failed batch = %d, want 2", len(images))
+	}
+}
+
 func TestServiceGetImage(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
@@ -1240,2614 +1488,4672 @@ func TestServiceGetImages(t *testing.T) {
 	}
 }
 
-func TestServiceLinkImageToContent(t *testing.T) {
+func TestServiceGetImagesWithPagination(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Link Image Site", "link-image-site")
-
+	site := createTestSite(t, svc, "Image Pages Site", "image-pages-site")
 	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
-
-	content := NewContent(site.ID, section.ID, "Image Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
+	content := NewContent(site.ID, section.ID, "Post", "Body")
 	svc.CreateContent(ctx, content)
 
-	image := NewImage(site.ID, "header.jpg", "/images/header.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	created := time.Now().Add(-time.Hour)
+	var images []*Image
+	for i, name := range []string{"beach.jpg", "mountain.jpg", "city.jpg", "forest.jpg", "desert.jpg"} {
+		image := NewImage(site.ID, name, "/images/"+name)
+		image.CreatedAt = created.Add(time.Duration(i) * time.Minute)
+		if err := svc.CreateImage(ctx, image); err != nil {
+			t.Fatalf("CreateImage() error = %v", err)
+		}
+		images = append(images, image)
+	}
+	images[2].Title = "Night skyline"
+	images[3].AltText = "Pine trees in the CITY park"
+	svc.UpdateImage(ctx, images[2])
+	svc.UpdateImage(ctx, images[3])
+	if err := svc.LinkImageToContent(ctx, content.ID, images[0].ID, false); err != nil {
+		t.Fatalf("LinkImageToContent() error = %v", err)
+	}
 
-	if err := svc.LinkImageToContent(ctx, content.ID, image.ID, true); err != nil {
-		t.Errorf("LinkImageToContent() error = %v", err)
+	names := func(images []*Image) []string {
+		var got []string
+		for _, i := range images {
+			got = append(got, i.FileName)
+		}
+		return got
 	}
 
-	images, err := svc.GetContentImagesWithDetails(ctx, content.ID)
+	page, total, err := svc.GetImagesWithPagination(ctx, site.ID, 2, 2, ImageFilter{})
 	if err != nil {
-		t.Errorf("GetContentImagesWithDetails() error = %v", err)
+		t.Fatalf("GetImagesWithPagination() error = %v", err)
 	}
-	if len(images) != 1 {
-		t.Errorf("Expected 1 image, got %d", len(images))
+	if got := names(page); total != 5 || len(got) != 2 || got[0] != "city.jpg" || got[1] != "mountain.jpg" {
+		t.Errorf("second page = %v of %d, want [city.jpg mountain.jpg] of 5", got, total)
 	}
-	if !images[0].IsHeader {
-		t.Error("Image should be marked as header")
+
+	tests := []struct {
+		name   string
+		filter ImageFilter
+		want   []string
+	}{
+		{"file name", ImageFilter{Search: "des"}, []string{"desert.jpg"}},
+		{"title or alt text", ImageFilter{Search: "city"}, []string{"forest.jpg", "city.jpg"}},
+		{"used", ImageFilter{Usage: ImageUsageUsed}, []string{"beach.jpg"}},
+		{"unused", ImageFilter{Search: "ta", Usage: ImageUsageUnused}, []string{"mountain.jpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, total, err := svc.GetImagesWithPagination(ctx, site.ID, 0, 10, tt.filter)
+			if err != nil {
+				t.Fatalf("GetImagesWithPagination() error = %v", err)
+			}
+			got := names(page)
+			if total != len(tt.want) || strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("GetImagesWithPagination(%+v) = %v of %d, want %v", tt.filter, got, total, tt.want)
+			}
+		})
 	}
 }
 
-func TestServiceUnlinkImageFromContent(t *testing.T) {
+func TestServiceSearchImages(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Unlink Image Site", "unlink-image-site")
-
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	content := NewContent(site.ID, section.ID, "Image Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	site := createTestSite(t, svc, "Search Site", "search-site")
+	other := createTestSite(t, svc, "Other Search Site", "other-search-site")
+
+	created := time.Now().Add(-time.Hour)
+	add := func(siteID uuid.UUID, name, alt string, minute int) *Image {
+		image := NewImage(siteID, name, name)
+		image.AltText = alt
+		image.CreatedAt = created.Add(time.Duration(minute) * time.Minute)
+		if err := svc.CreateImage(ctx, image); err != nil {
+			t.Fatalf("CreateImage() error = %v", err)
+		}
+		return image
+	}
+	add(site.ID, "img-001.jpg", "A red bicycle against a wall", 0)
+	dock := add(site.ID, "img-002.jpg", "Bicycles parked at the dock", 1)
+	add(site.ID, "img-003.jpg", "Sunset over the harbour", 2)
+	add(other.ID, "img-004.jpg", "A bicycle on another site", 3)
 
-	image := NewImage(site.ID, "photo.jpg", "/images/photo.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	names := func(images []*Image) string {
+		var got []string
+		for _, i := range images {
+			got = append(got, i.FileName)
+		}
+		return strings.Join(got, " ")
+	}
 
-	svc.LinkImageToContent(ctx, content.ID, image.ID, false)
+	images, total, err := svc.SearchImages(ctx, site.ID, "  BICYCLE ", uuid.Nil, 0, 10)
+	if err != nil {
+		t.Fatalf("SearchImages() error = %v", err)
+	}
+	if got := names(images); total != 2 || got != "img-002.jpg img-001.jpg" {
+		t.Errorf("SearchImages(bicycle) = %s of %d, want img-002.jpg img-001.jpg of 2", got, total)
+	}
 
-	images, _ := svc.GetContentImagesWithDetails(ctx, content.ID)
-	if len(images) != 1 {
-		t.Fatalf("Expected 1 image before unlink")
+	images, total, _ = svc.SearchImages(ctx, site.ID, "bicycle", uuid.Nil, 1, 1)
+	if got := names(images); total != 2 || got != "img-001.jpg" {
+		t.Errorf("second page of SearchImages(bicycle) = %s of %d, want img-001.jpg of 2", got, total)
 	}
 
-	if err := svc.UnlinkImageFromContent(ctx, images[0].ContentImageID); err != nil {
-		t.Errorf("UnlinkImageFromContent() error = %v", err)
+	harbour := NewCollection(site.ID, "Harbour")
+	if err := svc.CreateCollection(ctx, harbour); err != nil {
+		t.Fatalf("CreateCollection() error = %v", err)
+	}
+	if err := svc.AddImageToCollection(ctx, harbour.ID, dock.ID); err != nil {
+		t.Fatalf("AddImageToCollection() error = %v", err)
+	}
+	images, total, _ = svc.SearchImages(ctx, site.ID, "bicycle", harbour.ID, 0, 10)
+	if got := names(images); total != 1 || got != "img-002.jpg" {
+		t.Errorf("SearchImages(bicycle) in a collection = %s of %d, want img-002.jpg", got, total)
 	}
 
-	images, _ = svc.GetContentImagesWithDetails(ctx, content.ID)
-	if len(images) != 0 {
-		t.Errorf("Expected 0 images after unlink, got %d", len(images))
+	if images, total, _ = svc.SearchImages(ctx, site.ID, "", uuid.Nil, 0, 10); total != 3 || len(images) != 3 {
+		t.Errorf("SearchImages() without a query = %d of %d, want all 3 images of the site", len(images), total)
 	}
 }
 
-func TestServiceCreateMeta(t *testing.T) {
+func TestServiceContentFields(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Meta Site", "meta-site")
-
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
+	site := createTestSite(t, svc, "Fields Site", "fields-site")
+	section := NewSection(site.ID, "Recipes", "", "/recipes")
 	svc.CreateSection(ctx, section)
-
-	content := NewContent(site.ID, section.ID, "Meta Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
-
-	meta := NewMeta(site.ID, content.ID)
-	meta.Description = "Test description"
-	meta.Keywords = "test,keywords"
-	meta.CreatedBy = uuid.New()
-	meta.UpdatedBy = meta.CreatedBy
-
-	if err := svc.CreateMeta(ctx, meta); err != nil {
-		t.Errorf("CreateMeta() error = %v", err)
+	content := NewContent(site.ID, section.ID, "Pancakes", "Mix and fry.")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
 	}
-}
-
-func TestServiceGetMetaByContentID(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
-
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Get Meta Site", "get-meta-site")
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
+	err := svc.SetContentFields(ctx, content.ID, []*ContentField{
+		NewContentField(content.ID, "rating", FieldTypeNumber, "4.50"),
+		NewContentField(content.ID, "vegan", FieldTypeBool, "no"),
+	})
+	if !errors.Is(err, ErrInvalidField) {
+		t.Fatalf("SetContentFields() with an invalid bool error = %v, want ErrInvalidField", err)
+	}
+	if fields, _ := svc.GetContentFields(ctx, content.ID); len(fields) != 0 {
+		t.Fatalf("invalid fields saved %d fields, want none", len(fields))
+	}
 
-	content := NewContent(site.ID, section.ID, "Meta Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	err = svc.SetContentFields(ctx, content.ID, []*ContentField{
+		NewContentField(content.ID, "rating", FieldTypeNumber, "4.50"),
+		NewContentField(content.ID, "vegan", FieldTypeBool, "true"),
+		NewContentField(content.ID, "cook_time", FieldTypeNumber, "20"),
+	})
+	if err != nil {
+		t.Fatalf("SetContentFields() error = %v", err)
+	}
+	if err := svc.SetContentField(ctx, NewContentField(content.ID, "released", FieldTypeDate, "2024-03-07")); err != nil {
+		t.Fatalf("SetContentField() error = %v", err)
+	}
+	if err := svc.DeleteContentField(ctx, content.ID, "cook_time"); err != nil {
+		t.Fatalf("DeleteContentField() error = %v", err)
+	}
 
-	meta, err := svc.GetMetaByContentID(ctx, content.ID)
+	fields, err := svc.GetContentFields(ctx, content.ID)
 	if err != nil {
-		t.Errorf("GetMetaByContentID() error = %v", err)
+		t.Fatalf("GetContentFields() error = %v", err)
 	}
-	if meta != nil {
-		t.Error("Expected nil meta for content without meta")
+	var got []string
+	for _, f := range fields {
+		got = append(got, f.Name+"="+f.Value)
+	}
+	if want := "rating=4.5 released=2024-03-07 vegan=true"; strings.Join(got, " ") != want {
+		t.Errorf("GetContentFields() = %v, want %s", got, want)
 	}
 
-	newMeta := NewMeta(site.ID, content.ID)
-	newMeta.Description = "Description"
-	newMeta.CreatedBy = uuid.New()
-	newMeta.UpdatedBy = newMeta.CreatedBy
-	svc.CreateMeta(ctx, newMeta)
+	// Replacing the fields drops the ones left out and keeps the rest.
+	rating := fields[0]
+	err = svc.SetContentFields(ctx, content.ID, []*ContentField{NewContentField(content.ID, "rating", FieldTypeNumber, "5")})
+	if err != nil {
+		t.Fatalf("SetContentFields() error = %v", err)
+	}
+	fields, _ = svc.GetContentFields(ctx, content.ID)
+	if len(fields) != 1 || fields[0].Value != "5" || fields[0].ID != rating.ID {
+		t.Errorf("GetContentFields() after replacing = %+v, want rating 5 with its ID kept", fields)
+	}
+	err = svc.SetContentFields(ctx, content.ID, []*ContentField{
+		NewContentField(content.ID, "rating", FieldTypeNumber, "1"),
+		NewContentField(content.ID, "rating", FieldTypeNumber, "2"),
+	})
+	if !errors.Is(err, ErrInvalidField) {
+		t.Errorf("SetContentFields() with a repeated name error = %v, want ErrInvalidField", err)
+	}
 
-	meta, err = svc.GetMetaByContentID(ctx, content.ID)
+	// Generation reads them typed from the content.
+	contents, err := svc.GetAllContentWithMeta(ctx, site.ID)
 	if err != nil {
-		t.Errorf("GetMetaByContentID() error = %v", err)
+		t.Fatalf("GetAllContentWithMeta() error = %v", err)
 	}
-	if meta == nil {
-		t.Error("Expected meta, got nil")
+	if len(contents) != 1 || contents[0].Fields["rating"] != 5.0 {
+		t.Errorf("GetAllContentWithMeta() fields = %v, want rating 5", contents[0].Fields)
+	}
+	withMeta, err := svc.GetContentWithMeta(ctx, content.ID)
+	if err != nil {
+		t.Fatalf("GetContentWithMeta() error = %v", err)
+	}
+	if withMeta.Fields["rating"] != 5.0 {
+		t.Errorf("GetContentWithMeta() fields = %v, want rating 5", withMeta.Fields)
 	}
 }
 
-func TestServiceUpdateMeta(t *testing.T) {
+func TestServiceImageCollections(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Meta Site", "update-meta-site")
+	site := createTestSite(t, svc, "Collections Site", "collections-site")
+	other := createTestSite(t, svc, "Other Site", "other-site")
+
+	travel := NewCollection(site.ID, "Travel")
+	people := NewCollection(site.ID, "People")
+	elsewhere := NewCollection(other.ID, "Elsewhere")
+	for _, c := range []*Collection{travel, people, elsewhere} {
+		if err := svc.CreateCollection(ctx, c); err != nil {
+			t.Fatalf("CreateCollection(%q) error = %v", c.Name, err)
+		}
+	}
+	if err := svc.CreateCollection(ctx, NewCollection(site.ID, "Travel")); !errors.Is(err, ErrSlugTaken) {
+		t.Errorf("CreateCollection() duplicate error = %v, want ErrSlugTaken", err)
+	}
+	if err := svc.CreateCollection(ctx, NewCollection(site.ID, " ")); !errors.Is(err, ErrCollectionName) {
+		t.Errorf("CreateCollection() unnamed error = %v, want ErrCollectionName", err)
+	}
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
+	beach := NewImage(site.ID, "beach.jpg", "beach.jpg")
+	team := NewImage(site.ID, "team.jpg", "team.jpg")
+	loose := NewImage(site.ID, "loose.jpg", "loose.jpg")
+	if err := svc.CreateImages(ctx, []*Image{beach, team, loose}); err != nil {
+		t.Fatalf("CreateImages() error = %v", err)
+	}
+
+	if err := svc.AddImageToCollection(ctx, travel.ID, beach.ID); err != nil {
+		t.Fatalf("AddImageToCollection() error = %v", err)
+	}
+	if err := svc.AddImageToCollection(ctx, travel.ID, beach.ID); err != nil {
+		t.Errorf("AddImageToCollection() again error = %v", err)
+	}
+	if err := svc.SetImageCollections(ctx, team.ID, []uuid.UUID{travel.ID, people.ID}); err != nil {
+		t.Fatalf("SetImageCollections() error = %v", err)
+	}
+	if err := svc.SetImageCollections(ctx, loose.ID, []uuid.UUID{elsewhere.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SetImageCollections() with another site's collection error = %v, want ErrNotFound", err)
+	}
+
+	names := func(images []*Image) []string {
+		var got []string
+		for _, image := range images {
+			got = append(got, image.FileName)
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	images, err := svc.GetImagesByCollection(ctx, travel.ID)
+	if err != nil {
+		t.Fatalf("GetImagesByCollection() error = %v", err)
+	}
+	if got := names(images); strings.Join(got, " ") != "beach.jpg team.jpg" {
+		t.Errorf("GetImagesByCollection(travel) = %v, want [beach.jpg team.jpg]", got)
+	}
+
+	page, total, err := svc.GetImagesWithPagination(ctx, site.ID, 0, 10, ImageFilter{CollectionID: people.ID})
+	if err != nil {
+		t.Fatalf("GetImagesWithPagination() error = %v", err)
+	}
+	if got := names(page); total != 1 || strings.Join(got, " ") != "team.jpg" {
+		t.Errorf("GetImagesWithPagination(people) = %v of %d, want [team.jpg]", got, total)
+	}
+
+	collections, err := svc.GetCollections(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("GetCollections() error = %v", err)
+	}
+	counts := make(map[string]int)
+	for _, c := range collections {
+		counts[c.Name] = c.ImageCount
+	}
+	if len(collections) != 2 || counts["Travel"] != 2 || counts["People"] != 1 {
+		t.Errorf("GetCollections() counts = %v, want Travel 2 and People 1", counts)
+	}
+
+	// Narrowing the collections of an image takes it out of the others.
+	if err := svc.SetImageCollections(ctx, team.ID, []uuid.UUID{people.ID}); err != nil {
+		t.Fatalf("SetImageCollections() error = %v", err)
+	}
+	in, err := svc.GetImageCollections(ctx, team.ID)
+	if err != nil {
+		t.Fatalf("GetImageCollections() error = %v", err)
+	}
+	if len(in) != 1 || in[0].ID != people.ID {
+		t.Errorf("GetImageCollections(team) = %v, want only People", in)
+	}
+
+	// Deleting a collection keeps its images in the library.
+	if err := svc.DeleteCollection(ctx, travel.ID); err != nil {
+		t.Fatalf("DeleteCollection() error = %v", err)
+	}
+	if _, err := svc.GetImage(ctx, beach.ID); err != nil {
+		t.Errorf("GetImage() after deleting its collection error = %v", err)
+	}
+	if images, _ := svc.GetImagesByCollection(ctx, travel.ID); len(images) != 0 {
+		t.Errorf("deleted collection still lists %d images", len(images))
+	}
+}
+
+func TestServiceLinkImageToContent(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Link Image Site", "link-image-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Image Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	image := NewImage(site.ID, "header.jpg", "/images/header.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
+
+	if err := svc.LinkImageToContent(ctx, content.ID, image.ID, true); err != nil {
+		t.Errorf("LinkImageToContent() error = %v", err)
+	}
+
+	images, err := svc.GetContentImagesWithDetails(ctx, content.ID)
+	if err != nil {
+		t.Errorf("GetContentImagesWithDetails() error = %v", err)
+	}
+	if len(images) != 1 {
+		t.Errorf("Expected 1 image, got %d", len(images))
+	}
+	if !images[0].IsHeader {
+		t.Error("Image should be marked as header")
+	}
+}
+
+func TestServiceOrphanedImages(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, cfg, newTestLogger())
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Orphan Site", "orphan-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
+
+	newImage := func(name string) *Image {
+		t.Helper()
+		image := NewImage(site.ID, name, name)
+		if err := svc.CreateImage(ctx, image); err != nil {
+			t.Fatalf("CreateImage() error = %v", err)
+		}
+		return image
+	}
+	linked := newImage("linked-1a2b3c4d.jpg")
+	inBody := newImage("body-1a2b3c4d.jpg")
+	orphan := newImage("orphan-1a2b3c4d.jpg")
+
+	content := NewContent(site.ID, section.ID, "Post", "![x](/images/body-1a2b3c4d.jpg)")
+	svc.CreateContent(ctx, content)
+	svc.LinkImageToContent(ctx, content.ID, linked.ID, false)
+
+	orphans, err := svc.FindOrphanedImages(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("FindOrphanedImages() error = %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].ID != orphan.ID {
+		t.Fatalf("FindOrphanedImages() = %v, want only %s", orphans, orphan.FileName)
+	}
+
+	imagesPath := NewWorkspace(cfg.SSG.SitesBasePath).GetImagesPath(site.Slug)
+	if err := os.MkdirAll(imagesPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(imagesPath, orphan.FilePath), make([]byte, 2048), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Images still in use are skipped even if requested.
+	result, err := svc.PurgeOrphanedImages(ctx, site.ID, []uuid.UUID{orphan.ID, inBody.ID})
+	if err != nil {
+		t.Fatalf("PurgeOrphanedImages() error = %v", err)
+	}
+	if result.Deleted != 1 || result.Skipped != 1 || result.ReclaimedBytes != 2048 {
+		t.Errorf("PurgeOrphanedImages() = %+v, want 1 deleted, 1 skipped, 2048 bytes", result)
+	}
+	if _, err := os.Stat(filepath.Join(imagesPath, orphan.FilePath)); !os.IsNotExist(err) {
+		t.Errorf("expected orphan file to be removed, got %v", err)
+	}
+	if _, err := svc.GetImage(ctx, orphan.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetImage() after purge error = %v, want ErrNotFound", err)
+	}
+	if _, err := svc.GetImage(ctx, inBody.ID); err != nil {
+		t.Errorf("referenced image should remain: %v", err)
+	}
+}
+
+func TestServiceGetImageUsage(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, &config.Config{}, newTestLogger())
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Usage Site", "usage-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
+
+	image := NewImage(site.ID, "hero-9f8e7d6c.jpg", "hero-9f8e7d6c.jpg")
+	if err := svc.CreateImage(ctx, image); err != nil {
+		t.Fatalf("CreateImage() error = %v", err)
+	}
+	unused := NewImage(site.ID, "unused-9f8e7d6c.jpg", "unused-9f8e7d6c.jpg")
+	svc.CreateImage(ctx, unused)
+
+	linked := NewContent(site.ID, section.ID, "Linked Post", "No images here")
+	svc.CreateContent(ctx, linked)
+	svc.LinkImageToContent(ctx, linked.ID, image.ID, true)
+	inBody := NewContent(site.ID, section.ID, "Body Post", "Intro\n\n![Hero](/images/hero-9f8e7d6c.jpg)")
+	svc.CreateContent(ctx, inBody)
+	other := NewContent(site.ID, section.ID, "Other Post", "![Else](/images/else-9f8e7d6c.jpg)")
+	svc.CreateContent(ctx, other)
+
+	svc.LinkImageToSection(ctx, section.ID, image.ID, false)
+
+	layout := NewLayout(site.ID, "Default", "")
+	layout.HeaderImageID = image.ID
+	if err := svc.CreateLayout(ctx, layout); err != nil {
+		t.Fatalf("CreateLayout() error = %v", err)
+	}
+
+	tag := NewTag(site.ID, "Travel")
+	tag.HeaderImageID = image.ID
+	if err := svc.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+
+	usage, err := svc.GetImageUsage(ctx, image.ID)
+	if err != nil {
+		t.Fatalf("GetImageUsage() error = %v", err)
+	}
+	if len(usage.Contents) != 1 || usage.Contents[0].ID != linked.ID || !usage.Contents[0].Header {
+		t.Errorf("Contents = %+v, want %q as header", usage.Contents, linked.Heading)
+	}
+	if len(usage.Bodies) != 1 || usage.Bodies[0].ID != inBody.ID {
+		t.Errorf("Bodies = %+v, want only %q", usage.Bodies, inBody.Heading)
+	}
+	if len(usage.Sections) != 1 || usage.Sections[0].ID != section.ID || usage.Sections[0].Header {
+		t.Errorf("Sections = %+v, want %q", usage.Sections, section.Name)
+	}
+	if len(usage.Layouts) != 1 || usage.Layouts[0].ID != layout.ID {
+		t.Errorf("Layouts = %+v, want %q", usage.Layouts, layout.Name)
+	}
+	if len(usage.Tags) != 1 || usage.Tags[0].ID != tag.ID {
+		t.Errorf("Tags = %+v, want %q", usage.Tags, tag.Name)
+	}
+	if usage.Count() != 5 || !usage.InUse() {
+		t.Errorf("Count() = %d, want 5", usage.Count())
+	}
+
+	usage, err = svc.GetImageUsage(ctx, unused.ID)
+	if err != nil {
+		t.Fatalf("GetImageUsage() error = %v", err)
+	}
+	if usage.InUse() {
+		t.Errorf("unused image usage = %+v, want none", usage)
+	}
+
+	if _, err := svc.GetImageUsage(ctx, uuid.New()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetImageUsage() for a missing image error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServiceMoveContent(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, cfg, newTestLogger())
+	ctx := context.Background()
+
+	source := createTestSite(t, svc, "Source", "source")
+	target := createTestSite(t, svc, "Target", "target")
+	sourceSection := NewSection(source.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, sourceSection)
+	targetSection := NewSection(target.ID, "Notes", "", "/notes")
+	svc.CreateSection(ctx, targetSection)
+
+	contributor := NewContributor(source.ID, "jane", "Jane", "Doe")
+	if err := svc.CreateContributor(ctx, contributor); err != nil {
+		t.Fatalf("CreateContributor() error = %v", err)
+	}
+
+	content := NewContent(source.ID, sourceSection.ID, "Moving", "![x](/images/pic-1a2b3c4d.jpg)")
+	content.ContributorID = &contributor.ID
+	content.ContributorHandle = contributor.Handle
+	content.AuthorUsername = "jdoe"
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	if err := svc.CreateMeta(ctx, NewMeta(source.ID, content.ID)); err != nil {
+		t.Fatalf("CreateMeta() error = %v", err)
+	}
+
+	existing := NewTag(target.ID, "Go")
+	svc.CreateTag(ctx, existing)
+	svc.AddTagToContent(ctx, content.ID, "Go", source.ID)
+	svc.AddTagToContent(ctx, content.ID, "Travel", source.ID)
+
+	image := NewImage(source.ID, "pic.jpg", "pic-1a2b3c4d.jpg")
+	svc.CreateImage(ctx, image)
+	svc.LinkImageToContent(ctx, content.ID, image.ID, true)
+	workspace := NewWorkspace(cfg.SSG.SitesBasePath)
+	sourceFile := filepath.Join(workspace.GetImagesPath(source.Slug), image.FilePath)
+	if err := EnsureDir(sourceFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sourceFile, []byte("img"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("section from another site is rejected", func(t *testing.T) {
+		_, err := svc.MoveContent(ctx, content.ID, target.ID, sourceSection.ID)
+		if !errors.Is(err, ErrSectionNotInSite) {
+			t.Errorf("MoveContent() error = %v, want ErrSectionNotInSite", err)
+		}
+	})
+
+	result, err := svc.MoveContent(ctx, content.ID, target.ID, targetSection.ID)
+	if err != nil {
+		t.Fatalf("MoveContent() error = %v", err)
+	}
+	want := ContentMove{ImagesCopied: 1, TagsMapped: 1, TagsCreated: 1, ContributorDropped: "jane"}
+	if *result != want {
+		t.Errorf("MoveContent() = %+v, want %+v", *result, want)
+	}
+
+	moved, err := svc.GetContent(ctx, content.ID)
+	if err != nil {
+		t.Fatalf("GetContent() error = %v", err)
+	}
+	if moved.SiteID != target.ID || moved.SectionID != targetSection.ID {
+		t.Errorf("content site/section = %s/%s, want %s/%s", moved.SiteID, moved.SectionID, target.ID, targetSection.ID)
+	}
+	if moved.ContributorID != nil || moved.ContributorHandle != "" || moved.AuthorUsername != "jdoe" {
+		t.Errorf("author = %v/%q/%q, want fallback to username jdoe", moved.ContributorID, moved.ContributorHandle, moved.AuthorUsername)
+	}
+
+	meta, err := svc.GetMetaByContentID(ctx, content.ID)
+	if err != nil || meta.SiteID != target.ID {
+		t.Errorf("meta site = %v (err %v), want %s", meta, err, target.ID)
+	}
+
+	tags, _ := svc.GetTagsForContent(ctx, content.ID)
+	for _, tag := range tags {
+		if tag.SiteID != target.ID {
+			t.Errorf("tag %q belongs to site %s, want %s", tag.Name, tag.SiteID, target.ID)
+		}
+	}
+	if len(tags) != 2 {
+		t.Errorf("tags = %d, want 2", len(tags))
+	}
+
+	images, _ := svc.GetContentImagesWithDetails(ctx, content.ID)
+	if len(images) != 1 || !images[0].IsHeader {
+		t.Fatalf("content images = %v, want the header image", images)
+	}
+	if images[0].SiteID != target.ID || images[0].ID == image.ID {
+		t.Errorf("linked image %s of site %s, want a copy on %s", images[0].ID, images[0].SiteID, target.ID)
+	}
+	if _, err := os.Stat(filepath.Join(workspace.GetImagesPath(target.Slug), image.FilePath)); err != nil {
+		t.Errorf("expected image file in target workspace: %v", err)
+	}
+}
+
+func TestServiceCloneSite(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, cfg, newTestLogger())
+	ctx := context.Background()
+	workspace := NewWorkspace(cfg.SSG.SitesBasePath)
+
+	source := createTestSite(t, svc, "Template", "template")
+
+	header := NewImage(source.ID, "header.jpg", "header-1a2b3c4d.jpg")
+	svc.CreateImage(ctx, header)
+	headerFile := filepath.Join(workspace.GetImagesPath(source.Slug), header.FilePath)
+	if err := EnsureDir(headerFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(headerFile, []byte("img"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	layout := NewLayout(source.ID, "Default", "")
+	layout.Code = "<main>{{ .Content.Heading }}</main>"
+	layout.HeaderImageID = header.ID
+	if err := svc.CreateLayout(ctx, layout); err != nil {
+		t.Fatalf("CreateLayout() error = %v", err)
+	}
+	source.DefaultLayoutID = layout.ID
+	source.DefaultLayoutName = layout.Name
+	if err := svc.UpdateSite(ctx, source); err != nil {
+		t.Fatalf("UpdateSite() error = %v", err)
+	}
+
+	section := NewSection(source.ID, "Blog", "", "/blog")
+	section.LayoutID = layout.ID
+	svc.CreateSection(ctx, section)
+	svc.CreateSetting(ctx, NewSetting(source.ID, "Site title", "Template"))
+
+	contributor := NewContributor(source.ID, "jane", "Jane", "Doe")
+	svc.CreateContributor(ctx, contributor)
+	content := NewContent(source.ID, section.ID, "Hello", "Body")
+	content.ContributorID = &contributor.ID
+	content.ContributorHandle = contributor.Handle
+	svc.CreateContent(ctx, content)
+	svc.AddTagToContent(ctx, content.ID, "Go", source.ID)
+
+	t.Run("slug must be unique", func(t *testing.T) {
+		_, err := svc.CloneSite(ctx, source.ID, "Copy", source.Slug, CloneOptions{})
+		if !errors.Is(err, ErrSlugTaken) {
+			t.Errorf("CloneSite() error = %v, want ErrSlugTaken", err)
+		}
+	})
+
+	t.Run("structure only", func(t *testing.T) {
+		clone, err := svc.CloneSite(ctx, source.ID, "Structure", "structure", CloneOptions{})
+		if err != nil {
+			t.Fatalf("CloneSite() error = %v", err)
+		}
+		if clone.ID == source.ID {
+			t.Fatal("expected a fresh site ID")
+		}
+
+		layouts, _ := svc.GetLayouts(ctx, clone.ID)
+		if len(layouts) != 1 || layouts[0].ID == layout.ID || layouts[0].Code != layout.Code {
+			t.Fatalf("layouts = %+v, want a copy of %q", layouts, layout.Name)
+		}
+		got, _ := svc.GetSite(ctx, clone.ID)
+		if got.DefaultLayoutID != layouts[0].ID {
+			t.Errorf("default layout = %s, want cloned layout %s", got.DefaultLayoutID, layouts[0].ID)
+		}
+
+		images, _ := svc.GetImages(ctx, clone.ID)
+		if len(images) != 1 || layouts[0].HeaderImageID != images[0].ID {
+			t.Errorf("header image not cloned: layout header %s, images %+v", layouts[0].HeaderImageID, images)
+		}
+		if _, err := os.Stat(filepath.Join(workspace.GetImagesPath(clone.Slug), header.FilePath)); err != nil {
+			t.Errorf("expected header image file in clone workspace: %v", err)
+		}
+
+		sections, _ := svc.GetSections(ctx, clone.ID)
+		if len(sections) != 1 || sections[0].ID == section.ID || sections[0].LayoutID != layouts[0].ID {
+			t.Errorf("sections = %+v, want a copy using the cloned layout", sections)
+		}
+		params, _ := svc.GetSettings(ctx, clone.ID)
+		if len(params) != 1 || params[0].Value != "Template" {
+			t.Errorf("params = %+v, want the source param", params)
+		}
+		contributors, _ := svc.GetContributors(ctx, clone.ID)
+		contents, _ := svc.GetAllContentWithMeta(ctx, clone.ID)
+		if len(contributors) != 0 || len(contents) != 0 {
+			t.Errorf("got %d contributors and %d contents, want none", len(contributors), len(contents))
+		}
+	})
+
+	t.Run("with content", func(t *testing.T) {
+		clone, err := svc.CloneSite(ctx, source.ID, "Full", "full", CloneOptions{Content: true})
+		if err != nil {
+			t.Fatalf("CloneSite() error = %v", err)
+		}
+
+		contributors, _ := svc.GetContributors(ctx, clone.ID)
+		if len(contributors) != 1 || contributors[0].ID == contributor.ID {
+			t.Fatalf("contributors = %+v, want a copy of jane", contributors)
+		}
+		sections, _ := svc.GetSections(ctx, clone.ID)
+		contents, _ := svc.GetAllContentWithMeta(ctx, clone.ID)
+		if len(contents) != 1 || contents[0].ID == content.ID {
+			t.Fatalf("contents = %+v, want a copy of %q", contents, content.Heading)
+		}
+		c := contents[0]
+		if c.SectionID != sections[0].ID || c.ContributorID == nil || *c.ContributorID != contributors[0].ID {
+			t.Errorf("content section/contributor = %s/%v, want remapped IDs", c.SectionID, c.ContributorID)
+		}
+		tags, _ := svc.GetTagsForContent(ctx, c.ID)
+		if len(tags) != 1 || tags[0].SiteID != clone.ID {
+			t.Errorf("tags = %+v, want Go on the cloned site", tags)
+		}
+	})
+}
+
+func TestServiceUnlinkImageFromContent(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Unlink Image Site", "unlink-image-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Image Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	image := NewImage(site.ID, "photo.jpg", "/images/photo.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
+
+	svc.LinkImageToContent(ctx, content.ID, image.ID, false)
+
+	images, _ := svc.GetContentImagesWithDetails(ctx, content.ID)
+	if len(images) != 1 {
+		t.Fatalf("Expected 1 image before unlink")
+	}
+
+	if err := svc.UnlinkImageFromContent(ctx, images[0].ContentImageID); err != nil {
+		t.Errorf("UnlinkImageFromContent() error = %v", err)
+	}
+
+	images, _ = svc.GetContentImagesWithDetails(ctx, content.ID)
+	if len(images) != 0 {
+		t.Errorf("Expected 0 images after unlink, got %d", len(images))
+	}
+}
+
+func TestServiceCreateMeta(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Meta Site", "meta-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Meta Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	meta := NewMeta(site.ID, content.ID)
+	meta.Description = "Test description"
+	meta.Keywords = "test,keywords"
+	meta.CreatedBy = uuid.New()
+	meta.UpdatedBy = meta.CreatedBy
+
+	if err := svc.CreateMeta(ctx, meta); err != nil {
+		t.Errorf("CreateMeta() error = %v", err)
+	}
+}
+
+func TestServiceGetMetaByContentID(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Get Meta Site", "get-meta-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Meta Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	meta, err := svc.GetMetaByContentID(ctx, content.ID)
+	if err != nil {
+		t.Errorf("GetMetaByContentID() error = %v", err)
+	}
+	if meta != nil {
+		t.Error("Expected nil meta for content without meta")
+	}
+
+	newMeta := NewMeta(site.ID, content.ID)
+	newMeta.Description = "Description"
+	newMeta.CreatedBy = uuid.New()
+	newMeta.UpdatedBy = newMeta.CreatedBy
+	svc.CreateMeta(ctx, newMeta)
+
+	meta, err = svc.GetMetaByContentID(ctx, content.ID)
+	if err != nil {
+		t.Errorf("GetMetaByContentID() error = %v", err)
+	}
+	if meta == nil {
+		t.Error("Expected meta, got nil")
+	}
+}
+
+func TestServiceUpdateMeta(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Update Meta Site", "update-meta-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Meta Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	meta := NewMeta(site.ID, content.ID)
+	meta.Description = "Original"
+	meta.CreatedBy = uuid.New()
+	meta.UpdatedBy = meta.CreatedBy
+	svc.CreateMeta(ctx, meta)
+
+	meta.Description = "Updated"
+	meta.UpdatedAt = time.Now()
+
+	if err := svc.UpdateMeta(ctx, meta); err != nil {
+		t.Errorf("UpdateMeta() error = %v", err)
+	}
+}
+
+func TestServiceCreateContributor(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Contributor Site", "contributor-site")
+
+	contributor := NewContributor(site.ID, "johndoe", "John", "Doe")
+	contributor.Bio = "Software developer"
+	contributor.SocialLinks = []SocialLink{
+		{Platform: "twitter", Handle: "@johndoe"},
+	}
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+
+	if err := svc.CreateContributor(ctx, contributor); err != nil {
+		t.Errorf("CreateContributor() error = %v", err)
+	}
+}
+
+func TestServiceGetContributor(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Get Contributor Site", "get-contributor-site")
+
+	contributor := NewContributor(site.ID, "janedoe", "Jane", "Doe")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		wantErr error
+	}{
+		{
+			name:    "existing contributor",
+			id:      contributor.ID,
+			wantErr: nil,
+		},
+		{
+			name:    "non existent contributor",
+			id:      uuid.New(),
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetContributor(ctx, tt.id)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetContributor() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceGetContributors(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Contributors Site", "contributors-site")
+
+	for i := 0; i < 3; i++ {
+		contributor := NewContributor(site.ID, "user"+string(rune('a'+i)), "User", string(rune('A'+i)))
+		contributor.CreatedBy = uuid.New()
+		contributor.UpdatedBy = contributor.CreatedBy
+		svc.CreateContributor(ctx, contributor)
+	}
+
+	contributors, err := svc.GetContributors(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("GetContributors() error = %v", err)
+	}
+	if len(contributors) != 3 {
+		t.Errorf("GetContributors() returned %d contributors, want 3", len(contributors))
+	}
+}
+
+func TestServiceUpdateContributor(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Update Contributor Site", "update-contributor-site")
+
+	contributor := NewContributor(site.ID, "updateuser", "Original", "Name")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
+
+	contributor.Name = "Updated"
+	contributor.Bio = "New bio"
+	contributor.UpdatedAt = time.Now()
+
+	if err := svc.UpdateContributor(ctx, contributor); err != nil {
+		t.Errorf("UpdateContributor() error = %v", err)
+	}
+}
+
+func TestServiceDeleteContributor(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Delete Contributor Site", "delete-contributor-site")
+
+	contributor := NewContributor(site.ID, "deleteuser", "Delete", "Me")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
+
+	if err := svc.DeleteContributor(ctx, contributor.ID); err != nil {
+		t.Errorf("DeleteContributor() error = %v", err)
+	}
+
+	_, err := svc.GetContributor(ctx, contributor.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Contributor should have been deleted")
+	}
+}
+
+func TestServiceSetContributorProfile(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Profile Contributor Site", "profile-contributor-site")
+
+	contributor := NewContributor(site.ID, "profileuser", "Profile", "User")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
+
+	// Create a real profile in the database
+	profileID := uuid.New()
+	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, created_by, updated_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		profileID.String(), site.ID.String(), "abc12345", "test-profile", "Test Profile", contributor.CreatedBy.String(), contributor.CreatedBy.String())
+	if err != nil {
+		t.Fatalf("Failed to create test profile: %v", err)
+	}
+
+	if err := svc.SetContributorProfile(ctx, contributor.ID, profileID, "admin"); err != nil {
+		t.Errorf("SetContributorProfile() error = %v", err)
+	}
+}
+
+func TestServiceUpdateTag(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Update Tag Site", "update-tag-site")
+
+	tag := NewTag(site.ID, "Original Tag")
+	tag.CreatedBy = uuid.New()
+	tag.UpdatedBy = tag.CreatedBy
+	svc.CreateTag(ctx, tag)
+
+	tag.Name = "Updated Tag"
+	tag.Slug = Slugify("Updated Tag")
+	tag.Description = "Everything about the updated tag."
+	tag.UpdatedAt = time.Now()
+
+	if err := svc.UpdateTag(ctx, tag); err != nil {
+		t.Errorf("UpdateTag() error = %v", err)
+	}
+
+	got, err := svc.GetTag(ctx, tag.ID)
+	if err != nil {
+		t.Fatalf("GetTag() error = %v", err)
+	}
+	if got.Description != tag.Description || got.HeaderImageID != uuid.Nil {
+		t.Errorf("GetTag() = %+v, want the new description and no header image", got)
+	}
+}
+
+func TestServiceDeleteTag(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Delete Tag Site", "delete-tag-site")
+
+	tag := NewTag(site.ID, "To Delete")
+	tag.CreatedBy = uuid.New()
+	tag.UpdatedBy = tag.CreatedBy
+	svc.CreateTag(ctx, tag)
+
+	if err := svc.DeleteTag(ctx, tag.ID); err != nil {
+		t.Errorf("DeleteTag() error = %v", err)
+	}
+
+	_, err := svc.GetTag(ctx, tag.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Tag should have been deleted")
+	}
+}
+
+func TestServiceUpdateSetting(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Update Setting Site", "update-setting-site")
+
+	setting := NewSetting(site.ID, "original_param", "original_value")
+	setting.CreatedBy = uuid.New()
+	setting.UpdatedBy = setting.CreatedBy
+	svc.CreateSetting(ctx, setting)
+
+	setting.Value = "updated_value"
+	setting.UpdatedAt = time.Now()
+
+	if err := svc.UpdateSetting(ctx, setting); err != nil {
+		t.Errorf("UpdateSetting() error = %v", err)
+	}
+}
+
+func TestServiceDeleteSetting(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Delete Setting Site", "delete-setting-site")
+
+	setting := NewSetting(site.ID, "to_delete", "value")
+	setting.CreatedBy = uuid.New()
+	setting.UpdatedBy = setting.CreatedBy
+	svc.CreateSetting(ctx, setting)
+
+	if err := svc.DeleteSetting(ctx, setting.ID); err != nil {
+		t.Errorf("DeleteSetting() error = %v", err)
+	}
+
+	_, err := svc.GetSetting(ctx, setting.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Setting should have been deleted")
+	}
+}
+
+func TestServiceUpdateImage(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Update Image Site", "update-image-site")
+
+	image := NewImage(site.ID, "original.jpg", "/images/original.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
+
+	image.FileName = "updated.jpg"
+	image.FilePath = "/images/updated.jpg"
+	image.AltText = "Updated alt"
+	image.Attribution = "Photo by Jane"
+	image.AttributionURL = "https://example.com/jane"
+	image.Stock = true
+	image.UpdatedAt = time.Now()
+
+	if err := svc.UpdateImage(ctx, image); err != nil {
+		t.Errorf("UpdateImage() error = %v", err)
+	}
+
+	got, err := svc.GetImage(ctx, image.ID)
+	if err != nil {
+		t.Fatalf("GetImage() error = %v", err)
+	}
+	if got.Attribution != image.Attribution || got.AttributionURL != image.AttributionURL || !got.Stock {
+		t.Errorf("credit not persisted: attribution=%q url=%q stock=%v", got.Attribution, got.AttributionURL, got.Stock)
+	}
+}
+
+func TestServiceDeleteImage(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Delete Image Site", "delete-image-site")
+
+	image := NewImage(site.ID, "todelete.jpg", "/images/todelete.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
+
+	if err := svc.DeleteImage(ctx, image.ID); err != nil {
+		t.Errorf("DeleteImage() error = %v", err)
+	}
+
+	_, err := svc.GetImage(ctx, image.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Image should have been deleted")
+	}
+}
+
+func TestServiceSectionImageOperations(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Section Image Site", "section-image-site")
+
+	section := NewSection(site.ID, "Test Section", "", "/test")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	image := NewImage(site.ID, "section-img.jpg", "/images/section-img.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
+
+	// Link image to section
+	if err := svc.LinkImageToSection(ctx, section.ID, image.ID, true); err != nil {
+		t.Errorf("LinkImageToSection() error = %v", err)
+	}
+
+	// Get section images
+	images, err := svc.GetSectionImagesWithDetails(ctx, section.ID)
+	if err != nil {
+		t.Errorf("GetSectionImagesWithDetails() error = %v", err)
+	}
+	if len(images) != 1 {
+		t.Errorf("Expected 1 image, got %d", len(images))
+	}
+
+	// Get section image details
+	if len(images) > 0 {
+		details, err := svc.GetSectionImageDetails(ctx, images[0].SectionImageID)
+		if err != nil {
+			t.Errorf("GetSectionImageDetails() error = %v", err)
+		}
+		if details == nil {
+			t.Error("Expected details, got nil")
+		}
+
+		// Unlink image from section
+		if err := svc.UnlinkImageFromSection(ctx, images[0].SectionImageID); err != nil {
+			t.Errorf("UnlinkImageFromSection() error = %v", err)
+		}
+	}
+
+	// Verify unlink
+	images, _ = svc.GetSectionImagesWithDetails(ctx, section.ID)
+	if len(images) != 0 {
+		t.Errorf("Expected 0 images after unlink, got %d", len(images))
+	}
+}
+
+func TestServiceGetContentImageDetails(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Content Image Details Site", "content-image-details-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Image Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	image := NewImage(site.ID, "detail.jpg", "/images/detail.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
+
+	svc.LinkImageToContent(ctx, content.ID, image.ID, false)
+
+	images, _ := svc.GetContentImagesWithDetails(ctx, content.ID)
+	if len(images) != 1 {
+		t.Fatalf("Expected 1 image")
+	}
+
+	details, err := svc.GetContentImageDetails(ctx, images[0].ContentImageID)
+	if err != nil {
+		t.Errorf("GetContentImageDetails() error = %v", err)
+	}
+	if details == nil {
+		t.Error("Expected details, got nil")
+	}
+	if details.FilePath != "/images/detail.jpg" {
+		t.Errorf("FilePath = %q, want %q", details.FilePath, "/images/detail.jpg")
+	}
+}
+
+func TestServiceUnlinkHeaderImageFromContent(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Unlink Header Site", "unlink-header-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Header Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	// Add a header image
+	headerImage := NewImage(site.ID, "header.jpg", "/images/header.jpg")
+	headerImage.CreatedBy = uuid.New()
+	headerImage.UpdatedBy = headerImage.CreatedBy
+	svc.CreateImage(ctx, headerImage)
+	svc.LinkImageToContent(ctx, content.ID, headerImage.ID, true)
+
+	// Add a non-header image
+	otherImage := NewImage(site.ID, "other.jpg", "/images/other.jpg")
+	otherImage.CreatedBy = uuid.New()
+	otherImage.UpdatedBy = otherImage.CreatedBy
+	svc.CreateImage(ctx, otherImage)
+	svc.LinkImageToContent(ctx, content.ID, otherImage.ID, false)
+
+	// Unlink header
+	if err := svc.UnlinkHeaderImageFromContent(ctx, content.ID); err != nil {
+		t.Errorf("UnlinkHeaderImageFromContent() error = %v", err)
+	}
+
+	// Verify only non-header remains
+	images, _ := svc.GetContentImagesWithDetails(ctx, content.ID)
+	if len(images) != 1 {
+		t.Errorf("Expected 1 image after unlink, got %d", len(images))
+	}
+	if len(images) > 0 && images[0].IsHeader {
+		t.Error("Remaining image should not be header")
+	}
+}
+
+func TestServiceGetAllContentWithMeta(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "All Content Meta Site", "all-content-meta-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	for i := 0; i < 3; i++ {
+		content := NewContent(site.ID, section.ID, "Post "+string(rune('A'+i)), "Body")
+		content.CreatedBy = uuid.New()
+		content.UpdatedBy = content.CreatedBy
+		svc.CreateContent(ctx, content)
+	}
+
+	contents, err := svc.GetAllContentWithMeta(ctx, site.ID)
+	if err != nil {
+		t.Errorf("GetAllContentWithMeta() error = %v", err)
+	}
+	if len(contents) != 3 {
+		t.Errorf("Expected 3 contents, got %d", len(contents))
+	}
+}
+
+func TestServiceBuildUserAuthorsMap(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	site := createTestSite(t, svc, "Authors Site", "authors-site")
+
+	// Create a profile first
+	userID := uuid.New()
+	profileID := uuid.New()
+	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, surname, bio, photo_path, created_by, updated_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		profileID.String(), site.ID.String(), "p123", "author-user", "Author", "User", "Bio text", "/photos/author.jpg", userID.String(), userID.String())
+	if err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	// Create a user with profile_id reference
+	_, err = db.Exec(`INSERT INTO user (id, short_id, email, password_hash, name, status, roles, profile_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		userID.String(), "u123", "author@test.com", "hash", "authoruser", "active", "editor", profileID.String())
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	// Content with user author
+	content := NewContent(site.ID, section.ID, "Post 1", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	content.AuthorUsername = "authoruser"
+	svc.CreateContent(ctx, content)
+
+	// Content with non-existent user
+	content2 := NewContent(site.ID, section.ID, "Post 2", "Body")
+	content2.CreatedBy = uuid.New()
+	content2.UpdatedBy = content2.CreatedBy
+	content2.AuthorUsername = "unknownuser"
+	svc.CreateContent(ctx, content2)
+
+	// Contributor
+	contributor := NewContributor(site.ID, "contrib1", "Contrib", "One")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
+
+	// Content with contributor handle (should be excluded)
+	content3 := NewContent(site.ID, section.ID, "Post 3", "Body")
+	content3.CreatedBy = uuid.New()
+	content3.UpdatedBy = content3.CreatedBy
+	content3.AuthorUsername = "contrib1"
+	svc.CreateContent(ctx, content3)
+
+	contents := []*Content{content, content2, content3}
+	contributors := []*Contributor{contributor}
+
+	authorsMap := svc.BuildUserAuthorsMap(ctx, contents, contributors)
+
+	// Should have 2 entries: authoruser (with profile) and unknownuser (fallback)
+	if len(authorsMap) != 2 {
+		t.Errorf("Expected 2 authors in map, got %d", len(authorsMap))
+	}
+
+	// Check authoruser has profile info
+	author, ok := authorsMap["authoruser"]
+	if !ok {
+		t.Error("Expected authoruser in map")
+	} else {
+		if author.Name != "Author" {
+			t.Errorf("Expected name 'Author', got %q", author.Name)
+		}
+		if author.Surname != "User" {
+			t.Errorf("Expected surname 'User', got %q", author.Surname)
+		}
+		if author.Bio != "Bio text" {
+			t.Errorf("Expected bio 'Bio text', got %q", author.Bio)
+		}
+	}
+
+	// Check unknownuser has fallback
+	unknown, ok := authorsMap["unknownuser"]
+	if !ok {
+		t.Error("Expected unknownuser in map")
+	} else {
+		if unknown.Name != "unknownuser" {
+			t.Errorf("Expected fallback name 'unknownuser', got %q", unknown.Name)
+		}
+	}
+
+	// contrib1 should NOT be in map (it's a contributor handle)
+	if _, ok := authorsMap["contrib1"]; ok {
+		t.Error("contrib1 should not be in map as it's a contributor handle")
+	}
+}
+
+func TestServiceBuildUserAuthorsMapEmpty(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	authorsMap := svc.BuildUserAuthorsMap(ctx, nil, nil)
+
+	if len(authorsMap) != 0 {
+		t.Errorf("Expected empty map, got %d entries", len(authorsMap))
+	}
+}
+
+func TestServiceBuildUserAuthorsMapNoUserAuthors(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "No Authors Site", "no-authors-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
 
-	content := NewContent(site.ID, section.ID, "Meta Post", "Body")
+	// Content without author username
+	content := NewContent(site.ID, section.ID, "Post", "Body")
 	content.CreatedBy = uuid.New()
 	content.UpdatedBy = content.CreatedBy
 	svc.CreateContent(ctx, content)
 
-	meta := NewMeta(site.ID, content.ID)
-	meta.Description = "Original"
-	meta.CreatedBy = uuid.New()
-	meta.UpdatedBy = meta.CreatedBy
-	svc.CreateMeta(ctx, meta)
-
-	meta.Description = "Updated"
-	meta.UpdatedAt = time.Now()
+	contents := []*Content{content}
+	authorsMap := svc.BuildUserAuthorsMap(ctx, contents, nil)
 
-	if err := svc.UpdateMeta(ctx, meta); err != nil {
-		t.Errorf("UpdateMeta() error = %v", err)
+	if len(authorsMap) != 0 {
+		t.Errorf("Expected empty map, got %d entries", len(authorsMap))
 	}
 }
 
-func TestServiceCreateContributor(t *testing.T) {
+func TestServiceAddTagToContentCreatesTag(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Contributor Site", "contributor-site")
+	site := createTestSite(t, svc, "Tag Create Site", "tag-create-site")
 
-	contributor := NewContributor(site.ID, "johndoe", "John", "Doe")
-	contributor.Bio = "Software developer"
-	contributor.SocialLinks = []SocialLink{
-		{Platform: "twitter", Handle: "@johndoe"},
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	content := NewContent(site.ID, section.ID, "Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	// Add non-existent tag by name - should create the tag
+	err := svc.AddTagToContent(ctx, content.ID, "newtag", site.ID)
+	if err != nil {
+		t.Errorf("AddTagToContent() should create tag if not exists, got error: %v", err)
 	}
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
 
-	if err := svc.CreateContributor(ctx, contributor); err != nil {
-		t.Errorf("CreateContributor() error = %v", err)
+	// Verify tag was created
+	tags, err := svc.GetTagsForContent(ctx, content.ID)
+	if err != nil {
+		t.Errorf("GetTagsForContent() error = %v", err)
+	}
+	if len(tags) != 1 {
+		t.Errorf("Expected 1 tag, got %d", len(tags))
+	}
+	if len(tags) > 0 && tags[0].Name != "newtag" {
+		t.Errorf("Expected tag name 'newtag', got %q", tags[0].Name)
 	}
 }
 
-func TestServiceGetContributor(t *testing.T) {
+func TestServiceContributorCRUD(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Get Contributor Site", "get-contributor-site")
+	site := createTestSite(t, svc, "Contrib CRUD Site", "contrib-crud-site")
 
-	contributor := NewContributor(site.ID, "janedoe", "Jane", "Doe")
+	// Create contributor with all fields
+	contributor := NewContributor(site.ID, "testcontrib", "Test", "Contributor")
+	contributor.Bio = "A test contributor"
+	contributor.Role = "author"
+	contributor.PhotoPath = "/photos/test.jpg"
 	contributor.CreatedBy = uuid.New()
 	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
 
-	tests := []struct {
-		name    string
-		id      uuid.UUID
-		wantErr error
-	}{
-		{
-			name:    "existing contributor",
-			id:      contributor.ID,
-			wantErr: nil,
-		},
-		{
-			name:    "non existent contributor",
-			id:      uuid.New(),
-			wantErr: ErrNotFound,
-		},
+	err := svc.CreateContributor(ctx, contributor)
+	if err != nil {
+		t.Fatalf("CreateContributor() error = %v", err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := svc.GetContributor(ctx, tt.id)
-			if tt.wantErr != nil {
-				if !errors.Is(err, tt.wantErr) {
-					t.Errorf("GetContributor() error = %v, wantErr %v", err, tt.wantErr)
-				}
-			}
-		})
+	// Get contributor
+	got, err := svc.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Errorf("GetContributor() error = %v", err)
+	}
+	if got.Handle != "testcontrib" {
+		t.Errorf("Handle = %q, want %q", got.Handle, "testcontrib")
+	}
+	if got.Bio != "A test contributor" {
+		t.Errorf("Bio = %q, want %q", got.Bio, "A test contributor")
+	}
+
+	// Update contributor
+	got.Name = "Updated"
+	got.Surname = "Name"
+	got.Bio = "Updated bio"
+	err = svc.UpdateContributor(ctx, got)
+	if err != nil {
+		t.Errorf("UpdateContributor() error = %v", err)
+	}
+
+	// Verify update
+	updated, err := svc.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Errorf("GetContributor() after update error = %v", err)
+	}
+	if updated.Name != "Updated" {
+		t.Errorf("Name = %q, want %q", updated.Name, "Updated")
+	}
+	if updated.Bio != "Updated bio" {
+		t.Errorf("Bio = %q, want %q", updated.Bio, "Updated bio")
+	}
+
+	// List contributors
+	contributors, err := svc.GetContributors(ctx, site.ID)
+	if err != nil {
+		t.Errorf("GetContributors() error = %v", err)
+	}
+	if len(contributors) != 1 {
+		t.Errorf("Expected 1 contributor, got %d", len(contributors))
+	}
+
+	// Delete contributor
+	err = svc.DeleteContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Errorf("DeleteContributor() error = %v", err)
+	}
+
+	// Verify deletion
+	_, err = svc.GetContributor(ctx, contributor.ID)
+	if err == nil {
+		t.Error("Expected error when getting deleted contributor")
 	}
 }
 
-func TestServiceGetContributors(t *testing.T) {
+func TestServiceGetContributorNotFound(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Contributors Site", "contributors-site")
 
+	_, err := svc.GetContributor(ctx, uuid.New())
+	if err == nil {
+		t.Error("Expected error when getting non-existent contributor")
+	}
+}
+
+func TestServiceMultipleContributors(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Multi Contrib Site", "multi-contrib-site")
+
+	// Create multiple contributors
 	for i := 0; i < 3; i++ {
-		contributor := NewContributor(site.ID, "user"+string(rune('a'+i)), "User", string(rune('A'+i)))
+		contributor := NewContributor(site.ID, "contrib"+string(rune('1'+i)), "Contrib", string(rune('A'+i)))
 		contributor.CreatedBy = uuid.New()
 		contributor.UpdatedBy = contributor.CreatedBy
-		svc.CreateContributor(ctx, contributor)
+		if err := svc.CreateContributor(ctx, contributor); err != nil {
+			t.Fatalf("CreateContributor() error = %v", err)
+		}
 	}
 
 	contributors, err := svc.GetContributors(ctx, site.ID)
 	if err != nil {
-		t.Fatalf("GetContributors() error = %v", err)
+		t.Errorf("GetContributors() error = %v", err)
+	}
+	if len(contributors) != 3 {
+		t.Errorf("Expected 3 contributors, got %d", len(contributors))
+	}
+}
+
+func TestServiceCreateContentWithContributor(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Content Contrib Site", "content-contrib-site")
+
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
+
+	// Create contributor
+	contributor := NewContributor(site.ID, "author1", "Author", "One")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
+
+	// Create content with contributor
+	content := NewContent(site.ID, section.ID, "Authored Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	content.ContributorID = &contributor.ID
+	content.ContributorHandle = contributor.Handle
+
+	err := svc.CreateContent(ctx, content)
+	if err != nil {
+		t.Fatalf("CreateContent() with contributor error = %v", err)
+	}
+
+	// Get content with meta (which includes contributor info)
+	got, err := svc.GetContentWithMeta(ctx, content.ID)
+	if err != nil {
+		t.Errorf("GetContentWithMeta() error = %v", err)
 	}
-	if len(contributors) != 3 {
-		t.Errorf("GetContributors() returned %d contributors, want 3", len(contributors))
+	if got.ContributorID == nil {
+		t.Error("ContributorID should not be nil")
+	} else if *got.ContributorID != contributor.ID {
+		t.Errorf("ContributorID = %v, want %v", *got.ContributorID, contributor.ID)
 	}
 }
 
-func TestServiceUpdateContributor(t *testing.T) {
+func TestServiceUpdateContentFields(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Contributor Site", "update-contributor-site")
+	site := createTestSite(t, svc, "Update Content Site", "update-content-site")
 
-	contributor := NewContributor(site.ID, "updateuser", "Original", "Name")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
 
-	contributor.Name = "Updated"
-	contributor.Bio = "New bio"
-	contributor.UpdatedAt = time.Now()
+	content := NewContent(site.ID, section.ID, "Original Title", "Original Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
 
-	if err := svc.UpdateContributor(ctx, contributor); err != nil {
-		t.Errorf("UpdateContributor() error = %v", err)
+	// Update multiple fields
+	content.Heading = "Updated Title"
+	content.Body = "Updated Body"
+	content.Summary = "A summary"
+	content.Draft = false
+	content.Featured = true
+	content.Series = "test-series"
+	content.SeriesOrder = 1
+	now := time.Now()
+	content.PublishedAt = &now
+
+	err := svc.UpdateContent(ctx, content)
+	if err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+
+	// Verify all updates
+	updated, err := svc.GetContent(ctx, content.ID)
+	if err != nil {
+		t.Errorf("GetContent() error = %v", err)
+	}
+	if updated.Heading != "Updated Title" {
+		t.Errorf("Heading = %q, want %q", updated.Heading, "Updated Title")
+	}
+	if updated.Summary != "A summary" {
+		t.Errorf("Summary = %q, want %q", updated.Summary, "A summary")
+	}
+	if updated.Featured != true {
+		t.Error("Featured should be true")
+	}
+	if updated.Series != "test-series" {
+		t.Errorf("Series = %q, want %q", updated.Series, "test-series")
 	}
 }
 
-func TestServiceDeleteContributor(t *testing.T) {
+func TestServiceContributorWithSocialLinks(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Delete Contributor Site", "delete-contributor-site")
+	site := createTestSite(t, svc, "Social Links Site", "social-links-site")
 
-	contributor := NewContributor(site.ID, "deleteuser", "Delete", "Me")
+	// Create contributor with social links
+	contributor := NewContributor(site.ID, "socialuser", "Social", "User")
+	contributor.SocialLinks = []SocialLink{
+		{Platform: "twitter", Handle: "@socialuser", URL: "https://twitter.com/socialuser"},
+		{Platform: "github", Handle: "socialuser", URL: "https://github.com/socialuser"},
+		{Platform: "linkedin", Handle: "socialuser", URL: "https://linkedin.com/in/socialuser"},
+	}
 	contributor.CreatedBy = uuid.New()
 	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
 
-	if err := svc.DeleteContributor(ctx, contributor.ID); err != nil {
-		t.Errorf("DeleteContributor() error = %v", err)
+	err := svc.CreateContributor(ctx, contributor)
+	if err != nil {
+		t.Fatalf("CreateContributor() error = %v", err)
 	}
 
-	_, err := svc.GetContributor(ctx, contributor.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Error("Contributor should have been deleted")
+	// Get contributor and verify social links
+	got, err := svc.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Errorf("GetContributor() error = %v", err)
+	}
+	if len(got.SocialLinks) != 3 {
+		t.Errorf("Expected 3 social links, got %d", len(got.SocialLinks))
+	}
+	if got.SocialLinks[0].Platform != "twitter" {
+		t.Errorf("First social link platform = %q, want %q", got.SocialLinks[0].Platform, "twitter")
 	}
 }
 
-func TestServiceSetContributorProfile(t *testing.T) {
+func TestServiceContributorWithProfile(t *testing.T) {
 	svc, db, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Profile Contributor Site", "profile-contributor-site")
-
-	contributor := NewContributor(site.ID, "profileuser", "Profile", "User")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
+	site := createTestSite(t, svc, "Contrib Profile Site", "contrib-profile-site")
 
-	// Create a real profile in the database
+	// Create a profile first
 	profileID := uuid.New()
-	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, created_by, updated_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
-		profileID.String(), site.ID.String(), "abc12345", "test-profile", "Test Profile", contributor.CreatedBy.String(), contributor.CreatedBy.String())
+	creatorID := uuid.New()
+	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, surname, bio, photo_path, social_links, created_by, updated_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		profileID.String(), site.ID.String(), "prof1234", "contrib-profile", "Profile", "Name", "Profile bio", "/photos/profile.jpg",
+		`[{"platform":"twitter","handle":"@profile"}]`,
+		creatorID.String(), creatorID.String())
 	if err != nil {
-		t.Fatalf("Failed to create test profile: %v", err)
+		t.Fatalf("Failed to create profile: %v", err)
 	}
 
-	if err := svc.SetContributorProfile(ctx, contributor.ID, profileID, "admin"); err != nil {
-		t.Errorf("SetContributorProfile() error = %v", err)
+	// Create contributor
+	contributor := NewContributor(site.ID, "profcontrib", "Prof", "Contrib")
+	contributor.CreatedBy = creatorID
+	contributor.UpdatedBy = creatorID
+	err = svc.CreateContributor(ctx, contributor)
+	if err != nil {
+		t.Fatalf("CreateContributor() error = %v", err)
 	}
-}
-
-func TestServiceUpdateTag(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
-
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Tag Site", "update-tag-site")
-
-	tag := NewTag(site.ID, "Original Tag")
-	tag.CreatedBy = uuid.New()
-	tag.UpdatedBy = tag.CreatedBy
-	svc.CreateTag(ctx, tag)
-
-	tag.Name = "Updated Tag"
-	tag.Slug = Slugify("Updated Tag")
-	tag.UpdatedAt = time.Now()
 
-	if err := svc.UpdateTag(ctx, tag); err != nil {
-		t.Errorf("UpdateTag() error = %v", err)
+	// Set contributor profile
+	err = svc.SetContributorProfile(ctx, contributor.ID, profileID, creatorID.String())
+	if err != nil {
+		t.Fatalf("SetContributorProfile() error = %v", err)
 	}
-}
-
-func TestServiceDeleteTag(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
-
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Delete Tag Site", "delete-tag-site")
-
-	tag := NewTag(site.ID, "To Delete")
-	tag.CreatedBy = uuid.New()
-	tag.UpdatedBy = tag.CreatedBy
-	svc.CreateTag(ctx, tag)
 
-	if err := svc.DeleteTag(ctx, tag.ID); err != nil {
-		t.Errorf("DeleteTag() error = %v", err)
+	// List contributors (this uses contributorWithProfileFromSQLC)
+	contributors, err := svc.GetContributors(ctx, site.ID)
+	if err != nil {
+		t.Errorf("GetContributors() error = %v", err)
+	}
+	if len(contributors) != 1 {
+		t.Fatalf("Expected 1 contributor, got %d", len(contributors))
 	}
 
-	_, err := svc.GetTag(ctx, tag.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Error("Tag should have been deleted")
+	// Check that profile data was loaded
+	c := contributors[0]
+	if c.ProfileID == nil {
+		t.Error("ProfileID should not be nil")
+	}
+	if c.PhotoPath != "/photos/profile.jpg" {
+		t.Errorf("PhotoPath = %q, want %q", c.PhotoPath, "/photos/profile.jpg")
+	}
+	if len(c.SocialLinks) != 1 || c.SocialLinks[0].Handle != "@profile" {
+		t.Errorf("SocialLinks = %+v, want the profile's links", c.SocialLinks)
+	}
+	if c.Bio != "Profile bio" {
+		t.Errorf("Bio = %q, want the profile bio when the contributor has none", c.Bio)
 	}
 }
 
-func TestServiceUpdateSetting(t *testing.T) {
+func TestServiceGetContentByContributor(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Setting Site", "update-setting-site")
+	site := createTestSite(t, svc, "By Contributor", "by-contributor")
+	svc.CreateSection(ctx, NewSection(site.ID, "Blog", "", "blog"))
+	section, _ := svc.GetSectionByPath(ctx, site.ID, "blog")
 
-	setting := NewSetting(site.ID, "original_param", "original_value")
-	setting.CreatedBy = uuid.New()
-	setting.UpdatedBy = setting.CreatedBy
-	svc.CreateSetting(ctx, setting)
+	contributor := NewContributor(site.ID, "guest", "Guest", "Writer")
+	if err := svc.CreateContributor(ctx, contributor); err != nil {
+		t.Fatalf("CreateContributor() error = %v", err)
+	}
 
-	setting.Value = "updated_value"
-	setting.UpdatedAt = time.Now()
+	older := time.Now().Add(-48 * time.Hour)
+	newer := time.Now().Add(-time.Hour)
+	for _, c := range []struct {
+		heading     string
+		publishedAt *time.Time
+		credited    bool
+	}{
+		{"Older", &older, true},
+		{"Newer", &newer, true},
+		{"Someone else", &newer, false},
+	} {
+		content := NewContent(site.ID, section.ID, c.heading, "Body")
+		content.PublishedAt = c.publishedAt
+		if c.credited {
+			content.ContributorID = &contributor.ID
+			content.ContributorHandle = contributor.Handle
+		}
+		if err := svc.CreateContent(ctx, content); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+	}
 
-	if err := svc.UpdateSetting(ctx, setting); err != nil {
-		t.Errorf("UpdateSetting() error = %v", err)
+	contents, err := svc.GetContentByContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Fatalf("GetContentByContributor() error = %v", err)
+	}
+	if len(contents) != 2 || contents[0].Heading != "Newer" || contents[1].Heading != "Older" {
+		var headings []string
+		for _, c := range contents {
+			headings = append(headings, c.Heading)
+		}
+		t.Errorf("GetContentByContributor() = %v, want [Newer Older]", headings)
 	}
 }
 
-func TestServiceDeleteSetting(t *testing.T) {
+func TestServiceGetFeaturedContent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Delete Setting Site", "delete-setting-site")
+	site := createTestSite(t, svc, "Featured", "featured")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
 
-	setting := NewSetting(site.ID, "to_delete", "value")
-	setting.CreatedBy = uuid.New()
-	setting.UpdatedBy = setting.CreatedBy
-	svc.CreateSetting(ctx, setting)
+	older := time.Now().Add(-48 * time.Hour)
+	newer := time.Now().Add(-time.Hour)
+	later := time.Now().Add(24 * time.Hour)
+	for _, c := range []struct {
+		heading     string
+		featured    bool
+		draft       bool
+		visibility  string
+		publishedAt *time.Time
+	}{
+		{"Older", true, false, VisibilityPublic, &older},
+		{"Newer", true, false, VisibilityPublic, &newer},
+		{"Plain", false, false, VisibilityPublic, &newer},
+		{"Draft", true, true, VisibilityPublic, &newer},
+		{"Unlisted", true, false, VisibilityUnlisted, &newer},
+		{"Scheduled", true, false, VisibilityPublic, &later},
+	} {
+		content := NewContent(site.ID, section.ID, c.heading, "Body")
+		content.Featured = c.featured
+		content.Draft = c.draft
+		content.Visibility = c.visibility
+		content.PublishedAt = c.publishedAt
+		if err := svc.CreateContent(ctx, content); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+	}
 
-	if err := svc.DeleteSetting(ctx, setting.ID); err != nil {
-		t.Errorf("DeleteSetting() error = %v", err)
+	contents, err := svc.GetFeaturedContent(ctx, site.ID, 5)
+	if err != nil {
+		t.Fatalf("GetFeaturedContent() error = %v", err)
+	}
+	var headings []string
+	for _, c := range contents {
+		headings = append(headings, c.Heading)
+	}
+	if len(headings) != 2 || headings[0] != "Newer" || headings[1] != "Older" {
+		t.Errorf("GetFeaturedContent() = %v, want [Newer Older]", headings)
 	}
 
-	_, err := svc.GetSetting(ctx, setting.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Error("Setting should have been deleted")
+	contents, err = svc.GetFeaturedContent(ctx, site.ID, 1)
+	if err != nil {
+		t.Fatalf("GetFeaturedContent() error = %v", err)
+	}
+	if len(contents) != 1 || contents[0].Heading != "Newer" {
+		t.Errorf("GetFeaturedContent() with limit 1 = %d items, want only Newer", len(contents))
 	}
 }
 
-func TestServiceUpdateImage(t *testing.T) {
+func TestServiceGetContentByDateRange(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Image Site", "update-image-site")
+	site := createTestSite(t, svc, "Archive", "archive")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
 
-	image := NewImage(site.ID, "original.jpg", "/images/original.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+		return &d
+	}
+	later := time.Now().Add(24 * time.Hour)
+	for _, c := range []struct {
+		heading     string
+		draft       bool
+		visibility  string
+		publishedAt *time.Time
+	}{
+		{"Early March", false, VisibilityPublic, date(2024, time.March, 2)},
+		{"Late March", false, VisibilityPublic, date(2024, time.March, 30)},
+		{"April", false, VisibilityPublic, date(2024, time.April, 1)},
+		{"February", false, VisibilityPublic, date(2024, time.February, 29)},
+		{"Draft", true, VisibilityPublic, date(2024, time.March, 10)},
+		{"Unlisted", false, VisibilityUnlisted, date(2024, time.March, 10)},
+		{"Undated", false, VisibilityPublic, nil},
+		{"Scheduled", false, VisibilityPublic, &later},
+	} {
+		content := NewContent(site.ID, section.ID, c.heading, "Body")
+		content.Draft = c.draft
+		content.Visibility = c.visibility
+		content.PublishedAt = c.publishedAt
+		if err := svc.CreateContent(ctx, content); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+	}
 
-	image.FileName = "updated.jpg"
-	image.FilePath = "/images/updated.jpg"
-	image.AltText = "Updated alt"
-	image.UpdatedAt = time.Now()
+	contents, err := svc.GetContentByDateRange(ctx, site.ID, *date(2024, time.March, 1), *date(2024, time.April, 1))
+	if err != nil {
+		t.Fatalf("GetContentByDateRange() error = %v", err)
+	}
+	var headings []string
+	for _, c := range contents {
+		headings = append(headings, c.Heading)
+	}
+	if strings.Join(headings, ",") != "Late March,Early March" {
+		t.Errorf("GetContentByDateRange() = %v, want [Late March Early March]", headings)
+	}
 
-	if err := svc.UpdateImage(ctx, image); err != nil {
-		t.Errorf("UpdateImage() error = %v", err)
+	contents, err = svc.GetContentByDateRange(ctx, site.ID, time.Now(), later.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetContentByDateRange() error = %v", err)
+	}
+	if len(contents) != 0 {
+		t.Errorf("GetContentByDateRange() over the future = %d items, want scheduled content left out", len(contents))
 	}
 }
 
-func TestServiceDeleteImage(t *testing.T) {
+func TestServiceGetContentWithMetaNotFound(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Delete Image Site", "delete-image-site")
-
-	image := NewImage(site.ID, "todelete.jpg", "/images/todelete.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
-
-	if err := svc.DeleteImage(ctx, image.ID); err != nil {
-		t.Errorf("DeleteImage() error = %v", err)
-	}
+	ctx := context.Background()
 
-	_, err := svc.GetImage(ctx, image.ID)
+	_, err := svc.GetContentWithMeta(ctx, uuid.New())
 	if !errors.Is(err, ErrNotFound) {
-		t.Error("Image should have been deleted")
+		t.Errorf("GetContentWithMeta() for non-existent content should return ErrNotFound, got: %v", err)
 	}
 }
 
-func TestServiceSectionImageOperations(t *testing.T) {
+func TestServiceGetContentWithMetaWithTags(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Section Image Site", "section-image-site")
+	site := createTestSite(t, svc, "Meta Tags Site", "meta-tags-site")
 
-	section := NewSection(site.ID, "Test Section", "", "/test")
+	section := NewSection(site.ID, "Blog", "", "/blog")
 	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
 
-	image := NewImage(site.ID, "section-img.jpg", "/images/section-img.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	content := NewContent(site.ID, section.ID, "Tagged Content", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
 
-	// Link image to section
-	if err := svc.LinkImageToSection(ctx, section.ID, image.ID, true); err != nil {
-		t.Errorf("LinkImageToSection() error = %v", err)
-	}
+	// Add tags
+	svc.AddTagToContent(ctx, content.ID, "tag1", site.ID)
+	svc.AddTagToContent(ctx, content.ID, "tag2", site.ID)
 
-	// Get section images
-	images, err := svc.GetSectionImagesWithDetails(ctx, section.ID)
+	// Get with meta (should include tags)
+	got, err := svc.GetContentWithMeta(ctx, content.ID)
 	if err != nil {
-		t.Errorf("GetSectionImagesWithDetails() error = %v", err)
-	}
-	if len(images) != 1 {
-		t.Errorf("Expected 1 image, got %d", len(images))
-	}
-
-	// Get section image details
-	if len(images) > 0 {
-		details, err := svc.GetSectionImageDetails(ctx, images[0].SectionImageID)
-		if err != nil {
-			t.Errorf("GetSectionImageDetails() error = %v", err)
-		}
-		if details == nil {
-			t.Error("Expected details, got nil")
-		}
-
-		// Unlink image from section
-		if err := svc.UnlinkImageFromSection(ctx, images[0].SectionImageID); err != nil {
-			t.Errorf("UnlinkImageFromSection() error = %v", err)
-		}
+		t.Fatalf("GetContentWithMeta() error = %v", err)
 	}
-
-	// Verify unlink
-	images, _ = svc.GetSectionImagesWithDetails(ctx, section.ID)
-	if len(images) != 0 {
-		t.Errorf("Expected 0 images after unlink, got %d", len(images))
+	if len(got.Tags) != 2 {
+		t.Errorf("Expected 2 tags, got %d", len(got.Tags))
 	}
 }
 
-func TestServiceGetContentImageDetails(t *testing.T) {
+func TestServiceUpdateContentWithContributor(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Content Image Details Site", "content-image-details-site")
+	site := createTestSite(t, svc, "Update Contrib Site", "update-contrib-site")
 
 	section := NewSection(site.ID, "Blog", "", "/blog")
 	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
 
-	content := NewContent(site.ID, section.ID, "Image Post", "Body")
+	// Create contributor
+	contributor := NewContributor(site.ID, "author", "Author", "Name")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
+
+	// Create content without contributor
+	content := NewContent(site.ID, section.ID, "Post", "Body")
 	content.CreatedBy = uuid.New()
 	content.UpdatedBy = content.CreatedBy
 	svc.CreateContent(ctx, content)
 
-	image := NewImage(site.ID, "detail.jpg", "/images/detail.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
-
-	svc.LinkImageToContent(ctx, content.ID, image.ID, false)
+	// Update content to add contributor
+	content.ContributorID = &contributor.ID
+	content.ContributorHandle = contributor.Handle
+	content.UpdatedAt = time.Now()
 
-	images, _ := svc.GetContentImagesWithDetails(ctx, content.ID)
-	if len(images) != 1 {
-		t.Fatalf("Expected 1 image")
+	err := svc.UpdateContent(ctx, content)
+	if err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
 	}
 
-	details, err := svc.GetContentImageDetails(ctx, images[0].ContentImageID)
+	// Verify
+	updated, err := svc.GetContentWithMeta(ctx, content.ID)
 	if err != nil {
-		t.Errorf("GetContentImageDetails() error = %v", err)
+		t.Errorf("GetContentWithMeta() error = %v", err)
 	}
-	if details == nil {
-		t.Error("Expected details, got nil")
+	if updated.ContributorID == nil {
+		t.Error("ContributorID should not be nil")
 	}
-	if details.FilePath != "/images/detail.jpg" {
-		t.Errorf("FilePath = %q, want %q", details.FilePath, "/images/detail.jpg")
+	if updated.ContributorHandle != "author" {
+		t.Errorf("ContributorHandle = %q, want %q", updated.ContributorHandle, "author")
 	}
 }
 
-func TestServiceUnlinkHeaderImageFromContent(t *testing.T) {
+func TestServiceContributorSocialLinksUpdate(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Unlink Header Site", "unlink-header-site")
-
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	content := NewContent(site.ID, section.ID, "Header Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	site := createTestSite(t, svc, "Update Social Site", "update-social-site")
 
-	// Add a header image
-	headerImage := NewImage(site.ID, "header.jpg", "/images/header.jpg")
-	headerImage.CreatedBy = uuid.New()
-	headerImage.UpdatedBy = headerImage.CreatedBy
-	svc.CreateImage(ctx, headerImage)
-	svc.LinkImageToContent(ctx, content.ID, headerImage.ID, true)
+	// Create contributor without social links
+	contributor := NewContributor(site.ID, "nosocial", "No", "Social")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
 
-	// Add a non-header image
-	otherImage := NewImage(site.ID, "other.jpg", "/images/other.jpg")
-	otherImage.CreatedBy = uuid.New()
-	otherImage.UpdatedBy = otherImage.CreatedBy
-	svc.CreateImage(ctx, otherImage)
-	svc.LinkImageToContent(ctx, content.ID, otherImage.ID, false)
+	// Update with social links
+	contributor.SocialLinks = []SocialLink{
+		{Platform: "website", Handle: "", URL: "https://example.com"},
+	}
+	contributor.UpdatedAt = time.Now()
 
-	// Unlink header
-	if err := svc.UnlinkHeaderImageFromContent(ctx, content.ID); err != nil {
-		t.Errorf("UnlinkHeaderImageFromContent() error = %v", err)
+	err := svc.UpdateContributor(ctx, contributor)
+	if err != nil {
+		t.Fatalf("UpdateContributor() error = %v", err)
 	}
 
-	// Verify only non-header remains
-	images, _ := svc.GetContentImagesWithDetails(ctx, content.ID)
-	if len(images) != 1 {
-		t.Errorf("Expected 1 image after unlink, got %d", len(images))
+	// Verify
+	updated, err := svc.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Errorf("GetContributor() error = %v", err)
 	}
-	if len(images) > 0 && images[0].IsHeader {
-		t.Error("Remaining image should not be header")
+	if len(updated.SocialLinks) != 1 {
+		t.Errorf("Expected 1 social link, got %d", len(updated.SocialLinks))
 	}
 }
 
-func TestServiceGetAllContentWithMeta(t *testing.T) {
+func TestServiceUnlinkImageFromContentNotFound(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "All Content Meta Site", "all-content-meta-site")
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	for i := 0; i < 3; i++ {
-		content := NewContent(site.ID, section.ID, "Post "+string(rune('A'+i)), "Body")
-		content.CreatedBy = uuid.New()
-		content.UpdatedBy = content.CreatedBy
-		svc.CreateContent(ctx, content)
+	// Try to unlink non-existent content image - should not error (SQL DELETE)
+	err := svc.UnlinkImageFromContent(ctx, uuid.New())
+	if err != nil {
+		// Note: SQLite DELETE doesn't error on non-existent rows
+		t.Logf("UnlinkImageFromContent() returned error for non-existent: %v", err)
 	}
+}
 
-	contents, err := svc.GetAllContentWithMeta(ctx, site.ID)
+func TestServiceUnlinkImageFromSectionNotFound(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Try to unlink non-existent section image - should not error (SQL DELETE)
+	err := svc.UnlinkImageFromSection(ctx, uuid.New())
 	if err != nil {
-		t.Errorf("GetAllContentWithMeta() error = %v", err)
-	}
-	if len(contents) != 3 {
-		t.Errorf("Expected 3 contents, got %d", len(contents))
+		t.Logf("UnlinkImageFromSection() returned error for non-existent: %v", err)
 	}
 }
 
-func TestServiceBuildUserAuthorsMap(t *testing.T) {
+func TestServiceCreateContributorWithProfileID(t *testing.T) {
 	svc, db, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-
-	site := createTestSite(t, svc, "Authors Site", "authors-site")
+	site := createTestSite(t, svc, "Contrib PID Site", "contrib-pid-site")
 
 	// Create a profile first
-	userID := uuid.New()
 	profileID := uuid.New()
-	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, surname, bio, photo_path, created_by, updated_by, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
-		profileID.String(), site.ID.String(), "p123", "author-user", "Author", "User", "Bio text", "/photos/author.jpg", userID.String(), userID.String())
+	creatorID := uuid.New()
+	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, created_by, updated_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		profileID.String(), site.ID.String(), "pid12345", "pid-profile", "PID Profile", creatorID.String(), creatorID.String())
 	if err != nil {
 		t.Fatalf("Failed to create profile: %v", err)
 	}
 
-	// Create a user with profile_id reference
-	_, err = db.Exec(`INSERT INTO user (id, short_id, email, password_hash, name, status, roles, profile_id, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
-		userID.String(), "u123", "author@test.com", "hash", "authoruser", "active", "editor", profileID.String())
+	// Create contributor with profile ID
+	contributor := NewContributor(site.ID, "pidcontrib", "PID", "Contrib")
+	contributor.ProfileID = &profileID
+	contributor.CreatedBy = creatorID
+	contributor.UpdatedBy = creatorID
+
+	err = svc.CreateContributor(ctx, contributor)
 	if err != nil {
-		t.Fatalf("Failed to create user: %v", err)
+		t.Fatalf("CreateContributor() error = %v", err)
 	}
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	// Content with user author
-	content := NewContent(site.ID, section.ID, "Post 1", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	content.AuthorUsername = "authoruser"
-	svc.CreateContent(ctx, content)
-
-	// Content with non-existent user
-	content2 := NewContent(site.ID, section.ID, "Post 2", "Body")
-	content2.CreatedBy = uuid.New()
-	content2.UpdatedBy = content2.CreatedBy
-	content2.AuthorUsername = "unknownuser"
-	svc.CreateContent(ctx, content2)
-
-	// Contributor
-	contributor := NewContributor(site.ID, "contrib1", "Contrib", "One")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
-
-	// Content with contributor handle (should be excluded)
-	content3 := NewContent(site.ID, section.ID, "Post 3", "Body")
-	content3.CreatedBy = uuid.New()
-	content3.UpdatedBy = content3.CreatedBy
-	content3.AuthorUsername = "contrib1"
-	svc.CreateContent(ctx, content3)
+	// Verify profile ID was saved
+	got, err := svc.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Errorf("GetContributor() error = %v", err)
+	}
+	if got.ProfileID == nil {
+		t.Error("ProfileID should not be nil")
+	} else if *got.ProfileID != profileID {
+		t.Errorf("ProfileID = %v, want %v", *got.ProfileID, profileID)
+	}
+}
 
-	contents := []*Content{content, content2, content3}
-	contributors := []*Contributor{contributor}
+func TestServiceDeleteContributorVerify(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
 
-	authorsMap := svc.BuildUserAuthorsMap(ctx, contents, contributors)
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Del Contrib Site", "del-contrib-site")
 
-	// Should have 2 entries: authoruser (with profile) and unknownuser (fallback)
-	if len(authorsMap) != 2 {
-		t.Errorf("Expected 2 authors in map, got %d", len(authorsMap))
-	}
+	contributor := NewContributor(site.ID, "delme", "Del", "Me")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	svc.CreateContributor(ctx, contributor)
 
-	// Check authoruser has profile info
-	author, ok := authorsMap["authoruser"]
-	if !ok {
-		t.Error("Expected authoruser in map")
-	} else {
-		if author.Name != "Author" {
-			t.Errorf("Expected name 'Author', got %q", author.Name)
-		}
-		if author.Surname != "User" {
-			t.Errorf("Expected surname 'User', got %q", author.Surname)
-		}
-		if author.Bio != "Bio text" {
-			t.Errorf("Expected bio 'Bio text', got %q", author.Bio)
-		}
+	// Verify exists
+	_, err := svc.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Fatalf("Contributor should exist: %v", err)
 	}
 
-	// Check unknownuser has fallback
-	unknown, ok := authorsMap["unknownuser"]
-	if !ok {
-		t.Error("Expected unknownuser in map")
-	} else {
-		if unknown.Name != "unknownuser" {
-			t.Errorf("Expected fallback name 'unknownuser', got %q", unknown.Name)
-		}
+	// Delete
+	err = svc.DeleteContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Fatalf("DeleteContributor() error = %v", err)
 	}
 
-	// contrib1 should NOT be in map (it's a contributor handle)
-	if _, ok := authorsMap["contrib1"]; ok {
-		t.Error("contrib1 should not be in map as it's a contributor handle")
+	// Verify deleted
+	_, err = svc.GetContributor(ctx, contributor.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceBuildUserAuthorsMapEmpty(t *testing.T) {
+func TestServiceContributorEmptySocialLinks(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Empty Social Site", "empty-social-site")
 
-	authorsMap := svc.BuildUserAuthorsMap(ctx, nil, nil)
+	// Create contributor with empty social links
+	contributor := NewContributor(site.ID, "emptylinks", "Empty", "Links")
+	contributor.SocialLinks = []SocialLink{}
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
 
-	if len(authorsMap) != 0 {
-		t.Errorf("Expected empty map, got %d entries", len(authorsMap))
+	err := svc.CreateContributor(ctx, contributor)
+	if err != nil {
+		t.Fatalf("CreateContributor() error = %v", err)
+	}
+
+	// Get and verify
+	got, err := svc.GetContributor(ctx, contributor.ID)
+	if err != nil {
+		t.Errorf("GetContributor() error = %v", err)
+	}
+	if got.SocialLinks == nil {
+		// Empty slice is acceptable
 	}
 }
 
-func TestServiceBuildUserAuthorsMapNoUserAuthors(t *testing.T) {
+func TestServiceSiteWithDefaultLayout(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "No Authors Site", "no-authors-site")
+	site := createTestSite(t, svc, "Layout Site", "layout-site")
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
+	// Create a layout
+	layout := NewLayout(site.ID, "Default Layout", "The default layout")
+	layout.Code = "<html>{{.Content}}</html>"
+	layout.CreatedBy = uuid.New()
+	layout.UpdatedBy = layout.CreatedBy
+	svc.CreateLayout(ctx, layout)
 
-	// Content without author username
-	content := NewContent(site.ID, section.ID, "Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	// Update site with default layout
+	site.DefaultLayoutID = layout.ID
+	site.DefaultLayoutName = layout.Name
+	site.UpdatedAt = time.Now()
 
-	contents := []*Content{content}
-	authorsMap := svc.BuildUserAuthorsMap(ctx, contents, nil)
+	err := svc.UpdateSite(ctx, site)
+	if err != nil {
+		t.Fatalf("UpdateSite() error = %v", err)
+	}
 
-	if len(authorsMap) != 0 {
-		t.Errorf("Expected empty map, got %d entries", len(authorsMap))
+	// Get site and verify default layout fields
+	got, err := svc.GetSite(ctx, site.ID)
+	if err != nil {
+		t.Errorf("GetSite() error = %v", err)
+	}
+	if got.DefaultLayoutID != layout.ID {
+		t.Errorf("DefaultLayoutID = %v, want %v", got.DefaultLayoutID, layout.ID)
+	}
+	if got.DefaultLayoutName != layout.Name {
+		t.Errorf("DefaultLayoutName = %q, want %q", got.DefaultLayoutName, layout.Name)
 	}
 }
 
-func TestServiceAddTagToContentCreatesTag(t *testing.T) {
+func TestServiceContentWithHeaderImage(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Tag Create Site", "tag-create-site")
+	site := createTestSite(t, svc, "Header Img Site", "header-img-site")
 
 	section := NewSection(site.ID, "Blog", "", "/blog")
 	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
 
-	content := NewContent(site.ID, section.ID, "Post", "Body")
+	content := NewContent(site.ID, section.ID, "Header Image Post", "Body with header image")
 	content.CreatedBy = uuid.New()
 	content.UpdatedBy = content.CreatedBy
 	svc.CreateContent(ctx, content)
 
-	// Add non-existent tag by name - should create the tag
-	err := svc.AddTagToContent(ctx, content.ID, "newtag", site.ID)
-	if err != nil {
-		t.Errorf("AddTagToContent() should create tag if not exists, got error: %v", err)
-	}
+	// Create and link header image
+	image := NewImage(site.ID, "header.jpg", "posts/header.jpg")
+	image.AltText = "Header image alt text"
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
+	svc.LinkImageToContent(ctx, content.ID, image.ID, true)
 
-	// Verify tag was created
-	tags, err := svc.GetTagsForContent(ctx, content.ID)
+	// Get all content with meta (tests contentWithMetaFromSQLCAll with header image)
+	contents, err := svc.GetAllContentWithMeta(ctx, site.ID)
 	if err != nil {
-		t.Errorf("GetTagsForContent() error = %v", err)
+		t.Fatalf("GetAllContentWithMeta() error = %v", err)
 	}
-	if len(tags) != 1 {
-		t.Errorf("Expected 1 tag, got %d", len(tags))
+	if len(contents) != 1 {
+		t.Fatalf("Expected 1 content, got %d", len(contents))
 	}
-	if len(tags) > 0 && tags[0].Name != "newtag" {
-		t.Errorf("Expected tag name 'newtag', got %q", tags[0].Name)
+
+	c := contents[0]
+	if c.HeaderImageURL == "" {
+		t.Error("HeaderImageURL should not be empty")
+	}
+	if c.HeaderImageAlt != "Header image alt text" {
+		t.Errorf("HeaderImageAlt = %q, want %q", c.HeaderImageAlt, "Header image alt text")
 	}
 }
 
-func TestServiceContributorCRUD(t *testing.T) {
+func TestServiceMetaAllFields(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Contrib CRUD Site", "contrib-crud-site")
+	site := createTestSite(t, svc, "Meta All Fields Site", "meta-all-fields-site")
 
-	// Create contributor with all fields
-	contributor := NewContributor(site.ID, "testcontrib", "Test", "Contributor")
-	contributor.Bio = "A test contributor"
-	contributor.Role = "author"
-	contributor.PhotoPath = "/photos/test.jpg"
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
 
-	err := svc.CreateContributor(ctx, contributor)
-	if err != nil {
-		t.Fatalf("CreateContributor() error = %v", err)
-	}
+	content := NewContent(site.ID, section.ID, "Full Meta Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
 
-	// Get contributor
-	got, err := svc.GetContributor(ctx, contributor.ID)
-	if err != nil {
-		t.Errorf("GetContributor() error = %v", err)
-	}
-	if got.Handle != "testcontrib" {
-		t.Errorf("Handle = %q, want %q", got.Handle, "testcontrib")
-	}
-	if got.Bio != "A test contributor" {
-		t.Errorf("Bio = %q, want %q", got.Bio, "A test contributor")
-	}
+	// Create meta with all fields
+	meta := NewMeta(site.ID, content.ID)
+	meta.Summary = "Test summary"
+	meta.Excerpt = "Test excerpt"
+	meta.Description = "Test description"
+	meta.Keywords = "test,keywords"
+	meta.Robots = "index,follow"
+	meta.CanonicalURL = "https://example.com/post"
+	meta.Sitemap = "weekly"
+	meta.TableOfContents = true
+	meta.Share = true
+	meta.Comments = true
+	meta.CreatedBy = uuid.New()
+	meta.UpdatedBy = meta.CreatedBy
 
-	// Update contributor
-	got.Name = "Updated"
-	got.Surname = "Name"
-	got.Bio = "Updated bio"
-	err = svc.UpdateContributor(ctx, got)
+	err := svc.CreateMeta(ctx, meta)
 	if err != nil {
-		t.Errorf("UpdateContributor() error = %v", err)
+		t.Fatalf("CreateMeta() error = %v", err)
 	}
 
-	// Verify update
-	updated, err := svc.GetContributor(ctx, contributor.ID)
+	// Get meta
+	got, err := svc.GetMetaByContentID(ctx, content.ID)
 	if err != nil {
-		t.Errorf("GetContributor() after update error = %v", err)
+		t.Fatalf("GetMetaByContentID() error = %v", err)
 	}
-	if updated.Name != "Updated" {
-		t.Errorf("Name = %q, want %q", updated.Name, "Updated")
+	if got.Summary != "Test summary" {
+		t.Errorf("Summary = %q, want %q", got.Summary, "Test summary")
 	}
-	if updated.Bio != "Updated bio" {
-		t.Errorf("Bio = %q, want %q", updated.Bio, "Updated bio")
+	if got.Excerpt != "Test excerpt" {
+		t.Errorf("Excerpt = %q, want %q", got.Excerpt, "Test excerpt")
 	}
-
-	// List contributors
-	contributors, err := svc.GetContributors(ctx, site.ID)
-	if err != nil {
-		t.Errorf("GetContributors() error = %v", err)
+	if got.Description != "Test description" {
+		t.Errorf("Description = %q, want %q", got.Description, "Test description")
 	}
-	if len(contributors) != 1 {
-		t.Errorf("Expected 1 contributor, got %d", len(contributors))
+	if got.Keywords != "test,keywords" {
+		t.Errorf("Keywords = %q, want %q", got.Keywords, "test,keywords")
 	}
-
-	// Delete contributor
-	err = svc.DeleteContributor(ctx, contributor.ID)
-	if err != nil {
-		t.Errorf("DeleteContributor() error = %v", err)
+	if got.Robots != "index,follow" {
+		t.Errorf("Robots = %q, want %q", got.Robots, "index,follow")
 	}
-
-	// Verify deletion
-	_, err = svc.GetContributor(ctx, contributor.ID)
-	if err == nil {
-		t.Error("Expected error when getting deleted contributor")
+	if got.CanonicalURL != "https://example.com/post" {
+		t.Errorf("CanonicalURL = %q, want %q", got.CanonicalURL, "https://example.com/post")
+	}
+	if !got.TableOfContents {
+		t.Error("TableOfContents should be true")
+	}
+	if !got.Share {
+		t.Error("Share should be true")
+	}
+	if !got.Comments {
+		t.Error("Comments should be true")
 	}
 }
 
-func TestServiceGetContributorNotFound(t *testing.T) {
+func TestServiceLayoutWithAllFields(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Layout Fields Site", "layout-fields-site")
 
-	_, err := svc.GetContributor(ctx, uuid.New())
-	if err == nil {
-		t.Error("Expected error when getting non-existent contributor")
-	}
-}
-
-func TestServiceMultipleContributors(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+	// Create image for header
+	image := NewImage(site.ID, "layout-header.jpg", "layouts/header.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
 
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Multi Contrib Site", "multi-contrib-site")
+	// Create layout with all fields
+	layout := NewLayout(site.ID, "Full Layout", "Layout with all fields")
+	layout.Code = "<html><body>{{.Content}}</body></html>"
+	layout.HeaderImageID = image.ID
+	layout.CreatedBy = uuid.New()
+	layout.UpdatedBy = layout.CreatedBy
 
-	// Create multiple contributors
-	for i := 0; i < 3; i++ {
-		contributor := NewContributor(site.ID, "contrib"+string(rune('1'+i)), "Contrib", string(rune('A'+i)))
-		contributor.CreatedBy = uuid.New()
-		contributor.UpdatedBy = contributor.CreatedBy
-		if err := svc.CreateContributor(ctx, contributor); err != nil {
-			t.Fatalf("CreateContributor() error = %v", err)
-		}
+	err := svc.CreateLayout(ctx, layout)
+	if err != nil {
+		t.Fatalf("CreateLayout() error = %v", err)
 	}
 
-	contributors, err := svc.GetContributors(ctx, site.ID)
+	// Get and verify
+	got, err := svc.GetLayout(ctx, layout.ID)
 	if err != nil {
-		t.Errorf("GetContributors() error = %v", err)
+		t.Errorf("GetLayout() error = %v", err)
 	}
-	if len(contributors) != 3 {
-		t.Errorf("Expected 3 contributors, got %d", len(contributors))
+	if got.Code != layout.Code {
+		t.Errorf("Code = %q, want %q", got.Code, layout.Code)
+	}
+	if got.HeaderImageID != image.ID {
+		t.Errorf("HeaderImageID = %v, want %v", got.HeaderImageID, image.ID)
 	}
 }
 
-func TestServiceCreateContentWithContributor(t *testing.T) {
+func TestServiceSectionWithLayout(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Content Contrib Site", "content-contrib-site")
+	site := createTestSite(t, svc, "Section Layout Site", "section-layout-site")
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
+	// Create layout
+	layout := NewLayout(site.ID, "Section Layout", "Layout for section")
+	layout.CreatedBy = uuid.New()
+	layout.UpdatedBy = layout.CreatedBy
+	svc.CreateLayout(ctx, layout)
+
+	// Create section with layout
+	section := NewSection(site.ID, "Blog", "Blog section", "/blog")
+	section.LayoutID = layout.ID
+	section.LayoutName = layout.Name
 	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	// Create contributor
-	contributor := NewContributor(site.ID, "author1", "Author", "One")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
-
-	// Create content with contributor
-	content := NewContent(site.ID, section.ID, "Authored Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	content.ContributorID = &contributor.ID
-	content.ContributorHandle = contributor.Handle
 
-	err := svc.CreateContent(ctx, content)
+	err := svc.CreateSection(ctx, section)
 	if err != nil {
-		t.Fatalf("CreateContent() with contributor error = %v", err)
+		t.Fatalf("CreateSection() error = %v", err)
 	}
 
-	// Get content with meta (which includes contributor info)
-	got, err := svc.GetContentWithMeta(ctx, content.ID)
+	// Get and verify
+	got, err := svc.GetSection(ctx, section.ID)
 	if err != nil {
-		t.Errorf("GetContentWithMeta() error = %v", err)
+		t.Errorf("GetSection() error = %v", err)
 	}
-	if got.ContributorID == nil {
-		t.Error("ContributorID should not be nil")
-	} else if *got.ContributorID != contributor.ID {
-		t.Errorf("ContributorID = %v, want %v", *got.ContributorID, contributor.ID)
+	if got.LayoutID != layout.ID {
+		t.Errorf("LayoutID = %v, want %v", got.LayoutID, layout.ID)
+	}
+	if got.LayoutName != layout.Name {
+		t.Errorf("LayoutName = %q, want %q", got.LayoutName, layout.Name)
 	}
 }
 
-func TestServiceUpdateContentFields(t *testing.T) {
+func TestServiceSettingWithAllFields(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Content Site", "update-content-site")
-
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	content := NewContent(site.ID, section.ID, "Original Title", "Original Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	site := createTestSite(t, svc, "Setting All Fields Site", "setting-all-fields-site")
 
-	// Update multiple fields
-	content.Heading = "Updated Title"
-	content.Body = "Updated Body"
-	content.Summary = "A summary"
-	content.Draft = false
-	content.Featured = true
-	content.Series = "test-series"
-	content.SeriesOrder = 1
-	now := time.Now()
-	content.PublishedAt = &now
+	// Create setting with all fields
+	setting := NewSetting(site.ID, "full_param", "param_value")
+	setting.Description = "Full parameter description"
+	setting.RefKey = "full_param_key"
+	setting.Category = "general"
+	setting.Position = 5
+	setting.System = true
+	setting.CreatedBy = uuid.New()
+	setting.UpdatedBy = setting.CreatedBy
 
-	err := svc.UpdateContent(ctx, content)
+	err := svc.CreateSetting(ctx, setting)
 	if err != nil {
-		t.Fatalf("UpdateContent() error = %v", err)
+		t.Fatalf("CreateSetting() error = %v", err)
 	}
 
-	// Verify all updates
-	updated, err := svc.GetContent(ctx, content.ID)
+	// Get and verify
+	got, err := svc.GetSetting(ctx, setting.ID)
 	if err != nil {
-		t.Errorf("GetContent() error = %v", err)
+		t.Errorf("GetSetting() error = %v", err)
 	}
-	if updated.Heading != "Updated Title" {
-		t.Errorf("Heading = %q, want %q", updated.Heading, "Updated Title")
+	if got.Description != "Full parameter description" {
+		t.Errorf("Description = %q, want %q", got.Description, "Full parameter description")
 	}
-	if updated.Summary != "A summary" {
-		t.Errorf("Summary = %q, want %q", updated.Summary, "A summary")
+	if got.RefKey != "full_param_key" {
+		t.Errorf("RefKey = %q, want %q", got.RefKey, "full_param_key")
 	}
-	if updated.Featured != true {
-		t.Error("Featured should be true")
+	if got.Category != "general" {
+		t.Errorf("Category = %q, want %q", got.Category, "general")
 	}
-	if updated.Series != "test-series" {
-		t.Errorf("Series = %q, want %q", updated.Series, "test-series")
+	if got.Position != 5 {
+		t.Errorf("Position = %d, want %d", got.Position, 5)
+	}
+	if !got.System {
+		t.Error("System should be true")
 	}
 }
 
-func TestServiceContributorWithSocialLinks(t *testing.T) {
+func TestServiceReorderSettings(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Social Links Site", "social-links-site")
-
-	// Create contributor with social links
-	contributor := NewContributor(site.ID, "socialuser", "Social", "User")
-	contributor.SocialLinks = []SocialLink{
-		{Platform: "twitter", Handle: "@socialuser", URL: "https://twitter.com/socialuser"},
-		{Platform: "github", Handle: "socialuser", URL: "https://github.com/socialuser"},
-		{Platform: "linkedin", Handle: "socialuser", URL: "https://linkedin.com/in/socialuser"},
+	site := createTestSite(t, svc, "Reorder Settings Site", "reorder-settings-site")
+
+	var feeds []*Setting
+	for i, name := range []string{"Feed formats", "Section feeds", "Tag feeds"} {
+		setting := NewSetting(site.ID, name, "")
+		setting.Category = "feeds"
+		setting.Position = i + 1
+		if err := svc.CreateSetting(ctx, setting); err != nil {
+			t.Fatalf("CreateSetting() error = %v", err)
+		}
+		feeds = append(feeds, setting)
+	}
+	other := NewSetting(site.ID, "Site description", "")
+	other.Category = "site"
+	other.Position = 1
+	if err := svc.CreateSetting(ctx, other); err != nil {
+		t.Fatalf("CreateSetting() error = %v", err)
 	}
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
 
-	err := svc.CreateContributor(ctx, contributor)
-	if err != nil {
-		t.Fatalf("CreateContributor() error = %v", err)
+	order := []uuid.UUID{feeds[2].ID, feeds[0].ID, feeds[1].ID}
+	if err := svc.ReorderSettings(ctx, site.ID, "feeds", order); err != nil {
+		t.Fatalf("ReorderSettings() error = %v", err)
 	}
 
-	// Get contributor and verify social links
-	got, err := svc.GetContributor(ctx, contributor.ID)
+	categories, err := svc.GetSettingsByCategory(ctx, site.ID)
 	if err != nil {
-		t.Errorf("GetContributor() error = %v", err)
+		t.Fatalf("GetSettingsByCategory() error = %v", err)
 	}
-	if len(got.SocialLinks) != 3 {
-		t.Errorf("Expected 3 social links, got %d", len(got.SocialLinks))
+	if len(categories) != 2 || categories[0].Name != "site" || categories[1].Name != "feeds" {
+		t.Fatalf("GetSettingsByCategory() = %v categories, want site then feeds", len(categories))
 	}
-	if got.SocialLinks[0].Platform != "twitter" {
-		t.Errorf("First social link platform = %q, want %q", got.SocialLinks[0].Platform, "twitter")
+	for i, setting := range categories[1].Settings {
+		if setting.ID != order[i] || setting.Position != i+1 {
+			t.Errorf("feeds[%d] = %s at %d, want %s at %d", i, setting.Name, setting.Position, order[i], i+1)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		ids      []uuid.UUID
+		want     error
+	}{
+		{"missing setting", "feeds", order[:2], ErrSettingOrder},
+		{"repeated setting", "feeds", []uuid.UUID{order[0], order[0], order[1]}, ErrSettingOrder},
+		{"setting of another category", "feeds", []uuid.UUID{order[0], order[1], other.ID}, ErrSettingOrder},
+		{"unknown category", "forms", nil, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.ReorderSettings(ctx, site.ID, tt.category, tt.ids); !errors.Is(err, tt.want) {
+				t.Errorf("ReorderSettings() error = %v, want %v", err, tt.want)
+			}
+		})
 	}
 }
 
-func TestServiceContributorWithProfile(t *testing.T) {
-	svc, db, cleanup := setupTestService(t)
+func TestServiceImageWithAllFields(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Contrib Profile Site", "contrib-profile-site")
+	site := createTestSite(t, svc, "Image All Fields Site", "image-all-fields-site")
 
-	// Create a profile first
-	profileID := uuid.New()
-	creatorID := uuid.New()
-	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, surname, bio, photo_path, social_links, created_by, updated_by, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
-		profileID.String(), site.ID.String(), "prof1234", "contrib-profile", "Profile", "Name", "Profile bio", "/photos/profile.jpg",
-		`[{"platform":"twitter","handle":"@profile"}]`,
-		creatorID.String(), creatorID.String())
-	if err != nil {
-		t.Fatalf("Failed to create profile: %v", err)
-	}
+	// Create image with all fields
+	image := NewImage(site.ID, "full-image.jpg", "/images/full-image.jpg")
+	image.AltText = "Full image alt text"
+	image.Title = "Full image title"
+	image.Width = 1920
+	image.Height = 1080
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
 
-	// Create contributor
-	contributor := NewContributor(site.ID, "profcontrib", "Prof", "Contrib")
-	contributor.CreatedBy = creatorID
-	contributor.UpdatedBy = creatorID
-	err = svc.CreateContributor(ctx, contributor)
+	err := svc.CreateImage(ctx, image)
 	if err != nil {
-		t.Fatalf("CreateContributor() error = %v", err)
+		t.Fatalf("CreateImage() error = %v", err)
 	}
 
-	// Set contributor profile
-	err = svc.SetContributorProfile(ctx, contributor.ID, profileID, creatorID.String())
+	// Get and verify
+	got, err := svc.GetImage(ctx, image.ID)
 	if err != nil {
-		t.Fatalf("SetContributorProfile() error = %v", err)
+		t.Errorf("GetImage() error = %v", err)
 	}
-
-	// List contributors (this uses contributorWithProfileFromSQLC)
-	contributors, err := svc.GetContributors(ctx, site.ID)
-	if err != nil {
-		t.Errorf("GetContributors() error = %v", err)
+	if got.AltText != "Full image alt text" {
+		t.Errorf("AltText = %q, want %q", got.AltText, "Full image alt text")
 	}
-	if len(contributors) != 1 {
-		t.Fatalf("Expected 1 contributor, got %d", len(contributors))
+	if got.Title != "Full image title" {
+		t.Errorf("Title = %q, want %q", got.Title, "Full image title")
 	}
-
-	// Check that profile data was loaded
-	c := contributors[0]
-	if c.ProfileID == nil {
-		t.Error("ProfileID should not be nil")
+	if got.Width != 1920 {
+		t.Errorf("Width = %d, want %d", got.Width, 1920)
 	}
-	if c.PhotoPath != "/photos/profile.jpg" {
-		t.Errorf("PhotoPath = %q, want %q", c.PhotoPath, "/photos/profile.jpg")
+	if got.Height != 1080 {
+		t.Errorf("Height = %d, want %d", got.Height, 1080)
 	}
 }
 
-func TestServiceGetContentWithMetaNotFound(t *testing.T) {
+func TestServiceDeleteContributorNonExistent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
 
-	_, err := svc.GetContentWithMeta(ctx, uuid.New())
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("GetContentWithMeta() for non-existent content should return ErrNotFound, got: %v", err)
+	// Delete non-existent contributor - SQLite DELETE doesn't error
+	err := svc.DeleteContributor(ctx, uuid.New())
+	if err != nil {
+		t.Logf("DeleteContributor() for non-existent returned: %v", err)
 	}
 }
 
-func TestServiceGetContentWithMetaWithTags(t *testing.T) {
+func TestServiceDeleteSiteAndVerify(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Meta Tags Site", "meta-tags-site")
-
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	content := NewContent(site.ID, section.ID, "Tagged Content", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	site := createTestSite(t, svc, "Delete Me Site", "delete-me-site")
 
-	// Add tags
-	svc.AddTagToContent(ctx, content.ID, "tag1", site.ID)
-	svc.AddTagToContent(ctx, content.ID, "tag2", site.ID)
+	// Verify exists
+	_, err := svc.GetSite(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("Site should exist: %v", err)
+	}
 
-	// Get with meta (should include tags)
-	got, err := svc.GetContentWithMeta(ctx, content.ID)
+	// Delete
+	err = svc.DeleteSite(ctx, site.ID)
 	if err != nil {
-		t.Fatalf("GetContentWithMeta() error = %v", err)
+		t.Fatalf("DeleteSite() error = %v", err)
 	}
-	if len(got.Tags) != 2 {
-		t.Errorf("Expected 2 tags, got %d", len(got.Tags))
+
+	// Verify deleted
+	_, err = svc.GetSite(ctx, site.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceUpdateContentWithContributor(t *testing.T) {
+func TestServiceDeleteSectionAndVerify(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Contrib Site", "update-contrib-site")
+	site := createTestSite(t, svc, "Del Section Site", "del-section-site")
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
+	section := NewSection(site.ID, "Delete Me", "Delete me section", "/delete-me")
 	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
 
-	// Create contributor
-	contributor := NewContributor(site.ID, "author", "Author", "Name")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
-
-	// Create content without contributor
-	content := NewContent(site.ID, section.ID, "Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
-
-	// Update content to add contributor
-	content.ContributorID = &contributor.ID
-	content.ContributorHandle = contributor.Handle
-	content.UpdatedAt = time.Now()
-
-	err := svc.UpdateContent(ctx, content)
+	// Verify exists
+	_, err := svc.GetSection(ctx, section.ID)
 	if err != nil {
-		t.Fatalf("UpdateContent() error = %v", err)
+		t.Fatalf("Section should exist: %v", err)
 	}
 
-	// Verify
-	updated, err := svc.GetContentWithMeta(ctx, content.ID)
+	// Delete
+	err = svc.DeleteSection(ctx, section.ID)
 	if err != nil {
-		t.Errorf("GetContentWithMeta() error = %v", err)
-	}
-	if updated.ContributorID == nil {
-		t.Error("ContributorID should not be nil")
+		t.Fatalf("DeleteSection() error = %v", err)
 	}
-	if updated.ContributorHandle != "author" {
-		t.Errorf("ContributorHandle = %q, want %q", updated.ContributorHandle, "author")
+
+	// Verify deleted
+	_, err = svc.GetSection(ctx, section.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceContributorSocialLinksUpdate(t *testing.T) {
+func TestServiceDeleteLayoutAndVerify(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Social Site", "update-social-site")
-
-	// Create contributor without social links
-	contributor := NewContributor(site.ID, "nosocial", "No", "Social")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
+	site := createTestSite(t, svc, "Del Layout Site", "del-layout-site")
 
-	// Update with social links
-	contributor.SocialLinks = []SocialLink{
-		{Platform: "website", Handle: "", URL: "https://example.com"},
-	}
-	contributor.UpdatedAt = time.Now()
+	layout := NewLayout(site.ID, "Delete Me", "Delete me layout")
+	layout.CreatedBy = uuid.New()
+	layout.UpdatedBy = layout.CreatedBy
+	svc.CreateLayout(ctx, layout)
 
-	err := svc.UpdateContributor(ctx, contributor)
+	// Verify exists
+	_, err := svc.GetLayout(ctx, layout.ID)
 	if err != nil {
-		t.Fatalf("UpdateContributor() error = %v", err)
+		t.Fatalf("Layout should exist: %v", err)
 	}
 
-	// Verify
-	updated, err := svc.GetContributor(ctx, contributor.ID)
+	// Delete
+	err = svc.DeleteLayout(ctx, layout.ID)
 	if err != nil {
-		t.Errorf("GetContributor() error = %v", err)
+		t.Fatalf("DeleteLayout() error = %v", err)
 	}
-	if len(updated.SocialLinks) != 1 {
-		t.Errorf("Expected 1 social link, got %d", len(updated.SocialLinks))
+
+	// Verify deleted
+	_, err = svc.GetLayout(ctx, layout.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceUnlinkImageFromContentNotFound(t *testing.T) {
+func TestServiceDeleteTagAndVerify(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Del Tag Site", "del-tag-site")
 
-	// Try to unlink non-existent content image - should not error (SQL DELETE)
-	err := svc.UnlinkImageFromContent(ctx, uuid.New())
+	tag := NewTag(site.ID, "delete-me-tag")
+	tag.CreatedBy = uuid.New()
+	tag.UpdatedBy = tag.CreatedBy
+	svc.CreateTag(ctx, tag)
+
+	// Verify exists
+	_, err := svc.GetTag(ctx, tag.ID)
 	if err != nil {
-		// Note: SQLite DELETE doesn't error on non-existent rows
-		t.Logf("UnlinkImageFromContent() returned error for non-existent: %v", err)
+		t.Fatalf("Tag should exist: %v", err)
 	}
-}
-
-func TestServiceUnlinkImageFromSectionNotFound(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
-
-	ctx := context.Background()
 
-	// Try to unlink non-existent section image - should not error (SQL DELETE)
-	err := svc.UnlinkImageFromSection(ctx, uuid.New())
+	// Delete
+	err = svc.DeleteTag(ctx, tag.ID)
 	if err != nil {
-		t.Logf("UnlinkImageFromSection() returned error for non-existent: %v", err)
+		t.Fatalf("DeleteTag() error = %v", err)
+	}
+
+	// Verify deleted
+	_, err = svc.GetTag(ctx, tag.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceCreateContributorWithProfileID(t *testing.T) {
-	svc, db, cleanup := setupTestService(t)
+func TestServiceDeleteSettingAndVerify(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Contrib PID Site", "contrib-pid-site")
-
-	// Create a profile first
-	profileID := uuid.New()
-	creatorID := uuid.New()
-	_, err := db.Exec(`INSERT INTO profile (id, site_id, short_id, slug, name, created_by, updated_by, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
-		profileID.String(), site.ID.String(), "pid12345", "pid-profile", "PID Profile", creatorID.String(), creatorID.String())
-	if err != nil {
-		t.Fatalf("Failed to create profile: %v", err)
-	}
+	site := createTestSite(t, svc, "Del Setting Site", "del-setting-site")
 
-	// Create contributor with profile ID
-	contributor := NewContributor(site.ID, "pidcontrib", "PID", "Contrib")
-	contributor.ProfileID = &profileID
-	contributor.CreatedBy = creatorID
-	contributor.UpdatedBy = creatorID
+	setting := NewSetting(site.ID, "delete_me", "value")
+	setting.CreatedBy = uuid.New()
+	setting.UpdatedBy = setting.CreatedBy
+	svc.CreateSetting(ctx, setting)
 
-	err = svc.CreateContributor(ctx, contributor)
+	// Verify exists
+	_, err := svc.GetSetting(ctx, setting.ID)
 	if err != nil {
-		t.Fatalf("CreateContributor() error = %v", err)
+		t.Fatalf("Setting should exist: %v", err)
 	}
 
-	// Verify profile ID was saved
-	got, err := svc.GetContributor(ctx, contributor.ID)
+	// Delete
+	err = svc.DeleteSetting(ctx, setting.ID)
 	if err != nil {
-		t.Errorf("GetContributor() error = %v", err)
+		t.Fatalf("DeleteSetting() error = %v", err)
 	}
-	if got.ProfileID == nil {
-		t.Error("ProfileID should not be nil")
-	} else if *got.ProfileID != profileID {
-		t.Errorf("ProfileID = %v, want %v", *got.ProfileID, profileID)
+
+	// Verify deleted
+	_, err = svc.GetSetting(ctx, setting.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceDeleteContributorVerify(t *testing.T) {
+func TestServiceDeleteImageAndVerify(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Del Contrib Site", "del-contrib-site")
+	site := createTestSite(t, svc, "Del Image Site", "del-image-site")
 
-	contributor := NewContributor(site.ID, "delme", "Del", "Me")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	svc.CreateContributor(ctx, contributor)
+	image := NewImage(site.ID, "delete.jpg", "/images/delete.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
 
 	// Verify exists
-	_, err := svc.GetContributor(ctx, contributor.ID)
+	_, err := svc.GetImage(ctx, image.ID)
 	if err != nil {
-		t.Fatalf("Contributor should exist: %v", err)
+		t.Fatalf("Image should exist: %v", err)
 	}
 
 	// Delete
-	err = svc.DeleteContributor(ctx, contributor.ID)
+	err = svc.DeleteImage(ctx, image.ID)
 	if err != nil {
-		t.Fatalf("DeleteContributor() error = %v", err)
+		t.Fatalf("DeleteImage() error = %v", err)
 	}
 
 	// Verify deleted
-	_, err = svc.GetContributor(ctx, contributor.ID)
+	_, err = svc.GetImage(ctx, image.ID)
 	if !errors.Is(err, ErrNotFound) {
 		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceContributorEmptySocialLinks(t *testing.T) {
+func TestServiceDeleteContentAndVerify(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Empty Social Site", "empty-social-site")
+	site := createTestSite(t, svc, "Del Content Site", "del-content-site")
 
-	// Create contributor with empty social links
-	contributor := NewContributor(site.ID, "emptylinks", "Empty", "Links")
-	contributor.SocialLinks = []SocialLink{}
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
 
-	err := svc.CreateContributor(ctx, contributor)
+	content := NewContent(site.ID, section.ID, "Delete Me", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
+	svc.CreateContent(ctx, content)
+
+	// Verify exists
+	_, err := svc.GetContent(ctx, content.ID)
 	if err != nil {
-		t.Fatalf("CreateContributor() error = %v", err)
+		t.Fatalf("Content should exist: %v", err)
 	}
 
-	// Get and verify
-	got, err := svc.GetContributor(ctx, contributor.ID)
+	// Delete
+	err = svc.DeleteContent(ctx, content.ID)
 	if err != nil {
-		t.Errorf("GetContributor() error = %v", err)
+		t.Fatalf("DeleteContent() error = %v", err)
 	}
-	if got.SocialLinks == nil {
-		// Empty slice is acceptable
+
+	// Verify deleted
+	_, err = svc.GetContent(ctx, content.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
 	}
 }
 
-func TestServiceSiteWithDefaultLayout(t *testing.T) {
+func TestServiceLinkUnlinkImageToSectionFull(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Layout Site", "layout-site")
+	site := createTestSite(t, svc, "Section Img Site 2", "section-img-site-2")
 
-	// Create a layout
-	layout := NewLayout(site.ID, "Default Layout", "The default layout")
-	layout.Code = "<html>{{.Content}}</html>"
-	layout.CreatedBy = uuid.New()
-	layout.UpdatedBy = layout.CreatedBy
-	svc.CreateLayout(ctx, layout)
+	section := NewSection(site.ID, "Images", "", "/gallery")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+	svc.CreateSection(ctx, section)
 
-	// Update site with default layout
-	site.DefaultLayoutID = layout.ID
-	site.DefaultLayoutName = layout.Name
-	site.UpdatedAt = time.Now()
+	image := NewImage(site.ID, "section.jpg", "/images/section.jpg")
+	image.CreatedBy = uuid.New()
+	image.UpdatedBy = image.CreatedBy
+	svc.CreateImage(ctx, image)
 
-	err := svc.UpdateSite(ctx, site)
+	// Link image (with isHeader = false)
+	err := svc.LinkImageToSection(ctx, section.ID, image.ID, false)
 	if err != nil {
-		t.Fatalf("UpdateSite() error = %v", err)
+		t.Fatalf("LinkImageToSection() error = %v", err)
 	}
 
-	// Get site and verify default layout fields
-	got, err := svc.GetSite(ctx, site.ID)
+	// Verify linked
+	images, err := svc.GetSectionImagesWithDetails(ctx, section.ID)
 	if err != nil {
-		t.Errorf("GetSite() error = %v", err)
+		t.Fatalf("GetSectionImagesWithDetails() error = %v", err)
 	}
-	if got.DefaultLayoutID != layout.ID {
-		t.Errorf("DefaultLayoutID = %v, want %v", got.DefaultLayoutID, layout.ID)
+	if len(images) != 1 {
+		t.Fatalf("Expected 1 image, got %d", len(images))
 	}
-	if got.DefaultLayoutName != layout.Name {
-		t.Errorf("DefaultLayoutName = %q, want %q", got.DefaultLayoutName, layout.Name)
+
+	// Get section image details
+	details, err := svc.GetSectionImageDetails(ctx, images[0].SectionImageID)
+	if err != nil {
+		t.Fatalf("GetSectionImageDetails() error = %v", err)
+	}
+	if details.ImageID != image.ID {
+		t.Errorf("ImageID = %v, want %v", details.ImageID, image.ID)
+	}
+
+	// Unlink image
+	err = svc.UnlinkImageFromSection(ctx, images[0].SectionImageID)
+	if err != nil {
+		t.Fatalf("UnlinkImageFromSection() error = %v", err)
+	}
+
+	// Verify unlinked
+	images, _ = svc.GetSectionImagesWithDetails(ctx, section.ID)
+	if len(images) != 0 {
+		t.Errorf("Expected 0 images after unlink, got %d", len(images))
 	}
 }
 
-func TestServiceContentWithHeaderImage(t *testing.T) {
+func TestServiceContentImageDetailsFull(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Header Img Site", "header-img-site")
+	site := createTestSite(t, svc, "Content Img Details Site 2", "content-img-details-site-2")
 
 	section := NewSection(site.ID, "Blog", "", "/blog")
 	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
 
-	content := NewContent(site.ID, section.ID, "Header Image Post", "Body with header image")
+	content := NewContent(site.ID, section.ID, "Image Post", "Body with images")
 	content.CreatedBy = uuid.New()
 	content.UpdatedBy = content.CreatedBy
 	svc.CreateContent(ctx, content)
 
-	// Create and link header image
-	image := NewImage(site.ID, "header.jpg", "posts/header.jpg")
-	image.AltText = "Header image alt text"
+	image := NewImage(site.ID, "content.jpg", "/images/content.jpg")
 	image.CreatedBy = uuid.New()
 	image.UpdatedBy = image.CreatedBy
 	svc.CreateImage(ctx, image)
-	svc.LinkImageToContent(ctx, content.ID, image.ID, true)
 
-	// Get all content with meta (tests contentWithMetaFromSQLCAll with header image)
-	contents, err := svc.GetAllContentWithMeta(ctx, site.ID)
+	// Link image to content (not as header)
+	err := svc.LinkImageToContent(ctx, content.ID, image.ID, false)
 	if err != nil {
-		t.Fatalf("GetAllContentWithMeta() error = %v", err)
+		t.Fatalf("LinkImageToContent() error = %v", err)
 	}
-	if len(contents) != 1 {
-		t.Fatalf("Expected 1 content, got %d", len(contents))
+
+	// Get content images
+	images, err := svc.GetContentImagesWithDetails(ctx, content.ID)
+	if err != nil {
+		t.Fatalf("GetContentImagesWithDetails() error = %v", err)
+	}
+	if len(images) != 1 {
+		t.Fatalf("Expected 1 image, got %d", len(images))
 	}
 
-	c := contents[0]
-	if c.HeaderImageURL == "" {
-		t.Error("HeaderImageURL should not be empty")
+	// Get content image details
+	details, err := svc.GetContentImageDetails(ctx, images[0].ContentImageID)
+	if err != nil {
+		t.Fatalf("GetContentImageDetails() error = %v", err)
 	}
-	if c.HeaderImageAlt != "Header image alt text" {
-		t.Errorf("HeaderImageAlt = %q, want %q", c.HeaderImageAlt, "Header image alt text")
+	if details.ImageID != image.ID {
+		t.Errorf("ImageID = %v, want %v", details.ImageID, image.ID)
+	}
+
+	// Unlink
+	err = svc.UnlinkImageFromContent(ctx, images[0].ContentImageID)
+	if err != nil {
+		t.Fatalf("UnlinkImageFromContent() error = %v", err)
+	}
+
+	// Verify unlinked
+	images, _ = svc.GetContentImagesWithDetails(ctx, content.ID)
+	if len(images) != 0 {
+		t.Errorf("Expected 0 images after unlink, got %d", len(images))
 	}
 }
 
-func TestServiceMetaAllFields(t *testing.T) {
+// Tests to improve coverage on error paths
+
+func TestServiceAddTagToContentDuplicate(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Meta All Fields Site", "meta-all-fields-site")
+	site := createTestSite(t, svc, "Dup Tag Site", "dup-tag-site")
 
 	section := NewSection(site.ID, "Blog", "", "/blog")
 	section.CreatedBy = uuid.New()
 	section.UpdatedBy = section.CreatedBy
 	svc.CreateSection(ctx, section)
 
-	content := NewContent(site.ID, section.ID, "Full Meta Post", "Body")
+	content := NewContent(site.ID, section.ID, "Tagged Post", "Body")
 	content.CreatedBy = uuid.New()
 	content.UpdatedBy = content.CreatedBy
 	svc.CreateContent(ctx, content)
 
-	// Create meta with all fields
-	meta := NewMeta(site.ID, content.ID)
-	meta.Summary = "Test summary"
-	meta.Excerpt = "Test excerpt"
-	meta.Description = "Test description"
-	meta.Keywords = "test,keywords"
-	meta.Robots = "index,follow"
-	meta.CanonicalURL = "https://example.com/post"
-	meta.Sitemap = "weekly"
-	meta.TableOfContents = true
-	meta.Share = true
-	meta.Comments = true
-	meta.CreatedBy = uuid.New()
-	meta.UpdatedBy = meta.CreatedBy
-
-	err := svc.CreateMeta(ctx, meta)
+	// Add tag first time - should succeed
+	err := svc.AddTagToContent(ctx, content.ID, "test-tag", site.ID)
 	if err != nil {
-		t.Fatalf("CreateMeta() error = %v", err)
+		t.Fatalf("First AddTagToContent() error = %v", err)
 	}
 
-	// Get meta
-	got, err := svc.GetMetaByContentID(ctx, content.ID)
+	// Add same tag again - might fail with constraint or succeed
+	err = svc.AddTagToContent(ctx, content.ID, "test-tag", site.ID)
+	// Just log the result, we're exercising the code path
 	if err != nil {
-		t.Fatalf("GetMetaByContentID() error = %v", err)
-	}
-	if got.Summary != "Test summary" {
-		t.Errorf("Summary = %q, want %q", got.Summary, "Test summary")
-	}
-	if got.Excerpt != "Test excerpt" {
-		t.Errorf("Excerpt = %q, want %q", got.Excerpt, "Test excerpt")
-	}
-	if got.Description != "Test description" {
-		t.Errorf("Description = %q, want %q", got.Description, "Test description")
-	}
-	if got.Keywords != "test,keywords" {
-		t.Errorf("Keywords = %q, want %q", got.Keywords, "test,keywords")
-	}
-	if got.Robots != "index,follow" {
-		t.Errorf("Robots = %q, want %q", got.Robots, "index,follow")
-	}
-	if got.CanonicalURL != "https://example.com/post" {
-		t.Errorf("CanonicalURL = %q, want %q", got.CanonicalURL, "https://example.com/post")
-	}
-	if !got.TableOfContents {
-		t.Error("TableOfContents should be true")
+		t.Logf("Second AddTagToContent() returned: %v", err)
 	}
-	if !got.Share {
-		t.Error("Share should be true")
+}
+
+func TestServiceCreateSiteDuplicateSlugError(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Create first site
+	site1 := NewSite("Site One", "same-slug-err")
+	err := svc.CreateSite(ctx, site1)
+	if err != nil {
+		t.Fatalf("First CreateSite() error = %v", err)
 	}
-	if !got.Comments {
-		t.Error("Comments should be true")
+
+	// Try to create second site with same slug
+	site2 := NewSite("Site Two", "same-slug-err")
+	err = svc.CreateSite(ctx, site2)
+	if err == nil {
+		t.Error("Expected error for duplicate slug")
 	}
 }
 
-func TestServiceLayoutWithAllFields(t *testing.T) {
+func TestServiceCreateContentInvalidSection(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Layout Fields Site", "layout-fields-site")
-
-	// Create image for header
-	image := NewImage(site.ID, "layout-header.jpg", "layouts/header.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	site := createTestSite(t, svc, "Invalid Section Site", "invalid-section-site")
 
-	// Create layout with all fields
-	layout := NewLayout(site.ID, "Full Layout", "Layout with all fields")
-	layout.Code = "<html><body>{{.Content}}</body></html>"
-	layout.HeaderImageID = image.ID
-	layout.CreatedBy = uuid.New()
-	layout.UpdatedBy = layout.CreatedBy
+	// Try to create content with non-existent section ID
+	content := NewContent(site.ID, uuid.New(), "Invalid Post", "Body")
+	content.CreatedBy = uuid.New()
+	content.UpdatedBy = content.CreatedBy
 
-	err := svc.CreateLayout(ctx, layout)
-	if err != nil {
-		t.Fatalf("CreateLayout() error = %v", err)
+	err := svc.CreateContent(ctx, content)
+	if err == nil {
+		t.Error("Expected error for invalid section ID")
 	}
+}
 
-	// Get and verify
-	got, err := svc.GetLayout(ctx, layout.ID)
-	if err != nil {
-		t.Errorf("GetLayout() error = %v", err)
-	}
-	if got.Code != layout.Code {
-		t.Errorf("Code = %q, want %q", got.Code, layout.Code)
+func TestServiceCreateContributorInvalidSite(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Try to create contributor with non-existent site ID
+	contributor := NewContributor(uuid.New(), "invalid", "Invalid", "User")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+
+	err := svc.CreateContributor(ctx, contributor)
+	if err == nil {
+		t.Error("Expected error for invalid site ID")
 	}
-	if got.HeaderImageID != image.ID {
-		t.Errorf("HeaderImageID = %v, want %v", got.HeaderImageID, image.ID)
+}
+
+func TestServiceCreateSectionInvalidSite(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Try to create section with non-existent site ID
+	section := NewSection(uuid.New(), "Invalid Section", "Desc", "/invalid")
+	section.CreatedBy = uuid.New()
+	section.UpdatedBy = section.CreatedBy
+
+	err := svc.CreateSection(ctx, section)
+	if err == nil {
+		t.Error("Expected error for invalid site ID")
 	}
 }
 
-func TestServiceSectionWithLayout(t *testing.T) {
+func TestServiceCreateLayoutInvalidSite(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Section Layout Site", "section-layout-site")
 
-	// Create layout
-	layout := NewLayout(site.ID, "Section Layout", "Layout for section")
+	// Try to create layout with non-existent site ID
+	layout := NewLayout(uuid.New(), "Invalid Layout", "Desc")
 	layout.CreatedBy = uuid.New()
 	layout.UpdatedBy = layout.CreatedBy
-	svc.CreateLayout(ctx, layout)
-
-	// Create section with layout
-	section := NewSection(site.ID, "Blog", "Blog section", "/blog")
-	section.LayoutID = layout.ID
-	section.LayoutName = layout.Name
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
 
-	err := svc.CreateSection(ctx, section)
-	if err != nil {
-		t.Fatalf("CreateSection() error = %v", err)
+	err := svc.CreateLayout(ctx, layout)
+	if err == nil {
+		t.Error("Expected error for invalid site ID")
 	}
+}
 
-	// Get and verify
-	got, err := svc.GetSection(ctx, section.ID)
-	if err != nil {
-		t.Errorf("GetSection() error = %v", err)
-	}
-	if got.LayoutID != layout.ID {
-		t.Errorf("LayoutID = %v, want %v", got.LayoutID, layout.ID)
-	}
-	if got.LayoutName != layout.Name {
-		t.Errorf("LayoutName = %q, want %q", got.LayoutName, layout.Name)
+func TestServiceCreateTagInvalidSite(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Try to create tag with non-existent site ID
+	tag := NewTag(uuid.New(), "invalid-tag")
+	tag.CreatedBy = uuid.New()
+	tag.UpdatedBy = tag.CreatedBy
+
+	err := svc.CreateTag(ctx, tag)
+	if err == nil {
+		t.Error("Expected error for invalid site ID")
 	}
 }
 
-func TestServiceSettingWithAllFields(t *testing.T) {
+func TestServiceCreateSettingInvalidSite(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Setting All Fields Site", "setting-all-fields-site")
 
-	// Create setting with all fields
-	setting := NewSetting(site.ID, "full_param", "param_value")
-	setting.Description = "Full parameter description"
-	setting.RefKey = "full_param_key"
-	setting.Category = "general"
-	setting.Position = 5
-	setting.System = true
+	// Try to create setting with non-existent site ID
+	setting := NewSetting(uuid.New(), "invalid_param", "value")
 	setting.CreatedBy = uuid.New()
 	setting.UpdatedBy = setting.CreatedBy
 
 	err := svc.CreateSetting(ctx, setting)
-	if err != nil {
-		t.Fatalf("CreateSetting() error = %v", err)
-	}
-
-	// Get and verify
-	got, err := svc.GetSetting(ctx, setting.ID)
-	if err != nil {
-		t.Errorf("GetSetting() error = %v", err)
-	}
-	if got.Description != "Full parameter description" {
-		t.Errorf("Description = %q, want %q", got.Description, "Full parameter description")
-	}
-	if got.RefKey != "full_param_key" {
-		t.Errorf("RefKey = %q, want %q", got.RefKey, "full_param_key")
-	}
-	if got.Category != "general" {
-		t.Errorf("Category = %q, want %q", got.Category, "general")
-	}
-	if got.Position != 5 {
-		t.Errorf("Position = %d, want %d", got.Position, 5)
-	}
-	if !got.System {
-		t.Error("System should be true")
+	if err == nil {
+		t.Error("Expected error for invalid site ID")
 	}
 }
 
-func TestServiceImageWithAllFields(t *testing.T) {
+func TestServiceCreateImageInvalidSite(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Image All Fields Site", "image-all-fields-site")
 
-	// Create image with all fields
-	image := NewImage(site.ID, "full-image.jpg", "/images/full-image.jpg")
-	image.AltText = "Full image alt text"
-	image.Title = "Full image title"
-	image.Width = 1920
-	image.Height = 1080
+	// Try to create image with non-existent site ID
+	image := NewImage(uuid.New(), "invalid.jpg", "/images/invalid.jpg")
 	image.CreatedBy = uuid.New()
 	image.UpdatedBy = image.CreatedBy
 
 	err := svc.CreateImage(ctx, image)
-	if err != nil {
-		t.Fatalf("CreateImage() error = %v", err)
-	}
-
-	// Get and verify
-	got, err := svc.GetImage(ctx, image.ID)
-	if err != nil {
-		t.Errorf("GetImage() error = %v", err)
-	}
-	if got.AltText != "Full image alt text" {
-		t.Errorf("AltText = %q, want %q", got.AltText, "Full image alt text")
-	}
-	if got.Title != "Full image title" {
-		t.Errorf("Title = %q, want %q", got.Title, "Full image title")
-	}
-	if got.Width != 1920 {
-		t.Errorf("Width = %d, want %d", got.Width, 1920)
-	}
-	if got.Height != 1080 {
-		t.Errorf("Height = %d, want %d", got.Height, 1080)
+	if err == nil {
+		t.Error("Expected error for invalid site ID")
 	}
 }
 
-func TestServiceDeleteContributorNonExistent(t *testing.T) {
+func TestServiceCreateMetaInvalidContent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Invalid Meta Site", "invalid-meta-site")
 
-	// Delete non-existent contributor - SQLite DELETE doesn't error
-	err := svc.DeleteContributor(ctx, uuid.New())
-	if err != nil {
-		t.Logf("DeleteContributor() for non-existent returned: %v", err)
+	// Try to create meta with non-existent content ID
+	meta := NewMeta(site.ID, uuid.New())
+	meta.CreatedBy = uuid.New()
+	meta.UpdatedBy = meta.CreatedBy
+
+	err := svc.CreateMeta(ctx, meta)
+	if err == nil {
+		t.Error("Expected error for invalid content ID")
 	}
 }
 
-func TestServiceDeleteSiteAndVerify(t *testing.T) {
+func TestServiceLinkImageToContentInvalidIDs(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Delete Me Site", "delete-me-site")
-
-	// Verify exists
-	_, err := svc.GetSite(ctx, site.ID)
-	if err != nil {
-		t.Fatalf("Site should exist: %v", err)
-	}
 
-	// Delete
-	err = svc.DeleteSite(ctx, site.ID)
-	if err != nil {
-		t.Fatalf("DeleteSite() error = %v", err)
-	}
-
-	// Verify deleted
-	_, err = svc.GetSite(ctx, site.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+	// Try to link with non-existent IDs
+	err := svc.LinkImageToContent(ctx, uuid.New(), uuid.New(), false)
+	if err == nil {
+		t.Error("Expected error for invalid IDs")
 	}
 }
 
-func TestServiceDeleteSectionAndVerify(t *testing.T) {
+func TestServiceLinkImageToSectionInvalidIDs(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Del Section Site", "del-section-site")
-
-	section := NewSection(site.ID, "Delete Me", "Delete me section", "/delete-me")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
 
-	// Verify exists
-	_, err := svc.GetSection(ctx, section.ID)
-	if err != nil {
-		t.Fatalf("Section should exist: %v", err)
+	// Try to link with non-existent IDs
+	err := svc.LinkImageToSection(ctx, uuid.New(), uuid.New(), false)
+	if err == nil {
+		t.Error("Expected error for invalid IDs")
 	}
+}
 
-	// Delete
-	err = svc.DeleteSection(ctx, section.ID)
-	if err != nil {
-		t.Fatalf("DeleteSection() error = %v", err)
-	}
+func TestServiceAddTagToContentByIDInvalidIDs(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
 
-	// Verify deleted
-	_, err = svc.GetSection(ctx, section.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+	ctx := context.Background()
+
+	// Try to add tag with non-existent IDs
+	err := svc.AddTagToContentByID(ctx, uuid.New(), uuid.New())
+	if err == nil {
+		t.Error("Expected error for invalid IDs")
 	}
 }
 
-func TestServiceDeleteLayoutAndVerify(t *testing.T) {
+func TestServiceSetContributorProfileInvalidIDs(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Del Layout Site", "del-layout-site")
-
-	layout := NewLayout(site.ID, "Delete Me", "Delete me layout")
-	layout.CreatedBy = uuid.New()
-	layout.UpdatedBy = layout.CreatedBy
-	svc.CreateLayout(ctx, layout)
-
-	// Verify exists
-	_, err := svc.GetLayout(ctx, layout.ID)
-	if err != nil {
-		t.Fatalf("Layout should exist: %v", err)
-	}
 
-	// Delete
-	err = svc.DeleteLayout(ctx, layout.ID)
+	// Try to set profile with non-existent contributor ID
+	// SQLite UPDATE doesn't error on non-existent rows
+	err := svc.SetContributorProfile(ctx, uuid.New(), uuid.New(), uuid.New().String())
+	// Just exercise the code path
 	if err != nil {
-		t.Fatalf("DeleteLayout() error = %v", err)
-	}
-
-	// Verify deleted
-	_, err = svc.GetLayout(ctx, layout.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+		t.Logf("SetContributorProfile for non-existent returned: %v", err)
 	}
 }
 
-func TestServiceDeleteTagAndVerify(t *testing.T) {
+func TestServiceGetContentImageDetailsNotFound(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Del Tag Site", "del-tag-site")
 
-	tag := NewTag(site.ID, "delete-me-tag")
-	tag.CreatedBy = uuid.New()
-	tag.UpdatedBy = tag.CreatedBy
-	svc.CreateTag(ctx, tag)
-
-	// Verify exists
-	_, err := svc.GetTag(ctx, tag.ID)
-	if err != nil {
-		t.Fatalf("Tag should exist: %v", err)
+	// Try to get details for non-existent content image
+	_, err := svc.GetContentImageDetails(ctx, uuid.New())
+	if err == nil {
+		t.Error("Expected error for non-existent content image")
 	}
+}
 
-	// Delete
-	err = svc.DeleteTag(ctx, tag.ID)
-	if err != nil {
-		t.Fatalf("DeleteTag() error = %v", err)
-	}
+func TestServiceGetSectionImageDetailsNotFound(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
 
-	// Verify deleted
-	_, err = svc.GetTag(ctx, tag.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+	ctx := context.Background()
+
+	// Try to get details for non-existent section image
+	_, err := svc.GetSectionImageDetails(ctx, uuid.New())
+	if err == nil {
+		t.Error("Expected error for non-existent section image")
 	}
 }
 
-func TestServiceDeleteSettingAndVerify(t *testing.T) {
+func TestServiceUpdateContributorNonExistent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Del Setting Site", "del-setting-site")
+	site := createTestSite(t, svc, "Update Nonexistent Site", "update-nonexistent-site")
 
-	setting := NewSetting(site.ID, "delete_me", "value")
-	setting.CreatedBy = uuid.New()
-	setting.UpdatedBy = setting.CreatedBy
-	svc.CreateSetting(ctx, setting)
+	// Try to update non-existent contributor
+	contributor := NewContributor(site.ID, "nonexistent", "Non", "Existent")
+	contributor.UpdatedAt = time.Now()
 
-	// Verify exists
-	_, err := svc.GetSetting(ctx, setting.ID)
+	err := svc.UpdateContributor(ctx, contributor)
+	// SQLite UPDATE doesn't error on non-existent, just log
 	if err != nil {
-		t.Fatalf("Setting should exist: %v", err)
+		t.Logf("UpdateContributor for non-existent returned: %v", err)
 	}
+}
 
-	// Delete
-	err = svc.DeleteSetting(ctx, setting.ID)
-	if err != nil {
-		t.Fatalf("DeleteSetting() error = %v", err)
-	}
+func TestServiceUpdateSiteCancelledContext(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
 
-	// Verify deleted
-	_, err = svc.GetSetting(ctx, setting.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+	site := createTestSite(t, svc, "Context Cancel Test", "context-cancel-test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	site.Name = "Updated"
+	err := svc.UpdateSite(ctx, site)
+	if err == nil {
+		t.Error("UpdateSite should fail with cancelled context")
 	}
 }
 
-func TestServiceDeleteImageAndVerify(t *testing.T) {
+func TestServiceDeleteSiteCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Del Image Site", "del-image-site")
+	site := createTestSite(t, svc, "Delete Context Cancel", "delete-context-cancel")
 
-	image := NewImage(site.ID, "delete.jpg", "/images/delete.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
 
-	// Verify exists
-	_, err := svc.GetImage(ctx, image.ID)
-	if err != nil {
-		t.Fatalf("Image should exist: %v", err)
+	err := svc.DeleteSite(ctx, site.ID)
+	if err == nil {
+		t.Error("DeleteSite should fail with cancelled context")
 	}
+}
 
-	// Delete
-	err = svc.DeleteImage(ctx, image.ID)
-	if err != nil {
-		t.Fatalf("DeleteImage() error = %v", err)
+func TestServiceUpdateTagCancelledContext(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Tag Context Test", "tag-context-test")
+
+	tag := NewTag(site.ID, "test-tag")
+	if err := svc.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
 	}
 
-	// Verify deleted
-	_, err = svc.GetImage(ctx, image.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tag.Name = "updated-tag"
+	err := svc.UpdateTag(ctx2, tag)
+	if err == nil {
+		t.Error("UpdateTag should fail with cancelled context")
 	}
 }
 
-func TestServiceDeleteContentAndVerify(t *testing.T) {
+func TestServiceDeleteTagCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Del Content Site", "del-content-site")
-
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
-
-	content := NewContent(site.ID, section.ID, "Delete Me", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	site := createTestSite(t, svc, "Delete Tag Context", "delete-tag-context")
 
-	// Verify exists
-	_, err := svc.GetContent(ctx, content.ID)
-	if err != nil {
-		t.Fatalf("Content should exist: %v", err)
+	tag := NewTag(site.ID, "tag-to-delete")
+	if err := svc.CreateTag(ctx, tag); err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
 	}
 
-	// Delete
-	err = svc.DeleteContent(ctx, content.ID)
-	if err != nil {
-		t.Fatalf("DeleteContent() error = %v", err)
-	}
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
 
-	// Verify deleted
-	_, err = svc.GetContent(ctx, content.ID)
-	if !errors.Is(err, ErrNotFound) {
-		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+	err := svc.DeleteTag(ctx2, tag.ID)
+	if err == nil {
+		t.Error("DeleteTag should fail with cancelled context")
 	}
 }
 
-func TestServiceLinkUnlinkImageToSectionFull(t *testing.T) {
+func TestServiceUpdateLayoutCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Section Img Site 2", "section-img-site-2")
+	site := createTestSite(t, svc, "Layout Context Test", "layout-context-test")
 
-	section := NewSection(site.ID, "Images", "", "/images")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
+	layout := NewLayout(site.ID, "test-layout", "blog")
+	if err := svc.CreateLayout(ctx, layout); err != nil {
+		t.Fatalf("CreateLayout failed: %v", err)
+	}
 
-	image := NewImage(site.ID, "section.jpg", "/images/section.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
 
-	// Link image (with isHeader = false)
-	err := svc.LinkImageToSection(ctx, section.ID, image.ID, false)
-	if err != nil {
-		t.Fatalf("LinkImageToSection() error = %v", err)
+	layout.Name = "updated-layout"
+	err := svc.UpdateLayout(ctx2, layout)
+	if err == nil {
+		t.Error("UpdateLayout should fail with cancelled context")
 	}
+}
 
-	// Verify linked
-	images, err := svc.GetSectionImagesWithDetails(ctx, section.ID)
-	if err != nil {
-		t.Fatalf("GetSectionImagesWithDetails() error = %v", err)
-	}
-	if len(images) != 1 {
-		t.Fatalf("Expected 1 image, got %d", len(images))
-	}
+func TestServiceUpdateSettingCancelledContext(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
 
-	// Get section image details
-	details, err := svc.GetSectionImageDetails(ctx, images[0].SectionImageID)
-	if err != nil {
-		t.Fatalf("GetSectionImageDetails() error = %v", err)
-	}
-	if details.ImageID != image.ID {
-		t.Errorf("ImageID = %v, want %v", details.ImageID, image.ID)
-	}
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Setting Context Test", "setting-context-test")
 
-	// Unlink image
-	err = svc.UnlinkImageFromSection(ctx, images[0].SectionImageID)
-	if err != nil {
-		t.Fatalf("UnlinkImageFromSection() error = %v", err)
+	setting := NewSetting(site.ID, "test-setting", "value")
+	if err := svc.CreateSetting(ctx, setting); err != nil {
+		t.Fatalf("CreateSetting failed: %v", err)
 	}
 
-	// Verify unlinked
-	images, _ = svc.GetSectionImagesWithDetails(ctx, section.ID)
-	if len(images) != 0 {
-		t.Errorf("Expected 0 images after unlink, got %d", len(images))
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	setting.Value = "updated"
+	err := svc.UpdateSetting(ctx2, setting)
+	if err == nil {
+		t.Error("UpdateSetting should fail with cancelled context")
 	}
 }
 
-func TestServiceContentImageDetailsFull(t *testing.T) {
+func TestServiceUpdateImageCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Content Img Details Site 2", "content-img-details-site-2")
-
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
+	site := createTestSite(t, svc, "Image Context Test", "image-context-test")
 
-	content := NewContent(site.ID, section.ID, "Image Post", "Body with images")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	image := NewImage(site.ID, "test.jpg", "/images/test.jpg")
+	if err := svc.CreateImage(ctx, image); err != nil {
+		t.Fatalf("CreateImage failed: %v", err)
+	}
 
-	image := NewImage(site.ID, "content.jpg", "/images/content.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
-	svc.CreateImage(ctx, image)
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
 
-	// Link image to content (not as header)
-	err := svc.LinkImageToContent(ctx, content.ID, image.ID, false)
-	if err != nil {
-		t.Fatalf("LinkImageToContent() error = %v", err)
+	image.FileName = "updated.jpg"
+	err := svc.UpdateImage(ctx2, image)
+	if err == nil {
+		t.Error("UpdateImage should fail with cancelled context")
 	}
+}
 
-	// Get content images
-	images, err := svc.GetContentImagesWithDetails(ctx, content.ID)
-	if err != nil {
-		t.Fatalf("GetContentImagesWithDetails() error = %v", err)
-	}
-	if len(images) != 1 {
-		t.Fatalf("Expected 1 image, got %d", len(images))
-	}
+func TestServiceUpdateSectionCancelledContext(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
 
-	// Get content image details
-	details, err := svc.GetContentImageDetails(ctx, images[0].ContentImageID)
-	if err != nil {
-		t.Fatalf("GetContentImageDetails() error = %v", err)
-	}
-	if details.ImageID != image.ID {
-		t.Errorf("ImageID = %v, want %v", details.ImageID, image.ID)
-	}
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Section Context Test", "section-context-test")
 
-	// Unlink
-	err = svc.UnlinkImageFromContent(ctx, images[0].ContentImageID)
-	if err != nil {
-		t.Fatalf("UnlinkImageFromContent() error = %v", err)
+	section := NewSection(site.ID, "test-section", "Test description", "test-path")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection failed: %v", err)
 	}
 
-	// Verify unlinked
-	images, _ = svc.GetContentImagesWithDetails(ctx, content.ID)
-	if len(images) != 0 {
-		t.Errorf("Expected 0 images after unlink, got %d", len(images))
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	section.Name = "updated-section"
+	err := svc.UpdateSection(ctx2, section)
+	if err == nil {
+		t.Error("UpdateSection should fail with cancelled context")
 	}
 }
 
-// Tests to improve coverage on error paths
-
-func TestServiceAddTagToContentDuplicate(t *testing.T) {
+func TestServiceUpdateMetaCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Dup Tag Site", "dup-tag-site")
+	site := createTestSite(t, svc, "Meta Context Test", "meta-context-test")
 
-	section := NewSection(site.ID, "Blog", "", "/blog")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
-	svc.CreateSection(ctx, section)
+	section := NewSection(site.ID, "section", "Description", "path")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection failed: %v", err)
+	}
 
-	content := NewContent(site.ID, section.ID, "Tagged Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
-	svc.CreateContent(ctx, content)
+	content := NewContent(site.ID, section.ID, "Test Content", "Body")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent failed: %v", err)
+	}
 
-	// Add tag first time - should succeed
-	err := svc.AddTagToContent(ctx, content.ID, "test-tag", site.ID)
-	if err != nil {
-		t.Fatalf("First AddTagToContent() error = %v", err)
+	meta := NewMeta(site.ID, content.ID)
+	if err := svc.CreateMeta(ctx, meta); err != nil {
+		t.Fatalf("CreateMeta failed: %v", err)
 	}
 
-	// Add same tag again - might fail with constraint or succeed
-	err = svc.AddTagToContent(ctx, content.ID, "test-tag", site.ID)
-	// Just log the result, we're exercising the code path
-	if err != nil {
-		t.Logf("Second AddTagToContent() returned: %v", err)
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	meta.Description = "updated"
+	err := svc.UpdateMeta(ctx2, meta)
+	if err == nil {
+		t.Error("UpdateMeta should fail with cancelled context")
 	}
 }
 
-func TestServiceCreateSiteDuplicateSlugError(t *testing.T) {
+func TestServiceDeleteContributorCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Delete Contributor Context", "delete-contrib-context")
 
-	// Create first site
-	site1 := NewSite("Site One", "same-slug-err")
-	err := svc.CreateSite(ctx, site1)
-	if err != nil {
-		t.Fatalf("First CreateSite() error = %v", err)
+	contributor := NewContributor(site.ID, "test-contrib", "Test", "Contrib")
+	contributor.CreatedBy = uuid.New()
+	contributor.UpdatedBy = contributor.CreatedBy
+	if err := svc.CreateContributor(ctx, contributor); err != nil {
+		t.Fatalf("CreateContributor failed: %v", err)
 	}
 
-	// Try to create second site with same slug
-	site2 := NewSite("Site Two", "same-slug-err")
-	err = svc.CreateSite(ctx, site2)
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := svc.DeleteContributor(ctx2, contributor.ID)
 	if err == nil {
-		t.Error("Expected error for duplicate slug")
+		t.Error("DeleteContributor should fail with cancelled context")
 	}
 }
 
-func TestServiceCreateContentInvalidSection(t *testing.T) {
+func TestServiceUnlinkImageFromContentCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Invalid Section Site", "invalid-section-site")
+	site := createTestSite(t, svc, "Unlink Image Context", "unlink-image-context")
 
-	// Try to create content with non-existent section ID
-	content := NewContent(site.ID, uuid.New(), "Invalid Post", "Body")
-	content.CreatedBy = uuid.New()
-	content.UpdatedBy = content.CreatedBy
+	section := NewSection(site.ID, "section", "Description", "path")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection failed: %v", err)
+	}
 
-	err := svc.CreateContent(ctx, content)
-	if err == nil {
-		t.Error("Expected error for invalid section ID")
+	content := NewContent(site.ID, section.ID, "Test Content", "Body")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent failed: %v", err)
 	}
-}
 
-func TestServiceCreateContributorInvalidSite(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+	image := NewImage(site.ID, "test.jpg", "/images/test.jpg")
+	if err := svc.CreateImage(ctx, image); err != nil {
+		t.Fatalf("CreateImage failed: %v", err)
+	}
 
-	ctx := context.Background()
+	if err := svc.LinkImageToContent(ctx, content.ID, image.ID, false); err != nil {
+		t.Fatalf("LinkImageToContent failed: %v", err)
+	}
 
-	// Try to create contributor with non-existent site ID
-	contributor := NewContributor(uuid.New(), "invalid", "Invalid", "User")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
+	// Get the content image ID
+	images, err := svc.GetContentImagesWithDetails(ctx, content.ID)
+	if err != nil || len(images) == 0 {
+		t.Fatalf("GetContentImagesWithDetails failed: %v", err)
+	}
+	contentImageID := images[0].ContentImageID
 
-	err := svc.CreateContributor(ctx, contributor)
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = svc.UnlinkImageFromContent(ctx2, contentImageID)
 	if err == nil {
-		t.Error("Expected error for invalid site ID")
+		t.Error("UnlinkImageFromContent should fail with cancelled context")
 	}
 }
 
-func TestServiceCreateSectionInvalidSite(t *testing.T) {
+func TestServiceUnlinkImageFromSectionCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Unlink Section Image Context", "unlink-section-image")
 
-	// Try to create section with non-existent site ID
-	section := NewSection(uuid.New(), "Invalid Section", "Desc", "/invalid")
-	section.CreatedBy = uuid.New()
-	section.UpdatedBy = section.CreatedBy
+	section := NewSection(site.ID, "section", "Description", "path")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection failed: %v", err)
+	}
 
-	err := svc.CreateSection(ctx, section)
+	image := NewImage(site.ID, "test.jpg", "/images/test.jpg")
+	if err := svc.CreateImage(ctx, image); err != nil {
+		t.Fatalf("CreateImage failed: %v", err)
+	}
+
+	if err := svc.LinkImageToSection(ctx, section.ID, image.ID, false); err != nil {
+		t.Fatalf("LinkImageToSection failed: %v", err)
+	}
+
+	// Get the section image ID
+	images, err := svc.GetSectionImagesWithDetails(ctx, section.ID)
+	if err != nil || len(images) == 0 {
+		t.Fatalf("GetSectionImagesWithDetails failed: %v", err)
+	}
+	sectionImageID := images[0].SectionImageID
+
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = svc.UnlinkImageFromSection(ctx2, sectionImageID)
 	if err == nil {
-		t.Error("Expected error for invalid site ID")
+		t.Error("UnlinkImageFromSection should fail with cancelled context")
 	}
 }
 
-func TestServiceCreateLayoutInvalidSite(t *testing.T) {
+func TestServiceAddTagToContentCancelledContext(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Add Tag Context Test", "add-tag-context")
 
-	// Try to create layout with non-existent site ID
-	layout := NewLayout(uuid.New(), "Invalid Layout", "Desc")
-	layout.CreatedBy = uuid.New()
-	layout.UpdatedBy = layout.CreatedBy
+	section := NewSection(site.ID, "section", "Description", "path")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection failed: %v", err)
+	}
 
-	err := svc.CreateLayout(ctx, layout)
+	content := NewContent(site.ID, section.ID, "Test Content", "Body")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent failed: %v", err)
+	}
+
+	ctx2, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := svc.AddTagToContent(ctx2, content.ID, "new-tag", site.ID)
 	if err == nil {
-		t.Error("Expected error for invalid site ID")
+		t.Error("AddTagToContent should fail with cancelled context")
 	}
 }
 
-func TestServiceCreateTagInvalidSite(t *testing.T) {
+func TestServiceGetAdjacentContent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Adjacent Site", "adjacent-site")
 
-	// Try to create tag with non-existent site ID
-	tag := NewTag(uuid.New(), "invalid-tag")
-	tag.CreatedBy = uuid.New()
-	tag.UpdatedBy = tag.CreatedBy
+	blog := NewSection(site.ID, "Blog", "", "/blog")
+	notes := NewSection(site.ID, "Notes", "", "/notes")
+	for _, sec := range []*Section{blog, notes} {
+		if err := svc.CreateSection(ctx, sec); err != nil {
+			t.Fatalf("CreateSection() error = %v", err)
+		}
+	}
 
-	err := svc.CreateTag(ctx, tag)
-	if err == nil {
-		t.Error("Expected error for invalid site ID")
+	base := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	create := func(sectionID uuid.UUID, heading string, publishedAt time.Time, draft bool) *Content {
+		c := NewContent(site.ID, sectionID, heading, "Body")
+		c.Draft = draft
+		c.PublishedAt = &publishedAt
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+		return c
+	}
+
+	first := create(blog.ID, "First", base, false)
+	create(blog.ID, "Hidden Draft", base.Add(30*time.Minute), true)
+	note := create(notes.ID, "Note", base.Add(45*time.Minute), false)
+	second := create(blog.ID, "Second", base.Add(time.Hour), false)
+
+	newer, older, err := svc.GetAdjacentContent(ctx, blog.ID, first.ID)
+	if err != nil {
+		t.Fatalf("GetAdjacentContent() error = %v", err)
+	}
+	if older != nil {
+		t.Errorf("expected no older content for first post, got %q", older.Heading)
+	}
+	if newer == nil || newer.ID != second.ID {
+		t.Errorf("expected newer = Second, got %v", newer)
+	}
+
+	scope := NewSetting(site.ID, "Adjacent navigation scope", AdjacentScopeSite)
+	scope.RefKey = "ssg.navigation.adjacent.scope"
+	if err := svc.CreateSetting(ctx, scope); err != nil {
+		t.Fatalf("CreateSetting() error = %v", err)
+	}
+
+	newer, _, err = svc.GetAdjacentContent(ctx, blog.ID, first.ID)
+	if err != nil {
+		t.Fatalf("GetAdjacentContent() error = %v", err)
+	}
+	if newer == nil || newer.ID != note.ID {
+		t.Errorf("site scope: expected newer = Note, got %v", newer)
+	}
+
+	if _, _, err := svc.GetAdjacentContent(ctx, blog.ID, uuid.New()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for unknown content, got %v", err)
 	}
 }
 
-func TestServiceCreateSettingInvalidSite(t *testing.T) {
+func TestServiceResolveLayoutForContent(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Resolve Site", "resolve-site")
 
-	// Try to create setting with non-existent site ID
-	setting := NewSetting(uuid.New(), "invalid_param", "value")
-	setting.CreatedBy = uuid.New()
-	setting.UpdatedBy = setting.CreatedBy
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	if err := svc.CreateSection(ctx, section); err != nil {
+		t.Fatalf("CreateSection() error = %v", err)
+	}
+	content := NewContent(site.ID, section.ID, "Post", "Body")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
 
-	err := svc.CreateSetting(ctx, setting)
-	if err == nil {
-		t.Error("Expected error for invalid site ID")
+	newLayout := func(name string) *Layout {
+		l := NewLayout(site.ID, name, "")
+		l.Code = `{{ define "layout.html" }}` + name + `{{ end }}`
+		if err := svc.CreateLayout(ctx, l); err != nil {
+			t.Fatalf("CreateLayout() error = %v", err)
+		}
+		return l
+	}
+
+	// No layouts at all: built-in.
+	got, err := svc.ResolveLayoutForContent(ctx, content)
+	if err != nil {
+		t.Fatalf("ResolveLayoutForContent() error = %v", err)
+	}
+	if !got.IsBuiltin() {
+		t.Errorf("expected built-in layout, got %q", got.Name)
+	}
+
+	// Site default only.
+	siteLayout := newLayout("Site")
+	site.DefaultLayoutID = siteLayout.ID
+	if err := svc.UpdateSite(ctx, site); err != nil {
+		t.Fatalf("UpdateSite() error = %v", err)
+	}
+	got, err = svc.ResolveLayoutForContent(ctx, content)
+	if err != nil {
+		t.Fatalf("ResolveLayoutForContent() error = %v", err)
+	}
+	if got.ID != siteLayout.ID {
+		t.Errorf("expected site default layout, got %q", got.Name)
+	}
+
+	// Section layout wins over site default.
+	sectionLayout := newLayout("Section")
+	section.LayoutID = sectionLayout.ID
+	if err := svc.UpdateSection(ctx, section); err != nil {
+		t.Fatalf("UpdateSection() error = %v", err)
+	}
+	got, err = svc.ResolveLayoutForContent(ctx, content)
+	if err != nil {
+		t.Fatalf("ResolveLayoutForContent() error = %v", err)
+	}
+	if got.ID != sectionLayout.ID {
+		t.Errorf("expected section layout, got %q", got.Name)
+	}
+
+	// An empty section layout is skipped in favour of the site default.
+	empty := NewLayout(site.ID, "Empty", "")
+	if err := svc.CreateLayout(ctx, empty); err != nil {
+		t.Fatalf("CreateLayout() error = %v", err)
+	}
+	section.LayoutID = empty.ID
+	if err := svc.UpdateSection(ctx, section); err != nil {
+		t.Fatalf("UpdateSection() error = %v", err)
+	}
+	got, err = svc.ResolveLayoutForContent(ctx, content)
+	if err != nil {
+		t.Fatalf("ResolveLayoutForContent() error = %v", err)
+	}
+	if got.ID != siteLayout.ID {
+		t.Errorf("expected site default layout for empty section layout, got %q", got.Name)
+	}
+
+	// The kind layout wins over the section layout.
+	section.LayoutID = sectionLayout.ID
+	if err := svc.UpdateSection(ctx, section); err != nil {
+		t.Fatalf("UpdateSection() error = %v", err)
+	}
+	kindLayout := newLayout("Kind")
+	kind, err := svc.GetContentKind(ctx, site.ID, KindPost)
+	if err != nil {
+		t.Fatalf("GetContentKind() error = %v", err)
+	}
+	kind.LayoutID = kindLayout.ID
+	if err := svc.SaveContentKind(ctx, kind); err != nil {
+		t.Fatalf("SaveContentKind() error = %v", err)
+	}
+	got, err = svc.ResolveLayoutForContent(ctx, content)
+	if err != nil {
+		t.Fatalf("ResolveLayoutForContent() error = %v", err)
+	}
+	if got.ID != kindLayout.ID {
+		t.Errorf("expected kind layout, got %q", got.Name)
+	}
+
+	// The content's own layout wins over everything, and is saved with it.
+	contentLayout := newLayout("Content")
+	content.LayoutID = contentLayout.ID
+	if err := svc.UpdateContent(ctx, content); err != nil {
+		t.Fatalf("UpdateContent() error = %v", err)
+	}
+	stored, err := svc.GetContent(ctx, content.ID)
+	if err != nil {
+		t.Fatalf("GetContent() error = %v", err)
+	}
+	if stored.LayoutID != contentLayout.ID {
+		t.Fatalf("content layout not saved: %v", stored.LayoutID)
+	}
+	got, err = svc.ResolveLayoutForContent(ctx, stored)
+	if err != nil {
+		t.Fatalf("ResolveLayoutForContent() error = %v", err)
+	}
+	if got.ID != contentLayout.ID {
+		t.Errorf("expected content layout, got %q", got.Name)
+	}
+
+	// A deleted content layout falls back to the kind layout.
+	if err := svc.DeleteLayout(ctx, contentLayout.ID); err != nil {
+		t.Fatalf("DeleteLayout() error = %v", err)
+	}
+	got, err = svc.ResolveLayoutForContent(ctx, content)
+	if err != nil {
+		t.Fatalf("ResolveLayoutForContent() error = %v", err)
+	}
+	if got.ID != kindLayout.ID {
+		t.Errorf("expected kind layout after deleting the content layout, got %q", got.Name)
 	}
 }
 
-func TestServiceCreateImageInvalidSite(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+func TestServiceGetSiteStats(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
 
+	cfg := &config.Config{}
+	cfg.SSG.SitesBasePath = t.TempDir()
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, cfg, newTestLogger())
 	ctx := context.Background()
 
-	// Try to create image with non-existent site ID
-	image := NewImage(uuid.New(), "invalid.jpg", "/images/invalid.jpg")
-	image.CreatedBy = uuid.New()
-	image.UpdatedBy = image.CreatedBy
+	site := createTestSite(t, svc, "Stats", "stats")
+	other := createTestSite(t, svc, "Other", "other")
 
-	err := svc.CreateImage(ctx, image)
-	if err == nil {
-		t.Error("Expected error for invalid site ID")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
+	svc.CreateContributor(ctx, NewContributor(site.ID, "jane", "Jane", "Doe"))
+	svc.CreateImage(ctx, NewImage(site.ID, "a.jpg", "a.jpg"))
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	for i, tc := range []struct {
+		draft       bool
+		publishedAt *time.Time
+	}{{false, &past}, {false, nil}, {true, nil}, {false, &future}} {
+		c := NewContent(site.ID, section.ID, fmt.Sprintf("Item %d", i), "Body")
+		c.Draft = tc.draft
+		c.PublishedAt = tc.publishedAt
+		c.UpdatedAt = past.Add(time.Duration(i) * time.Minute)
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
+		if i == 0 {
+			svc.AddTagToContent(ctx, c.ID, "Go", site.ID)
+		}
+	}
+	otherSection := NewSection(other.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, otherSection)
+	if err := svc.CreateContent(ctx, NewContent(other.ID, otherSection.ID, "Elsewhere", "Body")); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
 	}
-}
-
-func TestServiceCreateMetaInvalidContent(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
-
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Invalid Meta Site", "invalid-meta-site")
 
-	// Try to create meta with non-existent content ID
-	meta := NewMeta(site.ID, uuid.New())
-	meta.CreatedBy = uuid.New()
-	meta.UpdatedBy = meta.CreatedBy
+	file := filepath.Join(NewWorkspace(cfg.SSG.SitesBasePath).GetImagesPath(site.Slug), "a.jpg")
+	if err := EnsureDir(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, make([]byte, 1500), 0644); err != nil {
+		t.Fatal(err)
+	}
 
-	err := svc.CreateMeta(ctx, meta)
-	if err == nil {
-		t.Error("Expected error for invalid content ID")
+	stats, err := svc.GetSiteStats(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("GetSiteStats() error = %v", err)
 	}
-}
 
-func TestServiceLinkImageToContentInvalidIDs(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+	got := []int{stats.Published, stats.Drafts, stats.Scheduled, stats.Sections, stats.Tags, stats.Images, stats.Contributors}
+	want := []int{2, 1, 1, 1, 1, 1, 1}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("counts = %v, want %v", got, want)
+	}
+	if stats.DiskUsage != 1500 {
+		t.Errorf("DiskUsage = %d, want 1500", stats.DiskUsage)
+	}
+	if len(stats.RecentlyEdited) != 4 || stats.RecentlyEdited[0].Heading != "Item 3" {
+		t.Errorf("RecentlyEdited = %d items, first %q, want 4 starting with Item 3", len(stats.RecentlyEdited), stats.RecentlyEdited[0].Heading)
+	}
+	if stats.LastPublishedAt != nil {
+		t.Errorf("LastPublishedAt = %v, want nil", stats.LastPublishedAt)
+	}
 
-	ctx := context.Background()
+	t.Run("cached", func(t *testing.T) {
+		svc.CreateSection(ctx, NewSection(site.ID, "Notes", "", "/notes"))
+		cached, _ := svc.GetSiteStats(ctx, site.ID)
+		if cached != stats {
+			t.Error("expected cached stats within the TTL")
+		}
+	})
 
-	// Try to link with non-existent IDs
-	err := svc.LinkImageToContent(ctx, uuid.New(), uuid.New(), false)
-	if err == nil {
-		t.Error("Expected error for invalid IDs")
-	}
+	t.Run("publish invalidates cache", func(t *testing.T) {
+		published := time.Now()
+		site.LastPublishedAt = &published
+		if err := svc.UpdateSite(ctx, site); err != nil {
+			t.Fatalf("UpdateSite() error = %v", err)
+		}
+		fresh, _ := svc.GetSiteStats(ctx, site.ID)
+		if fresh.LastPublishedAt == nil || fresh.Sections != 2 {
+			t.Errorf("got LastPublishedAt = %v, Sections = %d; want publish time and 2 sections", fresh.LastPublishedAt, fresh.Sections)
+		}
+	})
 }
 
-func TestServiceLinkImageToSectionInvalidIDs(t *testing.T) {
+func TestServiceMarkSiteGeneratedAndPublished(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
-
 	ctx := context.Background()
+	site := createTestSite(t, svc, "Timestamps", "timestamps")
 
-	// Try to link with non-existent IDs
-	err := svc.LinkImageToSection(ctx, uuid.New(), uuid.New(), false)
-	if err == nil {
-		t.Error("Expected error for invalid IDs")
+	generated := time.Now().Add(-2 * time.Hour)
+	if err := svc.MarkSiteGenerated(ctx, site.ID, generated); err != nil {
+		t.Fatalf("MarkSiteGenerated() error = %v", err)
+	}
+	published := time.Now().Add(-time.Hour)
+	if err := svc.MarkSitePublished(ctx, site.ID, published, "abc123"); err != nil {
+		t.Fatalf("MarkSitePublished() error = %v", err)
 	}
-}
 
-func TestServiceAddTagToContentByIDInvalidIDs(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+	got, _ := svc.GetSite(ctx, site.ID)
+	if got.LastGeneratedAt == nil || !got.LastGeneratedAt.Equal(generated) {
+		t.Errorf("LastGeneratedAt = %v, want %v", got.LastGeneratedAt, generated)
+	}
+	if got.LastPublishedAt == nil || !got.LastPublishedAt.Equal(published) || got.LastPublishedCommit != "abc123" {
+		t.Errorf("published = %v %q, want %v %q", got.LastPublishedAt, got.LastPublishedCommit, published, "abc123")
+	}
 
-	ctx := context.Background()
+	// A publish with nothing to commit keeps the previous commit.
+	if err := svc.MarkSitePublished(ctx, site.ID, time.Now(), ""); err != nil {
+		t.Fatalf("MarkSitePublished() error = %v", err)
+	}
+	got, _ = svc.GetSite(ctx, site.ID)
+	if got.LastPublishedCommit != "abc123" || !got.LastPublishedAt.After(published) {
+		t.Errorf("after no-op publish: %v %q, want later time and abc123", got.LastPublishedAt, got.LastPublishedCommit)
+	}
 
-	// Try to add tag with non-existent IDs
-	err := svc.AddTagToContentByID(ctx, uuid.New(), uuid.New())
-	if err == nil {
-		t.Error("Expected error for invalid IDs")
+	// Editing the site keeps the recorded timestamps.
+	got.Name = "Renamed"
+	if err := svc.UpdateSite(ctx, got); err != nil {
+		t.Fatalf("UpdateSite() error = %v", err)
+	}
+	got, _ = svc.GetSite(ctx, site.ID)
+	if got.LastGeneratedAt == nil || got.LastPublishedCommit != "abc123" {
+		t.Errorf("UpdateSite() lost timestamps: %+v", got)
 	}
 }
 
-func TestServiceSetContributorProfileInvalidIDs(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+func TestServiceGenerateAllSites(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
 
+	// Without the embedded templates every build fails, which shows a failing
+	// site does not stop the others.
+	svc := NewService(&testutil.TestDBProvider{DB: db}, NewHTMLGenerator(NewWorkspace(t.TempDir()), embed.FS{}), &config.Config{}, newTestLogger())
 	ctx := context.Background()
 
-	// Try to set profile with non-existent contributor ID
-	// SQLite UPDATE doesn't error on non-existent rows
-	err := svc.SetContributorProfile(ctx, uuid.New(), uuid.New(), uuid.New().String())
-	// Just exercise the code path
+	first := createTestSite(t, svc, "First", "first")
+	createTestSite(t, svc, "Second", "second")
+	archived := createTestSite(t, svc, "Archived", "archived")
+	archived.Active = false
+	svc.UpdateSite(ctx, archived)
+
+	results, err := svc.GenerateAllSites(ctx)
 	if err != nil {
-		t.Logf("SetContributorProfile for non-existent returned: %v", err)
+		t.Fatalf("GenerateAllSites() error = %v", err)
+	}
+	var slugs []string
+	for _, r := range results {
+		slugs = append(slugs, r.Site.Slug)
+		if r.Err == nil || r.Result != nil {
+			t.Errorf("site %s result = %+v, want an error", r.Site.Slug, r)
+		}
+	}
+	if strings.Join(slugs, ",") != "first,second" {
+		t.Errorf("regenerated %v, want every active site", slugs)
 	}
-}
-
-func TestServiceGetContentImageDetailsNotFound(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
 
-	ctx := context.Background()
+	got, _ := svc.GetSite(ctx, first.ID)
+	if got.LastGeneratedAt != nil {
+		t.Error("a failed site should not record a generation time")
+	}
 
-	// Try to get details for non-existent content image
-	_, err := svc.GetContentImageDetails(ctx, uuid.New())
-	if err == nil {
-		t.Error("Expected error for non-existent content image")
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := svc.GenerateAllSites(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateAllSites() with a cancelled context error = %v", err)
 	}
 }
 
-func TestServiceGetSectionImageDetailsNotFound(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
+func TestServiceReimportFileConflict(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
 	defer cleanup()
-
 	ctx := context.Background()
 
-	// Try to get details for non-existent section image
-	_, err := svc.GetSectionImageDetails(ctx, uuid.New())
-	if err == nil {
-		t.Error("Expected error for non-existent section image")
+	site := createTestSite(t, svc, "Import", "import")
+	section := NewSection(site.ID, "Blog", "", "blog")
+	svc.CreateSection(ctx, section)
+	userID := uuid.New()
+	if _, err := db.Exec(`INSERT INTO user (id, short_id, email, password_hash, name, status, roles, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))`,
+		userID.String(), "u123", "editor@test.com", "hash", "editor", "active", "editor"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
 	}
-}
 
-func TestServiceUpdateContributorNonExistent(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
-
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Update Nonexistent Site", "update-nonexistent-site")
-
-	// Try to update non-existent contributor
-	contributor := NewContributor(site.ID, "nonexistent", "Non", "Existent")
-	contributor.UpdatedAt = time.Now()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "post.md")
+	write := func(body string) ImportFile {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files, err := svc.ScanImportDirectory(ctx, dir)
+		if err != nil || len(files) != 1 {
+			t.Fatalf("ScanImportDirectory() = %d files, %v", len(files), err)
+		}
+		return files[0]
+	}
 
-	err := svc.UpdateContributor(ctx, contributor)
-	// SQLite UPDATE doesn't error on non-existent, just log
+	content, imp, err := svc.ImportFile(ctx, site.ID, userID, write("# Post\n\nFirst draft"), section.ID)
 	if err != nil {
-		t.Logf("UpdateContributor for non-existent returned: %v", err)
+		t.Fatalf("ImportFile() error = %v", err)
 	}
-}
-
-func TestServiceUpdateSiteCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
-
-	site := createTestSite(t, svc, "Context Cancel Test", "context-cancel-test")
-
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
 
-	site.Name = "Updated"
-	err := svc.UpdateSite(ctx, site)
-	if err == nil {
-		t.Error("UpdateSite should fail with cancelled context")
+	// Only the file changed: reimporting needs no force.
+	write("# Post\n\nSecond draft")
+	if diff, _ := svc.DiffImport(ctx, imp.ID); diff == nil || !diff.FileChanged || diff.ContentChanged {
+		t.Fatalf("DiffImport() = %+v, want only the file changed", diff)
+	}
+	if _, err := svc.ReimportFile(ctx, imp.ID, false); err != nil {
+		t.Fatalf("ReimportFile() error = %v", err)
 	}
-}
 
-func TestServiceDeleteSiteCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+	// Both changed: the editor's change is kept unless forced.
+	content, _ = svc.GetContent(ctx, content.ID)
+	content.Body = "Edited in Clio"
+	content.UpdatedAt = time.Now().Add(time.Second)
+	if err := svc.UpdateContent(ctx, content); err != nil {
+		t.Fatal(err)
+	}
+	write("# Post\n\nThird draft")
 
-	site := createTestSite(t, svc, "Delete Context Cancel", "delete-context-cancel")
+	diff, err := svc.DiffImport(ctx, imp.ID)
+	if err != nil {
+		t.Fatalf("DiffImport() error = %v", err)
+	}
+	if !diff.Conflict() || len(diff.Fields) != 1 || diff.Fields[0].Incoming != "# Post\n\nThird draft" {
+		t.Errorf("DiffImport() = %+v, want a body conflict", diff)
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
+	if _, err := svc.ReimportFile(ctx, imp.ID, false); !errors.Is(err, ErrImportConflict) {
+		t.Fatalf("ReimportFile() error = %v, want ErrImportConflict", err)
+	}
+	if got, _ := svc.GetContent(ctx, content.ID); got.Body != "Edited in Clio" {
+		t.Errorf("body = %q, a conflict must not overwrite it", got.Body)
+	}
 
-	err := svc.DeleteSite(ctx, site.ID)
-	if err == nil {
-		t.Error("DeleteSite should fail with cancelled context")
+	if _, err := svc.ReimportFile(ctx, imp.ID, true); err != nil {
+		t.Fatalf("ReimportFile(force) error = %v", err)
+	}
+	if got, _ := svc.GetContent(ctx, content.ID); got.Body != "# Post\n\nThird draft" {
+		t.Errorf("body = %q after a forced reimport", got.Body)
 	}
 }
 
-func TestServiceUpdateTagCancelledContext(t *testing.T) {
+func TestServiceGetContentEditedSince(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
-
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Tag Context Test", "tag-context-test")
+	site := createTestSite(t, svc, "Edits", "edits")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
 
-	tag := NewTag(site.ID, "test-tag")
-	if err := svc.CreateTag(ctx, tag); err != nil {
-		t.Fatalf("CreateTag failed: %v", err)
+	publishedAt := time.Now().Add(-time.Hour)
+	for i, offset := range []time.Duration{-2 * time.Hour, 10 * time.Minute, 30 * time.Minute} {
+		c := NewContent(site.ID, section.ID, fmt.Sprintf("Item %d", i), "Body")
+		c.UpdatedAt = publishedAt.Add(offset)
+		if err := svc.CreateContent(ctx, c); err != nil {
+			t.Fatalf("CreateContent() error = %v", err)
+		}
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	edited, err := svc.GetContentEditedSince(ctx, site.ID, publishedAt)
+	if err != nil {
+		t.Fatalf("GetContentEditedSince() error = %v", err)
+	}
+	if len(edited) != 2 || edited[0].Heading != "Item 2" || edited[1].Heading != "Item 1" {
+		var headings []string
+		for _, c := range edited {
+			headings = append(headings, c.Heading)
+		}
+		t.Errorf("edited = %v, want [Item 2 Item 1]", headings)
+	}
 
-	tag.Name = "updated-tag"
-	err := svc.UpdateTag(ctx2, tag)
-	if err == nil {
-		t.Error("UpdateTag should fail with cancelled context")
+	// Timestamps in another zone compare by instant, not by text.
+	edited, _ = svc.GetContentEditedSince(ctx, site.ID, publishedAt.In(time.FixedZone("UTC+9", 9*3600)))
+	if len(edited) != 2 {
+		t.Errorf("with zoned since, got %d items, want 2", len(edited))
 	}
 }
 
-func TestServiceDeleteTagCancelledContext(t *testing.T) {
+func TestServiceCheckPathCollision(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Delete Tag Context", "delete-tag-context")
+	site := createTestSite(t, svc, "Collision Site", "collision-site")
 
-	tag := NewTag(site.ID, "tag-to-delete")
-	if err := svc.CreateTag(ctx, tag); err != nil {
-		t.Fatalf("CreateTag failed: %v", err)
+	blog := NewSection(site.ID, "Blog", "", "blog")
+	tags := NewSection(site.ID, "Tags", "", "tags")
+	svc.CreateSection(ctx, blog)
+	svc.CreateSection(ctx, tags)
+
+	first := NewContent(site.ID, blog.ID, "Hello", "Body")
+	first.ShortID = "abc123"
+	second := NewContent(site.ID, blog.ID, "Other", "Body")
+	svc.CreateContent(ctx, first)
+	svc.CreateContent(ctx, second)
+
+	path, err := svc.ContentOutputPath(ctx, first)
+	if err != nil || path != "blog/hello-abc123" {
+		t.Fatalf("ContentOutputPath() = %q, %v", path, err)
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	kinds := func(collisions []*PathCollision) []string {
+		var got []string
+		for _, c := range collisions {
+			got = append(got, c.Kind+":"+c.Name)
+		}
+		return got
+	}
 
-	err := svc.DeleteTag(ctx2, tag.ID)
-	if err == nil {
-		t.Error("DeleteTag should fail with cancelled context")
+	tests := []struct {
+		name    string
+		path    string
+		exclude uuid.UUID
+		want    []string
+	}{
+		{"content itself", "blog/hello-abc123", first.ID, nil},
+		{"another content", "/blog/hello-abc123/", second.ID, []string{"content:Hello"}},
+		{"section itself", "blog", blog.ID, nil},
+		{"another section", "blog", uuid.New(), []string{"section:Blog"}},
+		{"reserved path", "tags", tags.ID, []string{"reserved:tag pages"}},
+		{"free path", "news", uuid.Nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collisions, err := svc.CheckPathCollision(ctx, site.ID, tt.path, tt.exclude)
+			if err != nil {
+				t.Fatalf("CheckPathCollision() error = %v", err)
+			}
+			if got := kinds(collisions); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("collisions = %v, want %v", got, tt.want)
+			}
+			for _, c := range collisions {
+				if c.Kind != PathCollisionReserved && !strings.Contains(c.Link, "site_id="+site.ID.String()) {
+					t.Errorf("link %q should point to the conflicting item", c.Link)
+				}
+			}
+		})
 	}
+
+	t.Run("follows the permalink pattern", func(t *testing.T) {
+		setting := NewSetting(site.ID, "Permalink pattern", "/:slug/")
+		setting.RefKey = PermalinkRefKey
+		if err := svc.CreateSetting(ctx, setting); err != nil {
+			t.Fatal(err)
+		}
+		collisions, err := svc.CheckPathCollision(ctx, site.ID, "hello-abc123", uuid.Nil)
+		if err != nil || len(collisions) != 1 || collisions[0].Name != "Hello" {
+			t.Errorf("collisions = %v, %v; want content Hello", kinds(collisions), err)
+		}
+	})
 }
 
-func TestServiceUpdateLayoutCancelledContext(t *testing.T) {
+func TestServiceReservedSectionPaths(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Layout Context Test", "layout-context-test")
+	site := createTestSite(t, svc, "Reserved Site", "reserved-site")
 
-	layout := NewLayout(site.ID, "test-layout", "blog")
-	if err := svc.CreateLayout(ctx, layout); err != nil {
-		t.Fatalf("CreateLayout failed: %v", err)
+	for _, path := range []string{"tags", "/authors/", "page", "static/css"} {
+		if err := svc.CreateSection(ctx, NewSection(site.ID, "Section", "", path)); !errors.Is(err, ErrReservedPath) {
+			t.Errorf("CreateSection(%q) error = %v, want ErrReservedPath", path, err)
+		}
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	archive := NewSection(site.ID, "Archive", "", "archive")
+	if err := svc.CreateSection(ctx, archive); err != nil {
+		t.Fatalf("CreateSection(archive) with date archives off error = %v", err)
+	}
+	if _, reserved, err := svc.IsReservedPath(ctx, site.ID, "archive"); err != nil || reserved {
+		t.Errorf("IsReservedPath(archive) = %v, %v; want free while date archives are off", reserved, err)
+	}
 
-	layout.Name = "updated-layout"
-	err := svc.UpdateLayout(ctx2, layout)
-	if err == nil {
-		t.Error("UpdateLayout should fail with cancelled context")
+	setting := NewSetting(site.ID, "Archive pages", "true")
+	setting.RefKey = ArchiveRefKey
+	if err := svc.CreateSetting(ctx, setting); err != nil {
+		t.Fatal(err)
+	}
+	name, reserved, err := svc.IsReservedPath(ctx, site.ID, "/archive/2024")
+	if err != nil || !reserved || name != "date archives" {
+		t.Errorf("IsReservedPath(archive) = %q, %v, %v; want date archives", name, reserved, err)
+	}
+	archive.Name = "Old posts"
+	if err := svc.UpdateSection(ctx, archive); !errors.Is(err, ErrReservedPath) {
+		t.Errorf("UpdateSection() at archive with date archives on error = %v, want ErrReservedPath", err)
+	}
+	archive.Path = "old-posts"
+	if err := svc.UpdateSection(ctx, archive); err != nil {
+		t.Errorf("UpdateSection() to a free path error = %v", err)
 	}
 }
 
-func TestServiceUpdateSettingCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
+func TestServiceContentRevisions(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
 	defer cleanup()
-
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Setting Context Test", "setting-context-test")
-
-	setting := NewSetting(site.ID, "test-setting", "value")
-	if err := svc.CreateSetting(ctx, setting); err != nil {
-		t.Fatalf("CreateSetting failed: %v", err)
-	}
-
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	site := createTestSite(t, svc, "Revisions", "revisions")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
 
-	setting.Value = "updated"
-	err := svc.UpdateSetting(ctx2, setting)
-	if err == nil {
-		t.Error("UpdateSetting should fail with cancelled context")
+	content := NewContent(site.ID, section.ID, "Post", "First draft")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
 	}
-}
 
-func TestServiceUpdateImageCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+	update := func(body string) {
+		t.Helper()
+		content.Body = body
+		if err := svc.UpdateContent(ctx, content); err != nil {
+			t.Fatalf("UpdateContent() error = %v", err)
+		}
+	}
+	revisions := func() []*ContentRevision {
+		t.Helper()
+		revs, err := svc.ListContentRevisions(ctx, content.ID)
+		if err != nil {
+			t.Fatalf("ListContentRevisions() error = %v", err)
+		}
+		return revs
+	}
 
-	ctx := context.Background()
-	site := createTestSite(t, svc, "Image Context Test", "image-context-test")
+	update("Second draft")
+	update("Third draft") // within the window of the first revision
+	revs := revisions()
+	if len(revs) != 1 || revs[0].Body != "First draft" {
+		t.Fatalf("revisions = %+v, want one with the first draft", revs)
+	}
 
-	image := NewImage(site.ID, "test.jpg", "/images/test.jpg")
-	if err := svc.CreateImage(ctx, image); err != nil {
-		t.Fatalf("CreateImage failed: %v", err)
+	db.Exec("UPDATE content_revision SET created_at = ?", time.Now().Add(-time.Hour))
+	update("Third draft") // unchanged text saves nothing
+	update("Final")
+	revs = revisions()
+	if len(revs) != 2 || revs[0].Body != "Third draft" {
+		t.Fatalf("revisions = %d, latest %q, want 2 with the third draft", len(revs), revs[0].Body)
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	diff, err := svc.DiffRevisions(ctx, content.ID, revs[1].ID, uuid.Nil)
+	if err != nil {
+		t.Fatalf("DiffRevisions() error = %v", err)
+	}
+	if !diff.B.IsCurrent() || diff.B.Body != "Final" || diff.A.Body != "First draft" {
+		t.Errorf("DiffRevisions() sides = %q, %q", diff.A.Body, diff.B.Body)
+	}
 
-	image.FileName = "updated.jpg"
-	err := svc.UpdateImage(ctx2, image)
-	if err == nil {
-		t.Error("UpdateImage should fail with cancelled context")
+	other := NewContent(site.ID, section.ID, "Other", "")
+	svc.CreateContent(ctx, other)
+	if _, err := svc.DiffRevisions(ctx, other.ID, revs[0].ID, uuid.Nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DiffRevisions() with another content's revision error = %v, want ErrNotFound", err)
 	}
 }
 
-func TestServiceUpdateSectionCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
+func TestServiceEditLocks(t *testing.T) {
+	svc, db, cleanup := setupTestService(t)
 	defer cleanup()
-
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Section Context Test", "section-context-test")
+	site := createTestSite(t, svc, "Locks", "locks")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
+	content := NewContent(site.ID, section.ID, "Post", "Body")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	ana, bob := uuid.New(), uuid.New()
 
-	section := NewSection(site.ID, "test-section", "Test description", "test-path")
-	if err := svc.CreateSection(ctx, section); err != nil {
-		t.Fatalf("CreateSection failed: %v", err)
+	if _, err := svc.GetEditLock(ctx, content.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetEditLock() of unlocked content error = %v, want ErrNotFound", err)
+	}
+	first, err := svc.AcquireEditLock(ctx, content.ID, ana, "Ana")
+	if err != nil || !first.HeldBy(ana) {
+		t.Fatalf("AcquireEditLock() = %+v, %v, want Ana's lock", first, err)
+	}
+	refreshed, err := svc.AcquireEditLock(ctx, content.ID, ana, "Ana")
+	if err != nil || !refreshed.AcquiredAt.Equal(first.AcquiredAt) || refreshed.ExpiresAt.Before(first.ExpiresAt) {
+		t.Errorf("refreshing the lock = %+v, %v, want the same lock extended", refreshed, err)
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	lock, err := svc.AcquireEditLock(ctx, content.ID, bob, "Bob")
+	if !errors.Is(err, ErrContentLocked) || lock == nil || lock.UserName != "Ana" {
+		t.Fatalf("AcquireEditLock() by another user = %+v, %v, want Ana's lock with ErrContentLocked", lock, err)
+	}
+	if err := svc.ReleaseEditLock(ctx, content.ID, bob); err != nil {
+		t.Fatalf("ReleaseEditLock() error = %v", err)
+	}
+	if lock, err := svc.GetEditLock(ctx, content.ID); err != nil || !lock.HeldBy(ana) {
+		t.Errorf("releasing another user's lock should keep it, got %+v, %v", lock, err)
+	}
 
-	section.Name = "updated-section"
-	err := svc.UpdateSection(ctx2, section)
-	if err == nil {
-		t.Error("UpdateSection should fail with cancelled context")
+	// An expired lock goes to the next user who asks.
+	if _, err := db.Exec("UPDATE content_edit_lock SET expires_at = ?", time.Now().Add(-time.Minute).UTC()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetEditLock(ctx, content.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetEditLock() of an expired lock error = %v, want ErrNotFound", err)
+	}
+	if lock, err := svc.AcquireEditLock(ctx, content.ID, bob, "Bob"); err != nil || !lock.HeldBy(bob) || lock.UserName != "Bob" {
+		t.Fatalf("AcquireEditLock() after expiry = %+v, %v, want Bob's lock", lock, err)
+	}
+
+	if err := svc.BreakEditLock(ctx, content.ID); err != nil {
+		t.Fatalf("BreakEditLock() error = %v", err)
+	}
+	if _, err := svc.AcquireEditLock(ctx, content.ID, ana, "Ana"); err != nil {
+		t.Errorf("AcquireEditLock() after a break error = %v", err)
+	}
+	if err := svc.ReleaseEditLock(ctx, content.ID, ana); err != nil {
+		t.Fatalf("ReleaseEditLock() error = %v", err)
+	}
+	if _, err := svc.GetEditLock(ctx, content.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetEditLock() after release error = %v, want ErrNotFound", err)
 	}
 }
 
-func TestServiceUpdateMetaCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+func TestServiceContentKinds(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
 
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, &config.Config{}, newTestLogger())
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Meta Context Test", "meta-context-test")
+	site := createTestSite(t, svc, "Kinds Site", "kinds-site")
 
-	section := NewSection(site.ID, "section", "Description", "path")
-	if err := svc.CreateSection(ctx, section); err != nil {
-		t.Fatalf("CreateSection failed: %v", err)
+	kinds, err := svc.ListContentKinds(ctx, site.ID)
+	if err != nil {
+		t.Fatalf("ListContentKinds() error = %v", err)
+	}
+	if len(kinds) != len(DefaultContentKinds()) {
+		t.Errorf("a new site has %d kinds, want the %d built-in ones", len(kinds), len(DefaultContentKinds()))
 	}
 
-	content := NewContent(site.ID, section.ID, "Test Content", "Body")
-	if err := svc.CreateContent(ctx, content); err != nil {
-		t.Fatalf("CreateContent failed: %v", err)
+	note, err := svc.GetContentKind(ctx, site.ID, KindNote)
+	if err != nil {
+		t.Fatalf("GetContentKind() error = %v", err)
+	}
+	note.Permalink = "/notes/:slug/"
+	note.Feed = false
+	if err := svc.SaveContentKind(ctx, note); err != nil {
+		t.Fatalf("SaveContentKind() error = %v", err)
+	}
+	if err := svc.SaveContentKind(ctx, &ContentKind{SiteID: site.ID, Name: "recipe", Label: "Recipe", Listed: true}); err != nil {
+		t.Fatalf("SaveContentKind() error = %v", err)
+	}
+	if err := svc.SaveContentKind(ctx, &ContentKind{SiteID: site.ID, Name: "Bad Name"}); err == nil {
+		t.Error("SaveContentKind() should reject an invalid name")
 	}
 
-	meta := NewMeta(site.ID, content.ID)
-	if err := svc.CreateMeta(ctx, meta); err != nil {
-		t.Fatalf("CreateMeta failed: %v", err)
+	got, err := svc.GetContentKind(ctx, site.ID, KindNote)
+	if err != nil {
+		t.Fatalf("GetContentKind() error = %v", err)
+	}
+	if got.Permalink != "/notes/:slug/" || got.Feed || !got.BuiltIn || !got.Customized() {
+		t.Errorf("saved note kind = %+v", got)
+	}
+	if _, err := svc.GetContentKind(ctx, site.ID, "recipe"); err != nil {
+		t.Errorf("custom kind not found: %v", err)
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
+	post := NewContent(site.ID, section.ID, "A post", "Body")
+	svc.CreateContent(ctx, post)
+	noteContent := NewContent(site.ID, section.ID, "A note", "Body")
+	noteContent.Kind = KindNote
+	svc.CreateContent(ctx, noteContent)
 
-	meta.Description = "updated"
-	err := svc.UpdateMeta(ctx2, meta)
-	if err == nil {
-		t.Error("UpdateMeta should fail with cancelled context")
+	notes, err := svc.GetContentByKind(ctx, site.ID, KindNote)
+	if err != nil {
+		t.Fatalf("GetContentByKind() error = %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != noteContent.ID {
+		t.Errorf("GetContentByKind(note) = %d items, want the note", len(notes))
+	}
+	path, err := svc.ContentOutputPath(ctx, noteContent)
+	if err != nil {
+		t.Fatalf("ContentOutputPath() error = %v", err)
+	}
+	if path != "notes/"+noteContent.Slug() {
+		t.Errorf("ContentOutputPath() = %q, want the note kind's pattern", path)
+	}
+
+	if err := svc.DeleteContentKind(ctx, site.ID, KindNote); err != nil {
+		t.Fatalf("DeleteContentKind() error = %v", err)
+	}
+	if err := svc.DeleteContentKind(ctx, site.ID, "recipe"); err != nil {
+		t.Fatalf("DeleteContentKind() error = %v", err)
+	}
+	got, _ = svc.GetContentKind(ctx, site.ID, KindNote)
+	if got == nil || got.Customized() || !got.Feed {
+		t.Errorf("deleting a built-in kind should restore its defaults, got %+v", got)
+	}
+	if _, err := svc.GetContentKind(ctx, site.ID, "recipe"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted custom kind error = %v, want ErrNotFound", err)
 	}
 }
 
-func TestServiceDeleteContributorCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+func TestServiceTranslations(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
 
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, &config.Config{}, newTestLogger())
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Delete Contributor Context", "delete-contrib-context")
+	site := createTestSite(t, svc, "Translations Site", "translations-site")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
 
-	contributor := NewContributor(site.ID, "test-contrib", "Test", "Contrib")
-	contributor.CreatedBy = uuid.New()
-	contributor.UpdatedBy = contributor.CreatedBy
-	if err := svc.CreateContributor(ctx, contributor); err != nil {
-		t.Fatalf("CreateContributor failed: %v", err)
+	original := NewContent(site.ID, section.ID, "Hello", "Body")
+	if err := svc.CreateContent(ctx, original); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	translations, err := svc.GetTranslations(ctx, original.ID)
+	if err != nil || len(translations) != 0 {
+		t.Fatalf("GetTranslations() of content outside a group = %v, %v", translations, err)
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	spanish := NewContent(site.ID, section.ID, "Hola", "Cuerpo")
+	spanish.Lang = "ES"
+	spanish.TranslationGroup = original.ID.String()
+	if err := svc.CreateContent(ctx, spanish); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	if spanish.Lang != "es" {
+		t.Errorf("Lang = %q, want it normalized to es", spanish.Lang)
+	}
 
-	err := svc.DeleteContributor(ctx2, contributor.ID)
-	if err == nil {
-		t.Error("DeleteContributor should fail with cancelled context")
+	translations, err = svc.GetTranslations(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("GetTranslations() error = %v", err)
+	}
+	if len(translations) != 1 || translations[0].ID != spanish.ID {
+		t.Errorf("GetTranslations(original) = %d items, want the Spanish one", len(translations))
+	}
+	got, _ := svc.GetContent(ctx, original.ID)
+	if got.TranslationGroup != original.ID.String() {
+		t.Errorf("original group = %q, want it to join the group named after it", got.TranslationGroup)
+	}
+
+	// Content without a language is in the site language, en.
+	english := NewContent(site.ID, section.ID, "Hi", "Body")
+	english.TranslationGroup = original.ID.String()
+	if err := svc.CreateContent(ctx, english); !errors.Is(err, ErrTranslationTaken) {
+		t.Errorf("second en translation error = %v, want ErrTranslationTaken", err)
+	}
+
+	spanish.Lang = "es-MX"
+	if err := svc.UpdateContent(ctx, spanish); err != nil {
+		t.Errorf("UpdateContent() error = %v", err)
+	}
+	spanish.Lang = "not a language"
+	if err := svc.UpdateContent(ctx, spanish); !errors.Is(err, ErrInvalidLang) {
+		t.Errorf("invalid language error = %v, want ErrInvalidLang", err)
 	}
 }
 
-func TestServiceUnlinkImageFromContentCancelledContext(t *testing.T) {
+func TestServiceCreateTranslationDraft(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Unlink Image Context", "unlink-image-context")
+	site := createTestSite(t, svc, "Languages", "languages")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
 
-	section := NewSection(site.ID, "section", "Description", "path")
-	if err := svc.CreateSection(ctx, section); err != nil {
-		t.Fatalf("CreateSection failed: %v", err)
+	source := NewContent(site.ID, section.ID, "Hello", "Some **text**.")
+	source.Summary = "A greeting"
+	source.Series = "Basics"
+	source.SeriesOrder = 2
+	source.AuthorUsername = "writer"
+	source.Draft = false
+	if err := svc.CreateContent(ctx, source); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
+	}
+	meta := NewMeta(site.ID, source.ID)
+	meta.Description = "About greetings"
+	meta.TableOfContents = true
+	if err := svc.CreateMeta(ctx, meta); err != nil {
+		t.Fatalf("CreateMeta() error = %v", err)
+	}
+	if err := svc.AddTagToContent(ctx, source.ID, "Intro", site.ID); err != nil {
+		t.Fatalf("AddTagToContent() error = %v", err)
+	}
+	image := NewImage(site.ID, "hello.jpg", "hello.jpg")
+	if err := svc.CreateImage(ctx, image); err != nil {
+		t.Fatalf("CreateImage() error = %v", err)
+	}
+	if err := svc.LinkImageToContent(ctx, source.ID, image.ID, true); err != nil {
+		t.Fatalf("LinkImageToContent() error = %v", err)
 	}
 
-	content := NewContent(site.ID, section.ID, "Test Content", "Body")
-	if err := svc.CreateContent(ctx, content); err != nil {
-		t.Fatalf("CreateContent failed: %v", err)
+	draft, err := svc.CreateTranslationDraft(ctx, source.ID, "es_es")
+	if err != nil {
+		t.Fatalf("CreateTranslationDraft() error = %v", err)
 	}
 
-	image := NewImage(site.ID, "test.jpg", "/images/test.jpg")
-	if err := svc.CreateImage(ctx, image); err != nil {
-		t.Fatalf("CreateImage failed: %v", err)
+	got, err := svc.GetContent(ctx, draft.ID)
+	if err != nil {
+		t.Fatalf("GetContent() error = %v", err)
+	}
+	if !got.Draft || got.Lang != "es-ES" || got.SectionID != section.ID {
+		t.Errorf("draft = %v, lang %q, section %v; want a draft in es-ES in the source's section", got.Draft, got.Lang, got.SectionID)
+	}
+	if got.Heading != source.Heading || got.Summary != source.Summary || got.Body != source.Body {
+		t.Errorf("draft text = %q / %q / %q, want the source's", got.Heading, got.Summary, got.Body)
+	}
+	if got.Series != "Basics" || got.SeriesOrder != 2 || got.AuthorUsername != "writer" {
+		t.Errorf("series %q #%d, author %q not copied", got.Series, got.SeriesOrder, got.AuthorUsername)
+	}
+	images, err := svc.GetContentImagesWithDetails(ctx, draft.ID)
+	if err != nil || len(images) != 1 || images[0].ID != image.ID || !images[0].IsHeader {
+		t.Errorf("draft images = %v, %v; want the source's header image", images, err)
 	}
 
-	if err := svc.LinkImageToContent(ctx, content.ID, image.ID, false); err != nil {
-		t.Fatalf("LinkImageToContent failed: %v", err)
+	gotMeta, err := svc.GetMetaByContentID(ctx, draft.ID)
+	if err != nil || gotMeta == nil {
+		t.Fatalf("GetMetaByContentID() = %v, %v", gotMeta, err)
+	}
+	if gotMeta.ID == meta.ID || gotMeta.Description != "About greetings" || !gotMeta.TableOfContents {
+		t.Errorf("meta = %+v, want a copy of the source's", gotMeta)
+	}
+	tags, _ := svc.GetTagsForContent(ctx, draft.ID)
+	if len(tags) != 1 || tags[0].Name != "Intro" {
+		t.Errorf("tags = %v, want [Intro]", tags)
 	}
 
-	// Get the content image ID
-	images, err := svc.GetContentImagesWithDetails(ctx, content.ID)
-	if err != nil || len(images) == 0 {
-		t.Fatalf("GetContentImagesWithDetails failed: %v", err)
+	// Both ends of the group see each other.
+	if got.TranslationGroup != source.ID.String() {
+		t.Errorf("draft group = %q, want %s", got.TranslationGroup, source.ID)
+	}
+	translations, _ := svc.GetTranslations(ctx, source.ID)
+	if len(translations) != 1 || translations[0].ID != draft.ID {
+		t.Errorf("GetTranslations(source) = %d items, want the draft", len(translations))
+	}
+	translations, _ = svc.GetTranslations(ctx, draft.ID)
+	if len(translations) != 1 || translations[0].ID != source.ID {
+		t.Errorf("GetTranslations(draft) = %d items, want the source", len(translations))
 	}
-	contentImageID := images[0].ContentImageID
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	// A translation of the draft stays in the source's group.
+	french, err := svc.CreateTranslationDraft(ctx, draft.ID, "fr")
+	if err != nil {
+		t.Fatalf("CreateTranslationDraft(draft) error = %v", err)
+	}
+	if french.TranslationGroup != source.ID.String() {
+		t.Errorf("French group = %q, want %s", french.TranslationGroup, source.ID)
+	}
 
-	err = svc.UnlinkImageFromContent(ctx2, contentImageID)
-	if err == nil {
-		t.Error("UnlinkImageFromContent should fail with cancelled context")
+	for lang, want := range map[string]error{"es-ES": ErrTranslationTaken, "en": ErrTranslationTaken, "": ErrInvalidLang, "not a language": ErrInvalidLang} {
+		if _, err := svc.CreateTranslationDraft(ctx, source.ID, lang); !errors.Is(err, want) {
+			t.Errorf("CreateTranslationDraft(%q) error = %v, want %v", lang, err, want)
+		}
+	}
+	if _, err := svc.CreateTranslationDraft(ctx, uuid.New(), "de"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CreateTranslationDraft(missing) error = %v, want ErrNotFound", err)
 	}
 }
 
-func TestServiceUnlinkImageFromSectionCancelledContext(t *testing.T) {
-	svc, _, cleanup := setupTestService(t)
-	defer cleanup()
+func TestServiceDeriveSummary(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
 
+	svc := NewService(&testutil.TestDBProvider{DB: db}, nil, &config.Config{}, newTestLogger())
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Unlink Section Image Context", "unlink-section-image")
+	site := createTestSite(t, svc, "Summary Site", "summary-site")
+	section := NewSection(site.ID, "Blog", "", "/blog")
+	svc.CreateSection(ctx, section)
 
-	section := NewSection(site.ID, "section", "Description", "path")
-	if err := svc.CreateSection(ctx, section); err != nil {
-		t.Fatalf("CreateSection failed: %v", err)
+	content := NewContent(site.ID, section.ID, "Hello", "## Intro\n\nA **short** body about summaries.")
+	if err := svc.CreateContent(ctx, content); err != nil {
+		t.Fatalf("CreateContent() error = %v", err)
 	}
 
-	image := NewImage(site.ID, "test.jpg", "/images/test.jpg")
-	if err := svc.CreateImage(ctx, image); err != nil {
-		t.Fatalf("CreateImage failed: %v", err)
+	var sent string
+	opts := SummaryOptions{Mode: SummaryLLM, Words: 3, Summarize: func(_ context.Context, text string, words int) (string, error) {
+		sent = text
+		return "A body about writing summaries.", nil
+	}}
+	got, err := svc.DeriveSummary(ctx, content, opts)
+	if err != nil {
+		t.Fatalf("DeriveSummary() error = %v", err)
+	}
+	if sent != "Intro A short body about summaries." {
+		t.Errorf("Summarize got %q, want the body as plain text", sent)
+	}
+	if got != "A body about…" {
+		t.Errorf("DeriveSummary() = %q, want the written summary cut to 3 words", got)
 	}
 
-	if err := svc.LinkImageToSection(ctx, section.ID, image.ID, false); err != nil {
-		t.Fatalf("LinkImageToSection failed: %v", err)
+	meta := NewMeta(site.ID, content.ID)
+	meta.Excerpt = "The excerpt"
+	if err := svc.CreateMeta(ctx, meta); err != nil {
+		t.Fatalf("CreateMeta() error = %v", err)
+	}
+	sent = ""
+	got, err = svc.DeriveSummary(ctx, content, opts)
+	if err != nil || got != "The excerpt" || sent != "" {
+		t.Errorf("DeriveSummary() = %q, %v, want the excerpt without summarizing", got, err)
 	}
 
-	// Get the section image ID
-	images, err := svc.GetSectionImagesWithDetails(ctx, section.ID)
-	if err != nil || len(images) == 0 {
-		t.Fatalf("GetSectionImagesWithDetails failed: %v", err)
+	content.Summary = "Own summary"
+	if got, _ := svc.DeriveSummary(ctx, content, opts); got != "Own summary" {
+		t.Errorf("DeriveSummary() = %q, want the content's own summary", got)
 	}
-	sectionImageID := images[0].SectionImageID
+}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+func TestServiceThemeSettingValidation(t *testing.T) {
+	db, err := testutil.NewTestDB()
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
 
-	err = svc.UnlinkImageFromSection(ctx2, sectionImageID)
-	if err == nil {
-		t.Error("UnlinkImageFromSection should fail with cancelled context")
+	workspace := NewWorkspace(t.TempDir())
+	svc := NewService(&testutil.TestDBProvider{DB: db}, NewHTMLGenerator(workspace, embed.FS{}), &config.Config{}, newTestLogger())
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Themed", "themed")
+
+	setting := NewSetting(site.ID, "Theme", "ghost")
+	setting.RefKey = ThemeRefKey
+	if err := svc.CreateSetting(ctx, setting); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("CreateSetting() with an unknown theme error = %v, want ErrThemeNotFound", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(workspace.GetThemesPath(site.Slug), "ghost"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateSetting(ctx, setting); err != nil {
+		t.Fatalf("CreateSetting() with an uploaded theme error = %v", err)
+	}
+
+	setting.Value = "gone"
+	if err := svc.UpdateSetting(ctx, setting); !errors.Is(err, ErrThemeNotFound) {
+		t.Errorf("UpdateSetting() with an unknown theme error = %v, want ErrThemeNotFound", err)
 	}
 }
 
-func TestServiceAddTagToContentCancelledContext(t *testing.T) {
+func TestServiceQuickImport(t *testing.T) {
 	svc, _, cleanup := setupTestService(t)
 	defer cleanup()
 
 	ctx := context.Background()
-	site := createTestSite(t, svc, "Add Tag Context Test", "add-tag-context")
+	site := createTestSite(t, svc, "Quick Import Site", "quick-import-site")
+	blog := NewSection(site.ID, "Blog", "", "blog")
+	svc.CreateSection(ctx, blog)
+	notes := NewSection(site.ID, "Notes", "", "notes")
+	svc.CreateSection(ctx, notes)
+	if err := svc.CreateTag(ctx, NewTag(site.ID, "Go")); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
 
-	section := NewSection(site.ID, "section", "Description", "path")
-	if err := svc.CreateSection(ctx, section); err != nil {
-		t.Fatalf("CreateSection failed: %v", err)
+	content, err := svc.QuickImport(ctx, site.ID, blog.ID, uuid.Nil, "admin", "---\ntags: [Go, reading]\nsection: notes\ndraft: false\n---\n\n# Pasted Note\n\nSome text.")
+	if err != nil {
+		t.Fatalf("QuickImport() error = %v", err)
 	}
 
-	content := NewContent(site.ID, section.ID, "Test Content", "Body")
-	if err := svc.CreateContent(ctx, content); err != nil {
-		t.Fatalf("CreateContent failed: %v", err)
+	got, err := svc.GetContent(ctx, content.ID)
+	if err != nil {
+		t.Fatalf("GetContent() error = %v", err)
+	}
+	if got.Heading != "Pasted Note" || got.Body != "Some text." {
+		t.Errorf("content = %q / %q, want the H1 as title and the rest as body", got.Heading, got.Body)
+	}
+	if !got.Draft {
+		t.Error("quick import should always create a draft")
+	}
+	if got.SectionID != notes.ID {
+		t.Errorf("section = %v, want the frontmatter section %v", got.SectionID, notes.ID)
+	}
+	if got.AuthorUsername != "admin" {
+		t.Errorf("author = %q, want admin", got.AuthorUsername)
 	}
 
-	ctx2, cancel := context.WithCancel(context.Background())
-	cancel()
+	tags, err := svc.GetTagsForContent(ctx, content.ID)
+	if err != nil {
+		t.Fatalf("GetTagsForContent() error = %v", err)
+	}
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "Go,reading" {
+		t.Errorf("tags = %v, want [Go reading]", names)
+	}
 
-	err := svc.AddTagToContent(ctx2, content.ID, "new-tag", site.ID)
-	if err == nil {
-		t.Error("AddTagToContent should fail with cancelled context")
+	content, err = svc.QuickImport(ctx, site.ID, blog.ID, uuid.Nil, "admin", "Just a thought.")
+	if err != nil {
+		t.Fatalf("QuickImport() error = %v", err)
+	}
+	if content.Heading != "Untitled" || content.SectionID != blog.ID {
+		t.Errorf("plain paste = %q in %v, want Untitled in the given section", content.Heading, content.SectionID)
 	}
 }
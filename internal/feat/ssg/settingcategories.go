@@ -0,0 +1,111 @@
+package ssg
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// settingCategoryOrder is the order categories are listed in on the settings
+// page. display and git are the names sites created before appearance and
+// publishing still use. Other categories follow by name, and settings without
+// one come last.
+var settingCategoryOrder = []string{
+	"site",
+	"seo",
+	"appearance",
+	"display",
+	"feeds",
+	"images",
+	"editor",
+	"publishing",
+	"git",
+	"scheduling",
+	"analytics",
+	"search",
+	"accessibility",
+	"api",
+	"forms",
+	"features",
+}
+
+// settingCategoryTitles holds the titles that are not just the capitalized name.
+var settingCategoryTitles = map[string]string{
+	"seo": "SEO",
+	"api": "API",
+}
+
+// otherSettings is the category of settings created without one.
+const otherSettings = "other"
+
+// SettingCategory is a group of the settings list.
+type SettingCategory struct {
+	Name     string
+	Title    string
+	Settings []*Setting // by position, then name
+}
+
+// groupSettings groups settings by category, known categories first in the
+// settings page order, and orders each group by position and name.
+func groupSettings(settings []*Setting) []*SettingCategory {
+	byName := make(map[string]*SettingCategory)
+	var categories []*SettingCategory
+	for _, setting := range settings {
+		name := settingCategoryName(setting.Category)
+		category := byName[name]
+		if category == nil {
+			category = &SettingCategory{Name: name, Title: settingCategoryTitle(name)}
+			byName[name] = category
+			categories = append(categories, category)
+		}
+		category.Settings = append(category.Settings, setting)
+	}
+
+	rank := func(name string) int {
+		for i, known := range settingCategoryOrder {
+			if name == known {
+				return i
+			}
+		}
+		if name == otherSettings {
+			return len(settingCategoryOrder) + 1
+		}
+		return len(settingCategoryOrder)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		ri, rj := rank(categories[i].Name), rank(categories[j].Name)
+		if ri != rj {
+			return ri < rj
+		}
+		return categories[i].Name < categories[j].Name
+	})
+
+	for _, category := range categories {
+		sort.SliceStable(category.Settings, func(i, j int) bool {
+			a, b := category.Settings[i], category.Settings[j]
+			if a.Position != b.Position {
+				return a.Position < b.Position
+			}
+			return a.Name < b.Name
+		})
+	}
+	return categories
+}
+
+// settingCategoryName returns the name settings of category are grouped under.
+func settingCategoryName(category string) string {
+	name := strings.ToLower(strings.TrimSpace(category))
+	if name == "" {
+		return otherSettings
+	}
+	return name
+}
+
+func settingCategoryTitle(name string) string {
+	if title, ok := settingCategoryTitles[name]; ok {
+		return title
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
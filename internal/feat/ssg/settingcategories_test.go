@@ -0,0 +1,46 @@
+package ssg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupSettings(t *testing.T) {
+	setting := func(name, category string, position int) *Setting {
+		return &Setting{Name: name, Category: category, Position: position}
+	}
+	settings := []*Setting{
+		setting("Custom", "Newsletter", 1),
+		setting("Loose", "", 1),
+		setting("Tag feeds", "feeds", 3),
+		setting("Theme", "appearance", 22),
+		setting("Feed formats", "feeds", 1),
+		setting("Base URL", "site", 4),
+		setting("Robots", "seo", 1),
+		setting("Description", "site", 1),
+		setting("Beta", "appearance", 5),
+		setting("Alpha", "appearance", 5),
+		setting("Extra", "extras", 2),
+	}
+
+	var got []string
+	for _, c := range groupSettings(settings) {
+		var names []string
+		for _, s := range c.Settings {
+			names = append(names, s.Name)
+		}
+		got = append(got, c.Title+": "+strings.Join(names, ","))
+	}
+	want := []string{
+		"Site: Description,Base URL",
+		"SEO: Robots",
+		"Appearance: Alpha,Beta,Theme",
+		"Feeds: Feed formats,Tag feeds",
+		"Extras: Extra",
+		"Newsletter: Custom",
+		"Other: Loose",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("groupSettings() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,232 @@
+package ssg
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Site-wide HTML snippets, injected as written into every generated page.
+// Only admins can edit settings, and nothing sanitizes these values.
+const (
+	// HeadHTMLRefKey is inserted right before </head>, e.g. analytics or
+	// verification tags and font links.
+	HeadHTMLRefKey = "ssg.head.html"
+	// FooterHTMLRefKey is inserted right before </body>.
+	FooterHTMLRefKey = "ssg.footer.html"
+)
+
+var snippetTagRe = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)((?:"[^"]*"|'[^']*'|[^'">])*)>`)
+
+var (
+	// voidElements never have an end tag.
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+		"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+	}
+	// rawTextElements hold text up to their end tag, markup included.
+	rawTextElements = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+	// optionalEndElements may be left open, as browsers close them implicitly.
+	optionalEndElements = map[string]bool{
+		"p": true, "li": true, "dt": true, "dd": true, "option": true, "optgroup": true,
+		"tr": true, "td": true, "th": true, "thead": true, "tbody": true, "tfoot": true,
+	}
+)
+
+// ValidateHTMLSnippet checks that a snippet is well-formed enough not to break
+// the page it is injected into: every tag ends with >, elements are closed in
+// order, comments and scripts are terminated, and there are no html, head or
+// body tags. It does not check what the snippet does.
+func ValidateHTMLSnippet(snippet string) error {
+	var open []string
+	rest := snippet
+	for {
+		i := strings.IndexByte(rest, '<')
+		if i < 0 {
+			break
+		}
+		rest = rest[i:]
+
+		if strings.HasPrefix(rest, "<!--") {
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return fmt.Errorf("HTML snippet has an unclosed comment")
+			}
+			rest = rest[end+len("-->"):]
+			continue
+		}
+
+		m := snippetTagRe.FindStringSubmatch(rest)
+		if m == nil {
+			if len(rest) > 1 && (rest[1] == '/' || isASCIILetter(rest[1])) {
+				return fmt.Errorf("HTML snippet has a malformed tag near %q", excerpt(rest, 20))
+			}
+			rest = rest[1:] // a lone < in text
+			continue
+		}
+		rest = rest[len(m[0]):]
+
+		closing := m[1] == "/"
+		name := strings.ToLower(m[2])
+		switch name {
+		case "html", "head", "body":
+			return fmt.Errorf("HTML snippet must not contain <%s> tags", name)
+		}
+
+		switch {
+		case closing:
+			for len(open) > 0 && open[len(open)-1] != name && optionalEndElements[open[len(open)-1]] {
+				open = open[:len(open)-1]
+			}
+			if len(open) == 0 || open[len(open)-1] != name {
+				return fmt.Errorf("HTML snippet has an unexpected </%s>", name)
+			}
+			open = open[:len(open)-1]
+		case voidElements[name] || strings.HasSuffix(strings.TrimSpace(m[3]), "/"):
+		case rawTextElements[name]:
+			end := strings.Index(strings.ToLower(rest), "</"+name)
+			if end < 0 {
+				return fmt.Errorf("HTML snippet has an unclosed <%s>", name)
+			}
+			rest = rest[end:]
+			open = append(open, name)
+		default:
+			open = append(open, name)
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		if !optionalEndElements[open[i]] {
+			return fmt.Errorf("HTML snippet has an unclosed <%s>", open[i])
+		}
+	}
+	return nil
+}
+
+func isASCIILetter(b byte) bool {
+	return ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func excerpt(s string, n int) string {
+	if len(s) > n {
+		return s[:n] + "..."
+	}
+	return s
+}
+
+// executeLayout renders a page through layout.html into w with the site's
+// head and footer snippets in place. The page streams through as it renders.
+func executeLayout(w io.Writer, tmpl *template.Template, data SSGPageData) error {
+	sw := newSnippetWriter(w, data.Params)
+	if err := tmpl.ExecuteTemplate(sw, "layout.html", data); err != nil {
+		return err
+	}
+	return sw.Close()
+}
+
+const (
+	headEndTag = "</head>"
+	bodyEndTag = "</body>"
+)
+
+// snippetWriter inserts the site's head snippet before the first </head> and
+// its footer snippet before the last </body>, or at the end of a page without
+// one. It only holds back what may be the start of a split end tag, and the
+// text after the last </body> seen until another one or the end of the page.
+type snippetWriter struct {
+	w       io.Writer
+	head    []byte // nil once written
+	footer  []byte
+	pending []byte
+	err     error
+}
+
+func newSnippetWriter(w io.Writer, params map[string]string) *snippetWriter {
+	sw := &snippetWriter{w: w}
+	if head := strings.TrimSpace(params[HeadHTMLRefKey]); head != "" {
+		sw.head = []byte(head + "\n")
+	}
+	if footer := strings.TrimSpace(params[FooterHTMLRefKey]); footer != "" {
+		sw.footer = []byte(footer + "\n")
+	}
+	return sw
+}
+
+func (sw *snippetWriter) Write(p []byte) (int, error) {
+	if sw.err != nil {
+		return 0, sw.err
+	}
+	if sw.head == nil && sw.footer == nil {
+		return sw.w.Write(p)
+	}
+
+	sw.pending = append(sw.pending, p...)
+	if sw.head != nil {
+		i := findTag(sw.pending, headEndTag, false)
+		if i < 0 {
+			sw.flush(len(sw.pending) - len(headEndTag) + 1)
+			return len(p), sw.err
+		}
+		sw.flush(i)
+		sw.emit(sw.head)
+		sw.head = nil
+	}
+	if sw.footer != nil {
+		if i := findTag(sw.pending, bodyEndTag, true); i >= 0 {
+			sw.flush(i)
+		} else {
+			sw.flush(len(sw.pending) - len(bodyEndTag) + 1)
+		}
+	} else {
+		sw.flush(len(sw.pending))
+	}
+	return len(p), sw.err
+}
+
+// Close writes what is held back. It does not close the underlying writer.
+func (sw *snippetWriter) Close() error {
+	if sw.footer != nil && findTag(sw.pending, bodyEndTag, false) == 0 {
+		sw.emit(sw.footer)
+		sw.flush(len(sw.pending))
+	} else {
+		sw.flush(len(sw.pending))
+		if sw.footer != nil {
+			sw.emit(sw.footer)
+		}
+	}
+	return sw.err
+}
+
+// flush writes the first n pending bytes.
+func (sw *snippetWriter) flush(n int) {
+	if n <= 0 {
+		return
+	}
+	sw.emit(sw.pending[:n])
+	sw.pending = append(sw.pending[:0], sw.pending[n:]...)
+}
+
+func (sw *snippetWriter) emit(b []byte) {
+	if sw.err == nil {
+		_, sw.err = sw.w.Write(b)
+	}
+}
+
+// findTag returns the index of the first, or last, case-insensitive match of
+// tag in b, or -1. Unlike indexFold it never lowercases b, so the index holds
+// for any text.
+func findTag(b []byte, tag string, last bool) int {
+	found := -1
+	for i := 0; i+len(tag) <= len(b); i++ {
+		if bytes.EqualFold(b[i:i+len(tag)], []byte(tag)) {
+			if !last {
+				return i
+			}
+			found = i
+		}
+	}
+	return found
+}
@@ -0,0 +1,147 @@
+package ssg
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateHTMLSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"meta and link", `<meta name="google-site-verification" content="abc"><link rel="preconnect" href="https://fonts.example.com">`, false},
+		{"script", `<script async src="https://stats.example.com/s.js" data-site="x"></script>`, false},
+		{"script with markup inside", `<script>if (a < b) { document.write("<div>"); }</script>`, false},
+		{"nested", `<div class="chat"><p>Hi <strong>there</strong></p></div>`, false},
+		{"optional end tags", `<ul><li>one<li>two</ul><p>text`, false},
+		{"self-closing", `<svg><path d="M0 0"/></svg>`, false},
+		{"comment", `<!-- <div> --><br>`, false},
+		{"attribute with >", `<div data-x="a>b"></div>`, false},
+		{"lone less-than", `<p>1 < 2</p>`, false},
+		{"unclosed div", `<div class="chat">`, true},
+		{"misnested", `<div><span></div></span>`, true},
+		{"stray end tag", `</div>`, true},
+		{"unclosed script", `<script>track()`, true},
+		{"unclosed comment", `<!-- note`, true},
+		{"unterminated tag", `<meta name="x"`, true},
+		{"body tag", `<body><p>x</p></body>`, true},
+		{"head end tag", `</head><script></script>`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHTMLSnippet(tt.snippet)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHTMLSnippet(%q) error = %v, wantErr %v", tt.snippet, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// writeSnippets streams page through a snippetWriter in chunks of size n.
+func writeSnippets(t *testing.T, page string, params map[string]string, n int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	sw := newSnippetWriter(&buf, params)
+	for i := 0; i < len(page); i += n {
+		end := min(i+n, len(page))
+		if _, err := sw.Write([]byte(page[i:end])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestSnippetWriter(t *testing.T) {
+	params := map[string]string{
+		HeadHTMLRefKey:   `<meta name="a">`,
+		FooterHTMLRefKey: `<script src="/w.js"></script>`,
+	}
+	tests := []struct {
+		name   string
+		page   string
+		params map[string]string
+		want   string
+	}{
+		{
+			name:   "both",
+			page:   "<html><HEAD><title>T</title></HEAD><body><p>x</p></body></html>",
+			params: params,
+			want:   "<html><HEAD><title>T</title><meta name=\"a\">\n</HEAD><body><p>x</p><script src=\"/w.js\"></script>\n</body></html>",
+		},
+		{
+			name:   "last body end tag",
+			page:   "<head></head><body><pre></body></pre></body>\n</html>\n",
+			params: params,
+			want:   "<head><meta name=\"a\">\n</head><body><pre></body></pre><script src=\"/w.js\"></script>\n</body>\n</html>\n",
+		},
+		{
+			name:   "no body end tag",
+			page:   "<p>fragment</p>",
+			params: map[string]string{FooterHTMLRefKey: "<hr>"},
+			want:   "<p>fragment</p><hr>\n",
+		},
+		{
+			name:   "blank snippets",
+			page:   "<head></head><body></body>",
+			params: map[string]string{HeadHTMLRefKey: "  "},
+			want:   "<head></head><body></body>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// One byte at a time splits every end tag across writes.
+			for _, n := range []int{1, 5, len(tt.page)} {
+				if got := writeSnippets(t, tt.page, tt.params, n); got != tt.want {
+					t.Errorf("in chunks of %d = %q, want %q", n, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderPagesInjectSnippets(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	params := map[string]string{
+		HeadHTMLRefKey:   `<meta name="site-verification" content="abc123">`,
+		FooterHTMLRefKey: `<script src="https://chat.example.com/widget.js"></script>`,
+	}
+	tmpl, err := g.parseTemplates(g.siteTheme(site.Slug, params))
+	if err != nil {
+		t.Fatalf("parseTemplates() error = %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	tag := &Tag{ID: uuid.New(), Name: "Go", Slug: "go"}
+	contents := []*Content{{ID: uuid.New(), ShortID: "p0000001", Heading: "Post", PublishedAt: &past, Tags: []*Tag{tag}}}
+	if _, _, err := g.renderTagPages(tmpl, nil, nil, site, contents, nil, nil, params); err != nil {
+		t.Fatalf("renderTagPages() error = %v", err)
+	}
+	data, err := os.ReadFile(g.workspace.GetPaginationHTMLPath(site.Slug, "tags/go", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := strings.ToLower(string(data))
+
+	head := strings.Index(page, params[HeadHTMLRefKey])
+	if head < 0 || head > strings.Index(page, "</head>") {
+		t.Errorf("head snippet not inside <head>:\n%s", data)
+	}
+	footer := strings.Index(page, params[FooterHTMLRefKey])
+	if footer < 0 || strings.LastIndex(page, "</body>") != footer+len(params[FooterHTMLRefKey])+1 {
+		t.Errorf("footer snippet not right before </body>:\n%s", data)
+	}
+}
@@ -0,0 +1,25 @@
+package ssg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cliossg/clio/internal/feat/profile"
+)
+
+func TestSocialLinkIcon(t *testing.T) {
+	for _, p := range profile.SocialPlatforms {
+		if socialIcons[p.Icon] == "" {
+			t.Errorf("platform %s uses unknown icon %q", p.ID, p.Icon)
+		}
+	}
+
+	github := SocialLink{Platform: "github", URL: "https://github.com/jdoe"}
+	if github.Name() != "GitHub" || !strings.Contains(string(github.Icon()), socialIcons["github"]) {
+		t.Errorf("github link = %q, %s", github.Name(), github.Icon())
+	}
+	other := SocialLink{Platform: "myspace", URL: "https://myspace.com/jdoe"}
+	if other.Name() != "myspace" || !strings.Contains(string(other.Icon()), socialIcons["globe"]) {
+		t.Errorf("unknown platform link = %q, %s", other.Name(), other.Icon())
+	}
+}
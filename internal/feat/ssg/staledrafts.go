@@ -0,0 +1,161 @@
+package ssg
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/logger"
+	"github.com/google/uuid"
+)
+
+// StaleDraftDaysRefKey sets after how many days without an edit a draft is
+// stale. Stale drafts are listed on the site dashboard and logged once a day
+// as a reminder; they are never deleted. 0 turns the reminders off.
+const StaleDraftDaysRefKey = "ssg.drafts.stale_days"
+
+const (
+	// DefaultStaleDraftDays applies to sites without ssg.drafts.stale_days.
+	DefaultStaleDraftDays = 30
+	// staleDraftCheckInterval is how often DraftReminder checks every site.
+	staleDraftCheckInterval = 24 * time.Hour
+)
+
+// StaleDrafts are the drafts of a site untouched for more than Days days,
+// least recently updated first.
+type StaleDrafts struct {
+	Days     int
+	Contents []*Content
+}
+
+// staleDraftDays returns the ssg.drafts.stale_days setting of a site, or
+// DefaultStaleDraftDays when it is missing or invalid.
+func staleDraftDays(ctx context.Context, svc Service, siteID uuid.UUID) int {
+	setting, err := svc.GetSettingByRefKey(ctx, siteID, StaleDraftDaysRefKey)
+	if err != nil || setting == nil {
+		return DefaultStaleDraftDays
+	}
+	days, err := strconv.Atoi(strings.TrimSpace(setting.Value))
+	if err != nil || days < 0 {
+		return DefaultStaleDraftDays
+	}
+	return days
+}
+
+// findStaleDrafts returns the stale drafts of a site, or nil when its
+// reminders are off.
+func findStaleDrafts(ctx context.Context, svc Service, siteID uuid.UUID) (*StaleDrafts, error) {
+	days := staleDraftDays(ctx, svc, siteID)
+	if days == 0 {
+		return nil, nil
+	}
+	contents, err := svc.FindStaleDrafts(ctx, siteID, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return &StaleDrafts{Days: days, Contents: contents}, nil
+}
+
+// DraftReminder looks for stale drafts on every site once when started and
+// then daily, and logs a reminder for each site that has some.
+type DraftReminder struct {
+	service  Service
+	interval time.Duration
+	log      logger.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewDraftReminder(service Service, log logger.Logger) *DraftReminder {
+	return &DraftReminder{service: service, interval: staleDraftCheckInterval, log: log}
+}
+
+func (d *DraftReminder) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cancel != nil {
+		return nil
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.run(runCtx, d.done)
+	return nil
+}
+
+// Stop cancels a running check and waits for it to return, or for ctx to end.
+func (d *DraftReminder) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel, d.done = nil, nil
+	d.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (d *DraftReminder) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	d.Check(ctx)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Check(ctx)
+		}
+	}
+}
+
+// Check finds the stale drafts of every site, logs a reminder for each site
+// that has some and returns them by site.
+func (d *DraftReminder) Check(ctx context.Context) map[uuid.UUID]*StaleDrafts {
+	sites, err := d.service.ListSites(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.log.Errorf("Draft reminder: cannot list sites: %v", err)
+		}
+		return nil
+	}
+
+	found := make(map[uuid.UUID]*StaleDrafts)
+	for _, site := range sites {
+		if ctx.Err() != nil {
+			break
+		}
+		stale, err := findStaleDrafts(ctx, d.service, site.ID)
+		if err != nil {
+			d.log.Errorf("Draft reminder: site %s: %v", site.Slug, err)
+			continue
+		}
+		if stale == nil || len(stale.Contents) == 0 {
+			continue
+		}
+		found[site.ID] = stale
+
+		headings := make([]string, len(stale.Contents))
+		for i, c := range stale.Contents {
+			headings[i] = strconv.Quote(c.Heading)
+		}
+		d.log.Infof("Draft reminder: site %s has %d drafts untouched for over %d days: %s",
+			site.Slug, len(stale.Contents), stale.Days, strings.Join(headings, ", "))
+	}
+	return found
+}
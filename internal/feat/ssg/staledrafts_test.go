@@ -0,0 +1,46 @@
+package ssg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDraftReminderCheck(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	withDrafts := createTestSite(t, svc, "Drafts", "drafts")
+	remindersOff := createTestSite(t, svc, "No Reminders", "no-reminders")
+
+	for _, site := range []*Site{withDrafts, remindersOff} {
+		draft := NewContent(site.ID, uuid.Nil, "Forgotten draft", "Body")
+		draft.Draft = true
+		draft.UpdatedAt = time.Now().Add(-60 * 24 * time.Hour)
+		if err := svc.CreateContent(ctx, draft); err != nil {
+			t.Fatal(err)
+		}
+	}
+	off := NewSetting(remindersOff.ID, "Stale draft days", "0")
+	off.RefKey = StaleDraftDaysRefKey
+	if err := svc.CreateSetting(ctx, off); err != nil {
+		t.Fatal(err)
+	}
+
+	found := NewDraftReminder(svc, newTestLogger()).Check(ctx)
+	if len(found) != 1 {
+		t.Fatalf("Check() found stale drafts on %d sites, want 1", len(found))
+	}
+	stale := found[withDrafts.ID]
+	if stale == nil || stale.Days != DefaultStaleDraftDays || len(stale.Contents) != 1 {
+		t.Errorf("Check() = %+v for the site with a draft, want one draft at the default threshold", stale)
+	}
+
+	drafts, err := svc.GetAllContentWithMeta(ctx, withDrafts.ID)
+	if err != nil || len(drafts) != 1 {
+		t.Errorf("reminders should not delete drafts, site has %d contents (%v)", len(drafts), err)
+	}
+}
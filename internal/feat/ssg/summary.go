@@ -0,0 +1,150 @@
+package ssg
+
+import (
+	"context"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Summary settings.
+const (
+	// SummaryAutoRefKey sets how content without a summary or excerpt gets
+	// one: SummaryWords, SummarySentences, SummaryLLM or SummaryOff.
+	SummaryAutoRefKey = "ssg.summary.auto"
+	// SummaryLengthRefKey is the most words of a derived summary.
+	SummaryLengthRefKey = "ssg.summary.length"
+)
+
+// Summary modes.
+const (
+	SummaryWords     = "words"     // the first words of the body
+	SummarySentences = "sentences" // the first whole sentences of the body
+	SummaryLLM       = "llm"       // written by the LLM when content is saved, else as SummaryWords
+	SummaryOff       = "off"
+)
+
+const defaultSummaryWords = 30
+
+// SummaryOptions sets how DeriveSummary derives a summary from the body.
+type SummaryOptions struct {
+	Mode  string
+	Words int // most words of the summary
+	// Summarize writes a summary of at most words words of text, e.g. with
+	// the LLM. It is only used in SummaryLLM mode.
+	Summarize func(ctx context.Context, text string, words int) (string, error)
+}
+
+// SummaryOptionsFromParams reads the summary settings. Sites without them
+// get summaries of the first defaultSummaryWords words.
+func SummaryOptionsFromParams(params map[string]string) SummaryOptions {
+	opts := SummaryOptions{Mode: SummaryWords, Words: defaultSummaryWords}
+	switch mode := strings.ToLower(strings.TrimSpace(params[SummaryAutoRefKey])); mode {
+	case SummaryWords, SummarySentences, SummaryLLM, SummaryOff:
+		opts.Mode = mode
+	}
+	if n, err := strconv.Atoi(params[SummaryLengthRefKey]); err == nil && n > 0 {
+		opts.Words = n
+	}
+	return opts
+}
+
+// displaySummary returns the summary shown for content: its own, else the
+// excerpt of its meta, else one derived from the body. It never calls
+// opts.Summarize, so SummaryLLM falls back to the first words.
+func displaySummary(c *Content, opts SummaryOptions) string {
+	if s := strings.TrimSpace(c.Summary); s != "" {
+		return s
+	}
+	if c.Meta != nil {
+		if s := strings.TrimSpace(c.Meta.Excerpt); s != "" {
+			return s
+		}
+	}
+	switch opts.Mode {
+	case SummaryOff:
+		return ""
+	case SummarySentences:
+		return firstSentences(plainText(c.Body), opts.Words)
+	default:
+		return firstWords(plainText(c.Body), opts.Words)
+	}
+}
+
+// applySummaries sets the summary of contents without one to their excerpt
+// or one derived from the body, for the generated pages and feeds.
+func applySummaries(contents []*Content, params map[string]string) {
+	opts := SummaryOptionsFromParams(params)
+	for _, c := range contents {
+		c.Summary = displaySummary(c, opts)
+	}
+}
+
+// firstWords returns the first max words of text, with an ellipsis when it
+// had more. Words are never cut.
+func firstWords(text string, max int) string {
+	words := strings.Fields(text)
+	if len(words) <= max {
+		return strings.Join(words, " ")
+	}
+	return strings.TrimRight(strings.Join(words[:max], " "), ",;:-") + "…"
+}
+
+// firstSentences returns the whole sentences at the start of text that fit in
+// max words, or its first words when the first sentence is longer.
+func firstSentences(text string, max int) string {
+	words := strings.Fields(text)
+	end := 0
+	for i, w := range words {
+		if i >= max {
+			break
+		}
+		if strings.HasSuffix(w, ".") || strings.HasSuffix(w, "!") || strings.HasSuffix(w, "?") {
+			end = i + 1
+		}
+	}
+	if end == 0 || len(words) <= max {
+		return firstWords(text, max)
+	}
+	return strings.Join(words[:end], " ")
+}
+
+var plainTextMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// plainText returns the text of a markdown body with the markup removed and
+// whitespace collapsed. Code blocks, images and raw HTML are left out.
+func plainText(markdown string) string {
+	source := []byte(markdown)
+	doc := plainTextMarkdown.Parser().Parse(text.NewReader(source))
+
+	var b strings.Builder
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			if n.Type() == ast.TypeBlock {
+				b.WriteByte(' ')
+			}
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.Image, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			b.Write(n.Label(source))
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			b.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(html.UnescapeString(b.String())), " ")
+}
@@ -0,0 +1,83 @@
+package ssg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	body := "# Title\n\nSome **bold** and *italic* text with a [link](https://example.com) and `code`.\n\n![alt text](/images/a.png)\n\n```go\nfunc main() {}\n```\n\n- one\n- two &amp; three\n\n<div>raw html</div>\n"
+	want := "Title Some bold and italic text with a link and code. one two & three"
+	if got := plainText(body); got != want {
+		t.Errorf("plainText() = %q, want %q", got, want)
+	}
+}
+
+func TestFirstWords(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog"
+	tests := []struct {
+		max  int
+		want string
+	}{
+		{3, "The quick brown…"},
+		{9, text},
+		{20, text},
+	}
+	for _, tt := range tests {
+		got := firstWords(text, tt.max)
+		if got != tt.want {
+			t.Errorf("firstWords(%d) = %q, want %q", tt.max, got, tt.want)
+		}
+		if n := len(strings.Fields(strings.TrimSuffix(got, "…"))); n > tt.max {
+			t.Errorf("firstWords(%d) has %d words", tt.max, n)
+		}
+	}
+	if got := firstWords("One, two, three, four", 2); got != "One, two…" {
+		t.Errorf("firstWords() = %q, want the trailing comma dropped", got)
+	}
+}
+
+func TestFirstSentences(t *testing.T) {
+	text := "First sentence here. Second one is here! A third sentence goes on and on."
+	if got := firstSentences(text, 8); got != "First sentence here. Second one is here!" {
+		t.Errorf("firstSentences(8) = %q, want the two sentences that fit", got)
+	}
+	if got := firstSentences(text, 2); got != "First sentence…" {
+		t.Errorf("firstSentences(2) = %q, want the first words", got)
+	}
+	if got := firstSentences(text, 50); got != text {
+		t.Errorf("firstSentences(50) = %q, want the whole text", got)
+	}
+}
+
+func TestDisplaySummary(t *testing.T) {
+	opts := SummaryOptions{Mode: SummaryWords, Words: 2}
+	body := "Body text goes here"
+
+	if got := displaySummary(&Content{Summary: "Own", Meta: &Meta{Excerpt: "Excerpt"}, Body: body}, opts); got != "Own" {
+		t.Errorf("displaySummary() = %q, want the content's own summary", got)
+	}
+	if got := displaySummary(&Content{Meta: &Meta{Excerpt: "Excerpt"}, Body: body}, opts); got != "Excerpt" {
+		t.Errorf("displaySummary() = %q, want the excerpt", got)
+	}
+	if got := displaySummary(&Content{Body: body}, opts); got != "Body text…" {
+		t.Errorf("displaySummary() = %q, want the first words", got)
+	}
+	if got := displaySummary(&Content{Body: body}, SummaryOptions{Mode: SummaryOff, Words: 2}); got != "" {
+		t.Errorf("displaySummary() = %q, want none when off", got)
+	}
+}
+
+func TestSummaryOptionsFromParams(t *testing.T) {
+	if got := SummaryOptionsFromParams(nil); got.Mode != SummaryWords || got.Words != defaultSummaryWords {
+		t.Errorf("SummaryOptionsFromParams(nil) = %+v, want words mode with the default length", got)
+	}
+	got := SummaryOptionsFromParams(map[string]string{SummaryAutoRefKey: "Sentences", SummaryLengthRefKey: "12"})
+	if got.Mode != SummarySentences || got.Words != 12 {
+		t.Errorf("SummaryOptionsFromParams() = %+v, want sentences mode with 12 words", got)
+	}
+	got = SummaryOptionsFromParams(map[string]string{SummaryAutoRefKey: "bogus", SummaryLengthRefKey: "-1"})
+	if got.Mode != SummaryWords || got.Words != defaultSummaryWords {
+		t.Errorf("SummaryOptionsFromParams() with invalid values = %+v, want the defaults", got)
+	}
+}
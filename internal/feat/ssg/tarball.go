@@ -0,0 +1,84 @@
+package ssg
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// GenerateToTar generates the site as GenerateHTML does and returns the whole
+// output, assets, images, feeds and sitemap included, as a tar stream.
+//
+// Pages are still rendered into the output directory, incrementally, so
+// unchanged pages cost nothing; the archive is then written as the reader is
+// consumed, one file at a time, and never held in memory. The caller must
+// close the reader; closing it early, or cancelling ctx, stops the stream.
+func (g *HTMLGenerator) GenerateToTar(ctx context.Context, site *Site, contents []*Content, sections []*Section, layouts []*Layout, kinds []*ContentKind, params []*Setting, contributors []*Contributor, userAuthors map[string]*Contributor) (io.ReadCloser, error) {
+	if _, err := g.GenerateHTML(ctx, site, contents, sections, layouts, kinds, params, contributors, userAuthors, false); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(ctx, pw, g.workspace.GetHTMLPath(site.Slug)))
+	}()
+	return pr, nil
+}
+
+// writeTar writes the files under root to w as a tar archive, with paths
+// relative to root.
+func writeTar(ctx context.Context, w io.Writer, root string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path == root || !(d.IsDir() || d.Type().IsRegular()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("cannot add %s: %w", header.Name, err)
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.CopyN(tw, f, header.Size); err != nil {
+			return fmt.Errorf("cannot add %s: %w", header.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
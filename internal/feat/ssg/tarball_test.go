@@ -0,0 +1,74 @@
+package ssg
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateToTar(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	if err := g.workspace.CreateSiteDirectories(site.Slug); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(g.workspace.GetImagesPath(site.Slug), "cover.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	published := time.Now().Add(-time.Hour)
+	section := &Section{ID: uuid.New(), SiteID: site.ID, Name: "Root", Path: "/"}
+	content := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, ShortID: "abc12345", Heading: "Hello tar", Body: "Streamed.", PublishedAt: &published}
+	params := []*Setting{{RefKey: BaseURLRefKey, Value: "https://example.com"}}
+
+	stream, err := g.GenerateToTar(context.Background(), site, []*Content{content}, []*Section{section}, nil, nil, params, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateToTar() error = %v", err)
+	}
+	defer stream.Close()
+
+	files := make(map[string]string)
+	tr := tar.NewReader(stream)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading tar: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(data)
+	}
+
+	if !strings.Contains(files["index.html"], "Hello tar") {
+		t.Errorf("index.html does not list the content:\n%s", files["index.html"])
+	}
+	if got := files["images/cover.png"]; got != "png" {
+		t.Errorf("images/cover.png = %q, want png", got)
+	}
+	for _, name := range []string{"static/css/style.css", "sitemap.xml", "feed/atom.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("archive has no %s", name)
+		}
+	}
+	for name := range files {
+		if strings.HasPrefix(name, "/") || strings.Contains(name, "..") {
+			t.Errorf("entry %q is not relative to the output", name)
+		}
+	}
+}
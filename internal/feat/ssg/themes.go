@@ -0,0 +1,287 @@
+package ssg
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ThemeRefKey is the setting naming the theme a site is generated with.
+// Empty uses DefaultTheme.
+const ThemeRefKey = "ssg.theme"
+
+// DefaultTheme is the bundled theme under assets/ssg.
+const DefaultTheme = "default"
+
+// ErrThemeNotFound is returned for theme names with no embedded or uploaded
+// theme.
+var ErrThemeNotFound = errors.New("theme not found")
+
+const (
+	defaultThemeDir   = "assets/ssg"
+	embeddedThemesDir = "assets/themes"
+	maxThemeFileSize  = 10 << 20
+	maxThemeSize      = 50 << 20 // all files of a bundle, uncompressed
+	maxThemeFiles     = 1000
+)
+
+var themeNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Theme is a named set of templates and static files laid out like
+// assets/ssg: layout.html, partials/*.html and static/. A theme only needs
+// the files it changes, the default theme provides the rest.
+type Theme struct {
+	Name     string
+	Uploaded bool // from the site's themes directory rather than the binary
+	fsys     fs.FS
+	key      string // identifies the theme in the asset manifest cache
+}
+
+// Themes returns the names of the themes a site can use: the default one,
+// the embedded ones and those uploaded to the site, sorted after the default.
+func (g *HTMLGenerator) Themes(siteSlug string) []string {
+	seen := map[string]bool{DefaultTheme: true}
+	var names []string
+	add := func(entries []fs.DirEntry) {
+		for _, e := range entries {
+			if e.IsDir() && themeNameRe.MatchString(e.Name()) && !seen[e.Name()] {
+				seen[e.Name()] = true
+				names = append(names, e.Name())
+			}
+		}
+	}
+	entries, _ := fs.ReadDir(g.assetsFS, embeddedThemesDir)
+	add(entries)
+	if siteSlug != "" {
+		entries, _ = os.ReadDir(g.workspace.GetThemesPath(siteSlug))
+		add(entries)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultTheme}, names...)
+}
+
+// LoadTheme returns the named theme of a site. Embedded themes take
+// precedence over uploaded ones with the same name; an empty name is the
+// default theme.
+func (g *HTMLGenerator) LoadTheme(siteSlug, name string) (*Theme, error) {
+	base, err := fs.Sub(g.assetsFS, defaultThemeDir)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || name == DefaultTheme {
+		return &Theme{Name: DefaultTheme, fsys: base, key: DefaultTheme}, nil
+	}
+	if !themeNameRe.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", ErrThemeNotFound, name)
+	}
+
+	dir := path.Join(embeddedThemesDir, name)
+	if info, err := fs.Stat(g.assetsFS, dir); err == nil && info.IsDir() {
+		top, err := fs.Sub(g.assetsFS, dir)
+		if err != nil {
+			return nil, err
+		}
+		return &Theme{Name: name, fsys: overlayFS{top: top, base: base}, key: name}, nil
+	}
+	if siteSlug != "" {
+		dir := filepath.Join(g.workspace.GetThemesPath(siteSlug), name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return &Theme{Name: name, Uploaded: true, fsys: overlayFS{top: os.DirFS(dir), base: base}, key: siteSlug + "/" + name}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrThemeNotFound, name)
+}
+
+// siteTheme returns the theme set for a site, or the default theme when the
+// setting is empty or names a theme that no longer exists.
+func (g *HTMLGenerator) siteTheme(siteSlug string, params map[string]string) *Theme {
+	if t, err := g.LoadTheme(siteSlug, params[ThemeRefKey]); err == nil {
+		return t
+	}
+	t, _ := g.LoadTheme("", DefaultTheme)
+	return t
+}
+
+// InstallTheme unpacks a zip bundle as the named theme of a site, replacing
+// an uploaded theme of the same name. The bundle holds layout.html,
+// partials/ and static/, at its root or inside a single top directory. Its
+// templates must parse together with the default ones.
+func (g *HTMLGenerator) InstallTheme(siteSlug, name string, r io.ReaderAt, size int64) error {
+	if !themeNameRe.MatchString(name) || name == DefaultTheme {
+		return fmt.Errorf("theme name %q must use lowercase letters, digits, hyphens and underscores, and not be %q", name, DefaultTheme)
+	}
+	if info, err := fs.Stat(g.assetsFS, path.Join(embeddedThemesDir, name)); err == nil && info.IsDir() {
+		return fmt.Errorf("theme %q is built in, choose another name", name)
+	}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("cannot read theme bundle: %w", err)
+	}
+
+	themesPath := g.workspace.GetThemesPath(siteSlug)
+	if err := os.MkdirAll(themesPath, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(themesPath, "."+name+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	prefix := bundlePrefix(zr.File)
+	var count int
+	var total int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if count == maxThemeFiles {
+			return fmt.Errorf("theme bundle has more than %d files", maxThemeFiles)
+		}
+		name := strings.TrimPrefix(f.Name, prefix)
+		if !themeFileAllowed(name) {
+			return fmt.Errorf("theme bundle file %q is not layout.html, a partial or under static/", f.Name)
+		}
+		if f.UncompressedSize64 > maxThemeFileSize {
+			return fmt.Errorf("theme bundle file %q is larger than %d MB", f.Name, maxThemeFileSize>>20)
+		}
+		if total+int64(f.UncompressedSize64) > maxThemeSize {
+			return fmt.Errorf("theme bundle is larger than %d MB uncompressed", maxThemeSize>>20)
+		}
+		// Sizes in the bundle are not trusted, the copy stops at the limit.
+		n, err := extractThemeFile(f, filepath.Join(tmp, filepath.FromSlash(name)), min(maxThemeFileSize, maxThemeSize-total))
+		if err != nil {
+			return err
+		}
+		total += n
+		count++
+	}
+	if count == 0 {
+		return errors.New("theme bundle has no files")
+	}
+
+	base, err := fs.Sub(g.assetsFS, defaultThemeDir)
+	if err != nil {
+		return err
+	}
+	if _, err := g.parseTemplates(&Theme{Name: name, fsys: overlayFS{top: os.DirFS(tmp), base: base}}); err != nil {
+		return fmt.Errorf("theme templates: %w", err)
+	}
+
+	dest := filepath.Join(themesPath, name)
+	if err := os.RemoveAll(dest); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// bundlePrefix returns the top directory every file of a bundle is in, with
+// its slash, or "" when files sit at the root.
+func bundlePrefix(files []*zip.File) string {
+	var prefix string
+	for i, f := range files {
+		top, _, ok := strings.Cut(f.Name, "/")
+		if !ok || top == "static" || top == "partials" {
+			return ""
+		}
+		if i == 0 {
+			prefix = top + "/"
+		} else if top+"/" != prefix {
+			return ""
+		}
+	}
+	return prefix
+}
+
+// themeFileAllowed reports whether a bundle path is one a theme can have.
+func themeFileAllowed(name string) bool {
+	if name == "" || !fs.ValidPath(name) {
+		return false
+	}
+	switch {
+	case name == "layout.html":
+		return true
+	case strings.HasPrefix(name, "partials/"):
+		return path.Dir(name) == "partials" && path.Ext(name) == ".html"
+	case strings.HasPrefix(name, "static/"):
+		return true
+	}
+	return false
+}
+
+// extractThemeFile writes f to dest and returns its size. Files longer than
+// limit bytes fail rather than being cut short.
+func extractThemeFile(f *zip.File, dest string, limit int64) (int64, error) {
+	if err := EnsureDir(dest); err != nil {
+		return 0, err
+	}
+	src, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, io.LimitReader(src, limit+1))
+	if err == nil && n > limit {
+		err = fmt.Errorf("theme bundle file %q is over the size limit", f.Name)
+	}
+	if err != nil {
+		out.Close()
+		return 0, err
+	}
+	return n, out.Close()
+}
+
+// overlayFS serves the files of top, falling back to base for those top does
+// not have. Directories list the entries of both.
+type overlayFS struct {
+	top, base fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if f, err := o.top.Open(name); err == nil {
+		return f, nil
+	}
+	return o.base.Open(name)
+}
+
+func (o overlayFS) ReadFile(name string) ([]byte, error) {
+	if data, err := fs.ReadFile(o.top, name); err == nil {
+		return data, nil
+	}
+	return fs.ReadFile(o.base, name)
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	top, topErr := fs.ReadDir(o.top, name)
+	base, baseErr := fs.ReadDir(o.base, name)
+	if topErr != nil && baseErr != nil {
+		return nil, baseErr
+	}
+	byName := make(map[string]fs.DirEntry, len(top)+len(base))
+	for _, e := range base {
+		byName[e.Name()] = e
+	}
+	for _, e := range top {
+		byName[e.Name()] = e
+	}
+	entries := make([]fs.DirEntry, 0, len(byName))
+	for _, e := range byName {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
@@ -0,0 +1,186 @@
+package ssg
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func themeBundle(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestSiteTheme(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+	site := &Site{ID: uuid.New(), Name: "Themed", Slug: "themed"}
+	published := time.Now().Add(-time.Hour)
+	content := &Content{ID: uuid.New(), SiteID: site.ID, SectionPath: "blog", Heading: "Hello", Body: "Body", PublishedAt: &published}
+
+	layout, err := os.ReadFile("../../../assets/ssg/layout.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	darkLayout := strings.Replace(string(layout), "<body", `<body class="theme-dark"`, 1)
+	bundle := themeBundle(t, map[string]string{
+		"dark/layout.html":          darkLayout,
+		"dark/static/css/theme.css": "body { background: #000; }",
+	})
+	if err := g.InstallTheme(site.Slug, "dark", bundle, bundle.Size()); err != nil {
+		t.Fatalf("InstallTheme() error = %v", err)
+	}
+	if got := g.Themes(site.Slug); len(got) != 2 || got[0] != DefaultTheme || got[1] != "dark" {
+		t.Errorf("Themes() = %v, want [default dark]", got)
+	}
+
+	render := func(params map[string]string) string {
+		theme := g.siteTheme(site.Slug, params)
+		tmpl, err := g.parseTemplates(theme)
+		if err != nil {
+			t.Fatalf("parseTemplates(%s) error = %v", theme.Name, err)
+		}
+		htmlPath := g.workspace.GetHTMLPath(site.Slug)
+		if _, err := g.renderContentPage(tmpl, nil, nil, htmlPath, site, content, nil, adjacentLinks{}, nil, nil, params, nil, BlocksConfig{}); err != nil {
+			t.Fatalf("renderContentPage() error = %v", err)
+		}
+		out, err := os.ReadFile(g.workspace.GetContentHTMLPath(site.Slug, content.SectionPath, content.Slug()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+
+	if page := render(map[string]string{}); strings.Contains(page, "theme-dark") {
+		t.Error("the default theme should not use the dark layout")
+	}
+	if page := render(map[string]string{ThemeRefKey: "dark"}); !strings.Contains(page, `<body class="theme-dark"`) || !strings.Contains(page, "Hello") {
+		t.Errorf("the dark theme should render its own layout with the default partials:\n%s", page)
+	}
+	if page := render(map[string]string{ThemeRefKey: "missing"}); strings.Contains(page, "theme-dark") {
+		t.Error("an unknown theme should fall back to the default one")
+	}
+
+	htmlPath := t.TempDir()
+	if err := g.copyStaticAssets(newBuildState(htmlPath, nil, "", true), htmlPath, g.siteTheme(site.Slug, map[string]string{ThemeRefKey: "dark"})); err != nil {
+		t.Fatalf("copyStaticAssets() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(htmlPath, "static/css/theme.css")); string(data) != "body { background: #000; }" {
+		t.Errorf("theme.css = %q, want the dark theme's", data)
+	}
+	if _, err := os.Stat(filepath.Join(htmlPath, "static/css/core.css")); err != nil {
+		t.Errorf("files the theme lacks should come from the default theme: %v", err)
+	}
+}
+
+func TestInstallThemeRejects(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+
+	tests := []struct {
+		name  string
+		theme string
+		files map[string]string
+	}{
+		{"reserved name", DefaultTheme, map[string]string{"layout.html": "x"}},
+		{"invalid name", "Bad Name", map[string]string{"layout.html": "x"}},
+		{"path outside the theme", "evil", map[string]string{"../evil.html": "x"}},
+		{"unexpected file", "extra", map[string]string{"layout.html": "x", "main.go": "package main"}},
+		{"broken template", "broken", map[string]string{"partials/hero.html": "{{ if }}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := themeBundle(t, tt.files)
+			if err := g.InstallTheme("site", tt.theme, bundle, bundle.Size()); err == nil {
+				t.Error("InstallTheme() should fail")
+			}
+			if _, err := g.LoadTheme("site", tt.theme); tt.theme != DefaultTheme && !errors.Is(err, ErrThemeNotFound) {
+				t.Errorf("LoadTheme() error = %v, want ErrThemeNotFound", err)
+			}
+		})
+	}
+}
+
+func TestInstallThemeLimits(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+
+	many := map[string]string{"layout.html": "x"}
+	for i := 0; i < maxThemeFiles; i++ {
+		many[fmt.Sprintf("static/%d.css", i)] = "x"
+	}
+	large := map[string]string{}
+	for i := 0; i <= maxThemeSize/maxThemeFileSize; i++ {
+		large[fmt.Sprintf("static/%d.bin", i)] = strings.Repeat("0", maxThemeFileSize)
+	}
+
+	tests := []struct {
+		name   string
+		bundle *bytes.Reader
+	}{
+		{"too many files", themeBundle(t, many)},
+		{"too large in total", themeBundle(t, large)},
+		{"size understated", understatedBundle(t, "static/big.bin", maxThemeFileSize+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := g.InstallTheme("site", "bomb", tt.bundle, tt.bundle.Size()); err == nil {
+				t.Error("InstallTheme() should fail")
+			}
+			if _, err := g.LoadTheme("site", "bomb"); !errors.Is(err, ErrThemeNotFound) {
+				t.Errorf("LoadTheme() error = %v, want ErrThemeNotFound", err)
+			}
+		})
+	}
+}
+
+// understatedBundle returns a bundle with one file of size bytes whose
+// header claims it is one byte long.
+func understatedBundle(t *testing.T, name string, size int) *bytes.Reader {
+	t.Helper()
+	data := bytes.Repeat([]byte("0"), size)
+	var compressed bytes.Buffer
+	fw, err := flate.NewWriter(&compressed, flate.BestSpeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(data)
+	fw.Close()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Deflate,
+		CRC32:              crc32.ChecksumIEEE(data),
+		CompressedSize64:   uint64(compressed.Len()),
+		UncompressedSize64: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(compressed.Bytes())
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
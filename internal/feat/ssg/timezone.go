@@ -0,0 +1,148 @@
+package ssg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // Timezone names resolve even where the host has no zoneinfo
+)
+
+// TimezoneRefKey is the setting holding the site's IANA timezone name.
+const TimezoneRefKey = "ssg.site.timezone"
+
+// DateFormatRefKey is the setting holding how generated pages show dates: the
+// name of a preset in dateFormatPresets or a Go time layout.
+const DateFormatRefKey = "ssg.date.format"
+
+// DefaultDateFormat is the date format of sites that set none.
+const DefaultDateFormat = "iso"
+
+// dateFormatPresets maps the named date formats to Go time layouts.
+var dateFormatPresets = map[string]string{
+	"iso":    "2006-01-02",
+	"short":  "Jan 2, 2006",
+	"medium": "January 2, 2006",
+	"long":   "Monday, January 2, 2006",
+}
+
+// dateTimeLocalLayout is the value format of datetime-local form inputs.
+const dateTimeLocalLayout = "2006-01-02T15:04"
+
+// LoadTimezone resolves an IANA timezone name such as Europe/Berlin. An empty
+// name is UTC. "Local" is rejected: generated output must not depend on the
+// zone of the machine building it.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	if name == "Local" {
+		return nil, fmt.Errorf("timezone %q depends on the server, use an IANA name such as Europe/Berlin", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q, use an IANA name such as Europe/Berlin", name)
+	}
+	return loc, nil
+}
+
+// siteLocation returns the site's timezone. Invalid values fall back to UTC;
+// settings validation rejects them on save.
+func siteLocation(params map[string]string) *time.Location {
+	loc, err := LoadTimezone(params[TimezoneRefKey])
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// withDefaultTimezone returns a settings map carrying tz as the site timezone
+// when the site does not set one.
+func withDefaultTimezone(settings []*Setting, tz string) map[string]string {
+	params := make(map[string]string, len(settings)+1)
+	for _, p := range settings {
+		params[p.RefKey] = p.Value
+	}
+	if strings.TrimSpace(params[TimezoneRefKey]) == "" && tz != "" {
+		params[TimezoneRefKey] = tz
+	}
+	return params
+}
+
+// formatInTZ formats t, a time.Time or *time.Time, in the named timezone.
+// It returns an empty string for nil or zero times, and uses UTC when the
+// name is not valid. Templates call it as {{ formatInTZ .PublishedAt $.Timezone "Jan 02, 2006" }}.
+func formatInTZ(t any, tz, layout string) string {
+	var v time.Time
+	switch tt := t.(type) {
+	case time.Time:
+		v = tt
+	case *time.Time:
+		if tt == nil {
+			return ""
+		}
+		v = *tt
+	default:
+		return ""
+	}
+	if v.IsZero() {
+		return ""
+	}
+
+	loc, err := LoadTimezone(tz)
+	if err != nil {
+		loc = time.UTC
+	}
+	return v.In(loc).Format(layout)
+}
+
+// dateLayout resolves a date format setting to a Go time layout. Empty is
+// DefaultDateFormat and anything that is not a preset is taken as a layout.
+func dateLayout(format string) string {
+	format = strings.TrimSpace(format)
+	if format == "" {
+		format = DefaultDateFormat
+	}
+	if layout, ok := dateFormatPresets[format]; ok {
+		return layout
+	}
+	return format
+}
+
+// siteDate formats t like formatInTZ, in the site's date format. Templates
+// call it as {{ siteDate .PublishedAt $.Timezone $.DateFormat }}; machine
+// readable dates, such as datetime attributes and feeds, keep fixed layouts.
+func siteDate(t any, tz, format string) string {
+	return formatInTZ(t, tz, dateLayout(format))
+}
+
+// parseLocalDateTime reads a datetime-local value as wall time in loc and
+// returns the instant in UTC. Around DST changes the result is deterministic:
+// a wall time that occurs twice resolves to the first occurrence, and one
+// skipped by the clock moving forward is shifted forward by the gap.
+func parseLocalDateTime(value string, loc *time.Location) (time.Time, error) {
+	wall, err := time.Parse(dateTimeLocalLayout, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Offsets on either side of any transition near the wall time. Zones
+	// never change twice within a day, so these are the only candidates.
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var found time.Time
+	for _, offset := range []int{before, after} {
+		t := wall.Add(-time.Duration(offset) * time.Second)
+		if t.In(loc).Format(dateTimeLocalLayout) != wall.Format(dateTimeLocalLayout) {
+			continue
+		}
+		if found.IsZero() || t.Before(found) {
+			found = t
+		}
+	}
+	if found.IsZero() {
+		found = wall.Add(-time.Duration(before) * time.Second)
+	}
+	return found.UTC(), nil
+}
@@ -0,0 +1,235 @@
+package ssg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", "UTC", false},
+		{"UTC", "UTC", false},
+		{" Europe/Berlin ", "Europe/Berlin", false},
+		{"America/New_York", "America/New_York", false},
+		{"Local", "", true},
+		{"Mars/Olympus_Mons", "", true},
+		{"../etc/passwd", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := LoadTimezone(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadTimezone(%q) = %v, want error", tt.name, loc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTimezone(%q) error = %v", tt.name, err)
+			}
+			if loc.String() != tt.want {
+				t.Errorf("LoadTimezone(%q) = %q, want %q", tt.name, loc, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLocalDateTimeDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		loc   *time.Location
+		want  string
+	}{
+		{"utc", "2024-03-10T02:30", time.UTC, "2024-03-10T02:30:00Z"},
+		{"standard time", "2024-01-15T09:00", ny, "2024-01-15T14:00:00Z"},
+		{"daylight time", "2024-07-15T09:00", ny, "2024-07-15T13:00:00Z"},
+		{"just before spring forward", "2024-03-10T01:59", ny, "2024-03-10T06:59:00Z"},
+		{"skipped by spring forward", "2024-03-10T02:30", ny, "2024-03-10T07:30:00Z"},
+		{"just after spring forward", "2024-03-10T03:00", ny, "2024-03-10T07:00:00Z"},
+		{"repeated by fall back", "2024-11-03T01:30", ny, "2024-11-03T05:30:00Z"},
+		{"after fall back", "2024-11-03T02:00", ny, "2024-11-03T07:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLocalDateTime(tt.value, tt.loc)
+			if err != nil {
+				t.Fatalf("parseLocalDateTime(%q) error = %v", tt.value, err)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("location = %v, want UTC", got.Location())
+			}
+			if got.Format(time.RFC3339) != tt.want {
+				t.Errorf("parseLocalDateTime(%q) = %s, want %s", tt.value, got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+
+	if _, err := parseLocalDateTime("10/03/2024 02:30", ny); err == nil {
+		t.Error("expected error for a value that is not datetime-local")
+	}
+}
+
+func TestParseLocalDateTimeRoundTrip(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	// A stored time shown in the edit form and saved again must not move,
+	// including the second 01:30 of a fall back night, which the form cannot
+	// tell apart from the first.
+	for _, stored := range []string{"2024-03-10T06:59:00Z", "2024-03-10T07:00:00Z", "2024-11-03T05:30:00Z", "2024-11-03T07:00:00Z"} {
+		instant, _ := time.Parse(time.RFC3339, stored)
+		shown := formatInTZ(instant, "America/New_York", dateTimeLocalLayout)
+		saved, err := parseLocalDateTime(shown, ny)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !saved.Equal(instant) {
+			t.Errorf("%s shown as %s saved as %s", stored, shown, saved.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestFormatInTZ(t *testing.T) {
+	// 03:30 UTC is still the previous day in New York.
+	instant := time.Date(2024, 3, 10, 3, 30, 0, 0, time.UTC)
+	var nilTime *time.Time
+
+	tests := []struct {
+		name string
+		t    any
+		tz   string
+		want string
+	}{
+		{"time value", instant, "America/New_York", "2024-03-09 22:30 EST"},
+		{"time pointer", &instant, "Asia/Tokyo", "2024-03-10 12:30 JST"},
+		{"empty timezone is UTC", instant, "", "2024-03-10 03:30 UTC"},
+		{"invalid timezone is UTC", instant, "Nowhere/Land", "2024-03-10 03:30 UTC"},
+		{"nil pointer", nilTime, "UTC", ""},
+		{"zero time", time.Time{}, "UTC", ""},
+		{"not a time", "2024-03-10", "UTC", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatInTZ(tt.t, tt.tz, "2006-01-02 15:04 MST"); got != tt.want {
+				t.Errorf("formatInTZ() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSiteDate(t *testing.T) {
+	// 03:30 UTC is still Saturday the 9th in New York.
+	instant := time.Date(2024, 3, 10, 3, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		tz     string
+		want   string
+	}{
+		{"", "UTC", "2024-03-10"},
+		{"iso", "America/New_York", "2024-03-09"},
+		{"short", "UTC", "Mar 10, 2024"},
+		{"medium", "UTC", "March 10, 2024"},
+		{"long", "America/New_York", "Saturday, March 9, 2024"},
+		{" long ", "UTC", "Sunday, March 10, 2024"},
+		{"02.01.2006", "UTC", "10.03.2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := siteDate(&instant, tt.tz, tt.format); got != tt.want {
+				t.Errorf("siteDate(%q, %q) = %q, want %q", tt.format, tt.tz, got, tt.want)
+			}
+		})
+	}
+
+	if got := siteDate((*time.Time)(nil), "UTC", "medium"); got != "" {
+		t.Errorf("siteDate(nil) = %q, want empty", got)
+	}
+}
+
+func TestSiteTimezoneInOutput(t *testing.T) {
+	published := time.Date(2024, 3, 10, 3, 30, 0, 0, time.UTC)
+	post := &Content{ShortID: "abc123", Heading: "Late", SectionPath: "blog", PublishedAt: &published}
+	params := withDefaultTimezone([]*Setting{{RefKey: PermalinkRefKey, Value: "/:year/:month/:day/:slug/"}}, "America/New_York")
+
+	if got := permalinkPattern(params).Path(post); got != "2024/03/09/late-abc123" {
+		t.Errorf("Path() = %q, want the New York date", got)
+	}
+	params[TimezoneRefKey] = "UTC"
+	if got := permalinkPattern(params).Path(post); got != "2024/03/10/late-abc123" {
+		t.Errorf("Path() = %q, want the UTC date", got)
+	}
+
+	data, err := MarshalContentMarkdown(post, mustLoadTimezone(t, "America/New_York"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "published-at: 2024-03-09T22:30:00-05:00") {
+		t.Errorf("expected published-at with the site offset:\n%s", data)
+	}
+	fm, _, err := UnmarshalContentMarkdown(string(data))
+	if err != nil || !fm.PublishedAt.Equal(published) {
+		t.Errorf("PublishedAt = %v, %v; want %v", fm.PublishedAt, err, published)
+	}
+}
+
+func TestWithDefaultTimezone(t *testing.T) {
+	if got := withDefaultTimezone(nil, "Europe/Berlin")[TimezoneRefKey]; got != "Europe/Berlin" {
+		t.Errorf("missing setting: timezone = %q, want the default", got)
+	}
+	site := []*Setting{{RefKey: TimezoneRefKey, Value: "Asia/Tokyo"}}
+	if got := withDefaultTimezone(site, "Europe/Berlin")[TimezoneRefKey]; got != "Asia/Tokyo" {
+		t.Errorf("site setting: timezone = %q, want Asia/Tokyo", got)
+	}
+	if _, ok := withDefaultTimezone(nil, "")[TimezoneRefKey]; ok {
+		t.Error("no default: timezone should be left unset")
+	}
+}
+
+func mustLoadTimezone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := LoadTimezone(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestServiceGetTimezone(t *testing.T) {
+	svc, _, cleanup := setupTestService(t)
+	defer cleanup()
+	ctx := context.Background()
+	site := createTestSite(t, svc, "Timezone Site", "timezone-site")
+
+	if got, err := svc.GetTimezone(ctx, site.ID); err != nil || got != time.UTC {
+		t.Errorf("GetTimezone() without setting = %v, %v, want UTC", got, err)
+	}
+
+	param := NewSetting(site.ID, "Site timezone", "Europe/Berlin")
+	param.RefKey = TimezoneRefKey
+	if err := svc.CreateSetting(ctx, param); err != nil {
+		t.Fatalf("CreateSetting() error = %v", err)
+	}
+	if got, err := svc.GetTimezone(ctx, site.ID); err != nil || got.String() != "Europe/Berlin" {
+		t.Errorf("GetTimezone() = %v, %v, want Europe/Berlin", got, err)
+	}
+
+	param.Value = "Europe/Atlantis"
+	if err := svc.UpdateSetting(ctx, param); err == nil {
+		t.Error("UpdateSetting() with an unknown timezone should fail validation")
+	}
+}
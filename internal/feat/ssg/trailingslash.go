@@ -0,0 +1,244 @@
+package ssg
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrailingSlashRefKey is the setting holding whether page URLs end in a slash:
+// "always" gives /blog/post/, "never" gives /blog/post. The site root keeps its
+// slash either way.
+const TrailingSlashRefKey = "ssg.urls.trailing_slash"
+
+// RedirectStatusRefKey is the setting holding the status the _redirects file
+// gives its rules: 301 for permanent moves, 302 for temporary ones.
+const RedirectStatusRefKey = "ssg.redirects.status"
+
+const (
+	TrailingSlashAlways = "always"
+	TrailingSlashNever  = "never"
+
+	DefaultRedirectStatus = "301"
+
+	// redirectsFile lists the redirects of the site for hosts that read it,
+	// such as Netlify and Cloudflare Pages.
+	redirectsFile = "_redirects"
+)
+
+// ParseTrailingSlash validates a trailing slash policy. Empty means always.
+func ParseTrailingSlash(raw string) (string, error) {
+	switch v := strings.TrimSpace(raw); v {
+	case "":
+		return TrailingSlashAlways, nil
+	case TrailingSlashAlways, TrailingSlashNever:
+		return v, nil
+	}
+	return "", fmt.Errorf("trailing slash must be %q or %q", TrailingSlashAlways, TrailingSlashNever)
+}
+
+// ParseRedirectStatus validates a redirect status. Empty means 301.
+func ParseRedirectStatus(raw string) (string, error) {
+	switch v := strings.TrimSpace(raw); v {
+	case "":
+		return DefaultRedirectStatus, nil
+	case "301", "302":
+		return v, nil
+	}
+	return "", fmt.Errorf("redirect status must be 301 or 302")
+}
+
+// trailingSlash reports whether page URLs end in a slash. Invalid values are
+// treated as always; settings validation rejects them on save.
+func trailingSlash(params map[string]string) bool {
+	policy, err := ParseTrailingSlash(params[TrailingSlashRefKey])
+	return err != nil || policy == TrailingSlashAlways
+}
+
+// redirectStatus returns the ssg.redirects.status setting, 301 when invalid.
+func redirectStatus(params map[string]string) string {
+	status, err := ParseRedirectStatus(params[RedirectStatusRefKey])
+	if err != nil {
+		return DefaultRedirectStatus
+	}
+	return status
+}
+
+// pageURL applies the trailing slash policy to the URL of a page, given with a
+// trailing slash. The site root is left as it is.
+func pageURL(params map[string]string, u string) string {
+	if trailingSlash(params) || u == siteBasePath(params) || !strings.HasSuffix(u, "/") {
+		return u
+	}
+	return strings.TrimSuffix(u, "/")
+}
+
+// pageFile returns where the page of outputPath, a <dir>/index.html, is
+// written under htmlPath. Without trailing slashes it is <dir>.html, which
+// static hosts serve at /dir. The root index stays where it is.
+func pageFile(params map[string]string, htmlPath, outputPath string) string {
+	if trailingSlash(params) || filepath.Base(outputPath) != "index.html" {
+		return outputPath
+	}
+	dir := filepath.Dir(outputPath)
+	if filepath.Clean(dir) == filepath.Clean(htmlPath) {
+		return outputPath
+	}
+	return dir + ".html"
+}
+
+// internalHref matches the href of a link, capturing its value.
+var internalHref = regexp.MustCompile(`(href=")([^"]*)(")`)
+
+// normalizeInternalLinks applies the trailing slash policy to links in html
+// that point to pages of the site, written as root paths under the base path
+// or as absolute URLs under the base URL. Links to files, which have an
+// extension, to the site root and to other sites are left as they are.
+func normalizeInternalLinks(html string, params map[string]string) string {
+	basePath := siteBasePath(params)
+	prefixes := []string{basePath}
+	if baseURL := siteBaseURL(params); baseURL != "" {
+		prefixes = append(prefixes, baseURL+basePath)
+	}
+	always := trailingSlash(params)
+
+	return internalHref.ReplaceAllStringFunc(html, func(m string) string {
+		parts := internalHref.FindStringSubmatch(m)
+		href := parts[2]
+
+		internal := false
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(href, prefix) && !strings.HasPrefix(href, "//") {
+				internal = true
+				break
+			}
+		}
+		if !internal {
+			return m
+		}
+
+		p, rest := href, ""
+		if i := strings.IndexAny(href, "?#"); i >= 0 {
+			p, rest = href[:i], href[i:]
+		}
+		if p == "" || strings.HasSuffix(p, "://") {
+			return m
+		}
+		for _, prefix := range prefixes {
+			if p == prefix || p+"/" == prefix {
+				return m
+			}
+		}
+		if path.Ext(path.Base(strings.TrimSuffix(p, "/"))) != "" {
+			return m
+		}
+
+		if always && !strings.HasSuffix(p, "/") {
+			p += "/"
+		} else if !always {
+			p = strings.TrimSuffix(p, "/")
+		}
+		return parts[1] + p + rest + parts[3]
+	})
+}
+
+// writeSlashRedirects enforces the policy when pages have no trailing slash:
+// each page written as <dir>.html gets a redirect page at <dir>/index.html
+// sending /dir/ on to /dir, unless a page is generated there already.
+func (g *HTMLGenerator) writeSlashRedirects(build *buildState, htmlPath string, params map[string]string) (int, error) {
+	if trailingSlash(params) {
+		return 0, nil
+	}
+
+	written := 0
+	for _, file := range build.pageFiles() {
+		rel, err := filepath.Rel(htmlPath, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		dir := strings.TrimSuffix(rel, ".html")
+		outputPath := filepath.Join(htmlPath, dir, "index.html")
+		if build.generated(outputPath) {
+			continue
+		}
+
+		target := siteBasePath(params) + filepath.ToSlash(dir)
+		if build.unchanged(outputPath, hashInputs(target), time.Time{}) {
+			continue
+		}
+		if err := EnsureDir(outputPath); err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(outputPath, []byte(redirectPage(target)), 0644); err != nil {
+			return written, err
+		}
+		build.record(outputPath, hashInputs(target), time.Time{})
+		written++
+	}
+	return written, nil
+}
+
+// redirectRules returns the rules of the _redirects file, source to target:
+// the previous permalinks of content and the pages of kinds that send
+// visitors on to the URL they link to.
+func (g *HTMLGenerator) redirectRules(build *buildState, pages []*Content, permalinks map[uuid.UUID]string, params map[string]string) map[string]string {
+	basePath := siteBasePath(params)
+	rules := make(map[string]string)
+	for oldPath, rawID := range build.redirectPaths {
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			continue
+		}
+		if newPath, ok := permalinks[id]; ok {
+			rules[pageURL(params, basePath+oldPath+"/")] = pageURL(params, basePath+newPath+"/")
+		}
+	}
+	for _, c := range pages {
+		target := linkTarget(c)
+		if !c.kindSettings().Redirect || target == "" {
+			continue
+		}
+		if p, ok := permalinks[c.ID]; ok {
+			rules[pageURL(params, basePath+p+"/")] = target
+		}
+	}
+	return rules
+}
+
+// writeRedirectsFile writes the _redirects file with the redirect rules of the
+// site and the configured status, so hosts that read it answer with a real
+// redirect instead of the redirect page. Sites with no redirects get none.
+func (g *HTMLGenerator) writeRedirectsFile(build *buildState, htmlPath string, rules map[string]string, params map[string]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	sources := make([]string, 0, len(rules))
+	for source := range rules {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	status := redirectStatus(params)
+	var b strings.Builder
+	for _, source := range sources {
+		b.WriteString(source + " " + rules[source] + " " + status + "\n")
+	}
+
+	outputPath := filepath.Join(htmlPath, redirectsFile)
+	data := b.String()
+	if build.unchanged(outputPath, hashInputs(data), time.Time{}) {
+		return nil
+	}
+	if err := os.WriteFile(outputPath, []byte(data), 0644); err != nil {
+		return err
+	}
+	build.record(outputPath, hashInputs(data), time.Time{})
+	return nil
+}
@@ -0,0 +1,174 @@
+package ssg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeInternalLinks(t *testing.T) {
+	never := map[string]string{BaseURLRefKey: "https://example.com", TrailingSlashRefKey: TrailingSlashNever}
+	always := map[string]string{BaseURLRefKey: "https://example.com"}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		in     string
+		want   string
+	}{
+		{"never trims page links", never, `<a href="/blog/post/">`, `<a href="/blog/post">`},
+		{"never trims absolute links", never, `<a href="https://example.com/blog/">`, `<a href="https://example.com/blog">`},
+		{"never keeps the fragment", never, `<a href="/blog/post/#intro">`, `<a href="/blog/post#intro">`},
+		{"never keeps the root", never, `<a href="/">`, `<a href="/">`},
+		{"always adds the slash", always, `<a href="/blog/post">`, `<a href="/blog/post/">`},
+		{"always keeps the query", always, `<a href="/search?q=go">`, `<a href="/search/?q=go">`},
+		{"files are left alone", always, `<link href="/static/css/style.css">`, `<link href="/static/css/style.css">`},
+		{"other sites are left alone", never, `<a href="https://other.com/post/">`, `<a href="https://other.com/post/">`},
+		{"protocol relative links are left alone", always, `<a href="//cdn.example.com/x">`, `<a href="//cdn.example.com/x">`},
+		{"under a base path", map[string]string{BasePathRefKey: "/docs", TrailingSlashRefKey: TrailingSlashNever}, `<a href="/docs/">`, `<a href="/docs/">`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeInternalLinks(tt.in, tt.params); got != tt.want {
+				t.Errorf("normalizeInternalLinks(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrailingSlashURLsAgree(t *testing.T) {
+	for _, tt := range []struct {
+		policy string
+		url    string
+		page   string
+	}{
+		{TrailingSlashAlways, "https://example.com/blog/hello-abc12345/", "blog/hello-abc12345/index.html"},
+		{TrailingSlashNever, "https://example.com/blog/hello-abc12345", "blog/hello-abc12345.html"},
+	} {
+		t.Run(tt.policy, func(t *testing.T) {
+			g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor(), assetsFS: os.DirFS("../../..")}
+			site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+			if err := g.workspace.CreateSiteDirectories(site.Slug); err != nil {
+				t.Fatal(err)
+			}
+			published := time.Now().Add(-time.Hour)
+			section := &Section{ID: uuid.New(), SiteID: site.ID, Name: "Blog", Path: "blog"}
+			content := &Content{ID: uuid.New(), SiteID: site.ID, SectionID: section.ID, SectionPath: "blog", ShortID: "abc12345",
+				Heading: "Hello", Body: "See [the blog](/blog/) and [the archive](/archive).", PublishedAt: &published}
+			params := []*Setting{
+				{RefKey: BaseURLRefKey, Value: "https://example.com"},
+				{RefKey: TrailingSlashRefKey, Value: tt.policy},
+			}
+
+			result, err := g.GenerateHTML(context.Background(), site, []*Content{content}, []*Section{section}, nil, nil, params, nil, nil, true)
+			if err != nil {
+				t.Fatalf("GenerateHTML() error = %v", err)
+			}
+			if len(result.Errors) > 0 {
+				t.Fatalf("GenerateHTML() errors = %v", result.Errors)
+			}
+
+			htmlPath := g.workspace.GetHTMLPath(site.Slug)
+			read := func(name string) string {
+				t.Helper()
+				data, err := os.ReadFile(filepath.Join(htmlPath, filepath.FromSlash(name)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return string(data)
+			}
+
+			page := read(tt.page)
+			if !strings.Contains(page, `<link rel="canonical" href="`+tt.url+`">`) {
+				t.Errorf("page has no canonical %s", tt.url)
+			}
+			if sitemap := read("sitemap.xml"); !strings.Contains(sitemap, "<loc>"+tt.url+"</loc>") {
+				t.Errorf("sitemap does not list %s:\n%s", tt.url, sitemap)
+			}
+			if feed := read("feed/atom.xml"); !strings.Contains(feed, `href="`+tt.url+`"`) {
+				t.Errorf("feed does not link %s:\n%s", tt.url, feed)
+			}
+
+			blog := `href="/blog/"`
+			if tt.policy == TrailingSlashNever {
+				blog = `href="/blog"`
+			}
+			if !strings.Contains(page, blog) {
+				t.Errorf("page body does not link the blog as %s", blog)
+			}
+		})
+	}
+}
+
+func TestTrailingSlashRedirectPages(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	htmlPath := g.workspace.GetHTMLPath("test")
+	params := map[string]string{TrailingSlashRefKey: TrailingSlashNever}
+
+	build := newBuildState(htmlPath, nil, "hash", true)
+	build.record(filepath.Join(htmlPath, "blog.html"), "", time.Time{})
+	build.record(filepath.Join(htmlPath, "index.html"), "", time.Time{})
+	build.record(filepath.Join(htmlPath, "about.html"), "", time.Time{})
+	build.record(filepath.Join(htmlPath, "about", "index.html"), "", time.Time{})
+
+	written, err := g.writeSlashRedirects(build, htmlPath, params)
+	if err != nil {
+		t.Fatalf("writeSlashRedirects() error = %v", err)
+	}
+	if written != 1 {
+		t.Errorf("written = %d, want 1", written)
+	}
+	data, err := os.ReadFile(filepath.Join(htmlPath, "blog", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "url=/blog\"") {
+		t.Errorf("redirect does not send /blog/ to /blog:\n%s", data)
+	}
+
+	if written, _ := g.writeSlashRedirects(build, htmlPath, nil); written != 0 {
+		t.Errorf("sites with trailing slashes got %d redirect pages", written)
+	}
+}
+
+func TestWriteRedirectsFile(t *testing.T) {
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir())}
+	htmlPath := g.workspace.GetHTMLPath("test")
+	if err := os.MkdirAll(htmlPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, link := uuid.New(), uuid.New()
+	build := newBuildState(htmlPath, nil, "hash", true)
+	build.setPermalinks(map[uuid.UUID]string{moved: "2024/post"}, map[string]uuid.UUID{"blog/post": moved})
+	pages := []*Content{{ID: link, KindInfo: &ContentKind{Redirect: true}, LinkURL: "https://other.com/x"}}
+	permalinks := map[uuid.UUID]string{moved: "2024/post", link: "links/x"}
+
+	for _, tt := range []struct {
+		name   string
+		params map[string]string
+		want   string
+	}{
+		{"defaults", nil, "/blog/post/ /2024/post/ 301\n/links/x/ https://other.com/x 301\n"},
+		{"temporary without trailing slashes", map[string]string{RedirectStatusRefKey: "302", TrailingSlashRefKey: TrailingSlashNever},
+			"/blog/post /2024/post 302\n/links/x https://other.com/x 302\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := g.writeRedirectsFile(build, htmlPath, g.redirectRules(build, pages, permalinks, tt.params), tt.params); err != nil {
+				t.Fatalf("writeRedirectsFile() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(htmlPath, redirectsFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("%s = %q, want %q", redirectsFile, data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package ssg
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LanguageRefKey is the setting holding the site's language, used for content
+// that does not set its own.
+const LanguageRefKey = "ssg.site.language"
+
+// DefaultLanguage is the site language when the setting is empty.
+const DefaultLanguage = "en"
+
+// ErrInvalidLang is returned for language codes that are not language tags.
+var ErrInvalidLang = errors.New("invalid language")
+
+var langRe = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// NormalizeLang checks a language tag such as en or pt-BR and returns it with
+// the language in lowercase and a two-letter region in uppercase. An empty
+// tag stays empty.
+func NormalizeLang(lang string) (string, error) {
+	lang = strings.TrimSpace(strings.ReplaceAll(lang, "_", "-"))
+	if lang == "" {
+		return "", nil
+	}
+	if !langRe.MatchString(lang) {
+		return "", fmt.Errorf("%w %q, use a code such as en or pt-BR", ErrInvalidLang, lang)
+	}
+	parts := strings.Split(lang, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		} else {
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return strings.Join(parts, "-"), nil
+}
+
+// siteLanguage returns the site's language. Empty or invalid values fall back
+// to DefaultLanguage; settings validation rejects invalid ones on save.
+func siteLanguage(params map[string]string) string {
+	lang, err := NormalizeLang(params[LanguageRefKey])
+	if err != nil || lang == "" {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+// contentLang returns the language of c, siteLang when it sets none.
+func contentLang(c *Content, siteLang string) string {
+	if c.Lang != "" {
+		return c.Lang
+	}
+	return siteLang
+}
+
+// linkTranslations sets Translations on every content of a translation group
+// to the other contents of the group in the list, sorted by language.
+func linkTranslations(contents []*Content) {
+	groups := make(map[string][]*Content)
+	for _, c := range contents {
+		c.Translations = nil
+		if c.TranslationGroup != "" {
+			groups[c.TranslationGroup] = append(groups[c.TranslationGroup], c)
+		}
+	}
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Lang < group[j].Lang })
+		for _, c := range group {
+			for _, other := range group {
+				if other != c {
+					c.Translations = append(c.Translations, other)
+				}
+			}
+		}
+	}
+}
+
+// TranslationLink is a language version of a page, linked from the page head
+// with rel="alternate" hreflang.
+type TranslationLink struct {
+	Lang    string
+	Heading string
+	URL     string
+	Current bool // the page itself
+}
+
+// translationLinks returns the language versions of a content page, the page
+// included, or nil when it has no translations.
+func (g *HTMLGenerator) translationLinks(rendered *RenderedContent, params map[string]string) []TranslationLink {
+	if len(rendered.Translations) == 0 {
+		return nil
+	}
+	siteLang := siteLanguage(params)
+	basePath := g.getAssetPath(params)
+	links := []TranslationLink{{
+		Lang:    contentLang(rendered.Content, siteLang),
+		Heading: rendered.Heading,
+		URL:     g.getAbsoluteURL(params, rendered.URL),
+		Current: true,
+	}}
+	for _, t := range rendered.Translations {
+		links = append(links, TranslationLink{
+			Lang:    contentLang(t, siteLang),
+			Heading: t.Heading,
+			URL:     g.getAbsoluteURL(params, g.getContentURL(t, basePath, params)),
+		})
+	}
+	sort.SliceStable(links, func(i, j int) bool { return links[i].Lang < links[j].Lang })
+	return links
+}
+
+// Lang returns the language of the page: the content's, the one of a
+// per-language index or the site's.
+func (d SSGPageData) Lang() string {
+	if d.Content != nil && d.Content.Content != nil && d.Content.Lang != "" {
+		return d.Content.Lang
+	}
+	if d.IndexLang != "" {
+		return d.IndexLang
+	}
+	return siteLanguage(d.Params)
+}
+
+// contentLanguages returns the languages of contents, sorted, when there is
+// more than one; nil otherwise.
+func contentLanguages(contents []*Content, siteLang string) []string {
+	seen := make(map[string]bool)
+	var langs []string
+	for _, c := range contents {
+		lang := contentLang(c, siteLang)
+		if !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) < 2 {
+		return nil
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// languageIndexPath is where the index of a language is generated, e.g. pt-br
+// for pt-BR.
+func languageIndexPath(lang string) string {
+	return strings.ToLower(lang)
+}
@@ -0,0 +1,110 @@
+package ssg
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeLang(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"en", "en", false},
+		{" ES ", "es", false},
+		{"pt-br", "pt-BR", false},
+		{"pt_BR", "pt-BR", false},
+		{"zh-Hant-TW", "zh-hant-TW", false},
+		{"english", "", true},
+		{"e", "", true},
+		{"en-", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeLang(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeLang(%q) = %q, %v, want %q, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLinkTranslations(t *testing.T) {
+	en := &Content{Heading: "Hello", Lang: "en", TranslationGroup: "g1"}
+	es := &Content{Heading: "Hola", Lang: "es", TranslationGroup: "g1"}
+	de := &Content{Heading: "Hallo", Lang: "de", TranslationGroup: "g1"}
+	alone := &Content{Heading: "Alone", TranslationGroup: "g2"}
+	other := &Content{Heading: "Other"}
+
+	linkTranslations([]*Content{en, es, alone, other, de})
+	if len(en.Translations) != 2 || en.Translations[0] != de || en.Translations[1] != es {
+		t.Errorf("en translations = %v, want de and es", en.Translations)
+	}
+	if len(es.Translations) != 2 || len(de.Translations) != 2 {
+		t.Error("every member of a group should link to the others")
+	}
+	if alone.Translations != nil || other.Translations != nil {
+		t.Error("content without other group members should have no translations")
+	}
+}
+
+func TestContentLanguages(t *testing.T) {
+	contents := []*Content{{Lang: "es"}, {}, {Lang: "en"}}
+	if got := contentLanguages(contents, "en"); len(got) != 2 || got[0] != "en" || got[1] != "es" {
+		t.Errorf("contentLanguages() = %v, want [en es]", got)
+	}
+	if got := contentLanguages(contents[1:], "en"); got != nil {
+		t.Errorf("contentLanguages() of one language = %v, want nil", got)
+	}
+}
+
+func TestRenderTranslations(t *testing.T) {
+	tmpl := parseSiteTemplatesFromDisk(t)
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "Test", Slug: "test"}
+	params := map[string]string{BaseURLRefKey: "https://example.com", LanguageRefKey: "en"}
+	published := time.Now().Add(-time.Hour)
+
+	en := &Content{ID: uuid.New(), ShortID: "aaa111", Heading: "Hello", SectionPath: "blog", TranslationGroup: "g1", PublishedAt: &published}
+	es := &Content{ID: uuid.New(), ShortID: "bbb222", Heading: "Hola", SectionPath: "blog", Lang: "es", TranslationGroup: "g1", PublishedAt: &published}
+	linkTranslations([]*Content{en, es})
+
+	rendered := &RenderedContent{Content: es, URL: g.getContentURL(es, "/", params)}
+	if _, err := g.renderContentPage(tmpl, nil, newBuildState(g.workspace.GetHTMLPath(site.Slug), nil, "", true), g.workspace.GetHTMLPath(site.Slug), site, es, rendered, adjacentLinks{}, nil, nil, params, nil, BlocksConfig{}); err != nil {
+		t.Fatalf("renderContentPage() error = %v", err)
+	}
+	data, err := os.ReadFile(g.workspace.GetPageHTMLPath(site.Slug, "blog/hola-bbb222"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		`<html lang="es">`,
+		`<link rel="alternate" hreflang="en" href="https://example.com/blog/hello-aaa111/">`,
+		`<link rel="alternate" hreflang="es" href="https://example.com/blog/hola-bbb222/">`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page should contain %s:\n%s", want, page)
+		}
+	}
+
+	count, _, err := g.renderLanguageIndexes(tmpl, nil, newBuildState(g.workspace.GetHTMLPath(site.Slug), nil, "", true), site, []*Content{en, es}, nil, nil, params, 10)
+	if err != nil || count != 2 {
+		t.Fatalf("renderLanguageIndexes() = %d, %v, want 2 indexes", count, err)
+	}
+	data, err = os.ReadFile(g.workspace.GetIndexHTMLPath(site.Slug, "es"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := string(data)
+	if !strings.Contains(index, `<html lang="es">`) || !strings.Contains(index, "Hola") || strings.Contains(index, "Hello") {
+		t.Errorf("the es index should list only the es content:\n%s", index)
+	}
+	if !strings.Contains(index, `<link rel="alternate" hreflang="en" href="https://example.com/en/">`) {
+		t.Errorf("language indexes should link each other:\n%s", index)
+	}
+}
@@ -0,0 +1,39 @@
+package ssg
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UpdatedMinHoursRefKey is the setting holding how many hours after publishing
+// an edit must come for a content page to show it as an update. Edits made
+// sooner, fixing a typo right after publishing say, are not worth a line.
+const UpdatedMinHoursRefKey = "ssg.updated.min_hours"
+
+// DefaultUpdatedMinHours applies to sites without ssg.updated.min_hours.
+const DefaultUpdatedMinHours = 24
+
+// updatedMinDelta returns the ssg.updated.min_hours setting as a duration.
+func updatedMinDelta(params map[string]string) time.Duration {
+	hours, err := strconv.Atoi(strings.TrimSpace(params[UpdatedMinHoursRefKey]))
+	if err != nil || hours < 0 {
+		hours = DefaultUpdatedMinHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// updatedDate returns when content was last updated if its page shows it: the
+// content opts in with Meta.ShowUpdated, is published and was updated at
+// least ssg.updated.min_hours later. Otherwise it returns nil.
+func updatedDate(content *Content, params map[string]string) *time.Time {
+	if content.Meta == nil || !content.Meta.ShowUpdated || content.PublishedAt == nil {
+		return nil
+	}
+	delta := content.UpdatedAt.Sub(*content.PublishedAt)
+	if delta <= 0 || delta < updatedMinDelta(params) {
+		return nil
+	}
+	updated := content.UpdatedAt
+	return &updated
+}
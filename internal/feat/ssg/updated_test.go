@@ -0,0 +1,94 @@
+package ssg
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdatedDate(t *testing.T) {
+	published := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	content := func(showUpdated bool, updatedAfter time.Duration) *Content {
+		return &Content{
+			PublishedAt: &published,
+			UpdatedAt:   published.Add(updatedAfter),
+			Meta:        &Meta{ShowUpdated: showUpdated},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		content *Content
+		params  map[string]string
+		want    bool
+	}{
+		{"updated past the default delta", content(true, 25*time.Hour), nil, true},
+		{"updated within the default delta", content(true, 23*time.Hour), nil, false},
+		{"updated exactly at the delta", content(true, 24*time.Hour), nil, true},
+		{"not opted in", content(false, 30*24*time.Hour), nil, false},
+		{"no meta", &Content{PublishedAt: &published, UpdatedAt: published.Add(48 * time.Hour)}, nil, false},
+		{"not published", &Content{UpdatedAt: published, Meta: &Meta{ShowUpdated: true}}, nil, false},
+		{"site delta", content(true, 3*time.Hour), map[string]string{UpdatedMinHoursRefKey: "2"}, true},
+		{"within site delta", content(true, 3*time.Hour), map[string]string{UpdatedMinHoursRefKey: "72"}, false},
+		{"zero delta shows any later edit", content(true, time.Minute), map[string]string{UpdatedMinHoursRefKey: "0"}, true},
+		{"zero delta, never edited", content(true, 0), map[string]string{UpdatedMinHoursRefKey: "0"}, false},
+		{"invalid delta uses the default", content(true, 3*time.Hour), map[string]string{UpdatedMinHoursRefKey: "soon"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := updatedDate(tt.content, tt.params)
+			if (got != nil) != tt.want {
+				t.Fatalf("updatedDate() = %v, want shown %v", got, tt.want)
+			}
+			if got != nil && !got.Equal(tt.content.UpdatedAt) {
+				t.Errorf("updatedDate() = %v, want %v", got, tt.content.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestContentPageUpdatedDate(t *testing.T) {
+	tmpl := parseSiteTemplatesFromDisk(t)
+	g := &HTMLGenerator{workspace: NewWorkspace(t.TempDir()), processor: NewProcessor()}
+	site := &Site{ID: uuid.New(), Name: "My Blog", Slug: "my-blog"}
+	published := time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)
+	content := &Content{ID: uuid.New(), SiteID: site.ID, SectionPath: "blog", ShortID: "abc123", Heading: "Post", Body: "Text",
+		PublishedAt: &published, UpdatedAt: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC), Meta: &Meta{ShowUpdated: true}}
+	params := map[string]string{}
+
+	render := func() string {
+		t.Helper()
+		htmlPath := g.workspace.GetHTMLPath(site.Slug)
+		if _, err := g.renderContentPage(tmpl, nil, nil, htmlPath, site, content, nil, adjacentLinks{}, nil, nil, params, nil, BlocksConfig{}); err != nil {
+			t.Fatalf("renderContentPage() error = %v", err)
+		}
+		data, err := os.ReadFile(g.workspace.GetPageHTMLPath(site.Slug, permalinkPattern(params).contentPath(content)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	page := render()
+	for _, want := range []string{
+		`Updated <time datetime="2024-05-01">`,
+		`"datePublished": "2024-03-07T10:00:00Z"`,
+		`"dateModified": "2024-05-01T09:30:00Z"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page has no %s", want)
+		}
+	}
+
+	content.Meta.ShowUpdated = false
+	page = render()
+	if strings.Contains(page, "Updated <time") || strings.Contains(page, "dateModified") {
+		t.Errorf("page shows the update of content not opted in")
+	}
+	if !strings.Contains(page, `"datePublished": "2024-03-07T10:00:00Z"`) {
+		t.Errorf("page has no datePublished")
+	}
+}
@@ -1,20 +1,35 @@
 package ssg
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Default workspace paths
 const (
 	DefaultSitesBasePath    = "_workspace/sites"
 	DefaultProfilesBasePath = "_workspace/profiles"
+	DefaultOutputDir        = "html"
 )
 
+// sourceDirs are the site workspace directories holding sources rather than
+// generated output. The output directory can never be one of them, so
+// cleaning it cannot reach the images or markdown.
+var sourceDirs = map[string]bool{
+	"markdown": true,
+	"images":   true,
+	"meta":     true,
+	"profiles": true,
+	"themes":   true,
+}
+
 // Workspace handles site directory operations.
 type Workspace struct {
-	basePath string
+	basePath  string
+	outputDir string
 }
 
 // NewWorkspace creates a new workspace manager.
@@ -22,7 +37,29 @@ func NewWorkspace(basePath string) *Workspace {
 	if basePath == "" {
 		basePath = DefaultSitesBasePath
 	}
-	return &Workspace{basePath: basePath}
+	return &Workspace{basePath: basePath, outputDir: DefaultOutputDir}
+}
+
+// ValidateOutputDir checks an output directory name: a single directory
+// inside the site workspace, other than the source directories.
+func ValidateOutputDir(dir string) error {
+	switch {
+	case dir == "":
+		return errors.New("output directory is empty")
+	case dir == "." || dir == ".." || strings.ContainsAny(dir, `/\`):
+		return fmt.Errorf("output directory %q must be a single directory name", dir)
+	case sourceDirs[dir]:
+		return fmt.Errorf("output directory %q is reserved for site sources", dir)
+	}
+	return nil
+}
+
+// SetOutputDir sets the site workspace subdirectory generated HTML is
+// written to. Invalid names, checked on startup, keep the default.
+func (w *Workspace) SetOutputDir(dir string) {
+	if ValidateOutputDir(dir) == nil {
+		w.outputDir = dir
+	}
 }
 
 // CreateSiteDirectories creates the directory structure for a site.
@@ -30,7 +67,7 @@ func NewWorkspace(basePath string) *Workspace {
 //
 //	_workspace/sites/{slug}/
 //	├── markdown/      # Archivos .md generados
-//	├── html/          # Archivos .html generados
+//	├── html/          # Archivos .html generados (ssg.output_dir)
 //	└── images/        # Imágenes del site
 func (w *Workspace) CreateSiteDirectories(slug string) error {
 	dirs := []string{
@@ -81,7 +118,7 @@ func (w *Workspace) GetMarkdownPath(slug string) string {
 // GetHTMLPath returns the HTML output path for a specific site.
 // e.g., _workspace/sites/my-blog/html
 func (w *Workspace) GetHTMLPath(slug string) string {
-	return filepath.Join(w.basePath, slug, "html")
+	return filepath.Join(w.basePath, slug, w.outputDir)
 }
 
 // GetImagesPath returns the images path for a specific site.
@@ -96,6 +133,33 @@ func (w *Workspace) GetMetaPath(slug string) string {
 	return filepath.Join(w.basePath, slug, "meta")
 }
 
+// GetThemesPath returns where the themes uploaded to a site are kept, one
+// directory per theme.
+// e.g., _workspace/sites/my-blog/themes
+func (w *Workspace) GetThemesPath(slug string) string {
+	return filepath.Join(w.basePath, slug, "themes")
+}
+
+// GetBuildManifestPath returns the path of the incremental build manifest.
+// It lives outside the HTML output so it is never published.
+// e.g., _workspace/sites/my-blog/build-manifest.json
+func (w *Workspace) GetBuildManifestPath(slug string) string {
+	return filepath.Join(w.GetSiteBasePath(slug), "build-manifest.json")
+}
+
+// GetAssetManifestPath returns where the fingerprinted asset names of the
+// last generation are recorded.
+func (w *Workspace) GetAssetManifestPath(slug string) string {
+	return filepath.Join(w.GetSiteBasePath(slug), "asset-manifest.json")
+}
+
+// GetOGImageCachePath returns where generated social images are kept
+// between builds, so they are not drawn again.
+// e.g., _workspace/sites/my-blog/og-cache
+func (w *Workspace) GetOGImageCachePath(slug string) string {
+	return filepath.Join(w.GetSiteBasePath(slug), "og-cache")
+}
+
 // GetProfilesPath returns the global profiles path.
 func (w *Workspace) GetProfilesPath() string {
 	return DefaultProfilesBasePath
@@ -123,6 +187,12 @@ func (w *Workspace) GetContentHTMLPath(slug, sectionPath, contentSlug string) st
 	return filepath.Join(w.GetHTMLPath(slug), sectionPath, contentSlug, "index.html")
 }
 
+// GetPageHTMLPath returns the path for the HTML file of a page served at pagePath.
+// e.g., _workspace/sites/my-blog/html/2024/05/my-post/index.html
+func (w *Workspace) GetPageHTMLPath(slug, pagePath string) string {
+	return filepath.Join(w.GetHTMLPath(slug), filepath.FromSlash(pagePath), "index.html")
+}
+
 // GetIndexHTMLPath returns the path for an index HTML file.
 // e.g., _workspace/sites/my-blog/html/posts/index.html
 func (w *Workspace) GetIndexHTMLPath(slug, path string) string {
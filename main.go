@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"embed"
+	"flag"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/cliossg/clio/internal/feat/api"
@@ -19,6 +22,8 @@ import (
 	"github.com/cliossg/clio/pkg/cl/git"
 	"github.com/cliossg/clio/pkg/cl/llm"
 	"github.com/cliossg/clio/pkg/cl/logger"
+	"github.com/cliossg/clio/pkg/cl/mail"
+	"github.com/cliossg/clio/pkg/cl/metrics"
 	"github.com/cliossg/clio/pkg/cl/middleware"
 	"github.com/go-chi/chi/v5"
 )
@@ -27,25 +32,71 @@ import (
 var assetsFS embed.FS
 
 func main() {
+	regenerateAll := flag.Bool("regenerate-all", false, "regenerate the HTML of every active site and exit")
+	flag.Parse()
+
 	ctx := context.Background()
 
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := ssg.LoadTimezone(cfg.SSG.Timezone); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: ssg.timezone: %v\n", err)
+		os.Exit(1)
+	}
+	if err := ssg.ValidateOutputDir(cfg.SSG.OutputDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: ssg.output_dir: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := ssg.ParseCacheMaxAge(cfg.SSG.Preview); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: ssg.preview.cache_max_age: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.Auth.Password.MinLength < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: auth.password.min_length: must not be negative\n")
+		os.Exit(1)
+	}
+	if _, err := auth.ParseLockout(cfg.Auth.Lockout); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: auth.lockout: %v\n", err)
+		os.Exit(1)
+	}
+	cleanupInterval, err := auth.ParseCleanupInterval(cfg.Auth.CleanupInterval)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: auth.cleanup_interval: %v\n", err)
+		os.Exit(1)
+	}
 	log := logger.New(cfg.Log.Level)
 
 	log.Infof("Starting Clio [%s mode]", cfg.Env)
 	log.Infof("Database: %s", cfg.Database.Path)
 	log.Infof("Sites: %s", cfg.SSG.SitesBasePath)
+	log.Debugf("Config:\n%s", cfg)
 
 	db := database.New(assetsFS, cfg, log)
 	db.SetMigrationPath("assets/migrations/sqlite")
 
+	var metricsRegistry *metrics.Registry
+	if cfg.Metrics.Enabled {
+		metricsRegistry = metrics.NewRegistry()
+		db.SetMetrics(metricsRegistry)
+	}
+
 	authService := auth.NewService(db, cfg, log)
 	profileService := profile.NewService(db, cfg, log)
 	ssgWorkspace := ssg.NewWorkspace(cfg.SSG.SitesBasePath)
+	ssgWorkspace.SetOutputDir(cfg.SSG.OutputDir)
 	ssgHTMLGen := ssg.NewHTMLGenerator(ssgWorkspace, assetsFS)
+	setGenerationLimits(ssgHTMLGen, cfg.SSG)
+	ssgHTMLGen.SetTimezone(cfg.SSG.Timezone)
 	ssgService := ssg.NewService(db, ssgHTMLGen, cfg, log)
 	gitClient := git.NewClient(log)
 	ssgPublisher := ssg.NewPublisher(ssgWorkspace, gitClient)
+	if metricsRegistry != nil {
+		ssgHTMLGen.SetMetrics(metricsRegistry)
+		ssgPublisher.SetMetrics(metricsRegistry)
+	}
 	llmClient := llm.NewClient(cfg.LLM.APIKey, cfg.LLM.Model, cfg.LLM.Temperature)
 
 	optionalSessionMw := middleware.OptionalSession(authService)
@@ -56,6 +107,10 @@ func main() {
 	profileHandler := profile.NewHandler(profileService, authService, requiredSessionMw, assetsFS, cfg, log)
 	ssgHandler := ssg.NewHandler(ssgService, profileService, ssgWorkspace, ssgHTMLGen, ssgPublisher, llmClient, siteCtxMw, requiredSessionMw, assetsFS, cfg, log)
 	previewServer := ssg.NewPreviewServer(ssgService, cfg, log)
+	previewServer.SetAuthFunc(func(ctx context.Context, email, password string) error {
+		_, err := authService.Authenticate(ctx, email, password)
+		return err
+	})
 
 	authSeeder := auth.NewSeeder(authService, profileService, assetsFS, log)
 	if cfg.Credentials.Path != "" {
@@ -64,9 +119,22 @@ func main() {
 
 	ssgSeeder := ssg.NewSeeder(ssgService, profileService, log)
 	ssgScheduler := ssg.NewScheduler(ssgService, ssgHTMLGen, ssgPublisher, log)
+	ssgHandler.SetScheduler(ssgScheduler)
+	ssgPublishQueue := ssg.NewPublishQueue(ssgService, ssgHTMLGen, ssgPublisher, log)
+	ssgHandler.SetPublishQueue(ssgPublishQueue)
+	ssgHandler.SetMailer(mail.New(cfg.Mail, log))
+	ssgDraftReminder := ssg.NewDraftReminder(ssgService, log)
+
+	if *regenerateAll {
+		os.Exit(runRegenerateAll(ctx, db, ssgService, log))
+	}
 
 	apiService := api.NewService(db, cfg, log)
 	apiTokenMw := api.TokenAuth(apiService)
+
+	janitor := auth.NewJanitor(cleanupInterval, log)
+	janitor.Add("sessions", authService.PurgeExpiredSessions)
+	janitor.Add("API tokens", apiService.PurgeExpiredTokens)
 	apiHandler := api.NewHandler(apiService, ssgService, ssgWorkspace, ssgHTMLGen, ssgPublisher, apiTokenMw, requiredSessionMw, assetsFS, cfg, log)
 
 	formsService := forms.NewService(db, cfg, log)
@@ -75,10 +143,15 @@ func main() {
 
 	router := chi.NewRouter()
 	middleware.DefaultStack(router)
+	if metricsRegistry != nil {
+		router.Use(middleware.HTTPMetrics(metricsRegistry))
+		router.Handle("/metrics", metricsRegistry.Handler())
+		log.Info("Metrics served at /metrics")
+	}
 
 	fileServer := web.NewFileServer(assetsFS, log)
 
-	deps := []any{db, authService, profileService, ssgService, apiService, formsService, authSeeder, ssgSeeder, ssgScheduler, authHandler, profileHandler, ssgHandler, apiHandler, formsHandler, previewServer, fileServer}
+	deps := []any{db, authService, profileService, ssgService, apiService, formsService, authSeeder, ssgSeeder, ssgScheduler, ssgPublishQueue, ssgDraftReminder, janitor, authHandler, profileHandler, ssgHandler, apiHandler, formsHandler, previewServer, fileServer}
 
 	starts, stops, registrars := app.Setup(ctx, router, deps...)
 	if err := app.Start(ctx, log, starts, stops, registrars, router); err != nil {
@@ -89,6 +162,19 @@ func main() {
 	go app.Serve(router, cfg.Server.Addr)
 	log.Infof("Server listening on %s", cfg.Server.Addr)
 
+	config.Watch(ctx, cfg, func(reload config.Reload) {
+		if reload.Err != nil {
+			log.Errorf("Config reload failed, keeping current settings: %v", reload.Err)
+			return
+		}
+		log.SetLevel(reload.Config.Log.Level)
+		setGenerationLimits(ssgHTMLGen, reload.Config.SSG)
+		log.Infof("Config reloaded: log level %s, SSG workers %d, image workers %d", reload.Config.Log.Level, reload.Config.SSG.Workers, reload.Config.SSG.ImageWorkers)
+		if len(reload.Ignored) > 0 {
+			log.Infof("Config changes ignored until restart: %s", strings.Join(reload.Ignored, ", "))
+		}
+	})
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -96,3 +182,44 @@ func main() {
 	app.Stop(ctx, log, stops)
 	log.Info("Server stopped")
 }
+
+// setGenerationLimits applies the SSG config limits to the generator. It runs
+// on startup and on every config reload.
+func setGenerationLimits(gen *ssg.HTMLGenerator, cfg config.SSGConfig) {
+	gen.SetWorkers(cfg.Workers)
+	gen.SetImageWorkers(cfg.ImageWorkers)
+	gen.SetPageBufferSize(cfg.PageBufferKB << 10)
+	gen.SetMemoryBudget(uint64(max(cfg.MemoryBudget, 0)) << 20)
+}
+
+// runRegenerateAll rebuilds every active site without starting the server,
+// prints a summary and returns the process exit code.
+func runRegenerateAll(ctx context.Context, db *database.Database, ssgService ssg.Service, log logger.Logger) int {
+	if err := db.Start(ctx); err != nil {
+		log.Errorf("Cannot open database: %v", err)
+		return 1
+	}
+	defer db.Stop(ctx)
+
+	results, err := ssgService.GenerateAllSites(ctx)
+	if err != nil {
+		log.Errorf("Cannot regenerate sites: %v", err)
+		return 1
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("FAIL  %s (%s): %v\n", r.Site.Slug, r.Duration, r.Err)
+			continue
+		}
+		fmt.Printf("ok    %s (%s): %d pages, %d errors\n", r.Site.Slug, r.Duration, r.Result.PagesGenerated, len(r.Result.Errors))
+	}
+	fmt.Printf("%d sites regenerated, %d failed\n", len(results)-failed, failed)
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,6 +22,8 @@ type Config struct {
 	SSG         SSGConfig         `yaml:"ssg"`
 	Credentials CredentialsConfig `yaml:"credentials"`
 	LLM         LLMConfig         `yaml:"llm"`
+	Mail        MailConfig        `yaml:"mail"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
 }
 
 func (c *Config) IsDev() bool {
@@ -35,13 +43,51 @@ type LogConfig struct {
 }
 
 type AuthConfig struct {
-	SessionSecret string `yaml:"session_secret"`
-	SessionTTL    string `yaml:"session_ttl"`
+	SessionSecret   string         `yaml:"session_secret" secret:"true"`
+	SessionTTL      string         `yaml:"session_ttl"`
+	CleanupInterval string         `yaml:"cleanup_interval"` // how often expired sessions and API tokens are deleted
+	Password        PasswordConfig `yaml:"password"`
+	Lockout         LockoutConfig  `yaml:"lockout"`
+}
+
+// PasswordConfig is the policy new passwords must meet. The zero value
+// accepts any password.
+type PasswordConfig struct {
+	MinLength    int  `yaml:"min_length"`    // in characters
+	RequireMixed bool `yaml:"require_mixed"` // three of lowercase, uppercase, digits and symbols
+	RejectCommon bool `yaml:"reject_common"` // refuse passwords from the built-in list of common ones
+}
+
+// LockoutConfig locks an account for Duration after MaxFailures failed sign
+// ins within Window. MaxFailures 0 turns lockout off.
+type LockoutConfig struct {
+	MaxFailures int    `yaml:"max_failures"`
+	Window      string `yaml:"window"`
+	Duration    string `yaml:"duration"`
 }
 
 type SSGConfig struct {
-	SitesBasePath string `yaml:"sites_base_path"`
-	PreviewAddr   string `yaml:"preview_addr"`
+	SitesBasePath string        `yaml:"sites_base_path"`
+	PreviewAddr   string        `yaml:"preview_addr"`
+	Workers       int           `yaml:"workers"`                  // parallel page renderers; 0 = GOMAXPROCS, 1 = sequential
+	ImageWorkers  int           `yaml:"image_workers"`            // social images drawn at once; 0 = workers
+	PageBufferKB  int           `yaml:"page_buffer_kb"`           // memory per page before it streams to disk; 0 = 64
+	MemoryBudget  int           `yaml:"memory_budget_mb"`         // heap MB above which generation warns; 0 = off
+	Timezone      string        `yaml:"timezone"`                 // IANA zone for sites without ssg.site.timezone; empty = UTC
+	OutputDir     string        `yaml:"output_dir"`               // subdirectory of each site workspace that generated HTML goes to
+	PhotoSize     int           `yaml:"photo_size"`               // side in pixels of square profile photos; 0 = 400
+	SecretKey     string        `yaml:"secret_key" secret:"true"` // encrypts secret settings in the database; empty = secrets cannot be saved
+	Preview       PreviewConfig `yaml:"preview"`
+}
+
+type PreviewConfig struct {
+	// RequireAuth puts the preview server behind HTTP basic auth with Clio
+	// user credentials. Authenticated previews also render draft and scheduled content.
+	RequireAuth bool `yaml:"require_auth"`
+	// CacheMaxAge is how long browsers reuse preview images and assets before
+	// revalidating them, as a duration. Pages are always revalidated and
+	// fingerprinted assets are cached for a year.
+	CacheMaxAge string `yaml:"cache_max_age"`
 }
 
 type CredentialsConfig struct {
@@ -49,15 +95,49 @@ type CredentialsConfig struct {
 }
 
 type LLMConfig struct {
-	Provider    string  `yaml:"provider"`    // "openai" (default)
-	APIKey      string  `yaml:"api_key"`
+	Provider    string  `yaml:"provider"` // "openai" (default)
+	APIKey      string  `yaml:"api_key" secret:"true"`
 	Model       string  `yaml:"model"`       // default: "gpt-4o"
 	Temperature float64 `yaml:"temperature"` // default: 0.3
 }
 
-func Load() *Config {
+// MailConfig is the SMTP server notification emails are sent through. With
+// no Host, emails are written to the log instead.
+type MailConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"` // default: 587
+	Username string `yaml:"username"`
+	Password string `yaml:"password" secret:"true"`
+	From     string `yaml:"from"` // sender address, e.g. "Clio <clio@example.com>"
+}
+
+// MetricsConfig controls the Prometheus endpoint. When Enabled, request,
+// generation, publish and database query metrics are served at /metrics.
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// EnvPrefix prefixes the environment variables that override config fields.
+// The variable name is the prefix followed by the field's YAML path in upper
+// case, joined by underscores: server.addr is CLIO_SERVER_ADDR.
+const EnvPrefix = "CLIO_"
+
+// envAliases are legacy variable names kept for existing deployments.
+// The canonical name takes precedence when both are set.
+var envAliases = map[string]string{
+	"CLIO_SSG_SITES_BASE_PATH": "CLIO_SSG_SITES_PATH",
+}
+
+// Load builds the configuration from defaults, then config.yaml, then
+// environment variables, each taking precedence over the previous one.
+// It fails when the file or an environment variable holds a malformed value.
+func Load() (*Config, error) {
+	return load("config.yaml", os.Getenv)
+}
+
+func load(path string, getenv func(string) string) (*Config, error) {
 	// Determine environment first
-	env := os.Getenv("CLIO_ENV")
+	env := getenv("CLIO_ENV")
 	if env == "" {
 		env = "dev" // Default to dev for safety
 	}
@@ -80,47 +160,142 @@ func Load() *Config {
 		Server:   ServerConfig{Addr: ":8080"},
 		Database: DatabaseConfig{Path: dbPath},
 		Log:      LogConfig{Level: "info"},
-		Auth:     AuthConfig{SessionTTL: "720h"}, // 30 days
-		SSG:      SSGConfig{SitesBasePath: sitesPath, PreviewAddr: ":3000"},
-		LLM:      LLMConfig{Provider: "openai", Model: "gpt-4o", Temperature: 0.3},
+		Auth: AuthConfig{
+			SessionTTL:      "720h", // 30 day sessions
+			CleanupInterval: "1h",
+			Password:        PasswordConfig{MinLength: 10, RequireMixed: true, RejectCommon: true},
+			Lockout:         LockoutConfig{MaxFailures: 5, Window: "15m", Duration: "15m"},
+		},
+		SSG:  SSGConfig{SitesBasePath: sitesPath, PreviewAddr: ":3000", OutputDir: "html", Preview: PreviewConfig{CacheMaxAge: "1m"}},
+		LLM:  LLMConfig{Provider: "openai", Model: "gpt-4o", Temperature: 0.3},
+		Mail: MailConfig{Port: 587},
 	}
 
-	data, err := os.ReadFile("config.yaml")
+	data, err := os.ReadFile(path)
 	if err == nil {
-		yaml.Unmarshal(data, cfg)
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+		}
 	}
 
-	// Environment overrides (highest priority)
-	if v := os.Getenv("CLIO_SERVER_ADDR"); v != "" {
-		cfg.Server.Addr = v
-	}
-	if v := os.Getenv("CLIO_DATABASE_PATH"); v != "" {
-		cfg.Database.Path = v
-	}
-	if v := os.Getenv("CLIO_LOG_LEVEL"); v != "" {
-		cfg.Log.Level = v
-	}
-	if v := os.Getenv("CLIO_AUTH_SESSION_SECRET"); v != "" {
-		cfg.Auth.SessionSecret = v
-	}
-	if v := os.Getenv("CLIO_CREDENTIALS_PATH"); v != "" {
-		cfg.Credentials.Path = v
+	// The shared OpenAI variable only fills a key missing from the file
+	if v := getenv("OPENAI_API_KEY"); v != "" && cfg.LLM.APIKey == "" {
+		cfg.LLM.APIKey = v
 	}
-	if v := os.Getenv("CLIO_SSG_SITES_PATH"); v != "" {
-		cfg.SSG.SitesBasePath = v
+
+	// Environment overrides (highest priority)
+	if err := applyEnv(reflect.ValueOf(cfg).Elem(), strings.TrimSuffix(EnvPrefix, "_"), getenv); err != nil {
+		return nil, err
 	}
-	if v := os.Getenv("CLIO_SSG_PREVIEW_ADDR"); v != "" {
-		cfg.SSG.PreviewAddr = v
+
+	return cfg, nil
+}
+
+// applyEnv walks the config struct and sets every field whose environment
+// variable is present. All malformed values are reported together.
+func applyEnv(v reflect.Value, prefix string, getenv func(string) string) error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(v.Field(i), name, getenv); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		raw := getenv(name)
+		if raw == "" {
+			if alias, ok := envAliases[name]; ok {
+				name, raw = alias, getenv(alias)
+			}
+		}
+		if raw == "" {
+			continue
+		}
+		if err := setField(v.Field(i), raw); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %w", name, err))
+		}
 	}
-	if v := os.Getenv("OPENAI_API_KEY"); v != "" && cfg.LLM.APIKey == "" {
-		cfg.LLM.APIKey = v
+	return errors.Join(errs...)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setField(f reflect.Value, raw string) error {
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration (e.g. 30s, 12h)", raw)
+		}
+		f.SetInt(int64(d))
+		return nil
 	}
-	if v := os.Getenv("CLIO_LLM_API_KEY"); v != "" {
-		cfg.LLM.APIKey = v
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		f.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		f.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean (true or false)", raw)
+		}
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
 	}
-	if v := os.Getenv("CLIO_LLM_MODEL"); v != "" {
-		cfg.LLM.Model = v
+	return nil
+}
+
+// Redacted returns a copy of the config with secrets masked, safe to log.
+// Fields tagged `secret:"true"` are masked when set.
+func (c *Config) Redacted() Config {
+	r := *c
+	redact(reflect.ValueOf(&r).Elem())
+	return r
+}
+
+// String renders the redacted config, so printing a Config never leaks secrets.
+func (c *Config) String() string {
+	r := c.Redacted()
+	data, err := yaml.Marshal(&r)
+	if err != nil {
+		return fmt.Sprintf("config: %v", err)
 	}
+	return string(data)
+}
 
-	return cfg
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() == reflect.Struct {
+			redact(f)
+			continue
+		}
+		if t.Field(i).Tag.Get("secret") == "true" && f.Kind() == reflect.String && f.String() != "" {
+			f.SetString(redactedValue)
+		}
+	}
 }
+
+const redactedValue = "[redacted]"
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envOf(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  addr: ":9000"
+log:
+  level: debug
+ssg:
+  workers: 2
+llm:
+  model: file-model
+`)
+
+	cfg, err := load(path, envOf(map[string]string{
+		"CLIO_SERVER_ADDR":     ":7000",
+		"CLIO_SSG_WORKERS":     "8",
+		"CLIO_LLM_TEMPERATURE": "0.9",
+		"CLIO_LLM_API_KEY":     "sk-env",
+		"OPENAI_API_KEY":       "sk-openai",
+		"CLIO_SSG_SITES_PATH":  "/legacy/sites",
+	}))
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"env overrides file", cfg.Server.Addr, ":7000"},
+		{"env overrides file int", cfg.SSG.Workers, 8},
+		{"file overrides default", cfg.Log.Level, "debug"},
+		{"file kept without env", cfg.LLM.Model, "file-model"},
+		{"env overrides default float", cfg.LLM.Temperature, 0.9},
+		{"prefixed key wins over shared key", cfg.LLM.APIKey, "sk-env"},
+		{"legacy alias", cfg.SSG.SitesBasePath, "/legacy/sites"},
+		{"default kept", cfg.SSG.PreviewAddr, ":3000"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	cfg, _ = load(path, envOf(map[string]string{
+		"CLIO_SSG_SITES_PATH":      "/legacy/sites",
+		"CLIO_SSG_SITES_BASE_PATH": "/canonical/sites",
+	}))
+	if cfg.SSG.SitesBasePath != "/canonical/sites" {
+		t.Errorf("canonical name should win over alias, got %q", cfg.SSG.SitesBasePath)
+	}
+}
+
+func TestLoadMalformedValues(t *testing.T) {
+	_, err := load(filepath.Join(t.TempDir(), "missing.yaml"), envOf(map[string]string{
+		"CLIO_SSG_WORKERS":     "many",
+		"CLIO_LLM_TEMPERATURE": "warm",
+	}))
+	if err == nil {
+		t.Fatal("expected error for malformed values")
+	}
+	for _, want := range []string{`CLIO_SSG_WORKERS: "many" is not an integer`, `CLIO_LLM_TEMPERATURE: "warm" is not a number`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	if _, err := load(writeConfigFile(t, "server: [unclosed"), envOf(nil)); err == nil {
+		t.Error("expected error for malformed config file")
+	}
+}
+
+func TestSetFieldTypes(t *testing.T) {
+	var s struct {
+		Enabled bool
+		Timeout time.Duration
+	}
+	v := reflect.ValueOf(&s).Elem()
+
+	if err := setField(v.Field(0), "true"); err != nil || !s.Enabled {
+		t.Errorf("bool: got %v, err %v", s.Enabled, err)
+	}
+	if err := setField(v.Field(0), "yes please"); err == nil {
+		t.Error("expected error for malformed bool")
+	}
+	if err := setField(v.Field(1), "90s"); err != nil || s.Timeout != 90*time.Second {
+		t.Errorf("duration: got %v, err %v", s.Timeout, err)
+	}
+	if err := setField(v.Field(1), "soon"); err == nil {
+		t.Error("expected error for malformed duration")
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Addr: ":8080"},
+		Auth:   AuthConfig{SessionSecret: "s3cret"},
+		LLM:    LLMConfig{APIKey: "sk-live", Model: "gpt-4o"},
+	}
+
+	for _, out := range []string{cfg.String(), fmt.Sprintf("%+v", cfg)} {
+		if strings.Contains(out, "s3cret") || strings.Contains(out, "sk-live") {
+			t.Errorf("secret leaked: %s", out)
+		}
+		if !strings.Contains(out, ":8080") || !strings.Contains(out, redactedValue) {
+			t.Errorf("unexpected dump: %s", out)
+		}
+	}
+	if cfg.LLM.APIKey != "sk-live" {
+		t.Error("Redacted must not modify the original config")
+	}
+}
+
+// TestSecretTags guards the debug dump of the config: a credential field
+// without the secret tag would be printed in clear text.
+func TestSecretTags(t *testing.T) {
+	var check func(typ reflect.Type, path string)
+	check = func(typ reflect.Type, path string) {
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if f.Type.Kind() == reflect.Struct {
+				check(f.Type, path+f.Name+".")
+				continue
+			}
+			if f.Type.Kind() != reflect.String {
+				continue
+			}
+			name := strings.ToLower(f.Name)
+			for _, word := range []string{"secret", "token", "key", "password"} {
+				if strings.Contains(name, word) && f.Tag.Get("secret") != "true" {
+					t.Errorf("%s%s looks like a credential but has no secret:\"true\" tag", path, f.Name)
+				}
+			}
+		}
+	}
+	check(reflect.TypeOf(Config{}), "")
+}
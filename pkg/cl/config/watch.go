@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Reload is the outcome of re-reading the configuration.
+type Reload struct {
+	// Config is the running configuration with reloadable fields updated.
+	Config *Config
+	// Ignored lists changed fields that only take effect after a restart.
+	Ignored []string
+	// Err is set when the new configuration could not be loaded. Config is
+	// then the running configuration, unchanged.
+	Err error
+}
+
+// Watch re-reads the configuration every time the process receives SIGHUP
+// and calls apply with the result, until ctx is done. Only the log level and
+// the SSG generation limits (workers, image workers, page buffer and memory
+// budget) are hot-reloadable; changes to any other field are reported in
+// Reload.Ignored and left as they were.
+func Watch(ctx context.Context, current *Config, apply func(Reload)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		running := *current
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				next, err := Load()
+				if err != nil {
+					apply(Reload{Config: &running, Err: err})
+					continue
+				}
+				reload := Merge(&running, next)
+				running = *reload.Config
+				apply(reload)
+			}
+		}
+	}()
+}
+
+// Merge returns a copy of current carrying the hot-reloadable fields of next,
+// along with the names of the non-reloadable fields that differ.
+func Merge(current, next *Config) Reload {
+	merged := *current
+	merged.Log.Level = next.Log.Level
+	merged.SSG.Workers = next.SSG.Workers
+	merged.SSG.ImageWorkers = next.SSG.ImageWorkers
+	merged.SSG.PageBufferKB = next.SSG.PageBufferKB
+	merged.SSG.MemoryBudget = next.SSG.MemoryBudget
+
+	var ignored []string
+	check := func(name string, changed bool) {
+		if changed {
+			ignored = append(ignored, name)
+		}
+	}
+	check("env", current.Env != next.Env)
+	check("server.addr", current.Server.Addr != next.Server.Addr)
+	check("database.path", current.Database.Path != next.Database.Path)
+	check("auth", current.Auth != next.Auth)
+	check("ssg.sites_base_path", current.SSG.SitesBasePath != next.SSG.SitesBasePath)
+	check("ssg.preview_addr", current.SSG.PreviewAddr != next.SSG.PreviewAddr)
+	check("ssg.timezone", current.SSG.Timezone != next.SSG.Timezone)
+	check("ssg.output_dir", current.SSG.OutputDir != next.SSG.OutputDir)
+	check("ssg.photo_size", current.SSG.PhotoSize != next.SSG.PhotoSize)
+	check("ssg.preview", current.SSG.Preview != next.SSG.Preview)
+	check("ssg.secret_key", current.SSG.SecretKey != next.SSG.SecretKey)
+	check("credentials.path", current.Credentials.Path != next.Credentials.Path)
+	check("llm", current.LLM != next.LLM)
+	check("mail", current.Mail != next.Mail)
+	check("metrics", current.Metrics != next.Metrics)
+
+	return Reload{Config: &merged, Ignored: ignored}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeReportsIgnored(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		ignored string
+	}{
+		{"secret key", func(c *Config) { c.SSG.SecretKey = "new" }, "ssg.secret_key"},
+		{"mail", func(c *Config) { c.Mail.Host = "smtp.example.com" }, "mail"},
+		{"timezone", func(c *Config) { c.SSG.Timezone = "Europe/Berlin" }, "ssg.timezone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &Config{SSG: SSGConfig{SecretKey: "old", Workers: 1}}
+			next := *current
+			next.SSG.Workers = 4
+			tt.change(&next)
+
+			reload := Merge(current, &next)
+			if reload.Config.SSG.Workers != 4 {
+				t.Errorf("merged workers = %d, want 4", reload.Config.SSG.Workers)
+			}
+			merged := *reload.Config
+			merged.SSG.Workers = current.SSG.Workers
+			if merged != *current {
+				t.Errorf("Merge() took the %s change, want the running value kept", tt.name)
+			}
+			if !reflect.DeepEqual(reload.Ignored, []string{tt.ignored}) {
+				t.Errorf("Ignored = %v, want [%s]", reload.Ignored, tt.ignored)
+			}
+		})
+	}
+}
+
+// TestMergeCoversEveryField fails when a config field is added without
+// deciding whether it reloads: Merge must either carry a change to it or
+// report it as ignored.
+func TestMergeCoversEveryField(t *testing.T) {
+	var walk func(v reflect.Value, path string)
+	walk = func(v reflect.Value, path string) {
+		for i := 0; i < v.NumField(); i++ {
+			name := path + v.Type().Field(i).Name
+			if v.Field(i).Kind() == reflect.Struct {
+				walk(v.Field(i), name+".")
+				continue
+			}
+
+			current := &Config{}
+			next := &Config{}
+			f := fieldByPath(reflect.ValueOf(next).Elem(), name)
+			switch f.Kind() {
+			case reflect.String:
+				f.SetString("changed")
+			case reflect.Int:
+				f.SetInt(1)
+			case reflect.Bool:
+				f.SetBool(true)
+			case reflect.Float64:
+				f.SetFloat(1)
+			default:
+				t.Fatalf("%s: unsupported kind %s", name, f.Kind())
+			}
+
+			reload := Merge(current, next)
+			merged := fieldByPath(reflect.ValueOf(reload.Config).Elem(), name)
+			if !merged.Equal(f) && len(reload.Ignored) == 0 {
+				t.Errorf("%s changed on reload but Merge neither applied nor reported it", name)
+			}
+		}
+	}
+	walk(reflect.ValueOf(Config{}), "")
+}
+
+func fieldByPath(v reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		v = v.FieldByName(name)
+	}
+	return v
+}
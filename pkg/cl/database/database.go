@@ -12,6 +12,7 @@ import (
 
 	"github.com/cliossg/clio/pkg/cl/config"
 	"github.com/cliossg/clio/pkg/cl/logger"
+	"github.com/cliossg/clio/pkg/cl/metrics"
 	"github.com/cliossg/clio/pkg/cl/migrate"
 )
 
@@ -22,6 +23,7 @@ type Database struct {
 	migrationPath string
 	cfg           *config.Config
 	log           logger.Logger
+	queryTime     *metrics.Histogram // see timing.go
 }
 
 // New creates a new Database instance.
@@ -48,7 +50,7 @@ func (d *Database) Start(ctx context.Context) error {
 
 	// Open SQLite database with WAL mode for better concurrency
 	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=ON", d.cfg.Database.Path)
-	db, err := sql.Open("sqlite3", dsn)
+	db, err := d.open(dsn)
 	if err != nil {
 		return fmt.Errorf("cannot open database: %w", err)
 	}
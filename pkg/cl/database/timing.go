@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/cliossg/clio/pkg/cl/metrics"
+)
+
+// queryBuckets are upper bounds in seconds for single SQLite statements.
+var queryBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+// otherQuery labels statements without a sqlc name, such as migrations.
+const otherQuery = "other"
+
+// SetMetrics registers the query timing collector in reg. It must be called
+// before Start; without it queries are not timed.
+func (d *Database) SetMetrics(reg *metrics.Registry) {
+	d.queryTime = reg.Histogram("clio_db_query_duration_seconds", "Time to run database queries, rows included, by sqlc query name.", queryBuckets, "query")
+}
+
+// open opens the database, through a timedConnector when metrics are on.
+func (d *Database) open(dsn string) (*sql.DB, error) {
+	if d.queryTime == nil {
+		return sql.Open("sqlite3", dsn)
+	}
+	return sql.OpenDB(&timedConnector{dsn: dsn, driver: &sqlite3.SQLiteDriver{}, queryTime: d.queryTime}), nil
+}
+
+// queryName returns the name from the "-- name: GetSite :one" comment sqlc
+// puts at the top of every query.
+func queryName(query string) string {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(query), "-- name:")
+	if !ok {
+		return otherQuery
+	}
+	if fields := strings.Fields(rest); len(fields) > 0 {
+		return fields[0]
+	}
+	return otherQuery
+}
+
+// timedConnector opens SQLite connections that time their statements.
+type timedConnector struct {
+	dsn       string
+	driver    *sqlite3.SQLiteDriver
+	queryTime *metrics.Histogram
+}
+
+func (c *timedConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &timedConn{SQLiteConn: conn.(*sqlite3.SQLiteConn), queryTime: c.queryTime}, nil
+}
+
+func (c *timedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// timedConn times statements run directly on the connection, which is how
+// database/sql runs the queries of sqlc and migrations. Prepared statements
+// pass through untimed.
+type timedConn struct {
+	*sqlite3.SQLiteConn
+	queryTime *metrics.Histogram
+}
+
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := c.SQLiteConn.ExecContext(ctx, query, args)
+	c.queryTime.ObserveSince(start, queryName(query))
+	return res, err
+}
+
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.SQLiteConn.QueryContext(ctx, query, args)
+	if err != nil {
+		c.queryTime.ObserveSince(start, queryName(query))
+		return nil, err
+	}
+	// SQLite steps through rows as they are read, so the query ends when
+	// its rows are closed.
+	return &timedRows{SQLiteRows: rows.(*sqlite3.SQLiteRows), start: start, name: queryName(query), queryTime: c.queryTime}, nil
+}
+
+type timedRows struct {
+	*sqlite3.SQLiteRows
+	start     time.Time
+	name      string
+	queryTime *metrics.Histogram
+}
+
+func (r *timedRows) Close() error {
+	err := r.SQLiteRows.Close()
+	r.queryTime.ObserveSince(r.start, r.name)
+	return err
+}
+
+// The column type methods are promoted from SQLiteRows; these assertions
+// keep database/sql's ColumnTypes working through the wrapper.
+var (
+	_ driver.RowsColumnTypeDatabaseTypeName = (*timedRows)(nil)
+	_ driver.RowsColumnTypeNullable         = (*timedRows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*timedRows)(nil)
+)
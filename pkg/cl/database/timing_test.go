@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/cliossg/clio/pkg/cl/metrics"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := map[string]string{
+		"-- name: GetSite :one\nSELECT * FROM site WHERE id = ?": "GetSite",
+		"\n-- name: ListTags :many\nSELECT 1":                    "ListTags",
+		"CREATE TABLE x (id TEXT)":                               otherQuery,
+		"-- name:":                                               otherQuery,
+	}
+	for query, want := range tests {
+		if got := queryName(query); got != want {
+			t.Errorf("queryName(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestTimedConnectorObservesQueries(t *testing.T) {
+	reg := metrics.NewRegistry()
+	d := &Database{}
+	d.SetMetrics(reg)
+
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	db := sql.OpenDB(&timedConnector{dsn: dsn, driver: &sqlite3.SQLiteDriver{}, queryTime: d.queryTime})
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "CREATE TABLE tag (name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "-- name: CreateTag :exec\nINSERT INTO tag (name) VALUES (?)", "go"); err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	if err := db.QueryRowContext(ctx, "-- name: GetTag :one\nSELECT name FROM tag").Scan(&name); err != nil || name != "go" {
+		t.Fatalf("GetTag = %q, %v", name, err)
+	}
+
+	var out strings.Builder
+	if err := reg.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`clio_db_query_duration_seconds_count{query="other"} 1`,
+		`clio_db_query_duration_seconds_count{query="CreateTag"} 1`,
+		`clio_db_query_duration_seconds_count{query="GetTag"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %s:\n%s", want, out.String())
+		}
+	}
+}
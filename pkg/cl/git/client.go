@@ -3,10 +3,14 @@ package git
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cliossg/clio/pkg/cl/logger"
 )
@@ -21,19 +25,55 @@ func NewClient(log logger.Logger) Client {
 	}
 }
 
-func (c *client) Clone(ctx context.Context, repoURL, localPath string, auth Auth, env []string) error {
+func (c *client) Clone(ctx context.Context, repoURL, localPath string, auth Auth, opts CloneOptions, env []string) error {
+	cloneURL := repoURL
 	if auth.Method == AuthToken {
 		u, err := url.Parse(repoURL)
 		if err != nil {
 			return fmt.Errorf("cannot parse repo URL: %w", err)
 		}
 		u.User = url.UserPassword("oauth2", auth.Token)
-		repoURL = u.String()
+		cloneURL = u.String()
 	}
 
-	cmd := exec.CommandContext(ctx, "git", "clone", repoURL, localPath)
+	env, cleanup, err := sshEnv(auth, env)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	start := time.Now()
+	if opts.Depth > 0 && opts.Branch != "" {
+		cmd := exec.CommandContext(ctx, "git", "clone", "--depth", strconv.Itoa(opts.Depth), "--branch", opts.Branch, "--single-branch", cloneURL, localPath)
+		cmd.Env = env
+		err := c.runCommand(cmd)
+		if errors.Is(err, ErrAuthFailed) || errors.Is(err, ErrHostKey) {
+			return err
+		}
+		if err == nil {
+			c.log.Infof("Cloned %s (branch %s, depth %d) in %s", repoURL, opts.Branch, opts.Depth, time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		c.log.Infof("Shallow clone of %s failed, falling back to full clone: %s", repoURL, redactToken(err.Error(), auth))
+		if err := os.RemoveAll(localPath); err != nil {
+			return fmt.Errorf("cannot clean up failed shallow clone: %w", err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "clone", cloneURL, localPath)
 	cmd.Env = env
-	return c.runCommand(cmd)
+	if err := c.runCommand(cmd); err != nil {
+		return err
+	}
+	c.log.Infof("Cloned %s in %s", repoURL, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func redactToken(s string, auth Auth) string {
+	if auth.Token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, auth.Token, "***")
 }
 
 func (c *client) Checkout(ctx context.Context, localRepoPath, branch string, create bool, env []string) error {
@@ -57,6 +97,12 @@ func (c *client) Add(ctx context.Context, localRepoPath, pathspec string, env []
 }
 
 func (c *client) Commit(ctx context.Context, localRepoPath string, commit Commit, env []string) (string, error) {
+	signArgs, cleanup, err := signingArgs(commit.Signing)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
 	configUserCmd := exec.CommandContext(ctx, "git", "config", "user.name", commit.UserName)
 	configUserCmd.Dir = localRepoPath
 	configUserCmd.Env = env
@@ -80,10 +126,13 @@ func (c *client) Commit(ctx context.Context, localRepoPath string, commit Commit
 		return "", nil
 	}
 
-	commitCmd := exec.CommandContext(ctx, "git", "commit", "-m", commit.Message)
+	commitCmd := exec.CommandContext(ctx, "git", append(signArgs, "commit", "-m", commit.Message)...)
 	commitCmd.Dir = localRepoPath
 	commitCmd.Env = env
 	if err := c.runCommand(commitCmd); err != nil {
+		if len(signArgs) > 0 && signingError(err.Error()) {
+			return "", fmt.Errorf("%w: %v", ErrSigningFailed, err)
+		}
 		return "", fmt.Errorf("cannot commit changes: %w", err)
 	}
 
@@ -121,6 +170,12 @@ func (c *client) Push(ctx context.Context, localRepoPath string, auth Auth, remo
 		pushRepoURL = u.String()
 	}
 
+	env, cleanup, err := sshEnv(auth, env)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
 	cmd := exec.CommandContext(ctx, "git", "push", "--force", pushRepoURL, branch)
 	cmd.Dir = localRepoPath
 	cmd.Env = env
@@ -161,6 +216,9 @@ func (c *client) runCommand(cmd *exec.Cmd) error {
 
 	err := cmd.Run()
 	if err != nil {
+		if authErr := authError(stderr.String()); authErr != nil {
+			return fmt.Errorf("%w: command %q failed: %v: %s", authErr, cmd.String(), err, stderr.String())
+		}
 		return fmt.Errorf("command %q failed: %w: %s", cmd.String(), err, stderr.String())
 	}
 
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cliossg/clio/pkg/cl/logger"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = gitTestEnv()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func gitTestEnv() []string {
+	return append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+}
+
+// newRemote creates a bare repository whose gh-pages branch has three commits.
+func newRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	base := t.TempDir()
+	remote := filepath.Join(base, "remote.git")
+	work := filepath.Join(base, "work")
+	runGit(t, base, "init", "--bare", remote)
+	runGit(t, base, "init", work)
+	runGit(t, work, "checkout", "-b", "gh-pages")
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.WriteFile(filepath.Join(work, name+".html"), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, work, "add", ".")
+		runGit(t, work, "commit", "-m", "add "+name)
+	}
+	runGit(t, work, "push", remote, "gh-pages")
+	return "file://" + remote
+}
+
+func TestCloneShallow(t *testing.T) {
+	remote := newRemote(t)
+	c := NewClient(logger.NewNoopLogger())
+	ctx := context.Background()
+	env := gitTestEnv()
+
+	local := filepath.Join(t.TempDir(), "repo")
+	if err := c.Clone(ctx, remote, local, Auth{Method: AuthSSH}, CloneOptions{Branch: "gh-pages", Depth: 1}, env); err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if got := runGit(t, local, "rev-list", "--count", "HEAD"); got != "1" {
+		t.Errorf("commits in shallow clone = %s, want 1", got)
+	}
+
+	// Publishing replaces the tree and force-pushes on top of the shallow head.
+	os.Remove(filepath.Join(local, "a.html"))
+	if err := os.WriteFile(filepath.Join(local, "d.html"), []byte("d"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Add(ctx, local, ".", env); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	hash, err := c.Commit(ctx, local, Commit{UserName: "Test", UserEmail: "test@example.com", Message: "deploy"}, env)
+	if err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := c.Push(ctx, local, Auth{Method: AuthSSH}, "origin", "gh-pages", env); err != nil {
+		t.Fatalf("Push() from shallow clone error = %v", err)
+	}
+
+	remoteDir := strings.TrimPrefix(remote, "file://")
+	if got := runGit(t, remoteDir, "rev-parse", "gh-pages"); got != hash {
+		t.Errorf("remote gh-pages = %s, want pushed commit %s", got, hash)
+	}
+	if got := runGit(t, remoteDir, "rev-list", "--count", "gh-pages"); got != "4" {
+		t.Errorf("remote history = %s commits, want 4", got)
+	}
+}
+
+func TestCloneFallsBackToFullClone(t *testing.T) {
+	remote := newRemote(t)
+	c := NewClient(logger.NewNoopLogger())
+
+	local := filepath.Join(t.TempDir(), "repo")
+	// The branch does not exist yet, so the shallow clone fails.
+	if err := c.Clone(context.Background(), remote, local, Auth{Method: AuthSSH}, CloneOptions{Branch: "missing", Depth: 1}, gitTestEnv()); err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if got := runGit(t, local, "rev-list", "--count", "origin/gh-pages"); got != "3" {
+		t.Errorf("commits after fallback = %s, want full history of 3", got)
+	}
+}
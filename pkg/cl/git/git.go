@@ -2,10 +2,11 @@ package git
 
 import (
 	"context"
+	"errors"
 )
 
 type Client interface {
-	Clone(ctx context.Context, repoURL, localPath string, auth Auth, env []string) error
+	Clone(ctx context.Context, repoURL, localPath string, auth Auth, opts CloneOptions, env []string) error
 	Checkout(ctx context.Context, localRepoPath, branch string, create bool, env []string) error
 	Add(ctx context.Context, localRepoPath, pathspec string, env []string) error
 	Commit(ctx context.Context, localRepoPath string, commit Commit, env []string) (string, error)
@@ -14,11 +15,55 @@ type Client interface {
 	Log(ctx context.Context, localRepoPath string, args []string, env []string) (string, error)
 }
 
+// CloneOptions limits what Clone transfers. The zero value is a full clone.
+type CloneOptions struct {
+	// Branch is fetched alone when Depth is set.
+	Branch string
+	// Depth, when positive, truncates history to that many commits. If the
+	// shallow clone fails (e.g. the branch does not exist yet or the server
+	// does not support it) Clone falls back to a full clone.
+	Depth int
+}
+
 type Auth struct {
 	Method AuthMethod
 	Token  string
+	SSH    SSHOptions
+}
+
+// SSHOptions configures SSH auth explicitly instead of relying on the host's
+// SSH agent and config. With no key set, ssh uses its defaults as before.
+type SSHOptions struct {
+	KeyPath       string // Path to a private key file
+	Key           string // Private key contents, used when KeyPath is empty
+	Passphrase    string // For encrypted keys
+	KnownHosts    string // known_hosts entries; empty uses the user's known_hosts file
+	HostKeyPolicy HostKeyPolicy
 }
 
+// HostKeyPolicy controls how unknown or changed host keys are handled.
+type HostKeyPolicy string
+
+const (
+	// HostKeyStrict only connects to hosts already in known_hosts. It is the default.
+	HostKeyStrict HostKeyPolicy = "strict"
+	// HostKeyAcceptNew trusts and records the key of hosts seen for the first
+	// time, but still rejects changed keys.
+	HostKeyAcceptNew HostKeyPolicy = "accept-new"
+	// HostKeyInsecure skips host key verification entirely.
+	HostKeyInsecure HostKeyPolicy = "insecure"
+)
+
+var (
+	// ErrAuthFailed is returned when the remote rejects the credentials.
+	ErrAuthFailed = errors.New("git authentication failed")
+	// ErrHostKey is returned when the remote host key cannot be verified.
+	ErrHostKey = errors.New("git host key verification failed")
+	// ErrSigningFailed is returned when a commit cannot be signed, or signing
+	// is required and no key is set.
+	ErrSigningFailed = errors.New("git commit signing failed")
+)
+
 type AuthMethod string
 
 const (
@@ -30,4 +75,27 @@ type Commit struct {
 	UserName  string
 	UserEmail string
 	Message   string
+	// Signing signs the commit when a key is set.
+	Signing Signing
+}
+
+// Signing configures commit signing. Without a key, commits are unsigned
+// unless Required is set.
+type Signing struct {
+	Format SigningFormat
+	// Key is a GPG key ID for openpgp. For ssh it is the path to a private
+	// key, or the key itself, which is written to a temp file for the commit.
+	Key string
+	// Required makes a missing key an error instead of an unsigned commit.
+	Required bool
 }
+
+// SigningFormat is the gpg.format git signs with.
+type SigningFormat string
+
+const (
+	// SigningOpenPGP signs with gpg and a key from its keyring. It is the default.
+	SigningOpenPGP SigningFormat = "openpgp"
+	// SigningSSH signs with ssh-keygen and an SSH key.
+	SigningSSH SigningFormat = "ssh"
+)
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// signingArgs returns the git options that sign a commit with s. They are
+// passed with -c to the commit command alone, so the key never ends up in the
+// clone's config. A key given as contents is written to a private temp dir
+// that cleanup removes. Without a key, it returns no options.
+func signingArgs(s Signing) ([]string, func(), error) {
+	noop := func() {}
+	key := strings.TrimSpace(s.Key)
+	if key == "" {
+		if s.Required {
+			return nil, noop, fmt.Errorf("%w: signing is required but no signing key is set", ErrSigningFailed)
+		}
+		return nil, noop, nil
+	}
+
+	switch s.Format {
+	case "", SigningOpenPGP:
+		return []string{"-c", "gpg.format=openpgp", "-c", "user.signingkey=" + key, "-c", "commit.gpgsign=true"}, noop, nil
+	case SigningSSH:
+	default:
+		return nil, noop, fmt.Errorf("%w: unknown signing format %q (use %s or %s)", ErrSigningFailed, s.Format, SigningOpenPGP, SigningSSH)
+	}
+
+	cleanup := noop
+	switch {
+	case strings.HasPrefix(key, "ssh-") || strings.HasPrefix(key, "sk-"):
+		// A public key: ssh-keygen finds the private half in the agent.
+		key = "key::" + key
+	case strings.Contains(key, "PRIVATE KEY"):
+		dir, err := os.MkdirTemp("", "clio-signing-*")
+		if err != nil {
+			return nil, noop, fmt.Errorf("cannot create signing temp dir: %w", err)
+		}
+		cleanup = func() { os.RemoveAll(dir) }
+		path := filepath.Join(dir, "id")
+		if err := os.WriteFile(path, []byte(key+"\n"), 0600); err != nil {
+			cleanup()
+			return nil, noop, fmt.Errorf("cannot write signing key: %w", err)
+		}
+		key = path
+	default:
+		if _, err := os.Stat(key); err != nil {
+			return nil, noop, fmt.Errorf("%w: cannot read signing key %s: %v", ErrSigningFailed, key, err)
+		}
+	}
+	return []string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + key, "-c", "commit.gpgsign=true"}, cleanup, nil
+}
+
+// signingError reports whether a failed commit failed because of signing,
+// from git's message, so it is not mistaken for other commit failures.
+func signingError(msg string) bool {
+	lower := strings.ToLower(msg)
+	return strings.Contains(lower, "failed to sign") ||
+		strings.Contains(lower, "failed to write commit object") ||
+		strings.Contains(lower, "ssh-keygen") ||
+		strings.Contains(lower, "signing key")
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// passphraseEnv carries the key passphrase to the askpass helper. It is only
+// set in the environment of the git child process and never written to disk.
+const passphraseEnv = "CLIO_SSH_PASSPHRASE"
+
+// sshEnv returns env extended so git runs ssh with the configured key and host
+// key policy. Key material is written to a private temp dir that cleanup
+// removes. Without SSH auth or options, env is returned unchanged.
+func sshEnv(auth Auth, env []string) ([]string, func(), error) {
+	noop := func() {}
+	opts := auth.SSH
+	if auth.Method != AuthSSH || (opts.KeyPath == "" && opts.Key == "" && opts.KnownHosts == "" && opts.HostKeyPolicy == "") {
+		return env, noop, nil
+	}
+
+	dir, err := os.MkdirTemp("", "clio-ssh-*")
+	if err != nil {
+		return nil, noop, fmt.Errorf("cannot create ssh temp dir: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	args := []string{"ssh", "-F", "/dev/null"}
+
+	keyPath := opts.KeyPath
+	if keyPath == "" && opts.Key != "" {
+		keyPath = filepath.Join(dir, "id")
+		key := opts.Key
+		if !strings.HasSuffix(key, "\n") {
+			key += "\n" // ssh rejects keys without a trailing newline
+		}
+		if err := os.WriteFile(keyPath, []byte(key), 0600); err != nil {
+			cleanup()
+			return nil, noop, fmt.Errorf("cannot write ssh key: %w", err)
+		}
+	}
+	if keyPath != "" {
+		if _, err := os.Stat(keyPath); err != nil {
+			cleanup()
+			return nil, noop, fmt.Errorf("%w: cannot read ssh key %s: %v", ErrAuthFailed, keyPath, err)
+		}
+		args = append(args, "-i", shellQuote(keyPath), "-o", "IdentitiesOnly=yes")
+	}
+
+	switch opts.HostKeyPolicy {
+	case "", HostKeyStrict:
+		args = append(args, "-o", "StrictHostKeyChecking=yes")
+	case HostKeyAcceptNew:
+		args = append(args, "-o", "StrictHostKeyChecking=accept-new")
+	case HostKeyInsecure:
+		args = append(args, "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null")
+	default:
+		cleanup()
+		return nil, noop, fmt.Errorf("unknown host key policy %q (use %s, %s or %s)", opts.HostKeyPolicy, HostKeyStrict, HostKeyAcceptNew, HostKeyInsecure)
+	}
+	if opts.KnownHosts != "" && opts.HostKeyPolicy != HostKeyInsecure {
+		knownHosts := filepath.Join(dir, "known_hosts")
+		if err := os.WriteFile(knownHosts, []byte(opts.KnownHosts+"\n"), 0600); err != nil {
+			cleanup()
+			return nil, noop, fmt.Errorf("cannot write known_hosts: %w", err)
+		}
+		args = append(args, "-o", "UserKnownHostsFile="+shellQuote(knownHosts))
+	}
+
+	env = append(append([]string{}, env...), "GIT_TERMINAL_PROMPT=0")
+	if opts.Passphrase != "" {
+		// BatchMode would disable the passphrase prompt, so ssh asks the helper instead.
+		askpass := filepath.Join(dir, "askpass")
+		script := "#!/bin/sh\nprintf '%s\\n' \"$" + passphraseEnv + "\"\n"
+		if err := os.WriteFile(askpass, []byte(script), 0700); err != nil {
+			cleanup()
+			return nil, noop, fmt.Errorf("cannot write askpass helper: %w", err)
+		}
+		env = append(env, "SSH_ASKPASS="+askpass, "SSH_ASKPASS_REQUIRE=force", "DISPLAY=none", passphraseEnv+"="+opts.Passphrase)
+	} else {
+		args = append(args, "-o", "BatchMode=yes")
+	}
+
+	env = append(env, "GIT_SSH_COMMAND="+strings.Join(args, " "))
+	return env, cleanup, nil
+}
+
+// shellQuote quotes s for the shell that git uses to run GIT_SSH_COMMAND.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// authError classifies git's stderr so callers can tell credential and host
+// key problems apart from other failures.
+func authError(stderr string) error {
+	lower := strings.ToLower(stderr)
+	switch {
+	case strings.Contains(lower, "host key verification failed"),
+		strings.Contains(lower, "remote host identification has changed"):
+		return ErrHostKey
+	case strings.Contains(lower, "permission denied (publickey"),
+		strings.Contains(lower, "authentication failed"),
+		strings.Contains(lower, "incorrect passphrase"),
+		strings.Contains(lower, "invalid format"),
+		strings.Contains(lower, "could not read username"):
+		return ErrAuthFailed
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+// Social preview images are shown at 1.91:1 by the major networks.
+const (
+	DefaultCardWidth  = 1200
+	DefaultCardHeight = 630
+)
+
+// ErrNoText is returned for card titles with nothing the font can draw.
+var ErrNoText = errors.New("no text the card font can draw")
+
+// Card describes a title card: a title over a plain or image background,
+// with a footer line above an accent band. Zero values take defaults.
+type Card struct {
+	Width, Height int
+	Title         string
+	Footer        string // small text at the bottom, e.g. the site name
+	Background    color.Color
+	// BackgroundImage is scaled to cover the card and tinted with
+	// Background, so the title stays readable.
+	BackgroundImage image.Image
+	TextColor       color.Color
+	AccentColor     color.Color
+	TextSize        int // title height in pixels; the footer is half of it
+}
+
+// maxTitleLines is where long titles are cut with an ellipsis.
+const maxTitleLines = 4
+
+// TitleCard draws c as a PNG. The text is drawn with a built-in pixel font,
+// so it needs no font files; titles are folded to ASCII.
+func TitleCard(c Card) ([]byte, error) {
+	c.setDefaults()
+	title := strings.Join(strings.Fields(foldText(c.Title)), " ")
+	if title == "" {
+		return nil, ErrNoText
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c.Background), image.Point{}, draw.Src)
+	if c.BackgroundImage != nil {
+		draw.Draw(img, img.Bounds(), cover(c.BackgroundImage, c.Width, c.Height), image.Point{}, draw.Src)
+		r, g, b, _ := c.Background.RGBA()
+		tint := color.NRGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xB0}
+		draw.Draw(img, img.Bounds(), image.NewUniform(tint), image.Point{}, draw.Over)
+	}
+
+	margin := c.Width / 15
+	band := max(c.Height/40, 4)
+	draw.Draw(img, image.Rect(0, c.Height-band, c.Width, c.Height), image.NewUniform(c.AccentColor), image.Point{}, draw.Src)
+
+	footerScale := max(c.TextSize/cellHeight/2, 2)
+	footerTop := c.Height - band - margin/2 - glyphHeight*footerScale
+	if footer := strings.Join(strings.Fields(foldText(c.Footer)), " "); footer != "" {
+		maxCols := (c.Width - 2*margin) / (cellWidth * footerScale)
+		lines := wrapText(footer, maxCols, 1)
+		drawText(img, lines[0], margin, footerTop, footerScale, c.AccentColor)
+	}
+
+	// Shrink the title until it fits above the footer.
+	scale := max(c.TextSize/cellHeight, 1)
+	var lines []string
+	for ; ; scale-- {
+		maxCols := (c.Width - 2*margin) / (cellWidth * scale)
+		lines = wrapText(title, maxCols, maxTitleLines)
+		if scale <= 2 || margin+len(lines)*cellHeight*scale <= footerTop-margin/2 {
+			break
+		}
+	}
+	for i, line := range lines {
+		drawText(img, line, margin, margin+i*cellHeight*scale, scale, c.TextColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("cannot encode card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *Card) setDefaults() {
+	if c.Width <= 0 || c.Height <= 0 {
+		c.Width, c.Height = DefaultCardWidth, DefaultCardHeight
+	}
+	if c.Background == nil {
+		c.Background = color.RGBA{0x1f, 0x29, 0x37, 0xff}
+	}
+	if c.TextColor == nil {
+		c.TextColor = color.White
+	}
+	if c.AccentColor == nil {
+		c.AccentColor = color.RGBA{0xf5, 0x9e, 0x0b, 0xff}
+	}
+	if c.TextSize <= 0 {
+		c.TextSize = c.Height / 8
+	}
+}
+
+// wrapText splits s into lines of at most cols characters, breaking at
+// spaces and cutting words longer than a line. Text beyond maxLines is
+// dropped and the last line ends with an ellipsis.
+func wrapText(s string, cols, maxLines int) []string {
+	cols = max(cols, 4)
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for len(word) > cols {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:cols])
+			word = word[cols:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= cols:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := lines[maxLines-1]
+		if len(last)+3 > cols {
+			last = strings.TrimRight(last[:cols-3], " ")
+		}
+		lines[maxLines-1] = last + "..."
+	}
+	return lines
+}
+
+// drawText draws s with the pixel font, each font pixel a scale by scale
+// square, with its top left corner at x, y.
+func drawText(img *image.RGBA, s string, x, y, scale int, c color.Color) {
+	src := image.NewUniform(c)
+	for i, r := range s {
+		glyph, ok := font[r]
+		if !ok {
+			continue
+		}
+		gx := x + i*cellWidth*scale
+		for row, bits := range glyph {
+			for col := 0; col < glyphWidth; col++ {
+				if bits&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px, py := gx+col*scale, y+row*scale
+				draw.Draw(img, image.Rect(px, py, px+scale, py+scale), src, image.Point{}, draw.Over)
+			}
+		}
+	}
+}
+
+// cover scales img to fill w by h, cropping what overflows from the center.
+func cover(img image.Image, w, h int) *image.RGBA {
+	b := img.Bounds()
+	cw, ch := b.Dx(), b.Dx()*h/w
+	if ch > b.Dy() {
+		cw, ch = b.Dy()*w/h, b.Dy()
+	}
+	x := b.Min.X + (b.Dx()-cw)/2
+	y := b.Min.Y + (b.Dy()-ch)/2
+	crop := image.NewRGBA(image.Rect(0, 0, cw, ch))
+	draw.Draw(crop, crop.Bounds(), img, image.Pt(x, y), draw.Src)
+	return Resize(crop, w, h)
+}
+
+// ParseHexColor parses a CSS hex color: #rgb, #rrggbb or #rrggbbaa, with or
+// without the #.
+func ParseHexColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
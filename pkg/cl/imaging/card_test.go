@@ -0,0 +1,101 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestTitleCard(t *testing.T) {
+	bg := color.RGBA{0x10, 0x20, 0x30, 0xff}
+	data, err := TitleCard(Card{Title: "Hello, wörld", Footer: "My Blog", Background: bg, TextColor: color.White})
+	if err != nil {
+		t.Fatalf("TitleCard() error = %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("card is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != DefaultCardWidth || b.Dy() != DefaultCardHeight {
+		t.Errorf("card is %dx%d, want %dx%d", b.Dx(), b.Dy(), DefaultCardWidth, DefaultCardHeight)
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != bg {
+		t.Errorf("background = %v, want %v", got, bg)
+	}
+	white := 0
+	for y := 0; y < DefaultCardHeight/2; y++ {
+		for x := 0; x < DefaultCardWidth; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+				white++
+			}
+		}
+	}
+	if white == 0 {
+		t.Error("no title drawn")
+	}
+
+	t.Run("background image", func(t *testing.T) {
+		photo, _ := Decode(pngOf(t, 300, 100))
+		if _, err := TitleCard(Card{Title: "Photo", BackgroundImage: photo, Width: 600, Height: 315}); err != nil {
+			t.Errorf("TitleCard() error = %v", err)
+		}
+	})
+
+	t.Run("nothing to draw", func(t *testing.T) {
+		if _, err := TitleCard(Card{Title: "日本語"}); !errors.Is(err, ErrNoText) {
+			t.Errorf("TitleCard() error = %v, want ErrNoText", err)
+		}
+	})
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		cols     int
+		maxLines int
+		want     []string
+	}{
+		{"fits", "a short title", 20, 3, []string{"a short title"}},
+		{"wraps at spaces", "one two three four", 9, 3, []string{"one two", "three", "four"}},
+		{"long words are cut", "abcdefghij", 4, 5, []string{"abcd", "efgh", "ij"}},
+		{"too many lines", "one two three four five", 5, 2, []string{"one", "tw..."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(tt.s, tt.cols, tt.maxLines)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrapText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldText(t *testing.T) {
+	if got, want := foldText("Qué día — “hoy”… 🎉"), `Que dia - "hoy"... `; got != want {
+		t.Errorf("foldText() = %q, want %q", got, want)
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.Color
+		wantErr bool
+	}{
+		{"#fff", color.NRGBA{0xff, 0xff, 0xff, 0xff}, false},
+		{"1f2937", color.NRGBA{0x1f, 0x29, 0x37, 0xff}, false},
+		{"#00000080", color.NRGBA{0, 0, 0, 0x80}, false},
+		{"#ggg", nil, true},
+		{"blue", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseHexColor(tt.in)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseHexColor(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
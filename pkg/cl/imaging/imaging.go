@@ -0,0 +1,212 @@
+// Package imaging decodes, crops and resizes uploaded images, and draws
+// title cards for social previews, with the standard library only.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // decoder registration
+	"image/jpeg"
+	_ "image/png" // decoder registration
+)
+
+// DefaultAvatarSize is the side, in pixels, of square profile photos.
+const DefaultAvatarSize = 400
+
+// ErrNotImage is returned for uploads that are not a JPEG, PNG or GIF image.
+var ErrNotImage = errors.New("not a JPEG, PNG or GIF image")
+
+// maxPixels guards against decompression bombs: small files declaring huge
+// dimensions.
+const maxPixels = 50_000_000
+
+// Avatar turns an uploaded photo into a square JPEG of size pixels: the
+// image is rotated upright following its EXIF orientation, center cropped
+// and scaled down, never up. Encoding anew drops EXIF and other metadata.
+func Avatar(data []byte, size int) ([]byte, error) {
+	if size <= 0 {
+		size = DefaultAvatarSize
+	}
+	img, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img = CenterSquare(img)
+	if side := img.Bounds().Dx(); side < size {
+		size = side
+	}
+	img = Resize(img, size, size)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("cannot encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode decodes a JPEG, PNG or GIF image, applying the EXIF orientation of
+// JPEG photos.
+func Decode(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("image dimensions %dx%d not supported", cfg.Width, cfg.Height)
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// CenterSquare crops the largest centered square out of img.
+func CenterSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, image.Pt(x, y), draw.Src)
+	return dst
+}
+
+// Resize scales img to w by h pixels. Each destination pixel averages the
+// source pixels it covers, which keeps downscaled photos smooth.
+func Resize(img image.Image, w, h int) *image.RGBA {
+	src := toRGBA(img)
+	sb := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		y0 := sb.Min.Y + dy*sb.Dy()/h
+		y1 := max(sb.Min.Y+(dy+1)*sb.Dy()/h, y0+1)
+		for dx := 0; dx < w; dx++ {
+			x0 := sb.Min.X + dx*sb.Dx()/w
+			x1 := max(sb.Min.X+(dx+1)*sb.Dx()/w, x0+1)
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					i := src.PixOffset(x, y)
+					r += uint64(src.Pix[i])
+					g += uint64(src.Pix[i+1])
+					b += uint64(src.Pix[i+2])
+					a += uint64(src.Pix[i+3])
+					n++
+				}
+			}
+			dst.SetRGBA(dx, dy, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), uint8(a / n)})
+		}
+	}
+	return dst
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(b)
+	draw.Draw(rgba, b, img, b.Min, draw.Src)
+	return rgba
+}
+
+// orient rotates and flips img so EXIF orientation o (1 to 8) becomes 1.
+func orient(img image.Image, o int) image.Image {
+	if o < 2 || o > 8 {
+		return img
+	}
+	src := toRGBA(img)
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if o >= 5 {
+		w, h = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			var nx, ny int
+			switch o {
+			case 2: // flip horizontally
+				nx, ny = w-1-x, y
+			case 3: // rotate 180°
+				nx, ny = w-1-x, h-1-y
+			case 4: // flip vertically
+				nx, ny = x, h-1-y
+			case 5: // transpose
+				nx, ny = y, x
+			case 6: // rotate 90° clockwise
+				nx, ny = w-1-y, x
+			case 7: // transverse
+				nx, ny = w-1-y, h-1-x
+			case 8: // rotate 90° counterclockwise
+				nx, ny = y, h-1-x
+			}
+			dst.SetRGBA(nx, ny, src.RGBAAt(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag of a JPEG file, or returns
+// 1 when there is none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			return 1 // image data starts, no EXIF before it
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < entries; e++ {
+		off := ifd + 2 + e*12
+		if off+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[off:]) == 0x0112 {
+			return int(order.Uint16(tiff[off+8:]))
+		}
+	}
+	return 1
+}
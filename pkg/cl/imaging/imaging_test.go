@@ -0,0 +1,120 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func pngOf(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 100, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAvatar(t *testing.T) {
+	tests := []struct {
+		name       string
+		w, h, size int
+		want       int
+	}{
+		{"wide image is cropped and scaled", 300, 120, 64, 64},
+		{"tall image is cropped and scaled", 90, 200, 32, 32},
+		{"small image is not upscaled", 50, 80, 400, 50},
+		{"zero size uses the default", 500, 450, 0, DefaultAvatarSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Avatar(pngOf(t, tt.w, tt.h), tt.size)
+			if err != nil {
+				t.Fatalf("Avatar() error = %v", err)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(out))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "jpeg" || cfg.Width != tt.want || cfg.Height != tt.want {
+				t.Errorf("Avatar() = %s %dx%d, want jpeg %dx%d", format, cfg.Width, cfg.Height, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvatarRejectsNonImages(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("<svg></svg>"), []byte("%PDF-1.7")} {
+		if _, err := Avatar(data, 100); !errors.Is(err, ErrNotImage) {
+			t.Errorf("Avatar(%q) error = %v, want ErrNotImage", data, err)
+		}
+	}
+}
+
+// withOrientation inserts an EXIF APP1 segment after the SOI marker of a JPEG.
+func withOrientation(t *testing.T, jpg []byte, o uint16) []byte {
+	t.Helper()
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, o)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // value padding, next IFD
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, app1...)
+	return append(out, jpg[2:]...)
+}
+
+func TestDecodeAppliesOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for o, want := range map[uint16]image.Point{1: {40, 20}, 3: {40, 20}, 6: {20, 40}, 8: {20, 40}} {
+		data := withOrientation(t, buf.Bytes(), o)
+		if got := jpegOrientation(data); got != int(o) {
+			t.Errorf("jpegOrientation() = %d, want %d", got, o)
+		}
+		img, err := Decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := img.Bounds().Size(); got != want {
+			t.Errorf("orientation %d: size = %v, want %v", o, got, want)
+		}
+	}
+	if got := jpegOrientation(buf.Bytes()); got != 1 {
+		t.Errorf("jpegOrientation() without EXIF = %d, want 1", got)
+	}
+}
+
+func TestOrientRotatesPixels(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	red := color.RGBA{255, 0, 0, 255}
+	src.SetRGBA(0, 0, red)
+
+	// Rotating 90° clockwise turns the left pixel of a row into the top one.
+	got := orient(src, 6).(*image.RGBA)
+	if got.Bounds().Dx() != 1 || got.Bounds().Dy() != 2 || got.RGBAAt(0, 0) != red {
+		t.Errorf("orient(6) = %v, top pixel %v", got.Bounds(), got.RGBAAt(0, 0))
+	}
+}
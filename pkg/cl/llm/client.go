@@ -140,23 +140,40 @@ func (c *Client) Proofread(ctx context.Context, text string) (*ProofreadResponse
 
 	userPrompt := fmt.Sprintf("Operation: Proofread\n\nPerform a comprehensive editorial pass on the following text.\nApply grammar, style, echo detection, and overuse flagging.\nReturn the corrected text and a detailed list of every correction made.\n\nText to proofread:\n\"\"\"\n%s\n\"\"\"", text)
 
+	content, err := c.complete(ctx, systemPrompt, userPrompt)
+	if err != nil {
+		return nil, err
+	}
+	content = cleanMarkdownWrapper(content)
+
+	var result ProofreadResponse
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM response as JSON: %w", err)
+	}
+
+	return &result, nil
+}
+
+// complete sends a system and a user message to the chat completions API and
+// returns the text of the first choice.
+func (c *Client) complete(ctx context.Context, system, user string) (string, error) {
 	req := openAIRequest{
 		Model:       c.model,
 		Temperature: c.temperature,
 		Messages: []openAIMessage{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userPrompt},
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
 		},
 	}
 
 	body, err := json.Marshal(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
@@ -164,36 +181,29 @@ func (c *Client) Proofread(ctx context.Context, text string) (*ProofreadResponse
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call LLM API: %w", err)
+		return "", fmt.Errorf("failed to call LLM API: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var openAIResp openAIResponse
 	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if openAIResp.Error != nil {
-		return nil, fmt.Errorf("LLM API error: %s", openAIResp.Error.Message)
+		return "", fmt.Errorf("LLM API error: %s", openAIResp.Error.Message)
 	}
 
 	if len(openAIResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from LLM")
+		return "", fmt.Errorf("no response from LLM")
 	}
 
-	content := cleanMarkdownWrapper(openAIResp.Choices[0].Message.Content)
-
-	var result ProofreadResponse
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse LLM response as JSON: %w", err)
-	}
-
-	return &result, nil
+	return openAIResp.Choices[0].Message.Content, nil
 }
 
 func cleanMarkdownWrapper(s string) string {
@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxSummarySource is the most characters of a text sent to be summarized.
+// The start of a long text is enough for a summary.
+const maxSummarySource = 12000
+
+const summarizeSystemPrompt = `You write short summaries of articles for listings and search results.
+
+Rules:
+- Write in the language of the article.
+- Write plain text: no markdown, no quotes around the summary, no preamble.
+- Describe what the article is about; do not address the reader or mention "this article".
+- Never exceed the word limit you are given.`
+
+// Summarize writes a plain text summary of text in at most words words.
+func (c *Client) Summarize(ctx context.Context, text string, words int) (string, error) {
+	if len(text) > maxSummarySource {
+		text = text[:maxSummarySource]
+		if i := strings.LastIndexAny(text, " \n"); i > 0 {
+			text = text[:i]
+		}
+	}
+
+	userPrompt := fmt.Sprintf("Summarize the following article in at most %d words.\n\nArticle:\n\"\"\"\n%s\n\"\"\"", words, text)
+	summary, err := c.complete(ctx, summarizeSystemPrompt, userPrompt)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(summary), `"`), nil
+}
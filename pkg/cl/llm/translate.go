@@ -0,0 +1,155 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxTranslateChunk is the most characters of text sent in one translation
+// request, well within the model's context for the text and its translation.
+const maxTranslateChunk = 6000
+
+var (
+	fencedCodeRe = regexp.MustCompile("(?ms)^[ \\t]*(```|~~~).*?^[ \\t]*(```|~~~)[ \\t]*$")
+	inlineCodeRe = regexp.MustCompile("`[^`\\n]+`")
+	linkTargetRe = regexp.MustCompile(`\]\([^)\s]+(?:\s+"[^"]*")?\)`)
+	autolinkRe   = regexp.MustCompile(`<https?://[^>\s]+>`)
+	bareURLRe    = regexp.MustCompile(`https?://[^\s<>()"']+`)
+	placeholdRe  = regexp.MustCompile(`@@\d+@@`)
+)
+
+const translateSystemPrompt = `You are a professional translator of markdown documents.
+Translate the text you are given into the requested language.
+
+Rules:
+- Keep the markdown structure exactly: headings, lists, emphasis, tables, blockquotes and line breaks.
+- Tokens like @@0@@ stand for code and URLs. Copy every one of them unchanged, in place.
+- Do not add notes, explanations or anything that is not in the original.
+- Return only the translated text, without wrapping it in a code block.`
+
+// Translate translates markdown text into the language with the given tag,
+// e.g. es or pt-BR. Code and URLs are kept as they are. Long texts are sent in
+// chunks split between paragraphs and joined back.
+func (c *Client) Translate(ctx context.Context, text, lang string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+
+	masked, kept := maskUntranslatable(text)
+	var out []string
+	for _, chunk := range chunkMarkdown(masked, maxTranslateChunk) {
+		userPrompt := fmt.Sprintf("Translate the following text into the language with tag %q.\n\nText to translate:\n\"\"\"\n%s\n\"\"\"", lang, chunk)
+		translated, err := c.complete(ctx, translateSystemPrompt, userPrompt)
+		if err != nil {
+			return "", err
+		}
+		translated = unwrapTranslation(translated)
+		if err := checkPlaceholders(chunk, translated); err != nil {
+			return "", err
+		}
+		out = append(out, translated)
+	}
+	return unmask(strings.Join(out, "\n\n"), kept), nil
+}
+
+// unwrapTranslation removes a code fence or the triple quotes of the prompt
+// when the model wraps its answer in them. Code in the text itself is masked,
+// so a leading fence can only be a wrapper.
+func unwrapTranslation(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") && strings.HasSuffix(s, "```") {
+		if i := strings.Index(s, "\n"); i >= 0 {
+			s = strings.TrimSuffix(s[i+1:], "```")
+		}
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), `"""`), `"""`)
+	return strings.TrimSpace(s)
+}
+
+// maskUntranslatable replaces code blocks, inline code and URLs with numbered
+// placeholders and returns the masked text and the replaced parts.
+func maskUntranslatable(text string) (string, []string) {
+	var kept []string
+	replace := func(re *regexp.Regexp, s string) string {
+		return re.ReplaceAllStringFunc(s, func(m string) string {
+			kept = append(kept, m)
+			return "@@" + strconv.Itoa(len(kept)-1) + "@@"
+		})
+	}
+	for _, re := range []*regexp.Regexp{fencedCodeRe, inlineCodeRe, autolinkRe} {
+		text = replace(re, text)
+	}
+	// Keep the brackets of links outside the placeholder so that the model
+	// still sees the link text as part of the sentence.
+	text = linkTargetRe.ReplaceAllStringFunc(text, func(m string) string {
+		kept = append(kept, m[2:len(m)-1])
+		return "](@@" + strconv.Itoa(len(kept)-1) + "@@)"
+	})
+	text = replace(bareURLRe, text)
+	return text, kept
+}
+
+// unmask puts the parts replaced by maskUntranslatable back.
+func unmask(text string, kept []string) string {
+	return placeholdRe.ReplaceAllStringFunc(text, func(m string) string {
+		i, err := strconv.Atoi(strings.Trim(m, "@"))
+		if err != nil || i >= len(kept) {
+			return m
+		}
+		return kept[i]
+	})
+}
+
+// checkPlaceholders makes sure a translation kept every placeholder of its
+// source, so no code or URL is lost.
+func checkPlaceholders(source, translated string) error {
+	for _, p := range placeholdRe.FindAllString(source, -1) {
+		if !strings.Contains(translated, p) {
+			return fmt.Errorf("translation dropped protected text %s", p)
+		}
+	}
+	return nil
+}
+
+// chunkMarkdown splits text between paragraphs into chunks of at most max
+// characters. Paragraphs longer than max are split between lines; a single
+// line longer than max becomes a chunk of its own.
+func chunkMarkdown(text string, max int) []string {
+	var chunks []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+	add := func(part, sep string) {
+		if current.Len() > 0 && current.Len()+len(sep)+len(part) > max {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString(sep)
+		}
+		current.WriteString(part)
+	}
+
+	for _, para := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if strings.TrimSpace(para) == "" {
+			continue
+		}
+		if len(para) <= max {
+			add(para, "\n\n")
+			continue
+		}
+		flush()
+		for _, line := range strings.Split(para, "\n") {
+			add(line, "\n")
+		}
+		flush()
+	}
+	flush()
+	return chunks
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaskUntranslatable(t *testing.T) {
+	text := "# Title\n\nSee [the docs](https://example.com/docs \"Docs\") and `go test`.\n\n```go\nfmt.Println(\"hi\")\n```\n\nOr visit https://example.com/a?b=c today."
+	masked, kept := maskUntranslatable(text)
+
+	for _, gone := range []string{"https://", "go test", "Println"} {
+		if strings.Contains(masked, gone) {
+			t.Errorf("masked text should not contain %q:\n%s", gone, masked)
+		}
+	}
+	for _, still := range []string{"# Title", "[the docs](@@", "today."} {
+		if !strings.Contains(masked, still) {
+			t.Errorf("masked text should contain %q:\n%s", still, masked)
+		}
+	}
+	if got := unmask(masked, kept); got != text {
+		t.Errorf("unmask() = %q, want %q", got, text)
+	}
+}
+
+func TestChunkMarkdown(t *testing.T) {
+	para := strings.Repeat("a", 40)
+	text := strings.Join([]string{para, para, para}, "\n\n")
+
+	chunks := chunkMarkdown(text, 90)
+	if len(chunks) != 2 || chunks[0] != para+"\n\n"+para || chunks[1] != para {
+		t.Errorf("chunkMarkdown() = %q, want two paragraphs then one", chunks)
+	}
+	if got := strings.Join(chunks, "\n\n"); got != text {
+		t.Errorf("joined chunks = %q, want the original text", got)
+	}
+
+	long := para + "\n" + para + "\n" + para
+	if chunks := chunkMarkdown(long, 50); len(chunks) != 3 {
+		t.Errorf("a paragraph longer than max should be split between lines, got %q", chunks)
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	if err := checkPlaceholders("Hi @@0@@ and @@1@@", "Hola @@1@@ y @@0@@"); err != nil {
+		t.Errorf("checkPlaceholders() error = %v, want nil", err)
+	}
+	if err := checkPlaceholders("Hi @@0@@ and @@1@@", "Hola @@0@@"); err == nil {
+		t.Error("checkPlaceholders() should fail when a placeholder is dropped")
+	}
+}
+
+func TestUnwrapTranslation(t *testing.T) {
+	for in, want := range map[string]string{
+		"Hola":                   "Hola",
+		"```markdown\nHola\n```": "Hola",
+		"\"\"\"\nHola\n\"\"\"":   "Hola",
+		"  Hola, \"mundo\"  \n":  "Hola, \"mundo\"",
+	} {
+		if got := unwrapTranslation(in); got != want {
+			t.Errorf("unwrapTranslation(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strings"
+	"sync/atomic"
 )
 
 // LogLevel represents the severity level for logging.
@@ -25,11 +27,28 @@ type Logger interface {
 	Error(v ...any)
 	Errorf(format string, a ...any)
 	With(args ...any) Logger
+	SetLevel(logLevelStr string)
+}
+
+// levelVar holds the current level. It is shared by a logger and every
+// logger derived from it with With, so SetLevel affects all of them.
+type levelVar struct {
+	level atomic.Int32
+	slog  slog.LevelVar
+}
+
+func (v *levelVar) set(level LogLevel) {
+	v.level.Store(int32(level))
+	v.slog.Set(toSlogLevel(level))
+}
+
+func (v *levelVar) enabled(level LogLevel) bool {
+	return LogLevel(v.level.Load()) <= level
 }
 
 type slogLogger struct {
 	logger   *slog.Logger
-	logLevel LogLevel
+	logLevel *levelVar
 }
 
 // New creates a logger with the specified level.
@@ -43,16 +62,21 @@ func New(logLevelStr string) Logger {
 // Defaults to InfoLevel if level string is unrecognized.
 // Output format is JSON if LOG_FORMAT=json, otherwise human-readable text.
 func NewLogger(logLevelStr string) Logger {
-	level := parseLevel(logLevelStr)
+	return newLogger(os.Stdout, logLevelStr)
+}
+
+func newLogger(w io.Writer, logLevelStr string) *slogLogger {
+	level := &levelVar{}
+	level.set(parseLevel(logLevelStr))
 
 	var handler slog.Handler
 	if os.Getenv("LOG_FORMAT") == "json" {
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: toSlogLevel(level),
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level: &level.slog,
 		})
 	} else {
-		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: toSlogLevel(level),
+		handler = slog.NewTextHandler(w, &slog.HandlerOptions{
+			Level: &level.slog,
 		})
 	}
 
@@ -62,44 +86,50 @@ func NewLogger(logLevelStr string) Logger {
 	}
 }
 
+// SetLevel changes the level at runtime, using the same names as NewLogger.
+// Loggers derived with With follow the change.
+func (l *slogLogger) SetLevel(logLevelStr string) {
+	l.logLevel.set(parseLevel(logLevelStr))
+}
+
 func (l *slogLogger) Debug(v ...any) {
-	if l.logLevel <= DebugLevel {
+	if l.logLevel.enabled(DebugLevel) {
 		l.logger.Debug(fmt.Sprint(v...))
 	}
 }
 
 func (l *slogLogger) Debugf(format string, a ...any) {
-	if l.logLevel <= DebugLevel {
+	if l.logLevel.enabled(DebugLevel) {
 		l.logger.Debug(fmt.Sprintf(format, a...))
 	}
 }
 
 func (l *slogLogger) Info(v ...any) {
-	if l.logLevel <= InfoLevel {
+	if l.logLevel.enabled(InfoLevel) {
 		l.logger.Info(fmt.Sprint(v...))
 	}
 }
 
 func (l *slogLogger) Infof(format string, a ...any) {
-	if l.logLevel <= InfoLevel {
+	if l.logLevel.enabled(InfoLevel) {
 		l.logger.Info(fmt.Sprintf(format, a...))
 	}
 }
 
 func (l *slogLogger) Error(v ...any) {
-	if l.logLevel <= ErrorLevel {
+	if l.logLevel.enabled(ErrorLevel) {
 		l.logger.Error(fmt.Sprint(v...))
 	}
 }
 
 func (l *slogLogger) Errorf(format string, a ...any) {
-	if l.logLevel <= ErrorLevel {
+	if l.logLevel.enabled(ErrorLevel) {
 		l.logger.Error(fmt.Sprintf(format, a...))
 	}
 }
 
 // With returns a new logger with additional contextual fields.
-// The returned logger preserves the current log level.
+// The returned logger shares the level of its parent.
 func (l *slogLogger) With(args ...any) Logger {
 	return &slogLogger{
 		logger:   l.logger.With(args...),
@@ -116,6 +146,7 @@ func (noopLogger) Infof(format string, a ...any)  {}
 func (noopLogger) Error(v ...any)                 {}
 func (noopLogger) Errorf(format string, a ...any) {}
 func (noopLogger) With(args ...any) Logger        { return noopLogger{} }
+func (noopLogger) SetLevel(logLevelStr string)    {}
 
 // NewNoopLogger creates a no-op logger that discards all log output.
 // Useful for testing or components that don't require logging.
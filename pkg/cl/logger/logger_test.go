@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := newLogger(&buf, "error")
+	child := log.With("component", "test")
+
+	log.Info("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("info logged at error level: %q", buf.String())
+	}
+
+	log.SetLevel("debug")
+	log.Debug("parent debug")
+	child.Debugf("child %s", "debug")
+	out := buf.String()
+	for _, want := range []string{"parent debug", "child debug"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q after SetLevel(debug): %q", want, out)
+		}
+	}
+
+	buf.Reset()
+	child.SetLevel("info")
+	log.Debug("hidden again")
+	log.Info("shown")
+	out = buf.String()
+	if strings.Contains(out, "hidden again") || !strings.Contains(out, "shown") {
+		t.Errorf("unexpected output after SetLevel(info): %q", out)
+	}
+}
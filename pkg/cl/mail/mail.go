@@ -0,0 +1,111 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/config"
+	"github.com/cliossg/clio/pkg/cl/logger"
+)
+
+var ErrNoRecipients = errors.New("message has no recipients")
+
+// Message is a plain text email.
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns a mailer that sends through the configured SMTP server, or one
+// that logs the emails when no server is configured.
+func New(cfg config.MailConfig, log logger.Logger) Mailer {
+	if cfg.Host == "" {
+		return &logMailer{log: log}
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	m := &smtpMailer{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		from: cfg.From,
+		send: smtp.SendMail,
+	}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	if m.from == "" {
+		m.from = cfg.Username
+	}
+	return m
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	sender, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", m.from, err)
+	}
+	if err := m.send(m.addr, m.auth, sender.Address, msg.To, format(m.from, msg, time.Now())); err != nil {
+		return fmt.Errorf("cannot send mail to %s: %w", strings.Join(msg.To, ", "), err)
+	}
+	return nil
+}
+
+// format renders msg with its headers. Line breaks in header values are
+// replaced so a subject cannot add headers of its own.
+func format(from string, msg Message, date time.Time) []byte {
+	header := strings.NewReplacer("\r", " ", "\n", " ")
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(from))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(strings.Join(msg.To, ", ")))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", header.Replace(msg.Subject)))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n")
+	b.WriteString(body)
+	return b.Bytes()
+}
+
+// logMailer stands in for a mail server on installs without one, so
+// notifications still show up somewhere.
+type logMailer struct {
+	log logger.Logger
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+	m.log.Infof("Mail to %s: %s\n%s", strings.Join(msg.To, ", "), msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cliossg/clio/pkg/cl/config"
+	"github.com/cliossg/clio/pkg/cl/logger"
+)
+
+func TestFormat(t *testing.T) {
+	msg := Message{To: []string{"ana@example.com"}, Subject: "Review\r\nBcc: eve@example.com", Body: "Line one\nLine two"}
+	got := string(format("Clio <clio@example.com>", msg, time.Date(2024, 3, 7, 10, 0, 0, 0, time.UTC)))
+
+	for _, want := range []string{
+		"From: Clio <clio@example.com>\r\n",
+		"To: ana@example.com\r\n",
+		"Subject: Review  Bcc: eve@example.com\r\n",
+		"Date: Thu, 07 Mar 2024 10:00:00 +0000\r\n",
+		"\r\n\r\nLine one\r\nLine two",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\nBcc:") {
+		t.Errorf("subject added a header:\n%s", got)
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	m := New(config.MailConfig{Host: "smtp.example.com", Username: "clio@example.com", Password: "secret"}, logger.NewNoopLogger()).(*smtpMailer)
+	if m.addr != "smtp.example.com:587" || m.from != "clio@example.com" {
+		t.Fatalf("mailer = %s from %s, want smtp.example.com:587 from the username", m.addr, m.from)
+	}
+
+	var gotFrom string
+	var gotTo []string
+	m.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotFrom, gotTo = from, to
+		return nil
+	}
+	if err := m.Send(context.Background(), Message{To: []string{"ana@example.com"}, Subject: "Hi"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if gotFrom != "clio@example.com" || len(gotTo) != 1 || gotTo[0] != "ana@example.com" {
+		t.Errorf("sent from %q to %v", gotFrom, gotTo)
+	}
+
+	if err := m.Send(context.Background(), Message{Subject: "Hi"}); !errors.Is(err, ErrNoRecipients) {
+		t.Errorf("Send() without recipients error = %v, want ErrNoRecipients", err)
+	}
+}
+
+func TestNewWithoutHostLogs(t *testing.T) {
+	if _, ok := New(config.MailConfig{}, logger.NewNoopLogger()).(*logMailer); !ok {
+		t.Error("New() without a host does not return the log mailer")
+	}
+}
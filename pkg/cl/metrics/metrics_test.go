@@ -0,0 +1,136 @@
+package metrics_test
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/cliossg/clio/pkg/cl/metrics"
+	"github.com/cliossg/clio/pkg/cl/middleware"
+)
+
+var (
+	commentRe = regexp.MustCompile(`^# (HELP|TYPE) ([a-zA-Z_:][a-zA-Z0-9_:]*) (.*)$`)
+	sampleRe  = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(?:[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*",?)*\})? (\S+)$`)
+)
+
+// parseExposition checks text against the exposition format and returns its
+// samples by name and label text, e.g. `hits_total{route="/"}`.
+func parseExposition(t *testing.T, r io.Reader) map[string]float64 {
+	t.Helper()
+	samples := make(map[string]float64)
+	types := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := commentRe.FindStringSubmatch(line); m != nil {
+			if m[1] == "TYPE" {
+				types[m[2]] = m[3]
+			}
+			continue
+		}
+		m := sampleRe.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("malformed line %q", line)
+		}
+		family := m[1]
+		if types[family] == "" {
+			family = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(family, "_bucket"), "_sum"), "_count")
+			if types[family] != "histogram" {
+				t.Fatalf("sample %q before its TYPE line", line)
+			}
+		}
+		v, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			t.Fatalf("bad value in %q: %v", line, err)
+		}
+		samples[m[1]+m[2]] = v
+	}
+	return samples
+}
+
+func TestHandlerServesExpositionFormat(t *testing.T) {
+	reg := metrics.NewRegistry()
+	hits := reg.Counter("test_hits_total", "Hits.\nSecond line.", "path")
+	latency := reg.Histogram("test_latency_seconds", "Latency.", []float64{.1, 1})
+	reg.Counter("test_unused_total", "Never incremented.")
+
+	hits.Inc(`/a "quoted" \ path`)
+	hits.Add(2, "/b")
+	latency.Observe(.05)
+	latency.Observe(.5)
+	latency.Observe(3)
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	samples := parseExposition(t, rec.Body)
+	want := map[string]float64{
+		`test_hits_total{path="/a \"quoted\" \\ path"}`: 1,
+		`test_hits_total{path="/b"}`:                    2,
+		`test_latency_seconds_bucket{le="0.1"}`:         1,
+		`test_latency_seconds_bucket{le="1"}`:           2,
+		`test_latency_seconds_bucket{le="+Inf"}`:        3,
+		`test_latency_seconds_sum`:                      3.55,
+		`test_latency_seconds_count`:                    3,
+	}
+	for name, v := range want {
+		if got, ok := samples[name]; !ok || got != v {
+			t.Errorf("%s = %v (present %v), want %v", name, got, ok, v)
+		}
+	}
+	if len(samples) != len(want) {
+		t.Errorf("got %d samples, want %d: %v", len(samples), len(want), samples)
+	}
+}
+
+func TestNilCollectorsRecordNothing(t *testing.T) {
+	var c *metrics.Counter
+	var h *metrics.Histogram
+	c.Inc("x")
+	h.Observe(1, "x")
+}
+
+func TestHTTPMetricsLabelsByRoute(t *testing.T) {
+	reg := metrics.NewRegistry()
+	router := chi.NewRouter()
+	router.Use(middleware.HTTPMetrics(reg))
+	router.Get("/sites/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	router.Handle("/metrics", reg.Handler())
+
+	for _, path := range []string{"/sites/1", "/sites/2", "/nowhere"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	for _, method := range []string{"BREW", "X-RANDOM-1"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/nowhere", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	samples := parseExposition(t, rec.Body)
+
+	if got := samples[`clio_http_requests_total{method="GET",route="/sites/{id}",status="418"}`]; got != 2 {
+		t.Errorf("requests to /sites/{id} = %v, want 2", got)
+	}
+	if got := samples[`clio_http_requests_total{method="GET",route="unmatched",status="404"}`]; got != 1 {
+		t.Errorf("unmatched requests = %v, want 1", got)
+	}
+	if got := samples[`clio_http_requests_total{method="other",route="unmatched",status="405"}`]; got != 2 {
+		t.Errorf("requests with other methods = %v, want 2", got)
+	}
+	if got := samples[`clio_http_request_duration_seconds_count{method="GET",route="/sites/{id}"}`]; got != 2 {
+		t.Errorf("latency count of /sites/{id} = %v, want 2", got)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/cliossg/clio/pkg/cl/metrics"
+)
+
+// unmatchedRoute labels requests no route matched, so that probes for
+// random paths do not each become a series of their own.
+const unmatchedRoute = "unmatched"
+
+// methodLabel returns the method of r for metric labels. Methods outside the
+// standard ones are "other", so clients cannot create series at will.
+func methodLabel(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return r.Method
+	}
+	return "other"
+}
+
+// RoutePattern returns the chi pattern of the route that served r, such as
+// /ssg/sites/{id}, or "unmatched". It is only complete once the request has
+// been routed, so middleware reads it after calling the next handler.
+func RoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return unmatchedRoute
+}
+
+// HTTPMetrics registers the request count and latency collectors and returns
+// the middleware that feeds them. Requests are labelled by route pattern
+// rather than path, and by a fixed set of methods, which keeps the number of
+// series bounded.
+func HTTPMetrics(reg *metrics.Registry) func(http.Handler) http.Handler {
+	requests := reg.Counter("clio_http_requests_total", "HTTP requests served, by method, route and status code.", "method", "route", "status")
+	latency := reg.Histogram("clio_http_request_duration_seconds", "Time to serve HTTP requests, by method and route.", metrics.DefaultBuckets, "method", "route")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK // nothing written
+			}
+			method, route := methodLabel(r), RoutePattern(r)
+			requests.Inc(method, route, strconv.Itoa(status))
+			latency.ObserveSince(start, method, route)
+		})
+	}
+}
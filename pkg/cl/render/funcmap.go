@@ -1,39 +1,15 @@
 package render
 
 import (
+	"fmt"
 	"html/template"
-	"strings"
+	"time"
 )
 
-// FuncMap returns a template.FuncMap with all render functions.
+// FuncMap returns the functions available to admin templates: the shared
+// Helpers plus escaping bypasses and comparisons the site layouts do not get.
 func FuncMap() template.FuncMap {
-	return template.FuncMap{
-		// String
-		"upper": strings.ToUpper,
-		"lower": strings.ToLower,
-		"title": strings.Title,
-
-		// Math
-		"add": func(a, b int) int { return a + b },
-		"sub": func(a, b int) int { return a - b },
-		"mul": func(a, b int) int { return a * b },
-		"div": func(a, b int) int {
-			if b == 0 {
-				return 0
-			}
-			return a / b
-		},
-		"seq": func(start, end int) []int {
-			if end < start {
-				return nil
-			}
-			result := make([]int, end-start+1)
-			for i := range result {
-				result[i] = start + i
-			}
-			return result
-		},
-
+	return MergeFuncMaps(Helpers(), template.FuncMap{
 		// HTML
 		"safeHTML": func(s string) template.HTML {
 			return template.HTML(s)
@@ -45,20 +21,6 @@ func FuncMap() template.FuncMap {
 			return template.URL(s)
 		},
 
-		// String manipulation
-		"truncate": func(s string, length int) string {
-			if len(s) <= length {
-				return s
-			}
-			return s[:length] + "..."
-		},
-		"contains": strings.Contains,
-		"hasPrefix": strings.HasPrefix,
-		"hasSuffix": strings.HasSuffix,
-		"replace":   strings.Replace,
-		"split":     strings.Split,
-		"join":      strings.Join,
-
 		// Comparisons
 		"eq": func(a, b any) bool { return a == b },
 		"ne": func(a, b any) bool { return a != b },
@@ -90,7 +52,41 @@ func FuncMap() template.FuncMap {
 				return 0
 			}
 		},
+	})
+}
+
+// TimeAgo describes t relative to now, e.g. "5 minutes ago" or "in 2 days".
+func TimeAgo(t, now time.Time) string {
+	d := now.Sub(t)
+	suffix := " ago"
+	if d < 0 {
+		d = -d
+		suffix = ""
+	}
+
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "hour"
+	case d < 30*24*time.Hour:
+		n, unit = int(d/(24*time.Hour)), "day"
+	case d < 365*24*time.Hour:
+		n, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		n, unit = int(d/(365*24*time.Hour)), "year"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	if suffix == "" {
+		return fmt.Sprintf("in %d %s", n, unit)
 	}
+	return fmt.Sprintf("%d %s%s", n, unit, suffix)
 }
 
 // MergeFuncMaps merges multiple FuncMaps into one.
@@ -0,0 +1,30 @@
+package render
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(-time.Minute), "1 minute ago"},
+		{now.Add(-45 * time.Minute), "45 minutes ago"},
+		{now.Add(-3 * time.Hour), "3 hours ago"},
+		{now.Add(-26 * time.Hour), "1 day ago"},
+		{now.Add(-70 * 24 * time.Hour), "2 months ago"},
+		{now.Add(-800 * 24 * time.Hour), "2 years ago"},
+		{now.Add(2 * 24 * time.Hour), "in 2 days"},
+	}
+
+	for _, tt := range tests {
+		if got := TimeAgo(tt.t, now); got != tt.want {
+			t.Errorf("TimeAgo(%v) = %q, want %q", now.Sub(tt.t), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Helpers are shared by the admin templates and the generated site layouts.
+// Site layouts are code written in the admin UI that runs in the server
+// process, so a helper must be a pure function of its arguments: nothing that
+// reads files, the environment or the network, and nothing that bypasses
+// escaping. Helpers that do not belong in layouts go in FuncMap instead.
+var (
+	helpersMu sync.RWMutex
+	helpers   = template.FuncMap{
+		// String
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"title":         strings.Title,
+		"truncate":      Truncate,
+		"truncateWords": TruncateWords,
+		"plainText":     PlainText,
+		"pluralize":     Pluralize,
+		"contains":      strings.Contains,
+		"hasPrefix":     strings.HasPrefix,
+		"hasSuffix":     strings.HasSuffix,
+		"replace":       strings.Replace,
+		"split":         strings.Split,
+		"join":          strings.Join,
+
+		// Math
+		"add": func(a, b int) int { return a + b },
+		"sub": func(a, b int) int { return a - b },
+		"mul": func(a, b int) int { return a * b },
+		"div": func(a, b int) int {
+			if b == 0 {
+				return 0
+			}
+			return a / b
+		},
+		"seq": Seq,
+
+		// Time
+		"formatDate": FormatDate,
+		"timeAgo":    func(t time.Time) string { return TimeAgo(t, time.Now()) },
+
+		// URL
+		"absURL": AbsURL,
+	}
+)
+
+// RegisterHelper adds a helper available to both admin templates and site
+// layouts. It is meant to be called from init functions and panics when the
+// name is already taken.
+func RegisterHelper(name string, fn any) {
+	helpersMu.Lock()
+	defer helpersMu.Unlock()
+	if _, ok := helpers[name]; ok {
+		panic(fmt.Sprintf("render: helper %q already registered", name))
+	}
+	helpers[name] = fn
+}
+
+// Helpers returns a copy of the registered helpers.
+func Helpers() template.FuncMap {
+	helpersMu.RLock()
+	defer helpersMu.RUnlock()
+	return MergeFuncMaps(helpers)
+}
+
+// Truncate cuts s to length bytes, adding an ellipsis when something was cut.
+func Truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length] + "..."
+}
+
+// TruncateWords keeps the first n words of s, adding an ellipsis when
+// something was cut. Runs of whitespace collapse to a single space.
+func TruncateWords(s string, n int) string {
+	words := strings.Fields(s)
+	if len(words) <= n {
+		return strings.Join(words, " ")
+	}
+	if n <= 0 {
+		return ""
+	}
+	return strings.Join(words[:n], " ") + "..."
+}
+
+var (
+	mdFenceRe    = regexp.MustCompile("(?m)^\\s*(```|~~~).*$")
+	mdImageRe    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLinkRe     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdTagRe      = regexp.MustCompile(`<[^>]+>`)
+	mdLineMarkRe = regexp.MustCompile(`(?m)^\s*(#{1,6}\s+|>\s?|[-*+]\s+|\d+\.\s+)`)
+	mdEmphasisRe = regexp.MustCompile("\\*\\*|__|~~|[*`]")
+)
+
+// PlainText turns Markdown into plain text for excerpts and meta
+// descriptions: links and images keep their text, markup and HTML tags
+// are dropped and whitespace is collapsed.
+func PlainText(md string) string {
+	s := mdFenceRe.ReplaceAllString(md, "")
+	s = mdImageRe.ReplaceAllString(s, "$1")
+	s = mdLinkRe.ReplaceAllString(s, "$1")
+	s = mdTagRe.ReplaceAllString(s, "")
+	s = mdLineMarkRe.ReplaceAllString(s, "")
+	s = mdEmphasisRe.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// maxSeq is the longest sequence Seq returns. Layouts run during generation,
+// so a huge range would allocate without bound.
+const maxSeq = 10000
+
+// Seq returns the integers from start to end, both included. It returns nil
+// when end is before start or the range is longer than maxSeq.
+func Seq(start, end int) []int {
+	if end < start || uint(end-start) >= maxSeq {
+		return nil
+	}
+	result := make([]int, end-start+1)
+	for i := range result {
+		result[i] = start + i
+	}
+	return result
+}
+
+// Pluralize returns singular when n is 1 and plural otherwise. An empty
+// plural adds an s to singular.
+func Pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	if plural == "" {
+		return singular + "s"
+	}
+	return plural
+}
+
+// FormatDate formats a time.Time or *time.Time with a Go layout. Nil and zero
+// times give an empty string.
+func FormatDate(t any, layout string) string {
+	var v time.Time
+	switch tt := t.(type) {
+	case time.Time:
+		v = tt
+	case *time.Time:
+		if tt == nil {
+			return ""
+		}
+		v = *tt
+	default:
+		return ""
+	}
+	if v.IsZero() {
+		return ""
+	}
+	return v.Format(layout)
+}
+
+// AbsURL joins path to base, e.g. the site base URL. URLs that already have
+// a scheme or are protocol-relative are returned as is.
+func AbsURL(base, path string) string {
+	if strings.Contains(path, "://") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "mailto:") {
+		return path
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
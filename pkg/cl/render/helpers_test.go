@@ -0,0 +1,125 @@
+package render
+
+import (
+	"html/template"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTruncateWords(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"one two three", 5, "one two three"},
+		{"one two three", 3, "one two three"},
+		{"one  two\nthree four", 2, "one two..."},
+		{"one two", 0, ""},
+		{"", 3, ""},
+	}
+	for _, tt := range tests {
+		if got := TruncateWords(tt.s, tt.n); got != tt.want {
+			t.Errorf("TruncateWords(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAbsURL(t *testing.T) {
+	tests := []struct {
+		base, path, want string
+	}{
+		{"https://example.com", "/feed/atom.xml", "https://example.com/feed/atom.xml"},
+		{"https://example.com/blog/", "posts/a/", "https://example.com/blog/posts/a/"},
+		{"https://example.com", "https://cdn.example.com/x.png", "https://cdn.example.com/x.png"},
+		{"https://example.com", "//cdn.example.com/x.png", "//cdn.example.com/x.png"},
+		{"", "about/", "/about/"},
+	}
+	for _, tt := range tests {
+		if got := AbsURL(tt.base, tt.path); got != tt.want {
+			t.Errorf("AbsURL(%q, %q) = %q, want %q", tt.base, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	md := "# Title\n\nSome **bold** and `code`, a [link](/x) and ![alt](/a.png).\n\n- item <br>\n> quote\n\n```go\nx := 1\n```\n"
+	want := "Title Some bold and code, a link and alt. item quote x := 1"
+	if got := PlainText(md); got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	if got := Seq(1, 3); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("Seq(1, 3) = %v", got)
+	}
+	if got := Seq(3, 1); got != nil {
+		t.Errorf("Seq(3, 1) = %v, want nil", got)
+	}
+	if got := Seq(1, maxSeq); len(got) != maxSeq {
+		t.Errorf("Seq(1, maxSeq) has %d items, want %d", len(got), maxSeq)
+	}
+	if got := Seq(0, 2000000000); got != nil {
+		t.Errorf("Seq(0, 2000000000) has %d items, want nil", len(got))
+	}
+	if got := Seq(math.MinInt, math.MaxInt); got != nil {
+		t.Errorf("Seq(MinInt, MaxInt) has %d items, want nil", len(got))
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	if got := Pluralize(1, "post", ""); got != "post" {
+		t.Errorf("Pluralize(1) = %q", got)
+	}
+	if got := Pluralize(0, "post", ""); got != "posts" {
+		t.Errorf("Pluralize(0) = %q", got)
+	}
+	if got := Pluralize(2, "entry", "entries"); got != "entries" {
+		t.Errorf("Pluralize(2) = %q", got)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	d := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got := FormatDate(&d, "2006-01-02"); got != "2026-03-01" {
+		t.Errorf("FormatDate() = %q", got)
+	}
+	var none *time.Time
+	if got := FormatDate(none, "2006-01-02"); got != "" {
+		t.Errorf("FormatDate(nil) = %q", got)
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	for _, name := range []string{"safeHTML", "safeAttr", "safeURL"} {
+		if _, ok := Helpers()[name]; ok {
+			t.Errorf("%s bypasses escaping and should not be a shared helper", name)
+		}
+	}
+
+	RegisterHelper("testShout", func(s string) string { return strings.ToUpper(s) + "!" })
+	defer func() {
+		helpersMu.Lock()
+		delete(helpers, "testShout")
+		helpersMu.Unlock()
+	}()
+
+	tmpl := template.Must(template.New("t").Funcs(FuncMap()).Parse(`{{ testShout "hi" }} {{ truncateWords "a b c" 2 }}`))
+	var out strings.Builder
+	if err := tmpl.Execute(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "HI! a b..." {
+		t.Errorf("registered helper output = %q", out.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a taken name should panic")
+		}
+	}()
+	RegisterHelper("truncate", Truncate)
+}